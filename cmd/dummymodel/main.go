@@ -0,0 +1,32 @@
+// cmd/dummymodel/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/SyedDaiam9101/policy-service/testenv"
+)
+
+// dummymodel writes the ONNX model the test environment serves: obs of shape
+// [N, C, H, W] in, action of shape [N, A] out, computed by testenv.ExpectedActions.
+//
+// Usage:
+//
+//	dummymodel -out /models/dummy.onnx -obs 3x8x8 -actions 2
+func main() {
+	out := flag.String("out", "dummy.onnx", "Path to write the model to")
+	obsDims := flag.String("obs", fmt.Sprintf("%dx%dx%d", testenv.Channels, testenv.Height, testenv.Width), "Observation dimensions as CxHxW")
+	actions := flag.Int("actions", testenv.Actions, "Action dimension")
+	flag.Parse()
+
+	var c, h, w int
+	if _, err := fmt.Sscanf(*obsDims, "%dx%dx%d", &c, &h, &w); err != nil {
+		log.Fatalf("Invalid -obs %q: %v", *obsDims, err)
+	}
+	if err := testenv.WriteDummyModel(*out, c, h, w, *actions); err != nil {
+		log.Fatalf("Failed to write model: %v", err)
+	}
+	log.Printf("Wrote dummy model to %s (obs %dx%dx%d, %d actions)", *out, c, h, w, *actions)
+}
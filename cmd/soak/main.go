@@ -0,0 +1,183 @@
+// cmd/soak/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/SyedDaiam9101/policy-service/internal/soak"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// soak runs sustained mixed load against policy-service for hours while
+// sampling goroutines, RSS, native (ORT) memory, and open FDs from /metrics,
+// and exits non-zero if any of them grow monotonically.
+//
+// Usage:
+//
+//	soak -duration 4h -server ./server -- -mock
+//	soak -addr policy-service:50051 -metrics-url http://policy-service:9100/metrics
+func main() {
+	addr := flag.String("addr", "localhost:50051", "gRPC address of the service under test")
+	metricsURL := flag.String("metrics-url", "http://localhost:9100/metrics", "Prometheus metrics URL of the service under test")
+	serverBin := flag.String("server", "", "Server binary to start (arguments after --); empty to target a running server")
+	duration := flag.Duration("duration", 4*time.Hour, "Total soak duration")
+	warmup := flag.Duration("warmup", 10*time.Minute, "Initial period excluded from leak detection")
+	interval := flag.Duration("interval", 15*time.Second, "Resource sampling interval")
+	concurrency := flag.Int("concurrency", 32, "Concurrent client workers")
+	obsDims := flag.String("obs", "3x64x64", "Observation dimensions as CxHxW")
+	maxBatch := flag.Int("max-batch", 32, "Largest BatchPlan size")
+	batchRatio := flag.Float64("batch-ratio", 0.3, "Fraction of calls sent as BatchPlan")
+	invalidRatio := flag.Float64("invalid-ratio", 0.05, "Fraction of deliberately invalid calls")
+	minTrend := flag.Float64("min-trend", soak.DefaultLeakDetector().MinTrend, "Kendall trend required to flag a leak")
+	minGrowth := flag.Float64("min-growth", soak.DefaultLeakDetector().MinGrowth, "Relative growth required to flag a leak")
+	flag.Parse()
+
+	var c, h, w uint32
+	if _, err := fmt.Sscanf(*obsDims, "%dx%dx%d", &c, &h, &w); err != nil {
+		log.Fatalf("Invalid -obs %q: %v", *obsDims, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Optionally run the server under test as a child process
+	if *serverBin != "" {
+		cmd := exec.Command(*serverBin, flag.Args()...)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+		defer func() {
+			cmd.Process.Signal(syscall.SIGTERM)
+			cmd.Wait()
+		}()
+		log.Printf("Started server %s (pid %d)", *serverBin, cmd.Process.Pid)
+	}
+
+	if err := waitReady(ctx, *metricsURL, time.Minute); err != nil {
+		log.Fatalf("Server not ready: %v", err)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", *addr, err)
+	}
+	defer conn.Close()
+
+	runCtx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	sampler := soak.NewSampler(*metricsURL)
+	go sampler.Run(runCtx, *interval, func(err error) {
+		log.Printf("Warning: %v", err)
+	})
+
+	stats := &soak.LoadStats{}
+	go reportProgress(runCtx, stats, sampler)
+
+	log.Printf("Soaking %s for %s (warmup %s, %d workers, obs %dx%dx%d)",
+		*addr, *duration, *warmup, *concurrency, c, h, w)
+	soak.RunLoad(runCtx, pb.NewPathPlannerClient(conn), soak.LoadConfig{
+		Concurrency:  *concurrency,
+		Channels:     c,
+		Height:       h,
+		Width:        w,
+		MaxBatch:     *maxBatch,
+		BatchRatio:   *batchRatio,
+		InvalidRatio: *invalidRatio,
+		Timeout:      10 * time.Second,
+	}, stats)
+
+	detector := soak.LeakDetector{
+		MinTrend:   *minTrend,
+		MinGrowth:  *minGrowth,
+		MinSamples: soak.DefaultLeakDetector().MinSamples,
+	}
+	skip := int(*warmup / *interval)
+
+	fmt.Printf("\nSoak results: plans=%d batches=%d invalid=%d errors=%d unexpected=%d\n",
+		stats.Plans.Load(), stats.Batches.Load(), stats.Invalid.Load(), stats.Errors.Load(), stats.Unexpected.Load())
+
+	failed := stats.Unexpected.Load() > 0
+	for _, v := range sampler.Evaluate(detector, skip) {
+		fmt.Println(v)
+		if v.Leaking {
+			failed = true
+		}
+	}
+
+	if failed {
+		fmt.Println("FAIL")
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}
+
+// waitReady polls the /readyz endpoint next to metricsURL until it returns 200
+func waitReady(ctx context.Context, metricsURL string, timeout time.Duration) error {
+	u, err := url.Parse(metricsURL)
+	if err != nil {
+		return err
+	}
+	u.Path = "/readyz"
+
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := http.Get(u.String())
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not become ready within %s", u, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// reportProgress logs call counts and the latest resource samples every minute
+func reportProgress(ctx context.Context, stats *soak.LoadStats, sampler *soak.Sampler) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		last := func(name string) float64 {
+			values := sampler.Samples(name)
+			if len(values) == 0 {
+				return 0
+			}
+			return values[len(values)-1]
+		}
+		log.Printf("calls: plans=%d batches=%d errors=%d | goroutines=%.0f rss=%.1fMiB native=%.1fMiB fds=%.0f",
+			stats.Plans.Load(), stats.Batches.Load(), stats.Errors.Load(),
+			last(soak.SeriesGoroutines), last(soak.SeriesRSS)/(1<<20),
+			last(soak.SeriesNativeMemory)/(1<<20), last(soak.SeriesOpenFDs))
+	}
+}
@@ -0,0 +1,90 @@
+// internal/action/contract.go
+package action
+
+import (
+	"fmt"
+	"math"
+)
+
+// Reasons a Contract check fails
+const (
+	ViolationLength    = "length"
+	ViolationNonFinite = "non_finite"
+	ViolationBounds    = "bounds"
+)
+
+// Bound is the inclusive range of one action value
+type Bound struct {
+	Min float32
+	Max float32
+}
+
+// Contract is the shape and range of the actions the service promises its
+// clients. Checking every inference result against it catches model or config
+// drift at the server instead of at the robot.
+type Contract struct {
+	// Dim is the number of values per action; 0 skips the length check
+	Dim int
+	// Bounds holds one Bound per action value, or a single Bound for all of
+	// them; empty leaves actions unbounded
+	Bounds []Bound
+}
+
+// ContractError describes actions that break a Contract
+type ContractError struct {
+	// Reason is one of the Violation constants
+	Reason string
+	msg    string
+}
+
+func (e *ContractError) Error() string {
+	return e.msg
+}
+
+// bound returns the bound of action value i, if any
+func (c Contract) bound(i int) (Bound, bool) {
+	switch len(c.Bounds) {
+	case 0:
+		return Bound{}, false
+	case 1:
+		return c.Bounds[0], true
+	}
+	if i < len(c.Bounds) {
+		return c.Bounds[i], true
+	}
+	return Bound{}, false
+}
+
+// Check verifies the flattened actions for batchSize observations, returning a
+// *ContractError on the first violation
+func (c Contract) Check(actions []float32, batchSize int) error {
+	if c.Dim > 0 && len(actions) != batchSize*c.Dim {
+		return &ContractError{
+			Reason: ViolationLength,
+			msg:    fmt.Sprintf("got %d action values for batch %d, expected %d per action", len(actions), batchSize, c.Dim),
+		}
+	}
+
+	dim := c.Dim
+	if dim == 0 && batchSize > 0 {
+		dim = len(actions) / batchSize
+	}
+	if dim == 0 {
+		dim = 1
+	}
+	for i, v := range actions {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return &ContractError{
+				Reason: ViolationNonFinite,
+				msg:    fmt.Sprintf("action %d value %d is %v", i/dim, i%dim, v),
+			}
+		}
+		if b, ok := c.bound(i % dim); ok && (v < b.Min || v > b.Max) {
+			return &ContractError{
+				Reason: ViolationBounds,
+				msg:    fmt.Sprintf("action %d value %d is %g, outside [%g, %g]", i/dim, i%dim, v, b.Min, b.Max),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// internal/action/contract_test.go
+package action
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestContract_Check(t *testing.T) {
+	c := Contract{Dim: 2, Bounds: []Bound{{Min: -1, Max: 1}, {Min: 0, Max: 2}}}
+
+	if err := c.Check([]float32{-1, 0, 0.5, 2}, 2); err != nil {
+		t.Fatalf("Expected actions within bounds to pass, got %v", err)
+	}
+
+	cases := []struct {
+		actions []float32
+		reason  string
+	}{
+		{[]float32{0, 0, 0}, ViolationLength},
+		{[]float32{0, 0, float32(math.NaN()), 1}, ViolationNonFinite},
+		{[]float32{0, float32(math.Inf(1)), 0, 1}, ViolationNonFinite},
+		{[]float32{0, 1, 0, 2.5}, ViolationBounds},
+	}
+	for _, tc := range cases {
+		err := c.Check(tc.actions, 2)
+		var violation *ContractError
+		if !errors.As(err, &violation) {
+			t.Fatalf("Expected ContractError for %v, got %v", tc.actions, err)
+		}
+		if violation.Reason != tc.reason {
+			t.Errorf("Expected %s violation for %v, got %s (%v)", tc.reason, tc.actions, violation.Reason, err)
+		}
+	}
+}
+
+func TestContract_SingleBoundAndUnknownDim(t *testing.T) {
+	// One bound applies to every value; Dim 0 takes the size from the batch
+	c := Contract{Bounds: []Bound{{Min: 0, Max: 1}}}
+	if err := c.Check([]float32{0, 1, 0.5}, 1); err != nil {
+		t.Fatalf("Expected pass, got %v", err)
+	}
+	if err := c.Check([]float32{0, 1, 1.5}, 1); err == nil {
+		t.Error("Expected bounds violation")
+	}
+}
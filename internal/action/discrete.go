@@ -0,0 +1,152 @@
+// Package action post-processes raw model outputs into robot actions
+package action
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Decoding modes for discrete-action policies
+const (
+	// ModeContinuous passes model outputs through unchanged
+	ModeContinuous = "continuous"
+	// ModeArgmax selects the highest-scoring discrete action
+	ModeArgmax = "argmax"
+	// ModeSample samples a discrete action from the softmax of the logits
+	ModeSample = "sample"
+)
+
+// Decoder converts flattened model outputs for a batch into flattened actions
+type Decoder interface {
+	// Decode maps the raw outputs for batchSize observations to actions
+	Decode(outputs []float32, batchSize int) ([]float32, error)
+}
+
+// DiscreteDecoder maps logits over a discrete action set to continuous actions
+// taken from a configured action table.
+type DiscreteDecoder struct {
+	mode        string
+	table       [][]float32
+	temperature float64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewDiscreteDecoder creates a decoder for the given mode ("argmax" or "sample").
+// table[i] is the continuous action returned when discrete action i is selected;
+// all rows must have the same length. temperature scales logits before sampling.
+func NewDiscreteDecoder(mode string, table [][]float32, temperature float64) (*DiscreteDecoder, error) {
+	if mode != ModeArgmax && mode != ModeSample {
+		return nil, fmt.Errorf("unsupported discrete action mode: %q", mode)
+	}
+	if len(table) == 0 {
+		return nil, fmt.Errorf("action table is empty")
+	}
+	for i, row := range table {
+		if len(row) == 0 || len(row) != len(table[0]) {
+			return nil, fmt.Errorf("action table row %d has %d values, expected %d", i, len(row), len(table[0]))
+		}
+	}
+	if temperature <= 0 {
+		temperature = 1
+	}
+
+	return &DiscreteDecoder{
+		mode:        mode,
+		table:       table,
+		temperature: temperature,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Seed resets the sampling source, making ModeSample deterministic
+func (d *DiscreteDecoder) Seed(seed int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rng = rand.New(rand.NewSource(seed))
+}
+
+// NumActions returns the size of the discrete action set
+func (d *DiscreteDecoder) NumActions() int {
+	return len(d.table)
+}
+
+// ActionDim returns the number of values in each decoded action
+func (d *DiscreteDecoder) ActionDim() int {
+	return len(d.table[0])
+}
+
+// Action returns a copy of the values decoded for discrete action i
+func (d *DiscreteDecoder) Action(i int) []float32 {
+	return append([]float32(nil), d.table[i]...)
+}
+
+// Mode returns the decoding mode, ModeArgmax or ModeSample
+func (d *DiscreteDecoder) Mode() string {
+	return d.mode
+}
+
+// Decode selects one discrete action per observation and returns the mapped
+// continuous actions, flattened to batchSize * len(table[0]) values
+func (d *DiscreteDecoder) Decode(logits []float32, batchSize int) ([]float32, error) {
+	n := len(d.table)
+	if batchSize <= 0 || len(logits) != batchSize*n {
+		return nil, fmt.Errorf("logits size mismatch: got %d values for batch %d over %d actions", len(logits), batchSize, n)
+	}
+
+	result := make([]float32, 0, batchSize*len(d.table[0]))
+	for i := 0; i < batchSize; i++ {
+		row := logits[i*n : (i+1)*n]
+
+		var idx int
+		if d.mode == ModeSample {
+			idx = d.sample(row)
+		} else {
+			idx = argmax(row)
+		}
+
+		result = append(result, d.table[idx]...)
+	}
+
+	return result, nil
+}
+
+// sample draws an index from softmax(logits / temperature)
+func (d *DiscreteDecoder) sample(logits []float32) int {
+	maxLogit := float64(logits[argmax(logits)])
+
+	probs := make([]float64, len(logits))
+	var sum float64
+	for i, l := range logits {
+		// Subtract the max logit for numerical stability
+		probs[i] = math.Exp((float64(l) - maxLogit) / d.temperature)
+		sum += probs[i]
+	}
+
+	d.mu.Lock()
+	r := d.rng.Float64() * sum
+	d.mu.Unlock()
+
+	for i, p := range probs {
+		r -= p
+		if r < 0 {
+			return i
+		}
+	}
+	return len(probs) - 1
+}
+
+// argmax returns the index of the largest value, preferring the lowest index on ties
+func argmax(values []float32) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,83 @@
+// internal/action/discrete_test.go
+package action
+
+import (
+	"testing"
+)
+
+var testTable = [][]float32{
+	{0, 0},  // stop
+	{1, 0},  // forward
+	{0, 1},  // turn left
+	{0, -1}, // turn right
+}
+
+func TestDiscreteDecoder_Argmax(t *testing.T) {
+	d, err := NewDiscreteDecoder(ModeArgmax, testTable, 1)
+	if err != nil {
+		t.Fatalf("NewDiscreteDecoder failed: %v", err)
+	}
+
+	logits := []float32{
+		0.1, 2.0, 0.3, 0.4, // forward
+		0.0, 0.1, 0.2, 5.0, // turn right
+	}
+
+	actions, err := d.Decode(logits, 2)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	expected := []float32{1, 0, 0, -1}
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d", len(expected), len(actions))
+	}
+	for i, v := range expected {
+		if actions[i] != v {
+			t.Errorf("Action[%d] = %f, expected %f", i, actions[i], v)
+		}
+	}
+}
+
+func TestDiscreteDecoder_SampleFollowsLogits(t *testing.T) {
+	d, err := NewDiscreteDecoder(ModeSample, testTable, 1)
+	if err != nil {
+		t.Fatalf("NewDiscreteDecoder failed: %v", err)
+	}
+	d.Seed(42)
+
+	// One overwhelmingly likely action should practically always be sampled
+	logits := []float32{-50, -50, 50, -50}
+	for i := 0; i < 100; i++ {
+		actions, err := d.Decode(logits, 1)
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if actions[0] != 0 || actions[1] != 1 {
+			t.Fatalf("Expected turn-left action, got %v", actions)
+		}
+	}
+}
+
+func TestDiscreteDecoder_SizeMismatch(t *testing.T) {
+	d, err := NewDiscreteDecoder(ModeArgmax, testTable, 1)
+	if err != nil {
+		t.Fatalf("NewDiscreteDecoder failed: %v", err)
+	}
+
+	if _, err := d.Decode([]float32{0.1, 0.2, 0.3}, 1); err == nil {
+		t.Fatal("Expected error for logits size mismatch")
+	}
+}
+
+func TestNewDiscreteDecoder_InvalidConfig(t *testing.T) {
+	if _, err := NewDiscreteDecoder("softmax", testTable, 1); err == nil {
+		t.Error("Expected error for unsupported mode")
+	}
+	if _, err := NewDiscreteDecoder(ModeArgmax, nil, 1); err == nil {
+		t.Error("Expected error for empty action table")
+	}
+	if _, err := NewDiscreteDecoder(ModeArgmax, [][]float32{{1, 2}, {3}}, 1); err == nil {
+		t.Error("Expected error for ragged action table")
+	}
+}
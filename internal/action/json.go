@@ -0,0 +1,82 @@
+// internal/action/json.go
+package action
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MaxJSONPrecision is the most decimal places JSONFormat writes; float32
+// carries no more than 9 significant digits
+const MaxJSONPrecision = 9
+
+// JSONFormat controls how actions are written as JSON. Numbers never depend
+// on the process locale: they always use '.' as the decimal separator, with
+// no digit grouping or exponent.
+type JSONFormat struct {
+	// Precision rounds each value to this many decimal places; 0 writes the
+	// shortest decimal that reads back as the same float32
+	Precision int
+	// Base64 writes the raw little-endian float32 values as one base64 string
+	// instead of numbers, so actions round-trip bit for bit
+	Base64 bool
+}
+
+// Encoding names the encoding f writes, as reported next to encoded actions
+func (f JSONFormat) Encoding() string {
+	if f.Base64 {
+		return "base64-f32le"
+	}
+	return ""
+}
+
+// Marshal writes a as a JSON array of numbers, or a base64 string. Non-finite
+// values have no JSON number form and are an error unless f is Base64.
+func (f JSONFormat) Marshal(a []float32) ([]byte, error) {
+	if f.Precision < 0 || f.Precision > MaxJSONPrecision {
+		return nil, fmt.Errorf("precision must be between 0 and %d, got %d", MaxJSONPrecision, f.Precision)
+	}
+	if f.Base64 {
+		raw := make([]byte, 4*len(a))
+		for i, v := range a {
+			binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+		}
+		return strconv.AppendQuote(nil, base64.StdEncoding.EncodeToString(raw)), nil
+	}
+
+	prec := f.Precision
+	if prec == 0 {
+		prec = -1
+	}
+	buf := make([]byte, 0, 2+12*len(a))
+	buf = append(buf, '[')
+	for i, v := range a {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return nil, fmt.Errorf("action value %d is %v, which JSON numbers cannot represent", i, v)
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendFloat(buf, float64(v), 'f', prec, 32)
+	}
+	return append(buf, ']'), nil
+}
+
+// DecodeBase64 reads an action written by a Base64 JSONFormat
+func DecodeBase64(s string) ([]float32, error) {
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 action: %w", err)
+	}
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("invalid base64 action: %d bytes is not a whole number of float32 values", len(raw))
+	}
+	a := make([]float32, len(raw)/4)
+	for i := range a {
+		a[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+	return a, nil
+}
@@ -0,0 +1,59 @@
+// internal/action/json_test.go
+package action
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJSONFormat_Marshal(t *testing.T) {
+	a := []float32{0.1, -2.5, 1e-7, 123456.79}
+
+	tests := []struct {
+		format JSONFormat
+		want   string
+	}{
+		{JSONFormat{}, `[0.1,-2.5,0.0000001,123456.79]`},
+		{JSONFormat{Precision: 3}, `[0.100,-2.500,0.000,123456.789]`},
+	}
+	for _, tt := range tests {
+		got, err := tt.format.Marshal(a)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Expected %s with precision %d, got %s", tt.want, tt.format.Precision, got)
+		}
+	}
+
+	if _, err := (JSONFormat{}).Marshal([]float32{float32(math.NaN())}); err == nil {
+		t.Error("Expected NaN to be rejected as a JSON number")
+	}
+	if _, err := (JSONFormat{Precision: 12}).Marshal(a); err == nil {
+		t.Error("Expected a precision above the maximum to be rejected")
+	}
+}
+
+func TestJSONFormat_Base64RoundTrip(t *testing.T) {
+	a := []float32{0.1, -2.5, float32(math.Inf(-1)), math.SmallestNonzeroFloat32}
+	got, err := JSONFormat{Base64: true}.Marshal(a)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got[0] != '"' {
+		t.Fatalf("Expected a JSON string, got %s", got)
+	}
+
+	decoded, err := DecodeBase64(string(got[1 : len(got)-1]))
+	if err != nil {
+		t.Fatalf("DecodeBase64 failed: %v", err)
+	}
+	for i := range a {
+		if math.Float32bits(decoded[i]) != math.Float32bits(a[i]) {
+			t.Fatalf("Expected %v to round-trip bit for bit, got %v", a, decoded)
+		}
+	}
+	if _, err := DecodeBase64("AAA="); err == nil {
+		t.Error("Expected a partial float32 to be rejected")
+	}
+}
@@ -0,0 +1,81 @@
+// internal/action/space.go
+package action
+
+import "fmt"
+
+// Kinds of action space
+const (
+	SpaceContinuous = "continuous"
+	SpaceDiscrete   = "discrete"
+)
+
+// Dimension is one value of an action
+type Dimension struct {
+	Name string
+	// Bound is nil if the value is unbounded
+	Bound *Bound
+}
+
+// Choice is one member of a discrete action set
+type Choice struct {
+	Label  string
+	Values []float32
+}
+
+// Space describes how to interpret the raw values of an action, so tooling
+// downstream of the service reads them consistently
+type Space struct {
+	// Kind is SpaceContinuous or SpaceDiscrete
+	Kind string
+	// Dims has one entry per action value; empty if the dimension is unknown
+	Dims []Dimension
+	// Choices is the discrete action set, by index
+	Choices []Choice
+}
+
+// NewSpace describes actions checked against contract, naming their values
+// with names. A non-nil decoder makes the space discrete, its choices labelled
+// with labels. names and labels may be empty; otherwise there must be one per
+// action value and discrete action respectively.
+func NewSpace(contract Contract, decoder *DiscreteDecoder, names, labels []string) (Space, error) {
+	space := Space{Kind: SpaceContinuous}
+	dim := contract.Dim
+	if dim == 0 && decoder != nil {
+		dim = decoder.ActionDim()
+	}
+	if dim == 0 {
+		dim = len(names)
+	}
+	if len(names) > 0 && len(names) != dim {
+		return space, fmt.Errorf("got %d action value names for %d values per action", len(names), dim)
+	}
+	for i := 0; i < dim; i++ {
+		d := Dimension{}
+		if len(names) > 0 {
+			d.Name = names[i]
+		}
+		if b, ok := contract.bound(i); ok {
+			d.Bound = &b
+		}
+		space.Dims = append(space.Dims, d)
+	}
+
+	if decoder == nil {
+		if len(labels) > 0 {
+			return space, fmt.Errorf("action labels need a discrete action set")
+		}
+		return space, nil
+	}
+	if len(labels) > 0 && len(labels) != decoder.NumActions() {
+		return space, fmt.Errorf("got %d action labels for %d discrete actions", len(labels), decoder.NumActions())
+	}
+	space.Kind = SpaceDiscrete
+	for i := 0; i < decoder.NumActions(); i++ {
+		c := Choice{Values: decoder.Action(i)}
+		if len(labels) > 0 {
+			c.Label = labels[i]
+		}
+		space.Choices = append(space.Choices, c)
+	}
+	return space, nil
+}
@@ -0,0 +1,50 @@
+// internal/action/space_test.go
+package action
+
+import "testing"
+
+func TestNewSpace_Continuous(t *testing.T) {
+	c := Contract{Dim: 2, Bounds: []Bound{{Min: -1, Max: 1}}}
+	space, err := NewSpace(c, nil, []string{"linear_x", "angular_z"}, nil)
+	if err != nil {
+		t.Fatalf("NewSpace failed: %v", err)
+	}
+	if space.Kind != SpaceContinuous || len(space.Dims) != 2 || len(space.Choices) != 0 {
+		t.Fatalf("Expected a 2-value continuous space, got %+v", space)
+	}
+	// A single bound applies to every value
+	for i, name := range []string{"linear_x", "angular_z"} {
+		d := space.Dims[i]
+		if d.Name != name || d.Bound == nil || d.Bound.Min != -1 || d.Bound.Max != 1 {
+			t.Errorf("Expected %s bounded by [-1, 1], got %+v", name, d)
+		}
+	}
+
+	if _, err := NewSpace(c, nil, []string{"linear_x"}, nil); err == nil {
+		t.Error("Expected an error for too few value names")
+	}
+	if _, err := NewSpace(c, nil, nil, []string{"stop"}); err == nil {
+		t.Error("Expected an error for labels without a discrete action set")
+	}
+}
+
+func TestNewSpace_Discrete(t *testing.T) {
+	d, err := NewDiscreteDecoder(ModeArgmax, [][]float32{{0, 0}, {1, 0}}, 1)
+	if err != nil {
+		t.Fatalf("NewDiscreteDecoder failed: %v", err)
+	}
+	space, err := NewSpace(Contract{}, d, nil, []string{"stop", "forward"})
+	if err != nil {
+		t.Fatalf("NewSpace failed: %v", err)
+	}
+	if space.Kind != SpaceDiscrete || len(space.Dims) != 2 || space.Dims[0].Bound != nil {
+		t.Fatalf("Expected a discrete space of 2 unbounded values, got %+v", space)
+	}
+	if len(space.Choices) != 2 || space.Choices[1].Label != "forward" || space.Choices[1].Values[0] != 1 {
+		t.Errorf("Expected the labelled action table, got %+v", space.Choices)
+	}
+
+	if _, err := NewSpace(Contract{}, d, nil, []string{"stop"}); err == nil {
+		t.Error("Expected an error for a label count not matching the action set")
+	}
+}
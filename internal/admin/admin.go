@@ -0,0 +1,70 @@
+// Package admin serves operator endpoints under /admin/ on the metrics port
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Prefix is the path prefix for all admin endpoints
+const Prefix = "/admin/"
+
+// Mux routes admin requests and enforces the admin bearer token
+type Mux struct {
+	mux   *http.ServeMux
+	token string
+}
+
+// New creates a Mux. When token is non-empty every request must carry
+// "Authorization: Bearer <token>".
+func New(token string) *Mux {
+	m := &Mux{
+		mux:   http.NewServeMux(),
+		token: token,
+	}
+	m.mux.HandleFunc("GET "+Prefix+"{$}", m.index)
+	return m
+}
+
+// Handle registers h for pattern, which may include a method (e.g. "POST /admin/rollout/advance")
+func (m *Mux) Handle(pattern string, h http.Handler) {
+	m.mux.Handle(pattern, h)
+}
+
+// HandleFunc registers f for pattern
+func (m *Mux) HandleFunc(pattern string, f func(http.ResponseWriter, *http.Request)) {
+	m.mux.HandleFunc(pattern, f)
+}
+
+// ServeHTTP implements http.Handler
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.token != "" && !m.authorized(r) {
+		WriteError(w, http.StatusUnauthorized, "missing or invalid admin token")
+		return
+	}
+	m.mux.ServeHTTP(w, r)
+}
+
+func (m *Mux) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) == 1
+}
+
+// index confirms the admin API is reachable
+func (m *Mux) index(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// WriteJSON writes v as a JSON response with the given status code
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes a JSON error response
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	WriteJSON(w, status, map[string]string{"error": msg})
+}
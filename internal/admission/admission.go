@@ -0,0 +1,154 @@
+// Package admission turns away new planning calls while the service is
+// overloaded, so the calls it admits keep their latency instead of every call
+// slowing down together.
+//
+// A call is rejected when MaxInFlight calls are already running, or when
+// inference batches have waited longer than MaxQueueLatency for a whole
+// interval. Like CoDel, the queue check uses the shortest wait seen in each
+// interval: a burst that drains quickly still leaves some short waits, while
+// a standing queue makes every wait long.
+package admission
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Reasons a call is rejected
+const (
+	ReasonInFlight     = "in_flight"
+	ReasonQueueLatency = "queue_latency"
+)
+
+// Limits decide when calls are rejected; a zero limit is not enforced
+type Limits struct {
+	// MaxInFlight is the most admitted calls running at once
+	MaxInFlight int64
+	// MaxQueueLatency is the longest inference batches may keep waiting
+	MaxQueueLatency time.Duration
+	// Interval is how long waits must stay over MaxQueueLatency, e.g. 100ms
+	Interval time.Duration
+}
+
+// Status is the controller's current state, as served by the admin API
+type Status struct {
+	InFlight    int64 `json:"in_flight"`
+	MaxInFlight int64 `json:"max_in_flight"`
+	// QueueLatency is the shortest wait in the last full interval
+	QueueLatency    string           `json:"queue_latency"`
+	MaxQueueLatency string           `json:"max_queue_latency"`
+	QueueOverloaded bool             `json:"queue_overloaded"`
+	Rejected        map[string]int64 `json:"rejected"`
+}
+
+// Controller admits or rejects new calls against its limits
+type Controller struct {
+	limits Limits
+	now    func() time.Time
+
+	inFlight       atomic.Int64
+	rejectedFlight atomic.Int64
+	rejectedQueue  atomic.Int64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowMin   time.Duration // Shortest wait in the current interval
+	sampled     bool          // Whether windowMin holds a wait
+	lastMin     time.Duration // Shortest wait in the last full interval
+	overloaded  bool
+}
+
+// New creates a Controller enforcing limits
+func New(limits Limits) *Controller {
+	if limits.Interval <= 0 {
+		limits.Interval = 100 * time.Millisecond
+	}
+	return &Controller{limits: limits, now: time.Now}
+}
+
+// Admit reserves a slot for a new call, or returns the reason it is rejected.
+// Admitted calls must call Done when they finish.
+func (c *Controller) Admit() (reason string, ok bool) {
+	if c.limits.MaxQueueLatency > 0 && c.QueueOverloaded() {
+		c.rejectedQueue.Add(1)
+		return ReasonQueueLatency, false
+	}
+	n := c.inFlight.Add(1)
+	if c.limits.MaxInFlight > 0 && n > c.limits.MaxInFlight {
+		c.inFlight.Add(-1)
+		c.rejectedFlight.Add(1)
+		return ReasonInFlight, false
+	}
+	metrics.AddAdmissionInFlight(1)
+	return "", true
+}
+
+// Done releases the slot of an admitted call
+func (c *Controller) Done() {
+	c.inFlight.Add(-1)
+	metrics.AddAdmissionInFlight(-1)
+}
+
+// ObserveQueueWait records how long an inference batch waited to run
+func (c *Controller) ObserveQueueWait(wait time.Duration) {
+	if c.limits.MaxQueueLatency <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollLocked(c.now())
+	if !c.sampled || wait < c.windowMin {
+		c.windowMin = wait
+		c.sampled = true
+	}
+}
+
+// QueueOverloaded reports whether every wait in the last full interval
+// exceeded MaxQueueLatency
+func (c *Controller) QueueOverloaded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollLocked(c.now())
+	return c.overloaded
+}
+
+// rollLocked closes the current interval once it has ended. An interval
+// followed by one without any waits is stale: nothing has queued since, so
+// the overload is cleared.
+func (c *Controller) rollLocked(now time.Time) {
+	elapsed := now.Sub(c.windowStart)
+	if elapsed < c.limits.Interval {
+		return
+	}
+	c.lastMin = 0
+	if c.sampled && elapsed < 2*c.limits.Interval {
+		c.lastMin = c.windowMin
+	}
+	c.overloaded = c.lastMin > c.limits.MaxQueueLatency
+	c.windowStart = now
+	c.windowMin = 0
+	c.sampled = false
+	metrics.SetAdmissionQueueLatency(c.lastMin.Seconds())
+}
+
+// Status returns the current in-flight count, queue latency, and rejections
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	c.rollLocked(c.now())
+	lastMin, overloaded := c.lastMin, c.overloaded
+	c.mu.Unlock()
+	return Status{
+		InFlight:        c.inFlight.Load(),
+		MaxInFlight:     c.limits.MaxInFlight,
+		QueueLatency:    lastMin.String(),
+		MaxQueueLatency: c.limits.MaxQueueLatency.String(),
+		QueueOverloaded: overloaded,
+		Rejected: map[string]int64{
+			ReasonInFlight:     c.rejectedFlight.Load(),
+			ReasonQueueLatency: c.rejectedQueue.Load(),
+		},
+	}
+}
@@ -0,0 +1,94 @@
+// internal/admission/admission_test.go
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestController_RejectsOverMaxInFlight(t *testing.T) {
+	c := New(Limits{MaxInFlight: 2})
+	for i := 0; i < 2; i++ {
+		if _, ok := c.Admit(); !ok {
+			t.Fatalf("Expected call %d admitted", i)
+		}
+	}
+	if reason, ok := c.Admit(); ok || reason != ReasonInFlight {
+		t.Fatalf("Expected rejection for in-flight calls, got ok=%v reason=%q", ok, reason)
+	}
+
+	c.Done()
+	if _, ok := c.Admit(); !ok {
+		t.Fatal("Expected a call admitted after one finished")
+	}
+	status := c.Status()
+	if status.InFlight != 2 || status.Rejected[ReasonInFlight] != 1 {
+		t.Fatalf("Expected 2 in flight and 1 rejection, got %+v", status)
+	}
+}
+
+func TestController_RejectsWhileQueueStaysLong(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := New(Limits{MaxQueueLatency: 10 * time.Millisecond, Interval: 100 * time.Millisecond})
+	c.now = func() time.Time { return now }
+
+	// A burst: one short wait in the interval keeps calls admitted
+	c.ObserveQueueWait(50 * time.Millisecond)
+	c.ObserveQueueWait(time.Millisecond)
+	now = now.Add(100 * time.Millisecond)
+	if _, ok := c.Admit(); !ok {
+		t.Fatal("Expected calls admitted after an interval with a short wait")
+	}
+	c.Done()
+
+	// A standing queue: every wait in the interval is over the limit
+	c.ObserveQueueWait(20 * time.Millisecond)
+	c.ObserveQueueWait(30 * time.Millisecond)
+	now = now.Add(100 * time.Millisecond)
+	if reason, ok := c.Admit(); ok || reason != ReasonQueueLatency {
+		t.Fatalf("Expected rejection for queue latency, got ok=%v reason=%q", ok, reason)
+	}
+	if status := c.Status(); !status.QueueOverloaded || status.QueueLatency != "20ms" {
+		t.Fatalf("Expected an overloaded queue at 20ms, got %+v", status)
+	}
+
+	// Nothing queued for an interval: the overload clears
+	now = now.Add(100 * time.Millisecond)
+	if _, ok := c.Admit(); !ok {
+		t.Fatal("Expected calls admitted once the queue went quiet")
+	}
+}
+
+func TestController_UnaryInterceptor(t *testing.T) {
+	c := New(Limits{MaxInFlight: 1})
+	interceptor := c.UnaryInterceptor(map[string]bool{"/test.Service/Plan": true})
+	plan := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Plan"}
+	other := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Other"}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	// A call still running holds the only slot
+	var inner error
+	_, err := interceptor(context.Background(), nil, plan, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, inner = interceptor(ctx, nil, plan, ok)
+		if _, err := interceptor(ctx, nil, other, ok); err != nil {
+			t.Errorf("Expected other methods admitted, got %v", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the first call admitted, got %v", err)
+	}
+	if status.Code(inner) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted while the slot is taken, got %v", inner)
+	}
+	if _, err := interceptor(context.Background(), nil, plan, ok); err != nil {
+		t.Fatalf("Expected the slot released after the call, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+// internal/admission/grpc.go
+package admission
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// rejectedErrors are returned to rejected calls; clients should back off
+var rejectedErrors = map[string]error{
+	ReasonInFlight:     status.Error(codes.ResourceExhausted, "server overloaded: too many requests in flight"),
+	ReasonQueueLatency: status.Error(codes.ResourceExhausted, "server overloaded: inference queue latency over its limit"),
+}
+
+// UnaryInterceptor admits calls to methods, keyed by full gRPC method name,
+// through the controller; calls to other methods pass straight through
+func (c *Controller) UnaryInterceptor(methods map[string]bool) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		reason, ok := c.Admit()
+		if !ok {
+			metrics.RecordAdmissionRejected(info.FullMethod, reason)
+			return nil, rejectedErrors[reason]
+		}
+		defer c.Done()
+		return handler(ctx, req)
+	}
+}
+
+// Register adds the admission status endpoint to the admin API
+func (c *Controller) Register(m *admin.Mux) {
+	m.HandleFunc("GET /admin/admission", func(w http.ResponseWriter, r *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, c.Status())
+	})
+}
@@ -0,0 +1,81 @@
+// Package affinity pins the process to specific CPU cores or a NUMA node and
+// builds matching thread affinities for ONNX Runtime's intra-op pool
+package affinity
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned by Pin on platforms without CPU affinity support
+var ErrUnsupported = errors.New("CPU affinity is not supported on this platform")
+
+// nodeCPUListPath is where Linux lists the CPUs of each NUMA node
+const nodeCPUListPath = "/sys/devices/system/node/node%d/cpulist"
+
+// ParseCPUList parses a Linux CPU list such as "0-3,8,10-11" into sorted,
+// distinct CPU indices
+func ParseCPUList(s string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(strings.TrimSpace(s), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("invalid CPU list %q: bad CPU %q", s, lo)
+		}
+		last := first
+		if isRange {
+			last, err = strconv.Atoi(hi)
+			if err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU list %q: bad range %q", s, part)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			seen[cpu] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("invalid CPU list %q: no CPUs", s)
+	}
+
+	cpus := make([]int, 0, len(seen))
+	for cpu := range seen {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// NodeCPUs returns the CPUs belonging to a NUMA node
+func NodeCPUs(node int) ([]int, error) {
+	data, err := os.ReadFile(fmt.Sprintf(nodeCPUListPath, node))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPUs of NUMA node %d: %w", node, err)
+	}
+	return ParseCPUList(string(data))
+}
+
+// IntraOpAffinities spreads the intra-op pool's threads over cpus in ORT's
+// "session.intra_op_thread_affinities" format. ORT runs the first of its
+// threads on the calling thread, so only threads-1 entries are produced, and
+// processors are numbered from 1.
+func IntraOpAffinities(cpus []int, threads int) string {
+	if len(cpus) == 0 || threads < 2 {
+		return ""
+	}
+
+	entries := make([]string, 0, threads-1)
+	for i := 1; i < threads; i++ {
+		entries = append(entries, strconv.Itoa(cpus[i%len(cpus)]+1))
+	}
+	return strings.Join(entries, ";")
+}
@@ -0,0 +1,38 @@
+// internal/affinity/affinity_linux.go
+
+//go:build linux
+
+package affinity
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// Pin restricts every thread of the process to cpus. Threads created later
+// (Go runtime and ONNX Runtime pools) inherit the mask from their creator.
+func Pin(cpus []int) error {
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return fmt.Errorf("failed to list threads: %w", err)
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		// Threads can exit while we iterate
+		if err := unix.SchedSetaffinity(tid, &set); err != nil && err != unix.ESRCH {
+			return fmt.Errorf("failed to pin thread %d: %w", tid, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+// internal/affinity/affinity_other.go
+
+//go:build !linux
+
+package affinity
+
+// Pin is not supported outside Linux
+func Pin(cpus []int) error {
+	return ErrUnsupported
+}
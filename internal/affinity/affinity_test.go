@@ -0,0 +1,35 @@
+// internal/affinity/affinity_test.go
+package affinity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := ParseCPUList("8-10, 2,0-1,9\n")
+	if err != nil {
+		t.Fatalf("ParseCPUList failed: %v", err)
+	}
+	if want := []int{0, 1, 2, 8, 9, 10}; !reflect.DeepEqual(cpus, want) {
+		t.Errorf("Expected %v, got %v", want, cpus)
+	}
+
+	for _, bad := range []string{"", "a", "3-1", "-2", "1,,x"} {
+		if _, err := ParseCPUList(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestIntraOpAffinities(t *testing.T) {
+	if got := IntraOpAffinities([]int{4, 5, 6, 7}, 4); got != "6;7;8" {
+		t.Errorf("Expected 6;7;8, got %q", got)
+	}
+	if got := IntraOpAffinities([]int{0, 1}, 4); got != "2;1;2" {
+		t.Errorf("Expected threads to wrap around the CPUs, got %q", got)
+	}
+	if got := IntraOpAffinities([]int{0, 1}, 1); got != "" {
+		t.Errorf("Expected no affinities for a single thread, got %q", got)
+	}
+}
@@ -0,0 +1,397 @@
+// internal/auth/auth_test.go
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/signing"
+)
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func withMetadata(kv ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+// testJWTProvider returns a provider and a key that signs tokens it accepts
+func testJWTProvider(t *testing.T) (*JWTProvider, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	path := filepath.Join(t.TempDir(), "jwt.pub")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	p, err := NewJWTProvider(JWTConfig{PublicKeyFile: path})
+	if err != nil {
+		t.Fatalf("NewJWTProvider failed: %v", err)
+	}
+	return p, key
+}
+
+func signToken(t *testing.T, key *ecdsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestAPIKeyProvider(t *testing.T) {
+	p, err := NewAPIKeyProvider(map[string]string{"fleet-a": hashKey("secret-a")})
+	if err != nil {
+		t.Fatalf("NewAPIKeyProvider failed: %v", err)
+	}
+
+	id, err := p.Authenticate(withMetadata(APIKeyHeader, "secret-a"))
+	if err != nil || id.Subject != "fleet-a" {
+		t.Fatalf("Expected fleet-a, got %+v, %v", id, err)
+	}
+	if _, err := p.Authenticate(withMetadata(APIKeyHeader, "secret-b")); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Expected an unknown key error, got %v", err)
+	}
+	if _, err := p.Authenticate(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Expected ErrNoCredentials, got %v", err)
+	}
+	if _, err := NewAPIKeyProvider(map[string]string{"fleet-a": "plaintext"}); err == nil {
+		t.Fatal("Expected error for a key that is not a SHA-256 hash")
+	}
+}
+
+func TestJWTProvider(t *testing.T) {
+	p, key := testJWTProvider(t)
+
+	valid := signToken(t, key, jwt.MapClaims{"sub": "controller-1", "exp": time.Now().Add(time.Hour).Unix()})
+	id, err := p.Authenticate(withMetadata("authorization", "Bearer "+valid))
+	if err != nil || id.Subject != "controller-1" {
+		t.Fatalf("Expected controller-1, got %+v, %v", id, err)
+	}
+
+	expired := signToken(t, key, jwt.MapClaims{"sub": "controller-1", "exp": time.Now().Add(-time.Hour).Unix()})
+	if _, err := p.Authenticate(withMetadata("authorization", "Bearer "+expired)); err == nil {
+		t.Fatal("Expected error for an expired token")
+	}
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	forged := signToken(t, other, jwt.MapClaims{"sub": "controller-1", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := p.Authenticate(withMetadata("authorization", "Bearer "+forged)); err == nil {
+		t.Fatal("Expected error for a token signed with another key")
+	}
+}
+
+// staticKeys is a KeySource serving fixed keys
+type staticKeys struct {
+	keys   []signing.Key
+	active string
+}
+
+func (s *staticKeys) Load() ([]signing.Key, string, error) { return s.keys, s.active, nil }
+
+func newEd25519Key(t *testing.T, id string) signing.Key {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return signing.Key{ID: id, PrivateKey: priv}
+}
+
+func TestRobotTokens(t *testing.T) {
+	source := &staticKeys{keys: []signing.Key{newEd25519Key(t, "k1")}, active: "k1"}
+	tokens, err := NewRobotTokens(source, "policy-service")
+	if err != nil {
+		t.Fatalf("NewRobotTokens failed: %v", err)
+	}
+
+	token, keyID, expires, err := tokens.Issue("robot/7", "acme", 7, time.Hour)
+	if err != nil || keyID != "k1" || time.Until(expires) < 59*time.Minute {
+		t.Fatalf("Expected a token signed with k1 lasting an hour, got %q, %v, %v", keyID, expires, err)
+	}
+	id, err := tokens.Authenticate(withMetadata("authorization", "Bearer "+token))
+	if err != nil || id.Subject != "robot/7" || id.Claims[RobotIDClaim] != "7" || id.Tenant != "acme" {
+		t.Fatalf("Expected robot/7 of acme with robot_id 7, got %+v, %v", id, err)
+	}
+
+	// Tokens signed before a rotation stay valid while their key is loaded
+	source.keys = append(source.keys, newEd25519Key(t, "k2"))
+	source.active = "k2"
+	if err := tokens.Reload(); err != nil || tokens.ActiveKeyID() != "k2" {
+		t.Fatalf("Expected k2 active after reload, got %q, %v", tokens.ActiveKeyID(), err)
+	}
+	if _, err := tokens.Authenticate(withMetadata("authorization", "Bearer "+token)); err != nil {
+		t.Fatalf("Expected a k1 token accepted after rotation, got %v", err)
+	}
+
+	tokens.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	expired, _, _, _ := tokens.Issue("robot/7", "acme", 7, time.Hour)
+	if _, err := tokens.Authenticate(withMetadata("authorization", "Bearer "+expired)); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Expected error for an expired token, got %v", err)
+	}
+
+	// Tokens from another issuer are left to the next provider
+	other, err := NewRobotTokens(&staticKeys{keys: []signing.Key{newEd25519Key(t, "k1")}, active: "k1"}, "other-service")
+	if err != nil {
+		t.Fatalf("NewRobotTokens failed: %v", err)
+	}
+	foreign, _, _, _ := other.Issue("robot/7", "acme", 7, time.Hour)
+	if _, err := tokens.Authenticate(withMetadata("authorization", "Bearer "+foreign)); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Expected ErrNoCredentials for another issuer, got %v", err)
+	}
+}
+
+// ecJWK returns the JWKS entry for key
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "use": "sig", "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y": base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func TestJWTProvider_JWKS(t *testing.T) {
+	k1, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	k2, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var mu sync.Mutex
+	published := []map[string]string{ecJWK("k1", k1)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": published})
+	}))
+	defer srv.Close()
+
+	p, err := NewJWTProvider(JWTConfig{JWKSURL: srv.URL, JWKSRefresh: time.Hour, Issuer: "https://idp.example", Audience: "policy-service"})
+	if err != nil {
+		t.Fatalf("NewJWTProvider failed: %v", err)
+	}
+	p.jwks.minRefetch = 0
+
+	sign := func(kid string, key *ecdsa.PrivateKey, claims jwt.MapClaims) context.Context {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = kid
+		raw, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return withMetadata("authorization", "Bearer "+raw)
+	}
+	claims := func(aud string, exp time.Duration) jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "controller-1", "iss": "https://idp.example", "aud": aud,
+			"exp": time.Now().Add(exp).Unix(), "role": "operator",
+		}
+	}
+
+	id, err := p.Authenticate(sign("k1", k1, claims("policy-service", time.Hour)))
+	if err != nil || id.Subject != "controller-1" || id.Claims["role"] != "operator" {
+		t.Fatalf("Expected controller-1 with its claims, got %+v, %v", id, err)
+	}
+	if _, err := p.Authenticate(sign("k1", k1, claims("other-service", time.Hour))); err == nil {
+		t.Fatal("Expected error for a token for another audience")
+	}
+	if _, err := p.Authenticate(sign("k1", k1, claims("policy-service", -time.Minute))); err == nil {
+		t.Fatal("Expected error for an expired token")
+	}
+
+	// A key published after the first fetch is picked up on first use
+	mu.Lock()
+	published = append(published, ecJWK("k2", k2))
+	mu.Unlock()
+	if _, err := p.Authenticate(sign("k2", k2, claims("policy-service", time.Hour))); err != nil {
+		t.Fatalf("Expected rotated key to be fetched, got %v", err)
+	}
+	if _, err := p.Authenticate(sign("k3", k2, claims("policy-service", time.Hour))); err == nil {
+		t.Fatal("Expected error for an unknown key ID")
+	}
+}
+
+// withPeerCert returns a context whose TLS peer presented a certificate with
+// the given URI SAN
+func withPeerCert(t *testing.T, uri string) context.Context {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	u, _ := url.Parse(uri)
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), URIs: []*url.URL{u}, NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+}
+
+func TestSPIFFEProvider(t *testing.T) {
+	p, err := NewSPIFFEProvider("example.org", []SPIFFERole{
+		{ID: "spiffe://example.org/robot/*", Roles: []string{"robot"}},
+		{ID: "spiffe://example.org/robot/7", Roles: []string{"robot", "canary"}},
+		{ID: "spiffe://example.org/controller", Roles: []string{"operator"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSPIFFEProvider failed: %v", err)
+	}
+
+	id, err := p.Authenticate(withPeerCert(t, "spiffe://example.org/robot/7"))
+	if err != nil {
+		t.Fatalf("Expected SVID to authenticate, got %v", err)
+	}
+	if id.Subject != "spiffe://example.org/robot/7" || len(id.Roles) != 2 || id.Roles[0] != "robot" || id.Roles[1] != "canary" {
+		t.Fatalf("Expected robot/7 with roles [robot canary], got %+v", id)
+	}
+	if id, _ := p.Authenticate(withPeerCert(t, "spiffe://example.org/controller")); len(id.Roles) != 1 || id.Roles[0] != "operator" {
+		t.Fatalf("Expected operator role, got %+v", id)
+	}
+
+	if _, err := p.Authenticate(withPeerCert(t, "spiffe://example.org/analytics")); err == nil {
+		t.Fatal("Expected error for an ID with no role")
+	}
+	if _, err := p.Authenticate(withPeerCert(t, "spiffe://other.org/robot/7")); err == nil {
+		t.Fatal("Expected error for another trust domain")
+	}
+	if _, err := p.Authenticate(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Expected ErrNoCredentials without a peer certificate, got %v", err)
+	}
+
+	if _, err := NewSPIFFEProvider("example.org", []SPIFFERole{{ID: "spiffe://other.org/robot/*"}}); err == nil {
+		t.Fatal("Expected error for a role in another trust domain")
+	}
+}
+
+func TestChain_FallbackAndRejection(t *testing.T) {
+	apiKeys, _ := NewAPIKeyProvider(map[string]string{"fleet-a": hashKey("secret-a")})
+	jwtProvider, _ := testJWTProvider(t)
+	chain := NewChain([]Provider{MTLSProvider{}, jwtProvider, apiKeys}, "/grpc.health.v1.Health/Check")
+	interceptor := chain.UnaryInterceptor()
+
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "response", nil
+	}
+	plan := &grpc.UnaryServerInfo{FullMethod: "/planner.PathPlanner/Plan"}
+
+	// No mTLS identity or token, so the API key decides
+	if _, err := interceptor(withMetadata(APIKeyHeader, "secret-a"), nil, plan, handler); err != nil {
+		t.Fatalf("Expected API key fallback to succeed, got %v", err)
+	}
+	id, _ := FromContext(captured)
+	if id.Subject != "fleet-a" || id.Provider != "api_key" || middleware.GetClientIdentity(captured) != "fleet-a" {
+		t.Fatalf("Expected fleet-a from api_key, got %+v", id)
+	}
+
+	// mTLS comes first
+	ctx := middleware.WithClientIdentity(withMetadata(APIKeyHeader, "secret-a"), "robot-7")
+	if _, err := interceptor(ctx, nil, plan, handler); err != nil {
+		t.Fatalf("Expected mTLS to succeed, got %v", err)
+	}
+	if id, _ := FromContext(captured); id.Subject != "robot-7" || id.Provider != "mtls" {
+		t.Fatalf("Expected robot-7 from mtls, got %+v", id)
+	}
+
+	// An invalid token is rejected even though a valid API key follows
+	ctx = withMetadata("authorization", "Bearer not-a-jwt", APIKeyHeader, "secret-a")
+	if _, err := interceptor(ctx, nil, plan, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Expected Unauthenticated for an invalid token, got %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, plan, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Expected Unauthenticated without credentials, got %v", err)
+	}
+
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := interceptor(context.Background(), nil, health, handler); err != nil {
+		t.Fatalf("Expected exempt method to pass, got %v", err)
+	}
+}
+
+func TestChain_Tenants(t *testing.T) {
+	apiKeys, _ := NewAPIKeyProvider(map[string]string{"fleet-a": hashKey("secret-a"), "ops": hashKey("secret-ops")})
+	jwtProvider, key := testJWTProvider(t)
+	chain := NewChain([]Provider{jwtProvider, apiKeys}).WithTenants(Tenants{
+		Claim:    TenantClaim,
+		Subjects: map[string]string{"fleet-a": "acme", "robot/*": "robots"},
+	})
+	interceptor := chain.UnaryInterceptor()
+
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "response", nil
+	}
+	plan := &grpc.UnaryServerInfo{FullMethod: "/planner.PathPlanner/Plan"}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		tenant string
+	}{
+		{"subject mapping", withMetadata(APIKeyHeader, "secret-a"), "acme"},
+		{"matching header", withMetadata(APIKeyHeader, "secret-a", middleware.TenantIDHeader, "acme"), "acme"},
+		{"unmapped subject", withMetadata(APIKeyHeader, "secret-ops"), middleware.DefaultTenant},
+		{"claim", withMetadata("authorization", "Bearer "+signToken(t, key, jwt.MapClaims{
+			"sub": "robot/7", "tenant": "globex", "exp": time.Now().Add(time.Hour).Unix(),
+		})), "globex"},
+		{"subject prefix", withMetadata("authorization", "Bearer "+signToken(t, key, jwt.MapClaims{
+			"sub": "robot/7", "exp": time.Now().Add(time.Hour).Unix(),
+		})), "robots"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := interceptor(tt.ctx, nil, plan, handler); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			id, _ := FromContext(captured)
+			if got := middleware.GetTenantID(captured); got != tt.tenant || id.Tenant != tt.tenant {
+				t.Errorf("Expected tenant %q, got %q (identity %q)", tt.tenant, got, id.Tenant)
+			}
+		})
+	}
+
+	// A caller cannot reach another tenant's state by naming it in the header
+	for _, spoofed := range []string{"globex", middleware.DefaultTenant} {
+		ctx := withMetadata(APIKeyHeader, "secret-a", middleware.TenantIDHeader, spoofed)
+		if _, err := interceptor(ctx, nil, plan, handler); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for header %q, got %v", spoofed, err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// internal/auth/jwks.go
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minJWKSRefetch limits how often a token with an unknown key ID can make the
+// provider fetch the JWKS again
+const minJWKSRefetch = time.Minute
+
+// jwk is one JSON Web Key; only public key fields are read
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKS caches the signing keys an identity provider publishes at a JWKS URL.
+// Keys are refetched in the background every refresh interval, and right away
+// (at most once per minute) when a token names an unknown key.
+type JWKS struct {
+	url        string
+	refresh    time.Duration
+	minRefetch time.Duration
+	client     *http.Client
+
+	fetchMu sync.Mutex // Serializes fetches
+
+	mu         sync.Mutex
+	keys       map[string]crypto.PublicKey
+	fetched    time.Time
+	attempted  time.Time
+	refreshing bool
+}
+
+// NewJWKS creates a JWKS for url; keys are fetched on first use
+func NewJWKS(url string, refresh time.Duration) *JWKS {
+	return &JWKS{
+		url:        url,
+		refresh:    refresh,
+		minRefetch: minJWKSRefetch,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Key returns the public key with ID kid
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := j.lookup(kid, true); ok {
+		return key, nil
+	}
+
+	// Keys rotate: an unknown ID may be a key published since the last fetch
+	j.fetchMu.Lock()
+	defer j.fetchMu.Unlock()
+	if key, ok := j.lookup(kid, false); ok {
+		return key, nil // Fetched while waiting
+	}
+	j.mu.Lock()
+	recent := time.Since(j.attempted) < j.minRefetch
+	j.mu.Unlock()
+	if !recent {
+		if err := j.update(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := j.lookup(kid, false); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup returns a cached key, starting a background refresh when the cache
+// is stale if refresh is set
+func (j *JWKS) lookup(kid string, refresh bool) (crypto.PublicKey, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	key, ok := j.keys[kid]
+	if ok && refresh && !j.refreshing && time.Since(j.fetched) > j.refresh {
+		j.refreshing = true
+		go func() {
+			j.fetchMu.Lock()
+			if err := j.update(context.Background()); err != nil {
+				slog.Warn("Failed to refresh JWKS; keeping cached keys", "error", err)
+			}
+			j.fetchMu.Unlock()
+			j.mu.Lock()
+			j.refreshing = false
+			j.mu.Unlock()
+		}()
+	}
+	return key, ok
+}
+
+// update fetches the key set and replaces the cached keys; call with fetchMu held
+func (j *JWKS) update(ctx context.Context) error {
+	j.mu.Lock()
+	j.attempted = time.Now()
+	j.mu.Unlock()
+
+	keys, err := j.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	j.keys, j.fetched = keys, time.Now()
+	j.mu.Unlock()
+	return nil
+}
+
+// fetch downloads and parses the key set
+func (j *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: %s returned %s", j.url, resp.Status)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS from %s: %w", j.url, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA, EC, or Ed25519 key; other key types return nil
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
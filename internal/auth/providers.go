@@ -0,0 +1,166 @@
+// internal/auth/providers.go
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+)
+
+// APIKeyHeader is the metadata key carrying an API key
+const APIKeyHeader = "x-api-key"
+
+// APIKeyProvider authenticates calls carrying a known x-api-key
+type APIKeyProvider struct {
+	// hashes maps the SHA-256 of each key to its subject
+	hashes map[[sha256.Size]byte]string
+}
+
+// NewAPIKeyProvider creates an APIKeyProvider from subjects mapped to the
+// hex SHA-256 of their key, so keys themselves never appear in config
+func NewAPIKeyProvider(keys map[string]string) (*APIKeyProvider, error) {
+	p := &APIKeyProvider{hashes: make(map[[sha256.Size]byte]string, len(keys))}
+	for subject, hexHash := range keys {
+		b, err := hex.DecodeString(hexHash)
+		if err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("API key for %s must be a hex SHA-256 hash", subject)
+		}
+		p.hashes[[sha256.Size]byte(b)] = subject
+	}
+	return p, nil
+}
+
+// Name implements Provider
+func (p *APIKeyProvider) Name() string { return "api_key" }
+
+// Authenticate implements Provider
+func (p *APIKeyProvider) Authenticate(ctx context.Context) (Identity, error) {
+	key := metadataValue(ctx, APIKeyHeader)
+	if key == "" {
+		return Identity{}, ErrNoCredentials
+	}
+	sum := sha256.Sum256([]byte(key))
+	for hash, subject := range p.hashes {
+		if subtle.ConstantTimeCompare(sum[:], hash[:]) == 1 {
+			return Identity{Subject: subject}, nil
+		}
+	}
+	return Identity{}, fmt.Errorf("unknown API key")
+}
+
+// JWTConfig configures a JWTProvider; set exactly one of PublicKeyFile and JWKSURL
+type JWTConfig struct {
+	// PublicKeyFile is a PEM RSA, ECDSA, or Ed25519 public key
+	PublicKeyFile string
+	// JWKSURL serves the identity provider's signing keys, e.g. an OIDC
+	// provider's jwks_uri
+	JWKSURL string
+	// JWKSRefresh is how often keys are refetched from JWKSURL
+	JWKSRefresh time.Duration
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+}
+
+// JWTProvider authenticates calls carrying a valid bearer JWT
+type JWTProvider struct {
+	key    crypto.PublicKey
+	jwks   *JWKS
+	parser *jwt.Parser
+}
+
+// NewJWTProvider creates a JWTProvider from cfg
+func NewJWTProvider(cfg JWTConfig) (*JWTProvider, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	p := &JWTProvider{parser: jwt.NewParser(opts...)}
+
+	switch {
+	case cfg.JWKSURL != "":
+		p.jwks = NewJWKS(cfg.JWKSURL, cfg.JWKSRefresh)
+	case cfg.PublicKeyFile != "":
+		pem, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if p.key, err = parsePublicKey(pem); err != nil {
+			return nil, fmt.Errorf("invalid JWT public key %s: %w", cfg.PublicKeyFile, err)
+		}
+	default:
+		return nil, fmt.Errorf("JWT authentication needs a public key file or JWKS URL")
+	}
+	return p, nil
+}
+
+// parsePublicKey reads the first supported public key from PEM data
+func parsePublicKey(pem []byte) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(pem); err == nil {
+		return key, nil
+	}
+	return jwt.ParseEdPublicKeyFromPEM(pem)
+}
+
+// Name implements Provider
+func (p *JWTProvider) Name() string { return "jwt" }
+
+// Authenticate implements Provider. Expired tokens, tokens for another
+// audience or issuer, and tokens signed with an unknown key are rejected.
+func (p *JWTProvider) Authenticate(ctx context.Context) (Identity, error) {
+	raw := bearerToken(ctx)
+	if raw == "" {
+		return Identity{}, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := p.parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		if p.jwks == nil {
+			return p.key, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.jwks.Key(ctx, kid)
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Identity{}, fmt.Errorf("token has no subject")
+	}
+	return Identity{Subject: subject, Claims: claims}, nil
+}
+
+// MTLSProvider authenticates calls by their verified client certificate; it
+// relies on the client identity interceptor and tls_client_ca
+type MTLSProvider struct{}
+
+// Name implements Provider
+func (MTLSProvider) Name() string { return "mtls" }
+
+// Authenticate implements Provider
+func (MTLSProvider) Authenticate(ctx context.Context) (Identity, error) {
+	subject := middleware.GetClientIdentity(ctx)
+	if subject == "" {
+		return Identity{}, ErrNoCredentials
+	}
+	return Identity{Subject: subject}, nil
+}
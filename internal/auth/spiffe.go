@@ -0,0 +1,149 @@
+// internal/auth/spiffe.go
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// SPIFFERole grants roles to callers whose SPIFFE ID matches ID, either
+// exactly or, when ID ends in "/*", by prefix
+type SPIFFERole struct {
+	ID    string
+	Roles []string
+}
+
+// SPIFFEProvider authenticates calls by the SPIFFE ID of the client's X.509
+// SVID and maps it to roles. It relies on SPIFFEServerTLS, which verifies the
+// SVID against the trust bundle during the handshake.
+type SPIFFEProvider struct {
+	trustDomain spiffeid.TrustDomain
+	roles       []SPIFFERole
+}
+
+// NewSPIFFEProvider creates a SPIFFEProvider accepting SVIDs from trustDomain.
+// When roles is not empty it is also an allowlist: IDs matching no entry are
+// rejected.
+func NewSPIFFEProvider(trustDomain string, roles []SPIFFERole) (*SPIFFEProvider, error) {
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPIFFE trust domain %q: %w", trustDomain, err)
+	}
+	for _, r := range roles {
+		id, err := spiffeid.FromString(strings.TrimSuffix(r.ID, "/*"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE ID %q: %w", r.ID, err)
+		}
+		if !id.MemberOf(td) {
+			return nil, fmt.Errorf("SPIFFE ID %q is not in trust domain %s", r.ID, td)
+		}
+	}
+	return &SPIFFEProvider{trustDomain: td, roles: roles}, nil
+}
+
+// Name implements Provider
+func (p *SPIFFEProvider) Name() string { return "spiffe" }
+
+// Authenticate implements Provider
+func (p *SPIFFEProvider) Authenticate(ctx context.Context) (Identity, error) {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, ErrNoCredentials
+	}
+	info, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return Identity{}, ErrNoCredentials
+	}
+
+	id, err := x509svid.IDFromCert(info.State.PeerCertificates[0])
+	if err != nil {
+		return Identity{}, fmt.Errorf("client certificate is not an X.509 SVID: %w", err)
+	}
+	if !id.MemberOf(p.trustDomain) {
+		return Identity{}, fmt.Errorf("SPIFFE ID %s is not in trust domain %s", id, p.trustDomain)
+	}
+	roles := p.rolesFor(id.String())
+	if len(p.roles) > 0 && len(roles) == 0 {
+		return Identity{}, fmt.Errorf("no role for SPIFFE ID %s", id)
+	}
+	return Identity{Subject: id.String(), Roles: roles}, nil
+}
+
+// rolesFor returns the roles of every entry matching id, without duplicates
+func (p *SPIFFEProvider) rolesFor(id string) []string {
+	var roles []string
+	seen := make(map[string]bool)
+	for _, r := range p.roles {
+		prefix, wildcard := strings.CutSuffix(r.ID, "*")
+		if id != r.ID && !(wildcard && strings.HasPrefix(id, prefix)) {
+			continue
+		}
+		for _, role := range r.Roles {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
+
+// NewSPIFFESource connects to the SPIFFE Workload API at socket (e.g.
+// "unix:///run/spire/sockets/agent.sock") and waits for the first SVID. The
+// source keeps the SVID and trust bundle current until closed.
+func NewSPIFFESource(ctx context.Context, socket string) (*workloadapi.X509Source, error) {
+	source, err := workloadapi.NewX509Source(ctx, workloadapi.WithClientOptions(workloadapi.WithAddr(socket)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch X.509 SVID from %s: %w", socket, err)
+	}
+	return source, nil
+}
+
+// SPIFFEServerTLS returns a TLS config that serves the source's current SVID,
+// so rotated SVIDs apply to new connections without a restart, and requires
+// client SVIDs from trustDomain
+func SPIFFEServerTLS(source *workloadapi.X509Source, trustDomain string) (*tls.Config, error) {
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPIFFE trust domain %q: %w", trustDomain, err)
+	}
+	return tlsconfig.MTLSServerConfig(source, source, tlsconfig.AuthorizeMemberOf(td)), nil
+}
+
+// SPIFFEClientTLS returns a TLS config that presents the source's current
+// SVID and accepts servers with SVIDs from trustDomain
+func SPIFFEClientTLS(source *workloadapi.X509Source, trustDomain string) (*tls.Config, error) {
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPIFFE trust domain %q: %w", trustDomain, err)
+	}
+	return tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeMemberOf(td)), nil
+}
+
+// LogSVIDUpdates logs each SVID the source receives until ctx is done
+func LogSVIDUpdates(ctx context.Context, source *workloadapi.X509Source) {
+	for {
+		select {
+		case <-source.Updated():
+			svid, err := source.GetX509SVID()
+			if err != nil {
+				slog.Warn("Failed to read updated SVID", "error", err)
+				continue
+			}
+			slog.Info("SVID updated", "id", svid.ID.String(), "expires", svid.Certificates[0].NotAfter.Format(time.RFC3339))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// internal/auth/tenant.go
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+)
+
+// TenantClaim is the claim carrying the tenant in a robot token, and the
+// default claim read from other tokens
+const TenantClaim = "tenant"
+
+// Tenants resolves the tenant an authenticated caller belongs to, so tenant
+// isolation rests on credentials rather than on the x-tenant-id header
+type Tenants struct {
+	// Claim names the token claim holding the caller's tenant; empty ignores
+	// claims
+	Claim string
+	// Subjects maps subjects to tenants, either exactly or by a prefix ending
+	// in "/*"; the longest match wins
+	Subjects map[string]string
+}
+
+// Of returns id's tenant: the one its provider set, its tenant claim, or the
+// Subjects entry matching its subject, in that order, and DefaultTenant when
+// none applies
+func (t Tenants) Of(id Identity) string {
+	if id.Tenant != "" {
+		return id.Tenant
+	}
+	if t.Claim != "" {
+		if tenant, ok := id.Claims[t.Claim].(string); ok && tenant != "" {
+			return tenant
+		}
+	}
+	if tenant, ok := t.Subjects[id.Subject]; ok {
+		return tenant
+	}
+	tenant, longest := middleware.DefaultTenant, -1
+	for pattern, candidate := range t.Subjects {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if !ok || !strings.HasSuffix(prefix, "/") || !strings.HasPrefix(id.Subject, prefix) {
+			continue
+		}
+		if len(prefix) > longest {
+			tenant, longest = candidate, len(prefix)
+		}
+	}
+	return tenant
+}
+
+// TenantFromContext returns the caller's authenticated tenant, or the
+// x-tenant-id header's tenant when the call was not authenticated
+func TenantFromContext(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok && id.Tenant != "" {
+		return id.Tenant
+	}
+	return middleware.GetTenantID(ctx)
+}
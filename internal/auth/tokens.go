@@ -0,0 +1,170 @@
+// internal/auth/tokens.go
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/SyedDaiam9101/policy-service/internal/signing"
+)
+
+// RobotIDClaim is the claim carrying the robot ID in a robot token
+const RobotIDClaim = "robot_id"
+
+// RobotID returns the robot a caller's token is bound to by its robot_id
+// claim, if it carries one
+func (id Identity) RobotID() (uint64, bool) {
+	claim, ok := id.Claims[RobotIDClaim].(string)
+	if !ok {
+		return 0, false
+	}
+	robotID, err := strconv.ParseUint(claim, 10, 64)
+	return robotID, err == nil
+}
+
+// RobotTokens issues short-lived per-robot bearer JWTs signed with the
+// service's ed25519 keys, and authenticates calls carrying them. Tokens are
+// verified against every loaded key, so they survive a key rotation.
+type RobotTokens struct {
+	source signing.KeySource
+	issuer string
+	parser *jwt.Parser
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	active signing.Key
+	public map[string]ed25519.PublicKey
+}
+
+// NewRobotTokens creates RobotTokens issuing tokens as issuer and loads the
+// initial keys from source
+func NewRobotTokens(source signing.KeySource, issuer string) (*RobotTokens, error) {
+	t := &RobotTokens{
+		source: source,
+		issuer: issuer,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{"EdDSA"}),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(issuer),
+		),
+		now: time.Now,
+	}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload re-reads keys from the source and switches to the current active key.
+// On error the previously loaded keys stay in use.
+func (t *RobotTokens) Reload() error {
+	keys, activeID, err := t.source.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load robot token keys: %w", err)
+	}
+	public := make(map[string]ed25519.PublicKey, len(keys))
+	var active *signing.Key
+	for i := range keys {
+		if len(keys[i].PrivateKey) != ed25519.PrivateKeySize {
+			return fmt.Errorf("robot token key %q has invalid size %d", keys[i].ID, len(keys[i].PrivateKey))
+		}
+		public[keys[i].ID] = keys[i].PrivateKey.Public().(ed25519.PublicKey)
+		if keys[i].ID == activeID {
+			active = &keys[i]
+		}
+	}
+	if active == nil {
+		return fmt.Errorf("active robot token key %q not found", activeID)
+	}
+
+	t.mu.Lock()
+	t.active = *active
+	t.public = public
+	t.mu.Unlock()
+	return nil
+}
+
+// ActiveKeyID returns the ID of the key new tokens are signed with
+func (t *RobotTokens) ActiveKeyID() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.active.ID
+}
+
+// Issue signs a token for robotID of tenant authenticating as subject until
+// ttl from now, returning the token, its signing key ID, and its expiry
+func (t *RobotTokens) Issue(subject, tenant string, robotID uint64, ttl time.Duration) (string, string, time.Time, error) {
+	t.mu.RLock()
+	key := t.active
+	t.mu.RUnlock()
+
+	now := t.now()
+	expires := now.Add(ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"iss":        t.issuer,
+		"aud":        t.issuer,
+		"sub":        subject,
+		"iat":        now.Unix(),
+		"exp":        expires.Unix(),
+		"jti":        uuid.NewString(),
+		RobotIDClaim: strconv.FormatUint(robotID, 10),
+		TenantClaim:  tenant,
+	})
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return signed, key.ID, expires, nil
+}
+
+// Name implements Provider
+func (t *RobotTokens) Name() string { return "robot_token" }
+
+// Authenticate implements Provider. Bearer tokens from other issuers are left
+// to the next provider, so robot tokens and an external identity provider's
+// JWTs can be accepted side by side.
+func (t *RobotTokens) Authenticate(ctx context.Context) (Identity, error) {
+	raw := bearerToken(ctx)
+	if raw == "" {
+		return Identity{}, ErrNoCredentials
+	}
+	unverified := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(raw, unverified); err != nil {
+		return Identity{}, ErrNoCredentials
+	}
+	if iss, _ := unverified.GetIssuer(); iss != t.issuer {
+		return Identity{}, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := t.parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		t.mu.RLock()
+		key, ok := t.public[kid]
+		t.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Identity{}, fmt.Errorf("token has no subject")
+	}
+	// Robots belong to the tenant of the provisioning system that issued
+	// their token
+	tenant, _ := claims[TenantClaim].(string)
+	return Identity{Subject: subject, Claims: claims, Tenant: tenant}, nil
+}
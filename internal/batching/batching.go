@@ -0,0 +1,170 @@
+// Package batching coalesces observations from concurrent calls that arrive
+// within a short window into a single inference call, so many single-robot
+// clients share one ONNX Runtime run instead of each paying for their own
+package batching
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// ErrBatchTooLarge is returned for calls over the batcher's size limit
+var ErrBatchTooLarge = errors.New("batch too large")
+
+// Result is one call's part of a coalesced batch
+type Result struct {
+	// Actions are the raw model outputs for the call's observations, in order
+	Actions []float32
+	// Share is the fraction of the coalesced batch's observations from this call
+	Share float64
+	// Wait is the time spent waiting for the batch to fill before inference
+	Wait time.Duration
+	// Inference is the inference time of the whole coalesced batch
+	Inference time.Duration
+}
+
+// shape groups observations that can share a Predict call
+type shape struct {
+	c, h, w int64
+}
+
+// batch collects observations until it is full or its window ends
+type batch struct {
+	obs   [][]float32
+	calls int
+	full  chan struct{} // closed once no more observations fit
+
+	done      chan struct{} // closed once inference has run
+	actions   []float32
+	inference time.Duration
+	err       error
+}
+
+// Batcher coalesces concurrent Predict calls on an engine
+type Batcher struct {
+	engine   inference.InferenceEngine
+	window   time.Duration
+	maxBatch int
+	maxSize  int
+
+	mu      sync.Mutex
+	pending map[shape]*batch
+}
+
+// New creates a Batcher that waits up to window for more observations, running
+// a batch early once it holds maxBatch observations. Calls of more than maxSize
+// observations are refused, so no single call can run an oversized batch; 0
+// means no limit.
+func New(engine inference.InferenceEngine, window time.Duration, maxBatch, maxSize int) *Batcher {
+	if maxSize > 0 && maxBatch > maxSize {
+		maxBatch = maxSize
+	}
+	return &Batcher{
+		engine:   engine,
+		window:   window,
+		maxBatch: maxBatch,
+		maxSize:  maxSize,
+		pending:  make(map[shape]*batch),
+	}
+}
+
+// Predict runs obsBatch as part of a coalesced batch of observations with the
+// same dimensions. The first call into an empty batch waits out the window and
+// runs it; later calls wait for its result. Calls that fill a batch on their
+// own run directly.
+func (b *Batcher) Predict(obsBatch [][]float32, c, h, w int64) (Result, error) {
+	if b.maxSize > 0 && len(obsBatch) > b.maxSize {
+		return Result{}, fmt.Errorf("%w: %d observations exceed the limit of %d", ErrBatchTooLarge, len(obsBatch), b.maxSize)
+	}
+	if len(obsBatch) >= b.maxBatch {
+		start := time.Now()
+		actions, err := b.engine.Predict(obsBatch, c, h, w)
+		metrics.RecordCoalescedBatch(len(obsBatch), 1)
+		return Result{Actions: actions, Share: 1, Inference: time.Since(start)}, err
+	}
+
+	start := time.Now()
+	key := shape{c, h, w}
+
+	b.mu.Lock()
+	current := b.pending[key]
+	if current != nil && len(current.obs)+len(obsBatch) > b.maxBatch {
+		// No room left; let the current batch run and start a new one
+		b.closeLocked(key, current)
+		current = nil
+	}
+	leader := current == nil
+	if leader {
+		current = &batch{full: make(chan struct{}), done: make(chan struct{})}
+		b.pending[key] = current
+	}
+	offset := len(current.obs)
+	current.obs = append(current.obs, obsBatch...)
+	current.calls++
+	if len(current.obs) == b.maxBatch {
+		b.closeLocked(key, current)
+	}
+	b.mu.Unlock()
+
+	if leader {
+		b.run(key, current)
+	} else {
+		<-current.done
+	}
+	wait := time.Since(start) - current.inference
+
+	if current.err != nil {
+		return Result{}, current.err
+	}
+	total := len(current.obs)
+	dim := len(current.actions) / total
+	if dim*total != len(current.actions) {
+		return Result{}, fmt.Errorf("action output size mismatch: got %d actions for coalesced batch %d", len(current.actions), total)
+	}
+
+	// Cap the slice so appends by the caller cannot spill into other calls' actions
+	lo, hi := offset*dim, (offset+len(obsBatch))*dim
+	return Result{
+		Actions:   current.actions[lo:hi:hi],
+		Share:     float64(len(obsBatch)) / float64(total),
+		Wait:      wait,
+		Inference: current.inference,
+	}, nil
+}
+
+// run waits for the batch to fill or its window to end, then runs inference
+func (b *Batcher) run(key shape, current *batch) {
+	timer := time.NewTimer(b.window)
+	select {
+	case <-current.full:
+		timer.Stop()
+	case <-timer.C:
+		b.mu.Lock()
+		b.closeLocked(key, current)
+		b.mu.Unlock()
+	}
+
+	// No calls join after closeLocked, so the batch is safe to read unlocked
+	start := time.Now()
+	current.actions, current.err = b.engine.Predict(current.obs, key.c, key.h, key.w)
+	current.inference = time.Since(start)
+	metrics.RecordCoalescedBatch(len(current.obs), current.calls)
+	close(current.done)
+}
+
+// closeLocked stops a batch from accepting observations
+func (b *Batcher) closeLocked(key shape, current *batch) {
+	if b.pending[key] == current {
+		delete(b.pending, key)
+	}
+	select {
+	case <-current.full:
+	default:
+		close(current.full)
+	}
+}
@@ -0,0 +1,130 @@
+// internal/batching/batching_test.go
+package batching
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// echoEngine returns the first value of each observation as its action
+type echoEngine struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (e *echoEngine) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	e.calls.Add(1)
+	if e.err != nil {
+		return nil, e.err
+	}
+	actions := make([]float32, len(obsBatch))
+	for i, obs := range obsBatch {
+		actions[i] = obs[0]
+	}
+	return actions, nil
+}
+
+func (e *echoEngine) Close() error { return nil }
+
+// predictConcurrently runs one single-observation call per value
+func predictConcurrently(b *Batcher, values []float32) ([]Result, []error) {
+	results := make([]Result, len(values))
+	errs := make([]error, len(values))
+	var wg sync.WaitGroup
+	for i, v := range values {
+		wg.Add(1)
+		go func(i int, v float32) {
+			defer wg.Done()
+			results[i], errs[i] = b.Predict([][]float32{{v}}, 1, 1, 1)
+		}(i, v)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+func TestBatcher_CoalescesConcurrentCalls(t *testing.T) {
+	engine := &echoEngine{}
+	b := New(engine, 10*time.Second, 4, 0)
+
+	start := time.Now()
+	results, errs := predictConcurrently(b, []float32{1, 2, 3, 4})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected a full batch to run before the window ends, took %v", elapsed)
+	}
+
+	if calls := engine.calls.Load(); calls != 1 {
+		t.Fatalf("Expected 1 Predict call, got %d", calls)
+	}
+	for i, res := range results {
+		if errs[i] != nil {
+			t.Fatalf("Predict %d failed: %v", i, errs[i])
+		}
+		if len(res.Actions) != 1 || res.Actions[0] != float32(i+1) {
+			t.Errorf("Expected action [%d] for call %d, got %v", i+1, i, res.Actions)
+		}
+		if res.Share != 0.25 {
+			t.Errorf("Expected share 0.25, got %v", res.Share)
+		}
+	}
+}
+
+func TestBatcher_FlushesAfterWindow(t *testing.T) {
+	engine := &echoEngine{}
+	b := New(engine, 5*time.Millisecond, 64, 0)
+
+	res, err := b.Predict([][]float32{{7}, {8}}, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if len(res.Actions) != 2 || res.Actions[1] != 8 || res.Share != 1 {
+		t.Errorf("Expected own actions with full share, got %+v", res)
+	}
+	if res.Wait < 5*time.Millisecond {
+		t.Errorf("Expected to wait out the window, waited %v", res.Wait)
+	}
+
+	// Calls as large as the batch limit skip the window
+	big := make([][]float32, 64)
+	for i := range big {
+		big[i] = []float32{1}
+	}
+	if res, err := b.Predict(big, 1, 1, 1); err != nil || len(res.Actions) != 64 {
+		t.Errorf("Expected direct run of a full batch, got %d actions (err %v)", len(res.Actions), err)
+	}
+}
+
+func TestBatcher_PropagatesErrors(t *testing.T) {
+	engine := &echoEngine{err: errors.New("boom")}
+	b := New(engine, time.Millisecond, 4, 0)
+
+	_, errs := predictConcurrently(b, []float32{1, 2, 3})
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("Expected error for call %d", i)
+		}
+	}
+}
+
+func TestBatcher_RefusesOversizedCalls(t *testing.T) {
+	engine := &echoEngine{}
+	b := New(engine, time.Millisecond, 64, 8)
+
+	big := make([][]float32, 9)
+	for i := range big {
+		big[i] = []float32{1}
+	}
+	if _, err := b.Predict(big, 1, 1, 1); !errors.Is(err, ErrBatchTooLarge) {
+		t.Fatalf("Expected ErrBatchTooLarge, got %v", err)
+	}
+	if calls := engine.calls.Load(); calls != 0 {
+		t.Errorf("Expected no Predict call for a refused batch, got %d", calls)
+	}
+
+	// Coalesced batches are capped at the limit too
+	if res, err := b.Predict(big[:8], 1, 1, 1); err != nil || len(res.Actions) != 8 {
+		t.Errorf("Expected direct run of a batch at the limit, got %d actions (err %v)", len(res.Actions), err)
+	}
+}
@@ -0,0 +1,111 @@
+// internal/cache/encrypt.go
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encryptedPrefix marks values sealed with a tenant key (format version 1)
+const encryptedPrefix = "enc:v1:"
+
+// ErrNoTenantKey is returned when encryption is enabled but a tenant has no key
+var ErrNoTenantKey = errors.New("no cache encryption key for tenant")
+
+// Keyring holds the AES-256-GCM key of each tenant
+type Keyring struct {
+	keys map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring from raw 32-byte keys by tenant
+func NewKeyring(keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD, len(keys))}
+	for tenant, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("cache key for tenant %q must be 32 bytes, got %d", tenant, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[tenant] = aead
+	}
+	return k, nil
+}
+
+// LoadKeyring reads per-tenant keys from a directory. Each "<tenant>.key" file
+// holds a base64-encoded 32-byte key, e.g. as written by a KMS secret-sync sidecar.
+func LoadKeyring(dir string) (*Keyring, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.key"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no cache encryption keys found in %s", dir)
+	}
+
+	keys := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache key %s: %w", path, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("cache key %s is not base64 encoded: %w", path, err)
+		}
+		keys[strings.TrimSuffix(filepath.Base(path), ".key")] = key
+	}
+	return NewKeyring(keys)
+}
+
+// Len returns the number of tenants with a key
+func (k *Keyring) Len() int {
+	return len(k.keys)
+}
+
+// seal encrypts value for tenant, binding the ciphertext to the tenant and
+// Redis key so it cannot be replayed under another tenant or key
+func seal(aead cipher.AEAD, tenant, key, value string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), additionalData(tenant, key))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a value written by seal
+func open(aead cipher.AEAD, tenant, key, stored string) (string, error) {
+	encoded, ok := strings.CutPrefix(stored, encryptedPrefix)
+	if !ok {
+		return "", fmt.Errorf("value at %s is not encrypted", key)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("value at %s is malformed", key)
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	value, err := aead.Open(nil, nonce, ciphertext, additionalData(tenant, key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value at %s for tenant %q: %w", key, tenant, err)
+	}
+	return string(value), nil
+}
+
+// additionalData is the GCM additional data for a tenant's value at key
+func additionalData(tenant, key string) []byte {
+	return []byte(tenant + "\x00" + key)
+}
@@ -0,0 +1,86 @@
+// internal/cache/encrypt_test.go
+package cache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSealOpen_BindsTenantAndKey(t *testing.T) {
+	k, err := NewKeyring(map[string][]byte{
+		"acme":   bytes.Repeat([]byte{1}, 32),
+		"globex": bytes.Repeat([]byte{2}, 32),
+	})
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	acme, globex := k.keys["acme"], k.keys["globex"]
+
+	sealed, err := seal(acme, "acme", "tenant:acme:robot:1:pose", `{"x":1}`)
+	if err != nil {
+		t.Fatalf("seal failed: %v", err)
+	}
+	if !strings.HasPrefix(sealed, encryptedPrefix) || strings.Contains(sealed, "{") {
+		t.Fatalf("Expected an encrypted value, got %q", sealed)
+	}
+
+	value, err := open(acme, "acme", "tenant:acme:robot:1:pose", sealed)
+	if err != nil || value != `{"x":1}` {
+		t.Fatalf("Expected round trip, got %q (err %v)", value, err)
+	}
+
+	if _, err := open(globex, "globex", "tenant:acme:robot:1:pose", sealed); err == nil {
+		t.Error("Expected another tenant's key to fail")
+	}
+	if _, err := open(acme, "acme", "tenant:acme:robot:2:pose", sealed); err == nil {
+		t.Error("Expected a value moved to another key to fail")
+	}
+	if _, err := open(acme, "acme", "tenant:acme:robot:1:pose", `{"x":1}`); err == nil {
+		t.Error("Expected plaintext to be rejected")
+	}
+}
+
+func TestForTenant_RequiresKey(t *testing.T) {
+	k, err := NewKeyring(map[string][]byte{"acme": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	c := &Cache{keyring: k}
+
+	if _, err := c.ForTenant("acme"); err != nil {
+		t.Errorf("Expected a view for a tenant with a key, got %v", err)
+	}
+	if _, err := c.ForTenant("globex"); !errors.Is(err, ErrNoTenantKey) {
+		t.Errorf("Expected ErrNoTenantKey, got %v", err)
+	}
+	if _, err := (&Cache{}).ForTenant("globex"); err != nil {
+		t.Errorf("Expected unencrypted view without a keyring, got %v", err)
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32))
+	if err := os.WriteFile(filepath.Join(dir, "acme.key"), []byte(key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := LoadKeyring(dir)
+	if err != nil {
+		t.Fatalf("LoadKeyring failed: %v", err)
+	}
+	if k.Len() != 1 || k.keys["acme"] == nil {
+		t.Errorf("Expected a key for acme, got %d keys", k.Len())
+	}
+
+	short := base64.StdEncoding.EncodeToString([]byte("too short"))
+	os.WriteFile(filepath.Join(dir, "globex.key"), []byte(short), 0o600)
+	if _, err := LoadKeyring(dir); err == nil {
+		t.Error("Expected error for a short key")
+	}
+}
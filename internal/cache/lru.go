@@ -0,0 +1,82 @@
+// internal/cache/lru.go
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is a bounded in-process cache that evicts the least recently used entry
+type lru struct {
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+type lruEntry struct {
+	key     string
+	value   string
+	expires time.Time // zero for no expiry
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the value under key, if present and not expired
+func (l *lru) get(key string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.entries[key]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && !l.now().Before(e.expires) {
+		l.order.Remove(el)
+		delete(l.entries, key)
+		return "", false
+	}
+	l.order.MoveToFront(el)
+	return e.value, true
+}
+
+// set stores value under key for ttl (0 means no expiry), evicting the least
+// recently used entry when full
+func (l *lru) set(key, value string, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = l.now().Add(ttl)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.entries[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value, e.expires = value, expires
+		l.order.MoveToFront(el)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// len returns the number of entries, including expired ones not yet evicted
+func (l *lru) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,80 @@
+// internal/cache/observation.go
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// ObservationVersion is the version of the stored observation format, kept
+// under its own keys like PoseVersion
+const ObservationVersion = 1
+
+// observationKey returns the key holding a robot's latest observation
+func observationKey(robotID uint64) string {
+	return fmt.Sprintf("robot:%d:obs:v%d", robotID, ObservationVersion)
+}
+
+// MarshalObservation encodes an observation in the stored format: its
+// deterministic protobuf wire encoding
+func MarshalObservation(obs *pb.Observation) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(obs)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode observation: %w", err)
+	}
+	return string(data), nil
+}
+
+// UnmarshalObservation decodes an observation stored by MarshalObservation
+func UnmarshalObservation(data string) (*pb.Observation, error) {
+	obs := &pb.Observation{}
+	if err := proto.Unmarshal([]byte(data), obs); err != nil {
+		return nil, fmt.Errorf("invalid cached observation: %w", err)
+	}
+	return obs, nil
+}
+
+// SetObservations stores each robot's latest observation for the tenant with
+// the specified TTL in a single pipeline
+func (t *TenantCache) SetObservations(ctx context.Context, observations map[uint64]*pb.Observation, ttl time.Duration) error {
+	values := make(map[string]string, len(observations))
+	for robotID, obs := range observations {
+		data, err := MarshalObservation(obs)
+		if err != nil {
+			return err
+		}
+		values[observationKey(robotID)] = data
+	}
+	return t.SetMany(ctx, values, ttl)
+}
+
+// GetObservations retrieves the tenant's latest observation of each robot in a
+// single round trip, nil for robots without one
+func (t *TenantCache) GetObservations(ctx context.Context, robotIDs []uint64) ([]*pb.Observation, error) {
+	keys := make([]string, len(robotIDs))
+	for i, robotID := range robotIDs {
+		keys[i] = observationKey(robotID)
+	}
+	values, err := t.GetMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	observations := make([]*pb.Observation, len(values))
+	for i, data := range values {
+		if data == "" {
+			continue
+		}
+		obs, err := UnmarshalObservation(data)
+		if err != nil {
+			return nil, fmt.Errorf("robot %d: %w", robotIDs[i], err)
+		}
+		observations[i] = obs
+	}
+	return observations, nil
+}
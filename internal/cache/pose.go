@@ -0,0 +1,127 @@
+// internal/cache/pose.go
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// PoseVersion is the version of the stored pose format. Each version is kept
+// under its own keys, so replicas of a mixed deployment reading another
+// version see no pose rather than misreading it; bump it on any change that
+// older readers cannot parse.
+const PoseVersion = 1
+
+// poseKey returns the key holding a robot's pose
+func poseKey(robotID uint64) string {
+	return fmt.Sprintf("robot:%d:pose:v%d", robotID, PoseVersion)
+}
+
+// poseKeys returns the keys holding the robots' poses
+func poseKeys(robotIDs []uint64) []string {
+	keys := make([]string, len(robotIDs))
+	for i, robotID := range robotIDs {
+		keys[i] = poseKey(robotID)
+	}
+	return keys
+}
+
+// MarshalPose encodes a pose in the stored format: its deterministic protobuf
+// wire encoding
+func MarshalPose(pose *pb.Pose) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(pose)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pose: %w", err)
+	}
+	return string(data), nil
+}
+
+// UnmarshalPose decodes a pose stored by MarshalPose
+func UnmarshalPose(data string) (*pb.Pose, error) {
+	pose := &pb.Pose{}
+	if err := proto.Unmarshal([]byte(data), pose); err != nil {
+		return nil, fmt.Errorf("invalid cached pose: %w", err)
+	}
+	return pose, nil
+}
+
+// poseValues encodes each robot's pose by key
+func poseValues(poses map[uint64]*pb.Pose) (map[string]string, error) {
+	values := make(map[string]string, len(poses))
+	for robotID, pose := range poses {
+		data, err := MarshalPose(pose)
+		if err != nil {
+			return nil, err
+		}
+		values[poseKey(robotID)] = data
+	}
+	return values, nil
+}
+
+// unmarshalPoses decodes the values found for each robot, nil where empty
+func unmarshalPoses(robotIDs []uint64, values []string) ([]*pb.Pose, error) {
+	poses := make([]*pb.Pose, len(values))
+	for i, data := range values {
+		if data == "" {
+			continue
+		}
+		pose, err := UnmarshalPose(data)
+		if err != nil {
+			return nil, fmt.Errorf("robot %d: %w", robotIDs[i], err)
+		}
+		poses[i] = pose
+	}
+	return poses, nil
+}
+
+// SetPose stores a robot's pose with the specified TTL
+func (c *Cache) SetPose(robotID uint64, pose *pb.Pose, ttl time.Duration) error {
+	data, err := MarshalPose(pose)
+	if err != nil {
+		return err
+	}
+	if err := c.Set(poseKey(robotID), data, ttl); err != nil {
+		return fmt.Errorf("failed to set pose for robot %d: %w", robotID, err)
+	}
+	return nil
+}
+
+// GetPose retrieves a robot's pose, or nil if none is cached
+func (c *Cache) GetPose(robotID uint64) (*pb.Pose, error) {
+	data, err := c.Get(poseKey(robotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pose for robot %d: %w", robotID, err)
+	}
+	if data == "" {
+		return nil, nil
+	}
+	return UnmarshalPose(data)
+}
+
+// SetPoses stores each robot's pose with the specified TTL in a single
+// pipeline
+func (c *Cache) SetPoses(ctx context.Context, poses map[uint64]*pb.Pose, ttl time.Duration) error {
+	values, err := poseValues(poses)
+	if err != nil {
+		return err
+	}
+	if err := c.SetMany(ctx, values, ttl); err != nil {
+		return fmt.Errorf("failed to set poses: %w", err)
+	}
+	return nil
+}
+
+// GetPoses retrieves the pose of each robot in a single pipeline, nil for
+// robots without one
+func (c *Cache) GetPoses(ctx context.Context, robotIDs []uint64) ([]*pb.Pose, error) {
+	values, err := c.GetMany(ctx, poseKeys(robotIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poses: %w", err)
+	}
+	return unmarshalPoses(robotIDs, values)
+}
@@ -3,75 +3,365 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/go-redis/redis/v9"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
 )
 
-// Cache wraps a Redis client for robot pose storage
+// redisRetryInterval is how long a cache with a local fallback serves from it
+// alone after a Redis call fails, so an outage does not add a Redis timeout to
+// every call
+const redisRetryInterval = time.Second
+
+// Sources of cache lookups
+const (
+	SourceRedis = "redis"
+	SourceLocal = "local"
+)
+
+// Cache wraps a Redis client for robot pose storage. The client is a single
+// node, a Redis Cluster in cluster mode, or the master named by Sentinel.
 type Cache struct {
-	client *redis.Client
+	client  redis.UniversalClient
+	keyring *Keyring
+	cluster bool
+	// sentinelMaster names the master the sentinels are asked for
+	sentinelMaster string
+	conn           Options
+	// local, when set, keeps a copy of values written and serves lookups
+	// while Redis is unavailable
+	local *lru
+	// redisDownUntil is the UnixNano time until which Redis is skipped
+	redisDownUntil atomic.Int64
+}
+
+// Options are the credentials, database, and TLS settings used to connect to
+// Redis
+type Options struct {
+	// Username and Password authenticate with AUTH; Username selects an ACL
+	// user, and is empty for the default user
+	Username string
+	Password string
+	// DB selects the database on a single node or Sentinel-managed master;
+	// Redis Cluster only has database 0
+	DB int
+	// SentinelPassword authenticates to the sentinels, when they require it
+	SentinelPassword string
+	// TLS, when set, connects over TLS with this configuration
+	TLS *tls.Config
+}
+
+// Option configures optional Cache behavior
+type Option func(*Cache)
+
+// WithKeyring encrypts values stored through ForTenant views with each tenant's key
+func WithKeyring(k *Keyring) Option {
+	return func(c *Cache) {
+		c.keyring = k
+	}
 }
 
-// New creates a new Cache instance connected to the specified Redis address
-// If addr is empty, defaults to localhost:6379
-func New(addr string) (*Cache, error) {
+// WithCluster connects to a Redis Cluster even when a single seed address is
+// given; a comma-separated list of addresses selects cluster mode on its own
+func WithCluster() Option {
+	return func(c *Cache) {
+		c.cluster = true
+	}
+}
+
+// WithOptions connects with the given credentials, database, and TLS settings
+// instead of unauthenticated plaintext to database 0
+func WithOptions(o Options) Option {
+	return func(c *Cache) {
+		c.conn = o
+	}
+}
+
+// WithLocalFallback keeps a copy of the last size values written in process,
+// and serves Get, GetMany, and GetPose from it while Redis is unavailable
+// instead of failing. Writes only fail when Redis is unavailable without one.
+func WithLocalFallback(size int) Option {
+	return func(c *Cache) {
+		if size > 0 {
+			c.local = newLRU(size)
+		}
+	}
+}
+
+// WithSentinel connects to the master named master through Redis Sentinel,
+// with the address given to New listing the sentinels. The client follows
+// failovers to the new master without a restart.
+func WithSentinel(master string) Option {
+	return func(c *Cache) {
+		c.sentinelMaster = master
+	}
+}
+
+// New creates a new Cache instance connected to the specified Redis address,
+// or to a Redis Cluster or Sentinel-managed master through a comma-separated
+// list of cluster seeds or sentinels. If addr is empty, defaults to
+// localhost:6379
+func New(addr string, opts ...Option) (*Cache, error) {
 	if addr == "" {
 		addr = "localhost:6379"
 	}
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: "", // No password by default
-		DB:       0,  // Default DB
-	})
+	c := &Cache{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.cluster && c.sentinelMaster != "" {
+		return nil, fmt.Errorf("redis cluster and sentinel modes are exclusive")
+	}
+	addrs := splitAddrs(addr)
+	if c.conn.DB != 0 && c.sentinelMaster == "" && (c.cluster || len(addrs) > 1) {
+		return nil, fmt.Errorf("redis cluster only supports database 0, got %d", c.conn.DB)
+	}
+	c.client = newClient(addrs, c.cluster, c.sentinelMaster, c.conn)
 
 	// Test connection
 	ctx := context.Background()
-	_, err := client.Ping(ctx).Result()
+	_, err := c.client.Ping(ctx).Result()
 	if err != nil {
+		c.client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", addr, err)
 	}
+	return c, nil
+}
 
-	return &Cache{client: client}, nil
+// NewLocal creates a Cache without Redis, holding the last size values written
+// in process. Only Get, Set, GetMany, SetMany, and the tenant and pose methods
+// built on them work; cluster and Sentinel options are ignored.
+func NewLocal(size int, opts ...Option) *Cache {
+	c := &Cache{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.local = newLRU(max(size, 1))
+	return c
 }
 
-// SetPose stores a robot's pose data with the specified TTL
-func (c *Cache) SetPose(robotID uint64, data string, ttl time.Duration) error {
-	if c.client == nil {
+// redisUp reports whether calls should go to Redis: the cache has a client,
+// and no call has failed within redisRetryInterval when there is a local
+// fallback to serve instead
+func (c *Cache) redisUp() bool {
+	return c.client != nil && (c.local == nil || time.Now().UnixNano() >= c.redisDownUntil.Load())
+}
+
+// redisFailed serves calls from the local fallback alone for redisRetryInterval
+func (c *Cache) redisFailed() {
+	c.redisDownUntil.Store(time.Now().Add(redisRetryInterval).UnixNano())
+}
+
+// localGet looks key up in the local fallback
+func (c *Cache) localGet(key string) string {
+	value, ok := c.local.get(key)
+	metrics.RecordCacheLookup(SourceLocal, ok)
+	return value
+}
+
+// splitAddrs splits a comma-separated address list, dropping empty entries
+func splitAddrs(addr string) []string {
+	var addrs []string
+	for _, a := range strings.Split(addr, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
+// newClient returns a failover client when a sentinel master is named, a
+// cluster client for several seed addresses or when cluster is set, else a
+// single-node client, each connecting with o
+func newClient(addrs []string, cluster bool, sentinelMaster string, o Options) redis.UniversalClient {
+	if sentinelMaster != "" {
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       sentinelMaster,
+			SentinelAddrs:    addrs,
+			SentinelPassword: o.SentinelPassword,
+			Username:         o.Username,
+			Password:         o.Password,
+			DB:               o.DB,
+			TLSConfig:        o.TLS,
+		})
+	}
+	if cluster || len(addrs) > 1 {
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     addrs,
+			Username:  o.Username,
+			Password:  o.Password,
+			TLSConfig: o.TLS,
+		})
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:      addrs[0],
+		Username:  o.Username,
+		Password:  o.Password,
+		DB:        o.DB,
+		TLSConfig: o.TLS,
+	})
+}
+
+// Set stores an arbitrary value under key with the specified TTL (0 means no expiry)
+func (c *Cache) Set(key, value string, ttl time.Duration) error {
+	if c.client == nil && c.local == nil {
 		return fmt.Errorf("cache client is nil")
 	}
+	if c.local != nil {
+		c.local.set(key, value, ttl)
+	}
+	if !c.redisUp() {
+		return nil
+	}
 
 	ctx := context.Background()
-	key := fmt.Sprintf("robot:%d:pose", robotID)
-
-	err := c.client.Set(ctx, key, data, ttl).Err()
-	if err != nil {
-		return fmt.Errorf("failed to set pose for robot %d: %w", robotID, err)
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		if c.local != nil {
+			c.redisFailed()
+			return nil
+		}
+		return fmt.Errorf("failed to set %s: %w", key, err)
 	}
 
 	return nil
 }
 
-// GetPose retrieves a robot's pose data
-func (c *Cache) GetPose(robotID uint64) (string, error) {
-	if c.client == nil {
+// Get retrieves the value stored under key, or "" if it does not exist
+func (c *Cache) Get(key string) (string, error) {
+	if c.client == nil && c.local == nil {
 		return "", fmt.Errorf("cache client is nil")
 	}
 
-	ctx := context.Background()
-	key := fmt.Sprintf("robot:%d:pose", robotID)
+	if c.redisUp() {
+		ctx := context.Background()
+		data, err := c.client.Get(ctx, key).Result()
+		switch {
+		case err == redis.Nil:
+			metrics.RecordCacheLookup(SourceRedis, false)
+			return "", nil // Key does not exist
+		case err == nil:
+			metrics.RecordCacheLookup(SourceRedis, true)
+			return data, nil
+		case c.local == nil:
+			return "", fmt.Errorf("failed to get %s: %w", key, err)
+		}
+		c.redisFailed()
+	}
+	return c.localGet(key), nil
+}
 
-	data, err := c.client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return "", nil // Key does not exist
+// GetMany retrieves the values stored under keys in a single pipeline.
+// Missing keys are returned as empty strings.
+func (c *Cache) GetMany(ctx context.Context, keys []string) ([]string, error) {
+	if c.client == nil && c.local == nil {
+		return nil, fmt.Errorf("cache client is nil")
 	}
-	if err != nil {
-		return "", fmt.Errorf("failed to get pose for robot %d: %w", robotID, err)
+
+	if c.redisUp() {
+		// Separate GETs rather than MGET, which Redis Cluster rejects for keys in
+		// different hash slots
+		pipe := c.client.Pipeline()
+		cmds := make([]*redis.StringCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		pipe.Exec(ctx) // Per-command errors are checked below; missing keys fail with redis.Nil
+
+		results, err := pipelinedValues(keys, cmds)
+		if err == nil {
+			return results, nil
+		}
+		if c.local == nil {
+			return nil, err
+		}
+		c.redisFailed()
 	}
 
-	return data, nil
+	results := make([]string, len(keys))
+	for i, key := range keys {
+		results[i] = c.localGet(key)
+	}
+	return results, nil
+}
+
+// pipelinedValues returns the values read by cmds, or the first error other
+// than a missing key
+func pipelinedValues(keys []string, cmds []*redis.StringCmd) ([]string, error) {
+	results := make([]string, len(keys))
+	for i, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("failed to get %s: %w", keys[i], err)
+		}
+		results[i] = cmd.Val()
+	}
+	for _, value := range results {
+		metrics.RecordCacheLookup(SourceRedis, value != "")
+	}
+	return results, nil
+}
+
+// SetMany stores each value under its key with the specified TTL (0 means no
+// expiry) in a single pipeline
+func (c *Cache) SetMany(ctx context.Context, values map[string]string, ttl time.Duration) error {
+	if c.client == nil && c.local == nil {
+		return fmt.Errorf("cache client is nil")
+	}
+	if c.local != nil {
+		for key, value := range values {
+			c.local.set(key, value, ttl)
+		}
+	}
+	if !c.redisUp() {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for key, value := range values {
+		pipe.Set(ctx, key, value, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		if c.local != nil {
+			c.redisFailed()
+			return nil
+		}
+		return fmt.Errorf("failed to set keys: %w", err)
+	}
+	return nil
+}
+
+// GetFields fetches the given hash fields from each key in a single pipeline.
+// Missing keys or fields are returned as empty strings.
+func (c *Cache) GetFields(ctx context.Context, keys []string, fields []string) ([][]string, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("cache client is nil")
+	}
+
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.HMGet(ctx, key, fields...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get fields: %w", err)
+	}
+
+	results := make([][]string, len(keys))
+	for i, cmd := range cmds {
+		results[i] = make([]string, len(fields))
+		for j, v := range cmd.Val() {
+			if s, ok := v.(string); ok {
+				results[i][j] = s
+			}
+		}
+	}
+	return results, nil
 }
 
 // Close closes the Redis connection
@@ -0,0 +1,196 @@
+// internal/cache/redis_test.go
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+func TestNewClient_SelectsMode(t *testing.T) {
+	tests := []struct {
+		addr     string
+		cluster  bool
+		sentinel string
+		want     string
+	}{
+		{"localhost:6379", false, "", "*redis.Client"},
+		{"localhost:6379", true, "", "*redis.ClusterClient"},
+		{"redis-0:6379, redis-1:6379,", false, "", "*redis.ClusterClient"},
+		// Sentinel-managed masters are served through a failover client
+		{"sentinel-0:26379,sentinel-1:26379", false, "mymaster", "*redis.Client"},
+	}
+	for _, tt := range tests {
+		client := newClient(splitAddrs(tt.addr), tt.cluster, tt.sentinel, Options{})
+		got := fmt.Sprintf("%T", client)
+		client.Close()
+		if got != tt.want {
+			t.Errorf("Expected %s for %q (cluster %t, sentinel %q), got %s", tt.want, tt.addr, tt.cluster, tt.sentinel, got)
+		}
+	}
+
+	if _, err := New("localhost:6379", WithCluster(), WithSentinel("mymaster")); err == nil {
+		t.Error("Expected cluster and sentinel modes to be exclusive")
+	}
+
+	if _, err := New("redis-0:6379,redis-1:6379", WithOptions(Options{DB: 2})); err == nil {
+		t.Error("Expected a database other than 0 to be rejected in cluster mode")
+	}
+
+	if addrs := splitAddrs("redis-0:6379, redis-1:6379,"); len(addrs) != 2 || addrs[1] != "redis-1:6379" {
+		t.Errorf("Expected 2 trimmed addresses, got %q", addrs)
+	}
+}
+
+func TestNewClient_AppliesOptions(t *testing.T) {
+	o := Options{Username: "planner", Password: "s3cret", DB: 3, TLS: &tls.Config{ServerName: "redis.internal"}}
+
+	client := newClient([]string{"localhost:6379"}, false, "", o).(*redis.Client)
+	defer client.Close()
+	got := client.Options()
+	if got.Username != "planner" || got.Password != "s3cret" || got.DB != 3 {
+		t.Errorf("Expected credentials and database 3, got %q/%q db %d", got.Username, got.Password, got.DB)
+	}
+	if got.TLSConfig == nil || got.TLSConfig.ServerName != "redis.internal" {
+		t.Errorf("Expected the TLS config to be used, got %v", got.TLSConfig)
+	}
+
+	cluster := newClient([]string{"redis-0:6379"}, true, "", o).(*redis.ClusterClient)
+	defer cluster.Close()
+	if got := cluster.Options(); got.Password != "s3cret" || got.TLSConfig == nil {
+		t.Error("Expected the cluster client to use the credentials and TLS")
+	}
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	l := newLRU(2)
+	l.set("a", "1", 0)
+	l.set("b", "2", 0)
+	l.get("a")
+	l.set("c", "3", 0)
+
+	if _, ok := l.get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if v, ok := l.get("a"); !ok || v != "1" {
+		t.Errorf("Expected a recently read entry to stay, got %q (ok=%t)", v, ok)
+	}
+
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	l.set("d", "4", time.Second)
+	now = now.Add(2 * time.Second)
+	if _, ok := l.get("d"); ok {
+		t.Error("Expected an expired entry to be a miss")
+	}
+	if l.len() != 1 {
+		t.Errorf("Expected only c after evicting a and dropping the expired d, got %d entries", l.len())
+	}
+}
+
+func TestCache_LocalFallback(t *testing.T) {
+	// Nothing listens on port 1, so every Redis call fails
+	c := &Cache{client: newClient([]string{"127.0.0.1:1"}, false, "", Options{}), local: newLRU(16)}
+	defer c.Close()
+	ctx := context.Background()
+
+	if err := c.SetPose(7, &pb.Pose{X: 1.5}, time.Minute); err != nil {
+		t.Fatalf("Expected SetPose to fall back to the local cache, got %v", err)
+	}
+	c.redisDownUntil.Store(0) // retry Redis immediately
+	if pose, err := c.GetPose(7); err != nil || pose.GetX() != 1.5 {
+		t.Fatalf("Expected the pose from the local cache, got %v (err %v)", pose, err)
+	}
+
+	tenant, _ := c.ForTenant("acme")
+	if err := tenant.SetMany(ctx, map[string]string{"plan:1": "p1"}, time.Minute); err != nil {
+		t.Fatalf("SetMany failed: %v", err)
+	}
+	values, err := tenant.GetMany(ctx, []string{"plan:1", "plan:2"})
+	if err != nil || values[0] != "p1" || values[1] != "" {
+		t.Fatalf("Expected a local hit and a miss, got %q (err %v)", values, err)
+	}
+	if c.redisUp() {
+		t.Error("Expected Redis to be skipped after a failure")
+	}
+
+	// Without a fallback, failures are returned
+	bare := &Cache{client: newClient([]string{"127.0.0.1:1"}, false, "", Options{})}
+	defer bare.Close()
+	if _, err := bare.Get("k"); err == nil {
+		t.Error("Expected Get to fail without a local fallback")
+	}
+
+	local := NewLocal(4)
+	if err := local.Set("k", "v", 0); err != nil {
+		t.Fatalf("Set failed on a local cache: %v", err)
+	}
+	if v, _ := local.Get("k"); v != "v" {
+		t.Errorf("Expected the local cache to return v, got %q", v)
+	}
+}
+
+func TestCache_Poses(t *testing.T) {
+	c := NewLocal(16)
+	ctx := context.Background()
+
+	if err := c.SetPoses(ctx, map[uint64]*pb.Pose{1: {X: 1, Theta: 0.5}, 3: {X: 3, Velocity: 0.2}}, time.Minute); err != nil {
+		t.Fatalf("SetPoses failed: %v", err)
+	}
+	poses, err := c.GetPoses(ctx, []uint64{1, 2, 3})
+	if err != nil || poses[0].GetTheta() != 0.5 || poses[1] != nil || poses[2].GetVelocity() != 0.2 {
+		t.Fatalf("Expected poses for robots 1 and 3 only, got %v (err %v)", poses, err)
+	}
+	// Batched and single-robot calls share keys
+	if pose, err := c.GetPose(3); err != nil || pose.GetX() != 3 {
+		t.Errorf("Expected GetPose to see the batched pose, got %v (err %v)", pose, err)
+	}
+
+	tenant, _ := c.ForTenant("acme")
+	if poses, _ := tenant.GetPoses(ctx, []uint64{1}); poses[0] != nil {
+		t.Errorf("Expected no pose in the tenant's keys, got %v", poses[0])
+	}
+	if err := tenant.SetPoses(ctx, map[uint64]*pb.Pose{1: {Y: 2}}, time.Minute); err != nil {
+		t.Fatalf("SetPoses failed: %v", err)
+	}
+	if pose, _ := tenant.GetPose(1); pose.GetY() != 2 {
+		t.Errorf("Expected the tenant's pose, got %v", pose)
+	}
+}
+
+func TestPose_VersionedFormat(t *testing.T) {
+	pose := &pb.Pose{X: 1, Y: -2, Theta: 3.14, Velocity: 0.5, TimestampUnixNano: 1700000000000000000}
+	data, err := MarshalPose(pose)
+	if err != nil {
+		t.Fatalf("MarshalPose failed: %v", err)
+	}
+	again, _ := MarshalPose(pose)
+	if again != data {
+		t.Error("Expected the same pose to encode identically")
+	}
+	decoded, err := UnmarshalPose(data)
+	if err != nil || !proto.Equal(decoded, pose) {
+		t.Fatalf("Expected %v back, got %v (err %v)", pose, decoded, err)
+	}
+	if _, err := UnmarshalPose("\xff\xff"); err == nil {
+		t.Error("Expected an error for a corrupt pose")
+	}
+
+	// Poses written in another format, here the unversioned float32 packing,
+	// live under other keys and are not misread
+	c := NewLocal(16)
+	c.Set("robot:9:pose", "\x00\x00\x80\x3f", time.Minute)
+	if pose, err := c.GetPose(9); err != nil || pose != nil {
+		t.Errorf("Expected no pose for another format's key, got %v (err %v)", pose, err)
+	}
+	if key := poseKey(9); key != fmt.Sprintf("robot:9:pose:v%d", PoseVersion) {
+		t.Errorf("Expected a versioned key, got %q", key)
+	}
+}
@@ -0,0 +1,165 @@
+// internal/cache/stream.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// streamField is the stream entry field holding the appended data
+const streamField = "data"
+
+// StreamEntry is one entry read from a Redis stream
+type StreamEntry struct {
+	ID   string
+	Data string
+}
+
+// AppendStream appends each data value to stream as its own entry in a single
+// round trip, trimming the stream to roughly maxLen entries (0 means no limit)
+func (c *Cache) AppendStream(ctx context.Context, stream string, maxLen int64, data ...string) error {
+	if c.client == nil {
+		return fmt.Errorf("cache client is nil")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for _, d := range data {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			MaxLen: maxLen,
+			Approx: maxLen > 0,
+			Values: []string{streamField, d},
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append %d entries to stream %s: %w", len(data), stream, err)
+	}
+	return nil
+}
+
+// Publish publishes each message to a Redis pub/sub channel in a single round
+// trip. Messages reach only subscribers connected at the time.
+func (c *Cache) Publish(ctx context.Context, channel string, messages ...string) error {
+	if c.client == nil {
+		return fmt.Errorf("cache client is nil")
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for _, m := range messages {
+		pipe.Publish(ctx, channel, m)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to publish %d messages to channel %s: %w", len(messages), channel, err)
+	}
+	return nil
+}
+
+// ReadGroup reads up to count entries of stream for consumer in group,
+// creating the group if needed. start ">" reads entries never delivered to the
+// group; "0" re-reads entries delivered to consumer but not yet acknowledged.
+// It does not block when there are none.
+func (c *Cache) ReadGroup(ctx context.Context, stream, group, consumer, start string, count int64) ([]StreamEntry, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("cache client is nil")
+	}
+
+	err := c.client.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create consumer group %s on %s: %w", group, stream, err)
+	}
+
+	streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, start},
+		Count:    count,
+		Block:    -1, // Return immediately when there is nothing to read
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream %s: %w", stream, err)
+	}
+
+	var entries []StreamEntry
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			data, _ := msg.Values[streamField].(string)
+			entries = append(entries, StreamEntry{ID: msg.ID, Data: data})
+		}
+	}
+	return entries, nil
+}
+
+// AckDelete acknowledges entries for group and deletes them from stream
+func (c *Cache) AckDelete(ctx context.Context, stream, group string, ids ...string) error {
+	if c.client == nil {
+		return fmt.Errorf("cache client is nil")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.XAck(ctx, stream, group, ids...)
+	pipe.XDel(ctx, stream, ids...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge %d entries of %s: %w", len(ids), stream, err)
+	}
+	return nil
+}
+
+// acquireLockScript takes the lock if it is free or already held by the owner,
+// refreshing its TTL either way
+var acquireLockScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == false or current == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// SwapOwner sets owner as the owner recorded at key until ttl passes and
+// returns the previous owner, or "" if there was none
+func (c *Cache) SwapOwner(ctx context.Context, key, owner string, ttl time.Duration) (string, error) {
+	if c.client == nil {
+		return "", fmt.Errorf("cache client is nil")
+	}
+
+	previous, err := c.client.SetArgs(ctx, key, owner, redis.SetArgs{TTL: ttl, Get: true}).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to swap owner of %s: %w", key, err)
+	}
+	return previous, nil
+}
+
+// AcquireLock takes or renews the lock at key for owner until ttl passes,
+// reporting whether owner holds it. Used to elect a single leader among
+// replicas.
+func (c *Cache) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if c.client == nil {
+		return false, fmt.Errorf("cache client is nil")
+	}
+
+	held, err := acquireLockScript.Run(ctx, c.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	return held == 1, nil
+}
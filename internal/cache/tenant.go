@@ -0,0 +1,138 @@
+// internal/cache/tenant.go
+package cache
+
+import (
+	"context"
+	"crypto/cipher"
+	"fmt"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// TenantCache is a view of the cache scoped to one tenant. Keys are prefixed
+// with the tenant ID, and values are encrypted with the tenant's key when the
+// cache has a keyring.
+type TenantCache struct {
+	cache  *Cache
+	tenant string
+	aead   cipher.AEAD
+}
+
+// ForTenant returns the cache view for tenant. With a keyring, tenants without
+// a key are refused rather than stored in plaintext.
+func (c *Cache) ForTenant(tenant string) (*TenantCache, error) {
+	t := &TenantCache{cache: c, tenant: tenant}
+	if c.keyring != nil {
+		aead, ok := c.keyring.keys[tenant]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrNoTenantKey, tenant)
+		}
+		t.aead = aead
+	}
+	return t, nil
+}
+
+// key returns the Redis key holding a tenant's value
+func (t *TenantCache) key(key string) string {
+	return fmt.Sprintf("tenant:%s:%s", t.tenant, key)
+}
+
+// Set stores value under the tenant's key with the specified TTL (0 means no expiry)
+func (t *TenantCache) Set(key, value string, ttl time.Duration) error {
+	key = t.key(key)
+	if t.aead != nil {
+		var err error
+		if value, err = seal(t.aead, t.tenant, key, value); err != nil {
+			return err
+		}
+	}
+	return t.cache.Set(key, value, ttl)
+}
+
+// Get retrieves the tenant's value stored under key, or "" if it does not exist
+func (t *TenantCache) Get(key string) (string, error) {
+	key = t.key(key)
+	value, err := t.cache.Get(key)
+	if err != nil || value == "" || t.aead == nil {
+		return value, err
+	}
+	return open(t.aead, t.tenant, key, value)
+}
+
+// GetMany retrieves the tenant's values stored under keys in a single round
+// trip, "" for keys that do not exist
+func (t *TenantCache) GetMany(ctx context.Context, keys []string) ([]string, error) {
+	full := make([]string, len(keys))
+	for i, key := range keys {
+		full[i] = t.key(key)
+	}
+	values, err := t.cache.GetMany(ctx, full)
+	if err != nil || t.aead == nil {
+		return values, err
+	}
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		if values[i], err = open(t.aead, t.tenant, full[i], value); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// SetMany stores each value under the tenant's key with the specified TTL (0
+// means no expiry) in a single pipeline
+func (t *TenantCache) SetMany(ctx context.Context, values map[string]string, ttl time.Duration) error {
+	full := make(map[string]string, len(values))
+	for key, value := range values {
+		key = t.key(key)
+		if t.aead != nil {
+			var err error
+			if value, err = seal(t.aead, t.tenant, key, value); err != nil {
+				return err
+			}
+		}
+		full[key] = value
+	}
+	return t.cache.SetMany(ctx, full, ttl)
+}
+
+// SetPose stores a robot's pose for the tenant with the specified TTL
+func (t *TenantCache) SetPose(robotID uint64, pose *pb.Pose, ttl time.Duration) error {
+	data, err := MarshalPose(pose)
+	if err != nil {
+		return err
+	}
+	return t.Set(poseKey(robotID), data, ttl)
+}
+
+// GetPose retrieves a robot's pose for the tenant, or nil if none is cached
+func (t *TenantCache) GetPose(robotID uint64) (*pb.Pose, error) {
+	data, err := t.Get(poseKey(robotID))
+	if err != nil || data == "" {
+		return nil, err
+	}
+	return UnmarshalPose(data)
+}
+
+// SetPoses stores each robot's pose for the tenant with the specified TTL in
+// a single pipeline
+func (t *TenantCache) SetPoses(ctx context.Context, poses map[uint64]*pb.Pose, ttl time.Duration) error {
+	values, err := poseValues(poses)
+	if err != nil {
+		return err
+	}
+	return t.SetMany(ctx, values, ttl)
+}
+
+// GetPoses retrieves the tenant's pose of each robot in a single round trip,
+// nil for robots without one
+func (t *TenantCache) GetPoses(ctx context.Context, robotIDs []uint64) ([]*pb.Pose, error) {
+	values, err := t.GetMany(ctx, poseKeys(robotIDs))
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalPoses(robotIDs, values)
+}
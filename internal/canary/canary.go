@@ -0,0 +1,156 @@
+// Package canary periodically plans a known observation through the server's
+// own gRPC listener, so regressions in TLS, authentication, interceptors, or
+// the model's output are caught even though internal health checks, which
+// call the engine directly, still pass
+package canary
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Check results reported in logs and metrics
+const (
+	ResultOK       = "ok"
+	ResultError    = "error"
+	ResultSlow     = "slow"
+	ResultMismatch = "mismatch"
+)
+
+// Config controls what the canary sends and what it accepts
+type Config struct {
+	// Interval between checks
+	Interval time.Duration
+	// Timeout bounds each Plan call
+	Timeout time.Duration
+	// MaxLatency is the slowest round trip that passes
+	MaxLatency time.Duration
+	// RobotID and Obs make up the canary's PlanRequest
+	RobotID uint64
+	Obs     *pb.Observation
+	// Expected is the action the observation must plan to; empty takes the
+	// first action planned as the baseline, so later changes are caught
+	Expected []float32
+	// Tolerance is the largest allowed difference of any action value
+	Tolerance float64
+	// Metadata is sent with every call, e.g. a tenant ID or API key
+	Metadata metadata.MD
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Result  string        `json:"result"`
+	Detail  string        `json:"detail,omitempty"`
+	Latency time.Duration `json:"latency"`
+	At      time.Time     `json:"at"`
+}
+
+// Canary checks the serving path by calling Plan through client
+type Canary struct {
+	client pb.PathPlannerClient
+	cfg    Config
+
+	mu       sync.Mutex
+	expected []float32
+	last     Result
+}
+
+// New creates a Canary calling client
+func New(client pb.PathPlannerClient, cfg Config) *Canary {
+	return &Canary{
+		client:   client,
+		cfg:      cfg,
+		expected: cfg.Expected,
+	}
+}
+
+// Check plans the canary observation once and records the outcome
+func (c *Canary) Check(ctx context.Context) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	if len(c.cfg.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, c.cfg.Metadata)
+	}
+
+	start := time.Now()
+	resp, err := c.client.Plan(ctx, &pb.PlanRequest{RobotId: c.cfg.RobotID, Obs: c.cfg.Obs})
+	r := Result{Result: ResultOK, Latency: time.Since(start), At: start}
+
+	c.mu.Lock()
+	switch {
+	case err != nil:
+		r.Result, r.Detail = ResultError, err.Error()
+	case r.Latency > c.cfg.MaxLatency:
+		r.Result, r.Detail = ResultSlow, fmt.Sprintf("took %s, limit %s", r.Latency.Round(time.Microsecond), c.cfg.MaxLatency)
+	case c.expected == nil:
+		c.expected = append([]float32(nil), resp.Action...)
+		r.Detail = "baseline action recorded"
+	default:
+		if detail := compare(resp.Action, c.expected, c.cfg.Tolerance); detail != "" {
+			r.Result, r.Detail = ResultMismatch, detail
+		}
+	}
+	c.last = r
+	c.mu.Unlock()
+
+	metrics.RecordCanaryCheck(r.Result, r.Latency.Seconds())
+	return r
+}
+
+// compare describes how action differs from expected beyond tolerance, or
+// returns "" if it does not
+func compare(action, expected []float32, tolerance float64) string {
+	if len(action) != len(expected) {
+		return fmt.Sprintf("action has %d values, expected %d", len(action), len(expected))
+	}
+	for i := range action {
+		if diff := math.Abs(float64(action[i]) - float64(expected[i])); !(diff <= tolerance) {
+			return fmt.Sprintf("action[%d] is %g, expected %g within %g", i, action[i], expected[i], tolerance)
+		}
+	}
+	return ""
+}
+
+// Last returns the outcome of the latest check, or a zero Result before the first
+func (c *Canary) Last() Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Run checks every interval until ctx is done, logging failures and recoveries
+func (c *Canary) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r := c.Check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case r.Result != ResultOK:
+			slog.Warn("Canary check failed", "result", r.Result, "detail", r.Detail)
+			failing = true
+		case failing:
+			slog.Info("Canary check passed again", "latency", r.Latency.Round(time.Microsecond))
+			failing = false
+		}
+	}
+}
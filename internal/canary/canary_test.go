@@ -0,0 +1,99 @@
+// internal/canary/canary_test.go
+package canary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// fakePlanner answers Plan with action, after delay, or err
+type fakePlanner struct {
+	pb.PathPlannerClient
+	action []float32
+	delay  time.Duration
+	err    error
+	md     metadata.MD
+}
+
+func (f *fakePlanner) Plan(ctx context.Context, req *pb.PlanRequest, _ ...grpc.CallOption) (*pb.PlanResponse, error) {
+	f.md, _ = metadata.FromOutgoingContext(ctx)
+	time.Sleep(f.delay)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pb.PlanResponse{Action: f.action}, nil
+}
+
+func testConfig() Config {
+	return Config{
+		Interval:   time.Second,
+		Timeout:    time.Second,
+		MaxLatency: 50 * time.Millisecond,
+		Obs:        &pb.Observation{Data: []float32{0, 0.1}, Channels: 1, Height: 1, Width: 2},
+		Tolerance:  1e-3,
+	}
+}
+
+func TestCheck_Expected(t *testing.T) {
+	planner := &fakePlanner{action: []float32{0.5, -0.5}}
+	cfg := testConfig()
+	cfg.Expected = []float32{0.5, -0.5004}
+	cfg.Metadata = metadata.Pairs("x-tenant-id", "canary")
+	c := New(planner, cfg)
+
+	if r := c.Check(context.Background()); r.Result != ResultOK {
+		t.Fatalf("Expected ok within tolerance, got %s: %s", r.Result, r.Detail)
+	}
+	if got := planner.md.Get("x-tenant-id"); len(got) != 1 || got[0] != "canary" {
+		t.Errorf("Expected the canary metadata to be sent, got %v", planner.md)
+	}
+
+	planner.action = []float32{0.5, -0.4}
+	if r := c.Check(context.Background()); r.Result != ResultMismatch {
+		t.Errorf("Expected mismatch beyond tolerance, got %s", r.Result)
+	}
+	planner.action = []float32{0.5}
+	if r := c.Check(context.Background()); r.Result != ResultMismatch {
+		t.Errorf("Expected mismatch for a different action size, got %s", r.Result)
+	}
+	if last := c.Last(); last.Result != ResultMismatch {
+		t.Errorf("Expected the last result to be kept, got %s", last.Result)
+	}
+}
+
+func TestCheck_Baseline(t *testing.T) {
+	planner := &fakePlanner{action: []float32{1, 2}}
+	c := New(planner, testConfig())
+
+	// The first action becomes the baseline
+	if r := c.Check(context.Background()); r.Result != ResultOK {
+		t.Fatalf("Expected ok for the baseline, got %s: %s", r.Result, r.Detail)
+	}
+	if r := c.Check(context.Background()); r.Result != ResultOK {
+		t.Errorf("Expected ok for an unchanged action, got %s: %s", r.Result, r.Detail)
+	}
+	planner.action = []float32{1, 2.5}
+	if r := c.Check(context.Background()); r.Result != ResultMismatch {
+		t.Errorf("Expected mismatch after the action changed, got %s", r.Result)
+	}
+}
+
+func TestCheck_ErrorAndSlow(t *testing.T) {
+	planner := &fakePlanner{err: errors.New("unauthenticated")}
+	c := New(planner, testConfig())
+	if r := c.Check(context.Background()); r.Result != ResultError {
+		t.Errorf("Expected error, got %s", r.Result)
+	}
+
+	planner.err, planner.action, planner.delay = nil, []float32{1}, 80*time.Millisecond
+	if r := c.Check(context.Background()); r.Result != ResultSlow {
+		t.Errorf("Expected slow, got %s", r.Result)
+	}
+}
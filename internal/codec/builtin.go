@@ -0,0 +1,143 @@
+// internal/codec/builtin.go
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"math"
+
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+)
+
+// Builtin returns a registry with the built-in codecs; uint8 and JPEG data
+// are normalized with norm
+func Builtin(norm observation.Normalization) *Registry {
+	r := NewRegistry()
+	r.Register(IDFloat32, Float32{})
+	r.Register(IDFloat16, Float16{})
+	r.Register(IDUint8, Uint8{Norm: norm})
+	r.Register(IDJPEG, JPEG{Norm: norm})
+	return r
+}
+
+// Float32 decodes little-endian float32 values
+type Float32 struct{}
+
+// Name implements Codec
+func (Float32) Name() string { return "float32" }
+
+// Decode implements Codec
+func (Float32) Decode(data []byte, c, h, w int) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("float32 data has %d bytes, not a multiple of 4", len(data))
+	}
+	out := make([]float32, len(data)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return out, nil
+}
+
+// Float16 decodes little-endian IEEE-754 half-precision values
+type Float16 struct{}
+
+// Name implements Codec
+func (Float16) Name() string { return "float16" }
+
+// Decode implements Codec
+func (Float16) Decode(data []byte, c, h, w int) ([]float32, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("float16 data has %d bytes, not a multiple of 2", len(data))
+	}
+	out := make([]float32, len(data)/2)
+	for i := range out {
+		out[i] = halfToFloat(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return out, nil
+}
+
+// halfToFloat widens IEEE-754 half-precision bits to float32
+func halfToFloat(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+	switch exp {
+	case 0:
+		// Zero or subnormal: frac * 2^-24
+		v := float32(frac) / (1 << 24)
+		if sign != 0 {
+			v = -v
+		}
+		return v
+	case 0x1f:
+		// Inf or NaN
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+	}
+}
+
+// Uint8 decodes uint8 values with a normalization
+type Uint8 struct {
+	Norm observation.Normalization
+}
+
+// Name implements Codec
+func (Uint8) Name() string { return "uint8" }
+
+// Decode implements Codec
+func (u Uint8) Decode(data []byte, c, h, w int) ([]float32, error) {
+	return u.Norm.Float32(data, c)
+}
+
+// JPEG decodes a JPEG image into one grayscale or three RGB channels, then
+// normalizes the pixels like Uint8
+type JPEG struct {
+	Norm observation.Normalization
+}
+
+// Name implements Codec
+func (JPEG) Name() string { return "jpeg" }
+
+// Decode implements Codec
+func (j JPEG) Decode(data []byte, c, h, w int) ([]float32, error) {
+	if c != 1 && c != 3 {
+		return nil, fmt.Errorf("jpeg observations need 1 or 3 channels, got %d", c)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jpeg: %w", err)
+	}
+	b := img.Bounds()
+	if b.Dx() != w || b.Dy() != h {
+		return nil, fmt.Errorf("jpeg is %dx%d, observation is %dx%d (WxH)", b.Dx(), b.Dy(), w, h)
+	}
+
+	// Planar C,H,W pixels
+	plane := h * w
+	pixels := make([]byte, c*plane)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			px := img.At(b.Min.X+x, b.Min.Y+y)
+			if c == 1 {
+				pixels[i] = color.GrayModel.Convert(px).(color.Gray).Y
+				continue
+			}
+			rgb := color.RGBAModel.Convert(px).(color.RGBA)
+			pixels[i], pixels[plane+i], pixels[2*plane+i] = rgb.R, rgb.G, rgb.B
+		}
+	}
+	return j.Norm.Float32(pixels, c)
+}
+
+// Ensure the built-in codecs implement Codec at compile time
+var (
+	_ Codec = Float32{}
+	_ Codec = Float16{}
+	_ Codec = Uint8{}
+	_ Codec = JPEG{}
+)
@@ -0,0 +1,90 @@
+// internal/codec/codec.go
+
+// Package codec decodes observations sent in encodings other than float32
+// values, such as float16 or JPEG camera frames. Codecs are registered under
+// the ID clients put in Observation.encoding, so site-specific sensor formats
+// only need a small Codec, not handler changes.
+package codec
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// IDs of the built-in codecs. IDs below MinCustomID are reserved for them.
+const (
+	// IDFloat32 is little-endian IEEE-754 float32 values
+	IDFloat32 uint32 = 1
+	// IDFloat16 is little-endian IEEE-754 half-precision values
+	IDFloat16 uint32 = 2
+	// IDUint8 is uint8 values, normalized like Observation.uint8_data
+	IDUint8 uint32 = 3
+	// IDJPEG is a JPEG image of H x W pixels, grayscale for one channel or RGB
+	// for three, normalized like uint8 values
+	IDJPEG uint32 = 4
+
+	// MinCustomID is the first ID for site-specific codecs
+	MinCustomID uint32 = 128
+)
+
+// Codec decodes an observation payload into float32 values in C,H,W order
+type Codec interface {
+	// Name identifies the codec in GetCapabilities and metrics
+	Name() string
+	// Decode returns the c*h*w values of the observation in data
+	Decode(data []byte, c, h, w int) ([]float32, error)
+}
+
+// Registry holds the codecs the server accepts, by ID. It is safe for
+// concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	codecs map[uint32]Codec
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{codecs: make(map[uint32]Codec)}
+}
+
+// Register adds c under id. ID 0 means float32 data and cannot be
+// registered, and an ID can only be registered once.
+func (r *Registry) Register(id uint32, c Codec) error {
+	if id == 0 {
+		return fmt.Errorf("codec %q: encoding 0 is reserved for float32 data", c.Name())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prev, ok := r.codecs[id]; ok {
+		return fmt.Errorf("codec %q: encoding %d is already registered to %q", c.Name(), id, prev.Name())
+	}
+	r.codecs[id] = c
+	return nil
+}
+
+// Lookup returns the codec registered under id
+func (r *Registry) Lookup(id uint32) (Codec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.codecs[id]
+	return c, ok
+}
+
+// Encoding is a registered codec's ID and name
+type Encoding struct {
+	ID   uint32
+	Name string
+}
+
+// Encodings lists the registered codecs by ID
+func (r *Registry) Encodings() []Encoding {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Encoding, 0, len(r.codecs))
+	for id, c := range r.codecs {
+		out = append(out, Encoding{ID: id, Name: c.Name()})
+	}
+	slices.SortFunc(out, func(a, b Encoding) int { return int(a.ID) - int(b.ID) })
+	return out
+}
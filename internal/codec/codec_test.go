@@ -0,0 +1,98 @@
+// internal/codec/codec_test.go
+package codec
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(MinCustomID, Float32{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := r.Register(MinCustomID, Float16{}); err == nil {
+		t.Error("Expected error registering an ID twice")
+	}
+	if err := r.Register(0, Float16{}); err == nil {
+		t.Error("Expected error registering ID 0")
+	}
+	if c, ok := r.Lookup(MinCustomID); !ok || c.Name() != "float32" {
+		t.Errorf("Expected float32 codec, got %v", c)
+	}
+	if _, ok := r.Lookup(IDJPEG); ok {
+		t.Error("Expected empty registry to have no JPEG codec")
+	}
+}
+
+func TestFloat16_Decode(t *testing.T) {
+	// 1, -2, 0.5, 65504 (max), smallest subnormal, +Inf
+	data := []byte{0x00, 0x3c, 0x00, 0xc0, 0x00, 0x38, 0xff, 0x7b, 0x01, 0x00, 0x00, 0x7c}
+	got, err := Float16{}.Decode(data, 1, 1, 6)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := []float32{1, -2, 0.5, 65504, float32(math.Pow(2, -24)), float32(math.Inf(1))}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if _, err := (Float16{}).Decode([]byte{1, 2, 3}, 1, 1, 1); err == nil {
+		t.Error("Expected error for an odd number of bytes")
+	}
+}
+
+func TestFloat32_Decode(t *testing.T) {
+	got, err := Float32{}.Decode([]byte{0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x00, 0xc0}, 1, 1, 2)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if want := []float32{1, -2}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestJPEG_Decode(t *testing.T) {
+	// A 4x2 gray image, lossless enough at full quality for flat regions
+	img := image.NewGray(image.Rect(0, 0, 4, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	codec := JPEG{Norm: observation.DefaultNormalization}
+	gray, err := codec.Decode(buf.Bytes(), 1, 2, 4)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	rgb, err := codec.Decode(buf.Bytes(), 3, 2, 4)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(gray) != 8 || len(rgb) != 24 {
+		t.Fatalf("Expected 8 and 24 values, got %d and %d", len(gray), len(rgb))
+	}
+	for _, v := range append(gray, rgb...) {
+		if math.Abs(float64(v)-200.0/255) > 2.0/255 {
+			t.Fatalf("Expected pixels near %g, got %v", 200.0/255, gray)
+		}
+	}
+
+	if _, err := codec.Decode(buf.Bytes(), 1, 4, 4); err == nil {
+		t.Error("Expected error for an image of another size")
+	}
+	if _, err := codec.Decode(buf.Bytes(), 2, 2, 4); err == nil {
+		t.Error("Expected error for two channels")
+	}
+	if _, err := codec.Decode([]byte("not a jpeg"), 1, 2, 4); err == nil {
+		t.Error("Expected error for invalid data")
+	}
+}
@@ -2,61 +2,634 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/SyedDaiam9101/policy-service/internal/affinity"
+	"github.com/SyedDaiam9101/policy-service/internal/modelfetch"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	"github.com/SyedDaiam9101/policy-service/internal/registry"
 )
 
-// Config holds all configuration for the service
+// Config holds all configuration for the service. A schema tag adds JSON
+// schema constraints to a key (see Schema); keys tagged secret are masked in
+// Settings and Diff.
 type Config struct {
 	// Server configuration
-	Port        int    `mapstructure:"port"`
-	MetricsPort int    `mapstructure:"metrics_port"`
+	Port        int    `mapstructure:"port" schema:"minimum=1,maximum=65535"`
+	MetricsPort int    `mapstructure:"metrics_port" schema:"minimum=0,maximum=65535"` // 0 picks a free port
 	Model       string `mapstructure:"model"`
-	Redis       string `mapstructure:"redis"`
+	// Redis is a host:port, or a comma-separated list of Redis Cluster seed
+	// addresses; RedisCluster selects cluster mode for a single seed. With
+	// RedisSentinelMaster set, Redis lists the sentinels instead.
+	Redis               string `mapstructure:"redis"`
+	RedisCluster        bool   `mapstructure:"redis_cluster"`
+	RedisSentinelMaster string `mapstructure:"redis_sentinel_master"`
+	// Redis AUTH credentials; the password is read from RedisPasswordFile (e.g.
+	// a mounted secret) when set
+	RedisUsername         string `mapstructure:"redis_username"`
+	RedisPassword         string `mapstructure:"redis_password" secret:"true"`
+	RedisPasswordFile     string `mapstructure:"redis_password_file"`
+	RedisSentinelPassword string `mapstructure:"redis_sentinel_password" secret:"true"`
+	RedisDB               int    `mapstructure:"redis_db" schema:"minimum=0"`
+	// TLS to Redis, verified against RedisTLSCA (PEM) or the system roots, with
+	// an optional client certificate for servers requiring one
+	RedisTLS           bool   `mapstructure:"redis_tls"`
+	RedisTLSCA         string `mapstructure:"redis_tls_ca"`
+	RedisTLSCert       string `mapstructure:"redis_tls_cert"`
+	RedisTLSKey        string `mapstructure:"redis_tls_key"`
+	RedisTLSServerName string `mapstructure:"redis_tls_server_name"`
+
+	// Log verbosity and output: "text" (key=value lines) or "json"
+	LogLevel  string `mapstructure:"log_level" schema:"enum=debug|info|warn|error"`
+	LogFormat string `mapstructure:"log_format" schema:"enum=text|json"`
+
+	// TLS certificate and private key (PEM files) for the gRPC listener; when
+	// both are empty the listener serves plaintext
+	TLSCert string `mapstructure:"tls_cert"`
+	TLSKey  string `mapstructure:"tls_key"`
+	// TLSClientCA is a PEM bundle of CAs; when set, clients must present a
+	// certificate signed by one of them (mutual TLS)
+	TLSClientCA string `mapstructure:"tls_client_ca"`
+	// SPIFFE Workload API socket; when set, the gRPC listener serves the
+	// workload's X.509 SVID, rotated by SPIRE, and requires client SVIDs from
+	// SPIFFETrustDomain instead of using the tls_* files
+	SPIFFEEndpointSocket string `mapstructure:"spiffe_endpoint_socket"`
+	SPIFFETrustDomain    string `mapstructure:"spiffe_trust_domain"`
+
+	// Authentication providers tried in order; empty disables authentication
+	AuthProviders []string `mapstructure:"auth_providers" schema:"enum=mtls|spiffe|jwt|api_key|robot_token"`
+	// AuthAPIKeys maps each API key's subject to the hex SHA-256 of the key
+	AuthAPIKeys map[string]string `mapstructure:"auth_api_keys" secret:"true"`
+	// Bearer JWTs are verified with a PEM public key file or the keys at a
+	// JWKS URL (e.g. an OIDC provider's jwks_uri), and must match the issuer
+	// and audience when set
+	AuthJWTPublicKey   string        `mapstructure:"auth_jwt_public_key"`
+	AuthJWTJWKSURL     string        `mapstructure:"auth_jwt_jwks_url"`
+	AuthJWTJWKSRefresh time.Duration `mapstructure:"auth_jwt_jwks_refresh"`
+	AuthJWTIssuer      string        `mapstructure:"auth_jwt_issuer"`
+	AuthJWTAudience    string        `mapstructure:"auth_jwt_audience"`
+	// AuthSPIFFERoles maps SPIFFE IDs to roles; when set, IDs it does not
+	// match are rejected
+	AuthSPIFFERoles []SPIFFERole `mapstructure:"auth_spiffe_roles"`
+	// Authenticated callers belong to the tenant in their AuthTenantClaim
+	// token claim, else to the AuthTenants entry matching their subject
+	// (exactly or by a prefix ending in "/*"), else to the default tenant;
+	// calls whose x-tenant-id header names another tenant are rejected
+	AuthTenantClaim string            `mapstructure:"auth_tenant_claim"`
+	AuthTenants     map[string]string `mapstructure:"auth_tenants"`
+	// Short-lived robot tokens: callers matching robot_token_issuers (exact
+	// subjects, or prefixes ending in "/*") mint per-robot JWTs with
+	// IssueRobotToken, signed with ed25519 keys from robot_token_key_dir and
+	// accepted by the robot_token auth provider
+	RobotTokensEnabled       bool          `mapstructure:"robot_tokens_enabled"`
+	RobotTokenKeyDir         string        `mapstructure:"robot_token_key_dir"`
+	RobotTokenActiveKey      string        `mapstructure:"robot_token_active_key"`
+	RobotTokenIssuer         string        `mapstructure:"robot_token_issuer"`
+	RobotTokenTTL            time.Duration `mapstructure:"robot_token_ttl"`
+	RobotTokenMaxTTL         time.Duration `mapstructure:"robot_token_max_ttl"`
+	RobotTokenIssuers        []string      `mapstructure:"robot_token_issuers"`
+	RobotTokenReloadInterval time.Duration `mapstructure:"robot_token_reload_interval"`
+
+	// Metrics backend: "prometheus" (scraped at /metrics) or "statsd" (pushed, DogStatsD format)
+	MetricsBackend      string        `mapstructure:"metrics_backend" schema:"enum=prometheus|statsd"`
+	StatsDAddress       string        `mapstructure:"statsd_address"`
+	StatsDPrefix        string        `mapstructure:"statsd_prefix"`
+	StatsDTags          []string      `mapstructure:"statsd_tags"`
+	StatsDFlushInterval time.Duration `mapstructure:"statsd_flush_interval"`
+	// Each unary interceptor, and the handler, records its own time in
+	// grpc_interceptor_seconds{stage}
+	InterceptorTimingEnabled bool `mapstructure:"interceptor_timing_enabled"`
 
 	// OpenTelemetry configuration
 	OTELEnabled  bool   `mapstructure:"otel_enabled"`
 	OTELEndpoint string `mapstructure:"otel_endpoint"`
+	// OTELSampleRatio is the fraction of requests traced fleet-wide
+	OTELSampleRatio float64 `mapstructure:"otel_sample_ratio" schema:"minimum=0,maximum=1"`
+	// OTELSampleRobotIDs are always traced, regardless of the sample ratio
+	OTELSampleRobotIDs []uint64 `mapstructure:"otel_sample_robot_ids"`
 
 	// Feature flags
 	UseMockInference bool `mapstructure:"use_mock_inference"`
+
+	// Fair scheduling across tenants
+	FairScheduling bool               `mapstructure:"fair_scheduling"`
+	SchedulerSlots int                `mapstructure:"scheduler_slots"`
+	TenantWeights  map[string]float64 `mapstructure:"tenant_weights" schema:"exclusiveMinimum=0"`
+
+	// Model download: a model given as an s3://, gs://, or https:// URL is
+	// downloaded into model_cache_dir at startup and must match model_sha256
+	ModelCacheDir   string `mapstructure:"model_cache_dir"`
+	ModelSHA256     string `mapstructure:"model_sha256"`
+	ModelS3Endpoint string `mapstructure:"model_s3_endpoint"`
+	ModelS3Region   string `mapstructure:"model_s3_region"`
+
+	// Model registry: with model_registry_model set ("name/stage" or
+	// "name/version"), the model is resolved in the MLflow-style registry at
+	// model_registry_url and model_registry_artifact of that version is
+	// downloaded in place of model
+	ModelRegistryURL      string `mapstructure:"model_registry_url"`
+	ModelRegistryModel    string `mapstructure:"model_registry_model"`
+	ModelRegistryArtifact string `mapstructure:"model_registry_artifact"`
+	ModelRegistryToken    string `mapstructure:"model_registry_token" secret:"true"`
+
+	// Model versions: the models in model_versions stay loaded alongside
+	// model, which is the latest version and is named model_version, so
+	// requests can pin an older one
+	ModelVersion  string         `mapstructure:"model_version"`
+	ModelVersions []ModelVersion `mapstructure:"model_versions"`
+
+	// Int8-quantized models: the graph's quantization format (checked at load,
+	// empty skips the check) and the element type of the model input. Float
+	// observations are quantized for int8/uint8 inputs with the scale and zero point.
+	ModelQuantization   string  `mapstructure:"model_quantization" schema:"enum=|qdq|qoperator"`
+	ModelInputType      string  `mapstructure:"model_input_type" schema:"enum=float32|int8|uint8"`
+	ModelInputScale     float32 `mapstructure:"model_input_scale" schema:"exclusiveMinimum=0"`
+	ModelInputZeroPoint int32   `mapstructure:"model_input_zero_point" schema:"minimum=-128,maximum=255"`
+
+	// ONNX Runtime execution providers, in priority order, and thread pool sizes (0 = ORT default)
+	ORTExecutionProviders []string `mapstructure:"ort_execution_providers" schema:"enum=cuda|tensorrt|cpu"`
+	ORTIntraOpThreads     int      `mapstructure:"ort_intra_op_threads" schema:"minimum=0"`
+	ORTInterOpThreads     int      `mapstructure:"ort_inter_op_threads" schema:"minimum=0"`
+
+	// CPU cores (Linux CPU list, e.g. "0-7,16-23") or NUMA node to pin the
+	// process and ORT's intra-op threads to; -1 disables NUMA pinning
+	CPUAffinity         string `mapstructure:"cpu_affinity"`
+	CPUAffinityNUMANode int    `mapstructure:"cpu_affinity_numa_node" schema:"minimum=-1"`
+
+	// GPUs to pin sessions to; batches are dispatched across all sessions
+	ORTDevices              []int         `mapstructure:"ort_devices" schema:"minimum=0"`
+	ORTDeviceDispatch       string        `mapstructure:"ort_device_dispatch" schema:"enum=round_robin|least_loaded"`
+	ORTDeviceMemoryInterval time.Duration `mapstructure:"ort_device_memory_interval"`
+	// ORTSessionsPerDevice is how many sessions run batches in parallel on
+	// each device (or on the CPU); each holds its own copy of the model
+	ORTSessionsPerDevice int `mapstructure:"ort_sessions_per_device" schema:"minimum=1,maximum=64"`
+	// ORTCUDAMemLimit caps the CUDA provider's memory arena per device, in bytes; 0 is unlimited
+	ORTCUDAMemLimit int64 `mapstructure:"ort_cuda_mem_limit" schema:"minimum=0"`
+	// MaxTensorBytes caps the input tensor packed for one request, in bytes; 0 is unlimited
+	MaxTensorBytes int64 `mapstructure:"max_tensor_bytes" schema:"minimum=0"`
+	// MaxBatchSize caps the observations planned in one call and in one
+	// coalesced batch; 0 is unlimited
+	MaxBatchSize int `mapstructure:"max_batch_size" schema:"minimum=0"`
+	// BatchValidationWorkers validate the observations of BatchPlan calls of
+	// at least BatchValidationMinParallel in parallel (0 = GOMAXPROCS, 1 = in order)
+	BatchValidationWorkers     int `mapstructure:"batch_validation_workers" schema:"minimum=0"`
+	BatchValidationMinParallel int `mapstructure:"batch_validation_min_parallel" schema:"minimum=1"`
+	// TensorRT engine cache directory (empty disables it) and FP16 mode
+	ORTTensorRTEngineCachePath string `mapstructure:"ort_tensorrt_engine_cache_path"`
+	ORTTensorRTFP16            bool   `mapstructure:"ort_tensorrt_fp16"`
+	// ORTOptimizedModelDir keeps the graph TensorRT compiles for each model so
+	// restarts load it instead of optimizing the model again; empty disables it
+	ORTOptimizedModelDir string `mapstructure:"ort_optimized_model_dir"`
+
+	// Discrete action decoding: "continuous", "argmax", or "sample" over action_table
+	ActionMode        string      `mapstructure:"action_mode" schema:"enum=continuous|argmax|sample"`
+	ActionTable       [][]float32 `mapstructure:"action_table"`
+	ActionTemperature float64     `mapstructure:"action_temperature"`
+
+	// Action contract checked after every inference: values per action (0 =
+	// taken from the model or action_table) and [min, max] per value, or a
+	// single [min, max] for all values
+	ActionDim    int         `mapstructure:"action_dim" schema:"minimum=0"`
+	ActionBounds [][]float32 `mapstructure:"action_bounds"`
+
+	// Handling of model outputs holding NaN or Inf: "reject" fails the call
+	// with DataLoss; "substitute" answers those robots with
+	// non_finite_safe_action instead
+	NonFiniteOutputs    string    `mapstructure:"non_finite_outputs" schema:"enum=reject|substitute"`
+	NonFiniteSafeAction []float32 `mapstructure:"non_finite_safe_action"`
+
+	// Safety limits every planned action is checked against: [min, max] per
+	// value, or a single [min, max] for all values. "flag" marks responses
+	// outside them unsafe; "clamp" clamps their actions into them.
+	SafetyEnabled bool        `mapstructure:"safety_enabled"`
+	SafetyMode    string      `mapstructure:"safety_mode" schema:"enum=flag|clamp"`
+	SafetyLimits  [][]float32 `mapstructure:"safety_limits"`
+
+	// Action space reported by GetCapabilities: a name per action value and a
+	// label per action_table row, both optional; echoed in every response when
+	// ActionSpaceInResponses is set
+	ActionValueNames       []string `mapstructure:"action_value_names"`
+	ActionLabels           []string `mapstructure:"action_labels"`
+	ActionSpaceInResponses bool     `mapstructure:"action_space_in_responses"`
+
+	// Per-tenant AES-256-GCM encryption of tenant values cached in Redis
+	CacheEncryptionEnabled bool   `mapstructure:"cache_encryption_enabled"`
+	CacheEncryptionKeyDir  string `mapstructure:"cache_encryption_key_dir"`
+	// Values kept in process to serve poses and plans while Redis is
+	// unavailable; 0 disables caching without Redis
+	CacheLocalFallbackSize int `mapstructure:"cache_local_fallback_size" schema:"minimum=0"`
+
+	// Response signing with ed25519 keys loaded from signing_key_dir
+	SigningEnabled        bool          `mapstructure:"signing_enabled"`
+	SigningKeyDir         string        `mapstructure:"signing_key_dir"`
+	SigningActiveKey      string        `mapstructure:"signing_active_key"`
+	SigningReloadInterval time.Duration `mapstructure:"signing_reload_interval"`
+
+	// Observation enrichment with per-robot features from Redis or a Feast feature server
+	EnrichmentSource   string            `mapstructure:"enrichment_source" schema:"enum=|redis|feast"`
+	EnrichmentFeastURL string            `mapstructure:"enrichment_feast_url"`
+	EnrichmentTimeout  time.Duration     `mapstructure:"enrichment_timeout"`
+	EnrichmentRequired bool              `mapstructure:"enrichment_required"`
+	EnrichmentModels   []EnrichmentModel `mapstructure:"enrichment_models"`
+
+	// AdminToken, when set, is required as a bearer token on /admin/ endpoints
+	AdminToken string `mapstructure:"admin_token" secret:"true"`
+
+	// Staged model rollout (shadow -> 5% -> 50% -> 100%) with automatic rollback
+	RolloutEnabled       bool          `mapstructure:"rollout_enabled"`
+	RolloutInterval      time.Duration `mapstructure:"rollout_interval"`
+	RolloutMaxErrorRate  float64       `mapstructure:"rollout_max_error_rate"`
+	RolloutMaxDivergence float64       `mapstructure:"rollout_max_divergence"`
+	RolloutMinSamples    int           `mapstructure:"rollout_min_samples"`
+	RolloutStageDuration time.Duration `mapstructure:"rollout_stage_duration"`
+	RolloutStateKey      string        `mapstructure:"rollout_state_key"`
+
+	// Watchdog for goroutine growth and stalled inference
+	WatchdogEnabled         bool          `mapstructure:"watchdog_enabled"`
+	WatchdogInterval        time.Duration `mapstructure:"watchdog_interval"`
+	WatchdogGoroutineWindow int           `mapstructure:"watchdog_goroutine_window"`
+	WatchdogGoroutineGrowth int           `mapstructure:"watchdog_goroutine_growth"`
+	WatchdogStallTimeout    time.Duration `mapstructure:"watchdog_stall_timeout"`
+	WatchdogFailHealth      bool          `mapstructure:"watchdog_fail_health"`
+
+	// Synthetic canary: a known observation is planned through the gRPC
+	// listener every interval and must return within max_latency and match
+	// canary_expected_action (or the first action planned) within tolerance
+	CanaryEnabled        bool          `mapstructure:"canary_enabled"`
+	CanaryInterval       time.Duration `mapstructure:"canary_interval"`
+	CanaryTimeout        time.Duration `mapstructure:"canary_timeout"`
+	CanaryMaxLatency     time.Duration `mapstructure:"canary_max_latency"`
+	CanaryTolerance      float64       `mapstructure:"canary_tolerance" schema:"minimum=0"`
+	CanaryExpectedAction []float32     `mapstructure:"canary_expected_action"`
+	CanaryRobotID        uint64        `mapstructure:"canary_robot_id"`
+	CanaryTenant         string        `mapstructure:"canary_tenant"`
+	CanaryAPIKey         string        `mapstructure:"canary_api_key" secret:"true"`
+
+	// Reuse a robot's last action when it re-sends the same observation
+	DedupEnabled bool          `mapstructure:"dedup_enabled"`
+	DedupWindow  time.Duration `mapstructure:"dedup_window"`
+
+	// Share plans for identical observations across robots and replicas
+	// through Redis
+	PlanCacheEnabled bool          `mapstructure:"plan_cache_enabled"`
+	PlanCacheTTL     time.Duration `mapstructure:"plan_cache_ttl"`
+
+	// Use each robot's last pose, cached by robot ID, in planning: append it
+	// to observations or return it in responses
+	PoseMode string        `mapstructure:"pose_mode" schema:"enum=|observation|response"`
+	PoseTTL  time.Duration `mapstructure:"pose_ttl"`
+
+	// Degraded mode: downsample image observations while shedding load or
+	// running on a fallback execution provider
+	DownsampleEnabled bool `mapstructure:"downsample_enabled"`
+	DownsampleFactor  int  `mapstructure:"downsample_factor" schema:"minimum=2"`
+	DownsampleMinSize int  `mapstructure:"downsample_min_size" schema:"minimum=1"`
+
+	// Turn off action sampling, caches, batching, downsampling, and rollout
+	// so replayed recordings produce bit-identical actions
+	ReplayMode bool `mapstructure:"replay_mode"`
+
+	// Reload the model when its file changes, after writes settle
+	ModelWatch       bool          `mapstructure:"model_watch"`
+	ModelWatchSettle time.Duration `mapstructure:"model_watch_settle"`
+
+	// Keep the previous model loaded after a reload for instant rollback,
+	// unless the model file is over the byte limit (0 = no limit)
+	ModelKeepPrevious         bool  `mapstructure:"model_keep_previous"`
+	ModelKeepPreviousMaxBytes int64 `mapstructure:"model_keep_previous_max_bytes" schema:"minimum=0"`
+
+	// Synthetic inferences per batch size run before the server reports
+	// SERVING and before a reloaded model is swapped in; 0 disables warmup
+	WarmupInferences int   `mapstructure:"warmup_inferences" schema:"minimum=0,maximum=1000"`
+	WarmupBatchSizes []int `mapstructure:"warmup_batch_sizes"`
+
+	// Coalesce concurrent calls into shared inference batches
+	BatchingEnabled  bool          `mapstructure:"batching_enabled"`
+	BatchingWindow   time.Duration `mapstructure:"batching_window"`
+	BatchingMaxBatch int           `mapstructure:"batching_max_batch"`
+
+	// BatchPlanStream default chunk size and chunks run concurrently per call
+	StreamChunkSize           int `mapstructure:"stream_chunk_size" schema:"minimum=1"`
+	StreamMaxConcurrentChunks int `mapstructure:"stream_max_concurrent_chunks" schema:"minimum=1"`
+
+	// PlanStream default replanning interval and the shortest a call may ask for
+	PlanStreamInterval    time.Duration `mapstructure:"plan_stream_interval"`
+	PlanStreamMinInterval time.Duration `mapstructure:"plan_stream_min_interval"`
+
+	// AggregatePlan requests planned per inference batch and the most one
+	// call may send
+	AggregateBatchSize   int `mapstructure:"aggregate_batch_size" schema:"minimum=1"`
+	AggregateMaxRequests int `mapstructure:"aggregate_max_requests" schema:"minimum=1"`
+
+	// Streams without a message received or sent for stream_idle_timeout are
+	// closed with UNAVAILABLE; 0 keeps them open
+	StreamIdleTimeout time.Duration `mapstructure:"stream_idle_timeout"`
+
+	// Server keepalive: ping idle connections every grpc_keepalive_time (0 is
+	// gRPC's default of two hours) and close them after grpc_keepalive_timeout
+	// without an answer. Clients pinging more often than
+	// grpc_keepalive_min_time are disconnected.
+	GRPCKeepaliveTime    time.Duration `mapstructure:"grpc_keepalive_time"`
+	GRPCKeepaliveTimeout time.Duration `mapstructure:"grpc_keepalive_timeout"`
+	GRPCKeepaliveMinTime time.Duration `mapstructure:"grpc_keepalive_min_time"`
+
+	// Queue-aware client hints: trailers with the queue depth and a suggested
+	// call interval, stretched once more than client_hints_queue_target
+	// observations are queued
+	ClientHintsEnabled      bool          `mapstructure:"client_hints_enabled"`
+	ClientHintsQueueTarget  int           `mapstructure:"client_hints_queue_target" schema:"minimum=1"`
+	ClientHintsBaseInterval time.Duration `mapstructure:"client_hints_base_interval"`
+	ClientHintsMaxInterval  time.Duration `mapstructure:"client_hints_max_interval"`
+
+	// PlanAsync background planning: workers, queued jobs, and how long results are kept
+	AsyncEnabled   bool          `mapstructure:"async_enabled"`
+	AsyncWorkers   int           `mapstructure:"async_workers" schema:"minimum=1"`
+	AsyncQueueSize int           `mapstructure:"async_queue_size" schema:"minimum=1"`
+	AsyncResultTTL time.Duration `mapstructure:"async_result_ttl"`
+
+	// GetPlanUpdates long polling: how often polling robots are planned, how
+	// long a poll waits for a plan, and when a silent robot is unscheduled
+	PlanUpdatesEnabled     bool          `mapstructure:"plan_updates_enabled"`
+	PlanUpdatesInterval    time.Duration `mapstructure:"plan_updates_interval"`
+	PlanUpdatesMaxWait     time.Duration `mapstructure:"plan_updates_max_wait"`
+	PlanUpdatesIdleTimeout time.Duration `mapstructure:"plan_updates_idle_timeout"`
+
+	// Episode recorder: served observations and actions go to a Redis stream,
+	// which one replica compacts hourly into S3
+	RecorderEnabled            bool          `mapstructure:"recorder_enabled"`
+	RecorderStream             string        `mapstructure:"recorder_stream"`
+	RecorderStreamMaxLen       int64         `mapstructure:"recorder_stream_max_len" schema:"minimum=0"`
+	RecorderBufferSize         int           `mapstructure:"recorder_buffer_size" schema:"minimum=1"`
+	RecorderDedupWindow        time.Duration `mapstructure:"recorder_dedup_window"`
+	RecorderCompactionEnabled  bool          `mapstructure:"recorder_compaction_enabled"`
+	RecorderCompactionInterval time.Duration `mapstructure:"recorder_compaction_interval"`
+	RecorderS3Endpoint         string        `mapstructure:"recorder_s3_endpoint"`
+	RecorderS3Region           string        `mapstructure:"recorder_s3_region"`
+	RecorderS3Bucket           string        `mapstructure:"recorder_s3_bucket"`
+	RecorderS3Prefix           string        `mapstructure:"recorder_s3_prefix"`
+
+	// Plan publisher: each planned action is sent as JSON to a Redis pub/sub
+	// channel or stream for telemetry and visualization subscribers
+	PublishEnabled      bool   `mapstructure:"publish_enabled"`
+	PublishMode         string `mapstructure:"publish_mode" schema:"enum=channel|stream"`
+	PublishTarget       string `mapstructure:"publish_target"`
+	PublishStreamMaxLen int64  `mapstructure:"publish_stream_max_len" schema:"minimum=0"`
+	PublishBufferSize   int    `mapstructure:"publish_buffer_size" schema:"minimum=1"`
+
+	// Robot placement: each replica claims the robots it plans in Redis, so
+	// robots moved between replicas are logged and counted. replica_id
+	// defaults to the hostname.
+	PlacementEnabled bool          `mapstructure:"placement_enabled"`
+	PlacementIdle    time.Duration `mapstructure:"placement_idle"`
+	ReplicaID        string        `mapstructure:"replica_id"`
+
+	// State snapshots: per-robot in-memory state (dedup window, plan update
+	// schedule) is saved to Redis every interval under key:replica_id and
+	// restored on startup unless older than max_age
+	StateSnapshotEnabled  bool          `mapstructure:"state_snapshot_enabled"`
+	StateSnapshotInterval time.Duration `mapstructure:"state_snapshot_interval"`
+	StateSnapshotMaxAge   time.Duration `mapstructure:"state_snapshot_max_age"`
+	StateSnapshotKey      string        `mapstructure:"state_snapshot_key"`
+
+	// Upstream proxy: calls an edge instance cannot serve (no model loaded,
+	// inference unavailable, or upstream_queue_threshold observations queued)
+	// are planned by the policy-service at upstream_address. Empty disables.
+	UpstreamAddress        string        `mapstructure:"upstream_address"`
+	UpstreamTLS            bool          `mapstructure:"upstream_tls"`
+	UpstreamTLSCA          string        `mapstructure:"upstream_tls_ca"`
+	UpstreamTLSCert        string        `mapstructure:"upstream_tls_cert"`
+	UpstreamTLSKey         string        `mapstructure:"upstream_tls_key"`
+	UpstreamTLSServerName  string        `mapstructure:"upstream_tls_server_name"`
+	UpstreamTimeout        time.Duration `mapstructure:"upstream_timeout"`
+	UpstreamQueueThreshold int64         `mapstructure:"upstream_queue_threshold" schema:"minimum=0"`
+	UpstreamMaxHops        int           `mapstructure:"upstream_max_hops" schema:"minimum=1"`
+	UpstreamCacheTTL       time.Duration `mapstructure:"upstream_cache_ttl"`
+
+	// Scheduled batch planning: robot groups planned at fixed control epochs
+	// from their latest observations in Redis
+	PlanSchedules []PlanSchedule `mapstructure:"plan_schedules"`
+
+	// Request journal: per-robot call summaries (no observation tensors) kept
+	// on local disk for journal_retention, within about journal_max_bytes
+	JournalEnabled    bool          `mapstructure:"journal_enabled"`
+	JournalDir        string        `mapstructure:"journal_dir"`
+	JournalRetention  time.Duration `mapstructure:"journal_retention"`
+	JournalMaxBytes   int64         `mapstructure:"journal_max_bytes" schema:"minimum=1"`
+	JournalBufferSize int           `mapstructure:"journal_buffer_size" schema:"minimum=1"`
+
+	// Error-budget load shedding: bulk traffic (x-priority: bulk, and PlanAsync)
+	// is rejected while both burn-rate windows exceed the threshold
+	SheddingEnabled      bool          `mapstructure:"shedding_enabled"`
+	SheddingSLOTarget    float64       `mapstructure:"shedding_slo_target" schema:"exclusiveMinimum=0,exclusiveMaximum=1"`
+	SheddingShortWindow  time.Duration `mapstructure:"shedding_short_window"`
+	SheddingLongWindow   time.Duration `mapstructure:"shedding_long_window"`
+	SheddingBurnRate     float64       `mapstructure:"shedding_burn_rate" schema:"exclusiveMinimum=0"`
+	SheddingMinRequests  int64         `mapstructure:"shedding_min_requests" schema:"minimum=0"`
+	SheddingEvalInterval time.Duration `mapstructure:"shedding_eval_interval"`
+
+	// Admission control: new Plan and BatchPlan calls are rejected with
+	// ResourceExhausted while admission_max_in_flight calls are running or
+	// inference batches have waited over admission_max_queue_latency for a
+	// whole admission_interval; 0 leaves a limit unset
+	AdmissionEnabled         bool          `mapstructure:"admission_enabled"`
+	AdmissionMaxInFlight     int64         `mapstructure:"admission_max_in_flight" schema:"minimum=0"`
+	AdmissionMaxQueueLatency time.Duration `mapstructure:"admission_max_queue_latency"`
+	AdmissionInterval        time.Duration `mapstructure:"admission_interval"`
+
+	// Inference circuit breaker: after breaker_failure_threshold consecutive
+	// inference failures, batches fail fast with Unavailable, with one probe
+	// batch let through every breaker_probe_interval
+	BreakerEnabled          bool          `mapstructure:"breaker_enabled"`
+	BreakerFailureThreshold int           `mapstructure:"breaker_failure_threshold" schema:"minimum=1"`
+	BreakerProbeInterval    time.Duration `mapstructure:"breaker_probe_interval"`
+
+	// Stage timeouts: batches fail with DeadlineExceeded after
+	// inference_timeout, and pose and plan cache lookups are abandoned (and
+	// planning goes on without them) after cache_timeout; 0 disables either
+	InferenceTimeout time.Duration `mapstructure:"inference_timeout"`
+	CacheTimeout     time.Duration `mapstructure:"cache_timeout"`
+	// Adaptive timeouts: each stage timeout starts at its static value (its
+	// ceiling when 0) and is retuned to adaptive_timeout_multiplier times the
+	// adaptive_timeout_percentile of the stage's last adaptive_timeout_window
+	// latencies, within the stage's floor and ceiling
+	AdaptiveTimeoutsEnabled   bool          `mapstructure:"adaptive_timeouts_enabled"`
+	AdaptiveTimeoutWindow     int           `mapstructure:"adaptive_timeout_window" schema:"minimum=10"`
+	AdaptiveTimeoutPercentile float64       `mapstructure:"adaptive_timeout_percentile" schema:"minimum=0,maximum=1"`
+	AdaptiveTimeoutMultiplier float64       `mapstructure:"adaptive_timeout_multiplier" schema:"minimum=1"`
+	InferenceTimeoutFloor     time.Duration `mapstructure:"inference_timeout_floor"`
+	InferenceTimeoutCeiling   time.Duration `mapstructure:"inference_timeout_ceiling"`
+	CacheTimeoutFloor         time.Duration `mapstructure:"cache_timeout_floor"`
+	CacheTimeoutCeiling       time.Duration `mapstructure:"cache_timeout_ceiling"`
+
+	// Per-client rate limiting: each client (by authenticated subject, else
+	// peer address) gets a token bucket per method refilled at rate_limit_rps.
+	// A positive rate_limit_peer_rps also gives each peer address a bucket
+	// per method, checked before authentication.
+	RateLimitEnabled   bool              `mapstructure:"rate_limit_enabled"`
+	RateLimitRPS       float64           `mapstructure:"rate_limit_rps" schema:"minimum=0"`
+	RateLimitBurst     int               `mapstructure:"rate_limit_burst" schema:"minimum=1"`
+	RateLimitMethods   []RateLimitMethod `mapstructure:"rate_limit_methods"`
+	RateLimitPeerRPS   float64           `mapstructure:"rate_limit_peer_rps" schema:"minimum=0"`
+	RateLimitPeerBurst int               `mapstructure:"rate_limit_peer_burst" schema:"minimum=1"`
+
+	// Expected observation value ranges per model and layout
+	InputRanges []InputRange `mapstructure:"input_ranges"`
+
+	// Observations sent as uint8_data are converted to float32 as
+	// (x * uint8_scale - uint8_mean[c]) / uint8_std[c]; mean and std hold one
+	// value for all channels or one per channel
+	Uint8Scale float32   `mapstructure:"uint8_scale" schema:"exclusiveMinimum=0"`
+	Uint8Mean  []float32 `mapstructure:"uint8_mean"`
+	Uint8Std   []float32 `mapstructure:"uint8_std" schema:"exclusiveMinimum=0"`
+
+	// Preprocessing pipelines per model and layout, run before inference
+	Preprocess []PreprocessPipeline `mapstructure:"preprocess"`
+
+	// Per-model alerting thresholds, exported as model_alert_threshold gauges
+	ModelThresholds []ModelThreshold `mapstructure:"model_thresholds"`
+
+	// How long planned actions stay valid, by default and per model and robot
+	// class; 0 leaves actions without a limit
+	ActionValidity      time.Duration        `mapstructure:"action_validity"`
+	ActionValidityRules []ActionValidityRule `mapstructure:"action_validity_rules"`
+
+	// Two-phase commit: actions for matching clients are proposals the robot
+	// must commit, which supervisors can veto within the window
+	CommitEnabled        bool          `mapstructure:"commit_enabled"`
+	CommitVetoWindow     time.Duration `mapstructure:"commit_veto_window"`
+	CommitTTL            time.Duration `mapstructure:"commit_ttl"`
+	CommitClients        []string      `mapstructure:"commit_clients"`
+	CommitSupervisorRole string        `mapstructure:"commit_supervisor_role"`
+
+	// Public telemetry snapshot for dashboards
+	TelemetryEnabled      bool          `mapstructure:"telemetry_enabled"`
+	TelemetryInterval     time.Duration `mapstructure:"telemetry_interval"`
+	TelemetryActiveWindow time.Duration `mapstructure:"telemetry_active_window"`
 }
 
-// Load loads configuration from flags, environment variables, and optional config file.
-// Priority (highest to lowest): flags > env vars > config file > defaults
-func Load() (*Config, error) {
-	v := viper.New()
+// EnrichmentModel lists the features appended to observations for one model
+type EnrichmentModel struct {
+	// Model matches the configured model path or its file name
+	Model    string              `mapstructure:"model"`
+	Features []EnrichmentFeature `mapstructure:"features"`
+}
 
-	// Set defaults
-	v.SetDefault("port", 50051)
-	v.SetDefault("metrics_port", 9100)
-	v.SetDefault("model", "policy_cpu.onnx")
-	v.SetDefault("redis", "localhost:6379")
-	v.SetDefault("otel_enabled", false)
-	v.SetDefault("otel_endpoint", "")
-	v.SetDefault("use_mock_inference", false)
+// EnrichmentFeature is one feature appended to observations, in order
+type EnrichmentFeature struct {
+	Name    string    `mapstructure:"name"`
+	Dim     int       `mapstructure:"dim"`
+	Default []float32 `mapstructure:"default"`
+}
 
-	// Environment variable configuration
-	v.SetEnvPrefix("POLICY_SERVICE")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
+// RateLimitMethod overrides the rate limit for one method
+type RateLimitMethod struct {
+	// Method is the full gRPC method name, e.g. "/planner.PathPlanner/Plan"
+	Method string `mapstructure:"method"`
+	// RPS of 0 leaves the method unlimited
+	RPS   float64 `mapstructure:"rps" schema:"minimum=0"`
+	Burst int     `mapstructure:"burst" schema:"minimum=1"`
+}
 
-	// Also read OTEL standard env vars
-	if otelEndpoint := viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"); otelEndpoint != "" {
-		v.Set("otel_endpoint", otelEndpoint)
-		v.Set("otel_enabled", true)
-	}
+// SPIFFERole grants roles to callers with matching SPIFFE IDs
+type SPIFFERole struct {
+	// ID is a SPIFFE ID, or a prefix ending in "/*" (e.g. "spiffe://example.org/robot/*")
+	ID    string   `mapstructure:"id"`
+	Roles []string `mapstructure:"roles"`
+}
 
-	// Bind specific environment variables
-	v.BindEnv("port", "POLICY_SERVICE_PORT")
-	v.BindEnv("metrics_port", "POLICY_SERVICE_METRICS_PORT")
-	v.BindEnv("model", "POLICY_SERVICE_MODEL")
-	v.BindEnv("redis", "POLICY_SERVICE_REDIS")
-	v.BindEnv("otel_enabled", "POLICY_SERVICE_OTEL_ENABLED")
-	v.BindEnv("otel_endpoint", "POLICY_SERVICE_OTEL_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT")
-	v.BindEnv("use_mock_inference", "POLICY_SERVICE_USE_MOCK")
+// InputRange is the value range a model expects for observations of one layout
+type InputRange struct {
+	// Model matches the configured model path or its file name; empty matches any
+	Model string `mapstructure:"model"`
+	// Layout is CxHxW with * for any size (e.g. "3x*x*"); empty matches any
+	Layout    string  `mapstructure:"layout"`
+	Min       float32 `mapstructure:"min"`
+	Max       float32 `mapstructure:"max"`
+	Tolerance float32 `mapstructure:"tolerance" schema:"minimum=0"`
+	// Action is "reject" (default) or "clamp"
+	Action string `mapstructure:"action" schema:"enum=|reject|clamp"`
+}
+
+// PreprocessPipeline is the preprocessing a model expects for a layout
+type PreprocessPipeline struct {
+	// Model matches the configured model path or its file name; empty matches any
+	Model string `mapstructure:"model"`
+	// Layout is CxHxW with * for any size (e.g. "3x*x*"); empty matches any
+	Layout string           `mapstructure:"layout"`
+	Steps  []PreprocessStep `mapstructure:"steps"`
+}
+
+// PreprocessStep is one step of a preprocessing pipeline; each op reads only
+// its own fields
+type PreprocessStep struct {
+	// Op is normalize (mean, std), resize (height, width), channel_order
+	// (order), or crop (height, width, and y and x or center)
+	Op     string    `mapstructure:"op" schema:"enum=normalize|resize|channel_order|crop"`
+	Mean   []float32 `mapstructure:"mean"`
+	Std    []float32 `mapstructure:"std" schema:"exclusiveMinimum=0"`
+	Height int       `mapstructure:"height" schema:"minimum=0"`
+	Width  int       `mapstructure:"width" schema:"minimum=0"`
+	Y      int       `mapstructure:"y" schema:"minimum=0"`
+	X      int       `mapstructure:"x" schema:"minimum=0"`
+	Center bool      `mapstructure:"center"`
+	Order  []int     `mapstructure:"order" schema:"minimum=0"`
+}
+
+// ModelThreshold holds the alerting limits for one model; zero leaves a limit
+// unset
+type ModelThreshold struct {
+	// Model matches the configured model path or its file name; empty matches any
+	Model         string        `mapstructure:"model"`
+	MaxLatency    time.Duration `mapstructure:"max_latency"`
+	MaxDivergence float64       `mapstructure:"max_divergence" schema:"minimum=0"`
+}
+
+// ModelVersion is a model kept loaded for requests that pin its version
+type ModelVersion struct {
+	// Version is the name requests pin, e.g. "v3"
+	Version string `mapstructure:"version"`
+	// Model is the version's local model path
+	Model string `mapstructure:"model"`
+}
+
+// ActionValidityRule sets how long actions stay valid for one model and robot
+// class
+type ActionValidityRule struct {
+	// Model matches the configured model path or its file name; empty matches any
+	Model string `mapstructure:"model"`
+	// Client matches the caller's client identity exactly or, when it ends in
+	// "/*", by prefix (e.g. "spiffe://example.org/robot/arm/*"); empty matches any
+	Client   string        `mapstructure:"client"`
+	Validity time.Duration `mapstructure:"validity"`
+}
+
+// PlanSchedule plans a group of robots every interval and publishes their
+// actions to a Redis channel or stream
+type PlanSchedule struct {
+	Name     string        `mapstructure:"name"`
+	Tenant   string        `mapstructure:"tenant"`
+	RobotIDs []uint64      `mapstructure:"robot_ids"`
+	Interval time.Duration `mapstructure:"interval"`
+	// Mode is "channel" (default) or "stream"
+	Mode   string `mapstructure:"mode" schema:"enum=|channel|stream"`
+	Target string `mapstructure:"target"`
+	// StreamMaxLen caps a stream at roughly this many messages; 0 means no cap
+	StreamMaxLen int64 `mapstructure:"stream_max_len" schema:"minimum=0"`
+}
+
+// Load loads configuration from flags, environment variables, and optional config file.
+// Priority (highest to lowest): flags > env vars > config file > defaults
+func Load() (*Config, error) {
+	v := newViper()
 
 	// Config file (optional)
 	v.SetConfigName("config")
@@ -74,43 +647,307 @@ func Load() (*Config, error) {
 		// Config file not found; ignore
 	}
 
-	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-	}
-
-	return &cfg, nil
+	return unmarshal(v)
 }
 
 // LoadWithConfigFile loads configuration from a specific config file
 func LoadWithConfigFile(configPath string) (*Config, error) {
+	v := newViper()
+
+	// Read specific config file
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", configPath, err)
+	}
+
+	return unmarshal(v)
+}
+
+// newViper returns a viper instance with defaults and environment bindings applied
+func newViper() *viper.Viper {
 	v := viper.New()
+	setDefaults(v)
 
-	// Set defaults (same as Load)
+	// Environment variable configuration
+	v.SetEnvPrefix("POLICY_SERVICE")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	// Also read OTEL standard env vars
+	if otelEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); otelEndpoint != "" {
+		v.Set("otel_endpoint", otelEndpoint)
+		v.Set("otel_enabled", true)
+	}
+
+	// Bind specific environment variables
+	v.BindEnv("port", "POLICY_SERVICE_PORT")
+	v.BindEnv("metrics_port", "POLICY_SERVICE_METRICS_PORT")
+	v.BindEnv("model", "POLICY_SERVICE_MODEL")
+	v.BindEnv("redis", "POLICY_SERVICE_REDIS")
+	v.BindEnv("otel_enabled", "POLICY_SERVICE_OTEL_ENABLED")
+	v.BindEnv("otel_endpoint", "POLICY_SERVICE_OTEL_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT")
+	v.BindEnv("otel_sample_ratio", "POLICY_SERVICE_OTEL_SAMPLE_RATIO")
+	v.BindEnv("otel_sample_robot_ids", "POLICY_SERVICE_OTEL_SAMPLE_ROBOT_IDS")
+	v.BindEnv("use_mock_inference", "POLICY_SERVICE_USE_MOCK")
+
+	return v
+}
+
+// setDefaults sets the default for every config key
+func setDefaults(v *viper.Viper) {
 	v.SetDefault("port", 50051)
 	v.SetDefault("metrics_port", 9100)
 	v.SetDefault("model", "policy_cpu.onnx")
 	v.SetDefault("redis", "localhost:6379")
+	v.SetDefault("redis_cluster", false)
+	v.SetDefault("redis_sentinel_master", "")
+	v.SetDefault("redis_username", "")
+	v.SetDefault("redis_password", "")
+	v.SetDefault("redis_password_file", "")
+	v.SetDefault("redis_sentinel_password", "")
+	v.SetDefault("redis_db", 0)
+	v.SetDefault("redis_tls", false)
+	v.SetDefault("redis_tls_ca", "")
+	v.SetDefault("redis_tls_cert", "")
+	v.SetDefault("redis_tls_key", "")
+	v.SetDefault("redis_tls_server_name", "")
+	v.SetDefault("log_level", "info")
+	v.SetDefault("log_format", "text")
+	v.SetDefault("tls_cert", "")
+	v.SetDefault("tls_key", "")
+	v.SetDefault("tls_client_ca", "")
+	v.SetDefault("spiffe_endpoint_socket", "")
+	v.SetDefault("spiffe_trust_domain", "")
+	v.SetDefault("auth_providers", []string{})
+	v.SetDefault("auth_api_keys", map[string]string{})
+	v.SetDefault("auth_jwt_public_key", "")
+	v.SetDefault("auth_jwt_jwks_url", "")
+	v.SetDefault("auth_jwt_jwks_refresh", time.Hour)
+	v.SetDefault("auth_jwt_issuer", "")
+	v.SetDefault("auth_jwt_audience", "")
+	v.SetDefault("auth_tenant_claim", "tenant")
+	v.SetDefault("auth_tenants", map[string]string{})
+	v.SetDefault("metrics_backend", "prometheus")
+	v.SetDefault("statsd_address", "127.0.0.1:8125")
+	v.SetDefault("statsd_prefix", "policy_service.")
+	v.SetDefault("statsd_flush_interval", time.Second)
+	v.SetDefault("interceptor_timing_enabled", false)
 	v.SetDefault("otel_enabled", false)
 	v.SetDefault("otel_endpoint", "")
+	v.SetDefault("otel_sample_ratio", 1.0)
+	v.SetDefault("otel_sample_robot_ids", []uint64{})
 	v.SetDefault("use_mock_inference", false)
+	v.SetDefault("fair_scheduling", false)
+	v.SetDefault("scheduler_slots", 1)
+	v.SetDefault("model_cache_dir", "/tmp/policy-service/models")
+	v.SetDefault("model_sha256", "")
+	v.SetDefault("model_s3_endpoint", "")
+	v.SetDefault("model_s3_region", "us-east-1")
+	v.SetDefault("model_registry_url", "")
+	v.SetDefault("model_registry_model", "")
+	v.SetDefault("model_registry_artifact", "model.onnx")
+	v.SetDefault("model_registry_token", "")
+	v.SetDefault("model_version", "latest")
+	v.SetDefault("model_quantization", "")
+	v.SetDefault("model_input_type", "float32")
+	v.SetDefault("model_input_scale", 1.0)
+	v.SetDefault("model_input_zero_point", 0)
+	v.SetDefault("ort_execution_providers", []string{"cpu"})
+	v.SetDefault("ort_intra_op_threads", 0)
+	v.SetDefault("ort_inter_op_threads", 0)
+	v.SetDefault("cpu_affinity", "")
+	v.SetDefault("cpu_affinity_numa_node", -1)
+	v.SetDefault("ort_devices", []int{})
+	v.SetDefault("ort_device_dispatch", "round_robin")
+	v.SetDefault("ort_sessions_per_device", 1)
+	v.SetDefault("ort_device_memory_interval", 15*time.Second)
+	v.SetDefault("ort_cuda_mem_limit", 0)
+	v.SetDefault("max_tensor_bytes", 0)
+	v.SetDefault("max_batch_size", 1024)
+	v.SetDefault("uint8_scale", 1.0/255)
+	v.SetDefault("uint8_mean", []float32{})
+	v.SetDefault("uint8_std", []float32{})
+	v.SetDefault("batch_validation_workers", 0)
+	v.SetDefault("batch_validation_min_parallel", 256)
+	v.SetDefault("ort_tensorrt_engine_cache_path", "")
+	v.SetDefault("ort_tensorrt_fp16", false)
+	v.SetDefault("ort_optimized_model_dir", "")
+	v.SetDefault("action_mode", "continuous")
+	v.SetDefault("action_temperature", 1.0)
+	v.SetDefault("action_dim", 0)
+	v.SetDefault("action_space_in_responses", false)
+	v.SetDefault("non_finite_outputs", "reject")
+	v.SetDefault("safety_enabled", false)
+	v.SetDefault("safety_mode", "flag")
+	v.SetDefault("cache_encryption_enabled", false)
+	v.SetDefault("cache_encryption_key_dir", "/etc/policy-service/cache-keys")
+	v.SetDefault("cache_local_fallback_size", 10000)
+	v.SetDefault("robot_tokens_enabled", false)
+	v.SetDefault("robot_token_key_dir", "/etc/policy-service/robot-tokens")
+	v.SetDefault("robot_token_active_key", "")
+	v.SetDefault("robot_token_issuer", "policy-service")
+	v.SetDefault("robot_token_ttl", time.Hour)
+	v.SetDefault("robot_token_max_ttl", 24*time.Hour)
+	v.SetDefault("robot_token_issuers", []string{})
+	v.SetDefault("robot_token_reload_interval", time.Minute)
 
-	// Environment variable configuration
-	v.SetEnvPrefix("POLICY_SERVICE")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
+	v.SetDefault("signing_enabled", false)
+	v.SetDefault("signing_key_dir", "/etc/policy-service/signing")
+	v.SetDefault("signing_active_key", "")
+	v.SetDefault("signing_reload_interval", time.Minute)
+	v.SetDefault("enrichment_source", "")
+	v.SetDefault("enrichment_feast_url", "")
+	v.SetDefault("enrichment_timeout", 20*time.Millisecond)
+	v.SetDefault("enrichment_required", false)
+	v.SetDefault("admin_token", "")
+	v.SetDefault("rollout_enabled", false)
+	v.SetDefault("rollout_interval", 30*time.Second)
+	v.SetDefault("rollout_max_error_rate", 0.05)
+	v.SetDefault("rollout_max_divergence", 0.1)
+	v.SetDefault("rollout_min_samples", 100)
+	v.SetDefault("rollout_stage_duration", 0)
+	v.SetDefault("rollout_state_key", "policy-service:rollout")
+	v.SetDefault("watchdog_enabled", true)
+	v.SetDefault("watchdog_interval", 10*time.Second)
+	v.SetDefault("watchdog_goroutine_window", 6)
+	v.SetDefault("watchdog_goroutine_growth", 500)
+	v.SetDefault("watchdog_stall_timeout", 30*time.Second)
+	v.SetDefault("watchdog_fail_health", false)
+	v.SetDefault("canary_enabled", false)
+	v.SetDefault("canary_interval", 30*time.Second)
+	v.SetDefault("canary_timeout", 5*time.Second)
+	v.SetDefault("canary_max_latency", 250*time.Millisecond)
+	v.SetDefault("canary_tolerance", 1e-4)
+	v.SetDefault("canary_expected_action", []float32{})
+	v.SetDefault("canary_robot_id", 0)
+	v.SetDefault("canary_tenant", "")
+	v.SetDefault("canary_api_key", "")
+	v.SetDefault("dedup_enabled", false)
+	v.SetDefault("dedup_window", 500*time.Millisecond)
+	v.SetDefault("plan_cache_enabled", false)
+	v.SetDefault("plan_cache_ttl", 2*time.Second)
+	v.SetDefault("pose_mode", "")
+	v.SetDefault("pose_ttl", time.Minute)
+	v.SetDefault("action_validity", time.Duration(0))
+	v.SetDefault("commit_enabled", false)
+	v.SetDefault("commit_veto_window", 200*time.Millisecond)
+	v.SetDefault("commit_ttl", 10*time.Second)
+	v.SetDefault("commit_clients", []string{})
+	v.SetDefault("commit_supervisor_role", "supervisor")
+	v.SetDefault("downsample_enabled", false)
+	v.SetDefault("downsample_factor", 2)
+	v.SetDefault("downsample_min_size", 64)
+	v.SetDefault("model_watch", false)
+	v.SetDefault("replay_mode", false)
+	v.SetDefault("model_watch_settle", 2*time.Second)
+	v.SetDefault("model_keep_previous", false)
+	v.SetDefault("model_keep_previous_max_bytes", 0)
+	v.SetDefault("warmup_inferences", 0)
+	v.SetDefault("warmup_batch_sizes", []int{1})
+	v.SetDefault("batching_enabled", false)
+	v.SetDefault("batching_window", 2*time.Millisecond)
+	v.SetDefault("batching_max_batch", 64)
+	v.SetDefault("stream_chunk_size", 32)
+	v.SetDefault("plan_stream_interval", 100*time.Millisecond)
+	v.SetDefault("plan_stream_min_interval", 20*time.Millisecond)
+	v.SetDefault("aggregate_batch_size", 256)
+	v.SetDefault("aggregate_max_requests", 16384)
+	v.SetDefault("client_hints_enabled", false)
+	v.SetDefault("client_hints_queue_target", 64)
+	v.SetDefault("client_hints_base_interval", 33*time.Millisecond)
+	v.SetDefault("client_hints_max_interval", time.Second)
+	v.SetDefault("stream_max_concurrent_chunks", 4)
+	v.SetDefault("stream_idle_timeout", time.Duration(0))
+	v.SetDefault("grpc_keepalive_time", time.Duration(0))
+	v.SetDefault("grpc_keepalive_timeout", 20*time.Second)
+	v.SetDefault("grpc_keepalive_min_time", 5*time.Minute)
+	v.SetDefault("async_enabled", false)
+	v.SetDefault("async_workers", 1)
+	v.SetDefault("async_queue_size", 1024)
+	v.SetDefault("async_result_ttl", 10*time.Minute)
+	v.SetDefault("plan_updates_enabled", false)
+	v.SetDefault("plan_updates_interval", time.Second)
+	v.SetDefault("plan_updates_max_wait", 30*time.Second)
+	v.SetDefault("plan_updates_idle_timeout", 5*time.Minute)
+	v.SetDefault("shedding_enabled", false)
+	v.SetDefault("shedding_slo_target", 0.999)
+	v.SetDefault("shedding_short_window", 5*time.Minute)
+	v.SetDefault("shedding_long_window", time.Hour)
+	v.SetDefault("shedding_burn_rate", 14.4)
+	v.SetDefault("shedding_min_requests", 100)
+	v.SetDefault("shedding_eval_interval", 10*time.Second)
+	v.SetDefault("admission_enabled", false)
+	v.SetDefault("admission_max_in_flight", 0)
+	v.SetDefault("admission_max_queue_latency", 50*time.Millisecond)
+	v.SetDefault("admission_interval", 100*time.Millisecond)
 
-	// Read specific config file
-	v.SetConfigFile(configPath)
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("error reading config file %s: %w", configPath, err)
-	}
+	v.SetDefault("breaker_enabled", false)
+	v.SetDefault("breaker_failure_threshold", 5)
+	v.SetDefault("breaker_probe_interval", 5*time.Second)
+	v.SetDefault("inference_timeout", time.Duration(0))
+	v.SetDefault("cache_timeout", time.Duration(0))
+	v.SetDefault("adaptive_timeouts_enabled", false)
+	v.SetDefault("adaptive_timeout_window", 1000)
+	v.SetDefault("adaptive_timeout_percentile", 0.99)
+	v.SetDefault("adaptive_timeout_multiplier", 2.0)
+	v.SetDefault("inference_timeout_floor", 10*time.Millisecond)
+	v.SetDefault("inference_timeout_ceiling", 2*time.Second)
+	v.SetDefault("cache_timeout_floor", 2*time.Millisecond)
+	v.SetDefault("cache_timeout_ceiling", 100*time.Millisecond)
+	v.SetDefault("rate_limit_enabled", false)
+	v.SetDefault("rate_limit_rps", 100.0)
+	v.SetDefault("rate_limit_burst", 200)
+	v.SetDefault("rate_limit_peer_rps", 0.0)
+	v.SetDefault("rate_limit_peer_burst", 2000)
+	v.SetDefault("recorder_enabled", false)
+	v.SetDefault("recorder_stream", "policy-service:episodes")
+	v.SetDefault("recorder_stream_max_len", 1000000)
+	v.SetDefault("recorder_buffer_size", 4096)
+	v.SetDefault("recorder_dedup_window", 5*time.Minute)
+	v.SetDefault("recorder_compaction_enabled", false)
+	v.SetDefault("recorder_compaction_interval", time.Hour)
+	v.SetDefault("recorder_s3_endpoint", "")
+	v.SetDefault("recorder_s3_region", "us-east-1")
+	v.SetDefault("recorder_s3_bucket", "")
+	v.SetDefault("recorder_s3_prefix", "episodes")
+	v.SetDefault("publish_enabled", false)
+	v.SetDefault("publish_mode", "channel")
+	v.SetDefault("publish_target", "policy-service:plans")
+	v.SetDefault("publish_stream_max_len", 100000)
+	v.SetDefault("publish_buffer_size", 4096)
+	v.SetDefault("placement_enabled", false)
+	v.SetDefault("placement_idle", 5*time.Minute)
+	v.SetDefault("replica_id", "")
+	v.SetDefault("state_snapshot_enabled", false)
+	v.SetDefault("state_snapshot_interval", 10*time.Second)
+	v.SetDefault("state_snapshot_max_age", 5*time.Minute)
+	v.SetDefault("state_snapshot_key", "policy-service:state")
+	v.SetDefault("upstream_address", "")
+	v.SetDefault("upstream_tls", false)
+	v.SetDefault("upstream_tls_ca", "")
+	v.SetDefault("upstream_tls_cert", "")
+	v.SetDefault("upstream_tls_key", "")
+	v.SetDefault("upstream_tls_server_name", "")
+	v.SetDefault("upstream_timeout", 2*time.Second)
+	v.SetDefault("upstream_queue_threshold", 0)
+	v.SetDefault("upstream_max_hops", 3)
+	v.SetDefault("upstream_cache_ttl", 0)
+	v.SetDefault("journal_enabled", false)
+	v.SetDefault("journal_dir", "/var/lib/policy-service/journal")
+	v.SetDefault("journal_retention", 24*time.Hour)
+	v.SetDefault("journal_max_bytes", int64(1<<30))
+	v.SetDefault("journal_buffer_size", 4096)
+	v.SetDefault("telemetry_enabled", false)
+	v.SetDefault("telemetry_interval", 5*time.Second)
+	v.SetDefault("telemetry_active_window", time.Minute)
+}
 
+// unmarshal decodes the merged viper settings into a Config
+func unmarshal(v *viper.Viper) (*Config, error) {
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
-
 	return &cfg, nil
 }
 
@@ -119,14 +956,585 @@ func (c *Config) Validate() error {
 	if c.Port <= 0 || c.Port > 65535 {
 		return fmt.Errorf("invalid port: %d", c.Port)
 	}
-	if c.MetricsPort <= 0 || c.MetricsPort > 65535 {
+	if c.MetricsPort < 0 || c.MetricsPort > 65535 {
 		return fmt.Errorf("invalid metrics port: %d", c.MetricsPort)
 	}
-	if c.Port == c.MetricsPort {
+	if c.RedisCluster && c.RedisSentinelMaster != "" {
+		return fmt.Errorf("redis_cluster and redis_sentinel_master cannot be used together")
+	}
+	if c.RedisPassword != "" && c.RedisPasswordFile != "" {
+		return fmt.Errorf("redis_password and redis_password_file cannot both be set")
+	}
+	if c.RedisDB < 0 {
+		return fmt.Errorf("invalid redis_db: %d", c.RedisDB)
+	}
+	if c.RedisDB != 0 && c.RedisCluster {
+		return fmt.Errorf("redis_db must be 0 with redis_cluster")
+	}
+	if (c.RedisTLSCert == "") != (c.RedisTLSKey == "") {
+		return fmt.Errorf("redis_tls_cert and redis_tls_key must be set together")
+	}
+	if !c.RedisTLS && (c.RedisTLSCA != "" || c.RedisTLSCert != "" || c.RedisTLSServerName != "") {
+		return fmt.Errorf("redis_tls_ca, redis_tls_cert, and redis_tls_server_name require redis_tls")
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return fmt.Errorf("tls_cert and tls_key must be set together")
+	}
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		return fmt.Errorf("tls_client_ca requires tls_cert and tls_key")
+	}
+	if c.SPIFFEEndpointSocket != "" {
+		if c.TLSCert != "" {
+			return fmt.Errorf("spiffe_endpoint_socket and tls_cert cannot both be set")
+		}
+		if c.SPIFFETrustDomain == "" {
+			return fmt.Errorf("spiffe_endpoint_socket requires spiffe_trust_domain")
+		}
+	}
+	for _, provider := range c.AuthProviders {
+		switch provider {
+		case "mtls":
+			if c.TLSClientCA == "" {
+				return fmt.Errorf("auth provider mtls requires tls_client_ca")
+			}
+		case "spiffe":
+			if c.SPIFFEEndpointSocket == "" {
+				return fmt.Errorf("auth provider spiffe requires spiffe_endpoint_socket")
+			}
+		case "jwt":
+			if (c.AuthJWTPublicKey == "") == (c.AuthJWTJWKSURL == "") {
+				return fmt.Errorf("auth provider jwt requires exactly one of auth_jwt_public_key and auth_jwt_jwks_url")
+			}
+			if c.AuthJWTJWKSURL != "" && c.AuthJWTJWKSRefresh <= 0 {
+				return fmt.Errorf("auth_jwt_jwks_refresh must be positive")
+			}
+		case "api_key":
+			if len(c.AuthAPIKeys) == 0 {
+				return fmt.Errorf("auth provider api_key requires auth_api_keys")
+			}
+		case "robot_token":
+			if !c.RobotTokensEnabled {
+				return fmt.Errorf("auth provider robot_token requires robot_tokens_enabled")
+			}
+		default:
+			return fmt.Errorf("invalid auth_providers entry: %q", provider)
+		}
+	}
+	if c.RobotTokensEnabled {
+		if len(c.AuthProviders) == 0 {
+			return fmt.Errorf("robot tokens require auth_providers to authenticate issuers")
+		}
+		if c.RobotTokenKeyDir == "" || c.RobotTokenIssuer == "" {
+			return fmt.Errorf("robot_token_key_dir and robot_token_issuer are required when robot tokens are enabled")
+		}
+		if c.RobotTokenTTL <= 0 || c.RobotTokenMaxTTL < c.RobotTokenTTL {
+			return fmt.Errorf("robot_token_ttl must be positive and robot_token_max_ttl at least robot_token_ttl when robot tokens are enabled")
+		}
+		if len(c.RobotTokenIssuers) == 0 || slices.Contains(c.RobotTokenIssuers, "") {
+			return fmt.Errorf("robot_token_issuers must list the subjects allowed to issue robot tokens")
+		}
+	}
+	for subject, tenant := range c.AuthTenants {
+		if subject == "" || tenant == "" {
+			return fmt.Errorf("auth_tenants: entry %q needs a subject and a tenant", subject)
+		}
+	}
+	for i, r := range c.AuthSPIFFERoles {
+		if !strings.HasPrefix(r.ID, "spiffe://") || len(r.Roles) == 0 {
+			return fmt.Errorf("auth_spiffe_roles[%d]: needs a spiffe:// id and at least one role", i)
+		}
+	}
+	for _, provider := range c.ORTExecutionProviders {
+		switch provider {
+		case "cuda", "tensorrt", "cpu":
+		default:
+			return fmt.Errorf("invalid ort_execution_providers entry: %q", provider)
+		}
+	}
+	if c.ORTOptimizedModelDir != "" && !slices.Contains(c.ORTExecutionProviders, "tensorrt") {
+		return fmt.Errorf("ort_optimized_model_dir requires tensorrt in ort_execution_providers")
+	}
+	switch c.ModelQuantization {
+	case "", "qdq", "qoperator":
+	default:
+		return fmt.Errorf("invalid model_quantization: %q", c.ModelQuantization)
+	}
+	switch c.ModelInputType {
+	case "float32":
+	case "int8", "uint8":
+		if !(c.ModelInputScale > 0) {
+			return fmt.Errorf("model_input_scale must be positive for %s model inputs", c.ModelInputType)
+		}
+		lo, hi := int32(-128), int32(127)
+		if c.ModelInputType == "uint8" {
+			lo, hi = 0, 255
+		}
+		if c.ModelInputZeroPoint < lo || c.ModelInputZeroPoint > hi {
+			return fmt.Errorf("model_input_zero_point must be in [%d, %d] for %s model inputs", lo, hi, c.ModelInputType)
+		}
+	default:
+		return fmt.Errorf("invalid model_input_type: %q", c.ModelInputType)
+	}
+	if c.ORTCUDAMemLimit < 0 {
+		return fmt.Errorf("ort_cuda_mem_limit must not be negative")
+	}
+	if c.MaxTensorBytes < 0 {
+		return fmt.Errorf("max_tensor_bytes must not be negative")
+	}
+	if c.MaxBatchSize < 0 {
+		return fmt.Errorf("max_batch_size must not be negative")
+	}
+	if c.BatchValidationWorkers < 0 {
+		return fmt.Errorf("batch_validation_workers must not be negative")
+	}
+	if c.BatchValidationMinParallel < 1 {
+		return fmt.Errorf("batch_validation_min_parallel must be at least 1")
+	}
+	if c.ORTIntraOpThreads < 0 || c.ORTInterOpThreads < 0 {
+		return fmt.Errorf("ort_intra_op_threads and ort_inter_op_threads must not be negative")
+	}
+	if c.CPUAffinity != "" && c.CPUAffinityNUMANode >= 0 {
+		return fmt.Errorf("set only one of cpu_affinity and cpu_affinity_numa_node")
+	}
+	if c.CPUAffinity != "" {
+		if _, err := affinity.ParseCPUList(c.CPUAffinity); err != nil {
+			return fmt.Errorf("invalid cpu_affinity: %w", err)
+		}
+	}
+	switch c.ORTDeviceDispatch {
+	case "round_robin", "least_loaded":
+	default:
+		return fmt.Errorf("invalid ort_device_dispatch: %q", c.ORTDeviceDispatch)
+	}
+	if c.ORTSessionsPerDevice < 1 || c.ORTSessionsPerDevice > 64 {
+		return fmt.Errorf("ort_sessions_per_device must be between 1 and 64, got %d", c.ORTSessionsPerDevice)
+	}
+	seen := make(map[int]bool)
+	for _, device := range c.ORTDevices {
+		if device < 0 || seen[device] {
+			return fmt.Errorf("ort_devices must list distinct non-negative GPU indices, got %v", c.ORTDevices)
+		}
+		seen[device] = true
+	}
+	if c.OTELSampleRatio < 0 || c.OTELSampleRatio > 1 {
+		return fmt.Errorf("otel_sample_ratio must be between 0 and 1, got %v", c.OTELSampleRatio)
+	}
+	if c.Port == c.MetricsPort && c.MetricsPort != 0 {
 		return fmt.Errorf("port and metrics_port must be different")
 	}
+	switch c.MetricsBackend {
+	case "", "prometheus":
+	case "statsd":
+		if c.StatsDAddress == "" {
+			return fmt.Errorf("statsd_address is required when metrics_backend is statsd")
+		}
+	default:
+		return fmt.Errorf("invalid metrics_backend: %q", c.MetricsBackend)
+	}
 	if c.Model == "" && !c.UseMockInference {
 		return fmt.Errorf("model path is required when not using mock inference")
 	}
+	if c.ModelRegistryModel != "" && !c.UseMockInference {
+		if _, _, err := registry.ParseRef(c.ModelRegistryModel); err != nil {
+			return err
+		}
+		if c.ModelRegistryURL == "" || c.ModelRegistryArtifact == "" || c.ModelCacheDir == "" {
+			return fmt.Errorf("model_registry_url, model_registry_artifact, and model_cache_dir must be set when model_registry_model is")
+		}
+	} else if modelfetch.IsURL(c.Model) && !c.UseMockInference {
+		if c.ModelCacheDir == "" || c.ModelS3Region == "" {
+			return fmt.Errorf("model_cache_dir and model_s3_region must be set when the model is a URL")
+		}
+		if sum, err := hex.DecodeString(c.ModelSHA256); err != nil || len(sum) != sha256.Size {
+			return fmt.Errorf("model_sha256 must be the model's hex SHA-256 checksum when the model is a URL, got %q", c.ModelSHA256)
+		}
+	}
+	if len(c.ModelVersions) > 0 && c.ModelVersion == "" {
+		return fmt.Errorf("model_version must be set when model_versions is")
+	}
+	versions := map[string]bool{c.ModelVersion: true}
+	for i, v := range c.ModelVersions {
+		if v.Version == "" || versions[v.Version] {
+			return fmt.Errorf("model_versions[%d]: version must be set and differ from model_version and the other versions, got %q", i, v.Version)
+		}
+		versions[v.Version] = true
+		if v.Model == "" && !c.UseMockInference {
+			return fmt.Errorf("model_versions[%d]: model must be set", i)
+		}
+		if modelfetch.IsURL(v.Model) {
+			return fmt.Errorf("model_versions[%d]: model must be a local path, got URL %q", i, v.Model)
+		}
+	}
+	if c.FairScheduling && c.SchedulerSlots <= 0 {
+		return fmt.Errorf("scheduler_slots must be positive when fair_scheduling is enabled")
+	}
+	switch c.LogLevel {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("invalid log_level: %q", c.LogLevel)
+	}
+	switch c.LogFormat {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("invalid log_format: %q", c.LogFormat)
+	}
+	switch c.ActionMode {
+	case "", "continuous":
+	case "argmax", "sample":
+		if len(c.ActionTable) == 0 {
+			return fmt.Errorf("action_table is required when action_mode is %q", c.ActionMode)
+		}
+	default:
+		return fmt.Errorf("invalid action_mode: %q", c.ActionMode)
+	}
+	if c.ActionDim < 0 {
+		return fmt.Errorf("action_dim must not be negative")
+	}
+	for i, b := range c.ActionBounds {
+		if len(b) != 2 || b[0] > b[1] {
+			return fmt.Errorf("action_bounds[%d] must be [min, max] with min <= max, got %v", i, b)
+		}
+	}
+	if len(c.ActionBounds) > 1 && c.ActionDim > 0 && len(c.ActionBounds) != c.ActionDim {
+		return fmt.Errorf("action_bounds has %d entries; expected 1 or action_dim (%d)", len(c.ActionBounds), c.ActionDim)
+	}
+	switch c.NonFiniteOutputs {
+	case "", "reject":
+	case "substitute":
+		if len(c.NonFiniteSafeAction) == 0 {
+			return fmt.Errorf("non_finite_safe_action must be set when non_finite_outputs is substitute")
+		}
+		for i, v := range c.NonFiniteSafeAction {
+			if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				return fmt.Errorf("non_finite_safe_action[%d] must be finite, got %v", i, v)
+			}
+		}
+		if c.ActionDim > 0 && len(c.NonFiniteSafeAction) != c.ActionDim {
+			return fmt.Errorf("non_finite_safe_action has %d values; expected action_dim (%d)", len(c.NonFiniteSafeAction), c.ActionDim)
+		}
+	default:
+		return fmt.Errorf("invalid non_finite_outputs: %q", c.NonFiniteOutputs)
+	}
+	if c.SafetyEnabled {
+		if c.SafetyMode != "flag" && c.SafetyMode != "clamp" {
+			return fmt.Errorf("invalid safety_mode: %q", c.SafetyMode)
+		}
+		if len(c.SafetyLimits) == 0 {
+			return fmt.Errorf("safety_limits must be set when safety is enabled")
+		}
+		for i, l := range c.SafetyLimits {
+			if len(l) != 2 || l[0] > l[1] {
+				return fmt.Errorf("safety_limits[%d] must be [min, max] with min <= max, got %v", i, l)
+			}
+		}
+		if len(c.SafetyLimits) > 1 && c.ActionDim > 0 && len(c.SafetyLimits) != c.ActionDim {
+			return fmt.Errorf("safety_limits has %d entries; expected 1 or action_dim (%d)", len(c.SafetyLimits), c.ActionDim)
+		}
+	}
+	if len(c.ActionValueNames) > 0 && c.ActionDim > 0 && len(c.ActionValueNames) != c.ActionDim {
+		return fmt.Errorf("action_value_names has %d entries; expected action_dim (%d)", len(c.ActionValueNames), c.ActionDim)
+	}
+	if len(c.ActionLabels) > 0 && len(c.ActionLabels) != len(c.ActionTable) {
+		return fmt.Errorf("action_labels has %d entries; expected one per action_table row (%d)", len(c.ActionLabels), len(c.ActionTable))
+	}
+	if c.CacheLocalFallbackSize < 0 {
+		return fmt.Errorf("invalid cache_local_fallback_size: %d", c.CacheLocalFallbackSize)
+	}
+	if c.CacheEncryptionEnabled && c.CacheEncryptionKeyDir == "" {
+		return fmt.Errorf("cache_encryption_key_dir is required when cache encryption is enabled")
+	}
+	if c.CacheEncryptionEnabled && len(c.AuthProviders) == 0 {
+		return fmt.Errorf("cache encryption requires auth_providers so tenant keys are chosen by authenticated tenant")
+	}
+	if c.SigningEnabled && c.SigningKeyDir == "" {
+		return fmt.Errorf("signing_key_dir is required when signing is enabled")
+	}
+	switch c.EnrichmentSource {
+	case "", "redis":
+	case "feast":
+		if c.EnrichmentFeastURL == "" {
+			return fmt.Errorf("enrichment_feast_url is required when enrichment_source is feast")
+		}
+	default:
+		return fmt.Errorf("invalid enrichment_source: %q", c.EnrichmentSource)
+	}
+	if c.RolloutEnabled && c.RolloutInterval <= 0 {
+		return fmt.Errorf("rollout_interval must be positive when rollout is enabled")
+	}
+	if c.WatchdogEnabled && (c.WatchdogInterval <= 0 || c.WatchdogStallTimeout <= 0) {
+		return fmt.Errorf("watchdog_interval and watchdog_stall_timeout must be positive when the watchdog is enabled")
+	}
+	if c.CanaryEnabled && (c.CanaryInterval <= 0 || c.CanaryTimeout <= 0 || c.CanaryMaxLatency <= 0 || c.CanaryTolerance < 0) {
+		return fmt.Errorf("canary_interval, canary_timeout, and canary_max_latency must be positive and canary_tolerance not negative when the canary is enabled")
+	}
+	if c.DedupEnabled && c.DedupWindow <= 0 {
+		return fmt.Errorf("dedup_window must be positive when dedup is enabled")
+	}
+	if c.PlanCacheEnabled && c.PlanCacheTTL <= 0 {
+		return fmt.Errorf("plan_cache_ttl must be positive when the plan cache is enabled")
+	}
+	switch c.PoseMode {
+	case "":
+	case "observation", "response":
+		if c.PoseTTL <= 0 {
+			return fmt.Errorf("pose_ttl must be positive when pose_mode is set")
+		}
+	default:
+		return fmt.Errorf("invalid pose_mode: %q", c.PoseMode)
+	}
+	if c.DownsampleEnabled && (c.DownsampleFactor < 2 || c.DownsampleMinSize < 1) {
+		return fmt.Errorf("downsample_factor must be at least 2 and downsample_min_size positive when downsampling is enabled")
+	}
+	if c.TelemetryEnabled && (c.TelemetryInterval <= 0 || c.TelemetryActiveWindow <= 0) {
+		return fmt.Errorf("telemetry_interval and telemetry_active_window must be positive when telemetry is enabled")
+	}
+	if c.ModelWatch && c.ModelWatchSettle <= 0 {
+		return fmt.Errorf("model_watch_settle must be positive when model_watch is enabled")
+	}
+	if c.ModelKeepPreviousMaxBytes < 0 {
+		return fmt.Errorf("model_keep_previous_max_bytes must not be negative")
+	}
+	if c.WarmupInferences < 0 || c.WarmupInferences > 1000 {
+		return fmt.Errorf("warmup_inferences must be between 0 and 1000")
+	}
+	for _, b := range c.WarmupBatchSizes {
+		if b <= 0 || b > 1024 {
+			return fmt.Errorf("warmup_batch_sizes must be between 1 and 1024, got %d", b)
+		}
+	}
+	if c.BatchingEnabled && (c.BatchingWindow <= 0 || c.BatchingMaxBatch <= 0) {
+		return fmt.Errorf("batching_window and batching_max_batch must be positive when batching is enabled")
+	}
+	if c.StreamChunkSize <= 0 || c.StreamMaxConcurrentChunks <= 0 {
+		return fmt.Errorf("stream_chunk_size and stream_max_concurrent_chunks must be positive")
+	}
+	if c.PlanStreamMinInterval <= 0 || c.PlanStreamInterval < c.PlanStreamMinInterval {
+		return fmt.Errorf("plan_stream_min_interval must be positive and plan_stream_interval at least the minimum")
+	}
+	if c.AggregateBatchSize <= 0 || c.AggregateMaxRequests < c.AggregateBatchSize {
+		return fmt.Errorf("aggregate_batch_size must be positive and aggregate_max_requests at least the batch size")
+	}
+	if c.StreamIdleTimeout < 0 || c.GRPCKeepaliveTime < 0 || c.GRPCKeepaliveMinTime < 0 {
+		return fmt.Errorf("stream_idle_timeout, grpc_keepalive_time, and grpc_keepalive_min_time must not be negative")
+	}
+	if c.GRPCKeepaliveTimeout <= 0 {
+		return fmt.Errorf("grpc_keepalive_timeout must be positive")
+	}
+	if c.ClientHintsEnabled && (c.ClientHintsQueueTarget <= 0 || c.ClientHintsBaseInterval <= 0 || c.ClientHintsMaxInterval < c.ClientHintsBaseInterval) {
+		return fmt.Errorf("client_hints_queue_target and client_hints_base_interval must be positive and client_hints_max_interval at least the base interval when client hints are enabled")
+	}
+	if c.AsyncEnabled && (c.AsyncWorkers <= 0 || c.AsyncQueueSize <= 0 || c.AsyncResultTTL <= 0) {
+		return fmt.Errorf("async_workers, async_queue_size, and async_result_ttl must be positive when async is enabled")
+	}
+	if c.PlanUpdatesEnabled && (c.PlanUpdatesInterval <= 0 || c.PlanUpdatesMaxWait <= 0 || c.PlanUpdatesIdleTimeout < c.PlanUpdatesMaxWait) {
+		return fmt.Errorf("plan_updates_interval and plan_updates_max_wait must be positive and plan_updates_idle_timeout at least the max wait when plan updates are enabled")
+	}
+	if c.SheddingEnabled {
+		if c.SheddingSLOTarget <= 0 || c.SheddingSLOTarget >= 1 || c.SheddingBurnRate <= 0 {
+			return fmt.Errorf("shedding_slo_target must be between 0 and 1 and shedding_burn_rate positive when shedding is enabled")
+		}
+		if c.SheddingShortWindow <= 0 || c.SheddingLongWindow < c.SheddingShortWindow || c.SheddingEvalInterval <= 0 {
+			return fmt.Errorf("shedding_short_window and shedding_eval_interval must be positive and shedding_long_window at least the short window when shedding is enabled")
+		}
+	}
+	if c.AdmissionEnabled {
+		if c.AdmissionMaxInFlight < 0 || c.AdmissionMaxQueueLatency < 0 || c.AdmissionInterval <= 0 {
+			return fmt.Errorf("admission_max_in_flight and admission_max_queue_latency must not be negative and admission_interval must be positive when admission control is enabled")
+		}
+		if c.AdmissionMaxInFlight == 0 && c.AdmissionMaxQueueLatency == 0 {
+			return fmt.Errorf("admission control requires admission_max_in_flight or admission_max_queue_latency")
+		}
+	}
+	if c.BreakerEnabled && (c.BreakerFailureThreshold < 1 || c.BreakerProbeInterval <= 0) {
+		return fmt.Errorf("breaker_failure_threshold and breaker_probe_interval must be positive when the circuit breaker is enabled")
+	}
+	if c.InferenceTimeout < 0 || c.CacheTimeout < 0 {
+		return fmt.Errorf("inference_timeout and cache_timeout must not be negative")
+	}
+	if c.AdaptiveTimeoutsEnabled {
+		if c.AdaptiveTimeoutWindow < 10 || c.AdaptiveTimeoutPercentile <= 0 || c.AdaptiveTimeoutPercentile > 1 || c.AdaptiveTimeoutMultiplier < 1 {
+			return fmt.Errorf("adaptive timeouts need adaptive_timeout_window of at least 10, adaptive_timeout_percentile in (0, 1], and adaptive_timeout_multiplier of at least 1")
+		}
+		if c.InferenceTimeoutFloor <= 0 || c.InferenceTimeoutCeiling < c.InferenceTimeoutFloor ||
+			c.CacheTimeoutFloor <= 0 || c.CacheTimeoutCeiling < c.CacheTimeoutFloor {
+			return fmt.Errorf("adaptive timeout floors must be positive and ceilings at least their floors")
+		}
+	}
+	if c.RateLimitEnabled {
+		if c.RateLimitRPS < 0 || c.RateLimitBurst < 1 {
+			return fmt.Errorf("rate_limit_rps must not be negative and rate_limit_burst must be positive when rate limiting is enabled")
+		}
+		for i, m := range c.RateLimitMethods {
+			if !strings.HasPrefix(m.Method, "/") || m.RPS < 0 || (m.RPS > 0 && m.Burst < 1) {
+				return fmt.Errorf("rate_limit_methods[%d]: needs a full method name, a non-negative rps, and a positive burst", i)
+			}
+		}
+		if c.RateLimitPeerRPS < 0 || (c.RateLimitPeerRPS > 0 && c.RateLimitPeerBurst < 1) {
+			return fmt.Errorf("rate_limit_peer_rps must not be negative and rate_limit_peer_burst must be positive when limiting peers")
+		}
+	}
+	if c.JournalEnabled && (c.JournalDir == "" || c.JournalRetention <= 0 || c.JournalMaxBytes <= 0 || c.JournalBufferSize <= 0) {
+		return fmt.Errorf("journal_dir must be set and journal_retention, journal_max_bytes, and journal_buffer_size positive when the journal is enabled")
+	}
+	if c.RecorderEnabled {
+		if c.RecorderStream == "" || c.RecorderBufferSize <= 0 || c.RecorderStreamMaxLen < 0 || c.RecorderDedupWindow < 0 {
+			return fmt.Errorf("recorder_stream must be set, recorder_buffer_size positive, and recorder_stream_max_len and recorder_dedup_window not negative when the recorder is enabled")
+		}
+		if c.RecorderCompactionEnabled && (c.RecorderCompactionInterval <= 0 || c.RecorderS3Bucket == "" || c.RecorderS3Region == "") {
+			return fmt.Errorf("recorder_compaction_interval must be positive and recorder_s3_bucket and recorder_s3_region set when compaction is enabled")
+		}
+	}
+	if c.PublishEnabled {
+		if c.PublishMode != "channel" && c.PublishMode != "stream" {
+			return fmt.Errorf("invalid publish_mode: %q (must be channel or stream)", c.PublishMode)
+		}
+		if c.PublishTarget == "" || c.PublishBufferSize <= 0 || c.PublishStreamMaxLen < 0 {
+			return fmt.Errorf("publish_target must be set, publish_buffer_size positive, and publish_stream_max_len not negative when publishing is enabled")
+		}
+	}
+	if c.PlacementEnabled && c.PlacementIdle <= 0 {
+		return fmt.Errorf("placement_idle must be positive when placement is enabled")
+	}
+	if c.StateSnapshotEnabled && (c.StateSnapshotInterval <= 0 || c.StateSnapshotMaxAge < c.StateSnapshotInterval || c.StateSnapshotKey == "") {
+		return fmt.Errorf("state_snapshot_interval must be positive, state_snapshot_max_age at least the interval, and state_snapshot_key set when state snapshots are enabled")
+	}
+	if c.UpstreamAddress != "" {
+		if c.UpstreamTimeout <= 0 || c.UpstreamMaxHops <= 0 {
+			return fmt.Errorf("upstream_timeout and upstream_max_hops must be positive when upstream_address is set")
+		}
+		if c.UpstreamQueueThreshold < 0 || c.UpstreamCacheTTL < 0 {
+			return fmt.Errorf("upstream_queue_threshold and upstream_cache_ttl must not be negative")
+		}
+	}
+	if (c.UpstreamTLSCert == "") != (c.UpstreamTLSKey == "") {
+		return fmt.Errorf("upstream_tls_cert and upstream_tls_key must be set together")
+	}
+	if !c.UpstreamTLS && (c.UpstreamTLSCA != "" || c.UpstreamTLSCert != "" || c.UpstreamTLSServerName != "") {
+		return fmt.Errorf("upstream_tls_ca, upstream_tls_cert, and upstream_tls_server_name require upstream_tls")
+	}
+	uint8Norm := observation.Normalization{Scale: c.Uint8Scale, Mean: c.Uint8Mean, Std: c.Uint8Std}
+	if err := uint8Norm.Validate(); err != nil {
+		return fmt.Errorf("invalid uint8 normalization: %w", err)
+	}
+	if len(c.Uint8Mean) > 1 && len(c.Uint8Std) > 1 && len(c.Uint8Mean) != len(c.Uint8Std) {
+		return fmt.Errorf("uint8_mean and uint8_std must have the same number of channels")
+	}
+	for i, r := range c.InputRanges {
+		if r.Max <= r.Min || r.Tolerance < 0 {
+			return fmt.Errorf("input_ranges[%d]: max must exceed min and tolerance must not be negative", i)
+		}
+		if r.Layout != "" {
+			if _, _, _, err := observation.ParseLayout(r.Layout); err != nil {
+				return fmt.Errorf("input_ranges[%d]: %w", i, err)
+			}
+		}
+		switch r.Action {
+		case "", "reject", "clamp":
+		default:
+			return fmt.Errorf("input_ranges[%d]: invalid action: %q", i, r.Action)
+		}
+	}
+	for i, p := range c.Preprocess {
+		if p.Layout != "" {
+			if _, _, _, err := observation.ParseLayout(p.Layout); err != nil {
+				return fmt.Errorf("preprocess[%d]: %w", i, err)
+			}
+		}
+		if len(p.Steps) == 0 {
+			return fmt.Errorf("preprocess[%d]: needs at least one step", i)
+		}
+		for j, step := range p.Steps {
+			if err := step.validate(); err != nil {
+				return fmt.Errorf("preprocess[%d].steps[%d]: %w", i, j, err)
+			}
+		}
+	}
+	for i, t := range c.ModelThresholds {
+		if t.MaxLatency < 0 || t.MaxDivergence < 0 {
+			return fmt.Errorf("model_thresholds[%d]: max_latency and max_divergence must not be negative", i)
+		}
+	}
+	if err := checkValidity("action_validity", c.ActionValidity); err != nil {
+		return err
+	}
+	for i, r := range c.ActionValidityRules {
+		if err := checkValidity(fmt.Sprintf("action_validity_rules[%d]: validity", i), r.Validity); err != nil {
+			return err
+		}
+		if strings.Contains(strings.TrimSuffix(r.Client, "/*"), "*") {
+			return fmt.Errorf("action_validity_rules[%d]: client may only end in \"/*\", got %q", i, r.Client)
+		}
+	}
+	names := make(map[string]bool, len(c.PlanSchedules))
+	for i, s := range c.PlanSchedules {
+		if s.Name == "" || names[s.Name] {
+			return fmt.Errorf("plan_schedules[%d]: name must be set and unique, got %q", i, s.Name)
+		}
+		names[s.Name] = true
+		if len(s.RobotIDs) == 0 || s.Interval <= 0 || s.Target == "" || s.StreamMaxLen < 0 {
+			return fmt.Errorf("plan_schedules[%d]: robot_ids and target must be set, interval positive, and stream_max_len not negative", i)
+		}
+		if s.Mode != "" && s.Mode != "channel" && s.Mode != "stream" {
+			return fmt.Errorf("plan_schedules[%d]: invalid mode: %q (must be channel or stream)", i, s.Mode)
+		}
+	}
+	if c.CommitEnabled {
+		if c.CommitVetoWindow <= 0 || c.CommitTTL <= c.CommitVetoWindow {
+			return fmt.Errorf("commit_veto_window must be positive and shorter than commit_ttl when two-phase commit is enabled")
+		}
+		for i, client := range c.CommitClients {
+			if strings.Contains(strings.TrimSuffix(client, "/*"), "*") {
+				return fmt.Errorf("commit_clients[%d]: may only end in \"/*\", got %q", i, client)
+			}
+		}
+	}
+	for tenant, weight := range c.TenantWeights {
+		if weight <= 0 {
+			return fmt.Errorf("invalid weight for tenant %q: %v", tenant, weight)
+		}
+	}
+	return nil
+}
+
+// checkValidity rejects action validities that are negative or too short to
+// report in whole milliseconds
+func checkValidity(name string, d time.Duration) error {
+	if d < 0 || (d > 0 && d < time.Millisecond) {
+		return fmt.Errorf("%s must be 0 or at least 1ms, got %s", name, d)
+	}
+	return nil
+}
+
+// validate checks the fields of a preprocessing step's op
+func (s PreprocessStep) validate() error {
+	switch s.Op {
+	case "normalize":
+		for _, std := range s.Std {
+			if !(std > 0) {
+				return fmt.Errorf("normalize std must be positive, got %g", std)
+			}
+		}
+		if len(s.Mean) > 1 && len(s.Std) > 1 && len(s.Mean) != len(s.Std) {
+			return fmt.Errorf("normalize mean and std must have the same number of channels")
+		}
+	case "resize", "crop":
+		if s.Height <= 0 || s.Width <= 0 {
+			return fmt.Errorf("%s needs a positive height and width", s.Op)
+		}
+		if s.Op == "crop" && s.Center && (s.X != 0 || s.Y != 0) {
+			return fmt.Errorf("crop takes either center or x and y")
+		}
+	case "channel_order":
+		if len(s.Order) == 0 {
+			return fmt.Errorf("channel_order needs an order")
+		}
+		for _, c := range s.Order {
+			if c < 0 {
+				return fmt.Errorf("channel_order entries must not be negative, got %d", c)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid op: %q", s.Op)
+	}
 	return nil
 }
@@ -0,0 +1,132 @@
+// internal/config/diff.go
+package config
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Masked replaces the value of secret keys in settings and diffs
+const Masked = "********"
+
+// Change is a key whose effective value differs from its default
+type Change struct {
+	Key     string `json:"key"`
+	Default any    `json:"default"`
+	Value   any    `json:"value"`
+}
+
+// Defaults returns the configuration with every key at its default
+func Defaults() *Config {
+	v := viper.New()
+	setDefaults(v)
+	cfg, err := unmarshal(v)
+	if err != nil {
+		// The defaults are fixed, so this is a programming error
+		panic(err)
+	}
+	return cfg
+}
+
+// Settings returns the effective value of every key in its config file form,
+// durations as strings, with secret keys masked
+func (c *Config) Settings() map[string]any {
+	settings := make(map[string]any)
+	eachKey(c, func(key string, field reflect.StructField, value reflect.Value) {
+		settings[key] = settingValue(field, value)
+	})
+	return settings
+}
+
+// Diff returns the keys whose effective value differs from the default,
+// sorted by key, with secret keys masked
+func (c *Config) Diff() []Change {
+	defaults := reflect.ValueOf(Defaults()).Elem()
+	var changes []Change
+	eachKey(c, func(key string, field reflect.StructField, value reflect.Value) {
+		def := defaults.FieldByIndex(field.Index)
+		if sameValue(value, def) {
+			return
+		}
+		changes = append(changes, Change{
+			Key:     key,
+			Default: settingValue(field, def),
+			Value:   settingValue(field, value),
+		})
+	})
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// eachKey calls f with each config key of c and its field and value
+func eachKey(c *Config, f func(key string, field reflect.StructField, value reflect.Value)) {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if key := field.Tag.Get("mapstructure"); key != "" {
+			f(key, field, v.Field(i))
+		}
+	}
+}
+
+// sameValue reports whether two values of a key are equal, treating nil and
+// empty lists and maps alike
+func sameValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// settingValue returns a key's value in its config file form, masked when the
+// field is tagged secret and set
+func settingValue(field reflect.StructField, v reflect.Value) any {
+	if field.Tag.Get("secret") == "true" && !v.IsZero() {
+		return Masked
+	}
+	return plainValue(v)
+}
+
+// plainValue converts v to its config file form: durations as strings and
+// structs keyed by their mapstructure tags
+func plainValue(v reflect.Value) any {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return []any{}
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = plainValue(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = plainValue(iter.Value())
+		}
+		return m
+	case reflect.Struct:
+		m := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if key := t.Field(i).Tag.Get("mapstructure"); key != "" {
+				m[key] = plainValue(v.Field(i))
+			}
+		}
+		return m
+	}
+	return v.Interface()
+}
@@ -0,0 +1,54 @@
+// internal/config/diff_test.go
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDiff_ChangedKeysWithSecretsMasked(t *testing.T) {
+	cfg := Defaults()
+	if changes := cfg.Diff(); len(changes) != 0 {
+		t.Fatalf("Expected no changes for the defaults, got %v", changes)
+	}
+
+	cfg.Port = 6000
+	cfg.DedupWindow = 2 * time.Second
+	cfg.AdminToken = "s3cret"
+	cfg.AuthAPIKeys = map[string]string{"robot": "abc123"}
+	cfg.ORTExecutionProviders = []string{"cuda", "cpu"}
+
+	changes := cfg.Diff()
+	byKey := make(map[string]Change)
+	for _, c := range changes {
+		byKey[c.Key] = c
+	}
+	if len(changes) != 5 || changes[0].Key != "admin_token" {
+		t.Fatalf("Expected 5 changes sorted by key, got %v", changes)
+	}
+	if c := byKey["port"]; c.Value != 6000 || c.Default != 50051 {
+		t.Errorf("Unexpected port change: %+v", c)
+	}
+	if c := byKey["dedup_window"]; c.Value != "2s" || c.Default != "500ms" {
+		t.Errorf("Expected durations as strings, got %+v", c)
+	}
+	if c := byKey["admin_token"]; c.Value != Masked || c.Default != "" {
+		t.Errorf("Expected the admin token masked, got %+v", c)
+	}
+	if c := byKey["auth_api_keys"]; c.Value != Masked {
+		t.Errorf("Expected the API keys masked, got %+v", c)
+	}
+
+	// Settings mask secrets too, and encode as JSON for the admin endpoint
+	data, err := json.Marshal(cfg.Settings())
+	if err != nil {
+		t.Fatalf("Failed to marshal settings: %v", err)
+	}
+	var settings map[string]any
+	json.Unmarshal(data, &settings)
+	if settings["admin_token"] != Masked || settings["redis_password"] != "" || settings["port"] != 6000.0 {
+		t.Errorf("Unexpected settings: admin_token=%v redis_password=%v port=%v",
+			settings["admin_token"], settings["redis_password"], settings["port"])
+	}
+}
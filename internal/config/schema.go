@@ -0,0 +1,150 @@
+// internal/config/schema.go
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// durationPattern matches Go duration strings such as "500ms" or "1h30m"
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Schema returns a JSON schema of the config file generated from Config: each
+// key's type, its default, and the constraints from its schema tag. Schema tag
+// constraints on lists and maps apply to their elements.
+func Schema() map[string]any {
+	defaults := viper.New()
+	setDefaults(defaults)
+
+	schema := objectSchema(reflect.TypeOf(Config{}), defaults)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "policy-service configuration"
+	return schema
+}
+
+// UnknownKeys returns the top-level keys in a config file that no Config
+// field reads, which are usually typos
+func UnknownKeys(configPath string) ([]string, error) {
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", configPath, err)
+	}
+
+	known := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		known[t.Field(i).Tag.Get("mapstructure")] = true
+	}
+
+	var unknown []string
+	for key := range v.AllSettings() {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// objectSchema describes a struct with mapstructure tags; defaults may be nil
+func objectSchema(t reflect.Type, defaults *viper.Viper) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+
+		prop := typeSchema(field.Type)
+		if tag := field.Tag.Get("schema"); tag != "" {
+			target := prop
+			switch field.Type.Kind() {
+			case reflect.Slice:
+				target = prop["items"].(map[string]any)
+			case reflect.Map:
+				target = prop["additionalProperties"].(map[string]any)
+			}
+			applyConstraints(target, tag)
+		}
+		if defaults != nil && defaults.IsSet(key) {
+			prop["default"] = defaultValue(field.Type, defaults.Get(key))
+		}
+		properties[key] = prop
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// typeSchema maps a Go type to its JSON schema type
+func typeSchema(t reflect.Type) map[string]any {
+	if t == durationType {
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t, nil)
+	}
+	panic(fmt.Sprintf("config: no JSON schema type for %s", t))
+}
+
+// applyConstraints adds a schema tag's comma-separated constraints, e.g.
+// "minimum=1,maximum=65535" or "enum=argmax|sample", to prop
+func applyConstraints(prop map[string]any, tag string) {
+	for _, constraint := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(constraint, "=")
+		switch name {
+		case "enum":
+			prop["enum"] = strings.Split(value, "|")
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				panic(fmt.Sprintf("config: invalid schema constraint %q", constraint))
+			}
+			prop[name] = n
+		default:
+			panic(fmt.Sprintf("config: unknown schema constraint %q", constraint))
+		}
+	}
+}
+
+// defaultValue converts a viper default to its JSON form
+func defaultValue(t reflect.Type, value any) any {
+	if t == durationType {
+		switch d := value.(type) {
+		case time.Duration:
+			return d.String()
+		case int:
+			return time.Duration(d).String()
+		}
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+// internal/config/schema_test.go
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchema_TypesDefaultsAndConstraints(t *testing.T) {
+	// Round-trip through JSON as the schema command prints it
+	data, err := json.Marshal(Schema())
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+
+	port := schema.Properties["port"]
+	if port["type"] != "integer" || port["default"] != 50051.0 || port["maximum"] != 65535.0 {
+		t.Errorf("Unexpected port schema: %v", port)
+	}
+	if window := schema.Properties["dedup_window"]; window["type"] != "string" || window["default"] != "500ms" {
+		t.Errorf("Expected dedup_window as a duration string defaulting to 500ms, got %v", window)
+	}
+	if stage := schema.Properties["rollout_stage_duration"]; stage["default"] != "0s" {
+		t.Errorf("Expected rollout_stage_duration to default to 0s, got %v", stage["default"])
+	}
+
+	providers := schema.Properties["ort_execution_providers"]
+	items, _ := providers["items"].(map[string]any)
+	if providers["type"] != "array" || len(items["enum"].([]any)) != 3 {
+		t.Errorf("Expected an enum of providers, got %v", providers)
+	}
+
+	ranges := schema.Properties["input_ranges"]["items"].(map[string]any)
+	action := ranges["properties"].(map[string]any)["action"].(map[string]any)
+	if len(action["enum"].([]any)) != 3 {
+		t.Errorf("Expected input_ranges action enum, got %v", action)
+	}
+}
+
+func TestUnknownKeys_ExampleConfig(t *testing.T) {
+	// The example config must only use keys Config reads
+	unknown, err := UnknownKeys("../../config.yaml")
+	if err != nil {
+		t.Fatalf("UnknownKeys failed: %v", err)
+	}
+	if len(unknown) != 0 {
+		t.Errorf("Expected no unknown keys in config.yaml, got %v", unknown)
+	}
+}
@@ -0,0 +1,149 @@
+// Package dedup remembers each robot's last observation and action so an
+// identical observation re-sent within a short window (a retry or sensor
+// stutter) can be answered without running inference again
+package dedup
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+// minSweep is the entry count below which expired entries are not swept
+const minSweep = 1024
+
+// robot identifies a robot across tenants, which number their robots
+// independently
+type robot struct {
+	tenant string
+	id     uint64
+}
+
+// entry is a robot's last computed result
+type entry struct {
+	obsHash string
+	action  []float32
+	at      time.Time
+}
+
+// Window is a per-robot last-result cache with a fixed expiry
+type Window struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	last    map[robot]entry
+	sweepAt int
+}
+
+// New creates a Window that reuses results for ttl after they were computed
+func New(ttl time.Duration) *Window {
+	return &Window{
+		ttl:     ttl,
+		now:     time.Now,
+		last:    make(map[robot]entry),
+		sweepAt: minSweep,
+	}
+}
+
+// Lookup returns the action last computed for tenant's robotID if it was for
+// the same observation and is still within the window
+func (w *Window) Lookup(tenant string, robotID uint64, obsHash string) ([]float32, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e, ok := w.last[robot{tenant: tenant, id: robotID}]
+	if !ok || e.obsHash != obsHash || w.now().Sub(e.at) > w.ttl {
+		return nil, false
+	}
+	return append([]float32(nil), e.action...), true
+}
+
+// Store records the action computed for the observation of tenant's robotID
+func (w *Window) Store(tenant string, robotID uint64, obsHash string, action []float32) {
+	now := w.now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.last[robot{tenant: tenant, id: robotID}] = entry{
+		obsHash: obsHash,
+		action:  append([]float32(nil), action...),
+		at:      now,
+	}
+
+	// Drop robots that went quiet so the map tracks the active fleet
+	if len(w.last) >= w.sweepAt {
+		for r, e := range w.last {
+			if now.Sub(e.at) > w.ttl {
+				delete(w.last, r)
+			}
+		}
+		w.sweepAt = max(minSweep, 2*len(w.last))
+	}
+}
+
+// Forget drops the results remembered for robotIDs of any tenant, e.g. when
+// their stream died
+func (w *Window) Forget(robotIDs []uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for r := range w.last {
+		if slices.Contains(robotIDs, r.id) {
+			delete(w.last, r)
+		}
+	}
+}
+
+// Len returns the number of robots currently tracked
+func (w *Window) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.last)
+}
+
+// snapshotEntry is an entry as saved by Snapshot
+type snapshotEntry struct {
+	Tenant  string    `json:"tenant,omitempty"`
+	RobotID uint64    `json:"robot_id"`
+	ObsHash string    `json:"obs_hash"`
+	Action  []float32 `json:"action"`
+	At      time.Time `json:"at"`
+}
+
+// Name identifies the window's state in state snapshots
+func (w *Window) Name() string { return "dedup" }
+
+// Snapshot encodes the results still within the window
+func (w *Window) Snapshot() ([]byte, error) {
+	now := w.now()
+
+	w.mu.Lock()
+	entries := make([]snapshotEntry, 0, len(w.last))
+	for r, e := range w.last {
+		if now.Sub(e.at) <= w.ttl {
+			entries = append(entries, snapshotEntry{Tenant: r.tenant, RobotID: r.id, ObsHash: e.obsHash, Action: e.action, At: e.at})
+		}
+	}
+	w.mu.Unlock()
+
+	return json.Marshal(entries)
+}
+
+// Restore loads results saved by Snapshot. They keep the time they were
+// computed, so results that expired while the service was down are not
+// reused.
+func (w *Window) Restore(data []byte) error {
+	var entries []snapshotEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, e := range entries {
+		w.last[robot{tenant: e.Tenant, id: e.RobotID}] = entry{obsHash: e.ObsHash, action: e.Action, at: e.At}
+	}
+	return nil
+}
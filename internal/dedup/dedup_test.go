@@ -0,0 +1,114 @@
+// internal/dedup/dedup_test.go
+package dedup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindow_Lookup(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	w := New(500 * time.Millisecond)
+	w.now = func() time.Time { return now }
+
+	if _, ok := w.Lookup("acme", 1, "abc"); ok {
+		t.Fatal("Expected miss on empty window")
+	}
+
+	w.Store("acme", 1, "abc", []float32{0.5, -0.5})
+
+	action, ok := w.Lookup("acme", 1, "abc")
+	if !ok || len(action) != 2 || action[0] != 0.5 {
+		t.Fatalf("Expected hit with stored action, got %v (hit %v)", action, ok)
+	}
+
+	// Returned actions are copies
+	action[0] = 9
+	if again, _ := w.Lookup("acme", 1, "abc"); again[0] != 0.5 {
+		t.Errorf("Expected stored action to be unaffected, got %v", again)
+	}
+
+	if _, ok := w.Lookup("acme", 1, "def"); ok {
+		t.Error("Expected miss for a different observation")
+	}
+	if _, ok := w.Lookup("acme", 2, "abc"); ok {
+		t.Error("Expected miss for a different robot")
+	}
+	if _, ok := w.Lookup("globex", 1, "abc"); ok {
+		t.Error("Expected miss for the same robot id of another tenant")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := w.Lookup("acme", 1, "abc"); ok {
+		t.Error("Expected miss after the window expired")
+	}
+}
+
+func TestWindow_SweepsExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	w := New(time.Second)
+	w.now = func() time.Time { return now }
+
+	for i := 0; i < minSweep-1; i++ {
+		w.Store("acme", uint64(i), "abc", []float32{1})
+	}
+	now = now.Add(2 * time.Second)
+	w.Store("acme", minSweep, "abc", []float32{1})
+
+	if w.Len() != 1 {
+		t.Errorf("Expected expired robots to be swept, got %d entries", w.Len())
+	}
+}
+
+func TestWindow_Forget(t *testing.T) {
+	w := New(time.Minute)
+	w.Store("acme", 1, "abc", []float32{0.5})
+	w.Store("acme", 2, "def", []float32{0.5})
+	w.Store("globex", 1, "abc", []float32{0.5})
+
+	w.Forget([]uint64{1, 3})
+	if _, ok := w.Lookup("acme", 1, "abc"); ok {
+		t.Error("Expected a forgotten robot to miss")
+	}
+	if _, ok := w.Lookup("globex", 1, "abc"); ok {
+		t.Error("Expected a forgotten robot id to miss for every tenant")
+	}
+	if _, ok := w.Lookup("acme", 2, "def"); !ok || w.Len() != 1 {
+		t.Errorf("Expected other robots to be kept, got %d tracked", w.Len())
+	}
+}
+
+func TestWindow_SnapshotRestore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	w := New(time.Second)
+	w.now = func() time.Time { return now }
+	w.Store("acme", 1, "abc", []float32{0.5})
+	now = now.Add(800 * time.Millisecond)
+	w.Store("acme", 2, "def", []float32{0.25})
+	now = now.Add(400 * time.Millisecond)
+
+	data, err := w.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	restored := New(time.Second)
+	restored.now = func() time.Time { return now }
+	if err := restored.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	// Robot 1's result had already expired and is not saved
+	if restored.Len() != 1 {
+		t.Fatalf("Expected 1 restored robot, got %d", restored.Len())
+	}
+	if action, ok := restored.Lookup("acme", 2, "def"); !ok || action[0] != 0.25 {
+		t.Errorf("Expected restored action 0.25, got %v (hit %v)", action, ok)
+	}
+
+	// Restored results keep their original time
+	now = now.Add(time.Second)
+	if _, ok := restored.Lookup("acme", 2, "def"); ok {
+		t.Error("Expected restored result to expire with its original time")
+	}
+}
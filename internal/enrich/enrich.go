@@ -0,0 +1,117 @@
+// Package enrich appends per-robot static features (payload type, map zone
+// embeddings, ...) from a feature store to state-vector observations, so the
+// model sees the same features at serving time as during training
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Feature is one feature appended to the observation, in configured order
+type Feature struct {
+	// Name identifies the feature in the source (Redis hash field or Feast feature reference)
+	Name string
+	// Dim is the number of values the feature contributes
+	Dim int
+	// Default is used when the feature is missing for a robot; zeros if empty
+	Default []float32
+}
+
+// Source fetches raw feature values for a set of robots
+type Source interface {
+	// Fetch returns, for each robot in order, the values found per feature name.
+	// Features missing for a robot are omitted from its map.
+	Fetch(ctx context.Context, robotIDs []uint64, features []Feature) ([]map[string][]float32, error)
+	// Name identifies the source in metrics
+	Name() string
+}
+
+// ErrUnavailable is returned when features cannot be fetched and enrichment is required
+var ErrUnavailable = errors.New("feature store unavailable")
+
+// Enricher builds the feature vector appended to each observation
+type Enricher struct {
+	source   Source
+	features []Feature
+	timeout  time.Duration
+	required bool
+	dim      int
+}
+
+// New creates an Enricher. When required is false, fetch failures and missing
+// features fall back to defaults instead of failing the request.
+func New(source Source, features []Feature, timeout time.Duration, required bool) (*Enricher, error) {
+	dim := 0
+	for _, f := range features {
+		if f.Dim <= 0 {
+			return nil, fmt.Errorf("feature %q must have a positive dim", f.Name)
+		}
+		if len(f.Default) != 0 && len(f.Default) != f.Dim {
+			return nil, fmt.Errorf("feature %q default has %d values, expected %d", f.Name, len(f.Default), f.Dim)
+		}
+		dim += f.Dim
+	}
+	if dim == 0 {
+		return nil, fmt.Errorf("at least one feature is required")
+	}
+
+	return &Enricher{
+		source:   source,
+		features: features,
+		timeout:  timeout,
+		required: required,
+		dim:      dim,
+	}, nil
+}
+
+// Dim returns the number of values appended to each observation
+func (e *Enricher) Dim() int {
+	return e.dim
+}
+
+// Enrich returns the feature vector for each robot, in order
+func (e *Enricher) Enrich(ctx context.Context, robotIDs []uint64) ([][]float32, error) {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	found, err := e.source.Fetch(ctx, robotIDs, e.features)
+	metrics.RecordEnrichmentFetch(e.source.Name(), time.Since(start).Seconds(), err == nil)
+	if err != nil {
+		if e.required {
+			return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		found = make([]map[string][]float32, len(robotIDs))
+	}
+
+	vectors := make([][]float32, len(robotIDs))
+	for i, robotID := range robotIDs {
+		vec := make([]float32, 0, e.dim)
+		for _, f := range e.features {
+			// Values of the wrong size are treated as missing
+			values, ok := found[i][f.Name]
+			ok = ok && len(values) == f.Dim
+			if !ok {
+				metrics.RecordEnrichmentMissing(f.Name)
+				if e.required {
+					return nil, fmt.Errorf("%w: feature %q missing for robot %d", ErrUnavailable, f.Name, robotID)
+				}
+				values = f.Default
+				if len(values) == 0 {
+					values = make([]float32, f.Dim)
+				}
+			}
+			vec = append(vec, values...)
+		}
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
@@ -0,0 +1,132 @@
+// internal/enrich/enrich_test.go
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// staticSource returns fixed features per robot ID
+type staticSource struct {
+	features map[uint64]map[string][]float32
+	err      error
+}
+
+func (s staticSource) Name() string { return "static" }
+
+func (s staticSource) Fetch(ctx context.Context, robotIDs []uint64, features []Feature) ([]map[string][]float32, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	found := make([]map[string][]float32, len(robotIDs))
+	for i, id := range robotIDs {
+		found[i] = s.features[id]
+	}
+	return found, nil
+}
+
+var testFeatures = []Feature{
+	{Name: "payload_type", Dim: 1, Default: []float32{-1}},
+	{Name: "zone_embedding", Dim: 2},
+}
+
+func TestEnrich_AppendsFeaturesInOrder(t *testing.T) {
+	source := staticSource{features: map[uint64]map[string][]float32{
+		1: {"payload_type": {3}, "zone_embedding": {0.5, 0.25}},
+		2: {"zone_embedding": {1, 2, 3}}, // wrong size counts as missing
+	}}
+	e, err := New(source, testFeatures, 0, false)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if e.Dim() != 3 {
+		t.Fatalf("Expected dim 3, got %d", e.Dim())
+	}
+
+	vectors, err := e.Enrich(context.Background(), []uint64{1, 2})
+	if err != nil {
+		t.Fatalf("Enrich failed: %v", err)
+	}
+
+	want := [][]float32{{3, 0.5, 0.25}, {-1, 0, 0}}
+	if !reflect.DeepEqual(vectors, want) {
+		t.Errorf("Expected %v, got %v", want, vectors)
+	}
+}
+
+func TestEnrich_Required(t *testing.T) {
+	source := staticSource{features: map[uint64]map[string][]float32{
+		1: {"payload_type": {3}},
+	}}
+	e, err := New(source, testFeatures, 0, true)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := e.Enrich(context.Background(), []uint64{1}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable for missing feature, got %v", err)
+	}
+}
+
+func TestEnrich_FetchErrorFallsBackToDefaults(t *testing.T) {
+	e, err := New(staticSource{err: errors.New("connection refused")}, testFeatures, 0, false)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	vectors, err := e.Enrich(context.Background(), []uint64{7})
+	if err != nil {
+		t.Fatalf("Enrich failed: %v", err)
+	}
+	if want := [][]float32{{-1, 0, 0}}; !reflect.DeepEqual(vectors, want) {
+		t.Errorf("Expected %v, got %v", want, vectors)
+	}
+}
+
+func TestFeastSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/get-online-features" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		var req struct {
+			Features []string            `json:"features"`
+			Entities map[string][]uint64 `json:"entities"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !reflect.DeepEqual(req.Entities["robot_id"], []uint64{1, 2}) {
+			t.Errorf("Unexpected entities %v", req.Entities)
+		}
+
+		w.Write([]byte(`{
+			"metadata": {"feature_names": ["robot_id", "payload_type", "zone_embedding"]},
+			"results": [
+				{"values": [1, 2], "statuses": ["PRESENT", "PRESENT"]},
+				{"values": [4, null], "statuses": ["PRESENT", "NOT_FOUND"]},
+				{"values": [[0.1, 0.2], [0.3, 0.4]], "statuses": ["PRESENT", "PRESENT"]}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	features := []Feature{
+		{Name: "robot_features:payload_type", Dim: 1},
+		{Name: "robot_features:zone_embedding", Dim: 2},
+	}
+	found, err := NewFeastSource(server.URL).Fetch(context.Background(), []uint64{1, 2}, features)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	want := []map[string][]float32{
+		{"robot_features:payload_type": {4}, "robot_features:zone_embedding": {0.1, 0.2}},
+		{"robot_features:zone_embedding": {0.3, 0.4}},
+	}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("Expected %v, got %v", want, found)
+	}
+}
@@ -0,0 +1,122 @@
+// internal/enrich/feast.go
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FeastSource reads features from a Feast feature server's
+// /get-online-features endpoint. Feature names are Feast feature references
+// ("feature_view:feature").
+type FeastSource struct {
+	URL string
+	// EntityKey is the Feast join key for robots; defaults to "robot_id"
+	EntityKey string
+	Client    *http.Client
+}
+
+// NewFeastSource creates a FeastSource for the feature server at url
+func NewFeastSource(url string) *FeastSource {
+	return &FeastSource{
+		URL:       url,
+		EntityKey: "robot_id",
+		Client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Name implements Source
+func (s *FeastSource) Name() string {
+	return "feast"
+}
+
+// feastResponse is the subset of the Feast online response that is used
+type feastResponse struct {
+	Metadata struct {
+		FeatureNames []string `json:"feature_names"`
+	} `json:"metadata"`
+	Results []struct {
+		Values   []json.RawMessage `json:"values"`
+		Statuses []string          `json:"statuses"`
+	} `json:"results"`
+}
+
+// Fetch implements Source
+func (s *FeastSource) Fetch(ctx context.Context, robotIDs []uint64, features []Feature) ([]map[string][]float32, error) {
+	refs := make([]string, len(features))
+	for i, f := range features {
+		refs[i] = f.Name
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"features": refs,
+		"entities": map[string][]uint64{s.EntityKey: robotIDs},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL+"/get-online-features", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("feast request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feast returned status %d", resp.StatusCode)
+	}
+
+	var out feastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid feast response: %w", err)
+	}
+
+	found := make([]map[string][]float32, len(robotIDs))
+	for i := range found {
+		found[i] = make(map[string][]float32, len(features))
+	}
+
+	// Feast returns one column per feature (plus the entity key), with one value per entity.
+	// Feature names come back without the view prefix unless full names are requested.
+	for col, name := range out.Metadata.FeatureNames {
+		f, ok := matchFeature(features, name)
+		if !ok || col >= len(out.Results) {
+			continue
+		}
+		result := out.Results[col]
+		for i := range robotIDs {
+			if i >= len(result.Values) || (i < len(result.Statuses) && result.Statuses[i] != "PRESENT") {
+				continue
+			}
+			values, err := parseValues(result.Values[i])
+			if err != nil {
+				continue
+			}
+			found[i][f.Name] = values
+		}
+	}
+	return found, nil
+}
+
+// matchFeature finds the configured feature for a name returned by Feast,
+// which may be either the full reference or just the feature part
+func matchFeature(features []Feature, name string) (Feature, bool) {
+	for _, f := range features {
+		if f.Name == name {
+			return f, true
+		}
+		if i := strings.LastIndexByte(f.Name, ':'); i >= 0 && f.Name[i+1:] == name {
+			return f, true
+		}
+	}
+	return Feature{}, false
+}
@@ -0,0 +1,75 @@
+// internal/enrich/redis.go
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+)
+
+// RedisSource reads features from a Redis hash per robot ("robot:<id>:features"
+// by default), with one field per feature holding a number or a JSON array
+type RedisSource struct {
+	Cache *cache.Cache
+	// KeyFormat is the hash key for a robot ID; defaults to "robot:%d:features"
+	KeyFormat string
+}
+
+// Name implements Source
+func (s RedisSource) Name() string {
+	return "redis"
+}
+
+// Fetch implements Source, reading all robots in one pipeline
+func (s RedisSource) Fetch(ctx context.Context, robotIDs []uint64, features []Feature) ([]map[string][]float32, error) {
+	format := s.KeyFormat
+	if format == "" {
+		format = "robot:%d:features"
+	}
+
+	keys := make([]string, len(robotIDs))
+	for i, id := range robotIDs {
+		keys[i] = fmt.Sprintf(format, id)
+	}
+	fields := make([]string, len(features))
+	for i, f := range features {
+		fields[i] = f.Name
+	}
+
+	rows, err := s.Cache.GetFields(ctx, keys, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]map[string][]float32, len(robotIDs))
+	for i, row := range rows {
+		found[i] = make(map[string][]float32, len(fields))
+		for j, raw := range row {
+			if raw == "" {
+				continue
+			}
+			values, err := parseValues([]byte(raw))
+			if err != nil {
+				continue // Malformed values count as missing
+			}
+			found[i][fields[j]] = values
+		}
+	}
+	return found, nil
+}
+
+// parseValues decodes a JSON number or array of numbers
+func parseValues(raw []byte) ([]float32, error) {
+	var values []float32
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values, nil
+	}
+
+	var v float32
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, fmt.Errorf("expected number or array, got %s", raw)
+	}
+	return []float32{v}, nil
+}
@@ -0,0 +1,167 @@
+// Package epoch plans configured robot groups at fixed control epochs, for
+// fleets where planning is server-initiated rather than client-initiated. At
+// each epoch a group's latest observations are read from Redis, planned in one
+// batch, and the actions published to the group's channel or stream.
+//
+// Epochs are aligned to multiples of the group's interval since the Unix
+// epoch, so replicas agree on them; every replica may run the Runner, and a
+// Redis lock per group makes only one of them plan it.
+package epoch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/publish"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Planner plans a batch; implemented by *handler.Handler
+type Planner interface {
+	BatchPlan(ctx context.Context, req *pb.BatchPlanRequest) (*pb.BatchPlanResponse, error)
+}
+
+// ObservationSource returns the latest observation of each robot of a tenant,
+// nil for robots without one
+type ObservationSource interface {
+	Observations(ctx context.Context, tenant string, robotIDs []uint64) ([]*pb.Observation, error)
+}
+
+// Locker elects a single leader among replicas; implemented by *cache.Cache
+type Locker interface {
+	AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+}
+
+// Group is a set of robots planned together every interval
+type Group struct {
+	Name     string
+	Tenant   string
+	RobotIDs []uint64
+	Interval time.Duration
+	// Sink receives the planned actions
+	Sink publish.Sink
+}
+
+// Runner plans each group at its epochs
+type Runner struct {
+	planner Planner
+	source  ObservationSource
+	locker  Locker
+	groups  []Group
+	owner   string
+	now     func() time.Time
+}
+
+// New creates a Runner planning groups with planner from the observations in
+// source, electing the replica that plans each group with locker
+func New(planner Planner, source ObservationSource, locker Locker, groups []Group) *Runner {
+	host, _ := os.Hostname()
+	return &Runner{
+		planner: planner,
+		source:  source,
+		locker:  locker,
+		groups:  groups,
+		owner:   fmt.Sprintf("%s-%d", host, os.Getpid()),
+		now:     time.Now,
+	}
+}
+
+// Run plans every group at its epochs, while this replica holds the group's
+// lock, until ctx is done
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, g := range r.groups {
+		wg.Add(1)
+		go func(g Group) {
+			defer wg.Done()
+			r.runGroup(ctx, g)
+		}(g)
+	}
+	wg.Wait()
+}
+
+// runGroup plans g at each epoch until ctx is done
+func (r *Runner) runGroup(ctx context.Context, g Group) {
+	lockKey := "plan-schedule:" + g.Name
+	for {
+		now := r.now()
+		next := now.Truncate(g.Interval).Add(g.Interval)
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Hold the lock a little past the next epoch so the leader keeps it
+		held, err := r.locker.AcquireLock(ctx, lockKey, r.owner, g.Interval*3/2)
+		if err != nil {
+			slog.Warn("Plan schedule skipped", "schedule", g.Name, "error", err)
+			continue
+		}
+		if !held {
+			continue
+		}
+		if _, err := r.Plan(ctx, g, next); err != nil {
+			slog.Warn("Plan schedule failed", "schedule", g.Name, "error", err)
+		}
+	}
+}
+
+// Plan plans the group's robots that have an observation for the epoch and
+// publishes their actions, returning how many were planned. Robots without an
+// observation are skipped.
+func (r *Runner) Plan(ctx context.Context, g Group, epoch time.Time) (int, error) {
+	observations, err := r.source.Observations(ctx, g.Tenant, g.RobotIDs)
+	if err != nil {
+		metrics.RecordScheduledPlans(g.Name, "failed", len(g.RobotIDs))
+		return 0, fmt.Errorf("failed to read observations: %w", err)
+	}
+
+	req := &pb.BatchPlanRequest{}
+	for i, obs := range observations {
+		if obs != nil {
+			req.Requests = append(req.Requests, &pb.PlanRequest{RobotId: g.RobotIDs[i], Obs: obs})
+		}
+	}
+	metrics.RecordScheduledPlans(g.Name, "missing", len(g.RobotIDs)-len(req.Requests))
+	if len(req.Requests) == 0 {
+		return 0, nil
+	}
+
+	requestID := fmt.Sprintf("schedule-%s-%d", g.Name, epoch.UnixNano())
+	planCtx := middleware.WithTenantID(ctx, g.Tenant)
+	planCtx = middleware.WithRequestID(planCtx, requestID)
+	resp, err := r.planner.BatchPlan(planCtx, req)
+	if err != nil {
+		metrics.RecordScheduledPlans(g.Name, "failed", len(req.Requests))
+		return 0, fmt.Errorf("failed to plan %d robots: %w", len(req.Requests), err)
+	}
+
+	planned := r.now().UnixNano()
+	messages := make([]publish.Message, len(resp.Responses))
+	for i, plan := range resp.Responses {
+		messages[i] = publish.Message{
+			RobotID:           req.Requests[i].RobotId,
+			Tenant:            g.Tenant,
+			Action:            plan.Action,
+			Safe:              plan.Safe,
+			ObsHash:           plan.ObsHash,
+			RequestID:         requestID,
+			TimestampUnixNano: planned,
+		}
+	}
+	if err := g.Sink.Send(ctx, messages); err != nil {
+		metrics.RecordScheduledPlans(g.Name, "failed", len(messages))
+		return 0, fmt.Errorf("failed to publish %d actions: %w", len(messages), err)
+	}
+	metrics.RecordScheduledPlans(g.Name, "planned", len(messages))
+	return len(messages), nil
+}
@@ -0,0 +1,77 @@
+// internal/epoch/epoch_test.go
+package epoch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/publish"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// echoPlanner returns each robot's first observation value as its action
+type echoPlanner struct {
+	tenant    string
+	requestID string
+}
+
+func (p *echoPlanner) BatchPlan(ctx context.Context, req *pb.BatchPlanRequest) (*pb.BatchPlanResponse, error) {
+	p.tenant, p.requestID = middleware.GetTenantID(ctx), middleware.GetRequestID(ctx)
+	resp := &pb.BatchPlanResponse{}
+	for _, r := range req.Requests {
+		resp.Responses = append(resp.Responses, &pb.PlanResponse{Action: r.Obs.Data[:1], Safe: true})
+	}
+	return resp, nil
+}
+
+type memorySink struct {
+	messages []publish.Message
+}
+
+func (s *memorySink) Send(_ context.Context, messages []publish.Message) error {
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func TestRunner_Plan(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLocal(16)
+	tenant, _ := c.ForTenant("acme")
+	err := tenant.SetObservations(ctx, map[uint64]*pb.Observation{
+		1: {Data: []float32{0.1, 0.2}, Channels: 1, Height: 1, Width: 2},
+		3: {Data: []float32{0.3, 0.4}, Channels: 1, Height: 1, Width: 2},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("SetObservations failed: %v", err)
+	}
+
+	planner := &echoPlanner{}
+	sink := &memorySink{}
+	r := New(planner, CacheSource{Cache: c}, c, nil)
+	g := Group{Name: "dock", Tenant: "acme", RobotIDs: []uint64{1, 2, 3}, Interval: time.Second, Sink: sink}
+	epoch := time.Unix(1700000000, 0)
+
+	n, err := r.Plan(ctx, g, epoch)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	// Robot 2 has no observation and is skipped
+	if n != 2 || len(sink.messages) != 2 {
+		t.Fatalf("Expected 2 robots planned and published, got %d and %d", n, len(sink.messages))
+	}
+	if m := sink.messages[1]; m.RobotID != 3 || m.Tenant != "acme" || len(m.Action) != 1 || m.Action[0] != 0.3 || m.TimestampUnixNano == 0 {
+		t.Errorf("Expected robot 3's action, got %+v", m)
+	}
+	if planner.tenant != "acme" || planner.requestID != "schedule-dock-1700000000000000000" {
+		t.Errorf("Expected the group's tenant and an epoch request ID, got %q and %q", planner.tenant, planner.requestID)
+	}
+
+	// Other tenants' observations are not read
+	g.Tenant = "globex"
+	if n, err := r.Plan(ctx, g, epoch); err != nil || n != 0 {
+		t.Errorf("Expected nothing planned for another tenant, got %d (err %v)", n, err)
+	}
+}
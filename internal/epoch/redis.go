@@ -0,0 +1,23 @@
+// internal/epoch/redis.go
+package epoch
+
+import (
+	"context"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// CacheSource reads observations stored with TenantCache.SetObservations
+type CacheSource struct {
+	Cache *cache.Cache
+}
+
+// Observations implements ObservationSource
+func (s CacheSource) Observations(ctx context.Context, tenant string, robotIDs []uint64) ([]*pb.Observation, error) {
+	t, err := s.Cache.ForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return t.GetObservations(ctx, robotIDs)
+}
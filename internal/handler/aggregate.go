@@ -0,0 +1,151 @@
+// internal/handler/aggregate.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Defaults for AggregatePlan when not configured with WithAggregation
+const (
+	DefaultAggregateBatchSize   = 256
+	DefaultAggregateMaxRequests = 16384
+)
+
+// WithAggregation sets how many AggregatePlan requests are planned per
+// inference batch and the most one call may send. Non-positive values keep
+// the defaults.
+func WithAggregation(batchSize, maxRequests int) Option {
+	return func(h *Handler) {
+		if batchSize > 0 {
+			h.aggregateBatchSize = batchSize
+		}
+		if maxRequests > 0 {
+			h.aggregateMaxRequests = maxRequests
+		}
+	}
+}
+
+// AggregatePlan plans the requests streamed by a gateway in batches of the
+// aggregate batch size, each started as soon as it fills while more requests
+// arrive, and answers with every response in request order once the gateway
+// closes its side. Batches run up to the stream concurrency at once. The
+// first failing batch fails the call.
+func (h *Handler) AggregatePlan(stream pb.PathPlanner_AggregatePlanServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	defer h.setHintTrailer(ctx)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		chunks   = make(map[int][]*pb.PlanResponse)
+		total    callCost
+		attached time.Duration // inference time attributed to this call
+	)
+	slots := make(chan struct{}, h.streamConcurrency)
+	plan := func(offset int, requests []*pb.PlanRequest) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+
+			resp, cost, err := h.batchPlan(ctx, &pb.BatchPlanRequest{Requests: requests})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			chunks[offset] = resp.Responses
+			total.inference += cost.inference
+			total.queue += cost.queue
+			attached += time.Duration(float64(cost.inference) * cost.batchShare)
+		}()
+	}
+	failed := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr
+	}
+
+	batchSize := h.aggregateBatchSize
+	if h.maxBatchSize > 0 {
+		batchSize = min(batchSize, h.maxBatchSize)
+	}
+	received := 0
+	var pending []*pb.PlanRequest
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			cancel()
+			wg.Wait()
+			if err := stream.Context().Err(); err != nil {
+				return contextError(err)
+			}
+			return err
+		}
+		if err := failed(); err != nil {
+			wg.Wait()
+			return err
+		}
+		if err := checkRobot(ctx, req.GetRobotId()); err != nil {
+			cancel()
+			wg.Wait()
+			return err
+		}
+		if received == h.aggregateMaxRequests {
+			cancel()
+			wg.Wait()
+			return resourceExhaustedError("aggregate call exceeds %d requests", h.aggregateMaxRequests)
+		}
+
+		received++
+		pending = append(pending, req)
+		if len(pending) == batchSize {
+			plan(received-len(pending), pending)
+			pending = nil
+		}
+	}
+	if received == 0 {
+		return invalidArgumentError("aggregate call sent no requests")
+	}
+	if len(pending) > 0 {
+		plan(received-len(pending), pending)
+	}
+	wg.Wait()
+	if err := failed(); err != nil {
+		return err
+	}
+
+	responses := make([]*pb.PlanResponse, 0, received)
+	for offset := 0; offset < received; offset += batchSize {
+		responses = append(responses, chunks[offset]...)
+	}
+
+	// Report the summed cost of all batches, with the overall share of the
+	// coalesced batches they ran in
+	total.batchShare = 1
+	if total.inference > 0 {
+		total.batchShare = float64(attached) / float64(total.inference)
+	}
+	setCostTrailer(ctx, total)
+	return stream.SendAndClose(&pb.BatchPlanResponse{Responses: responses})
+}
@@ -0,0 +1,305 @@
+// internal/handler/async.go
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// asyncJob is one PlanAsync call
+type asyncJob struct {
+	ticket    string
+	tenant    string
+	owner     string // authenticated subject that may read the result
+	client    string
+	requestID string
+	req       *pb.BatchPlanRequest
+
+	done     chan struct{} // closed once resp or err is set
+	resp     *pb.BatchPlanResponse
+	err      error
+	finished time.Time
+}
+
+// result converts the job to a PlanResult; call only after done is closed,
+// or with pending set for a job still queued or running
+func (j *asyncJob) result(pending bool) *pb.PlanResult {
+	r := &pb.PlanResult{Ticket: j.ticket}
+	switch {
+	case pending:
+		r.State = pb.PlanState_PLAN_STATE_PENDING
+	case j.err != nil:
+		st := status.Convert(j.err)
+		r.State = pb.PlanState_PLAN_STATE_FAILED
+		r.ErrorCode = int32(st.Code())
+		r.Error = st.Message()
+	default:
+		r.State = pb.PlanState_PLAN_STATE_DONE
+		r.Response = j.resp
+	}
+	return r
+}
+
+// asyncQueue runs PlanAsync jobs on a few background workers, so consumers
+// that need no immediate answer take a bounded share of inference
+type asyncQueue struct {
+	workers int
+	ttl     time.Duration
+	jobs    chan *asyncJob
+
+	mu      sync.Mutex
+	tickets map[string]*asyncJob
+	// expiry holds finished jobs in finishing order, so the oldest expire first
+	expiry []*asyncJob
+	closed bool
+}
+
+// WithAsync enables PlanAsync with the given number of workers, queued jobs
+// beyond which PlanAsync is rejected, and time results are kept once done.
+// The workers run in RunAsync.
+func WithAsync(workers, queueSize int, ttl time.Duration) Option {
+	return func(h *Handler) {
+		h.async = &asyncQueue{
+			workers: workers,
+			ttl:     ttl,
+			jobs:    make(chan *asyncJob, queueSize),
+			tickets: make(map[string]*asyncJob),
+		}
+	}
+}
+
+// RunAsync runs the PlanAsync workers until ctx is done, then fails the jobs
+// still queued. It returns immediately if async planning is not enabled.
+func (h *Handler) RunAsync(ctx context.Context) {
+	q := h.async
+	if q == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case job := <-q.jobs:
+					h.runAsyncJob(job)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	<-ctx.Done()
+	wg.Wait()
+
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	for {
+		select {
+		case job := <-q.jobs:
+			q.finish(job, nil, unavailableError("server is shutting down"))
+		default:
+			metrics.SetAsyncQueueDepth(0)
+			return
+		}
+	}
+}
+
+// runAsyncJob plans a job with the tenant, client, and request ID of its
+// PlanAsync call
+func (h *Handler) runAsyncJob(job *asyncJob) {
+	metrics.SetAsyncQueueDepth(len(h.async.jobs))
+	ctx := middleware.WithTenantID(context.Background(), job.tenant)
+	ctx = middleware.WithRequestID(ctx, job.requestID)
+	if job.client != "" {
+		ctx = middleware.WithClientIdentity(ctx, job.client)
+	}
+
+	resp, _, err := h.batchPlan(ctx, job.req)
+	if err != nil {
+		slog.ErrorContext(ctx, "Async plan failed",
+			"request_id", job.requestID, "ticket", job.ticket, "batch_size", len(job.req.GetRequests()), "error", err)
+	}
+	h.async.finish(job, resp, err)
+}
+
+// finish records a job's outcome and wakes its watchers
+func (q *asyncQueue) finish(job *asyncJob, resp *pb.BatchPlanResponse, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	metrics.RecordAsyncPlan(result)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job.resp, job.err, job.finished = resp, err, time.Now()
+	q.expiry = append(q.expiry, job)
+	close(job.done)
+}
+
+// expireLocked forgets results kept longer than the TTL
+func (q *asyncQueue) expireLocked(now time.Time) {
+	n := 0
+	for n < len(q.expiry) && now.Sub(q.expiry[n].finished) > q.ttl {
+		delete(q.tickets, q.expiry[n].ticket)
+		n++
+	}
+	q.expiry = q.expiry[n:]
+}
+
+// lookup returns the tenant's job for ticket, if it exists and has not expired
+func (q *asyncQueue) lookup(tenant, owner, ticket string) (*asyncJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireLocked(time.Now())
+	job, ok := q.tickets[ticket]
+	if !ok || job.tenant != tenant || job.owner != owner {
+		return nil, false
+	}
+	return job, true
+}
+
+// ticketOwner returns the tenant and authenticated subject a ticket created or
+// read by the caller of ctx belongs to; the subject is empty when
+// authentication is disabled, leaving tickets to the tenant
+func ticketOwner(ctx context.Context) (string, string) {
+	if id, ok := auth.FromContext(ctx); ok {
+		return id.Tenant, id.Subject
+	}
+	return middleware.GetTenantID(ctx), ""
+}
+
+// newTicket returns a random, unguessable ticket
+func newTicket() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// PlanAsync validates and enqueues a batch, returning a ticket for its result
+func (h *Handler) PlanAsync(ctx context.Context, req *pb.BatchPlanRequest) (*pb.PlanTicket, error) {
+	q := h.async
+	if q == nil {
+		return nil, failedPreconditionError("async planning is not enabled")
+	}
+	if req == nil || len(req.Requests) == 0 {
+		return nil, invalidArgumentError("batch request cannot be nil or empty")
+	}
+	if err := h.checkBatchSize(len(req.Requests)); err != nil {
+		return nil, err
+	}
+	if err := checkRobots(ctx, req.Requests); err != nil {
+		return nil, err
+	}
+
+	ticket, err := newTicket()
+	if err != nil {
+		return nil, internalError("failed to create ticket: %v", err)
+	}
+	tenant, owner := ticketOwner(ctx)
+	job := &asyncJob{
+		ticket:    ticket,
+		tenant:    tenant,
+		owner:     owner,
+		client:    middleware.GetClientIdentity(ctx),
+		requestID: middleware.GetRequestID(ctx),
+		req:       req,
+		done:      make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, unavailableError("server is shutting down")
+	}
+	q.expireLocked(time.Now())
+	select {
+	case q.jobs <- job:
+	default:
+		metrics.RecordAsyncPlan("rejected")
+		return nil, resourceExhaustedError("async plan queue is full (%d jobs)", cap(q.jobs))
+	}
+	q.tickets[ticket] = job
+	metrics.SetAsyncQueueDepth(len(q.jobs))
+	return &pb.PlanTicket{Ticket: ticket}, nil
+}
+
+// GetPlanResult returns the state of an async plan and, once done, its result
+func (h *Handler) GetPlanResult(ctx context.Context, req *pb.PlanTicket) (*pb.PlanResult, error) {
+	if h.async == nil {
+		return nil, failedPreconditionError("async planning is not enabled")
+	}
+
+	tenant, owner := ticketOwner(ctx)
+	job, ok := h.async.lookup(tenant, owner, req.GetTicket())
+	if !ok {
+		return nil, notFoundError("unknown or expired ticket %q", req.GetTicket())
+	}
+	select {
+	case <-job.done:
+		return job.result(false), nil
+	default:
+		return job.result(true), nil
+	}
+}
+
+// WatchPlanResults sends each ticket's result as it completes, in completion
+// order, and returns once all have been sent
+func (h *Handler) WatchPlanResults(req *pb.WatchPlanResultsRequest, stream pb.PathPlanner_WatchPlanResultsServer) error {
+	if h.async == nil {
+		return failedPreconditionError("async planning is not enabled")
+	}
+	if len(req.GetTickets()) == 0 {
+		return invalidArgumentError("no tickets to watch")
+	}
+
+	ctx := stream.Context()
+	tenant, owner := ticketOwner(ctx)
+	jobs := make([]*asyncJob, len(req.Tickets))
+	for i, ticket := range req.Tickets {
+		job, ok := h.async.lookup(tenant, owner, ticket)
+		if !ok {
+			return notFoundError("unknown or expired ticket %q", ticket)
+		}
+		jobs[i] = job
+	}
+
+	finished := make(chan *asyncJob, len(jobs))
+	for _, job := range jobs {
+		go func(job *asyncJob) {
+			select {
+			case <-job.done:
+				finished <- job
+			case <-ctx.Done():
+			}
+		}(job)
+	}
+	for range jobs {
+		select {
+		case job := <-finished:
+			if err := stream.Send(job.result(false)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// internal/handler/capabilities.go
+package handler
+
+import (
+	"context"
+
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// WithActionSpace reports the action space in GetCapabilities and, when echo
+// is set, in every response
+func WithActionSpace(space action.Space, echo bool) Option {
+	return func(h *Handler) {
+		h.space = actionSpaceProto(space)
+		h.echoSpace = echo
+	}
+}
+
+// actionSpaceProto converts space to its wire form
+func actionSpaceProto(space action.Space) *pb.ActionSpace {
+	out := &pb.ActionSpace{Kind: space.Kind}
+	for _, d := range space.Dims {
+		dim := &pb.ActionDimension{Name: d.Name}
+		if d.Bound != nil {
+			dim.Bound = &pb.ActionBound{Min: d.Bound.Min, Max: d.Bound.Max}
+		}
+		out.Dims = append(out.Dims, dim)
+	}
+	for i, c := range space.Choices {
+		out.Choices = append(out.Choices, &pb.DiscreteAction{Index: uint32(i), Label: c.Label, Values: c.Values})
+	}
+	return out
+}
+
+// GetCapabilities reports the observation shape the model accepts and the
+// action contract every response is checked against
+func (h *Handler) GetCapabilities(ctx context.Context, req *pb.CapabilitiesRequest) (*pb.Capabilities, error) {
+	if h.infer == nil {
+		return nil, failedPreconditionError("inference engine not initialized")
+	}
+
+	caps := &pb.Capabilities{
+		ActionDtype: "float32",
+		ActionMode:  action.ModeContinuous,
+	}
+	if info, ok := inference.Describe(h.infer); ok {
+		caps.Model = info.ModelPath
+		caps.ObservationShape = info.InputShape
+		caps.ActionDim = uint32(info.ActionDim)
+	}
+	if d, ok := h.decoder.(*action.DiscreteDecoder); ok {
+		caps.ActionMode = d.Mode()
+		caps.DiscreteActions = uint32(d.NumActions())
+		caps.ActionDim = uint32(d.ActionDim())
+	}
+	if h.contract != nil {
+		if h.contract.Dim > 0 {
+			caps.ActionDim = uint32(h.contract.Dim)
+		}
+		for _, b := range h.contract.Bounds {
+			caps.ActionBounds = append(caps.ActionBounds, &pb.ActionBound{Min: b.Min, Max: b.Max})
+		}
+	}
+	caps.ActionSpace = h.space
+	for _, e := range h.codecRegistry().Encodings() {
+		caps.ObservationEncodings = append(caps.ObservationEncodings, &pb.ObservationEncoding{Id: e.ID, Name: e.Name})
+	}
+	return caps, nil
+}
@@ -0,0 +1,102 @@
+// internal/handler/codecs.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SyedDaiam9101/policy-service/internal/codec"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// WithCodecs decodes observations sent as uint8_data or encoded_data with the
+// codecs in r. Without it the built-in codecs are used, with uint8 values
+// scaled to [0, 1].
+func WithCodecs(r *codec.Registry) Option {
+	return func(h *Handler) {
+		h.codecs = r
+	}
+}
+
+// defaultCodecs are the built-in codecs with the default normalization
+var defaultCodecs = codec.Builtin(observation.DefaultNormalization)
+
+// codecRegistry returns the codecs observations are decoded with
+func (h *Handler) codecRegistry() *codec.Registry {
+	if h.codecs == nil {
+		return defaultCodecs
+	}
+	return h.codecs
+}
+
+// encoded reports whether obs carries data for a codec
+func encoded(obs *pb.Observation) bool {
+	return len(obs.GetUint8Data()) > 0 || len(obs.GetEncodedData()) > 0
+}
+
+// decodeObservations returns req with its encoded observations decoded to
+// float32, or req itself if it has none. The caller's messages are left
+// untouched; decoded observations keep their encoded data, so their hash is
+// still the one the robot can compute from what it sent.
+func (h *Handler) decodeObservations(req *pb.BatchPlanRequest) (*pb.BatchPlanRequest, error) {
+	var decoded *pb.BatchPlanRequest
+	for i, r := range req.Requests {
+		if r == nil || !encoded(r.Obs) {
+			continue
+		}
+		obs, err := h.decodeObservation(r.Obs)
+		if err != nil {
+			return nil, invalidArgumentError("observation %d: %v", i, err)
+		}
+		if decoded == nil {
+			decoded = &pb.BatchPlanRequest{Requests: append([]*pb.PlanRequest(nil), req.Requests...)}
+		}
+		decoded.Requests[i] = &pb.PlanRequest{RobotId: r.RobotId, Obs: obs, Pose: r.Pose, ModelVersion: r.ModelVersion}
+	}
+	if decoded == nil {
+		return req, nil
+	}
+	return decoded, nil
+}
+
+// decodeObservation decodes an observation sent as uint8_data or
+// encoded_data to float32
+func (h *Handler) decodeObservation(obs *pb.Observation) (*pb.Observation, error) {
+	set := 0
+	for _, n := range []int{len(obs.Data), len(obs.Uint8Data), len(obs.EncodedData)} {
+		if n > 0 {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.New("set only one of data, uint8_data, and encoded_data")
+	}
+
+	id, payload := obs.Encoding, obs.EncodedData
+	if len(obs.Uint8Data) > 0 {
+		id, payload = codec.IDUint8, obs.Uint8Data
+	}
+	c, ok := h.codecRegistry().Lookup(id)
+	if !ok {
+		metrics.RecordObservationDecodeError(strconv.FormatUint(uint64(id), 10))
+		return nil, fmt.Errorf("unknown observation encoding %d", id)
+	}
+	data, err := c.Decode(payload, int(obs.Channels), int(obs.Height), int(obs.Width))
+	if err != nil {
+		metrics.RecordObservationDecodeError(c.Name())
+		return nil, fmt.Errorf("%s: %w", c.Name(), err)
+	}
+	metrics.RecordDecodedObservation(c.Name())
+	return &pb.Observation{
+		Data:        data,
+		Channels:    obs.Channels,
+		Height:      obs.Height,
+		Width:       obs.Width,
+		Uint8Data:   obs.Uint8Data,
+		Encoding:    obs.Encoding,
+		EncodedData: obs.EncodedData,
+	}, nil
+}
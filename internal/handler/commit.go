@@ -0,0 +1,292 @@
+// internal/handler/commit.go
+package handler
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// proposalWatchBuffer is how many proposals a WatchProposals stream queues
+// before further ones are dropped for it
+const proposalWatchBuffer = 256
+
+// proposal is an action awaiting its veto window and the robot's commit
+type proposal struct {
+	token    string
+	tenant   string
+	robotID  uint64
+	action   []float32
+	obsHash  string
+	proposed time.Time
+	// deadline is the end of the veto window
+	deadline time.Time
+
+	vetoed    chan struct{} // closed once vetoed
+	reason    string
+	committed bool
+}
+
+// message converts the proposal to the PlanProposal sent to supervisors
+func (p *proposal) message() *pb.PlanProposal {
+	return &pb.PlanProposal{
+		Token:                p.token,
+		RobotId:              p.robotID,
+		Action:               p.action,
+		ObsHash:              p.obsHash,
+		ProposedUnixNano:     p.proposed.UnixNano(),
+		VetoDeadlineUnixNano: p.deadline.UnixNano(),
+	}
+}
+
+// commitLedger holds proposed actions until they are committed or expire, so
+// a supervising safety service can veto them first
+type commitLedger struct {
+	window time.Duration
+	ttl    time.Duration
+	// clients are the client identity patterns whose plans need a commit
+	clients        []string
+	supervisorRole string
+
+	mu        sync.Mutex
+	proposals map[string]*proposal
+	// order holds proposals oldest first, so the oldest expire first
+	order    []*proposal
+	watchers map[chan *pb.PlanProposal]string // tenant of each watcher
+}
+
+// WithTwoPhaseCommit returns actions planned for callers matching clients (all
+// callers if empty) as proposals with a commit token. Supervisors can veto a
+// proposal for window after it is made; CommitPlan answers once the window
+// has passed. Proposals not committed within ttl are dropped. With
+// supervisorRole set, only authenticated callers holding it may watch and veto
+// proposals.
+func WithTwoPhaseCommit(window, ttl time.Duration, clients []string, supervisorRole string) Option {
+	return func(h *Handler) {
+		h.commits = &commitLedger{
+			window:         window,
+			ttl:            ttl,
+			clients:        clients,
+			supervisorRole: supervisorRole,
+			proposals:      make(map[string]*proposal),
+			watchers:       make(map[chan *pb.PlanProposal]string),
+		}
+	}
+}
+
+// requiredFor reports whether plans for client need a commit
+func (l *commitLedger) requiredFor(client string) bool {
+	if len(l.clients) == 0 {
+		return true
+	}
+	for _, pattern := range l.clients {
+		if matchClient(pattern, client) {
+			return true
+		}
+	}
+	return false
+}
+
+// propose records resp's action as a proposal of tenant's robot, sets its
+// commit token, and sends it to the tenant's watching supervisors
+func (l *commitLedger) propose(tenant string, robotID uint64, resp *pb.PlanResponse) error {
+	token, err := newTicket()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	p := &proposal{
+		token:    token,
+		tenant:   tenant,
+		robotID:  robotID,
+		action:   resp.Action,
+		obsHash:  resp.ObsHash,
+		proposed: now,
+		deadline: now.Add(l.window),
+		vetoed:   make(chan struct{}),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expireLocked(now)
+	l.proposals[token] = p
+	l.order = append(l.order, p)
+	resp.CommitToken = token
+
+	msg := p.message()
+	for ch, t := range l.watchers {
+		if t != tenant {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+			metrics.RecordProposalWatchDropped()
+		}
+	}
+	return nil
+}
+
+// expireLocked forgets proposals older than the TTL
+func (l *commitLedger) expireLocked(now time.Time) {
+	n := 0
+	for n < len(l.order) && now.Sub(l.order[n].proposed) > l.ttl {
+		p := l.order[n]
+		if !p.committed && !isClosed(p.vetoed) {
+			metrics.RecordPlanProposal("expired")
+		}
+		delete(l.proposals, p.token)
+		n++
+	}
+	l.order = l.order[n:]
+}
+
+// lookup returns the tenant's proposal for token, if it exists and has not expired
+func (l *commitLedger) lookup(tenant, token string) (*proposal, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expireLocked(time.Now())
+	p, ok := l.proposals[token]
+	if !ok || p.tenant != tenant {
+		return nil, false
+	}
+	return p, true
+}
+
+// watch registers a tenant's supervisor for new proposals; call the returned
+// function to stop watching
+func (l *commitLedger) watch(tenant string) (<-chan *pb.PlanProposal, func()) {
+	ch := make(chan *pb.PlanProposal, proposalWatchBuffer)
+	l.mu.Lock()
+	l.watchers[ch] = tenant
+	l.mu.Unlock()
+	return ch, func() {
+		l.mu.Lock()
+		delete(l.watchers, ch)
+		l.mu.Unlock()
+	}
+}
+
+// authorizeSupervisor rejects authenticated callers without the supervisor
+// role. Calls are not authenticated when auth is disabled, and pass.
+func (l *commitLedger) authorizeSupervisor(ctx context.Context) error {
+	if l.supervisorRole == "" {
+		return nil
+	}
+	id, ok := auth.FromContext(ctx)
+	if ok && !slices.Contains(id.Roles, l.supervisorRole) {
+		return permissionDeniedError("role %q required", l.supervisorRole)
+	}
+	return nil
+}
+
+// isClosed reports whether ch is closed
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// CommitPlan answers whether a proposed action may be applied, once its veto
+// window has passed or as soon as it is vetoed. Committing is idempotent.
+func (h *Handler) CommitPlan(ctx context.Context, req *pb.CommitPlanRequest) (*pb.CommitPlanResponse, error) {
+	l := h.commits
+	if l == nil {
+		return nil, failedPreconditionError("two-phase commit is not enabled")
+	}
+	if req.GetToken() == "" {
+		return nil, invalidArgumentError("commit token is required")
+	}
+	p, ok := l.lookup(middleware.GetTenantID(ctx), req.GetToken())
+	if !ok {
+		return nil, notFoundError("unknown or expired commit token %q", req.GetToken())
+	}
+	if err := checkRobot(ctx, p.robotID); err != nil {
+		return nil, err
+	}
+
+	if wait := time.Until(p.deadline); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-p.vetoed:
+		case <-ctx.Done():
+			return nil, contextError(ctx.Err())
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if isClosed(p.vetoed) {
+		return &pb.CommitPlanResponse{VetoReason: p.reason}, nil
+	}
+	if !p.committed {
+		p.committed = true
+		metrics.RecordPlanProposal("committed")
+	}
+	return &pb.CommitPlanResponse{Committed: true, Action: p.action}, nil
+}
+
+// VetoPlan rejects a proposed action if its veto window has not passed
+func (h *Handler) VetoPlan(ctx context.Context, req *pb.VetoPlanRequest) (*pb.VetoPlanResponse, error) {
+	l := h.commits
+	if l == nil {
+		return nil, failedPreconditionError("two-phase commit is not enabled")
+	}
+	if err := l.authorizeSupervisor(ctx); err != nil {
+		return nil, err
+	}
+	p, ok := l.lookup(middleware.GetTenantID(ctx), req.GetToken())
+	if !ok {
+		return nil, notFoundError("unknown or expired commit token %q", req.GetToken())
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if isClosed(p.vetoed) {
+		return &pb.VetoPlanResponse{Vetoed: true}, nil
+	}
+	if p.committed || !time.Now().Before(p.deadline) {
+		return &pb.VetoPlanResponse{Vetoed: false}, nil
+	}
+	p.reason = req.GetReason()
+	close(p.vetoed)
+	metrics.RecordPlanProposal("vetoed")
+	return &pb.VetoPlanResponse{Vetoed: true}, nil
+}
+
+// WatchProposals streams the caller's tenant's proposals as they are made,
+// until the call ends
+func (h *Handler) WatchProposals(req *pb.WatchProposalsRequest, stream pb.PathPlanner_WatchProposalsServer) error {
+	l := h.commits
+	if l == nil {
+		return failedPreconditionError("two-phase commit is not enabled")
+	}
+	ctx := stream.Context()
+	if err := l.authorizeSupervisor(ctx); err != nil {
+		return err
+	}
+
+	proposals, stop := l.watch(middleware.GetTenantID(ctx))
+	defer stop()
+	for {
+		select {
+		case p := <-proposals:
+			if err := stream.Send(p); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// internal/handler/cost.go
+package handler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Trailer keys reporting the server-side compute cost of a call, so clients can
+// budget their request rate on actual cost rather than observed latency
+const (
+	// CostInferenceMsTrailer is the inference time attributed to this call, in milliseconds
+	CostInferenceMsTrailer = "x-cost-inference-ms"
+	// CostQueueMsTrailer is the time spent waiting for an inference slot, in milliseconds
+	CostQueueMsTrailer = "x-cost-queue-ms"
+	// CostBatchShareTrailer is the fraction (0..1] of the inference batch this call made up
+	CostBatchShareTrailer = "x-cost-batch-share"
+)
+
+// callCost is the compute cost of a single call
+type callCost struct {
+	inference  time.Duration
+	queue      time.Duration
+	batchShare float64
+}
+
+// setCostTrailer attaches the call cost as response trailers. Inference time is
+// scaled by the call's share of the batch it ran in.
+func setCostTrailer(ctx context.Context, cost callCost) {
+	trailer := metadata.Pairs(
+		CostInferenceMsTrailer, formatMs(time.Duration(float64(cost.inference)*cost.batchShare)),
+		CostQueueMsTrailer, formatMs(cost.queue),
+		CostBatchShareTrailer, strconv.FormatFloat(cost.batchShare, 'f', 4, 64),
+	)
+	// Fails only outside a gRPC call (e.g. direct handler use); nothing to report then
+	grpc.SetTrailer(ctx, trailer)
+}
+
+func formatMs(d time.Duration) string {
+	return strconv.FormatFloat(float64(d.Microseconds())/1000.0, 'f', 3, 64)
+}
@@ -2,10 +2,15 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"strings"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/batching"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
 )
 
 // grpcError maps known internal errors to appropriate gRPC status errors
@@ -14,6 +19,31 @@ func grpcError(err error) error {
 		return nil
 	}
 
+	// The engine is closing during shutdown; clients should retry elsewhere
+	if errors.Is(err, inference.ErrShuttingDown) {
+		return status.Errorf(codes.Unavailable, "server is shutting down")
+	}
+
+	// A session that panicked is being recreated; retrying shortly should succeed
+	if errors.Is(err, inference.ErrSessionQuarantined) {
+		return status.Errorf(codes.Unavailable, "inference session is being recreated")
+	}
+
+	// The engine keeps failing; calls fail fast until a probe succeeds
+	if errors.Is(err, inference.ErrCircuitOpen) {
+		return status.Errorf(codes.Unavailable, "inference temporarily unavailable: %v", err)
+	}
+
+	// The batch ran past the inference timeout
+	if errors.Is(err, inference.ErrInferenceTimeout) {
+		return status.Errorf(codes.DeadlineExceeded, "%v", err)
+	}
+
+	// The batcher refused a call over the batch size limit
+	if errors.Is(err, batching.ErrBatchTooLarge) {
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+
 	errMsg := err.Error()
 
 	// Map specific error patterns to gRPC status codes
@@ -24,6 +54,9 @@ func grpcError(err error) error {
 	case strings.Contains(errMsg, "wrong size"):
 		return status.Errorf(codes.InvalidArgument, "observation shape mismatch: %v", err)
 
+	case strings.Contains(errMsg, "do not match model input shape"):
+		return status.Errorf(codes.InvalidArgument, "observation shape mismatch: %v", err)
+
 	case strings.Contains(errMsg, "session is nil"):
 		return status.Errorf(codes.FailedPrecondition, "inference engine not initialized")
 
@@ -47,6 +80,14 @@ func grpcError(err error) error {
 	}
 }
 
+// contextError maps a context cancellation or deadline error to its gRPC status
+func contextError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return grpcError(err)
+}
+
 // invalidArgumentError creates an InvalidArgument gRPC error
 func invalidArgumentError(format string, args ...interface{}) error {
 	return status.Errorf(codes.InvalidArgument, format, args...)
@@ -57,7 +98,32 @@ func failedPreconditionError(format string, args ...interface{}) error {
 	return status.Errorf(codes.FailedPrecondition, format, args...)
 }
 
+// unavailableError creates an Unavailable gRPC error
+func unavailableError(format string, args ...interface{}) error {
+	return status.Errorf(codes.Unavailable, format, args...)
+}
+
+// notFoundError creates a NotFound gRPC error
+func notFoundError(format string, args ...interface{}) error {
+	return status.Errorf(codes.NotFound, format, args...)
+}
+
+// resourceExhaustedError creates a ResourceExhausted gRPC error
+func resourceExhaustedError(format string, args ...interface{}) error {
+	return status.Errorf(codes.ResourceExhausted, format, args...)
+}
+
 // internalError creates an Internal gRPC error
 func internalError(format string, args ...interface{}) error {
 	return status.Errorf(codes.Internal, format, args...)
 }
+
+// permissionDeniedError creates a PermissionDenied gRPC error
+func permissionDeniedError(format string, args ...interface{}) error {
+	return status.Errorf(codes.PermissionDenied, format, args...)
+}
+
+// dataLossError creates a DataLoss gRPC error
+func dataLossError(format string, args ...interface{}) error {
+	return status.Errorf(codes.DataLoss, format, args...)
+}
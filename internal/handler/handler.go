@@ -3,13 +3,35 @@ package handler
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync/atomic"
 	"time"
 
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/admission"
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/batching"
 	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/codec"
+	"github.com/SyedDaiam9101/policy-service/internal/dedup"
+	"github.com/SyedDaiam9101/policy-service/internal/enrich"
 	"github.com/SyedDaiam9101/policy-service/internal/inference"
 	"github.com/SyedDaiam9101/policy-service/internal/metrics"
 	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	"github.com/SyedDaiam9101/policy-service/internal/placement"
+	"github.com/SyedDaiam9101/policy-service/internal/plancache"
+	"github.com/SyedDaiam9101/policy-service/internal/preprocess"
+	"github.com/SyedDaiam9101/policy-service/internal/publish"
+	"github.com/SyedDaiam9101/policy-service/internal/recorder"
+	"github.com/SyedDaiam9101/policy-service/internal/safety"
+	"github.com/SyedDaiam9101/policy-service/internal/scheduler"
+	"github.com/SyedDaiam9101/policy-service/internal/signing"
+	"github.com/SyedDaiam9101/policy-service/internal/telemetry"
+	"github.com/SyedDaiam9101/policy-service/internal/timeouts"
+	"github.com/SyedDaiam9101/policy-service/internal/watchdog"
 	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
 )
 
@@ -17,17 +39,293 @@ import (
 // It uses the InferenceEngine interface for flexibility and testability.
 type Handler struct {
 	pb.UnimplementedPathPlannerServer
-	infer inference.InferenceEngine
-	cache *cache.Cache
+	infer     inference.InferenceEngine
+	cache     *cache.Cache
+	scheduler *scheduler.FairScheduler
+	decoder   action.Decoder
+	signer    *signing.Signer
+	progress  *watchdog.Progress
+	enricher  *enrich.Enricher
+	telemetry *telemetry.Collector
+	dedup     *dedup.Window
+	planCache *plancache.Cache
+	ranges    []observation.RangeRule
+	batcher   *batching.Batcher
+	contract  *action.Contract
+	safety    *safety.Checker
+	space     *pb.ActionSpace
+	echoSpace bool // attach space to every response
+	async     *asyncQueue
+	updates   *planSchedule
+	recorder  *recorder.Recorder
+	publisher *publish.Publisher
+	placement *placement.Tracker
+	upstream  *upstream
+	admission *admission.Controller
+	// robotTokens serves IssueRobotToken; nil when robot tokens are disabled
+	robotTokens *robotTokenIssuer
+	// cacheTimeout bounds pose and plan cache lookups; nil for none
+	cacheTimeout *timeouts.Stage
+
+	// nonFiniteAction replaces actions from NaN or Inf outputs; nil rejects them
+	nonFiniteAction []float32
+
+	downsampleFactor  int64
+	downsampleMinSize int64
+	degraded          func() bool
+
+	// queueDepth counts observations waiting for or running inference
+	queueDepth atomic.Int64
+	hints      *clientHints
+	validity   *actionValidity
+	commits    *commitLedger
+	poses      *poseCache
+
+	// tensorBytes counts the input tensor bytes of queued observations, and
+	// tensorHighWater the most counted at once
+	tensorBytes     atomic.Int64
+	tensorHighWater atomic.Int64
+	maxTensorBytes  int64
+	maxBatchSize    int
+
+	// preprocess transforms observations before inference, by layout
+	preprocess []preprocess.Rule
+
+	// registryModel is the registry version the model was resolved to; nil
+	// without a registry
+	registryModel *pb.RegistryModel
+
+	// latestVersion names the version infer serves; versions are the older
+	// ones requests may pin. Both are empty without model versions.
+	latestVersion string
+	versions      map[string]inference.InferenceEngine
+
+	// codecs decode observations sent as uint8_data or encoded_data; nil uses
+	// the built-in codecs
+	codecs *codec.Registry
+
+	// validateWorkers validate the observations of batches of at least
+	// validateMinBatch in parallel; <= 1 validates in order
+	validateWorkers  int
+	validateMinBatch int
+
+	streamChunkSize       int
+	streamConcurrency     int
+	planStreamInterval    time.Duration
+	planStreamMinInterval time.Duration
+	aggregateBatchSize    int
+	aggregateMaxRequests  int
+
+	warmupInferences int
+	warmupBatchSizes []int
+}
+
+// Option configures optional Handler dependencies
+type Option func(*Handler)
+
+// WithScheduler makes BatchPlan acquire a fair-scheduling slot for the caller's
+// tenant before running inference
+func WithScheduler(s *scheduler.FairScheduler) Option {
+	return func(h *Handler) {
+		h.scheduler = s
+	}
+}
+
+// WithActionDecoder post-processes raw model outputs (e.g. discrete-action logits)
+// into the actions returned to clients
+func WithActionDecoder(d action.Decoder) Option {
+	return func(h *Handler) {
+		h.decoder = d
+	}
+}
+
+// WithSigner signs every PlanResponse so robots can verify it was not tampered with
+func WithSigner(s *signing.Signer) Option {
+	return func(h *Handler) {
+		h.signer = s
+	}
+}
+
+// WithProgress reports batches entering and leaving inference so the watchdog
+// can detect a wedged pipeline
+func WithProgress(p *watchdog.Progress) Option {
+	return func(h *Handler) {
+		h.progress = p
+	}
+}
+
+// WithEnricher appends per-robot features from a feature store to each
+// state-vector observation before inference
+func WithEnricher(e *enrich.Enricher) Option {
+	return func(h *Handler) {
+		h.enricher = e
+	}
+}
+
+// WithTelemetry reports the robots in each batch to the dashboard telemetry snapshot
+func WithTelemetry(c *telemetry.Collector) Option {
+	return func(h *Handler) {
+		h.telemetry = c
+	}
+}
+
+// WithDedup answers an observation a robot re-sends within the window with the
+// action computed for it last time instead of running inference again
+func WithDedup(w *dedup.Window) Option {
+	return func(h *Handler) {
+		h.dedup = w
+	}
+}
+
+// WithCacheTimeout bounds pose and plan cache lookups by stage's timeout;
+// lookups that run out of time are treated as failed and planning goes on
+// without them
+func WithCacheTimeout(stage *timeouts.Stage) Option {
+	return func(h *Handler) {
+		h.cacheTimeout = stage
+	}
+}
+
+// WithPlanCache answers observations planned recently, by any robot or
+// replica, from the plan cache and stores fresh plans in it
+func WithPlanCache(c *plancache.Cache) Option {
+	return func(h *Handler) {
+		h.planCache = c
+	}
+}
+
+// WithInputRanges checks observations against the value range the model
+// expects for their layout, rejecting or clamping those far outside it
+func WithInputRanges(rules []observation.RangeRule) Option {
+	return func(h *Handler) {
+		h.ranges = rules
+	}
+}
+
+// WithPreprocess runs observations through the pipeline of the first rule
+// matching their layout before inference
+func WithPreprocess(rules []preprocess.Rule) Option {
+	return func(h *Handler) {
+		h.preprocess = rules
+	}
+}
+
+// WithBatcher runs inference through a batcher that coalesces concurrent calls
+// into shared Predict calls on the engine
+func WithBatcher(b *batching.Batcher) Option {
+	return func(h *Handler) {
+		h.batcher = b
+	}
+}
+
+// WithMaxBatchSize refuses calls planning more than n observations at once
+// with ResourceExhausted, so one client cannot run an inference batch large
+// enough to exhaust the runtime's memory. BatchPlanStream chunks are capped at
+// n. Non-positive values mean no limit.
+func WithMaxBatchSize(n int) Option {
+	return func(h *Handler) {
+		h.maxBatchSize = max(n, 0)
+	}
+}
+
+// WithContract checks every inference result against the declared action
+// contract, failing the call instead of returning actions that break it
+func WithContract(c action.Contract) Option {
+	return func(h *Handler) {
+		h.contract = &c
+	}
+}
+
+// WithSafety checks every planned action against the checker's limits,
+// marking responses unsafe or clamping their actions
+func WithSafety(c *safety.Checker) Option {
+	return func(h *Handler) {
+		h.safety = c
+	}
+}
+
+// WithPlacement claims every planned robot for this replica, reporting robots
+// reassigned from other replicas
+func WithPlacement(t *placement.Tracker) Option {
+	return func(h *Handler) {
+		h.placement = t
+	}
+}
+
+// WithAdmission reports how long each inference batch waited to run, so the
+// admission controller can reject new calls while the queue stays long
+func WithAdmission(c *admission.Controller) Option {
+	return func(h *Handler) {
+		h.admission = c
+	}
+}
+
+// WithRecorder records each served observation and action as an episode entry
+func WithRecorder(r *recorder.Recorder) Option {
+	return func(h *Handler) {
+		h.recorder = r
+	}
+}
+
+// WithPublisher publishes each planned action for subscribers outside the
+// gRPC API
+func WithPublisher(p *publish.Publisher) Option {
+	return func(h *Handler) {
+		h.publisher = p
+	}
+}
+
+// WithDownsampling average-pools image observations by factor before
+// inference while degraded reports true, e.g. when shedding load or running on
+// a fallback CPU engine. Observations smaller than minSize on either side are
+// left at full resolution.
+func WithDownsampling(factor, minSize int64, degraded func() bool) Option {
+	return func(h *Handler) {
+		h.downsampleFactor = factor
+		h.downsampleMinSize = minSize
+		h.degraded = degraded
+	}
+}
+
+// WithClientHints reports the queue depth and a suggested call interval in
+// response trailers: baseInterval while at most queueTarget observations are
+// queued, stretched in proportion to deeper queues up to maxInterval
+func WithClientHints(queueTarget int, baseInterval, maxInterval time.Duration) Option {
+	return func(h *Handler) {
+		h.hints = &clientHints{
+			queueTarget:  int64(queueTarget),
+			baseInterval: baseInterval,
+			maxInterval:  maxInterval,
+		}
+	}
+}
+
+// WithActionValidity stamps each action with how long it stays valid: the
+// validity of the first rule matching the caller's client identity, else
+// fallback. A validity of 0 leaves actions without a limit.
+func WithActionValidity(fallback time.Duration, rules []ValidityRule) Option {
+	return func(h *Handler) {
+		h.validity = &actionValidity{fallback: fallback, rules: rules}
+	}
 }
 
 // New creates a new Handler with the given inference engine and cache.
 // The inference engine must implement the InferenceEngine interface.
-func New(infer inference.InferenceEngine, cache *cache.Cache) *Handler {
-	return &Handler{
-		infer: infer,
-		cache: cache,
+func New(infer inference.InferenceEngine, cache *cache.Cache, opts ...Option) *Handler {
+	h := &Handler{
+		infer:                 infer,
+		cache:                 cache,
+		streamChunkSize:       DefaultStreamChunkSize,
+		streamConcurrency:     DefaultStreamConcurrency,
+		planStreamInterval:    DefaultPlanStreamInterval,
+		planStreamMinInterval: DefaultPlanStreamMinInterval,
+		aggregateBatchSize:    DefaultAggregateBatchSize,
+		aggregateMaxRequests:  DefaultAggregateMaxRequests,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Plan handles a single planning request by delegating to BatchPlan
@@ -56,6 +354,23 @@ func (h *Handler) Plan(ctx context.Context, req *pb.PlanRequest) (*pb.PlanRespon
 
 // BatchPlan handles batch planning requests
 func (h *Handler) BatchPlan(ctx context.Context, req *pb.BatchPlanRequest) (*pb.BatchPlanResponse, error) {
+	if err := checkRobots(ctx, req.GetRequests()); err != nil {
+		return nil, err
+	}
+	resp, cost, err := h.planOrProxy(ctx, req)
+	// Hint at the load on failures too, so clients back off before retrying
+	h.setHintTrailer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report compute cost, scaled by the call's share of any coalesced batch
+	setCostTrailer(ctx, cost)
+	return resp, nil
+}
+
+// batchPlan plans a batch and returns its responses with the compute cost
+func (h *Handler) batchPlan(ctx context.Context, req *pb.BatchPlanRequest) (*pb.BatchPlanResponse, callCost, error) {
 	start := time.Now()
 
 	// Get request ID for logging
@@ -65,11 +380,22 @@ func (h *Handler) BatchPlan(ctx context.Context, req *pb.BatchPlanRequest) (*pb.
 	}
 
 	if req == nil || len(req.Requests) == 0 {
-		return nil, invalidArgumentError("batch request cannot be nil or empty")
+		return nil, callCost{}, invalidArgumentError("batch request cannot be nil or empty")
 	}
 
 	if h.infer == nil {
-		return nil, failedPreconditionError("inference engine not initialized")
+		return nil, callCost{}, failedPreconditionError("inference engine not initialized")
+	}
+	if err := h.checkBatchSize(len(req.Requests)); err != nil {
+		return nil, callCost{}, err
+	}
+	version, pinned, err := h.modelVersion(req)
+	if err != nil {
+		return nil, callCost{}, err
+	}
+	req, err = h.decodeObservations(req)
+	if err != nil {
+		return nil, callCost{}, err
 	}
 
 	batchSize := len(req.Requests)
@@ -77,84 +403,597 @@ func (h *Handler) BatchPlan(ctx context.Context, req *pb.BatchPlanRequest) (*pb.
 	// Record batch size metric
 	metrics.RecordInferenceBatch(batchSize)
 
-	// Extract observations from each request
-	var obsBatch [][]float32
-	var c, height, w int64
+	// Dimensions come from the first observation; the others must match
+	first := req.Requests[0]
+	if first == nil {
+		return nil, callCost{}, invalidArgumentError("request %d is nil", 0)
+	}
+	if first.Obs == nil {
+		return nil, callCost{}, invalidArgumentError("request %d has nil observation", 0)
+	}
+	c, height, w := int64(first.Obs.Channels), int64(first.Obs.Height), int64(first.Obs.Width)
+	if c <= 0 || height <= 0 || w <= 0 {
+		return nil, callCost{}, invalidArgumentError("invalid observation dimensions: channels=%d, height=%d, width=%d", c, height, w)
+	}
+	expectedLen := int(c * height * w)
 
-	for i, planReq := range req.Requests {
+	// Extract observations from each request
+	obsBatch := make([][]float32, batchSize)
+	robotIDs := make([]uint64, batchSize)
+	err = h.forEachObservation(batchSize, func(i int) error {
+		planReq := req.Requests[i]
 		if planReq == nil {
-			return nil, invalidArgumentError("request %d is nil", i)
+			return invalidArgumentError("request %d is nil", i)
 		}
 		if planReq.Obs == nil {
-			return nil, invalidArgumentError("request %d has nil observation", i)
+			return invalidArgumentError("request %d has nil observation", i)
 		}
 
 		obs := planReq.Obs
-
-		// Use dimensions from first observation, validate others match
-		if i == 0 {
-			c = int64(obs.Channels)
-			height = int64(obs.Height)
-			w = int64(obs.Width)
-
-			// Validate dimensions are positive
-			if c <= 0 || height <= 0 || w <= 0 {
-				return nil, invalidArgumentError("invalid observation dimensions: channels=%d, height=%d, width=%d", c, height, w)
-			}
-		} else {
-			if int64(obs.Channels) != c || int64(obs.Height) != height || int64(obs.Width) != w {
-				return nil, invalidArgumentError(
-					"observation %d has mismatched dimensions: got (%d,%d,%d), expected (%d,%d,%d)",
-					i, obs.Channels, obs.Height, obs.Width, c, height, w)
-			}
+		if int64(obs.Channels) != c || int64(obs.Height) != height || int64(obs.Width) != w {
+			return invalidArgumentError(
+				"observation %d has mismatched dimensions: got (%d,%d,%d), expected (%d,%d,%d)",
+				i, obs.Channels, obs.Height, obs.Width, c, height, w)
 		}
 
 		// Validate observation data length
-		expectedLen := int(c * height * w)
 		if len(obs.Data) != expectedLen {
-			return nil, invalidArgumentError(
+			return invalidArgumentError(
 				"observation %d has wrong data length: got %d, expected %d",
 				i, len(obs.Data), expectedLen)
 		}
 
-		obsBatch = append(obsBatch, obs.Data)
+		obsBatch[i] = obs.Data
+		robotIDs[i] = planReq.RobotId
+		return nil
+	})
+	if err != nil {
+		return nil, callCost{}, err
+	}
+	if h.poses != nil && h.poses.mode == PoseObservation && (c != 1 || height != 1) {
+		return nil, callCost{}, invalidArgumentError(
+			"appending the robot pose requires state-vector observations (channels=1, height=1), got (%d,%d,%d)", c, height, w)
 	}
 
-	// Run inference with timing
-	inferStart := time.Now()
-	actions, err := h.infer.Predict(obsBatch, c, height, w)
-	inferDuration := time.Since(inferStart)
-	metrics.RecordInferenceLatency(inferDuration.Seconds())
+	// Catch observations far outside the model's expected input range, such
+	// as raw 0-255 pixels sent to a model trained on normalized images, and
+	// NaNs. The scan runs like the checks above; clamping then runs in order.
+	if r, ok := observation.FindRange(h.ranges, uint32(c), uint32(height), uint32(w)); ok {
+		outliers := make([]int, batchSize)
+		worst := make([]float32, batchSize)
+		err := h.forEachObservation(batchSize, func(i int) error {
+			outliers[i], worst[i] = r.Check(obsBatch[i])
+			if outliers[i] > 0 && !r.Clamp {
+				return invalidArgumentError(
+					"observation %d has %d values outside expected range [%g, %g] (e.g. %g)",
+					i, outliers[i], r.Min, r.Max, worst[i])
+			}
+			return nil
+		})
+		if err != nil {
+			metrics.RecordObservationOutOfRange("rejected")
+			return nil, callCost{}, err
+		}
+		for i := range obsBatch {
+			if outliers[i] == 0 {
+				continue
+			}
+			metrics.RecordObservationOutOfRange("clamped")
+			slog.WarnContext(ctx, "Observation outside expected range; clamping",
+				"request_id", requestID, "robot_id", robotIDs[i], "outliers", outliers[i], "min", r.Min, "max", r.Max, "example", worst[i])
+			// Copy so the caller's observation data is left untouched
+			obsBatch[i] = r.Clamped(obsBatch[i])
+		}
+	}
 
-	if err != nil {
-		log.Printf("[%s] Inference error: %v", requestID, err)
-		return nil, grpcError(err)
+	if h.telemetry != nil {
+		h.telemetry.RecordRobots(robotIDs)
+	}
+
+	// Fetch each robot's last pose before this batch's poses replace it
+	var lastPoses []*pb.Pose
+	if h.poses != nil {
+		lastPoses = h.lastPoses(ctx, requestID, robotIDs)
+	}
+
+	obsHashes := make([]string, batchSize)
+	for i, planReq := range req.Requests {
+		obsHashes[i] = observation.Hash(planReq.Obs)
+	}
+
+	// Answer repeated observations from the dedup window; only the rest
+	// (indexed by pending) go through inference. Both hold actions of the
+	// latest version, so pinned versions skip them.
+	robotActions := make([][]float32, batchSize)
+	pending := make([]int, 0, batchSize)
+	for i := range req.Requests {
+		if h.dedup != nil && pinned == nil {
+			if action, ok := h.dedup.Lookup(middleware.GetTenantID(ctx), robotIDs[i], obsHashes[i]); ok {
+				robotActions[i] = action
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+	dedupHits := batchSize - len(pending)
+	if dedupHits > 0 {
+		metrics.RecordDedupHits(dedupHits)
+	}
+	// Plans are cached under the version of the model serving this batch, or
+	// not at all while it has none
+	var planVersion string
+	if h.planCache != nil && pinned == nil {
+		planVersion = h.planCache.Version()
+	}
+	if planVersion != "" && len(pending) > 0 {
+		pending = h.lookupPlans(ctx, requestID, planVersion, req.Requests, pending, robotActions)
+	}
+	if len(pending) < batchSize {
+		pendingObs := make([][]float32, len(pending))
+		pendingIDs := make([]uint64, len(pending))
+		for j, i := range pending {
+			pendingObs[j] = obsBatch[i]
+			pendingIDs[j] = robotIDs[i]
+		}
+		obsBatch, robotIDs = pendingObs, pendingIDs
+	}
+
+	// Transform observations into the input the model was trained on; steps
+	// copy, so the caller's observation data is left untouched
+	inC, inHeight, inWidth := c, height, w
+	if pipeline, ok := preprocess.Find(h.preprocess, uint32(c), uint32(height), uint32(w)); ok && len(pending) > 0 {
+		in := preprocess.Shape{C: int(c), H: int(height), W: int(w)}
+		out, err := pipeline.Shape(in)
+		if err != nil {
+			return nil, callCost{}, invalidArgumentError("%v", err)
+		}
+		preprocessStart := time.Now()
+		for j := range obsBatch {
+			obsBatch[j] = pipeline.Apply(obsBatch[j], in)
+		}
+		metrics.RecordPreprocess(len(pending), time.Since(preprocessStart).Seconds())
+		inC, inHeight, inWidth = int64(out.C), int64(out.H), int64(out.W)
+	}
+
+	// Trade resolution for latency while the service is degraded
+	downsampled := len(pending) > 0 && h.shouldDownsample(inHeight, inWidth)
+	if downsampled {
+		// Downsample copies, so the caller's observation data is left untouched
+		dsHeight, dsWidth := inHeight, inWidth
+		for j := range obsBatch {
+			obsBatch[j], dsHeight, dsWidth = observation.Downsample(obsBatch[j], inC, inHeight, inWidth, h.downsampleFactor)
+		}
+		inHeight, inWidth = dsHeight, dsWidth
+		metrics.RecordDownsampledObservations(len(pending))
+	}
+	if h.poses != nil && h.poses.mode == PoseObservation && len(pending) > 0 {
+		// withPose copies, so the caller's observation data is left untouched
+		for j, i := range pending {
+			obsBatch[j] = withPose(obsBatch[j], lastPoses[i])
+		}
+		inWidth += poseFeatures
 	}
 
-	// Calculate action dimension from output
-	actionDim := len(actions) / batchSize
-	if actionDim*batchSize != len(actions) {
-		return nil, internalError("action output size mismatch: got %d actions for batch %d", len(actions), batchSize)
+	var (
+		actions     []float32
+		actionDim   int
+		substituted []int // indices into pending answered with the non-finite substitute
+		cost        = callCost{batchShare: 1}
+	)
+	if len(pending) > 0 {
+		var err error
+		actions, substituted, cost, err = h.runInference(ctx, requestID, version, pinned, obsBatch, robotIDs, inC, inHeight, inWidth)
+		if err != nil {
+			return nil, callCost{}, err
+		}
+
+		// Calculate action dimension from output
+		actionDim = len(actions) / len(pending)
+		if actionDim*len(pending) != len(actions) {
+			return nil, callCost{}, internalError("action output size mismatch: got %d actions for batch %d", len(actions), len(pending))
+		}
 	}
 
-	// Split actions into per-robot responses
+	// Split fresh actions per robot and remember them for the dedup window.
+	// Degraded-resolution and substituted actions are not remembered, so
+	// repeats are planned by the model again.
+	degradedResolution := make([]bool, batchSize)
+	var sources []string
+	if h.recorder != nil {
+		sources = recordSources(batchSize, pending, downsampled, shadowing(h.infer))
+	}
+	modelled := pending
+	if len(substituted) > 0 {
+		modelled = make([]int, 0, len(pending)-len(substituted))
+	}
+	for j, i := range pending {
+		robotActions[i] = actions[j*actionDim : (j+1)*actionDim]
+		degradedResolution[i] = downsampled
+		if len(substituted) > 0 {
+			if slices.Contains(substituted, j) {
+				if sources != nil {
+					sources[i] = recorder.SourceFallback
+				}
+				continue
+			}
+			modelled = append(modelled, i)
+		}
+		if h.dedup != nil && pinned == nil && !downsampled {
+			h.dedup.Store(middleware.GetTenantID(ctx), req.Requests[i].RobotId, obsHashes[i], robotActions[i])
+		}
+	}
+	if planVersion != "" && len(modelled) > 0 && !downsampled {
+		h.storePlans(ctx, requestID, planVersion, req.Requests, modelled, robotActions)
+	}
+
+	var (
+		planned  = time.Now()
+		validity time.Duration
+		client   = middleware.GetClientIdentity(ctx)
+	)
+	if h.validity != nil {
+		validity = h.validity.forClient(client)
+	}
+	propose := h.commits != nil && h.commits.requiredFor(client)
+
 	responses := make([]*pb.PlanResponse, batchSize)
 	for i := 0; i < batchSize; i++ {
-		startIdx := i * actionDim
-		endIdx := startIdx + actionDim
-
+		safe := true
+		if h.safety != nil {
+			var result string
+			robotActions[i], safe, result = h.safety.Check(robotActions[i])
+			metrics.RecordSafetyCheck(result)
+			if !safe {
+				slog.WarnContext(ctx, "Planned action outside the safety limits",
+					"request_id", requestID, "robot_id", req.Requests[i].RobotId, "result", result, "action", robotActions[i])
+			}
+		}
 		responses[i] = &pb.PlanResponse{
-			Action: actions[startIdx:endIdx],
-			Safe:   true, // Placeholder for future confidence logic
+			Action:             robotActions[i],
+			Safe:               safe,
+			ObsHash:            obsHashes[i],
+			DegradedResolution: degradedResolution[i],
+			ModelVersion:       version,
+		}
+		if h.poses != nil && h.poses.mode == PoseResponse {
+			responses[i].LastPose = lastPoses[i]
+		}
+		if h.echoSpace {
+			responses[i].ActionSpace = h.space
+		}
+		stampValidity(responses[i], planned, validity)
+		if propose {
+			if err := h.commits.propose(middleware.GetTenantID(ctx), req.Requests[i].RobotId, responses[i]); err != nil {
+				return nil, callCost{}, internalError("failed to create commit token: %v", err)
+			}
+		}
+
+		if h.signer != nil {
+			h.signer.Sign(req.Requests[i].RobotId, responses[i])
+		}
+		if h.recorder != nil {
+			obs := req.Requests[i].Obs
+			h.recorder.Record(recorder.Entry{
+				Time:           start,
+				RobotID:        req.Requests[i].RobotId,
+				Tenant:         middleware.GetTenantID(ctx),
+				Client:         middleware.GetClientIdentity(ctx),
+				RequestID:      middleware.GetRequestID(ctx),
+				IdempotencyKey: middleware.GetIdempotencyKey(ctx),
+				ObsHash:        obsHashes[i],
+				Obs:            obs.Data,
+				Channels:       obs.Channels,
+				Height:         obs.Height,
+				Width:          obs.Width,
+				Action:         robotActions[i],
+				Source:         sources[i],
+			})
 		}
+		if h.publisher != nil {
+			h.publisher.Publish(publish.Message{
+				RobotID:           req.Requests[i].RobotId,
+				Tenant:            middleware.GetTenantID(ctx),
+				Action:            robotActions[i],
+				Safe:              responses[i].Safe,
+				ObsHash:           obsHashes[i],
+				RequestID:         middleware.GetRequestID(ctx),
+				TimestampUnixNano: planned.UnixNano(),
+			})
+		}
+	}
+
+	if h.poses != nil {
+		h.storePoses(ctx, requestID, req.Requests, start)
+	}
+	if h.placement != nil {
+		h.placement.Seen(middleware.GetTenantID(ctx), robotIDs)
 	}
 
 	// Log batch metrics
 	latencyMs := float64(time.Since(start).Microseconds()) / 1000.0
-	log.Printf("[%s] BatchPlan: batch_size=%d, inference_ms=%.2f, total_ms=%.2f",
-		requestID, batchSize, float64(inferDuration.Microseconds())/1000.0, latencyMs)
+	if client == "" {
+		client = "-"
+	}
+	attrs := []any{
+		"request_id", requestID,
+		"client", client,
+		"batch_size", batchSize,
+		"dedup_hits", dedupHits,
+		"plan_cache_hits", batchSize - dedupHits - len(pending),
+		"downsampled", downsampled,
+		"inference_ms", float64(cost.inference.Microseconds()) / 1000.0,
+		"total_ms", latencyMs,
+	}
+	if batchSize == 1 {
+		attrs = append(attrs, "robot_id", req.Requests[0].RobotId)
+	}
+	slog.InfoContext(ctx, "BatchPlan", attrs...)
 
 	return &pb.BatchPlanResponse{
 		Responses: responses,
-	}, nil
+	}, cost, nil
+}
+
+// lookupPlans fills robotActions for the pending requests found in the plan
+// cache under version and returns the indexes still to be planned. Cache
+// errors are logged and the observations planned as usual.
+func (h *Handler) lookupPlans(ctx context.Context, requestID, version string, requests []*pb.PlanRequest, pending []int, robotActions [][]float32) []int {
+	obs := make([]*pb.Observation, len(pending))
+	for j, i := range pending {
+		obs[j] = requests[i].Obs
+	}
+	plans, err := cacheLookup(ctx, h.cacheTimeout, func(ctx context.Context) ([]*pb.PlanResponse, error) {
+		return h.planCache.Lookup(ctx, middleware.GetTenantID(ctx), version, obs)
+	})
+	if err != nil {
+		metrics.RecordPlanCache("error", len(pending))
+		slog.WarnContext(ctx, "Plan cache lookup failed", "request_id", requestID, "error", err)
+		return pending
+	}
+
+	remaining := make([]int, 0, len(pending))
+	for j, i := range pending {
+		if plans[j] == nil {
+			remaining = append(remaining, i)
+			continue
+		}
+		robotActions[i] = plans[j].Action
+	}
+	if hits := len(pending) - len(remaining); hits > 0 {
+		metrics.RecordPlanCache("hit", hits)
+	}
+	if len(remaining) > 0 {
+		metrics.RecordPlanCache("miss", len(remaining))
+	}
+	return remaining
+}
+
+// cacheLookup runs lookup bounded by the cache stage's timeout, feeding the
+// stage its latency when it completes or runs out of time
+func cacheLookup[T any](ctx context.Context, stage *timeouts.Stage, lookup func(context.Context) (T, error)) (T, error) {
+	if stage == nil {
+		return lookup(ctx)
+	}
+	lookupCtx, cancel := stage.Context(ctx)
+	defer cancel()
+	start := time.Now()
+	v, err := lookup(lookupCtx)
+	switch {
+	case err == nil:
+		stage.Observe(time.Since(start))
+	case lookupCtx.Err() != nil && ctx.Err() == nil:
+		// Observed too, so an adaptive timeout grows when Redis slows down
+		stage.Observe(time.Since(start))
+		stage.Expired()
+	}
+	return v, err
+}
+
+// storePlans caches the actions version freshly planned for the pending
+// requests. Failures are logged; the call is still served.
+func (h *Handler) storePlans(ctx context.Context, requestID, version string, requests []*pb.PlanRequest, pending []int, robotActions [][]float32) {
+	obs := make([]*pb.Observation, len(pending))
+	plans := make([]*pb.PlanResponse, len(pending))
+	for j, i := range pending {
+		obs[j] = requests[i].Obs
+		plans[j] = &pb.PlanResponse{Action: robotActions[i], Safe: true}
+	}
+	if err := h.planCache.Store(ctx, middleware.GetTenantID(ctx), version, obs, plans); err != nil {
+		slog.WarnContext(ctx, "Failed to store plans in the plan cache", "request_id", requestID, "error", err)
+	}
+}
+
+// recordSources returns where each of a batch's recorded actions came from:
+// the requests not pending were answered from a cache, and the pending ones
+// were planned downsampled, alongside a shadowed candidate, or plainly
+func recordSources(batchSize int, pending []int, downsampled, shadowed bool) []string {
+	fresh := recorder.SourceInference
+	switch {
+	case downsampled:
+		fresh = recorder.SourceFallback
+	case shadowed:
+		fresh = recorder.SourceShadow
+	}
+	sources := make([]string, batchSize)
+	for i := range sources {
+		sources[i] = recorder.SourceCache
+	}
+	for _, i := range pending {
+		sources[i] = fresh
+	}
+	return sources
+}
+
+// shadowing reports whether e, or an engine it wraps, is replaying traffic on
+// a candidate model for comparison
+func shadowing(e inference.InferenceEngine) bool {
+	for e != nil {
+		if s, ok := e.(interface{ Shadowing() bool }); ok {
+			return s.Shadowing()
+		}
+		w, ok := e.(inference.Wrapper)
+		if !ok {
+			return false
+		}
+		e = w.Unwrap()
+	}
+	return false
+}
+
+// shouldDownsample reports whether height x width observations are downsampled
+// before inference: downsampling is configured, the observations are large
+// enough, and the service is currently degraded
+func (h *Handler) shouldDownsample(height, w int64) bool {
+	if h.degraded == nil || h.downsampleFactor <= 1 {
+		return false
+	}
+	if height < h.downsampleMinSize || w < h.downsampleMinSize || height < h.downsampleFactor || w < h.downsampleFactor {
+		return false
+	}
+	return h.degraded()
+}
+
+// checkBatchSize refuses batches over the configured maximum
+func (h *Handler) checkBatchSize(n int) error {
+	if h.maxBatchSize > 0 && n > h.maxBatchSize {
+		metrics.RecordBatchRejected()
+		return resourceExhaustedError("batch of %d requests exceeds the limit of %d per call", n, h.maxBatchSize)
+	}
+	return nil
+}
+
+// runInference enriches, schedules, and runs a batch of observations, returning the
+// decoded actions, the indices of those replaced by the non-finite substitute,
+// and the call's compute cost. A pinned engine runs the batch in place of the
+// latest version.
+func (h *Handler) runInference(ctx context.Context, requestID, version string, pinned inference.InferenceEngine, obsBatch [][]float32, robotIDs []uint64, c, height, w int64) ([]float32, []int, callCost, error) {
+	batchSize := len(obsBatch)
+
+	// Append per-robot static features so serving inputs match training inputs
+	if h.enricher != nil {
+		if c != 1 || height != 1 {
+			return nil, nil, callCost{}, invalidArgumentError(
+				"feature enrichment requires state-vector observations (channels=1, height=1), got (%d,%d,%d)", c, height, w)
+		}
+
+		features, err := h.enricher.Enrich(ctx, robotIDs)
+		if err != nil {
+			slog.ErrorContext(ctx, "Enrichment failed", "request_id", requestID, "batch_size", batchSize, "error", err)
+			if errors.Is(err, enrich.ErrUnavailable) {
+				return nil, nil, callCost{}, unavailableError("feature enrichment failed: %v", err)
+			}
+			return nil, nil, callCost{}, internalError("feature enrichment failed: %v", err)
+		}
+
+		// Copy so the caller's observation data is left untouched
+		for i := range obsBatch {
+			enriched := make([]float32, 0, len(obsBatch[i])+h.enricher.Dim())
+			obsBatch[i] = append(append(enriched, obsBatch[i]...), features[i]...)
+		}
+		w += int64(h.enricher.Dim())
+	}
+
+	// Refuse tensors over the memory cap before packing them
+	releaseTensor, err := h.reserveTensor(int64(batchSize) * c * height * w * float32Size)
+	if err != nil {
+		slog.WarnContext(ctx, "Input tensor too large", "request_id", requestID, "batch_size", batchSize, "error", err)
+		return nil, nil, callCost{}, err
+	}
+	defer releaseTensor()
+
+	// Track the batch from queueing until inference returns
+	h.queueDepth.Add(int64(batchSize))
+	defer h.queueDepth.Add(-int64(batchSize))
+	done := func() {}
+	if h.progress != nil {
+		done = h.progress.Begin()
+	}
+
+	// Wait for a fair-scheduling slot when tenants share the engine; shares
+	// follow the authenticated tenant, so callers cannot pick a heavier weight
+	release := func() {}
+	queueStart := time.Now()
+	if h.scheduler != nil {
+		var err error
+		release, err = h.scheduler.Acquire(ctx, auth.TenantFromContext(ctx), batchSize)
+		if err != nil {
+			done()
+			return nil, nil, callCost{}, contextError(err)
+		}
+	}
+	cost := callCost{queue: time.Since(queueStart), batchShare: 1}
+
+	// Run inference with timing, coalesced with other calls when batching
+	var actions []float32
+	switch {
+	case pinned != nil:
+		// Pinned versions are not coalesced with batches for the latest
+		inferStart := time.Now()
+		actions, err = pinned.Predict(obsBatch, c, height, w)
+		cost.inference = time.Since(inferStart)
+	case h.batcher != nil:
+		var res batching.Result
+		res, err = h.batcher.Predict(obsBatch, c, height, w)
+		actions = res.Actions
+		cost.inference = res.Inference
+		cost.queue += res.Wait
+		cost.batchShare = res.Share
+	default:
+		inferStart := time.Now()
+		actions, err = h.infer.Predict(obsBatch, c, height, w)
+		cost.inference = time.Since(inferStart)
+	}
+	release()
+	done()
+	metrics.RecordInferenceLatency(cost.inference.Seconds())
+	if version != "" {
+		metrics.RecordModelVersionBatch(version, batchSize, cost.inference.Seconds(), err == nil)
+	}
+	if h.admission != nil {
+		h.admission.ObserveQueueWait(cost.queue)
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Inference failed", "request_id", requestID, "batch_size", batchSize, "error", err)
+		return nil, nil, callCost{}, grpcError(err)
+	}
+
+	// Check the raw outputs, as decoding can hide NaN or Inf (e.g. in argmax)
+	nonFinite := nonFiniteRows(actions, batchSize)
+	if len(nonFinite) > 0 && h.nonFiniteAction == nil {
+		metrics.RecordNonFiniteOutputs("rejected", len(nonFinite))
+		slog.ErrorContext(ctx, "Model returned non-finite outputs", "request_id", requestID, "batch_size", batchSize, "observations", len(nonFinite))
+		return nil, nil, callCost{}, dataLossError("model returned NaN or Inf outputs for %d of %d observations", len(nonFinite), batchSize)
+	}
+
+	// Map raw outputs (e.g. logits over a discrete action set) to actions
+	if h.decoder != nil {
+		actions, err = h.decoder.Decode(actions, batchSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "Action decoding failed", "request_id", requestID, "batch_size", batchSize, "error", err)
+			return nil, nil, callCost{}, internalError("action decoding failed: %v", err)
+		}
+	}
+
+	if len(nonFinite) > 0 {
+		if err := h.substituteRows(actions, batchSize, nonFinite); err != nil {
+			return nil, nil, callCost{}, err
+		}
+		metrics.RecordNonFiniteOutputs("substituted", len(nonFinite))
+		slog.WarnContext(ctx, "Substituted the safe action for non-finite outputs", "request_id", requestID, "batch_size", batchSize, "observations", len(nonFinite))
+	}
+
+	// Fail loudly on model or config drift rather than returning bad actions
+	if h.contract != nil {
+		if err := h.contract.Check(actions, batchSize); err != nil {
+			var violation *action.ContractError
+			if errors.As(err, &violation) {
+				metrics.RecordActionContractViolation(violation.Reason)
+			}
+			slog.ErrorContext(ctx, "Action contract violation", "request_id", requestID, "batch_size", batchSize, "error", err)
+			return nil, nil, callCost{}, failedPreconditionError("action contract violation: %v", err)
+		}
+	}
+
+	return actions, nonFinite, cost, nil
 }
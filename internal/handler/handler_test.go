@@ -3,15 +3,40 @@ package handler
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/batching"
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/codec"
+	"github.com/SyedDaiam9101/policy-service/internal/dedup"
 	"github.com/SyedDaiam9101/policy-service/internal/inference"
 	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	"github.com/SyedDaiam9101/policy-service/internal/plancache"
+	"github.com/SyedDaiam9101/policy-service/internal/preprocess"
+	"github.com/SyedDaiam9101/policy-service/internal/recorder"
+	"github.com/SyedDaiam9101/policy-service/internal/safety"
+	"github.com/SyedDaiam9101/policy-service/internal/scheduler"
+	"github.com/SyedDaiam9101/policy-service/internal/signing"
+	"github.com/SyedDaiam9101/policy-service/internal/timeouts"
 	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
 )
 
@@ -101,6 +126,10 @@ func TestPlanWithMockInference(t *testing.T) {
 		t.Error("Expected Safe=true")
 	}
 
+	if resp.ObsHash != observation.Hash(req.Obs) {
+		t.Errorf("Expected ObsHash %s, got %s", observation.Hash(req.Obs), resp.ObsHash)
+	}
+
 	// Verify mock was called
 	if mock.CallCount != 1 {
 		t.Errorf("Expected mock.CallCount=1, got %d", mock.CallCount)
@@ -355,3 +384,1831 @@ func TestBatchPlanWithInferenceError(t *testing.T) {
 		t.Errorf("Expected Internal error code, got: %v", st.Code())
 	}
 }
+
+func TestBatchPlanWithSchedulerTimeout(t *testing.T) {
+	mock := inference.NewMock()
+	sched := scheduler.New(1, nil)
+	h := New(mock, nil, WithScheduler(sched))
+
+	// Hold the only slot so the request has to queue
+	hold, err := sched.Acquire(context.Background(), "other", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer hold()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req := &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{
+			{
+				RobotId: 1,
+				Obs: &pb.Observation{
+					Data:     []float32{0.1, 0.2, 0.3, 0.4},
+					Channels: 1,
+					Height:   2,
+					Width:    2,
+				},
+			},
+		},
+	}
+
+	_, err = h.BatchPlan(ctx, req)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("Expected DeadlineExceeded, got: %v", err)
+	}
+
+	if mock.CallCount != 0 {
+		t.Errorf("Expected inference not to run, got CallCount=%d", mock.CallCount)
+	}
+}
+
+func TestPlanWithDiscreteActionDecoder(t *testing.T) {
+	// Model emits logits over three discrete actions; the second scores highest
+	mock := inference.NewMockWithAction([]float32{0.1, 2.5, -1.0})
+	decoder, err := action.NewDiscreteDecoder(action.ModeArgmax, [][]float32{{0, 0}, {1, 0.5}, {-1, 0}}, 1)
+	if err != nil {
+		t.Fatalf("NewDiscreteDecoder failed: %v", err)
+	}
+	h := New(mock, nil, WithActionDecoder(decoder))
+
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs: &pb.Observation{
+			Data:     []float32{0.1, 0.2, 0.3, 0.4},
+			Channels: 1,
+			Height:   2,
+			Width:    2,
+		},
+	}
+
+	resp, err := h.Plan(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	expected := []float32{1, 0.5}
+	if len(resp.Action) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d", len(expected), len(resp.Action))
+	}
+	for i, v := range expected {
+		if resp.Action[i] != v {
+			t.Errorf("Action[%d] = %f, expected %f", i, resp.Action[i], v)
+		}
+	}
+}
+
+// trailerStream captures trailers set by the handler
+type trailerStream struct {
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string                  { return "/planner.PathPlanner/Plan" }
+func (s *trailerStream) SetHeader(md metadata.MD) error  { return nil }
+func (s *trailerStream) SendHeader(md metadata.MD) error { return nil }
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestPlanSetsCostTrailer(t *testing.T) {
+	mock := inference.NewMock()
+	h := New(mock, nil)
+
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs: &pb.Observation{
+			Data:     []float32{0.1, 0.2, 0.3, 0.4},
+			Channels: 1,
+			Height:   2,
+			Width:    2,
+		},
+	}
+
+	if _, err := h.Plan(ctx, req); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	for _, key := range []string{CostInferenceMsTrailer, CostQueueMsTrailer} {
+		values := stream.trailer.Get(key)
+		if len(values) != 1 {
+			t.Fatalf("Expected one %s trailer, got %v", key, values)
+		}
+		if ms, err := strconv.ParseFloat(values[0], 64); err != nil || ms < 0 {
+			t.Errorf("Expected non-negative %s, got %q", key, values[0])
+		}
+	}
+	if share := stream.trailer.Get(CostBatchShareTrailer); len(share) != 1 || share[0] != "1.0000" {
+		t.Errorf("Expected batch share 1.0000, got %v", share)
+	}
+}
+
+func TestClientHints(t *testing.T) {
+	hints := clientHints{queueTarget: 10, baseInterval: 33 * time.Millisecond, maxInterval: 500 * time.Millisecond}
+	if d := hints.interval(4); d != 33*time.Millisecond {
+		t.Errorf("Expected the base interval below the target, got %s", d)
+	}
+	if d := hints.interval(30); d != 99*time.Millisecond {
+		t.Errorf("Expected 3x the base interval at 3x the target, got %s", d)
+	}
+	if d := hints.interval(1000); d != 500*time.Millisecond {
+		t.Errorf("Expected the max interval for a deep queue, got %s", d)
+	}
+
+	h := New(inference.NewMock(), nil, WithClientHints(10, 33*time.Millisecond, 500*time.Millisecond))
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	if _, err := h.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: nil}); err == nil {
+		t.Fatal("Expected an error for a nil observation")
+	}
+	if depth := stream.trailer.Get(QueueDepthTrailer); len(depth) != 1 || depth[0] != "0" {
+		t.Errorf("Expected queue depth 0 on a failed call, got %v", depth)
+	}
+	if interval := stream.trailer.Get(SuggestedIntervalMsTrailer); len(interval) != 1 || interval[0] != "33.000" {
+		t.Errorf("Expected suggested interval 33.000, got %v", interval)
+	}
+}
+
+// recordingSink collects recorded entries
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []recorder.Entry
+}
+
+func (s *recordingSink) Write(_ context.Context, entries []recorder.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func TestBatchPlanRecordsSources(t *testing.T) {
+	sink := &recordingSink{}
+	rec := recorder.New(sink, "policy-v1", 16, time.Minute)
+	h := New(inference.NewMock(), nil, WithDedup(dedup.New(time.Minute)), WithRecorder(rec))
+
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	plan := func(requestID string) {
+		ctx := middleware.WithRequestID(context.Background(), requestID)
+		if _, err := h.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: obs}); err != nil {
+			t.Fatalf("Plan failed: %v", err)
+		}
+	}
+	plan("req-1")
+	plan("req-1") // Retry, answered from the dedup window and not recorded again
+	plan("req-2") // Repeated observation, answered from the dedup window
+	rec.Close()
+
+	if len(sink.entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(sink.entries))
+	}
+	if sink.entries[0].Source != recorder.SourceInference || sink.entries[1].Source != recorder.SourceCache {
+		t.Errorf("Expected inference then cache sources, got %q and %q", sink.entries[0].Source, sink.entries[1].Source)
+	}
+}
+
+func TestBatchPlanWithDedup(t *testing.T) {
+	mock := inference.NewMock()
+	h := New(mock, nil, WithDedup(dedup.New(time.Minute)))
+
+	obs := func(v float32) *pb.Observation {
+		return &pb.Observation{Data: []float32{v, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	}
+
+	first, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs(0.1)}, {RobotId: 2, Obs: obs(0.1)}},
+	})
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+
+	// Robot 1 re-sends its observation; robot 2 sends a new one
+	second, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs(0.1)}, {RobotId: 2, Obs: obs(0.9)}},
+	})
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if mock.CallCount != 2 {
+		t.Fatalf("Expected 2 inference calls, got %d", mock.CallCount)
+	}
+	if len(second.Responses) != 2 || len(second.Responses[0].Action) != len(first.Responses[0].Action) {
+		t.Fatalf("Expected a reused action for robot 1, got %+v", second.Responses)
+	}
+	if second.Responses[0].ObsHash != first.Responses[0].ObsHash {
+		t.Errorf("Expected matching obs hashes for the repeated observation")
+	}
+
+	// A fully repeated batch skips inference entirely
+	if _, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs(0.1)}, {RobotId: 2, Obs: obs(0.9)}},
+	}); err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if mock.CallCount != 2 {
+		t.Errorf("Expected no inference for a repeated batch, got %d calls", mock.CallCount)
+	}
+
+	// Another tenant's robot with the same id does not share results
+	if _, err := h.BatchPlan(middleware.WithTenantID(context.Background(), "globex"), &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs(0.1)}},
+	}); err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if mock.CallCount != 3 {
+		t.Errorf("Expected inference for another tenant, got %d calls", mock.CallCount)
+	}
+}
+
+func TestBatchPlanWithPlanCache(t *testing.T) {
+	mock := inference.NewMock()
+	store := &plancache.MemoryStore{}
+	version := "v1"
+	h := New(mock, nil, WithPlanCache(plancache.New(store, func() string { return version }, time.Minute)))
+
+	obs := func(v float32) *pb.Observation {
+		return &pb.Observation{Data: []float32{v, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	}
+	ctx := middleware.WithTenantID(context.Background(), "acme")
+
+	first, err := h.BatchPlan(ctx, &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs(0.1)}},
+	})
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+
+	// Another robot, served by another replica sharing the store, sends the
+	// same observation alongside a new one
+	other := New(mock, nil, WithPlanCache(plancache.New(store, func() string { return "v1" }, time.Minute)))
+	second, err := other.BatchPlan(ctx, &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 2, Obs: obs(0.1)}, {RobotId: 3, Obs: obs(0.9)}},
+	})
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if mock.CallCount != 2 {
+		t.Fatalf("Expected one inference call for the new observation, got %d calls", mock.CallCount)
+	}
+	if len(second.Responses[0].Action) != len(first.Responses[0].Action) || second.Responses[0].ObsHash != first.Responses[0].ObsHash {
+		t.Fatalf("Expected the cached plan for robot 2, got %+v", second.Responses[0])
+	}
+
+	// Other tenants do not share plans
+	if _, err := h.BatchPlan(middleware.WithTenantID(context.Background(), "globex"), &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 4, Obs: obs(0.1)}},
+	}); err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if mock.CallCount != 3 {
+		t.Errorf("Expected inference for another tenant, got %d calls", mock.CallCount)
+	}
+
+	// A reloaded model does not serve the previous version's plans, and
+	// nothing is cached while no version serves alone
+	version = "v2"
+	planOnce := func() {
+		t.Helper()
+		if _, err := h.BatchPlan(ctx, &pb.BatchPlanRequest{
+			Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs(0.1)}},
+		}); err != nil {
+			t.Fatalf("BatchPlan failed: %v", err)
+		}
+	}
+	planOnce()
+	if mock.CallCount != 4 {
+		t.Errorf("Expected inference after a model version change, got %d calls", mock.CallCount)
+	}
+	version = ""
+	planOnce()
+	planOnce()
+	if mock.CallCount != 6 {
+		t.Errorf("Expected inference for every batch without a model version, got %d calls", mock.CallCount)
+	}
+}
+
+func TestBatchPlanActionValidity(t *testing.T) {
+	h := New(inference.NewMock(), nil, WithActionValidity(250*time.Millisecond, []ValidityRule{
+		{Client: "spiffe://example.org/robot/arm/*", Validity: 100 * time.Millisecond},
+		{Client: "spiffe://example.org/robot/agv-7", Validity: 0},
+	}))
+	req := &pb.PlanRequest{RobotId: 1, Obs: &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}}
+
+	tests := []struct {
+		client   string
+		validity uint32
+	}{
+		{"spiffe://example.org/robot/arm/12", 100},
+		{"spiffe://example.org/robot/agv-7", 0},
+		{"spiffe://example.org/robot/agv-8", 250},
+		{"", 250},
+	}
+	for _, tt := range tests {
+		before := time.Now()
+		resp, err := h.Plan(middleware.WithClientIdentity(context.Background(), tt.client), req)
+		if err != nil {
+			t.Fatalf("Plan failed: %v", err)
+		}
+		if resp.ValidityMs != tt.validity {
+			t.Errorf("Expected validity %dms for %q, got %d", tt.validity, tt.client, resp.ValidityMs)
+		}
+		if tt.validity == 0 && resp.ExpiresUnixNano != 0 {
+			t.Errorf("Expected no expiry for %q, got %d", tt.client, resp.ExpiresUnixNano)
+		}
+		if earliest := before.Add(time.Duration(tt.validity) * time.Millisecond).UnixNano(); tt.validity > 0 && resp.ExpiresUnixNano < earliest {
+			t.Errorf("Expected expiry at least %d for %q, got %d", earliest, tt.client, resp.ExpiresUnixNano)
+		}
+	}
+}
+
+func TestGetModelInfo(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil)
+
+	info, err := h.GetModelInfo(context.Background(), &pb.ModelInfoRequest{})
+	if err != nil {
+		t.Fatalf("GetModelInfo failed: %v", err)
+	}
+	if len(info.AppendedExecutionProviders) != 1 || info.AppendedExecutionProviders[0] != "mock" || info.ActionDim != 2 {
+		t.Errorf("Unexpected model info: %+v", info)
+	}
+	if len(info.ResidentModels) != 0 {
+		t.Errorf("Expected no resident versions for a fixed engine, got %v", info.ResidentModels)
+	}
+	if info.RegistryModel != nil {
+		t.Errorf("Expected no registry model without a registry, got %v", info.RegistryModel)
+	}
+
+	registered := &pb.RegistryModel{Name: "policy", Stage: "Production", Version: "7"}
+	info, err = New(inference.NewMock(), nil, WithRegistryModel(registered)).GetModelInfo(context.Background(), &pb.ModelInfoRequest{})
+	if err != nil {
+		t.Fatalf("GetModelInfo failed: %v", err)
+	}
+	if info.RegistryModel.GetVersion() != "7" || info.RegistryModel.GetStage() != "Production" {
+		t.Errorf("Expected registry version 7 in Production, got %v", info.RegistryModel)
+	}
+
+	model := inference.NewSwappable(inference.NewMockWithAction([]float32{1, 2}), "v1")
+	if err := model.Swap(inference.NewMockWithAction([]float32{1, 2}), "v2", true); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+	info, err = New(model, nil).GetModelInfo(context.Background(), &pb.ModelInfoRequest{})
+	if err != nil {
+		t.Fatalf("GetModelInfo failed: %v", err)
+	}
+	if len(info.ResidentModels) != 2 || info.ResidentModels[0].Version != "v2" || !info.ResidentModels[0].Active || info.ResidentModels[1].Version != "v1" {
+		t.Errorf("Expected v2 serving with v1 spare, got %v", info.ResidentModels)
+	}
+
+	_, err = New(nil, nil).GetModelInfo(context.Background(), &pb.ModelInfoRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without an engine, got %v", err)
+	}
+}
+
+func TestPlan_ActionContractViolation(t *testing.T) {
+	contract := action.Contract{Dim: 2, Bounds: []action.Bound{{Min: -1, Max: 1}}}
+	mock := inference.NewMockWithAction([]float32{0.5, 3})
+	h := New(mock, nil, WithContract(contract))
+
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}
+	_, err := h.Plan(context.Background(), req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition for an out-of-bounds action, got %v", err)
+	}
+
+	// A model that drifted to a different action size is caught too
+	h = New(inference.NewMockWithAction([]float32{0.5, 0.5, 0.5}), nil, WithContract(contract))
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition for a wrong action size, got %v", err)
+	}
+
+	h = New(inference.NewMockWithAction([]float32{0.5, -1}), nil, WithContract(contract))
+	if _, err := h.Plan(context.Background(), req); err != nil {
+		t.Fatalf("Expected actions within the contract to pass, got %v", err)
+	}
+}
+
+func TestPlan_NonFiniteOutputs(t *testing.T) {
+	nan := float32(math.NaN())
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}
+
+	h := New(inference.NewMockWithAction([]float32{0.5, nan}), nil)
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.DataLoss {
+		t.Fatalf("Expected DataLoss for a NaN output, got %v", err)
+	}
+
+	// NaN logits are caught before argmax could hide them
+	d, err := action.NewDiscreteDecoder(action.ModeArgmax, [][]float32{{0, 0}, {1, 0}}, 1)
+	if err != nil {
+		t.Fatalf("NewDiscreteDecoder failed: %v", err)
+	}
+	h = New(inference.NewMockWithAction([]float32{nan, 1}), nil, WithActionDecoder(d))
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.DataLoss {
+		t.Fatalf("Expected DataLoss for NaN logits, got %v", err)
+	}
+
+	h = New(inference.NewMockWithAction([]float32{float32(math.Inf(1)), 0.5}), nil, WithNonFiniteSubstitute([]float32{0, 0}))
+	resp, err := h.Plan(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected the safe action to be substituted, got %v", err)
+	}
+	if !slices.Equal(resp.Action, []float32{0, 0}) {
+		t.Errorf("Expected the safe action [0 0], got %v", resp.Action)
+	}
+
+	h = New(inference.NewMockWithAction([]float32{nan, 0.5}), nil, WithNonFiniteSubstitute([]float32{0}))
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal for a substitute of the wrong size, got %v", err)
+	}
+}
+
+func TestGetCapabilities(t *testing.T) {
+	contract := action.Contract{Dim: 2, Bounds: []action.Bound{{Min: -1, Max: 1}}}
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil, WithContract(contract))
+
+	caps, err := h.GetCapabilities(context.Background(), &pb.CapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities failed: %v", err)
+	}
+	if caps.ActionDim != 2 || caps.ActionDtype != "float32" || caps.ActionMode != action.ModeContinuous {
+		t.Errorf("Unexpected capabilities: %+v", caps)
+	}
+	if len(caps.ActionBounds) != 1 || caps.ActionBounds[0].Min != -1 || caps.ActionBounds[0].Max != 1 {
+		t.Errorf("Unexpected action bounds: %v", caps.ActionBounds)
+	}
+}
+
+func TestActionSpace(t *testing.T) {
+	contract := action.Contract{Dim: 2, Bounds: []action.Bound{{Min: -1, Max: 1}}}
+	space, err := action.NewSpace(contract, nil, []string{"linear_x", "angular_z"}, nil)
+	if err != nil {
+		t.Fatalf("NewSpace failed: %v", err)
+	}
+	h := New(inference.NewMockWithAction([]float32{1, 0.5}), nil, WithContract(contract), WithActionSpace(space, true))
+
+	caps, err := h.GetCapabilities(context.Background(), &pb.CapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities failed: %v", err)
+	}
+	dims := caps.GetActionSpace().GetDims()
+	if caps.ActionSpace.GetKind() != action.SpaceContinuous || len(dims) != 2 || dims[1].Name != "angular_z" || dims[1].Bound.GetMax() != 1 {
+		t.Errorf("Unexpected action space: %v", caps.ActionSpace)
+	}
+
+	resp, err := h.Plan(context.Background(), &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(resp.GetActionSpace().GetDims()) != 2 {
+		t.Errorf("Expected the action space echoed in the response, got %v", resp.ActionSpace)
+	}
+}
+
+func TestPlanWithSafety(t *testing.T) {
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	plan := func(mode string) *pb.PlanResponse {
+		checker, err := safety.New(mode, []safety.Limit{{Min: -1, Max: 1}})
+		if err != nil {
+			t.Fatalf("safety.New failed: %v", err)
+		}
+		h := New(inference.NewMockWithAction([]float32{0.5, 3}), nil, WithSafety(checker))
+		resp, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: obs})
+		if err != nil {
+			t.Fatalf("Plan failed: %v", err)
+		}
+		return resp
+	}
+
+	if resp := plan(safety.ModeFlag); resp.Safe || resp.Action[1] != 3 {
+		t.Errorf("Expected the action flagged unsafe and unchanged, got %v", resp)
+	}
+	if resp := plan(safety.ModeClamp); !resp.Safe || resp.Action[0] != 0.5 || resp.Action[1] != 1 {
+		t.Errorf("Expected the action clamped to [0.5 1], got %v", resp)
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	mock := inference.NewMock()
+	h := New(mock, nil, WithWarmup(2, []int{1, 4}))
+
+	resp, err := h.Warmup(context.Background(), &pb.WarmupRequest{})
+	if err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if len(resp.Batches) != 2 || resp.Batches[1].BatchSize != 4 || resp.Batches[1].Inferences != 2 || mock.CallCount != 4 {
+		t.Errorf("Expected the configured warmup, got %v after %d inferences", resp.Batches, mock.CallCount)
+	}
+
+	resp, err = h.Warmup(context.Background(), &pb.WarmupRequest{Inferences: 1, BatchSizes: []uint32{16}})
+	if err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if len(resp.Batches) != 1 || resp.Batches[0].BatchSize != 16 {
+		t.Errorf("Expected the requested batch size, got %v", resp.Batches)
+	}
+
+	if _, err := h.Warmup(context.Background(), &pb.WarmupRequest{BatchSizes: []uint32{0}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for batch size 0, got %v", err)
+	}
+	if _, err := h.Warmup(context.Background(), &pb.WarmupRequest{Inferences: MaxWarmupInferences + 1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument past the inference limit, got %v", err)
+	}
+}
+
+func TestPlanAfterEngineClosed(t *testing.T) {
+	mock := inference.NewMock()
+	h := New(mock, nil)
+	mock.Close()
+
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs: &pb.Observation{
+			Data:     []float32{0.1, 0.2, 0.3, 0.4},
+			Channels: 1,
+			Height:   2,
+			Width:    2,
+		},
+	}
+
+	_, err := h.Plan(context.Background(), req)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable while shutting down, got %v", err)
+	}
+}
+
+func TestBatchPlanInputRange(t *testing.T) {
+	rules := []observation.RangeRule{{Channels: 1, Range: observation.Range{Min: 0, Max: 1, Tolerance: 0.1}}}
+	pixels := []float32{0.5, 255, 0, 1.05}
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: pixels, Channels: 1, Height: 2, Width: 2},
+	}
+
+	h := New(inference.NewMock(), nil, WithInputRanges(rules))
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for out-of-range observation, got %v", err)
+	}
+
+	clamp := []observation.RangeRule{{Range: observation.Range{Min: 0, Max: 1, Tolerance: 0.1, Clamp: true}}}
+	h = New(inference.NewMock(), nil, WithInputRanges(clamp))
+	if _, err := h.Plan(context.Background(), req); err != nil {
+		t.Fatalf("Expected clamped observation to be served, got %v", err)
+	}
+	if pixels[1] != 255 {
+		t.Errorf("Expected caller's observation to be left untouched, got %v", pixels)
+	}
+
+	// Rules for other layouts do not apply
+	h = New(inference.NewMock(), nil, WithInputRanges([]observation.RangeRule{{Channels: 3, Range: rules[0].Range}}))
+	if _, err := h.Plan(context.Background(), req); err != nil {
+		t.Errorf("Expected no range check for another layout, got %v", err)
+	}
+}
+
+func TestForEachParallel_ReportsLowestFailure(t *testing.T) {
+	var checked atomic.Int64
+	err := forEachParallel(1000, 8, func(i int) error {
+		checked.Add(1)
+		if i == 130 || i == 620 || i >= 900 {
+			return fmt.Errorf("observation %d", i)
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "observation 130" {
+		t.Fatalf("Expected the lowest failing index, got %v", err)
+	}
+	// Workers past the failure stop early
+	if n := checked.Load(); n == 1000 {
+		t.Errorf("Expected later observations to be skipped after a failure, got %d checked", n)
+	}
+
+	if err := forEachParallel(1000, 8, func(int) error { return nil }); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestBatchPlanWithParallelValidation(t *testing.T) {
+	h := New(inference.NewMock(), nil, WithParallelValidation(4, 16))
+
+	requests := make([]*pb.PlanRequest, 64)
+	for i := range requests {
+		requests[i] = &pb.PlanRequest{
+			RobotId: uint64(i),
+			Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+		}
+	}
+	resp, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{Requests: requests})
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if len(resp.Responses) != len(requests) {
+		t.Fatalf("Expected %d responses, got %d", len(requests), len(resp.Responses))
+	}
+
+	// The first bad observation is reported, as when validating in order
+	requests[50] = &pb.PlanRequest{RobotId: 50, Obs: &pb.Observation{Data: []float32{0.1}, Channels: 1, Height: 2, Width: 2}}
+	requests[40] = &pb.PlanRequest{RobotId: 40, Obs: &pb.Observation{Data: make([]float32, 8), Channels: 2, Height: 2, Width: 2}}
+	_, err = h.BatchPlan(context.Background(), &pb.BatchPlanRequest{Requests: requests})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	if !strings.Contains(status.Convert(err).Message(), "observation 40 has mismatched dimensions") {
+		t.Errorf("Expected error for observation 40, got %v", err)
+	}
+}
+
+// BenchmarkBatchValidation validates a 1024-observation batch of 3x64x64
+// images against an input range, as BatchPlan does, in order and on 2 to 8
+// workers; run with -cpu to compare
+func BenchmarkBatchValidation(b *testing.B) {
+	r := observation.Range{Min: 0, Max: 1}
+	batch := make([][]float32, 1024)
+	for i := range batch {
+		batch[i] = make([]float32, 3*64*64)
+		for j := range batch[i] {
+			batch[i][j] = float32(j%256) / 255
+		}
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			h := New(inference.NewMock(), nil, WithParallelValidation(workers, 1))
+			for n := 0; n < b.N; n++ {
+				err := h.forEachObservation(len(batch), func(i int) error {
+					if len(batch[i]) != 3*64*64 {
+						return fmt.Errorf("observation %d has wrong data length", i)
+					}
+					if outliers, _ := r.Check(batch[i]); outliers > 0 {
+						return fmt.Errorf("observation %d is out of range", i)
+					}
+					return nil
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// obsRecorder records the observations of the last Predict call
+type obsRecorder struct {
+	*inference.MockInference
+	obs [][]float32
+}
+
+func (r *obsRecorder) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	r.obs = obsBatch
+	return r.MockInference.Predict(obsBatch, c, h, w)
+}
+
+func TestBatchPlanWithUint8Observations(t *testing.T) {
+	engine := &obsRecorder{MockInference: inference.NewMock()}
+	norm := observation.Normalization{Scale: 1.0 / 255, Mean: []float32{0.5}, Std: []float32{0.5}}
+	h := New(engine, nil, WithCodecs(codec.Builtin(norm)))
+
+	frame := &pb.Observation{Uint8Data: []byte{0, 255, 51, 204}, Channels: 1, Height: 2, Width: 2}
+	req := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{
+		{RobotId: 1, Obs: frame},
+		{RobotId: 2, Obs: &pb.Observation{Data: []float32{0, 1, 0, 1}, Channels: 1, Height: 2, Width: 2}},
+	}}
+	resp, err := h.BatchPlan(context.Background(), req)
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+
+	want := []float32{-1, 1, -0.6, 0.6}
+	for i, v := range engine.obs[0] {
+		if math.Abs(float64(v-want[i])) > 1e-6 {
+			t.Fatalf("Expected normalized observation %v, got %v", want, engine.obs[0])
+		}
+	}
+	if frame.Data != nil {
+		t.Error("Expected caller's observation to be left untouched")
+	}
+	if resp.Responses[0].ObsHash != observation.Hash(frame) {
+		t.Errorf("Expected the hash of the uint8 data, got %s", resp.Responses[0].ObsHash)
+	}
+
+	// Both encodings, or a length that does not match the dimensions
+	for _, obs := range []*pb.Observation{
+		{Data: []float32{0, 0, 0, 0}, Uint8Data: []byte{0, 0, 0, 0}, Channels: 1, Height: 2, Width: 2},
+		{Uint8Data: []byte{0, 0, 0}, Channels: 1, Height: 2, Width: 2},
+	} {
+		_, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: obs})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	}
+}
+
+// doubleCodec is a site-specific codec sending each value as one signed byte
+// of twice its value
+type doubleCodec struct{}
+
+func (doubleCodec) Name() string { return "half-int8" }
+
+func (doubleCodec) Decode(data []byte, c, h, w int) ([]float32, error) {
+	out := make([]float32, len(data))
+	for i, b := range data {
+		out[i] = float32(int8(b)) / 2
+	}
+	return out, nil
+}
+
+func TestBatchPlanWithEncodedObservations(t *testing.T) {
+	engine := &obsRecorder{MockInference: inference.NewMock()}
+	codecs := codec.Builtin(observation.DefaultNormalization)
+	if err := codecs.Register(codec.MinCustomID, doubleCodec{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	h := New(engine, nil, WithCodecs(codecs))
+
+	// float16 1, -2, 0.5, 0 and the custom codec's 1, -1, 0.5, 0
+	fp16 := []byte{0x00, 0x3c, 0x00, 0xc0, 0x00, 0x38, 0x00, 0x00}
+	custom := []byte{2, 0xfe, 1, 0}
+	req := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{
+		{RobotId: 1, Obs: &pb.Observation{Encoding: codec.IDFloat16, EncodedData: fp16, Channels: 1, Height: 2, Width: 2}},
+		{RobotId: 2, Obs: &pb.Observation{Encoding: codec.MinCustomID, EncodedData: custom, Channels: 1, Height: 2, Width: 2}},
+	}}
+	if _, err := h.BatchPlan(context.Background(), req); err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	want := [][]float32{{1, -2, 0.5, 0}, {1, -1, 0.5, 0}}
+	for i := range want {
+		if !slices.Equal(engine.obs[i], want[i]) {
+			t.Errorf("Expected observation %d decoded to %v, got %v", i, want[i], engine.obs[i])
+		}
+	}
+
+	_, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: &pb.Observation{Encoding: 99, EncodedData: custom, Channels: 1, Height: 2, Width: 2}})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "unknown observation encoding 99") {
+		t.Errorf("Expected InvalidArgument for an unregistered encoding, got %v", err)
+	}
+
+	caps, err := h.GetCapabilities(context.Background(), &pb.CapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities failed: %v", err)
+	}
+	var names []string
+	for _, e := range caps.ObservationEncodings {
+		names = append(names, fmt.Sprintf("%d:%s", e.Id, e.Name))
+	}
+	if got := strings.Join(names, ","); got != "1:float32,2:float16,3:uint8,4:jpeg,128:half-int8" {
+		t.Errorf("Expected registered encodings in capabilities, got %s", got)
+	}
+}
+
+func TestBatchPlanWithPreprocess(t *testing.T) {
+	engine := &obsRecorder{MockInference: inference.NewMock()}
+	pipeline := preprocess.Pipeline{
+		preprocess.Crop{Height: 2, Width: 2, Center: true},
+		preprocess.ChannelOrder{Order: []int{1, 0}},
+		preprocess.Normalize{Mean: []float32{1}},
+	}
+	h := New(engine, nil, WithPreprocess([]preprocess.Rule{{Channels: 2, Pipeline: pipeline}}))
+
+	// Two 4x4 channels of 0s and 1s, cropped to 2x2, swapped, and shifted by -1
+	data := make([]float32, 2*4*4)
+	for i := 16; i < 32; i++ {
+		data[i] = 1
+	}
+	req := &pb.PlanRequest{RobotId: 1, Obs: &pb.Observation{Data: data, Channels: 2, Height: 4, Width: 4}}
+	if _, err := h.Plan(context.Background(), req); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	want := []float32{0, 0, 0, 0, -1, -1, -1, -1}
+	if !slices.Equal(engine.obs[0], want) {
+		t.Errorf("Expected preprocessed observation %v, got %v", want, engine.obs[0])
+	}
+	if data[0] != 0 || data[16] != 1 {
+		t.Error("Expected caller's observation to be left untouched")
+	}
+
+	// Observations the pipeline cannot apply to fail before inference
+	small := &pb.Observation{Data: make([]float32, 2), Channels: 2, Height: 1, Width: 1}
+	if _, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: small}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	// Other layouts are not preprocessed
+	state := &pb.Observation{Data: []float32{1, 2, 3, 4}, Channels: 1, Height: 1, Width: 4}
+	if _, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 2, Obs: state}); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if !slices.Equal(engine.obs[0], state.Data) {
+		t.Errorf("Expected observation as sent, got %v", engine.obs[0])
+	}
+}
+
+// shapeRecorder records the observation shape of the last Predict call
+type shapeRecorder struct {
+	*inference.MockInference
+	height, width int64
+}
+
+func (r *shapeRecorder) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	r.height, r.width = h, w
+	return r.MockInference.Predict(obsBatch, c, h, w)
+}
+
+func TestBatchPlanDownsampling(t *testing.T) {
+	engine := &shapeRecorder{MockInference: inference.NewMock()}
+	degraded := false
+	h := New(engine, nil, WithDownsampling(2, 4, func() bool { return degraded }), WithDedup(dedup.New(time.Minute)))
+
+	image := &pb.Observation{Data: make([]float32, 3*8*6), Channels: 3, Height: 8, Width: 6}
+	small := &pb.Observation{Data: make([]float32, 1*2*8), Channels: 1, Height: 2, Width: 8}
+
+	resp, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: image})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if resp.DegradedResolution || engine.height != 8 || engine.width != 6 {
+		t.Fatalf("Expected full resolution while healthy, got %dx%d (degraded=%t)", engine.height, engine.width, resp.DegradedResolution)
+	}
+
+	degraded = true
+	resp, err = h.Plan(context.Background(), &pb.PlanRequest{RobotId: 2, Obs: image})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if !resp.DegradedResolution || engine.height != 4 || engine.width != 3 {
+		t.Fatalf("Expected 4x3 degraded-resolution plan, got %dx%d (degraded=%t)", engine.height, engine.width, resp.DegradedResolution)
+	}
+	if resp.ObsHash != observation.Hash(image) {
+		t.Errorf("Expected the obs hash of the observation as sent")
+	}
+
+	// Observations below the minimum size keep their resolution
+	resp, err = h.Plan(context.Background(), &pb.PlanRequest{RobotId: 3, Obs: small})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if resp.DegradedResolution || engine.height != 2 {
+		t.Fatalf("Expected small observation at full resolution, got %dx%d", engine.height, engine.width)
+	}
+
+	// Degraded actions are not reused once the service recovers
+	degraded = false
+	calls := engine.CallCount
+	resp, err = h.Plan(context.Background(), &pb.PlanRequest{RobotId: 2, Obs: image})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if engine.CallCount != calls+1 || resp.DegradedResolution {
+		t.Fatalf("Expected a fresh full-resolution plan, got degraded=%t after %d calls", resp.DegradedResolution, engine.CallCount-calls)
+	}
+}
+
+func TestBatchPlanWithPoseCache(t *testing.T) {
+	ctx := middleware.WithTenantID(context.Background(), "acme")
+	c := cache.NewLocal(100)
+	obs := &pb.Observation{Data: []float32{0.1, 0.2}, Channels: 1, Height: 1, Width: 2}
+
+	// Returned in the response: nothing on the first call, then the pose sent
+	h := New(inference.NewMock(), c, WithPoseCache(PoseResponse, time.Minute))
+	resp, err := h.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: obs, Pose: &pb.Pose{X: 1, Y: 2, Theta: 0.5}})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if resp.LastPose != nil {
+		t.Fatalf("Expected no last pose on the first call, got %v", resp.LastPose)
+	}
+	resp, err = h.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: obs})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if resp.LastPose.GetY() != 2 || resp.LastPose.GetTheta() != 0.5 {
+		t.Fatalf("Expected the last pose sent, got %v", resp.LastPose)
+	}
+	if resp.LastPose.GetTimestampUnixNano() == 0 {
+		t.Error("Expected a pose sent without a timestamp to be stamped on receipt")
+	}
+
+	// Poses are kept per tenant
+	resp, err = h.Plan(middleware.WithTenantID(context.Background(), "globex"), &pb.PlanRequest{RobotId: 1, Obs: obs})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if resp.LastPose != nil {
+		t.Errorf("Expected no last pose for another tenant, got %v", resp.LastPose)
+	}
+
+	// Appended to the observation, zeros when none is cached
+	engine := &shapeRecorder{MockInference: inference.NewMock()}
+	h = New(engine, c, WithPoseCache(PoseObservation, time.Minute))
+	if _, err := h.Plan(ctx, &pb.PlanRequest{RobotId: 2, Obs: obs}); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if engine.width != 6 {
+		t.Fatalf("Expected the observation widened to 6, got %d", engine.width)
+	}
+	if len(obs.Data) != 2 {
+		t.Errorf("Expected the caller's observation to be left untouched, got %v", obs.Data)
+	}
+	image := &pb.Observation{Data: make([]float32, 4), Channels: 1, Height: 2, Width: 2}
+	if _, err := h.Plan(ctx, &pb.PlanRequest{RobotId: 2, Obs: image}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an image observation, got %v", err)
+	}
+}
+
+func TestBatchPlanMaxTensorBytes(t *testing.T) {
+	mock := inference.NewMock()
+	h := New(mock, nil, WithMaxTensorBytes(64))
+
+	// 2 observations of 2x2 floats pack into 32 bytes
+	small := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	if _, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{
+		Requests: []*pb.PlanRequest{{RobotId: 1, Obs: small}, {RobotId: 2, Obs: small}},
+	}); err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if high := h.tensorHighWater.Load(); high != 32 {
+		t.Errorf("Expected a high-water mark of 32 bytes, got %d", high)
+	}
+	if inflight := h.tensorBytes.Load(); inflight != 0 {
+		t.Errorf("Expected no tensor bytes held after the call, got %d", inflight)
+	}
+
+	large := &pb.Observation{Data: make([]float32, 3*4*4), Channels: 3, Height: 4, Width: 4}
+	_, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 3, Obs: large})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted for a 192-byte tensor, got %v", err)
+	}
+	if mock.CallCount != 1 {
+		t.Errorf("Expected no inference for the rejected request, got %d calls", mock.CallCount)
+	}
+}
+
+func TestBatchPlanMaxBatchSize(t *testing.T) {
+	mock := inference.NewMock()
+	h := New(mock, nil, WithMaxBatchSize(2))
+
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	requests := []*pb.PlanRequest{{RobotId: 1, Obs: obs}, {RobotId: 2, Obs: obs}, {RobotId: 3, Obs: obs}}
+	_, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{Requests: requests})
+	if status.Code(err) != codes.ResourceExhausted || !strings.Contains(err.Error(), "limit of 2") {
+		t.Fatalf("Expected ResourceExhausted naming the limit, got %v", err)
+	}
+	if mock.CallCount != 0 {
+		t.Errorf("Expected no inference for the rejected batch, got %d calls", mock.CallCount)
+	}
+	if _, err := h.BatchPlan(context.Background(), &pb.BatchPlanRequest{Requests: requests[:2]}); err != nil {
+		t.Fatalf("Expected a batch at the limit to be planned, got %v", err)
+	}
+
+	// Streams are chunked within the limit instead
+	stream := &chunkStream{ctx: context.Background()}
+	if err := h.BatchPlanStream(&pb.BatchPlanStreamRequest{Requests: requests, ChunkSize: 3}, stream); err != nil {
+		t.Fatalf("BatchPlanStream failed: %v", err)
+	}
+	if len(stream.chunks) != 2 {
+		t.Errorf("Expected 2 chunks of at most 2 requests, got %d", len(stream.chunks))
+	}
+}
+
+// chunkStream captures the chunks sent by BatchPlanStream
+type chunkStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*pb.BatchPlanChunk
+}
+
+func (s *chunkStream) Context() context.Context { return s.ctx }
+
+func (s *chunkStream) Send(chunk *pb.BatchPlanChunk) error {
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func TestBatchPlanStream(t *testing.T) {
+	mock := inference.NewMockWithAction([]float32{1, 2})
+	h := New(mock, nil, WithStreamChunks(2, 2))
+
+	req := &pb.BatchPlanStreamRequest{}
+	for i := 0; i < 5; i++ {
+		req.Requests = append(req.Requests, &pb.PlanRequest{
+			RobotId: uint64(i),
+			Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+		})
+	}
+
+	stream := &chunkStream{ctx: context.Background()}
+	if err := h.BatchPlanStream(req, stream); err != nil {
+		t.Fatalf("BatchPlanStream failed: %v", err)
+	}
+	if len(stream.chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(stream.chunks))
+	}
+	covered := make([]bool, len(req.Requests))
+	for _, chunk := range stream.chunks {
+		for j, resp := range chunk.Responses {
+			covered[int(chunk.Offset)+j] = true
+			if len(resp.Action) != 2 {
+				t.Errorf("Expected 2 action values, got %v", resp.Action)
+			}
+		}
+	}
+	for i, ok := range covered {
+		if !ok {
+			t.Errorf("Expected a response for request %d", i)
+		}
+	}
+
+	// A cancelled call runs no chunks
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := mock.CallCount
+	err := h.BatchPlanStream(req, &chunkStream{ctx: ctx})
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled, got %v", err)
+	}
+	if mock.CallCount != calls {
+		t.Errorf("Expected no inference after cancel, got %d calls", mock.CallCount-calls)
+	}
+}
+
+// planStream captures the plans sent by PlanStream, calling onSend after each
+type planStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	plans  []*pb.PlanResponse
+	onSend func(n int)
+}
+
+func (s *planStream) Context() context.Context { return s.ctx }
+
+func (s *planStream) Send(resp *pb.PlanResponse) error {
+	s.plans = append(s.plans, resp)
+	s.onSend(len(s.plans))
+	return nil
+}
+
+func TestPlanStream(t *testing.T) {
+	c := cache.NewLocal(16)
+	h := New(inference.NewMockWithAction([]float32{1, 2}), c,
+		WithPoseCache(PoseResponse, time.Minute), WithPlanStreamInterval(time.Second, time.Millisecond))
+	req := &pb.PlanStreamRequest{
+		Request: &pb.PlanRequest{
+			RobotId: 7,
+			Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+			Pose:    &pb.Pose{X: 1},
+		},
+		IntervalMs: 1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tenant, _ := c.ForTenant(middleware.GetTenantID(ctx))
+	stream := &planStream{ctx: ctx, onSend: func(n int) {
+		switch n {
+		case 1:
+			// A pose reported elsewhere after the first plan
+			if err := tenant.SetPose(7, &pb.Pose{X: 5}, time.Minute); err != nil {
+				t.Errorf("SetPose failed: %v", err)
+			}
+		case 3:
+			cancel()
+		}
+	}}
+	if err := h.PlanStream(req, stream); status.Code(err) != codes.Canceled {
+		t.Fatalf("Expected the stream to end Canceled, got %v", err)
+	}
+	if len(stream.plans) != 3 {
+		t.Fatalf("Expected 3 plans, got %d", len(stream.plans))
+	}
+	if len(stream.plans[2].Action) != 2 {
+		t.Errorf("Expected 2 action values, got %v", stream.plans[2].Action)
+	}
+	// The request's pose is cached once, so later plans see the newer pose
+	if pose := stream.plans[2].LastPose; pose.GetX() != 5 {
+		t.Errorf("Expected the latest cached pose, got %v", pose)
+	}
+
+	if err := h.PlanStream(&pb.PlanStreamRequest{}, stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without a request, got %v", err)
+	}
+}
+
+// bidiStream delivers its requests in order, then io.EOF, and captures the
+// responses sent
+type bidiStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  []*pb.StreamPlanRequest
+	responses []*pb.StreamPlanResponse
+}
+
+func (s *bidiStream) Context() context.Context { return s.ctx }
+
+func (s *bidiStream) Recv() (*pb.StreamPlanRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *bidiStream) Send(resp *pb.StreamPlanResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func TestStreamPlan(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil)
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	stream := &bidiStream{
+		ctx: middleware.WithRequestID(context.Background(), "stream-1"),
+		requests: []*pb.StreamPlanRequest{
+			{Seq: 10, Request: &pb.PlanRequest{RobotId: 7, Obs: obs}},
+			{Seq: 11, Request: &pb.PlanRequest{RobotId: 7, Obs: &pb.Observation{Data: []float32{0.1}, Channels: 1, Height: 2, Width: 2}}},
+			{Seq: 12, Request: &pb.PlanRequest{RobotId: 7, Obs: obs}},
+		},
+	}
+	if err := h.StreamPlan(stream); err != nil {
+		t.Fatalf("StreamPlan failed: %v", err)
+	}
+	if len(stream.responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(stream.responses))
+	}
+	for i, resp := range stream.responses {
+		if resp.Seq != uint64(10+i) || resp.RequestId != fmt.Sprintf("stream-1-%d", i+1) {
+			t.Errorf("Expected seq %d with request ID stream-1-%d, got %d and %q", 10+i, i+1, resp.Seq, resp.RequestId)
+		}
+	}
+	if len(stream.responses[0].Response.GetAction()) != 2 || stream.responses[0].ErrorCode != 0 {
+		t.Errorf("Expected an action for the first message, got %v", stream.responses[0])
+	}
+	// A bad observation fails its message only
+	if bad := stream.responses[1]; codes.Code(bad.ErrorCode) != codes.InvalidArgument || bad.Response != nil {
+		t.Errorf("Expected InvalidArgument for the second message, got %v", bad)
+	}
+	if len(stream.responses[2].Response.GetAction()) != 2 {
+		t.Errorf("Expected the stream to continue after a failed message, got %v", stream.responses[2])
+	}
+}
+
+// clientStream delivers its requests in order, then io.EOF, and captures the
+// response closing the call
+type clientStream struct {
+	grpc.ServerStream
+	requests []*pb.PlanRequest
+	response *pb.BatchPlanResponse
+}
+
+func (s *clientStream) Context() context.Context { return context.Background() }
+
+func (s *clientStream) Recv() (*pb.PlanRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *clientStream) SendAndClose(resp *pb.BatchPlanResponse) error {
+	s.response = resp
+	return nil
+}
+
+func TestAggregatePlan(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil, WithAggregation(2, 5))
+	requests := make([]*pb.PlanRequest, 5)
+	for i := range requests {
+		requests[i] = &pb.PlanRequest{
+			RobotId: uint64(i),
+			Obs:     &pb.Observation{Data: []float32{float32(i), 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+		}
+	}
+
+	stream := &clientStream{requests: slices.Clone(requests)}
+	if err := h.AggregatePlan(stream); err != nil {
+		t.Fatalf("AggregatePlan failed: %v", err)
+	}
+	if len(stream.response.GetResponses()) != 5 {
+		t.Fatalf("Expected 5 responses, got %d", len(stream.response.GetResponses()))
+	}
+	// Responses of the three batches are returned in request order
+	for i, resp := range stream.response.Responses {
+		if want := observation.Hash(requests[i].Obs); resp.ObsHash != want {
+			t.Errorf("Expected response %d for observation %s, got %s", i, want, resp.ObsHash)
+		}
+	}
+
+	stream = &clientStream{requests: append(slices.Clone(requests), requests[0])}
+	if err := h.AggregatePlan(stream); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted past the request limit, got %v", err)
+	}
+	if err := h.AggregatePlan(&clientStream{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without requests, got %v", err)
+	}
+}
+
+func TestPlanWithBatcher(t *testing.T) {
+	mock := inference.NewMockWithAction([]float32{1, 2})
+	h := New(mock, nil, WithBatcher(batching.New(mock, 10*time.Second, 3, 0)))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = h.Plan(context.Background(), &pb.PlanRequest{
+				RobotId: uint64(i),
+				Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Plan %d failed: %v", i, err)
+		}
+	}
+	if mock.CallCount != 1 {
+		t.Errorf("Expected concurrent Plans to share 1 inference call, got %d", mock.CallCount)
+	}
+}
+
+// resultStream captures the results sent by WatchPlanResults
+type resultStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	results []*pb.PlanResult
+}
+
+func (s *resultStream) Context() context.Context { return s.ctx }
+
+func (s *resultStream) Send(result *pb.PlanResult) error {
+	s.results = append(s.results, result)
+	return nil
+}
+
+func TestPlanAsync(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil, WithAsync(1, 2, time.Minute))
+	ctx := middleware.WithTenantID(context.Background(), "analytics")
+	batch := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}}}
+
+	// Queued before the workers start, so the result is pending
+	ticket, err := h.PlanAsync(ctx, batch)
+	if err != nil {
+		t.Fatalf("PlanAsync failed: %v", err)
+	}
+	result, err := h.GetPlanResult(ctx, ticket)
+	if err != nil || result.State != pb.PlanState_PLAN_STATE_PENDING {
+		t.Fatalf("Expected a pending result, got %v (%v)", result, err)
+	}
+
+	// Other tenants cannot read the ticket
+	other := middleware.WithTenantID(context.Background(), "robots")
+	if _, err := h.GetPlanResult(other, ticket); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for another tenant, got %v", err)
+	}
+
+	// The queue holds two jobs
+	second, err := h.PlanAsync(ctx, batch)
+	if err != nil {
+		t.Fatalf("PlanAsync failed: %v", err)
+	}
+	if _, err := h.PlanAsync(ctx, batch); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted with a full queue, got %v", err)
+	}
+
+	runCtx, stop := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		h.RunAsync(runCtx)
+		close(stopped)
+	}()
+
+	stream := &resultStream{ctx: ctx}
+	req := &pb.WatchPlanResultsRequest{Tickets: []string{ticket.Ticket, second.Ticket}}
+	if err := h.WatchPlanResults(req, stream); err != nil {
+		t.Fatalf("WatchPlanResults failed: %v", err)
+	}
+	if len(stream.results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(stream.results))
+	}
+	for _, r := range stream.results {
+		if r.State != pb.PlanState_PLAN_STATE_DONE || len(r.Response.Responses[0].Action) != 2 {
+			t.Errorf("Unexpected result: %v", r)
+		}
+	}
+
+	stop()
+	<-stopped
+	if _, err := h.PlanAsync(ctx, batch); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after shutdown, got %v", err)
+	}
+}
+
+func TestPlanAsync_Owner(t *testing.T) {
+	h := New(inference.NewMock(), nil, WithAsync(1, 2, time.Minute))
+	header := middleware.WithTenantID(context.Background(), "analytics")
+	batch := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}}}
+
+	// With authentication, tickets belong to the subject that created them,
+	// whatever tenant the header names
+	owner := auth.WithIdentity(header, auth.Identity{Subject: "analytics/etl", Tenant: "analytics"})
+	ticket, err := h.PlanAsync(owner, batch)
+	if err != nil {
+		t.Fatalf("PlanAsync failed: %v", err)
+	}
+	if _, err := h.GetPlanResult(owner, ticket); err != nil {
+		t.Errorf("Expected the owner to read its ticket, got %v", err)
+	}
+	others := map[string]context.Context{
+		"another subject":       auth.WithIdentity(header, auth.Identity{Subject: "analytics/dashboard", Tenant: "analytics"}),
+		"the header's tenant":   header,
+		"the subject elsewhere": auth.WithIdentity(header, auth.Identity{Subject: "analytics/etl", Tenant: "robots"}),
+	}
+	for name, ctx := range others {
+		if _, err := h.GetPlanResult(ctx, ticket); status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound for %s, got %v", name, err)
+		}
+	}
+}
+
+func TestPlanAsync_NotEnabled(t *testing.T) {
+	h := New(inference.NewMock(), nil)
+	if _, err := h.PlanAsync(context.Background(), &pb.BatchPlanRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without async, got %v", err)
+	}
+}
+
+func TestPlanUpdates(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil, WithPlanUpdates(20*time.Millisecond, time.Second, time.Minute))
+	ctx := middleware.WithTenantID(context.Background(), "acme")
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+
+	if _, err := h.GetPlanUpdates(ctx, &pb.PlanUpdatesRequest{RobotId: 7}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a first poll without an observation, got %v", err)
+	}
+
+	runCtx, stop := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		h.RunPlanUpdates(runCtx)
+		close(stopped)
+	}()
+
+	// The first poll waits for the next scheduled run
+	first, err := h.GetPlanUpdates(ctx, &pb.PlanUpdatesRequest{RobotId: 7, Obs: obs})
+	if err != nil {
+		t.Fatalf("GetPlanUpdates failed: %v", err)
+	}
+	if first.Response == nil || len(first.Response.Action) != 2 {
+		t.Fatalf("Expected a plan for robot 7, got %v", first)
+	}
+	if first.PlannedUnixNano == 0 || first.NextPlanUnixNano <= first.PlannedUnixNano {
+		t.Errorf("Expected the next run after the plan, got planned %d and next %d", first.PlannedUnixNano, first.NextPlanUnixNano)
+	}
+
+	// Later polls keep the observation and wait for a newer plan
+	second, err := h.GetPlanUpdates(ctx, &pb.PlanUpdatesRequest{RobotId: 7, AfterUnixNano: first.PlannedUnixNano})
+	if err != nil {
+		t.Fatalf("GetPlanUpdates failed: %v", err)
+	}
+	if second.Response == nil || second.PlannedUnixNano <= first.PlannedUnixNano {
+		t.Errorf("Expected a newer plan, got %v", second)
+	}
+
+	// Robots of other tenants are scheduled separately
+	other := middleware.WithTenantID(context.Background(), "globex")
+	if _, err := h.GetPlanUpdates(other, &pb.PlanUpdatesRequest{RobotId: 7}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for another tenant's first poll, got %v", err)
+	}
+
+	stop()
+	<-stopped
+	if _, err := h.GetPlanUpdates(ctx, &pb.PlanUpdatesRequest{RobotId: 7, AfterUnixNano: time.Now().UnixNano()}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after shutdown, got %v", err)
+	}
+}
+
+func TestPlanUpdates_Timeout(t *testing.T) {
+	h := New(inference.NewMock(), nil, WithPlanUpdates(time.Hour, 10*time.Millisecond, time.Hour))
+	obs := &pb.Observation{Data: []float32{0.1, 0.2}, Channels: 1, Height: 1, Width: 2}
+	update, err := h.GetPlanUpdates(context.Background(), &pb.PlanUpdatesRequest{RobotId: 1, Obs: obs})
+	if err != nil {
+		t.Fatalf("GetPlanUpdates failed: %v", err)
+	}
+	if update.Response != nil {
+		t.Errorf("Expected no plan before the first run, got %v", update.Response)
+	}
+}
+
+func TestPlanUpdates_NotEnabled(t *testing.T) {
+	h := New(inference.NewMock(), nil)
+	if _, err := h.GetPlanUpdates(context.Background(), &pb.PlanUpdatesRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without plan updates, got %v", err)
+	}
+}
+
+func TestPlanUpdates_SnapshotRestore(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{1, 2}), nil, WithPlanUpdates(20*time.Millisecond, time.Second, time.Minute))
+	ctx := middleware.WithTenantID(context.Background(), "acme")
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+
+	runCtx, stop := context.WithCancel(context.Background())
+	go h.RunPlanUpdates(runCtx)
+	first, err := h.GetPlanUpdates(ctx, &pb.PlanUpdatesRequest{RobotId: 7, Obs: obs})
+	stop()
+	if err != nil {
+		t.Fatalf("GetPlanUpdates failed: %v", err)
+	}
+
+	sources := h.StateSources()
+	if len(sources) != 1 {
+		t.Fatalf("Expected the plan schedule as the only state source, got %d", len(sources))
+	}
+	data, err := sources[0].Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	restored := New(inference.NewMock(), nil, WithPlanUpdates(time.Hour, 10*time.Millisecond, time.Minute))
+	if err := restored.StateSources()[0].Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	// The restored robot polls without an observation and gets its last plan
+	update, err := restored.GetPlanUpdates(ctx, &pb.PlanUpdatesRequest{RobotId: 7})
+	if err != nil {
+		t.Fatalf("GetPlanUpdates after restore failed: %v", err)
+	}
+	if update.PlannedUnixNano != first.PlannedUnixNano || len(update.Response.GetAction()) != 2 {
+		t.Errorf("Expected the plan made at %d, got %v", first.PlannedUnixNano, update)
+	}
+}
+
+func TestTwoPhaseCommit(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{0.5, -0.5}), nil,
+		WithTwoPhaseCommit(50*time.Millisecond, time.Minute, []string{"spiffe://example.org/robot/arm/*"}, "supervisor"))
+	req := &pb.PlanRequest{RobotId: 7, Obs: &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}}
+	robot := middleware.WithTenantID(middleware.WithClientIdentity(context.Background(), "spiffe://example.org/robot/arm/1"), "acme")
+	supervisor := auth.WithIdentity(middleware.WithTenantID(context.Background(), "acme"), auth.Identity{Roles: []string{"supervisor"}})
+
+	// Clients not listed act without a commit
+	resp, err := h.Plan(middleware.WithClientIdentity(context.Background(), "spiffe://example.org/robot/agv/1"), req)
+	if err != nil || resp.CommitToken != "" {
+		t.Fatalf("Expected no commit token for an unlisted client, got %q (err %v)", resp.GetCommitToken(), err)
+	}
+
+	// An unvetoed proposal commits once the window has passed
+	resp, err = h.Plan(robot, req)
+	if err != nil || resp.CommitToken == "" {
+		t.Fatalf("Expected a commit token, got %q (err %v)", resp.GetCommitToken(), err)
+	}
+	start := time.Now()
+	commit, err := h.CommitPlan(robot, &pb.CommitPlanRequest{Token: resp.CommitToken})
+	if err != nil || !commit.Committed || len(commit.Action) != 2 {
+		t.Fatalf("Expected the action to commit, got %v (err %v)", commit, err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected CommitPlan to wait out the veto window, returned after %s", elapsed)
+	}
+	if veto, _ := h.VetoPlan(supervisor, &pb.VetoPlanRequest{Token: resp.CommitToken}); veto.Vetoed {
+		t.Error("Expected a committed proposal not to be vetoed")
+	}
+
+	// A veto within the window ends a pending commit at once
+	resp, _ = h.Plan(robot, req)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		h.VetoPlan(supervisor, &pb.VetoPlanRequest{Token: resp.CommitToken, Reason: "human in cell"})
+	}()
+	commit, err = h.CommitPlan(robot, &pb.CommitPlanRequest{Token: resp.CommitToken})
+	if err != nil || commit.Committed || commit.VetoReason != "human in cell" || len(commit.Action) != 0 {
+		t.Fatalf("Expected the veto, got %v (err %v)", commit, err)
+	}
+
+	// Only supervisors of the same tenant may veto
+	resp, _ = h.Plan(robot, req)
+	robotID := auth.WithIdentity(robot, auth.Identity{Roles: []string{"robot"}})
+	if _, err := h.VetoPlan(robotID, &pb.VetoPlanRequest{Token: resp.CommitToken}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied without the supervisor role, got %v", err)
+	}
+	other := auth.WithIdentity(middleware.WithTenantID(context.Background(), "globex"), auth.Identity{Roles: []string{"supervisor"}})
+	if _, err := h.VetoPlan(other, &pb.VetoPlanRequest{Token: resp.CommitToken}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for another tenant, got %v", err)
+	}
+	if _, err := h.CommitPlan(robot, &pb.CommitPlanRequest{Token: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown token, got %v", err)
+	}
+}
+
+func TestTwoPhaseCommit_NotEnabled(t *testing.T) {
+	h := New(inference.NewMock(), nil)
+	if _, err := h.CommitPlan(context.Background(), &pb.CommitPlanRequest{Token: "t"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without two-phase commit, got %v", err)
+	}
+}
+
+// fakeUpstream plans every observation with a fixed action, recording the
+// metadata of each call
+type fakeUpstream struct {
+	pb.PathPlannerClient
+	calls []*pb.BatchPlanRequest
+	md    []metadata.MD
+}
+
+func (f *fakeUpstream) BatchPlan(ctx context.Context, req *pb.BatchPlanRequest, opts ...grpc.CallOption) (*pb.BatchPlanResponse, error) {
+	f.calls = append(f.calls, req)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.md = append(f.md, md)
+	for _, o := range opts {
+		if t, ok := o.(grpc.TrailerCallOption); ok {
+			*t.TrailerAddr = metadata.Pairs(UpstreamHopsTrailer, "1")
+		}
+	}
+	resp := &pb.BatchPlanResponse{}
+	for range req.Requests {
+		resp.Responses = append(resp.Responses, &pb.PlanResponse{Action: []float32{7, 7}, Safe: true})
+	}
+	return resp, nil
+}
+
+func TestBatchPlanUpstreamProxy(t *testing.T) {
+	up := &fakeUpstream{}
+	h := New(nil, nil, WithUpstream(up, time.Second, 0, 2, time.Minute))
+
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	ctx = middleware.WithTenantID(ctx, "acme")
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	req := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{RobotId: 1, Obs: obs}, {RobotId: 2, Obs: obs}}}
+
+	resp, err := h.BatchPlan(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected a call without a model to be proxied, got %v", err)
+	}
+	if len(resp.Responses) != 2 || resp.Responses[1].Action[0] != 7 {
+		t.Fatalf("Expected the upstream actions, got %v", resp.Responses)
+	}
+	if len(up.calls) != 1 {
+		t.Fatalf("Expected 1 upstream call, got %d", len(up.calls))
+	}
+	if got := up.md[0].Get(HopsHeader); len(got) != 1 || got[0] != "1" {
+		t.Errorf("Expected hop count 1 upstream, got %v", got)
+	}
+	if got := up.md[0].Get(middleware.TenantIDHeader); len(got) != 1 || got[0] != "acme" {
+		t.Errorf("Expected tenant acme forwarded, got %v", got)
+	}
+	if got := stream.trailer.Get(UpstreamHopsTrailer); len(got) != 1 || got[0] != "2" {
+		t.Errorf("Expected 2 upstream hops, got %v", got)
+	}
+	if got := stream.trailer.Get(UpstreamLatencyMsTrailer); len(got) != 1 {
+		t.Errorf("Expected an upstream latency trailer, got %v", got)
+	}
+
+	// Repeated observations are answered from the local cache
+	if _, err := h.BatchPlan(ctx, req); err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if len(up.calls) != 1 {
+		t.Errorf("Expected cached upstream actions to be reused, got %d upstream calls", len(up.calls))
+	}
+
+	// Calls at the hop limit are not proxied again
+	hopped := metadata.NewIncomingContext(context.Background(), metadata.Pairs(HopsHeader, "2"))
+	other := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{RobotId: 3, Obs: obs}}}
+	if _, err := h.BatchPlan(hopped, other); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected the local FailedPrecondition at the hop limit, got %v", err)
+	}
+	if len(up.calls) != 1 {
+		t.Errorf("Expected no upstream call at the hop limit, got %d", len(up.calls))
+	}
+}
+
+// robotTokenKeys is a KeySource serving one generated key
+type robotTokenKeys struct{ key signing.Key }
+
+func (k robotTokenKeys) Load() ([]signing.Key, string, error) {
+	return []signing.Key{k.key}, k.key.ID, nil
+}
+
+func TestIssueRobotToken(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	tokens, err := auth.NewRobotTokens(robotTokenKeys{signing.Key{ID: "k1", PrivateKey: priv}}, "policy-service")
+	if err != nil {
+		t.Fatalf("NewRobotTokens failed: %v", err)
+	}
+	h := New(inference.NewMock(), nil, WithRobotTokens(tokens, time.Hour, 24*time.Hour, []string{"provisioner/*"}))
+	provisioner := auth.WithIdentity(context.Background(), auth.Identity{Subject: "provisioner/fleet-a", Provider: "mtls", Tenant: "acme"})
+
+	resp, err := h.IssueRobotToken(provisioner, &pb.IssueRobotTokenRequest{RobotId: 42})
+	if err != nil {
+		t.Fatalf("IssueRobotToken failed: %v", err)
+	}
+	if resp.KeyId != "k1" || time.Until(time.Unix(0, resp.ExpiresUnixNano)) < 59*time.Minute {
+		t.Errorf("Expected a k1 token lasting the default hour, got %v", resp)
+	}
+	robot := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+resp.Token))
+	id, err := tokens.Authenticate(robot)
+	if err != nil || id.Subject != "robot/42" || id.Tenant != "acme" {
+		t.Fatalf("Expected the token to authenticate robot/42 of acme, got %+v, %v", id, err)
+	}
+
+	// Robots and unlisted callers cannot mint tokens
+	id.Provider = tokens.Name()
+	if _, err := h.IssueRobotToken(auth.WithIdentity(context.Background(), id), &pb.IssueRobotTokenRequest{RobotId: 42}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a robot token, got %v", err)
+	}
+	stranger := auth.WithIdentity(context.Background(), auth.Identity{Subject: "controller-1", Provider: "mtls"})
+	if _, err := h.IssueRobotToken(stranger, &pb.IssueRobotTokenRequest{RobotId: 42}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for an unlisted caller, got %v", err)
+	}
+
+	if _, err := h.IssueRobotToken(provisioner, &pb.IssueRobotTokenRequest{RobotId: 42, TtlSeconds: 48 * 3600}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument past the max TTL, got %v", err)
+	}
+	if _, err := h.IssueRobotToken(provisioner, &pb.IssueRobotTokenRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without a robot ID, got %v", err)
+	}
+	if _, err := New(nil, nil).IssueRobotToken(provisioner, &pb.IssueRobotTokenRequest{RobotId: 42}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition when robot tokens are disabled, got %v", err)
+	}
+}
+
+func TestRobotTokenScope(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{0.5, -0.5}), nil,
+		WithPlanUpdates(time.Hour, 10*time.Millisecond, time.Minute),
+		WithTwoPhaseCommit(time.Millisecond, time.Minute, []string{"robot/*"}, "supervisor"))
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+	robot := func(robotID string) context.Context {
+		ctx := middleware.WithClientIdentity(middleware.WithTenantID(context.Background(), "acme"), "robot/"+robotID)
+		return auth.WithIdentity(ctx, auth.Identity{Subject: "robot/" + robotID, Tenant: "acme",
+			Claims: map[string]interface{}{auth.RobotIDClaim: robotID}})
+	}
+
+	resp, err := h.Plan(robot("7"), &pb.PlanRequest{RobotId: 7, Obs: obs})
+	if err != nil {
+		t.Fatalf("Expected robot 7 to plan for itself, got %v", err)
+	}
+	if _, err := h.CommitPlan(robot("7"), &pb.CommitPlanRequest{Token: resp.CommitToken}); err != nil {
+		t.Errorf("Expected robot 7 to commit its own proposal, got %v", err)
+	}
+
+	// Robot 8's token cannot act for robot 7
+	other := robot("8")
+	if _, err := h.Plan(other, &pb.PlanRequest{RobotId: 7, Obs: obs}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied planning for another robot, got %v", err)
+	}
+	batch := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{RobotId: 8, Obs: obs}, {RobotId: 7, Obs: obs}}}
+	if _, err := h.BatchPlan(other, batch); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a batch naming another robot, got %v", err)
+	}
+	if _, err := h.CommitPlan(other, &pb.CommitPlanRequest{Token: resp.CommitToken}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied committing another robot's proposal, got %v", err)
+	}
+	if _, err := h.GetPlanUpdates(other, &pb.PlanUpdatesRequest{RobotId: 7, Obs: obs}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied subscribing to another robot, got %v", err)
+	}
+	stream := &planStream{ctx: other}
+	if err := h.PlanStream(&pb.PlanStreamRequest{Request: &pb.PlanRequest{RobotId: 7, Obs: obs}}, stream); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied streaming another robot's plans, got %v", err)
+	}
+}
+
+func TestPlanWithOpenBreaker(t *testing.T) {
+	mock := inference.NewMock()
+	mock.ShouldError = true
+	h := New(inference.NewBreaker(mock, 1, time.Hour), nil)
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}
+
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.Internal {
+		t.Fatalf("Expected Internal for the failure that opens the breaker, got %v", err)
+	}
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable while the breaker is open, got %v", err)
+	}
+}
+
+// stalledStore is a plan cache store whose lookups hang until cancelled
+type stalledStore struct{ plancache.MemoryStore }
+
+func (s *stalledStore) Get(ctx context.Context, tenant string, keys []string) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestPlanWithCacheTimeout(t *testing.T) {
+	h := New(inference.NewMockWithAction([]float32{0.5, 0.5}), nil,
+		WithPlanCache(plancache.New(&stalledStore{}, func() string { return "v1" }, time.Minute)),
+		WithCacheTimeout(timeouts.Static(timeouts.StageCache, 20*time.Millisecond)))
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := h.Plan(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected planning to go on after the cache lookup timed out, got %v", err)
+	}
+	if len(resp.Action) != 2 {
+		t.Errorf("Expected a planned action, got %v", resp.Action)
+	}
+}
+
+// stallingEngine holds Predict until release is closed
+type stallingEngine struct {
+	*inference.MockInference
+	release chan struct{}
+}
+
+func (e *stallingEngine) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	<-e.release
+	return e.MockInference.Predict(obsBatch, c, h, w)
+}
+
+func TestPlanWithInferenceTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	engine := &stallingEngine{MockInference: inference.NewMock(), release: release}
+	h := New(inference.NewDeadline(engine, timeouts.Static(timeouts.StageInference, 20*time.Millisecond)), nil)
+	req := &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2},
+	}
+
+	if _, err := h.Plan(context.Background(), req); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("Expected DeadlineExceeded for a batch past the inference timeout, got %v", err)
+	}
+}
+
+func TestPlanWithModelVersions(t *testing.T) {
+	older := inference.NewMockWithAction([]float32{1, 1})
+	h := New(inference.NewMockWithAction([]float32{2, 2}), nil,
+		WithDedup(dedup.New(time.Minute)),
+		WithModelVersions("v2", map[string]inference.InferenceEngine{"v1": older}))
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+
+	// The latest version plans first, so a pinned repeat must not be answered
+	// from the dedup window
+	for _, tc := range []struct {
+		pin, version string
+		action       float32
+	}{
+		{"", "v2", 2},
+		{"v2", "v2", 2},
+		{"v1", "v1", 1},
+	} {
+		resp, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: obs, ModelVersion: tc.pin})
+		if err != nil {
+			t.Fatalf("Plan pinning %q failed: %v", tc.pin, err)
+		}
+		if resp.ModelVersion != tc.version || resp.Action[0] != tc.action {
+			t.Errorf("Expected version %s to plan %g when pinning %q, got %s planning %v", tc.version, tc.action, tc.pin, resp.ModelVersion, resp.Action)
+		}
+	}
+
+	_, err := h.Plan(context.Background(), &pb.PlanRequest{RobotId: 1, Obs: obs, ModelVersion: "v0"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown version, got %v", err)
+	}
+	_, err = h.BatchPlan(context.Background(), &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{
+		{RobotId: 1, Obs: obs, ModelVersion: "v1"},
+		{RobotId: 2, Obs: obs},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a batch pinning different versions, got %v", err)
+	}
+
+	info, err := h.GetModelInfo(context.Background(), &pb.ModelInfoRequest{})
+	if err != nil {
+		t.Fatalf("GetModelInfo failed: %v", err)
+	}
+	if !slices.Equal(info.ModelVersions, []string{"v2", "v1"}) {
+		t.Errorf("Expected versions [v2 v1], got %v", info.ModelVersions)
+	}
+}
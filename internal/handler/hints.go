@@ -0,0 +1,55 @@
+// internal/handler/hints.go
+package handler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Trailer keys hinting at the server's load, so well-behaved clients can slow
+// down during load spikes instead of running into rejections
+const (
+	// QueueDepthTrailer is the number of observations waiting for or running inference
+	QueueDepthTrailer = "x-queue-depth"
+	// SuggestedIntervalMsTrailer is the interval, in milliseconds, the client
+	// should leave between calls
+	SuggestedIntervalMsTrailer = "x-suggested-interval-ms"
+)
+
+// clientHints turns the queue depth into a suggested call interval
+type clientHints struct {
+	// queueTarget is the queue depth up to which clients keep their base interval
+	queueTarget  int64
+	baseInterval time.Duration
+	maxInterval  time.Duration
+}
+
+// interval returns the suggested call interval at the given queue depth:
+// baseInterval up to queueTarget, then stretched in proportion to the depth
+// (twice the target suggests half the rate) up to maxInterval
+func (c *clientHints) interval(depth int64) time.Duration {
+	if depth <= c.queueTarget {
+		return c.baseInterval
+	}
+	d := time.Duration(float64(c.baseInterval) * float64(depth) / float64(c.queueTarget))
+	return min(d, c.maxInterval)
+}
+
+// setHintTrailer attaches the current queue depth and suggested call interval
+// as response trailers, if client hints are enabled
+func (h *Handler) setHintTrailer(ctx context.Context) {
+	if h.hints == nil {
+		return
+	}
+	depth := h.queueDepth.Load()
+	trailer := metadata.Pairs(
+		QueueDepthTrailer, strconv.FormatInt(depth, 10),
+		SuggestedIntervalMsTrailer, formatMs(h.hints.interval(depth)),
+	)
+	// Fails only outside a gRPC call (e.g. direct handler use); nothing to report then
+	grpc.SetTrailer(ctx, trailer)
+}
@@ -0,0 +1,40 @@
+// internal/handler/memory.go
+package handler
+
+import (
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// float32Size is the size in bytes of one packed observation value
+const float32Size = 4
+
+// WithMaxTensorBytes rejects requests whose input tensor would exceed limit
+// bytes with ResourceExhausted, before it is packed
+func WithMaxTensorBytes(limit int64) Option {
+	return func(h *Handler) {
+		h.maxTensorBytes = limit
+	}
+}
+
+// reserveTensor accounts for a request's input tensor of bytes until the
+// returned function is called, raising the high-water mark as needed. It fails
+// if bytes exceeds the per-request limit.
+func (h *Handler) reserveTensor(bytes int64) (func(), error) {
+	if h.maxTensorBytes > 0 && bytes > h.maxTensorBytes {
+		metrics.RecordTensorRejected()
+		return nil, resourceExhaustedError("input tensor of %d bytes exceeds the limit of %d bytes per request", bytes, h.maxTensorBytes)
+	}
+	metrics.RecordTensorBytes(bytes)
+
+	inflight := h.tensorBytes.Add(bytes)
+	for {
+		high := h.tensorHighWater.Load()
+		if inflight <= high || h.tensorHighWater.CompareAndSwap(high, inflight) {
+			break
+		}
+	}
+	metrics.SetTensorBytesInflight(inflight, h.tensorHighWater.Load())
+	return func() {
+		metrics.SetTensorBytesInflight(h.tensorBytes.Add(-bytes), h.tensorHighWater.Load())
+	}, nil
+}
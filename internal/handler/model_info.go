@@ -0,0 +1,66 @@
+// internal/handler/model_info.go
+package handler
+
+import (
+	"context"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// WithRegistryModel reports the registry version the model was resolved to
+// in GetModelInfo
+func WithRegistryModel(m *pb.RegistryModel) Option {
+	return func(h *Handler) {
+		h.registryModel = m
+	}
+}
+
+// GetModelInfo reports the primary model and the execution providers, thread
+// pools, and ONNX Runtime version its session actually runs with, along with
+// the model versions loaded
+func (h *Handler) GetModelInfo(ctx context.Context, req *pb.ModelInfoRequest) (*pb.ModelInfo, error) {
+	if h.infer == nil {
+		return nil, failedPreconditionError("inference engine not initialized")
+	}
+
+	info, ok := inference.Describe(h.infer)
+	if !ok {
+		return nil, failedPreconditionError("inference engine does not report runtime info")
+	}
+
+	var devices []int32
+	for _, d := range info.Devices {
+		devices = append(devices, int32(d))
+	}
+
+	// Engines that can be swapped report their serving and spare versions
+	var resident []*pb.ResidentModel
+	slots, _ := inference.ResidentModels(h.infer)
+	for _, s := range slots {
+		resident = append(resident, &pb.ResidentModel{
+			Version:        s.Version,
+			Active:         s.Active,
+			LoadedUnixNano: s.LoadedAt.UnixNano(),
+		})
+	}
+
+	return &pb.ModelInfo{
+		Model:                       info.ModelPath,
+		OrtVersion:                  info.ORTVersion,
+		AppendedExecutionProviders:  info.AppendedProviders,
+		RequestedExecutionProviders: info.RequestedProviders,
+		ProviderErrors:              info.ProviderErrors,
+		IntraOpThreads:              int32(info.IntraOpThreads),
+		InterOpThreads:              int32(info.InterOpThreads),
+		OptimizedCacheHit:           info.OptimizedCacheHit,
+		ActionDim:                   info.ActionDim,
+		Devices:                     devices,
+		InputShape:                  info.InputShape,
+		OutputShape:                 info.OutputShape,
+		ResidentModels:              resident,
+		Sessions:                    int32(max(info.Sessions, 1)),
+		RegistryModel:               h.registryModel,
+		ModelVersions:               h.modelVersions(),
+	}, nil
+}
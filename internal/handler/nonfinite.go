@@ -0,0 +1,46 @@
+// internal/handler/nonfinite.go
+package handler
+
+import "math"
+
+// WithNonFiniteSubstitute answers observations whose model outputs hold NaN
+// or Inf with a copy of safeAction instead of failing the call. Without it
+// such calls fail with codes.DataLoss.
+func WithNonFiniteSubstitute(safeAction []float32) Option {
+	return func(h *Handler) {
+		h.nonFiniteAction = append([]float32(nil), safeAction...)
+	}
+}
+
+// nonFiniteRows returns the indices of the observations of a batch whose raw
+// model outputs hold NaN or Inf. Outputs that do not split evenly over the
+// batch are left to the action size checks.
+func nonFiniteRows(outputs []float32, batchSize int) []int {
+	if batchSize == 0 || len(outputs)%batchSize != 0 {
+		return nil
+	}
+	width := len(outputs) / batchSize
+	var rows []int
+	for i := 0; i < batchSize; i++ {
+		for _, v := range outputs[i*width : (i+1)*width] {
+			if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				rows = append(rows, i)
+				break
+			}
+		}
+	}
+	return rows
+}
+
+// substituteRows overwrites the decoded actions of rows with the configured
+// safe action
+func (h *Handler) substituteRows(actions []float32, batchSize int, rows []int) error {
+	actionDim := len(actions) / batchSize
+	if actionDim*batchSize != len(actions) || actionDim != len(h.nonFiniteAction) {
+		return internalError("non-finite substitute has %d values but actions have %d", len(h.nonFiniteAction), actionDim)
+	}
+	for _, i := range rows {
+		copy(actions[i*actionDim:(i+1)*actionDim], h.nonFiniteAction)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// internal/handler/pose.go
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Ways a robot's cached pose is used in planning
+const (
+	// PoseObservation appends the last pose to state-vector observations
+	PoseObservation = "observation"
+	// PoseResponse returns the last pose in PlanResponse.last_pose
+	PoseResponse = "response"
+)
+
+// poseFeatures is the number of values a pose adds to an observation: x, y,
+// theta, and velocity
+const poseFeatures = 4
+
+// poseCache uses each robot's last pose, cached by robot ID, in planning
+type poseCache struct {
+	mode string
+	ttl  time.Duration
+}
+
+// WithPoseCache fetches each robot's last pose from the cache by robot ID.
+// With PoseObservation its x, y, theta, and velocity are appended to the
+// robot's state-vector observation before inference, zeros if none is cached;
+// with PoseResponse it is returned in last_pose. Poses sent in requests are
+// cached for ttl once the batch is planned.
+func WithPoseCache(mode string, ttl time.Duration) Option {
+	return func(h *Handler) {
+		h.poses = &poseCache{mode: mode, ttl: ttl}
+	}
+}
+
+// lastPoses returns the cached pose of each robot, nil for robots without one,
+// fetched in one round trip. Cache errors are logged and the robots treated as
+// having no pose.
+func (h *Handler) lastPoses(ctx context.Context, requestID string, robotIDs []uint64) []*pb.Pose {
+	if h.cache == nil {
+		metrics.RecordPoseLookups("miss", len(robotIDs))
+		return make([]*pb.Pose, len(robotIDs))
+	}
+	t, err := h.cache.ForTenant(middleware.GetTenantID(ctx))
+	if err == nil {
+		var poses []*pb.Pose
+		poses, err = cacheLookup(ctx, h.cacheTimeout, func(ctx context.Context) ([]*pb.Pose, error) {
+			return t.GetPoses(ctx, robotIDs)
+		})
+		if err == nil {
+			hits := 0
+			for _, pose := range poses {
+				if pose != nil {
+					hits++
+				}
+			}
+			metrics.RecordPoseLookups("hit", hits)
+			metrics.RecordPoseLookups("miss", len(poses)-hits)
+			return poses
+		}
+	}
+	metrics.RecordPoseLookups("error", len(robotIDs))
+	slog.WarnContext(ctx, "Pose cache lookup failed", "request_id", requestID, "error", err)
+	return make([]*pb.Pose, len(robotIDs))
+}
+
+// storePoses caches the poses sent in requests as the robots' last poses in
+// one round trip, stamping those without a timestamp with received. Failures
+// are logged; the call is still served.
+func (h *Handler) storePoses(ctx context.Context, requestID string, requests []*pb.PlanRequest, received time.Time) {
+	if h.cache == nil {
+		return
+	}
+	poses := make(map[uint64]*pb.Pose, len(requests))
+	for _, req := range requests {
+		if req.Pose == nil {
+			continue
+		}
+		pose := proto.Clone(req.Pose).(*pb.Pose)
+		if pose.TimestampUnixNano == 0 {
+			pose.TimestampUnixNano = received.UnixNano()
+		}
+		poses[req.RobotId] = pose
+	}
+	if len(poses) == 0 {
+		return
+	}
+	t, err := h.cache.ForTenant(middleware.GetTenantID(ctx))
+	if err == nil {
+		err = t.SetPoses(ctx, poses, h.poses.ttl)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to store robot poses", "request_id", requestID, "error", err)
+	}
+}
+
+// withPose returns a copy of obs with the pose's x, y, theta, and velocity
+// appended, zeros if pose is nil
+func withPose(obs []float32, pose *pb.Pose) []float32 {
+	out := make([]float32, len(obs), len(obs)+poseFeatures)
+	copy(out, obs)
+	return append(out,
+		float32(pose.GetX()), float32(pose.GetY()), float32(pose.GetTheta()), float32(pose.GetVelocity()))
+}
@@ -0,0 +1,128 @@
+// internal/handler/snapshot.go
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/SyedDaiam9101/policy-service/internal/snapshot"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// StateSources returns the handler's per-robot state to snapshot: the dedup
+// window and the GetPlanUpdates schedule, where enabled
+func (h *Handler) StateSources() []snapshot.Source {
+	var sources []snapshot.Source
+	if h.dedup != nil {
+		sources = append(sources, h.dedup)
+	}
+	if h.updates != nil {
+		sources = append(sources, h.updates)
+	}
+	return sources
+}
+
+// snapshotRobot is a scheduled robot as saved by Snapshot; messages are
+// encoded protobufs
+type snapshotRobot struct {
+	Tenant   string    `json:"tenant"`
+	Client   string    `json:"client,omitempty"`
+	RobotID  uint64    `json:"robot_id"`
+	Obs      []byte    `json:"obs"`
+	Pose     []byte    `json:"pose,omitempty"`
+	LastPoll time.Time `json:"last_poll"`
+	Plan     []byte    `json:"plan,omitempty"`
+	Planned  time.Time `json:"planned"`
+}
+
+// Name identifies the schedule's state in state snapshots
+func (s *planSchedule) Name() string { return "plan_updates" }
+
+// Snapshot encodes the scheduled robots with their latest observations and plans
+func (s *planSchedule) Snapshot() ([]byte, error) {
+	s.mu.Lock()
+	scheduled := make([]scheduledRobot, 0, len(s.robots))
+	for _, r := range s.robots {
+		scheduled = append(scheduled, *r)
+	}
+	s.mu.Unlock()
+
+	// Messages are replaced, never modified, so the copies can be encoded
+	// outside the lock
+	robots := make([]snapshotRobot, len(scheduled))
+	for i := range scheduled {
+		r := &scheduled[i]
+		sr := snapshotRobot{
+			Tenant:   r.tenant,
+			Client:   r.client,
+			RobotID:  r.robotID,
+			LastPoll: r.lastPoll,
+			Planned:  r.planned,
+		}
+		var err error
+		if sr.Obs, err = proto.Marshal(r.obs); err != nil {
+			return nil, fmt.Errorf("robot %d observation: %w", r.robotID, err)
+		}
+		if r.pose != nil {
+			if sr.Pose, err = proto.Marshal(r.pose); err != nil {
+				return nil, fmt.Errorf("robot %d pose: %w", r.robotID, err)
+			}
+		}
+		if r.plan != nil {
+			if sr.Plan, err = proto.Marshal(r.plan); err != nil {
+				return nil, fmt.Errorf("robot %d plan: %w", r.robotID, err)
+			}
+		}
+		robots[i] = sr
+	}
+	return json.Marshal(robots)
+}
+
+// Restore loads robots saved by Snapshot. They keep their last poll time, so
+// robots that went idle while the service was down are dropped on the next
+// tick rather than planned.
+func (s *planSchedule) Restore(data []byte) error {
+	var robots []snapshotRobot
+	if err := json.Unmarshal(data, &robots); err != nil {
+		return err
+	}
+
+	restored := make(map[robotKey]*scheduledRobot, len(robots))
+	for _, sr := range robots {
+		r := &scheduledRobot{
+			tenant:   sr.Tenant,
+			client:   sr.Client,
+			robotID:  sr.RobotID,
+			obs:      &pb.Observation{},
+			lastPoll: sr.LastPoll,
+			planned:  sr.Planned,
+			updated:  make(chan struct{}),
+		}
+		if err := proto.Unmarshal(sr.Obs, r.obs); err != nil {
+			return fmt.Errorf("robot %d observation: %w", sr.RobotID, err)
+		}
+		if sr.Pose != nil {
+			r.pose = &pb.Pose{}
+			if err := proto.Unmarshal(sr.Pose, r.pose); err != nil {
+				return fmt.Errorf("robot %d pose: %w", sr.RobotID, err)
+			}
+		}
+		if sr.Plan != nil {
+			r.plan = &pb.PlanResponse{}
+			if err := proto.Unmarshal(sr.Plan, r.plan); err != nil {
+				return fmt.Errorf("robot %d plan: %w", sr.RobotID, err)
+			}
+		}
+		restored[robotKey{tenant: sr.Tenant, robotID: sr.RobotID}] = r
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, r := range restored {
+		s.robots[key] = r
+	}
+	return nil
+}
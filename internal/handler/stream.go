@@ -0,0 +1,237 @@
+// internal/handler/stream.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Defaults for BatchPlanStream when not configured with WithStreamChunks
+const (
+	DefaultStreamChunkSize   = 32
+	DefaultStreamConcurrency = 4
+)
+
+// Defaults for PlanStream when not configured with WithPlanStreamInterval
+const (
+	DefaultPlanStreamInterval    = 100 * time.Millisecond
+	DefaultPlanStreamMinInterval = 20 * time.Millisecond
+)
+
+// WithStreamChunks sets the BatchPlanStream chunk size used when a request does
+// not specify one, and how many chunks of a call run at once. Non-positive
+// values keep the defaults.
+func WithStreamChunks(chunkSize, concurrency int) Option {
+	return func(h *Handler) {
+		if chunkSize > 0 {
+			h.streamChunkSize = chunkSize
+		}
+		if concurrency > 0 {
+			h.streamConcurrency = concurrency
+		}
+	}
+}
+
+// WithPlanStreamInterval sets the PlanStream replanning interval used when a
+// request does not specify one, and the shortest interval a request may ask
+// for. Non-positive values keep the defaults.
+func WithPlanStreamInterval(interval, minInterval time.Duration) Option {
+	return func(h *Handler) {
+		if interval > 0 {
+			h.planStreamInterval = interval
+		}
+		if minInterval > 0 {
+			h.planStreamMinInterval = minInterval
+		}
+	}
+}
+
+// chunkResult is the outcome of planning one chunk of a stream
+type chunkResult struct {
+	offset int
+	resp   *pb.BatchPlanResponse
+	cost   callCost
+	err    error
+}
+
+// BatchPlanStream plans the requests in chunks and sends each chunk's responses
+// as soon as it finishes. The first failing chunk ends the stream with its error,
+// and chunks not yet started are skipped once the call is cancelled.
+func (h *Handler) BatchPlanStream(req *pb.BatchPlanStreamRequest, stream pb.PathPlanner_BatchPlanStreamServer) error {
+	if req == nil || len(req.Requests) == 0 {
+		return invalidArgumentError("batch request cannot be nil or empty")
+	}
+	if err := checkRobots(stream.Context(), req.Requests); err != nil {
+		return err
+	}
+
+	chunkSize := int(req.ChunkSize)
+	if chunkSize <= 0 {
+		chunkSize = h.streamChunkSize
+	}
+	if h.maxBatchSize > 0 {
+		chunkSize = min(chunkSize, h.maxBatchSize)
+	}
+	chunks := (len(req.Requests) + chunkSize - 1) / chunkSize
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	defer h.setHintTrailer(ctx)
+
+	// Buffered so chunks still running after an early return never block
+	results := make(chan chunkResult, chunks)
+	slots := make(chan struct{}, h.streamConcurrency)
+	for offset := 0; offset < len(req.Requests); offset += chunkSize {
+		batch := &pb.BatchPlanRequest{Requests: req.Requests[offset:min(offset+chunkSize, len(req.Requests))]}
+		go func(offset int) {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				results <- chunkResult{offset: offset, err: contextError(ctx.Err())}
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				results <- chunkResult{offset: offset, err: contextError(err)}
+				return
+			}
+
+			resp, cost, err := h.batchPlan(ctx, batch)
+			results <- chunkResult{offset: offset, resp: resp, cost: cost, err: err}
+		}(offset)
+	}
+
+	var (
+		total    callCost
+		attached time.Duration // inference time attributed to this call
+	)
+	for i := 0; i < chunks; i++ {
+		r := <-results
+		if r.err != nil {
+			return r.err
+		}
+		total.inference += r.cost.inference
+		total.queue += r.cost.queue
+		attached += time.Duration(float64(r.cost.inference) * r.cost.batchShare)
+
+		if err := stream.Send(&pb.BatchPlanChunk{Offset: uint32(r.offset), Responses: r.resp.Responses}); err != nil {
+			return err
+		}
+	}
+
+	// Report the summed cost of all chunks, with the overall share of the
+	// batches they ran in
+	total.batchShare = 1
+	if total.inference > 0 {
+		total.batchShare = float64(attached) / float64(total.inference)
+	}
+	setCostTrailer(ctx, total)
+	return nil
+}
+
+// PlanStream replans the request every interval and sends each plan until the
+// call is cancelled or a plan fails. The pose sent with the request is cached
+// with the first plan; later plans use the robot's latest cached pose.
+func (h *Handler) PlanStream(req *pb.PlanStreamRequest, stream pb.PathPlanner_PlanStreamServer) error {
+	if req == nil || req.Request == nil {
+		return invalidArgumentError("request cannot be nil")
+	}
+	if err := checkRobot(stream.Context(), req.Request.RobotId); err != nil {
+		return err
+	}
+
+	interval := h.planStreamInterval
+	if req.IntervalMs > 0 {
+		interval = max(time.Duration(req.IntervalMs)*time.Millisecond, h.planStreamMinInterval)
+	}
+
+	ctx := stream.Context()
+	defer h.setHintTrailer(ctx)
+
+	planReq := proto.Clone(req.Request).(*pb.PlanRequest)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, _, err := h.batchPlan(ctx, &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{planReq}})
+		if err != nil {
+			return err
+		}
+		planReq.Pose = nil
+		if err := stream.Send(resp.Responses[0]); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamPlanMethod labels StreamPlan's per-message metrics
+const streamPlanMethod = "/planner.PathPlanner/StreamPlan"
+
+// StreamPlan plans each request received on the stream, in order, and sends
+// back its response. Each message is planned with its own request ID, the
+// stream's followed by the message's position. A failed plan is answered with
+// its status and the stream stays open; the stream ends when the client closes
+// its side or the call is cancelled.
+func (h *Handler) StreamPlan(stream pb.PathPlanner_StreamPlanServer) error {
+	ctx := stream.Context()
+	defer h.setHintTrailer(ctx)
+
+	streamID := middleware.GetRequestID(ctx)
+	if streamID == "" {
+		streamID = "unknown"
+	}
+	for n := 1; ; n++ {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return contextError(ctx.Err())
+			}
+			return err
+		}
+
+		start := time.Now()
+		requestID := fmt.Sprintf("%s-%d", streamID, n)
+		out := &pb.StreamPlanResponse{Seq: msg.Seq, RequestId: requestID}
+		if msg.Request == nil {
+			err = invalidArgumentError("message %d has no request", n)
+		} else if err = checkRobot(ctx, msg.Request.RobotId); err == nil {
+			var resp *pb.BatchPlanResponse
+			resp, _, err = h.batchPlan(middleware.WithRequestID(ctx, requestID),
+				&pb.BatchPlanRequest{Requests: []*pb.PlanRequest{msg.Request}})
+			if err == nil {
+				out.Response = resp.Responses[0]
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return contextError(ctx.Err())
+			}
+			st := status.Convert(err)
+			out.ErrorCode = int32(st.Code())
+			out.Error = st.Message()
+		}
+		metrics.RecordStreamMessage(streamPlanMethod, status.Code(err).String(), time.Since(start).Seconds())
+
+		if err := stream.Send(out); err != nil {
+			return err
+		}
+	}
+}
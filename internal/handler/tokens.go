@@ -0,0 +1,104 @@
+// internal/handler/tokens.go
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// robotTokenIssuer mints robot tokens for authorized provisioning systems
+type robotTokenIssuer struct {
+	tokens *auth.RobotTokens
+	ttl    time.Duration
+	maxTTL time.Duration
+	// issuers match the subjects allowed to mint tokens, as in ValidityRule
+	issuers []string
+}
+
+// WithRobotTokens serves IssueRobotToken: callers whose authenticated subject
+// matches an entry of issuers get tokens lasting ttl by default and maxTTL at
+// most
+func WithRobotTokens(tokens *auth.RobotTokens, ttl, maxTTL time.Duration, issuers []string) Option {
+	return func(h *Handler) {
+		h.robotTokens = &robotTokenIssuer{tokens: tokens, ttl: ttl, maxTTL: maxTTL, issuers: issuers}
+	}
+}
+
+// authorize rejects callers not listed as issuers, and callers holding a
+// robot token, so a robot cannot extend its own credentials
+func (r *robotTokenIssuer) authorize(ctx context.Context) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok || id.Provider == r.tokens.Name() {
+		return permissionDeniedError("issuing robot tokens requires provisioning credentials")
+	}
+	if !slices.ContainsFunc(r.issuers, func(pattern string) bool { return matchClient(pattern, id.Subject) }) {
+		return permissionDeniedError("%q may not issue robot tokens", id.Subject)
+	}
+	return nil
+}
+
+// checkRobot rejects calls about robots other than the one the caller's token
+// is bound to by its robot_id claim, so a robot can neither plan for nor
+// commit or subscribe to another robot's actions
+func checkRobot(ctx context.Context, robotID uint64) error {
+	id, _ := auth.FromContext(ctx)
+	if own, ok := id.RobotID(); ok && robotID != own {
+		return permissionDeniedError("%q may only act for robot %d, not robot %d", id.Subject, own, robotID)
+	}
+	return nil
+}
+
+// checkRobots applies checkRobot to every request of a batch
+func checkRobots(ctx context.Context, requests []*pb.PlanRequest) error {
+	for _, r := range requests {
+		if err := checkRobot(ctx, r.GetRobotId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IssueRobotToken mints a short-lived bearer token authenticating one robot
+func (h *Handler) IssueRobotToken(ctx context.Context, req *pb.IssueRobotTokenRequest) (*pb.IssueRobotTokenResponse, error) {
+	r := h.robotTokens
+	if r == nil {
+		return nil, failedPreconditionError("robot tokens are not enabled")
+	}
+	if err := r.authorize(ctx); err != nil {
+		metrics.RecordRobotToken("denied")
+		return nil, err
+	}
+	if req.GetRobotId() == 0 {
+		return nil, invalidArgumentError("robot_id is required")
+	}
+	ttl := r.ttl
+	if req.GetTtlSeconds() > 0 {
+		ttl = time.Duration(req.GetTtlSeconds()) * time.Second
+	}
+	if ttl > r.maxTTL {
+		return nil, invalidArgumentError("ttl must be at most %s, got %s", r.maxTTL, ttl)
+	}
+	// The subject is derived from the robot ID, never taken from the caller,
+	// so a provisioner cannot mint tokens for subjects it does not own
+	subject := "robot/" + strconv.FormatUint(req.GetRobotId(), 10)
+
+	issuer, _ := auth.FromContext(ctx)
+	token, keyID, expires, err := r.tokens.Issue(subject, issuer.Tenant, req.GetRobotId(), ttl)
+	if err != nil {
+		metrics.RecordRobotToken("error")
+		slog.ErrorContext(ctx, "Failed to issue robot token", "request_id", middleware.GetRequestID(ctx), "robot_id", req.GetRobotId(), "error", err)
+		return nil, internalError("failed to issue robot token: %v", err)
+	}
+	metrics.RecordRobotToken("issued")
+	slog.InfoContext(ctx, "Issued robot token", "request_id", middleware.GetRequestID(ctx),
+		"robot_id", req.GetRobotId(), "subject", subject, "tenant", issuer.Tenant, "issuer", middleware.GetClientIdentity(ctx), "expires", expires)
+	return &pb.IssueRobotTokenResponse{Token: token, KeyId: keyID, ExpiresUnixNano: expires.UnixNano()}, nil
+}
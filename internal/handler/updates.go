@@ -0,0 +1,257 @@
+// internal/handler/updates.go
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// robotKey identifies a robot on the plan schedule; robot IDs are only unique
+// within a tenant
+type robotKey struct {
+	tenant  string
+	robotID uint64
+}
+
+// scheduledRobot is a robot planned on the GetPlanUpdates schedule
+type scheduledRobot struct {
+	tenant   string
+	client   string
+	robotID  uint64
+	obs      *pb.Observation
+	pose     *pb.Pose // sent with the next plan only, then cleared
+	lastPoll time.Time
+
+	plan    *pb.PlanResponse
+	planned time.Time
+	updated chan struct{} // closed and replaced each time plan changes
+}
+
+// planSchedule plans every robot polling GetPlanUpdates on a fixed interval,
+// so low-power robots can hold one long poll open instead of calling Plan
+type planSchedule struct {
+	interval time.Duration
+	maxWait  time.Duration
+	idle     time.Duration
+
+	mu      sync.Mutex
+	robots  map[robotKey]*scheduledRobot
+	next    time.Time
+	stopped chan struct{} // closed once RunPlanUpdates returns
+}
+
+// WithPlanUpdates enables GetPlanUpdates, planning each polling robot's
+// latest observation every interval. Polls wait up to maxWait for a new plan;
+// robots that have not polled for idle are dropped from the schedule. The
+// schedule runs in RunPlanUpdates.
+func WithPlanUpdates(interval, maxWait, idle time.Duration) Option {
+	return func(h *Handler) {
+		h.updates = &planSchedule{
+			interval: interval,
+			maxWait:  maxWait,
+			idle:     idle,
+			robots:   make(map[robotKey]*scheduledRobot),
+			stopped:  make(chan struct{}),
+		}
+	}
+}
+
+// RunPlanUpdates plans the scheduled robots every interval until ctx is done,
+// then wakes the polls still waiting. It returns immediately if plan updates
+// are not enabled.
+func (h *Handler) RunPlanUpdates(ctx context.Context) {
+	s := h.updates
+	if s == nil {
+		return
+	}
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	s.mu.Lock()
+	s.next = time.Now().Add(s.interval)
+	s.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			metrics.SetPlanUpdatesRobots(0)
+			return
+		case now := <-ticker.C:
+			h.planScheduled(ctx, now)
+		}
+	}
+}
+
+// planGroup is scheduled robots that can be planned in one batch
+type planGroup struct {
+	tenant string
+	client string
+	shape  [3]uint32
+}
+
+// planScheduled drops idle robots and plans the rest, one batch per tenant,
+// client, and observation shape
+func (h *Handler) planScheduled(ctx context.Context, now time.Time) {
+	s := h.updates
+	groups := make(map[planGroup][]*scheduledRobot)
+	requests := make(map[planGroup][]*pb.PlanRequest)
+
+	s.mu.Lock()
+	s.next = now.Add(s.interval)
+	for key, r := range s.robots {
+		if now.Sub(r.lastPoll) > s.idle {
+			delete(s.robots, key)
+			continue
+		}
+		g := planGroup{tenant: r.tenant, client: r.client, shape: [3]uint32{r.obs.Channels, r.obs.Height, r.obs.Width}}
+		groups[g] = append(groups[g], r)
+		requests[g] = append(requests[g], &pb.PlanRequest{RobotId: r.robotID, Obs: r.obs, Pose: r.pose})
+		r.pose = nil
+	}
+	metrics.SetPlanUpdatesRobots(len(s.robots))
+	s.mu.Unlock()
+
+	for g, robots := range groups {
+		if ctx.Err() != nil {
+			return
+		}
+		planCtx := middleware.WithTenantID(ctx, g.tenant)
+		planCtx = middleware.WithRequestID(planCtx, fmt.Sprintf("plan-updates-%d", now.UnixNano()))
+		if g.client != "" {
+			planCtx = middleware.WithClientIdentity(planCtx, g.client)
+		}
+
+		resp, _, err := h.batchPlan(planCtx, &pb.BatchPlanRequest{Requests: requests[g]})
+		if err != nil {
+			// Keep serving the previous plans; the robots are planned again next tick
+			slog.ErrorContext(planCtx, "Scheduled plan failed",
+				"tenant", g.tenant, "batch_size", len(robots), "error", err)
+			continue
+		}
+
+		planned := time.Now()
+		s.mu.Lock()
+		for i, r := range robots {
+			r.plan = resp.Responses[i]
+			r.planned = planned
+			close(r.updated)
+			r.updated = make(chan struct{})
+		}
+		s.mu.Unlock()
+	}
+}
+
+// GetPlanUpdates schedules the robot for planning with its latest observation
+// and returns its plan once one newer than after_unix_nano is made, or an
+// update without a plan after the maximum wait
+func (h *Handler) GetPlanUpdates(ctx context.Context, req *pb.PlanUpdatesRequest) (*pb.PlanUpdate, error) {
+	s := h.updates
+	if s == nil {
+		return nil, failedPreconditionError("plan updates are not enabled")
+	}
+	if req == nil {
+		return nil, invalidArgumentError("request cannot be nil")
+	}
+	if err := checkRobot(ctx, req.RobotId); err != nil {
+		return nil, err
+	}
+	if obs := req.Obs; obs != nil {
+		if obs.Channels == 0 || obs.Height == 0 || obs.Width == 0 {
+			return nil, invalidArgumentError("invalid observation dimensions: channels=%d, height=%d, width=%d",
+				obs.Channels, obs.Height, obs.Width)
+		}
+		// Encoded observations are kept as sent and decoded when planned
+		n := len(obs.Data)
+		if encoded(obs) {
+			decoded, err := h.decodeObservation(obs)
+			if err != nil {
+				return nil, invalidArgumentError("observation: %v", err)
+			}
+			n = len(decoded.Data)
+		}
+		if expected := int(obs.Channels) * int(obs.Height) * int(obs.Width); n != expected {
+			return nil, invalidArgumentError("observation has wrong data length: got %d, expected %d", n, expected)
+		}
+	}
+
+	now := time.Now()
+	key := robotKey{tenant: middleware.GetTenantID(ctx), robotID: req.RobotId}
+
+	s.mu.Lock()
+	r, ok := s.robots[key]
+	if !ok {
+		if req.Obs == nil {
+			s.mu.Unlock()
+			return nil, invalidArgumentError("the first poll for robot %d must carry an observation", req.RobotId)
+		}
+		r = &scheduledRobot{
+			tenant:  key.tenant,
+			client:  middleware.GetClientIdentity(ctx),
+			robotID: req.RobotId,
+			updated: make(chan struct{}),
+		}
+		s.robots[key] = r
+		metrics.SetPlanUpdatesRobots(len(s.robots))
+	}
+	r.lastPoll = now
+	if req.Obs != nil {
+		r.obs = req.Obs
+	}
+	if req.Pose != nil {
+		pose := proto.Clone(req.Pose).(*pb.Pose)
+		if pose.TimestampUnixNano == 0 {
+			pose.TimestampUnixNano = now.UnixNano()
+		}
+		r.pose = pose
+	}
+	if update := r.updateLocked(s.next, req.AfterUnixNano); update != nil {
+		s.mu.Unlock()
+		metrics.RecordPlanUpdatePoll("updated")
+		return update, nil
+	}
+	updated := r.updated
+	s.mu.Unlock()
+
+	timer := time.NewTimer(s.maxWait)
+	defer timer.Stop()
+	select {
+	case <-updated:
+		s.mu.Lock()
+		update := r.updateLocked(s.next, 0)
+		s.mu.Unlock()
+		metrics.RecordPlanUpdatePoll("updated")
+		return update, nil
+	case <-timer.C:
+		s.mu.Lock()
+		next := s.next
+		s.mu.Unlock()
+		metrics.RecordPlanUpdatePoll("timeout")
+		return &pb.PlanUpdate{NextPlanUnixNano: next.UnixNano()}, nil
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err())
+	case <-s.stopped:
+		return nil, unavailableError("server is shutting down")
+	}
+}
+
+// updateLocked returns the robot's plan if it was made after the UnixNano time
+// after, else nil; call with the schedule's lock held
+func (r *scheduledRobot) updateLocked(next time.Time, after int64) *pb.PlanUpdate {
+	if r.plan == nil || r.planned.UnixNano() <= after {
+		return nil
+	}
+	return &pb.PlanUpdate{
+		Response:         r.plan,
+		PlannedUnixNano:  r.planned.UnixNano(),
+		NextPlanUnixNano: next.UnixNano(),
+	}
+}
@@ -0,0 +1,189 @@
+// internal/handler/upstream.go
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/dedup"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Metadata of calls proxied to an upstream instance
+const (
+	// HopsHeader is the number of times a call has been proxied so far
+	HopsHeader = "x-policy-hops"
+	// UpstreamHopsTrailer is the number of hops between this instance and the
+	// one that planned the call
+	UpstreamHopsTrailer = "x-upstream-hops"
+	// UpstreamLatencyMsTrailer is the round trip to the upstream, in milliseconds
+	UpstreamLatencyMsTrailer = "x-upstream-latency-ms"
+)
+
+// Why a call was proxied, counted in upstream_calls_total
+const (
+	upstreamUnavailable = "unavailable"
+	upstreamBusy        = "busy"
+)
+
+// upstream is the policy-service that calls this instance cannot serve are
+// proxied to
+type upstream struct {
+	client  pb.PathPlannerClient
+	timeout time.Duration
+	// queueThreshold is the local queue depth from which calls are proxied
+	// without trying locally; 0 proxies only calls that fail locally
+	queueThreshold int64
+	maxHops        int
+	// results remembers upstream actions, so repeated observations are
+	// answered locally
+	results *dedup.Window
+}
+
+// WithUpstream proxies Plan and BatchPlan calls this instance cannot serve,
+// because no model is loaded or inference is unavailable or overloaded, to
+// client, waiting up to timeout. Calls are proxied without trying locally
+// while queueThreshold or more observations are queued (0 disables this).
+// Calls proxied maxHops times already are served locally only, so a loop of
+// instances cannot bounce calls forever. Upstream actions are reused for
+// repeated observations of a robot within cacheTTL (0 disables this).
+func WithUpstream(client pb.PathPlannerClient, timeout time.Duration, queueThreshold int64, maxHops int, cacheTTL time.Duration) Option {
+	return func(h *Handler) {
+		u := &upstream{client: client, timeout: timeout, queueThreshold: queueThreshold, maxHops: maxHops}
+		if cacheTTL > 0 {
+			u.results = dedup.New(cacheTTL)
+		}
+		h.upstream = u
+	}
+}
+
+// hops returns how many times the incoming call has been proxied
+func hops(ctx context.Context) int {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0
+	}
+	values := md.Get(HopsHeader)
+	if len(values) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(values[0])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// planOrProxy plans req locally, or proxies it upstream when the local
+// queue is over the threshold or local planning cannot serve it
+func (h *Handler) planOrProxy(ctx context.Context, req *pb.BatchPlanRequest) (*pb.BatchPlanResponse, callCost, error) {
+	u := h.upstream
+	if u == nil || hops(ctx) >= u.maxHops || req == nil || len(req.Requests) == 0 {
+		return h.batchPlan(ctx, req)
+	}
+
+	if u.queueThreshold > 0 && h.queueDepth.Load() >= u.queueThreshold {
+		return h.proxy(ctx, req, upstreamBusy)
+	}
+	if h.infer == nil {
+		return h.proxy(ctx, req, upstreamUnavailable)
+	}
+	resp, cost, err := h.batchPlan(ctx, req)
+	if code := status.Code(err); code == codes.Unavailable || code == codes.ResourceExhausted {
+		slog.WarnContext(ctx, "Local planning failed; proxying upstream",
+			"request_id", middleware.GetRequestID(ctx), "batch_size", len(req.Requests), "error", err)
+		return h.proxy(ctx, req, upstreamUnavailable)
+	}
+	return resp, cost, err
+}
+
+// proxy plans req on the upstream, answering robots whose observation the
+// upstream planned recently from the local results
+func (h *Handler) proxy(ctx context.Context, req *pb.BatchPlanRequest, reason string) (*pb.BatchPlanResponse, callCost, error) {
+	u := h.upstream
+	responses := make([]*pb.PlanResponse, len(req.Requests))
+	obsHashes := make([]string, len(req.Requests))
+	var missing []*pb.PlanRequest
+	var missingAt []int
+	for i, r := range req.Requests {
+		if r == nil || r.Obs == nil {
+			return nil, callCost{}, invalidArgumentError("request %d has nil observation", i)
+		}
+		obsHashes[i] = observation.Hash(r.Obs)
+		if u.results != nil {
+			if action, ok := u.results.Lookup(middleware.GetTenantID(ctx), r.RobotId, obsHashes[i]); ok {
+				responses[i] = &pb.PlanResponse{Action: action, Safe: true, ObsHash: obsHashes[i]}
+				continue
+			}
+		}
+		missing = append(missing, r)
+		missingAt = append(missingAt, i)
+	}
+	if len(missing) == 0 {
+		metrics.RecordUpstreamCall(reason, "cached")
+		return &pb.BatchPlanResponse{Responses: responses}, callCost{batchShare: 1}, nil
+	}
+
+	// Carry the caller's identity and one more hop
+	pairs := []string{HopsHeader, strconv.Itoa(hops(ctx) + 1)}
+	if id := middleware.GetRequestID(ctx); id != "" {
+		pairs = append(pairs, middleware.RequestIDHeader, id)
+	}
+	pairs = append(pairs, middleware.TenantIDHeader, middleware.GetTenantID(ctx))
+	if key := middleware.GetIdempotencyKey(ctx); key != "" {
+		pairs = append(pairs, middleware.IdempotencyKeyHeader, key)
+	}
+	callCtx, cancel := context.WithTimeout(metadata.AppendToOutgoingContext(ctx, pairs...), u.timeout)
+	defer cancel()
+
+	var trailer metadata.MD
+	start := time.Now()
+	resp, err := u.client.BatchPlan(callCtx, &pb.BatchPlanRequest{Requests: missing}, grpc.Trailer(&trailer))
+	latency := time.Since(start)
+	metrics.RecordUpstreamLatency(latency.Seconds())
+	if err != nil {
+		metrics.RecordUpstreamCall(reason, "error")
+		slog.ErrorContext(ctx, "Upstream planning failed",
+			"request_id", middleware.GetRequestID(ctx), "batch_size", len(missing), "reason", reason, "error", err)
+		if s, ok := status.FromError(err); ok {
+			return nil, callCost{}, status.Errorf(s.Code(), "upstream: %s", s.Message())
+		}
+		return nil, callCost{}, unavailableError("upstream: %v", err)
+	}
+	if len(resp.Responses) != len(missing) {
+		metrics.RecordUpstreamCall(reason, "error")
+		return nil, callCost{}, internalError("upstream returned %d responses for %d requests", len(resp.Responses), len(missing))
+	}
+	metrics.RecordUpstreamCall(reason, "ok")
+
+	for j, i := range missingAt {
+		responses[i] = resp.Responses[j]
+		if u.results != nil && resp.Responses[j].Safe {
+			u.results.Store(middleware.GetTenantID(ctx), req.Requests[i].RobotId, obsHashes[i], resp.Responses[j].Action)
+		}
+	}
+
+	// Report the hops to the instance that planned, counting this one
+	upstreamHops := 1
+	if v := trailer.Get(UpstreamHopsTrailer); len(v) > 0 {
+		if n, err := strconv.Atoi(v[0]); err == nil {
+			upstreamHops = n + 1
+		}
+	}
+	// Fails only outside a gRPC call (e.g. direct handler use); nothing to report then
+	grpc.SetTrailer(ctx, metadata.Pairs(
+		UpstreamHopsTrailer, strconv.Itoa(upstreamHops),
+		UpstreamLatencyMsTrailer, formatMs(latency),
+	))
+	return &pb.BatchPlanResponse{Responses: responses}, callCost{batchShare: 1}, nil
+}
@@ -0,0 +1,87 @@
+// internal/handler/validate.go
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// WithParallelValidation validates the observations of BatchPlan calls of at
+// least minBatch observations on up to workers goroutines. Smaller batches,
+// where starting goroutines costs more than it saves, and workers <= 1
+// validate in order.
+func WithParallelValidation(workers, minBatch int) Option {
+	return func(h *Handler) {
+		h.validateWorkers = workers
+		h.validateMinBatch = max(minBatch, 1)
+	}
+}
+
+// forEachObservation calls check for every index of a batch of n
+// observations, in parallel when the batch is large enough, and returns the
+// error of the lowest failing index
+func (h *Handler) forEachObservation(n int, check func(i int) error) error {
+	if h.validateWorkers <= 1 || n < h.validateMinBatch {
+		return forEachSerial(n, check)
+	}
+	return forEachParallel(n, h.validateWorkers, check)
+}
+
+// forEachSerial calls check in index order, stopping at the first error
+func forEachSerial(n int, check func(i int) error) error {
+	for i := 0; i < n; i++ {
+		if err := check(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachParallel calls check for [0, n) on up to workers goroutines, each
+// taking a contiguous chunk in index order. Once an index fails, workers skip
+// every later index, but keep checking earlier ones, so the error returned
+// is the lowest failing index's: the same one forEachSerial returns.
+func forEachParallel(n, workers int, check func(i int) error) error {
+	workers = min(workers, n)
+	if workers <= 1 {
+		return forEachSerial(n, check)
+	}
+
+	var failed atomic.Int64 // Lowest failing index so far; n if none
+	failed.Store(int64(n))
+	errs := make([]error, workers)
+	chunk := (n + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for k := 0; k < workers; k++ {
+		lo, hi := k*chunk, min((k+1)*chunk, n)
+		if lo >= hi {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				if int64(i) > failed.Load() {
+					return
+				}
+				if err := check(i); err != nil {
+					errs[k] = err
+					for cur := failed.Load(); int64(i) < cur && !failed.CompareAndSwap(cur, int64(i)); cur = failed.Load() {
+					}
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Chunks are in index order and each worker stops at its first error, so
+	// the first one recorded is the lowest index's
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// internal/handler/validity.go
+package handler
+
+import (
+	"strings"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// ValidityRule sets how long actions stay valid for one class of robots
+type ValidityRule struct {
+	// Client matches the caller's client identity exactly or, when it ends in
+	// "/*", by prefix (e.g. "spiffe://example.org/robot/arm/*"); empty matches any
+	Client   string
+	Validity time.Duration
+}
+
+// matches reports whether the rule applies to callers with identity client
+func (r ValidityRule) matches(client string) bool {
+	return matchClient(r.Client, client)
+}
+
+// matchClient reports whether pattern matches the client identity exactly or,
+// when it ends in "/*", by prefix; an empty pattern matches any client
+func matchClient(pattern, client string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(client, prefix)
+	}
+	return pattern == "" || pattern == client
+}
+
+// actionValidity picks the validity of actions planned for a caller
+type actionValidity struct {
+	fallback time.Duration
+	rules    []ValidityRule
+}
+
+// forClient returns the validity of the first rule matching client, or the
+// fallback
+func (v *actionValidity) forClient(client string) time.Duration {
+	for _, r := range v.rules {
+		if r.matches(client) {
+			return r.Validity
+		}
+	}
+	return v.fallback
+}
+
+// stampValidity sets how long resp's action stays valid from planned on
+func stampValidity(resp *pb.PlanResponse, planned time.Time, validity time.Duration) {
+	if validity <= 0 {
+		return
+	}
+	resp.ValidityMs = uint32(validity.Milliseconds())
+	resp.ExpiresUnixNano = planned.Add(validity).UnixNano()
+}
@@ -0,0 +1,54 @@
+// internal/handler/versions.go
+package handler
+
+import (
+	"slices"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// WithModelVersions lets requests pin an older model version. The handler's
+// engine serves latest, which requests pinning no version get; versions maps
+// each older version to its engine.
+func WithModelVersions(latest string, versions map[string]inference.InferenceEngine) Option {
+	return func(h *Handler) {
+		h.latestVersion = latest
+		h.versions = versions
+	}
+}
+
+// modelVersion returns the version the batch's requests pin and its engine,
+// or the latest version and a nil engine, which means the handler's own. A
+// batch runs on one model, so its requests must pin the same version.
+func (h *Handler) modelVersion(req *pb.BatchPlanRequest) (string, inference.InferenceEngine, error) {
+	version := req.Requests[0].GetModelVersion()
+	for i, r := range req.Requests[1:] {
+		if r.GetModelVersion() != version {
+			return "", nil, invalidArgumentError(
+				"request %d pins model version %q, but request 0 pins %q; a batch runs on one version", i+1, r.GetModelVersion(), version)
+		}
+	}
+	if version == "" || version == h.latestVersion {
+		return h.latestVersion, nil, nil
+	}
+	engine, ok := h.versions[version]
+	if !ok {
+		return "", nil, invalidArgumentError("unknown model version %q", version)
+	}
+	return version, engine, nil
+}
+
+// modelVersions lists the versions requests may pin, the latest first, or
+// nil without model versions
+func (h *Handler) modelVersions() []string {
+	if h.latestVersion == "" {
+		return nil
+	}
+	older := make([]string, 0, len(h.versions))
+	for v := range h.versions {
+		older = append(older, v)
+	}
+	slices.Sort(older)
+	return append([]string{h.latestVersion}, older...)
+}
@@ -0,0 +1,72 @@
+// internal/handler/warmup.go
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Limits of one Warmup call, so it cannot monopolize the engine
+const (
+	MaxWarmupInferences = 1000
+	MaxWarmupBatchSize  = 1024
+)
+
+// WithWarmup sets the inferences per batch size and the batch sizes Warmup
+// runs when the request leaves them unset
+func WithWarmup(inferences int, batchSizes []int) Option {
+	return func(h *Handler) {
+		h.warmupInferences = inferences
+		h.warmupBatchSizes = batchSizes
+	}
+}
+
+// Warmup runs synthetic inferences of each batch size straight through the
+// engine, bypassing the scheduler, caches, and recorder
+func (h *Handler) Warmup(ctx context.Context, req *pb.WarmupRequest) (*pb.WarmupResponse, error) {
+	if h.infer == nil {
+		return nil, failedPreconditionError("inference engine not initialized")
+	}
+
+	n := int(req.GetInferences())
+	if n == 0 {
+		n = max(h.warmupInferences, 1)
+	}
+	if n > MaxWarmupInferences {
+		return nil, invalidArgumentError("inferences must be at most %d, got %d", MaxWarmupInferences, n)
+	}
+	batchSizes := h.warmupBatchSizes
+	if len(req.GetBatchSizes()) > 0 {
+		batchSizes = make([]int, len(req.BatchSizes))
+		for i, b := range req.BatchSizes {
+			if b == 0 || b > MaxWarmupBatchSize {
+				return nil, invalidArgumentError("batch sizes must be between 1 and %d, got %d", MaxWarmupBatchSize, b)
+			}
+			batchSizes[i] = int(b)
+		}
+	}
+	if len(batchSizes) == 0 {
+		batchSizes = []int{1}
+	}
+
+	results, err := inference.Warmup(h.infer, n, batchSizes)
+	if err != nil {
+		slog.ErrorContext(ctx, "Warmup failed", "request_id", middleware.GetRequestID(ctx), "error", err)
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.WarmupResponse{}
+	for _, r := range results {
+		resp.Batches = append(resp.Batches, &pb.WarmupBatch{
+			BatchSize:  uint32(r.BatchSize),
+			Inferences: uint32(r.Inferences),
+			FirstMs:    float64(r.First.Microseconds()) / 1000,
+			LastMs:     float64(r.Last.Microseconds()) / 1000,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,164 @@
+// internal/inference/breaker.go
+package inference
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// ErrCircuitOpen is returned without running inference while a Breaker is open
+var ErrCircuitOpen = errors.New("inference circuit breaker is open")
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerHalfOpen = "half_open"
+	BreakerOpen     = "open"
+)
+
+// breakerStateValues are the breaker_state gauge values of each state
+var breakerStateValues = map[string]float64{
+	BreakerClosed:   0,
+	BreakerHalfOpen: 1,
+	BreakerOpen:     2,
+}
+
+// Breaker is an InferenceEngine that stops calling an engine that keeps
+// failing. After Threshold consecutive failures it opens and fails every
+// batch with ErrCircuitOpen, so callers get an immediate Unavailable instead
+// of paying full inference latency for another error. Every ProbeInterval one
+// batch is let through as a probe: success closes the breaker, failure keeps
+// it open. Input errors and shutdown do not count as failures.
+type Breaker struct {
+	engine        InferenceEngine
+	threshold     int
+	probeInterval time.Duration
+	now           func() time.Time
+
+	mu       sync.Mutex
+	state    string
+	failures int       // Consecutive failures while closed
+	openedAt time.Time // When the breaker opened or the last probe failed
+}
+
+// NewBreaker wraps engine in a Breaker that opens after threshold consecutive
+// failures and probes the engine every probeInterval while open
+func NewBreaker(engine InferenceEngine, threshold int, probeInterval time.Duration) *Breaker {
+	metrics.SetBreakerState(breakerStateValues[BreakerClosed])
+	return &Breaker{
+		engine:        engine,
+		threshold:     threshold,
+		probeInterval: probeInterval,
+		now:           time.Now,
+		state:         BreakerClosed,
+	}
+}
+
+// Predict implements InferenceEngine
+func (b *Breaker) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	probe, ok := b.allow()
+	if !ok {
+		metrics.RecordBreakerRejected()
+		return nil, ErrCircuitOpen
+	}
+	actions, err := b.engine.Predict(obsBatch, c, h, w)
+	b.record(probe, err)
+	return actions, err
+}
+
+// allow reports whether a batch may run, and whether it is the probe of an
+// open breaker
+func (b *Breaker) allow() (probe, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerClosed:
+		return false, true
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.probeInterval {
+			return false, false
+		}
+		b.setStateLocked(BreakerHalfOpen)
+		return true, true
+	default:
+		// A probe is already running
+		return false, false
+	}
+}
+
+// record updates the breaker with the result of a batch
+func (b *Breaker) record(probe bool, err error) {
+	failed := isEngineFailure(err)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		switch {
+		case failed:
+			b.openedAt = b.now()
+			b.setStateLocked(BreakerOpen)
+			slog.Warn("Inference circuit breaker probe failed, staying open", "error", err)
+		case err == nil:
+			b.failures = 0
+			b.setStateLocked(BreakerClosed)
+			slog.Info("Inference circuit breaker closed after a successful probe")
+		default:
+			// The batch never reached the model; the next batch probes again
+			b.setStateLocked(BreakerOpen)
+		}
+		return
+	}
+	if b.state != BreakerClosed {
+		return
+	}
+	if !failed {
+		if err == nil {
+			b.failures = 0
+		}
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setStateLocked(BreakerOpen)
+		slog.Warn("Inference circuit breaker opened", "consecutive_failures", b.failures, "error", err)
+	}
+}
+
+// setStateLocked moves the breaker to state
+func (b *Breaker) setStateLocked(state string) {
+	b.state = state
+	metrics.SetBreakerState(breakerStateValues[state])
+}
+
+// State returns the breaker state: BreakerClosed, BreakerHalfOpen, or BreakerOpen
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Open reports whether batches are being failed fast
+func (b *Breaker) Open() bool {
+	return b.State() != BreakerClosed
+}
+
+// Unwrap returns the wrapped engine
+func (b *Breaker) Unwrap() InferenceEngine {
+	return b.engine
+}
+
+// Close closes the wrapped engine
+func (b *Breaker) Close() error {
+	return b.engine.Close()
+}
+
+// isEngineFailure reports whether err says the engine is unhealthy, rather
+// than that the batch was invalid or the engine is shutting down
+func isEngineFailure(err error) bool {
+	var inputErr *InputError
+	return err != nil && !errors.Is(err, ErrShuttingDown) && !errors.As(err, &inputErr)
+}
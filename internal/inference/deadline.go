@@ -0,0 +1,74 @@
+// internal/inference/deadline.go
+package inference
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/timeouts"
+)
+
+// ErrInferenceTimeout is returned when a batch runs past the inference timeout
+var ErrInferenceTimeout = errors.New("inference timed out")
+
+// Deadline is an InferenceEngine that stops waiting for a batch after the
+// stage's current timeout. ONNX Runtime cannot abandon a run, so the batch
+// keeps running in the background and its latency is still observed, which
+// lets an adaptive stage follow a model that has become slower.
+type Deadline struct {
+	engine InferenceEngine
+	stage  *timeouts.Stage
+}
+
+// NewDeadline wraps engine so batches time out after stage's timeout
+func NewDeadline(engine InferenceEngine, stage *timeouts.Stage) *Deadline {
+	return &Deadline{engine: engine, stage: stage}
+}
+
+type predictResult struct {
+	actions []float32
+	err     error
+}
+
+// Predict implements InferenceEngine
+func (d *Deadline) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	timeout := d.stage.Timeout()
+	if timeout <= 0 {
+		start := time.Now()
+		actions, err := d.engine.Predict(obsBatch, c, h, w)
+		if err == nil {
+			d.stage.Observe(time.Since(start))
+		}
+		return actions, err
+	}
+
+	done := make(chan predictResult, 1)
+	go func() {
+		start := time.Now()
+		actions, err := d.engine.Predict(obsBatch, c, h, w)
+		if err == nil {
+			d.stage.Observe(time.Since(start))
+		}
+		done <- predictResult{actions, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.actions, r.err
+	case <-timer.C:
+		d.stage.Expired()
+		return nil, ErrInferenceTimeout
+	}
+}
+
+// Unwrap returns the wrapped engine
+func (d *Deadline) Unwrap() InferenceEngine {
+	return d.engine
+}
+
+// Close closes the wrapped engine
+func (d *Deadline) Close() error {
+	return d.engine.Close()
+}
@@ -0,0 +1,42 @@
+// internal/inference/devices.go
+package inference
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// nvidiaSMI runs nvidia-smi with args; replaceable in tests
+var nvidiaSMI = func(ctx context.Context, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, "nvidia-smi", args...).Output()
+}
+
+// DeviceMemoryUsed returns the memory in use (bytes) on each GPU, by device
+// index. ORT does not expose device memory, so this queries nvidia-smi.
+func DeviceMemoryUsed(ctx context.Context) (map[int]uint64, error) {
+	out, err := nvidiaSMI(ctx, "--query-gpu=index,memory.used", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GPU memory: %w", err)
+	}
+
+	used := make(map[int]uint64)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		index, mib, ok := strings.Cut(line, ",")
+		if !ok {
+			continue
+		}
+		device, err := strconv.Atoi(strings.TrimSpace(index))
+		if err != nil {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(mib), 10, 64)
+		if err != nil {
+			continue
+		}
+		used[device] = value << 20 // MiB
+	}
+	return used, nil
+}
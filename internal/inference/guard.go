@@ -0,0 +1,39 @@
+// internal/inference/guard.go
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrSessionQuarantined is returned while a session that panicked is recreated
+var ErrSessionQuarantined = errors.New("inference session quarantined after a panic")
+
+// PanicError is a panic recovered from the inference call path
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error implements error
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("inference panicked: %v", e.Value)
+}
+
+// runGuarded runs fn on a dedicated goroutine and converts panics into a
+// *PanicError. Memory faults in Go code reading native (cgo) buffers become
+// panics too; faults inside native code itself still terminate the process.
+func runGuarded(fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		debug.SetPanicOnFault(true)
+		defer func() {
+			if r := recover(); r != nil {
+				done <- &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		done <- fn()
+	}()
+	return <-done
+}
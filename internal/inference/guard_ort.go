@@ -0,0 +1,48 @@
+// internal/inference/guard_ort.go
+
+//go:build cgo && !noort
+
+package inference
+
+import (
+	"log/slog"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// quarantine takes a session that panicked out of service and recreates it in
+// the background; the caller must hold inf.mu
+func (inf *Inference) quarantine(p *PanicError) {
+	metrics.RecordInferencePanic()
+	slog.Error("Inference panic; quarantining session", "model", inf.info.ModelPath, "panic", p.Value, "stack", string(p.Stack))
+
+	inf.quarantined = true
+	go inf.recreate()
+}
+
+// recreate replaces a quarantined session with a fresh one from the model file
+func (inf *Inference) recreate() {
+	inf.mu.Lock()
+	defer inf.mu.Unlock()
+
+	if inf.closing.Load() || !inf.quarantined {
+		return
+	}
+
+	// The old session's native state may be corrupt; destroying it is best effort
+	if err := runGuarded(inf.session.Destroy); err != nil {
+		slog.Warn("Failed to destroy quarantined session", "error", err)
+	}
+	inf.session = nil
+
+	fresh, err := newSession(inf.info.ModelPath, inf.opts)
+	if err != nil {
+		// Predicts fail with "session is nil" until the model is reloaded
+		slog.Error("Failed to recreate quarantined session", "error", err)
+		inf.quarantined = false
+		return
+	}
+	inf.session = fresh.session
+	inf.quarantined = false
+	slog.Info("Recreated inference session", "model", inf.info.ModelPath)
+}
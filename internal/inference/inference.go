@@ -1,47 +1,193 @@
 // internal/inference/inference.go
+
+//go:build cgo && !noort
+
 package inference
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	ort "github.com/yalue/onnxruntime_go"
 )
 
+// RuntimeAvailable reports whether this build links ONNX Runtime
+const RuntimeAvailable = true
+
 // Inference wraps an ONNX runtime session for thread-safe inference.
 // It implements the InferenceEngine interface.
 type Inference struct {
 	mu        sync.Mutex
 	session   *ort.DynamicAdvancedSession
+	io        modelIO
 	actionDim int64
+
+	// closing rejects new Predicts while in-flight ones finish
+	closing atomic.Bool
+
+	// quarantined is set after a panic until the session is recreated with opts
+	quarantined bool
+	opts        Options
+
+	// info describes the session's providers and thread pools
+	info RuntimeInfo
+}
+
+// The ORT environment is shared by every session in the process, so it is
+// initialized with the first session and destroyed only after the last one
+// (primary, rollout candidates, per-GPU sessions) is closed
+var (
+	envMu   sync.Mutex
+	envRefs int
+)
+
+// acquireEnvironment initializes the ORT environment if no session holds it
+func acquireEnvironment() error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	if envRefs == 0 {
+		if err := ort.InitializeEnvironment(); err != nil {
+			return err
+		}
+	}
+	envRefs++
+	return nil
+}
+
+// releaseEnvironment destroys the ORT environment once no session holds it
+func releaseEnvironment() error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	envRefs--
+	if envRefs > 0 {
+		return nil
+	}
+	return ort.DestroyEnvironment()
 }
 
 // New creates a new Inference instance by loading the ONNX model from modelPath
 func New(modelPath string) (*Inference, error) {
+	return NewWithOptions(modelPath, Options{})
+}
+
+// NewWithOptions creates a new Inference instance using the given session options
+func NewWithOptions(modelPath string, opts Options) (*Inference, error) {
 	// Initialize the ONNX runtime environment
-	err := ort.InitializeEnvironment()
-	if err != nil {
+	if err := acquireEnvironment(); err != nil {
 		return nil, fmt.Errorf("failed to initialize ONNX environment: %w", err)
 	}
 
-	// Create input/output names - adjust based on your model
-	inputNames := []string{"obs"}
-	outputNames := []string{"action"}
+	inf, err := newSession(modelPath, opts)
+	if err != nil {
+		releaseEnvironment()
+		return nil, err
+	}
+	return inf, nil
+}
+
+// newSession creates the ORT session; the environment must already be acquired
+func newSession(modelPath string, opts Options) (*Inference, error) {
+	sessionOpts, err := ort.NewSessionOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session options: %w", err)
+	}
+	defer sessionOpts.Destroy()
+
+	info, err := configureProviders(sessionOpts, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the graph TensorRT compiled for this model on an earlier start,
+	// otherwise have ORT write the graph it compiles during this load
+	loadPath := modelPath
+	cacheDir, cacheHit := "", false
+	if opts.OptimizedModelDir != "" && slices.Contains(info.AppendedProviders, ProviderTensorRT) {
+		cacheDir, cacheHit, err = configureOptimizedModelCache(sessionOpts, modelPath, opts)
+		if err != nil {
+			return nil, err
+		}
+		if cacheHit {
+			loadPath = filepath.Join(cacheDir, optimizedModelName)
+		}
+	}
+
+	// Take tensor names and shapes from the model itself
+	io, err := readModelIO(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	if want := opts.Quantization.inputType(); io.inputType != want {
+		return nil, fmt.Errorf("model input %q is %s but the configured input type is %s", io.inputName, io.inputType, want)
+	}
+	if err := opts.Quantization.CheckFormat(modelPath); err != nil {
+		return nil, err
+	}
 
 	// Create a dynamic session that supports variable batch sizes
 	session, err := ort.NewDynamicAdvancedSession(
-		modelPath,
-		inputNames,
-		outputNames,
-		nil, // Use default session options
+		loadPath,
+		[]string{io.inputName},
+		[]string{io.outputName},
+		sessionOpts,
 	)
 	if err != nil {
+		// A cached graph the runtime cannot load, e.g. one compiled for
+		// another GPU, is compiled again from the model
+		if cacheHit {
+			if err := os.RemoveAll(cacheDir); err != nil {
+				return nil, fmt.Errorf("failed to clear optimized model cache: %w", err)
+			}
+			return newSession(modelPath, opts)
+		}
+		// TensorRT often fails while building its engine for the model rather
+		// than when the provider is appended; retry with the next providers
+		if fallback, ok := withoutProvider(opts, ProviderTensorRT, info); ok {
+			inf, retryErr := newSession(modelPath, fallback)
+			if retryErr != nil {
+				return nil, retryErr
+			}
+			inf.info.RequestedProviders = opts.ExecutionProviders
+			inf.info.ProviderErrors = append([]string{fmt.Sprintf("%s: session creation failed: %v", ProviderTensorRT, err)}, inf.info.ProviderErrors...)
+			return inf, nil
+		}
 		return nil, fmt.Errorf("failed to create ONNX session: %w", err)
 	}
 
+	// Record that the freshly written graph is complete
+	if cacheDir != "" && !cacheHit {
+		if _, err := writeOptimizedModelStamp(cacheDir); err != nil {
+			session.Destroy()
+			return nil, err
+		}
+	}
+
+	info.ModelPath = modelPath
+	info.OptimizedCacheHit = cacheHit
+	info.InputShape = io.inputShape
+	info.OutputShape = io.outputShape
+	info.InputType = io.inputType
+	info.Quantization = opts.Quantization.Format
+
+	// Models with a dynamic action dimension need SetActionDim
+	actionDim := io.actionDim()
+	if actionDim == 0 {
+		actionDim = 2
+	}
+
 	return &Inference{
 		session:   session,
-		actionDim: 2, // Default action dimension, adjust as needed
+		io:        io,
+		actionDim: actionDim,
+		info:      info,
+		opts:      opts,
 	}, nil
 }
 
@@ -50,16 +196,32 @@ func New(modelPath string) (*Inference, error) {
 // c, h, w: channel, height, width dimensions
 // Returns flattened actions of length batch * actionDim
 func (inf *Inference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	if inf.closing.Load() {
+		return nil, ErrShuttingDown
+	}
+
 	inf.mu.Lock()
 	defer inf.mu.Unlock()
 
+	// Close may have run while this call waited for the lock
+	if inf.closing.Load() {
+		return nil, ErrShuttingDown
+	}
+	if inf.quarantined {
+		return nil, ErrSessionQuarantined
+	}
 	if inf.session == nil {
 		return nil, fmt.Errorf("inference session is nil")
 	}
 
 	batch := int64(len(obsBatch))
 	if batch == 0 {
-		return nil, fmt.Errorf("empty observation batch")
+		return nil, inputErrorf("empty observation batch")
+	}
+
+	inputShape, err := inf.io.inputDims(batch, c, h, w)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate expected observation size
@@ -69,14 +231,32 @@ func (inf *Inference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, e
 	tensorData := make([]float32, 0, batch*obsSize)
 	for i, obs := range obsBatch {
 		if int64(len(obs)) != obsSize {
-			return nil, fmt.Errorf("observation %d has wrong size: got %d, expected %d", i, len(obs), obsSize)
+			return nil, inputErrorf("observation %d has wrong size: got %d, expected %d", i, len(obs), obsSize)
 		}
 		tensorData = append(tensorData, obs...)
 	}
 
-	// Create input tensor with shape [batch, C, H, W]
-	inputShape := ort.NewShape(batch, c, h, w)
-	inputTensor, err := ort.NewTensor(inputShape, tensorData)
+	// Everything touching native memory runs guarded, so a panic quarantines
+	// the session instead of taking down the process
+	var actions []float32
+	err = runGuarded(func() error {
+		var err error
+		actions, err = inf.run(tensorData, inputShape)
+		return err
+	})
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		inf.quarantine(panicErr)
+	}
+	return actions, err
+}
+
+// run executes the session on a packed batch of observations
+func (inf *Inference) run(tensorData []float32, inputShape ort.Shape) ([]float32, error) {
+	batch := inputShape[0]
+
+	// Create input tensor with shape [batch, C, H, W] or [batch, N]
+	inputTensor, err := inf.inputTensor(inputShape, tensorData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create input tensor: %w", err)
 	}
@@ -104,8 +284,27 @@ func (inf *Inference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, e
 	return outputTensor.GetData(), nil
 }
 
-// Close releases the ONNX session resources
+// inputTensor creates the model's input tensor from a packed batch,
+// quantizing it for int8 and uint8 inputs
+func (inf *Inference) inputTensor(shape ort.Shape, data []float32) (ort.ArbitraryTensor, error) {
+	switch inf.io.inputType {
+	case InputInt8:
+		return ort.NewTensor(shape, inf.opts.Quantization.quantizeInt8(data))
+	case InputUint8:
+		return ort.NewTensor(shape, inf.opts.Quantization.quantizeUint8(data))
+	default:
+		return ort.NewTensor(shape, data)
+	}
+}
+
+// Close rejects new Predicts with ErrShuttingDown, waits for the in-flight
+// run to finish, and releases the session. The ORT environment is destroyed
+// after the last session in the process is closed.
 func (inf *Inference) Close() error {
+	if inf.closing.Swap(true) {
+		return nil
+	}
+
 	inf.mu.Lock()
 	defer inf.mu.Unlock()
 
@@ -113,18 +312,33 @@ func (inf *Inference) Close() error {
 		err := inf.session.Destroy()
 		inf.session = nil
 		if err != nil {
+			releaseEnvironment()
 			return fmt.Errorf("failed to destroy session: %w", err)
 		}
 	}
 
-	return ort.DestroyEnvironment()
+	return releaseEnvironment()
+}
+
+// RuntimeInfo reports the appended execution providers, thread pools, and ORT version
+func (inf *Inference) RuntimeInfo() RuntimeInfo {
+	inf.mu.Lock()
+	defer inf.mu.Unlock()
+	info := inf.info
+	info.ActionDim = inf.actionDim
+	return info
 }
 
-// SetActionDim sets the action dimension for the model
-func (inf *Inference) SetActionDim(dim int64) {
+// SetActionDim sets the action dimension for models whose output shape is
+// dynamic. It fails if the model declares a different fixed action dimension.
+func (inf *Inference) SetActionDim(dim int64) error {
 	inf.mu.Lock()
 	defer inf.mu.Unlock()
+	if detected := inf.io.actionDim(); detected != 0 && detected != dim {
+		return fmt.Errorf("model output shape %v has %d values per observation, not %d", inf.io.outputShape, detected, dim)
+	}
 	inf.actionDim = dim
+	return nil
 }
 
 // Ensure Inference implements InferenceEngine at compile time
@@ -0,0 +1,216 @@
+// internal/inference/inference_ort_test.go
+
+//go:build cgo && !noort
+
+package inference
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ort "github.com/yalue/onnxruntime_go"
+
+	"github.com/SyedDaiam9101/policy-service/testenv"
+)
+
+func TestRealInference_WithModel(t *testing.T) {
+	// The shared dummy model: action = (obs[0] + obs[2], obs[1] + obs[3])
+	modelPath := filepath.Join(t.TempDir(), "dummy.onnx")
+	if err := testenv.WriteDummyModel(modelPath, 1, 2, 2, 2); err != nil {
+		t.Fatalf("WriteDummyModel failed: %v", err)
+	}
+
+	// Try to create inference - will fail if ONNX library not installed
+	infer, err := New(modelPath)
+	if err != nil {
+		t.Skipf("Skipping real inference test: %v", err)
+	}
+	defer infer.Close()
+
+	// The action dimension comes from the model's output shape
+	if dim := infer.RuntimeInfo().ActionDim; dim != 2 {
+		t.Fatalf("Expected action dim 2 from model metadata, got %d", dim)
+	}
+
+	obsBatch := [][]float32{
+		{0.1, 0.2, 0.3, 0.4},
+		{1, 2, 3, 4},
+	}
+
+	actions, err := infer.Predict(obsBatch, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+
+	// action = (obs[0] + obs[2], obs[1] + obs[3]) per observation
+	expected := []float32{0.4, 0.6, 4, 6}
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d", len(expected), len(actions))
+	}
+	for i := range expected {
+		if math.Abs(float64(actions[i]-expected[i])) > 1e-5 {
+			t.Fatalf("Expected actions %v, got %v", expected, actions)
+		}
+	}
+
+	// The model's fixed input dims reject other layouts
+	if _, err := infer.Predict([][]float32{{1, 2, 3, 4}}, 4, 1, 1); err == nil {
+		t.Error("Expected error for observation dims that do not match the model")
+	}
+}
+
+func TestOptimizedModelCache(t *testing.T) {
+	if err := acquireEnvironment(); err != nil {
+		t.Skipf("Skipping optimized model cache test: %v", err)
+	}
+	defer releaseEnvironment()
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "policy.onnx")
+	if err := os.WriteFile(modelPath, []byte("model-v1"), 0o644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	o := Options{OptimizedModelDir: filepath.Join(dir, "cache")}
+
+	configure := func(o Options) (string, bool, *ort.SessionOptions) {
+		t.Helper()
+		opts, err := ort.NewSessionOptions()
+		if err != nil {
+			t.Fatalf("NewSessionOptions failed: %v", err)
+		}
+		t.Cleanup(func() { opts.Destroy() })
+		cacheDir, hit, err := configureOptimizedModelCache(opts, modelPath, o)
+		if err != nil {
+			t.Fatalf("configureOptimizedModelCache failed: %v", err)
+		}
+		return cacheDir, hit, opts
+	}
+
+	// Nothing cached yet, so ORT is asked to write the compiled graph
+	cacheDir, hit, opts := configure(o)
+	if hit {
+		t.Fatal("Expected a cache miss without a compiled graph")
+	}
+	if got, err := opts.GetSessionConfigEntry("ep.context_file_path"); err != nil || got != filepath.Join(cacheDir, optimizedModelName) {
+		t.Errorf("Expected the compiled graph written to the cache, got %q (%v)", got, err)
+	}
+	if written, err := writeOptimizedModelStamp(cacheDir); err != nil || written {
+		t.Errorf("Expected no stamp without a compiled graph, got %v (%v)", written, err)
+	}
+
+	// Simulate ORT having written the graph
+	if err := os.WriteFile(filepath.Join(cacheDir, optimizedModelName), []byte("compiled"), 0o644); err != nil {
+		t.Fatalf("Failed to write compiled graph: %v", err)
+	}
+	if _, hit, _ := configure(o); hit {
+		t.Fatal("Expected a cache miss without a stamp")
+	}
+	os.WriteFile(filepath.Join(cacheDir, optimizedModelName), []byte("compiled"), 0o644)
+	if written, err := writeOptimizedModelStamp(cacheDir); err != nil || !written {
+		t.Fatalf("Expected the graph stamped, got %v (%v)", written, err)
+	}
+	if got, hit, _ := configure(o); !hit || got != cacheDir {
+		t.Errorf("Expected a cache hit in %s, got %v in %s", cacheDir, hit, got)
+	}
+
+	// Another precision or a changed model compiles a new graph
+	fp16 := o
+	fp16.TensorRTFP16 = true
+	if got, hit, _ := configure(fp16); hit || got == cacheDir {
+		t.Errorf("Expected a cache miss in a new directory for FP16, got %v in %s", hit, got)
+	}
+	if err := os.WriteFile(modelPath, []byte("model-v2-larger"), 0o644); err != nil {
+		t.Fatalf("Failed to rewrite model: %v", err)
+	}
+	if got, hit, _ := configure(o); hit || got == cacheDir {
+		t.Errorf("Expected a cache miss in a new directory after the model changed, got %v in %s", hit, got)
+	}
+}
+
+func TestConfigureProviders_IntraOpAffinity(t *testing.T) {
+	if err := acquireEnvironment(); err != nil {
+		t.Skipf("Skipping session options test: %v", err)
+	}
+	defer releaseEnvironment()
+	opts, err := ort.NewSessionOptions()
+	if err != nil {
+		t.Fatalf("NewSessionOptions failed: %v", err)
+	}
+	defer opts.Destroy()
+
+	info, err := configureProviders(opts, Options{IntraOpThreads: 3, IntraOpAffinity: "2;3"})
+	if err != nil {
+		t.Fatalf("configureProviders failed: %v", err)
+	}
+	if info.IntraOpAffinity != "2;3" {
+		t.Errorf("Expected affinity 2;3 in the runtime info, got %q", info.IntraOpAffinity)
+	}
+	got, err := opts.GetSessionConfigEntry("session.intra_op_thread_affinities")
+	if err != nil || got != "2;3" {
+		t.Errorf("Expected session config entry 2;3, got %q (%v)", got, err)
+	}
+}
+
+// floatTensor describes a float32 model input or output
+func floatTensor(name string, dims ...int64) ort.InputOutputInfo {
+	return ort.InputOutputInfo{Name: name, DataType: ort.TensorElementDataTypeFloat, Dimensions: ort.NewShape(dims...)}
+}
+
+func TestParseModelIO(t *testing.T) {
+	io, err := parseModelIO(
+		[]ort.InputOutputInfo{floatTensor("obs", -1, 3, 64, 64), floatTensor("mask", -1, 4)},
+		[]ort.InputOutputInfo{floatTensor("logits", -1, 7)},
+	)
+	if err != nil {
+		t.Fatalf("parseModelIO failed: %v", err)
+	}
+	if io.inputName != "obs" || io.outputName != "logits" {
+		t.Fatalf("Expected obs -> logits, got %s -> %s", io.inputName, io.outputName)
+	}
+	if dim := io.actionDim(); dim != 7 {
+		t.Errorf("Expected action dim 7, got %d", dim)
+	}
+	if _, err := io.inputDims(2, 3, 64, 64); err != nil {
+		t.Errorf("Expected matching dims to pass, got %v", err)
+	}
+	if _, err := io.inputDims(2, 1, 64, 64); err == nil {
+		t.Error("Expected mismatched channels to fail")
+	}
+
+	// Ambiguous inputs without an "obs" tensor
+	_, err = parseModelIO(
+		[]ort.InputOutputInfo{floatTensor("a", -1, 4), floatTensor("b", -1, 4)},
+		[]ort.InputOutputInfo{floatTensor("action", -1, 2)},
+	)
+	if err == nil {
+		t.Error("Expected error for ambiguous inputs")
+	}
+
+	// Integer outputs cannot be read into float32 actions
+	_, err = parseModelIO(
+		[]ort.InputOutputInfo{floatTensor("obs", -1, 4)},
+		[]ort.InputOutputInfo{{Name: "action", DataType: ort.TensorElementDataTypeInt64, Dimensions: ort.NewShape(-1, 2)}},
+	)
+	if err == nil {
+		t.Error("Expected error for a non-float output")
+	}
+}
+
+func TestModelIO_DynamicAndFlatShapes(t *testing.T) {
+	io := modelIO{inputShape: []int64{-1, 12}, outputShape: []int64{-1, -1}}
+	if dim := io.actionDim(); dim != 0 {
+		t.Errorf("Expected dynamic action dim 0, got %d", dim)
+	}
+
+	shape, err := io.inputDims(5, 3, 2, 2)
+	if err != nil {
+		t.Fatalf("inputDims failed: %v", err)
+	}
+	if len(shape) != 2 || shape[0] != 5 || shape[1] != 12 {
+		t.Errorf("Expected flattened shape [5 12], got %v", shape)
+	}
+	if _, err := io.inputDims(5, 1, 2, 2); err == nil {
+		t.Error("Expected error when flattened size differs")
+	}
+}
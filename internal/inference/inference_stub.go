@@ -0,0 +1,52 @@
+// internal/inference/inference_stub.go
+
+//go:build !cgo || noort
+
+package inference
+
+import "errors"
+
+// RuntimeAvailable reports whether this build links ONNX Runtime
+const RuntimeAvailable = false
+
+// ErrRuntimeUnavailable is returned when loading a model in a build without
+// ONNX Runtime (CGO_ENABLED=0 or the noort tag)
+var ErrRuntimeUnavailable = errors.New("built without ONNX Runtime; rebuild with cgo and without the noort tag")
+
+// Inference stands in for the ONNX Runtime session in builds without it.
+// No value can be created, so models cannot be loaded; use MockInference or
+// another InferenceEngine instead.
+type Inference struct{}
+
+// New always fails in builds without ONNX Runtime
+func New(modelPath string) (*Inference, error) {
+	return NewWithOptions(modelPath, Options{})
+}
+
+// NewWithOptions always fails in builds without ONNX Runtime
+func NewWithOptions(modelPath string, opts Options) (*Inference, error) {
+	return nil, ErrRuntimeUnavailable
+}
+
+// Predict implements InferenceEngine
+func (inf *Inference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	return nil, ErrRuntimeUnavailable
+}
+
+// Close implements InferenceEngine
+func (inf *Inference) Close() error {
+	return nil
+}
+
+// RuntimeInfo reports the appended execution providers, thread pools, and ORT version
+func (inf *Inference) RuntimeInfo() RuntimeInfo {
+	return RuntimeInfo{}
+}
+
+// SetActionDim sets the action dimension for models whose output shape is dynamic
+func (inf *Inference) SetActionDim(dim int64) error {
+	return ErrRuntimeUnavailable
+}
+
+// Ensure Inference implements InferenceEngine at compile time
+var _ InferenceEngine = (*Inference)(nil)
@@ -0,0 +1,19 @@
+// internal/inference/inference_stub_test.go
+
+//go:build !cgo || noort
+
+package inference
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStubInference_Unavailable(t *testing.T) {
+	if RuntimeAvailable {
+		t.Fatal("Expected RuntimeAvailable to be false without ONNX Runtime")
+	}
+	if _, err := NewWithOptions("policy.onnx", Options{}); !errors.Is(err, ErrRuntimeUnavailable) {
+		t.Fatalf("Expected ErrRuntimeUnavailable, got %v", err)
+	}
+}
@@ -2,8 +2,12 @@
 package inference
 
 import (
-	"os"
+	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/timeouts"
 )
 
 func TestMockInference_Predict(t *testing.T) {
@@ -99,36 +103,382 @@ func TestMockInference_CustomAction(t *testing.T) {
 	}
 }
 
-func TestRealInference_WithModel(t *testing.T) {
-	// Skip if ONNX model or library is not available
-	modelPath := "testdata/dummy.onnx"
-	if _, err := os.Stat(modelPath); os.IsNotExist(err) {
-		t.Skip("Skipping real inference test: testdata/dummy.onnx not found")
+// passthrough wraps an engine without describing itself
+type passthrough struct {
+	InferenceEngine
+}
+
+func (p passthrough) Unwrap() InferenceEngine { return p.InferenceEngine }
+
+func TestDescribe_UnwrapsEngines(t *testing.T) {
+	info, ok := Describe(passthrough{passthrough{NewMockWithAction([]float32{1, 2, 3, 4})}})
+	if !ok {
+		t.Fatal("Expected wrapped mock to be described")
+	}
+	if info.ActionDim != 4 || len(info.AppendedProviders) != 1 || info.AppendedProviders[0] != "mock" {
+		t.Errorf("Unexpected runtime info: %+v", info)
 	}
 
-	// Try to create inference - will fail if ONNX library not installed
-	infer, err := New(modelPath)
+	if _, ok := Describe(passthrough{}); ok {
+		t.Error("Expected no runtime info for an empty wrapper")
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	mock := NewMock()
+	results, err := Warmup(mock, 3, []int{1, 8})
 	if err != nil {
-		t.Skipf("Skipping real inference test: %v", err)
+		t.Fatalf("Warmup failed: %v", err)
 	}
-	defer infer.Close()
+	if mock.CallCount != 6 {
+		t.Errorf("Expected 6 inferences, got %d", mock.CallCount)
+	}
+	if len(results) != 2 || results[1].BatchSize != 8 || results[1].Inferences != 3 {
+		t.Errorf("Unexpected warmup results: %+v", results)
+	}
+
+	mock.Close()
+	if _, err := Warmup(mock, 1, []int{1}); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown from a closed engine, got %v", err)
+	}
+}
 
-	// Set action dimension (adjust based on your dummy model)
-	infer.SetActionDim(2)
+func TestPool_RoundRobin(t *testing.T) {
+	a, b := NewMockWithAction([]float32{1}), NewMockWithAction([]float32{2})
+	pool, err := NewPool([]InferenceEngine{a, b}, []int{0, 1}, DispatchRoundRobin)
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
 
-	// Test with a single observation
-	obsBatch := [][]float32{
-		{0.1, 0.2, 0.3, 0.4},
+	for i := 0; i < 4; i++ {
+		if _, err := pool.Predict([][]float32{{0.1}}, 1, 1, 1); err != nil {
+			t.Fatalf("Predict failed: %v", err)
+		}
+	}
+	if a.CallCount != 2 || b.CallCount != 2 {
+		t.Errorf("Expected batches split evenly, got %d and %d", a.CallCount, b.CallCount)
 	}
 
-	actions, err := infer.Predict(obsBatch, 1, 2, 2)
+	info := pool.RuntimeInfo()
+	if len(info.Devices) != 2 || info.Devices[1] != 1 {
+		t.Errorf("Expected devices [0 1], got %v", info.Devices)
+	}
+}
+
+func TestPool_LeastLoaded(t *testing.T) {
+	a, b := NewMockWithAction([]float32{1}), NewMockWithAction([]float32{2})
+	pool, err := NewPool([]InferenceEngine{a, b}, []int{0, 1}, DispatchLeastLoaded)
 	if err != nil {
-		t.Fatalf("Predict failed: %v", err)
+		t.Fatalf("NewPool failed: %v", err)
 	}
 
-	// Verify output length matches actionDim
-	expectedLen := 1 * 2 // batch=1, actionDim=2
-	if len(actions) != expectedLen {
-		t.Errorf("Expected %d actions, got %d", expectedLen, len(actions))
+	// Device 0 is busy, so every batch goes to device 1
+	pool.sessions[0].inflight.Add(1)
+	for i := 0; i < 3; i++ {
+		pool.Predict([][]float32{{0.1}}, 1, 1, 1)
+	}
+	if a.CallCount != 0 || b.CallCount != 3 {
+		t.Errorf("Expected all batches on the idle device, got %d and %d", a.CallCount, b.CallCount)
+	}
+
+	if _, err := NewPool([]InferenceEngine{a}, []int{0, 1}, DispatchLeastLoaded); err == nil {
+		t.Error("Expected error for mismatched engines and devices")
+	}
+}
+
+func TestDeviceMemoryUsed(t *testing.T) {
+	orig := nvidiaSMI
+	defer func() { nvidiaSMI = orig }()
+	nvidiaSMI = func(ctx context.Context, args ...string) ([]byte, error) {
+		return []byte("0, 1024\n1, 2048\n"), nil
+	}
+
+	used, err := DeviceMemoryUsed(context.Background())
+	if err != nil {
+		t.Fatalf("DeviceMemoryUsed failed: %v", err)
+	}
+	if used[0] != 1024<<20 || used[1] != 2048<<20 {
+		t.Errorf("Unexpected memory usage: %v", used)
+	}
+}
+
+// blockingEngine holds Predict until release is closed
+type blockingEngine struct {
+	*MockInference
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingEngine) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	close(b.started)
+	<-b.release
+	return b.MockInference.Predict(obsBatch, c, h, w)
+}
+
+func TestSwappable_DrainsBeforeClosing(t *testing.T) {
+	old := &blockingEngine{MockInference: NewMockWithAction([]float32{1}), started: make(chan struct{}), release: make(chan struct{})}
+	s := NewSwappable(old, "v1")
+
+	obs := [][]float32{{0.1, 0.2, 0.3, 0.4}}
+	inflight := make(chan error, 1)
+	go func() {
+		_, err := s.Predict(obs, 1, 2, 2)
+		inflight <- err
+	}()
+	<-old.started
+
+	next := NewMockWithAction([]float32{2})
+	swapped := make(chan error, 1)
+	go func() { swapped <- s.Swap(next, "v2", false) }()
+
+	select {
+	case <-swapped:
+		t.Fatal("Expected Swap to wait for the in-flight Predict")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(old.release)
+	if err := <-inflight; err != nil {
+		t.Fatalf("In-flight Predict failed: %v", err)
+	}
+	if err := <-swapped; err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+	if !old.Closed {
+		t.Error("Expected the previous engine to be closed")
+	}
+
+	actions, err := s.Predict(obs, 1, 2, 2)
+	if err != nil || actions[0] != 2 {
+		t.Fatalf("Expected the new engine to serve, got %v (err %v)", actions, err)
+	}
+
+	s.Close()
+	if _, err := s.Predict(obs, 1, 2, 2); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown after Close, got %v", err)
+	}
+	late := NewMock()
+	if err := s.Swap(late, "v3", false); !errors.Is(err, ErrShuttingDown) || !late.Closed {
+		t.Errorf("Expected a Swap after Close to close the new engine, got %v", err)
+	}
+}
+
+func TestSwappable_Rollback(t *testing.T) {
+	v1, v2, v3 := NewMockWithAction([]float32{1}), NewMockWithAction([]float32{2}), NewMockWithAction([]float32{3})
+	s := NewSwappable(v1, "v1")
+	if _, err := s.Rollback(); !errors.Is(err, ErrNoSpare) {
+		t.Fatalf("Expected ErrNoSpare before any swap, got %v", err)
+	}
+
+	if err := s.Swap(v2, "v2", true); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+	if v1.Closed {
+		t.Fatal("Expected the previous engine to stay loaded as the spare")
+	}
+	if slots := s.Resident(); len(slots) != 2 || slots[0].Version != "v2" || !slots[0].Active || slots[1].Version != "v1" || slots[1].Active {
+		t.Fatalf("Expected v2 active with v1 spare, got %+v", slots)
+	}
+
+	obs := [][]float32{{0.1, 0.2, 0.3, 0.4}}
+	version, err := s.Rollback()
+	if err != nil || version != "v1" {
+		t.Fatalf("Expected a rollback to v1, got %q (err %v)", version, err)
+	}
+	if actions, _ := s.Predict(obs, 1, 2, 2); actions[0] != 1 {
+		t.Errorf("Expected v1 to serve after rollback, got %v", actions)
+	}
+	if slots := s.Resident(); slots[0].Version != "v1" || slots[1].Version != "v2" {
+		t.Errorf("Expected v2 kept as the spare, got %+v", slots)
+	}
+
+	// A swap without keeping the previous engine drops the spare too
+	if err := s.Swap(v3, "v3", false); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+	if !v1.Closed || !v2.Closed {
+		t.Error("Expected the previous engine and the spare to be closed")
+	}
+	if slots := s.Resident(); len(slots) != 1 || slots[0].Version != "v3" {
+		t.Errorf("Expected only v3 resident, got %+v", slots)
+	}
+	s.Close()
+	if !v3.Closed {
+		t.Error("Expected Close to close the active engine")
+	}
+}
+
+func TestRunGuarded_RecoversPanics(t *testing.T) {
+	if err := runGuarded(func() error { return nil }); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err := runGuarded(func() error { panic("ort callback failed") })
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "ort callback failed" || len(panicErr.Stack) == 0 {
+		t.Fatalf("Expected a PanicError with stack, got %v", err)
+	}
+
+	// Nil dereferences (e.g. a destroyed tensor) are contained as well
+	var tensor *struct{ data []float32 }
+	err = runGuarded(func() error {
+		_ = tensor.data
+		return nil
+	})
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Expected a PanicError for a nil dereference, got %v", err)
+	}
+}
+
+func TestProviderOptions_CUDAMemLimit(t *testing.T) {
+	o := Options{DeviceID: 2, CUDAMemLimit: 4 << 30}
+
+	cuda := providerOptions(ProviderCUDA, o)
+	if cuda["device_id"] != "2" || cuda["gpu_mem_limit"] != "4294967296" {
+		t.Errorf("Unexpected CUDA provider options: %v", cuda)
+	}
+
+	// The limit is a CUDA provider option only
+	if _, ok := providerOptions(ProviderTensorRT, o)["gpu_mem_limit"]; ok {
+		t.Error("Expected no gpu_mem_limit for TensorRT")
+	}
+	if _, ok := providerOptions(ProviderCUDA, Options{})["gpu_mem_limit"]; ok {
+		t.Error("Expected no gpu_mem_limit when unlimited")
+	}
+}
+
+func TestProviderOptions_TensorRT(t *testing.T) {
+	trt := providerOptions(ProviderTensorRT, Options{TensorRTEngineCachePath: "/cache/trt", TensorRTFP16: true})
+	if trt["trt_engine_cache_enable"] != "1" || trt["trt_engine_cache_path"] != "/cache/trt" || trt["trt_fp16_enable"] != "1" {
+		t.Errorf("Unexpected TensorRT provider options: %v", trt)
+	}
+	if _, ok := providerOptions(ProviderTensorRT, Options{})["trt_engine_cache_enable"]; ok {
+		t.Error("Expected no engine cache without a path")
+	}
+}
+
+func TestWithoutProvider(t *testing.T) {
+	o := Options{ExecutionProviders: []string{ProviderTensorRT, ProviderCUDA, ProviderCPU}}
+
+	fallback, ok := withoutProvider(o, ProviderTensorRT, RuntimeInfo{AppendedProviders: []string{ProviderTensorRT, ProviderCUDA, ProviderCPU}})
+	if !ok || len(fallback.ExecutionProviders) != 2 || fallback.ExecutionProviders[0] != ProviderCUDA {
+		t.Fatalf("Expected fallback to [cuda cpu], got %v", fallback.ExecutionProviders)
+	}
+	if len(o.ExecutionProviders) != 3 {
+		t.Errorf("Expected the original providers to be left untouched, got %v", o.ExecutionProviders)
+	}
+
+	// Nothing to retry when TensorRT was never active
+	if _, ok := withoutProvider(o, ProviderTensorRT, RuntimeInfo{AppendedProviders: []string{ProviderCUDA, ProviderCPU}}); ok {
+		t.Error("Expected no fallback when TensorRT is not active")
+	}
+}
+
+func TestBreaker_OpensAndProbes(t *testing.T) {
+	mock := NewMock()
+	b := NewBreaker(mock, 3, time.Second)
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+	obs := [][]float32{{0.1, 0.2, 0.3, 0.4}}
+
+	// Input errors do not count as failures
+	for i := 0; i < 5; i++ {
+		if _, err := b.Predict([][]float32{{0.1}}, 1, 2, 2); err == nil {
+			t.Fatal("Expected an error for a wrong observation size")
+		}
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected the breaker closed after input errors, got %s", b.State())
+	}
+
+	mock.ShouldError = true
+	for i := 0; i < 3; i++ {
+		if _, err := b.Predict(obs, 1, 2, 2); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected engine error %d, got %v", i, err)
+		}
+	}
+	calls := mock.CallCount
+	if _, err := b.Predict(obs, 1, 2, 2); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen after 3 failures, got %v", err)
+	}
+	if mock.CallCount != calls {
+		t.Fatal("Expected the open breaker not to call the engine")
+	}
+
+	// A failed probe keeps the breaker open for another interval
+	now = now.Add(time.Second)
+	if _, err := b.Predict(obs, 1, 2, 2); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the probe to reach the engine, got %v", err)
+	}
+	if _, err := b.Predict(obs, 1, 2, 2); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen after a failed probe, got %v", err)
+	}
+
+	// A successful probe closes it
+	mock.ShouldError = false
+	now = now.Add(time.Second)
+	if _, err := b.Predict(obs, 1, 2, 2); err != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", err)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected the breaker closed after a successful probe, got %s", b.State())
+	}
+}
+
+func TestPool_SessionsOnOneDevice(t *testing.T) {
+	// Each session holds its batch until released, like an ORT session
+	// running one batch at a time
+	release := make(chan struct{})
+	a := &blockingEngine{MockInference: NewMockWithAction([]float32{1}), started: make(chan struct{}), release: release}
+	b := &blockingEngine{MockInference: NewMockWithAction([]float32{1}), started: make(chan struct{}), release: release}
+	pool, err := NewPool([]InferenceEngine{a, b}, []int{0, 0}, DispatchRoundRobin)
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
+
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := pool.Predict([][]float32{{0.1}}, 1, 1, 1)
+			done <- err
+		}()
+	}
+	for _, e := range []*blockingEngine{a, b} {
+		select {
+		case <-e.started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected both batches to run in parallel")
+		}
+	}
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("Predict failed: %v", err)
+		}
+	}
+
+	info := pool.RuntimeInfo()
+	if info.Sessions != 2 || len(info.Devices) != 0 {
+		t.Errorf("Expected 2 sessions and no GPU devices, got %d sessions on %v", info.Sessions, info.Devices)
+	}
+}
+
+func TestDeadline_TimesOutSlowBatches(t *testing.T) {
+	engine := &blockingEngine{MockInference: NewMockWithAction([]float32{1}), started: make(chan struct{}), release: make(chan struct{})}
+	d := NewDeadline(engine, timeouts.Static(timeouts.StageInference, 20*time.Millisecond))
+	obs := [][]float32{{0.1, 0.2, 0.3, 0.4}}
+
+	if _, err := d.Predict(obs, 1, 2, 2); !errors.Is(err, ErrInferenceTimeout) {
+		t.Fatalf("Expected ErrInferenceTimeout, got %v", err)
+	}
+	close(engine.release)
+
+	// Fast batches and input errors pass through
+	fast := NewDeadline(NewMock(), timeouts.Static(timeouts.StageInference, time.Second))
+	if _, err := fast.Predict(obs, 1, 2, 2); err != nil {
+		t.Fatalf("Expected a fast batch to succeed, got %v", err)
+	}
+	var inputErr *InputError
+	if _, err := fast.Predict([][]float32{{0.1}}, 1, 2, 2); !errors.As(err, &inputErr) {
+		t.Fatalf("Expected the engine's input error, got %v", err)
 	}
 }
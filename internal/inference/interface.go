@@ -1,6 +1,31 @@
 // internal/inference/interface.go
 package inference
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrShuttingDown is returned by Predict once the engine has started closing
+var ErrShuttingDown = errors.New("inference engine is shutting down")
+
+// InputError reports observations the model cannot take. It says nothing
+// about the engine's health, so it does not count against a Breaker.
+type InputError struct {
+	Err error
+}
+
+// Error implements error
+func (e *InputError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error
+func (e *InputError) Unwrap() error { return e.Err }
+
+// inputErrorf formats an InputError
+func inputErrorf(format string, args ...interface{}) error {
+	return &InputError{Err: fmt.Errorf(format, args...)}
+}
+
 // InferenceEngine defines the interface for running batch inference.
 // This abstraction allows for easy mocking in tests and swapping implementations.
 type InferenceEngine interface {
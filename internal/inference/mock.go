@@ -3,6 +3,7 @@ package inference
 
 import (
 	"fmt"
+	"sync"
 )
 
 // MockInference is a mock implementation of InferenceEngine for testing.
@@ -18,6 +19,11 @@ type MockInference struct {
 	ErrorMessage string
 	// CallCount tracks the number of times Predict was called
 	CallCount int
+	// Closed is set by Close; later Predicts return ErrShuttingDown
+	Closed bool
+
+	// mu guards CallCount and Closed against concurrent Predicts
+	mu sync.Mutex
 }
 
 // NewMock creates a new MockInference with default action [0.1, 0.2, 0.3]
@@ -41,8 +47,14 @@ func NewMockWithAction(action []float32) *MockInference {
 // Predict returns deterministic dummy actions for each observation in the batch.
 // It validates inputs and returns DefaultAction repeated for each observation.
 func (m *MockInference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	m.mu.Lock()
 	m.CallCount++
+	closed := m.Closed
+	m.mu.Unlock()
 
+	if closed {
+		return nil, ErrShuttingDown
+	}
 	if m.ShouldError {
 		if m.ErrorMessage != "" {
 			return nil, fmt.Errorf("%s", m.ErrorMessage)
@@ -52,14 +64,14 @@ func (m *MockInference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32,
 
 	batch := len(obsBatch)
 	if batch == 0 {
-		return nil, fmt.Errorf("empty observation batch")
+		return nil, inputErrorf("empty observation batch")
 	}
 
 	// Validate observation sizes
 	expectedSize := c * h * w
 	for i, obs := range obsBatch {
 		if int64(len(obs)) != expectedSize {
-			return nil, fmt.Errorf("observation %d has wrong size: got %d, expected %d", i, len(obs), expectedSize)
+			return nil, inputErrorf("observation %d has wrong size: got %d, expected %d", i, len(obs), expectedSize)
 		}
 	}
 
@@ -72,8 +84,11 @@ func (m *MockInference) Predict(obsBatch [][]float32, c, h, w int64) ([]float32,
 	return result, nil
 }
 
-// Close is a no-op for the mock implementation
+// Close marks the mock as closed
 func (m *MockInference) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Closed = true
 	return nil
 }
 
@@ -89,5 +104,13 @@ func (m *MockInference) ClearError() {
 	m.ErrorMessage = ""
 }
 
+// RuntimeInfo reports the mock as its only execution provider
+func (m *MockInference) RuntimeInfo() RuntimeInfo {
+	return RuntimeInfo{
+		AppendedProviders: []string{"mock"},
+		ActionDim:         int64(m.ActionDim),
+	}
+}
+
 // Ensure MockInference implements InferenceEngine at compile time
 var _ InferenceEngine = (*MockInference)(nil)
@@ -0,0 +1,105 @@
+// internal/inference/optimized_cache.go
+
+//go:build cgo && !noort
+
+package inference
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// optimizedModelName names the compiled graph the session writes into a
+// model's cache directory, and optimizedStampName the file marking it complete
+const (
+	optimizedModelName = "model_ctx.onnx"
+	optimizedStampName = "complete"
+)
+
+// configureOptimizedModelCache prepares session options for the optimized model
+// cache and returns the model's cache directory. It returns true when that
+// directory already holds a compiled graph, which should be loaded instead of
+// modelPath. Otherwise ORT is told to write the graph TensorRT compiles during
+// this load, with the engines embedded, as an EP context model.
+func configureOptimizedModelCache(opts *ort.SessionOptions, modelPath string, o Options) (string, bool, error) {
+	dir, err := optimizedModelDir(o.OptimizedModelDir, modelPath, o)
+	if err != nil {
+		return "", false, err
+	}
+	if optimizedModelValid(dir) {
+		return dir, true, nil
+	}
+
+	// Start over so a crash mid-write never leaves a partial graph behind
+	if err := os.RemoveAll(dir); err != nil {
+		return "", false, fmt.Errorf("failed to clear optimized model cache: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", false, fmt.Errorf("failed to create optimized model cache directory: %w", err)
+	}
+	for key, value := range map[string]string{
+		"ep.context_enable":     "1",
+		"ep.context_file_path":  filepath.Join(dir, optimizedModelName),
+		"ep.context_embed_mode": "1",
+	} {
+		if err := opts.AddSessionConfigEntry(key, value); err != nil {
+			return "", false, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return dir, false, nil
+}
+
+// optimizedModelDir returns the directory under cacheDir holding the compiled
+// graph of modelPath. It is keyed by the model file, the ORT version, and the
+// TensorRT precision, so a changed model or runtime never loads a stale graph.
+func optimizedModelDir(cacheDir, modelPath string, o Options) (string, error) {
+	fingerprint, err := modelFingerprint(modelPath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:fp16=%t", fingerprint, ort.GetVersion(), o.TensorRTFP16)))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:8])), nil
+}
+
+// optimizedModelValid reports whether dir holds a completely written graph
+func optimizedModelValid(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, optimizedModelName)); err != nil {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, optimizedStampName))
+	return err == nil
+}
+
+// writeOptimizedModelStamp marks the graph the session wrote into dir as
+// complete. It returns false when nothing was written, e.g. when TensorRT
+// took no part of the model.
+func writeOptimizedModelStamp(dir string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(dir, optimizedModelName)); err != nil {
+		return false, nil
+	}
+	if err := os.WriteFile(filepath.Join(dir, optimizedStampName), nil, 0o644); err != nil {
+		return false, fmt.Errorf("failed to write optimized model stamp: %w", err)
+	}
+	return true, nil
+}
+
+// modelFingerprint identifies a model file by path, size, and modification time.
+// This is cheap enough to compute on every start, unlike hashing a large model.
+func modelFingerprint(modelPath string) (string, error) {
+	abs, err := filepath.Abs(modelPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve model path: %w", err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat model: %w", err)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", abs, info.Size(), info.ModTime().UnixNano()), nil
+}
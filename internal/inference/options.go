@@ -0,0 +1,40 @@
+// internal/inference/options.go
+package inference
+
+// Options configures how the ONNX session is created
+type Options struct {
+	// OptimizedModelDir is where ORT writes the graph TensorRT compiles for a
+	// model after its first load. Later loads of the same model read that
+	// graph and skip graph optimization and the engine build. It only applies
+	// while the TensorRT provider is active.
+	OptimizedModelDir string
+
+	// ExecutionProviders are tried in priority order ("cuda", "tensorrt",
+	// "cpu"); empty means CPU only. Providers that fail to load are skipped.
+	ExecutionProviders []string
+
+	// DeviceID is the GPU the CUDA and TensorRT providers run on
+	DeviceID int
+
+	// CUDAMemLimit caps the CUDA provider's memory arena on the device, in
+	// bytes; 0 leaves it unlimited
+	CUDAMemLimit int64
+
+	// TensorRTEngineCachePath is where the TensorRT provider caches built
+	// engines, so restarts skip the engine build; empty disables the cache
+	TensorRTEngineCachePath string
+	// TensorRTFP16 lets TensorRT run layers in FP16
+	TensorRTFP16 bool
+
+	// IntraOpThreads and InterOpThreads size ORT's thread pools; 0 keeps ORT's default
+	IntraOpThreads int
+	InterOpThreads int
+
+	// IntraOpAffinity pins intra-op threads to processors, in ORT's
+	// "session.intra_op_thread_affinities" format; requires IntraOpThreads
+	IntraOpAffinity string
+
+	// Quantization checks an int8-quantized model's format and input type,
+	// and quantizes observations for integer inputs
+	Quantization Quantization
+}
@@ -0,0 +1,120 @@
+// internal/inference/pool.go
+package inference
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Dispatch strategies for Pool
+const (
+	DispatchRoundRobin  = "round_robin"
+	DispatchLeastLoaded = "least_loaded"
+)
+
+// Pool is an InferenceEngine that spreads batches across sessions, pinned to
+// different GPUs or several on one device. Each session runs one batch at a
+// time, so a pool of N sessions runs up to N batches in parallel.
+type Pool struct {
+	sessions []*poolSession
+	strategy string
+	next     atomic.Uint64
+}
+
+// poolSession is one engine pinned to a device
+type poolSession struct {
+	engine   InferenceEngine
+	device   string
+	inflight atomic.Int64
+}
+
+// NewPool creates a Pool dispatching to engines[i], which runs on devices[i],
+// using strategy (DispatchRoundRobin or DispatchLeastLoaded). A device may be
+// listed once per session on it.
+func NewPool(engines []InferenceEngine, devices []int, strategy string) (*Pool, error) {
+	if len(engines) == 0 || len(engines) != len(devices) {
+		return nil, fmt.Errorf("pool needs one engine per device, got %d engines for %d devices", len(engines), len(devices))
+	}
+	switch strategy {
+	case DispatchRoundRobin, DispatchLeastLoaded:
+	default:
+		return nil, fmt.Errorf("invalid dispatch strategy: %q", strategy)
+	}
+
+	p := &Pool{strategy: strategy}
+	for i, e := range engines {
+		p.sessions = append(p.sessions, &poolSession{engine: e, device: strconv.Itoa(devices[i])})
+	}
+	return p, nil
+}
+
+// pick chooses the session for the next batch
+func (p *Pool) pick() *poolSession {
+	start := int(p.next.Add(1) % uint64(len(p.sessions)))
+	if p.strategy == DispatchRoundRobin {
+		return p.sessions[start]
+	}
+
+	// Least loaded, starting from the round-robin position so ties rotate
+	best := p.sessions[start]
+	for i := 1; i < len(p.sessions); i++ {
+		s := p.sessions[(start+i)%len(p.sessions)]
+		if s.inflight.Load() < best.inflight.Load() {
+			best = s
+		}
+	}
+	return best
+}
+
+// Predict implements InferenceEngine
+func (p *Pool) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	s := p.pick()
+
+	s.inflight.Add(1)
+	metrics.AddDeviceInflight(s.device, 1)
+	start := time.Now()
+	actions, err := s.engine.Predict(obsBatch, c, h, w)
+	metrics.RecordDeviceLatency(s.device, time.Since(start).Seconds())
+	metrics.AddDeviceInflight(s.device, -1)
+	s.inflight.Add(-1)
+
+	return actions, err
+}
+
+// Close closes every session in the pool
+func (p *Pool) Close() error {
+	var err error
+	for _, s := range p.sessions {
+		if cerr := s.engine.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// RuntimeInfo describes the first session and lists every device in the
+// pool. Sessions all on one device list it only if they report it, so a pool
+// of CPU sessions lists no devices.
+func (p *Pool) RuntimeInfo() RuntimeInfo {
+	info, _ := Describe(p.sessions[0].engine)
+	var devices []int
+	for _, s := range p.sessions {
+		device, _ := strconv.Atoi(s.device)
+		if !slices.Contains(devices, device) {
+			devices = append(devices, device)
+		}
+	}
+	if len(devices) > 1 || len(info.Devices) > 0 {
+		info.Devices = devices
+	}
+	info.Sessions = len(p.sessions)
+	return info
+}
+
+// Ensure Pool implements InferenceEngine at compile time
+var _ InferenceEngine = (*Pool)(nil)
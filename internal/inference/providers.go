@@ -0,0 +1,113 @@
+// internal/inference/providers.go
+package inference
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Execution providers that can be requested in Options.ExecutionProviders
+const (
+	ProviderCUDA     = "cuda"
+	ProviderTensorRT = "tensorrt"
+	ProviderCPU      = "cpu"
+)
+
+// RuntimeInfo describes how an engine actually executes its model
+type RuntimeInfo struct {
+	ModelPath  string
+	ORTVersion string
+	// RequestedProviders are the execution providers asked for, in priority order
+	RequestedProviders []string
+	// AppendedProviders are the providers appended to the session options
+	// without error, in priority order, with CPU always last. ORT does not
+	// report which of them it assigns nodes to, so an appended provider may
+	// still leave some or all of the graph on CPU.
+	AppendedProviders []string
+	// ProviderErrors explains why requested providers were not appended
+	ProviderErrors []string
+	// Devices are the GPUs the model runs on, when pinned to specific devices
+	Devices []int
+	// Sessions is how many sessions run batches in parallel; 0 means one
+	Sessions int
+	// CUDAMemLimit is the CUDA memory arena limit in bytes; 0 is unlimited
+	CUDAMemLimit int64
+	// IntraOpThreads and InterOpThreads are the thread pool sizes; 0 means ORT's default
+	IntraOpThreads    int
+	InterOpThreads    int
+	IntraOpAffinity   string
+	OptimizedCacheHit bool
+	ActionDim         int64
+	// InputShape and OutputShape are the model's observation and action tensor
+	// shapes from its metadata, including the batch dimension; -1 is dynamic
+	InputShape  []int64
+	OutputShape []int64
+	// InputType is the observation tensor's element type (InputFloat32,
+	// InputInt8, or InputUint8), and Quantization the checked quantization
+	// format, empty for float models
+	InputType    string
+	Quantization string
+}
+
+// Fallback reports whether a requested accelerator is not active
+func (r RuntimeInfo) Fallback() bool {
+	return len(r.ProviderErrors) > 0
+}
+
+// Describer is implemented by engines that can report their RuntimeInfo
+type Describer interface {
+	RuntimeInfo() RuntimeInfo
+}
+
+// Wrapper is implemented by engines that delegate to another engine
+type Wrapper interface {
+	Unwrap() InferenceEngine
+}
+
+// Describe returns the RuntimeInfo of e, unwrapping wrapper engines until one
+// describes itself
+func Describe(e InferenceEngine) (RuntimeInfo, bool) {
+	for e != nil {
+		if d, ok := e.(Describer); ok {
+			return d.RuntimeInfo(), true
+		}
+		w, ok := e.(Wrapper)
+		if !ok {
+			break
+		}
+		e = w.Unwrap()
+	}
+	return RuntimeInfo{}, false
+}
+
+// withoutProvider returns o without provider if it is among the appended
+// providers in info, for retrying a session on the remaining providers
+func withoutProvider(o Options, provider string, info RuntimeInfo) (Options, bool) {
+	if !slices.Contains(info.AppendedProviders, provider) {
+		return o, false
+	}
+	o.ExecutionProviders = slices.DeleteFunc(slices.Clone(o.ExecutionProviders), func(p string) bool {
+		return p == provider
+	})
+	return o, true
+}
+
+// providerOptions returns the ORT provider options for an accelerator
+func providerOptions(provider string, o Options) map[string]string {
+	providerOpts := map[string]string{"device_id": strconv.Itoa(o.DeviceID)}
+	switch provider {
+	case ProviderCUDA:
+		if o.CUDAMemLimit > 0 {
+			providerOpts["gpu_mem_limit"] = strconv.FormatInt(o.CUDAMemLimit, 10)
+		}
+	case ProviderTensorRT:
+		if o.TensorRTEngineCachePath != "" {
+			providerOpts["trt_engine_cache_enable"] = "1"
+			providerOpts["trt_engine_cache_path"] = o.TensorRTEngineCachePath
+		}
+		if o.TensorRTFP16 {
+			providerOpts["trt_fp16_enable"] = "1"
+		}
+	}
+	return providerOpts
+}
@@ -0,0 +1,46 @@
+// internal/inference/providers_cuda.go
+
+//go:build cgo && !noort && !nocuda
+
+package inference
+
+import (
+	"fmt"
+	"os"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// appendProvider adds a single accelerator execution provider to opts
+func appendProvider(opts *ort.SessionOptions, provider string, o Options) error {
+	providerOpts := providerOptions(provider, o)
+	switch provider {
+	case ProviderCUDA:
+		cuda, err := ort.NewCUDAProviderOptions()
+		if err != nil {
+			return err
+		}
+		defer cuda.Destroy()
+		if err := cuda.Update(providerOpts); err != nil {
+			return err
+		}
+		return opts.AppendExecutionProviderCUDA(cuda)
+	case ProviderTensorRT:
+		if o.TensorRTEngineCachePath != "" {
+			if err := os.MkdirAll(o.TensorRTEngineCachePath, 0o755); err != nil {
+				return fmt.Errorf("failed to create engine cache directory: %w", err)
+			}
+		}
+		trt, err := ort.NewTensorRTProviderOptions()
+		if err != nil {
+			return err
+		}
+		defer trt.Destroy()
+		if err := trt.Update(providerOpts); err != nil {
+			return err
+		}
+		return opts.AppendExecutionProviderTensorRT(trt)
+	default:
+		return fmt.Errorf("unknown execution provider")
+	}
+}
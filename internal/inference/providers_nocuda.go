@@ -0,0 +1,20 @@
+// internal/inference/providers_nocuda.go
+
+//go:build cgo && !noort && nocuda
+
+package inference
+
+import (
+	"errors"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// errNoGPUProviders is why accelerators are skipped in builds without them
+var errNoGPUProviders = errors.New("not available in this build (built with the nocuda tag)")
+
+// appendProvider skips accelerators, which this build leaves out, so sessions
+// fall back to the CPU provider
+func appendProvider(opts *ort.SessionOptions, provider string, o Options) error {
+	return errNoGPUProviders
+}
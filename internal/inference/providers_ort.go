@@ -0,0 +1,60 @@
+// internal/inference/providers_ort.go
+
+//go:build cgo && !noort
+
+package inference
+
+import (
+	"fmt"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// configureProviders appends the requested execution providers and thread pool
+// sizes to opts. A provider that cannot be appended (e.g. CUDA after a driver
+// mismatch) is skipped so the session falls back to the next one; the returned
+// info records what was appended and why anything was skipped.
+func configureProviders(opts *ort.SessionOptions, o Options) (RuntimeInfo, error) {
+	info := RuntimeInfo{
+		ORTVersion:         ort.GetVersion(),
+		RequestedProviders: o.ExecutionProviders,
+		IntraOpThreads:     o.IntraOpThreads,
+		InterOpThreads:     o.InterOpThreads,
+		IntraOpAffinity:    o.IntraOpAffinity,
+		CUDAMemLimit:       o.CUDAMemLimit,
+	}
+
+	if o.IntraOpThreads > 0 {
+		if err := opts.SetIntraOpNumThreads(o.IntraOpThreads); err != nil {
+			return info, fmt.Errorf("failed to set intra-op threads: %w", err)
+		}
+	}
+	if o.InterOpThreads > 0 {
+		if err := opts.SetInterOpNumThreads(o.InterOpThreads); err != nil {
+			return info, fmt.Errorf("failed to set inter-op threads: %w", err)
+		}
+	}
+
+	if o.IntraOpAffinity != "" {
+		if err := opts.AddSessionConfigEntry("session.intra_op_thread_affinities", o.IntraOpAffinity); err != nil {
+			return info, fmt.Errorf("failed to set intra-op thread affinities: %w", err)
+		}
+	}
+
+	for _, provider := range o.ExecutionProviders {
+		if provider == ProviderCPU {
+			break
+		}
+		if err := appendProvider(opts, provider, o); err != nil {
+			info.ProviderErrors = append(info.ProviderErrors, fmt.Sprintf("%s: %v", provider, err))
+			continue
+		}
+		info.AppendedProviders = append(info.AppendedProviders, provider)
+	}
+	info.AppendedProviders = append(info.AppendedProviders, ProviderCPU)
+	if len(info.AppendedProviders) > 1 {
+		info.Devices = []int{o.DeviceID}
+	}
+
+	return info, nil
+}
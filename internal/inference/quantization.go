@@ -0,0 +1,194 @@
+// internal/inference/quantization.go
+package inference
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Quantization formats of int8 models
+const (
+	// QuantizationQDQ models carry QuantizeLinear/DequantizeLinear pairs
+	// around the operators ORT fuses into integer kernels (static quantization)
+	QuantizationQDQ = "qdq"
+	// QuantizationQOperator models use quantized operators such as
+	// QLinearConv and MatMulInteger directly (including dynamic quantization)
+	QuantizationQOperator = "qoperator"
+)
+
+// Input tensor element types
+const (
+	InputFloat32 = "float32"
+	InputInt8    = "int8"
+	InputUint8   = "uint8"
+)
+
+// Quantization describes an int8-quantized model: the format its graph is
+// checked against, and how float observations are quantized for a model
+// whose input tensor is int8 or uint8
+type Quantization struct {
+	// Format is QuantizationQDQ, QuantizationQOperator, or empty to skip the
+	// graph check
+	Format string
+	// InputType is the model's input element type; empty means InputFloat32
+	InputType string
+	// InputScale and InputZeroPoint quantize observations for integer inputs
+	// as round(x / InputScale) + InputZeroPoint, saturated to the type's range
+	InputScale     float32
+	InputZeroPoint int32
+}
+
+// inputType returns the input element type, defaulting to float32
+func (q Quantization) inputType() string {
+	if q.InputType == "" {
+		return InputFloat32
+	}
+	return q.InputType
+}
+
+// qdqOps are the operators a QDQ model must contain
+var qdqOps = []string{"QuantizeLinear", "DequantizeLinear"}
+
+// qoperatorOps are quantized operators, at least one of which a QOperator
+// model contains
+var qoperatorOps = []string{
+	"QLinearConv", "QLinearMatMul", "QLinearAdd", "QLinearMul", "QGemm",
+	"MatMulInteger", "ConvInteger", "DynamicQuantizeLinear",
+}
+
+// CheckFormat verifies that the model at path is quantized in q.Format
+func (q Quantization) CheckFormat(path string) error {
+	if q.Format == "" {
+		return nil
+	}
+	ops, err := modelOps(path)
+	if err != nil {
+		return err
+	}
+	return q.checkOps(ops)
+}
+
+// checkOps verifies the operator counts of a model against q.Format
+func (q Quantization) checkOps(ops map[string]int) error {
+	switch q.Format {
+	case QuantizationQDQ:
+		for _, op := range qdqOps {
+			if ops[op] == 0 {
+				return fmt.Errorf("model has no %s nodes; it is not a QDQ-quantized model", op)
+			}
+		}
+	case QuantizationQOperator:
+		for _, op := range qoperatorOps {
+			if ops[op] > 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("model has no quantized operators (%v); it is not a QOperator-quantized model", qoperatorOps)
+	default:
+		return fmt.Errorf("unknown quantization format %q", q.Format)
+	}
+	return nil
+}
+
+// quantizeInt8 quantizes observations into an int8 tensor
+func (q Quantization) quantizeInt8(data []float32) []int8 {
+	out := make([]int8, len(data))
+	for i, x := range data {
+		out[i] = int8(q.quantize(x, math.MinInt8, math.MaxInt8))
+	}
+	return out
+}
+
+// quantizeUint8 quantizes observations into a uint8 tensor
+func (q Quantization) quantizeUint8(data []float32) []uint8 {
+	out := make([]uint8, len(data))
+	for i, x := range data {
+		out[i] = uint8(q.quantize(x, 0, math.MaxUint8))
+	}
+	return out
+}
+
+// quantize maps x to the integer grid, rounding half to even as ONNX
+// QuantizeLinear does, and saturating to [lo, hi]
+func (q Quantization) quantize(x float32, lo, hi float64) float64 {
+	v := math.RoundToEven(float64(x)/float64(q.InputScale)) + float64(q.InputZeroPoint)
+	if math.IsNaN(v) {
+		return float64(q.InputZeroPoint)
+	}
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// ONNX protobuf field numbers read by modelOps (onnx/onnx.proto)
+const (
+	onnxModelGraphField = 7
+	onnxGraphNodeField  = 1
+	onnxNodeOpTypeField = 4
+)
+
+// modelOps counts the operator types of the top-level graph of the ONNX model
+// at path
+func modelOps(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model: %w", err)
+	}
+	graph, err := protoField(data, onnxModelGraphField)
+	if err != nil || graph == nil {
+		return nil, fmt.Errorf("failed to parse model %s: no graph", path)
+	}
+
+	ops := make(map[string]int)
+	err = eachBytesField(graph, onnxGraphNodeField, func(node []byte) error {
+		opType, err := protoField(node, onnxNodeOpTypeField)
+		if err != nil {
+			return err
+		}
+		ops[string(opType)]++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model %s: %w", path, err)
+	}
+	return ops, nil
+}
+
+// protoField returns the last occurrence of bytes field num in msg, or nil
+func protoField(msg []byte, num protowire.Number) ([]byte, error) {
+	var found []byte
+	err := eachBytesField(msg, num, func(v []byte) error {
+		found = v
+		return nil
+	})
+	return found, err
+}
+
+// eachBytesField calls fn with every occurrence of bytes field num in msg
+func eachBytesField(msg []byte, num protowire.Number, fn func([]byte) error) error {
+	for len(msg) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(msg)
+		if tagLen < 0 {
+			return protowire.ParseError(tagLen)
+		}
+		msg = msg[tagLen:]
+		if n == num && typ == protowire.BytesType {
+			v, vLen := protowire.ConsumeBytes(msg)
+			if vLen < 0 {
+				return protowire.ParseError(vLen)
+			}
+			if err := fn(v); err != nil {
+				return err
+			}
+			msg = msg[vLen:]
+			continue
+		}
+		vLen := protowire.ConsumeFieldValue(n, typ, msg)
+		if vLen < 0 {
+			return protowire.ParseError(vLen)
+		}
+		msg = msg[vLen:]
+	}
+	return nil
+}
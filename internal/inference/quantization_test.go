@@ -0,0 +1,86 @@
+// internal/inference/quantization_test.go
+package inference
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestQuantization_Quantize(t *testing.T) {
+	q := Quantization{InputScale: 0.5, InputZeroPoint: 10}
+
+	got := q.quantizeInt8([]float32{0, 1, 1.25, 1.75, -100, 100, float32(math.NaN())})
+	want := []int8{10, 12, 12, 14, -128, 127, 10}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected int8 %v, got %v", want, got)
+	}
+
+	q.InputZeroPoint = 128
+	gotU := q.quantizeUint8([]float32{0, 1, -100, 100})
+	wantU := []uint8{128, 130, 0, 255}
+	if !slices.Equal(gotU, wantU) {
+		t.Errorf("Expected uint8 %v, got %v", wantU, gotU)
+	}
+}
+
+func TestQuantization_CheckOps(t *testing.T) {
+	qdq := Quantization{Format: QuantizationQDQ}
+	if err := qdq.checkOps(map[string]int{"QuantizeLinear": 2, "DequantizeLinear": 3, "Conv": 1}); err != nil {
+		t.Errorf("Expected QDQ model to pass, got %v", err)
+	}
+	if err := qdq.checkOps(map[string]int{"QuantizeLinear": 1, "Conv": 1}); err == nil {
+		t.Error("Expected error for QDQ model without DequantizeLinear")
+	}
+
+	qop := Quantization{Format: QuantizationQOperator}
+	if err := qop.checkOps(map[string]int{"QLinearConv": 1}); err != nil {
+		t.Errorf("Expected QOperator model to pass, got %v", err)
+	}
+	if err := qop.checkOps(map[string]int{"Conv": 1, "Gemm": 1}); err == nil {
+		t.Error("Expected error for float model checked as QOperator")
+	}
+}
+
+func TestQuantization_CheckFormat(t *testing.T) {
+	// A model whose graph holds a DynamicQuantizeLinear and a MatMulInteger node
+	var graph []byte
+	for _, op := range []string{"DynamicQuantizeLinear", "MatMulInteger"} {
+		var node []byte
+		node = protowire.AppendTag(node, onnxNodeOpTypeField, protowire.BytesType)
+		node = protowire.AppendString(node, op)
+		graph = protowire.AppendTag(graph, onnxGraphNodeField, protowire.BytesType)
+		graph = protowire.AppendBytes(graph, node)
+	}
+	var model []byte
+	model = protowire.AppendTag(model, 1, protowire.VarintType)
+	model = protowire.AppendVarint(model, 8)
+	model = protowire.AppendTag(model, onnxModelGraphField, protowire.BytesType)
+	model = protowire.AppendBytes(model, graph)
+
+	path := filepath.Join(t.TempDir(), "policy.int8.onnx")
+	if err := os.WriteFile(path, model, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ops, err := modelOps(path)
+	if err != nil {
+		t.Fatalf("modelOps failed: %v", err)
+	}
+	if ops["MatMulInteger"] != 1 || ops["DynamicQuantizeLinear"] != 1 {
+		t.Errorf("Expected one node of each op, got %v", ops)
+	}
+	if err := (Quantization{Format: QuantizationQOperator}).CheckFormat(path); err != nil {
+		t.Errorf("Expected QOperator check to pass, got %v", err)
+	}
+	if err := (Quantization{Format: QuantizationQDQ}).CheckFormat(path); err == nil {
+		t.Error("Expected QDQ check to fail")
+	}
+	if err := (Quantization{}).CheckFormat(filepath.Join(t.TempDir(), "missing.onnx")); err != nil {
+		t.Errorf("Expected no check without a format, got %v", err)
+	}
+}
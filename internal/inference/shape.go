@@ -0,0 +1,127 @@
+// internal/inference/shape.go
+
+//go:build cgo && !noort
+
+package inference
+
+import (
+	"fmt"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// Default tensor names, used when a model has several inputs or outputs
+const (
+	defaultInputName  = "obs"
+	defaultOutputName = "action"
+)
+
+// modelIO is the observation input and action output of a model, read from
+// its ONNX metadata. Shapes include the leading batch dimension; dynamic
+// dimensions are -1.
+type modelIO struct {
+	inputName   string
+	outputName  string
+	inputShape  []int64
+	outputShape []int64
+	// inputType is the observation tensor's element type
+	inputType string
+}
+
+// inputTypes are the supported observation tensor element types
+var inputTypes = map[ort.TensorElementDataType]string{
+	ort.TensorElementDataTypeFloat: InputFloat32,
+	ort.TensorElementDataTypeInt8:  InputInt8,
+	ort.TensorElementDataTypeUint8: InputUint8,
+}
+
+// readModelIO reads the input and output tensors of the model at path; the ORT
+// environment must be initialized
+func readModelIO(path string) (modelIO, error) {
+	inputs, outputs, err := ort.GetInputOutputInfo(path)
+	if err != nil {
+		return modelIO{}, fmt.Errorf("failed to read model inputs and outputs: %w", err)
+	}
+	return parseModelIO(inputs, outputs)
+}
+
+// parseModelIO picks the observation input and action output: the only one,
+// or the one with the default name
+func parseModelIO(inputs, outputs []ort.InputOutputInfo) (modelIO, error) {
+	input, err := pickTensor(inputs, defaultInputName, "input")
+	if err != nil {
+		return modelIO{}, err
+	}
+	output, err := pickTensor(outputs, defaultOutputName, "output")
+	if err != nil {
+		return modelIO{}, err
+	}
+
+	io := modelIO{
+		inputName:   input.Name,
+		outputName:  output.Name,
+		inputShape:  append([]int64(nil), input.Dimensions...),
+		outputShape: append([]int64(nil), output.Dimensions...),
+	}
+	// The session feeds float32 tensors, or int8 and uint8 ones for quantized
+	// models, and reads float32 actions
+	inputType, ok := inputTypes[input.DataType]
+	if !ok {
+		return modelIO{}, fmt.Errorf("model input %q has element type %v; expected float32, int8, or uint8", io.inputName, input.DataType)
+	}
+	io.inputType = inputType
+	if output.DataType != ort.TensorElementDataTypeFloat {
+		return modelIO{}, fmt.Errorf("model output %q must be a float32 tensor; dequantize it in the graph", io.outputName)
+	}
+	if rank := len(io.inputShape); rank != 2 && rank != 4 {
+		return modelIO{}, fmt.Errorf("model input %q has shape %v; expected [batch, N] or [batch, C, H, W]", io.inputName, io.inputShape)
+	}
+	if len(io.outputShape) < 2 {
+		return modelIO{}, fmt.Errorf("model output %q has shape %v; expected [batch, action_dim]", io.outputName, io.outputShape)
+	}
+	return io, nil
+}
+
+// pickTensor returns the only tensor, or the one named name
+func pickTensor(tensors []ort.InputOutputInfo, name, kind string) (ort.InputOutputInfo, error) {
+	if len(tensors) == 1 {
+		return tensors[0], nil
+	}
+	for _, t := range tensors {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return ort.InputOutputInfo{}, fmt.Errorf("model has %d %ss and none is named %q", len(tensors), kind, name)
+}
+
+// actionDim returns the number of output values per observation, or 0 if any
+// non-batch output dimension is dynamic
+func (io modelIO) actionDim() int64 {
+	dim := int64(1)
+	for _, d := range io.outputShape[1:] {
+		if d <= 0 {
+			return 0
+		}
+		dim *= d
+	}
+	return dim
+}
+
+// inputDims returns the shape of a batch of observations of (c, h, w) fed to
+// the model, or an error if the model's fixed input dimensions disagree
+func (io modelIO) inputDims(batch, c, h, w int64) (ort.Shape, error) {
+	want := []int64{c, h, w}
+	shape := ort.NewShape(batch, c, h, w)
+	if len(io.inputShape) == 2 {
+		// State-vector models take flattened observations
+		want = []int64{c * h * w}
+		shape = ort.NewShape(batch, c*h*w)
+	}
+	for i, d := range io.inputShape[1:] {
+		if d > 0 && d != want[i] {
+			return nil, inputErrorf("observation dims (%d,%d,%d) do not match model input shape %v", c, h, w, io.inputShape)
+		}
+	}
+	return shape, nil
+}
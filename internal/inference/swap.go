@@ -0,0 +1,161 @@
+// internal/inference/swap.go
+package inference
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoSpare is returned by Rollback when no previous engine is kept loaded
+var ErrNoSpare = errors.New("no previous model is loaded")
+
+// ModelSlot is a model version resident in a Swappable
+type ModelSlot struct {
+	Version  string
+	LoadedAt time.Time
+	// Active is set for the version serving traffic, unset for the spare
+	Active bool
+}
+
+// slot is an engine loaded in a Swappable
+type slot struct {
+	engine   InferenceEngine
+	version  string
+	loadedAt time.Time
+}
+
+// Swappable is an InferenceEngine whose underlying engine can be replaced at
+// runtime, e.g. to hot-reload a model without restarting the server. The
+// replaced engine can be kept loaded as a spare, so Rollback to it is instant.
+type Swappable struct {
+	mu     sync.RWMutex
+	active slot
+	spare  *slot
+	closed bool
+}
+
+// NewSwappable wraps engine, serving the given model version, so it can later
+// be replaced with Swap
+func NewSwappable(engine InferenceEngine, version string) *Swappable {
+	return &Swappable{active: slot{engine: engine, version: version, loadedAt: time.Now()}}
+}
+
+// Predict implements InferenceEngine
+func (s *Swappable) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrShuttingDown
+	}
+	return s.active.engine.Predict(obsBatch, c, h, w)
+}
+
+// Swap replaces the engine with one serving version. It waits for in-flight
+// Predicts on the previous engine to finish; the previous engine then becomes
+// the spare if keepPrevious is set, and is closed otherwise. Any older spare
+// is closed. If s is already closed, engine is closed instead and
+// ErrShuttingDown is returned.
+func (s *Swappable) Swap(engine InferenceEngine, version string, keepPrevious bool) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		engine.Close()
+		return ErrShuttingDown
+	}
+	previous, spare := s.active, s.spare
+	s.active = slot{engine: engine, version: version, loadedAt: time.Now()}
+	s.spare = nil
+	if keepPrevious {
+		s.spare = &previous
+	}
+	s.mu.Unlock()
+
+	// New Predicts already use engine; the write lock drained the rest
+	var errs []error
+	if spare != nil {
+		errs = append(errs, spare.engine.Close())
+	}
+	if !keepPrevious {
+		errs = append(errs, previous.engine.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Rollback swaps the spare back in, keeping the engine it replaces as the new
+// spare, and returns the version now serving. It fails with ErrNoSpare if no
+// previous engine was kept.
+func (s *Swappable) Rollback() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return "", ErrShuttingDown
+	}
+	if s.spare == nil {
+		return "", ErrNoSpare
+	}
+	previous := s.active
+	s.active, s.spare = *s.spare, &previous
+	return s.active.version, nil
+}
+
+// Version returns the version of the engine serving traffic
+func (s *Swappable) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active.version
+}
+
+// Resident returns the loaded model versions, the active one first
+func (s *Swappable) Resident() []ModelSlot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	slots := []ModelSlot{{Version: s.active.version, LoadedAt: s.active.loadedAt, Active: true}}
+	if s.spare != nil {
+		slots = append(slots, ModelSlot{Version: s.spare.version, LoadedAt: s.spare.loadedAt})
+	}
+	return slots
+}
+
+// Unwrap returns the current engine, implementing Wrapper
+func (s *Swappable) Unwrap() InferenceEngine {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active.engine
+}
+
+// Close closes the current engine and any spare once in-flight Predicts finish
+func (s *Swappable) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.active.engine.Close()
+	if s.spare != nil {
+		err = errors.Join(err, s.spare.engine.Close())
+		s.spare = nil
+	}
+	return err
+}
+
+// ResidentModels returns the model versions loaded in e, unwrapping wrapper
+// engines until one holds them
+func ResidentModels(e InferenceEngine) ([]ModelSlot, bool) {
+	for e != nil {
+		if s, ok := e.(interface{ Resident() []ModelSlot }); ok {
+			return s.Resident(), true
+		}
+		w, ok := e.(Wrapper)
+		if !ok {
+			break
+		}
+		e = w.Unwrap()
+	}
+	return nil, false
+}
@@ -0,0 +1,72 @@
+// internal/inference/warmup.go
+package inference
+
+import (
+	"fmt"
+	"time"
+)
+
+// WarmupResult is the latency of warming up one batch size
+type WarmupResult struct {
+	BatchSize  int
+	Inferences int
+	First      time.Duration
+	Last       time.Duration
+}
+
+// Warmup runs n synthetic inferences of each batch size through e, so the
+// first requests served do not pay for lazy session initialization, memory
+// arena growth, or kernel selection
+func Warmup(e InferenceEngine, n int, batchSizes []int) ([]WarmupResult, error) {
+	c, h, w := SyntheticDims(e)
+	results := make([]WarmupResult, 0, len(batchSizes))
+	for _, batch := range batchSizes {
+		obs := SyntheticObservations(batch, c, h, w)
+		result := WarmupResult{BatchSize: batch, Inferences: n}
+		for i := 0; i < n; i++ {
+			start := time.Now()
+			if _, err := e.Predict(obs, c, h, w); err != nil {
+				return results, fmt.Errorf("warmup batch of %d (%dx%dx%d): %w", batch, c, h, w, err)
+			}
+			result.Last = time.Since(start)
+			if i == 0 {
+				result.First = result.Last
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SyntheticDims returns observation dims accepted by e's model, using 1 for
+// dynamic dimensions and for engines that do not describe their model
+func SyntheticDims(e InferenceEngine) (int64, int64, int64) {
+	info, ok := Describe(e)
+	if !ok || len(info.InputShape) < 2 {
+		return 1, 1, 1
+	}
+	dims := make([]int64, 0, 3)
+	for _, d := range info.InputShape[1:] {
+		dims = append(dims, max(d, 1))
+	}
+	if len(dims) == 1 {
+		// State-vector models take flattened observations
+		return 1, 1, dims[0]
+	}
+	if len(dims) != 3 {
+		return 1, 1, 1
+	}
+	return dims[0], dims[1], dims[2]
+}
+
+// SyntheticObservations returns batch observations of c*h*w values in [0, 1)
+func SyntheticObservations(batch int, c, h, w int64) [][]float32 {
+	obs := make([][]float32, batch)
+	for i := range obs {
+		obs[i] = make([]float32, c*h*w)
+		for j := range obs[i] {
+			obs[i][j] = float32(j%10) / 10
+		}
+	}
+	return obs
+}
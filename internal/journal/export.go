@@ -0,0 +1,181 @@
+// internal/journal/export.go
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+)
+
+// maxLine bounds the length of a journal line read back by Export
+const maxLine = 1 << 20
+
+// Query selects journal entries from From (inclusive) to To (exclusive),
+// optionally for one robot
+type Query struct {
+	From    time.Time
+	To      time.Time
+	RobotID *uint64
+	// Actions re-encodes each entry's action; the zero value exports lines
+	// as written
+	Actions action.JSONFormat
+}
+
+// formattedEntry is an entry whose action is re-encoded for export
+type formattedEntry struct {
+	Entry
+	Action         json.RawMessage `json:"action,omitempty"`
+	ActionEncoding string          `json:"action_encoding,omitempty"`
+}
+
+// format returns the export line of e, the line read from disk, in the
+// query's action format
+func (q Query) format(e Entry, line []byte) ([]byte, error) {
+	if q.Actions == (action.JSONFormat{}) {
+		return line, nil
+	}
+	f := formattedEntry{Entry: e}
+	if len(e.Action) > 0 {
+		a, err := q.Actions.Marshal(e.Action)
+		if err != nil {
+			return nil, err
+		}
+		f.Action = a
+		f.ActionEncoding = q.Actions.Encoding()
+	}
+	return json.Marshal(f)
+}
+
+// matches reports whether e is selected by q
+func (q Query) matches(e Entry) bool {
+	if e.Time.Before(q.From) || !e.Time.Before(q.To) {
+		return false
+	}
+	return q.RobotID == nil || e.RobotID == *q.RobotID
+}
+
+// Export writes the entries selected by q to w as JSON lines, oldest first,
+// and returns how many it wrote
+func (j *Journal) Export(w io.Writer, q Query) (int, error) {
+	// Flush so entries written so far are on disk, then read without the lock
+	j.mu.Lock()
+	j.flushLocked()
+	segments := append([]segment(nil), j.segments...)
+	j.mu.Unlock()
+
+	n := 0
+	for i, s := range segments {
+		// A segment holds entries from its start until the next segment's
+		if !s.start.Before(q.To) {
+			break
+		}
+		if i+1 < len(segments) && segments[i+1].start.Before(q.From) {
+			continue
+		}
+		written, err := exportSegment(w, s.path, q)
+		n += written
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// exportSegment writes the entries of one segment selected by q to w. Lines
+// that do not parse, such as one cut short by a crash, are skipped.
+func exportSegment(w io.Writer, path string, q Query) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Pruned since the segment list was copied
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLine)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || !q.matches(e) {
+			continue
+		}
+		line, err := q.format(e, scanner.Bytes())
+		if err != nil {
+			return n, err
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, scanner.Err()
+}
+
+// Register adds the journal export endpoint to the admin API:
+//
+//	GET /admin/journal?from=<RFC 3339>&to=<RFC 3339>&robot_id=<id>&precision=<0-9>&action_encoding=base64
+//
+// from defaults to the start of the retention period and to to now. Entries
+// are returned as JSON lines, with actions rounded to precision decimal
+// places or, with action_encoding=base64, as raw float32 values.
+func (j *Journal) Register(m *admin.Mux) {
+	m.HandleFunc("GET /admin/journal", j.handleExport)
+}
+
+func (j *Journal) handleExport(w http.ResponseWriter, r *http.Request) {
+	now := j.now()
+	q := Query{From: now.Add(-j.retention), To: now.Add(time.Nanosecond)}
+	params := r.URL.Query()
+	for name, t := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := params.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				admin.WriteError(w, http.StatusBadRequest, "invalid "+name+": expected an RFC 3339 time")
+				return
+			}
+			*t = parsed
+		}
+	}
+	if v := params.Get("robot_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			admin.WriteError(w, http.StatusBadRequest, "invalid robot_id")
+			return
+		}
+		q.RobotID = &id
+	}
+	if v := params.Get("precision"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 0 || p > action.MaxJSONPrecision {
+			admin.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid precision: expected 0 to %d", action.MaxJSONPrecision))
+			return
+		}
+		q.Actions.Precision = p
+	}
+	switch params.Get("action_encoding") {
+	case "", "json":
+	case "base64":
+		q.Actions.Base64 = true
+	default:
+		admin.WriteError(w, http.StatusBadRequest, "invalid action_encoding: expected json or base64")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if _, err := j.Export(w, q); err != nil {
+		// The status line has been sent; the truncated body is all we can signal
+		slog.WarnContext(r.Context(), "Journal export failed", "error", err)
+	}
+}
@@ -0,0 +1,133 @@
+// internal/journal/grpc.go
+package journal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// UnaryInterceptor journals Plan and BatchPlan calls, one entry per robot;
+// other methods pass through
+func (j *Journal) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := j.now()
+		resp, err := handler(ctx, req)
+
+		switch r := req.(type) {
+		case *pb.PlanRequest:
+			var responses []*pb.PlanResponse
+			if p, ok := resp.(*pb.PlanResponse); ok && p != nil {
+				responses = []*pb.PlanResponse{p}
+			}
+			j.recordCall(ctx, info.FullMethod, start, []*pb.PlanRequest{r}, responses, err)
+		case *pb.BatchPlanRequest:
+			var responses []*pb.PlanResponse
+			if b, ok := resp.(*pb.BatchPlanResponse); ok {
+				responses = b.GetResponses()
+			}
+			j.recordCall(ctx, info.FullMethod, start, r.GetRequests(), responses, err)
+		}
+		return resp, err
+	}
+}
+
+// StreamInterceptor journals BatchPlanStream calls, one entry per robot, once
+// the stream ends; robots whose chunk was not sent get the stream's error
+func (j *Journal) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := j.now()
+		stream := &journalStream{ServerStream: ss}
+		err := handler(srv, stream)
+		if stream.requests != nil {
+			j.recordCall(ss.Context(), info.FullMethod, start, stream.requests, stream.responses, err)
+		}
+		return err
+	}
+}
+
+// journalStream captures a BatchPlanStream request and the responses sent
+// for it
+type journalStream struct {
+	grpc.ServerStream
+	requests  []*pb.PlanRequest
+	responses []*pb.PlanResponse // by request index
+}
+
+func (s *journalStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if r, ok := m.(*pb.BatchPlanStreamRequest); ok && err == nil {
+		s.requests = r.GetRequests()
+		s.responses = make([]*pb.PlanResponse, len(s.requests))
+	}
+	return err
+}
+
+func (s *journalStream) SendMsg(m interface{}) error {
+	if chunk, ok := m.(*pb.BatchPlanChunk); ok {
+		for i, resp := range chunk.GetResponses() {
+			if k := int(chunk.GetOffset()) + i; k < len(s.responses) {
+				s.responses[k] = resp
+			}
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// recordCall journals one entry per request. responses is either empty or
+// indexed like requests; requests without a response get err's status.
+func (j *Journal) recordCall(ctx context.Context, method string, start time.Time, requests []*pb.PlanRequest, responses []*pb.PlanResponse, err error) {
+	latencyMs := float64(j.now().Sub(start).Microseconds()) / 1000.0
+	for i, req := range requests {
+		if req == nil {
+			continue
+		}
+		e := Entry{
+			Time:      start,
+			RequestID: middleware.GetRequestID(ctx),
+			Method:    method,
+			Tenant:    middleware.GetTenantID(ctx),
+			Client:    middleware.GetClientIdentity(ctx),
+			RobotID:   req.GetRobotId(),
+			BatchSize: len(requests),
+			LatencyMs: latencyMs,
+		}
+		if obs := req.GetObs(); obs != nil {
+			e.ObsShape = fmt.Sprintf("%dx%dx%d", obs.Channels, obs.Height, obs.Width)
+		}
+
+		var resp *pb.PlanResponse
+		if i < len(responses) {
+			resp = responses[i]
+		}
+		if resp != nil {
+			e.Code = "OK"
+			e.ObsHash = resp.GetObsHash()
+			e.Action = resp.GetAction()
+			e.Degraded = resp.GetDegradedResolution()
+		} else {
+			st := status.Convert(err)
+			e.Code = st.Code().String()
+			e.Error = st.Message()
+			e.ObsHash = observation.Hash(req.GetObs())
+		}
+		j.Record(e)
+	}
+}
@@ -0,0 +1,284 @@
+// Package journal keeps a bounded on-disk record of served calls, one summary
+// per robot (no observation tensors), so incidents can be reconstructed
+// without depending on log retention.
+//
+// Entries are appended as JSON lines to segment files in a directory. A new
+// segment starts periodically and when the current one grows large; segments
+// older than the retention period, or beyond the size budget, are deleted
+// oldest first, so the directory behaves as a ring.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+const (
+	// DefaultBufferSize is how many entries New queues by default before dropping
+	DefaultBufferSize = 4096
+	// segmentsPerRetention is roughly how many segments the retention period
+	// and size budget are split into
+	segmentsPerRetention = 16
+	// flushInterval bounds how long written entries sit in memory
+	flushInterval = time.Second
+	segmentPrefix = "journal-"
+	segmentSuffix = ".jsonl"
+)
+
+// Entry summarizes one robot's part of a served call
+type Entry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Client    string    `json:"client,omitempty"`
+	RobotID   uint64    `json:"robot_id"`
+	BatchSize int       `json:"batch_size"`
+	ObsHash   string    `json:"obs_hash,omitempty"`
+	// ObsShape is the observation's CxHxW layout
+	ObsShape  string    `json:"obs_shape,omitempty"`
+	Action    []float32 `json:"action,omitempty"`
+	Degraded  bool      `json:"degraded_resolution,omitempty"`
+	Code      string    `json:"code"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs float64   `json:"latency_ms"`
+}
+
+// segment is one journal file; entries in it are no older than start
+type segment struct {
+	path  string
+	start time.Time
+	size  int64
+}
+
+// Journal appends entries to segment files in the background
+type Journal struct {
+	dir       string
+	retention time.Duration
+	maxBytes  int64
+	now       func() time.Time
+	entries   chan Entry
+
+	mu       sync.Mutex
+	segments []segment // oldest first; the last is being written
+	file     *os.File
+	w        *bufio.Writer
+
+	closeMu sync.RWMutex
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// New opens a journal in dir keeping entries for retention and at most about
+// maxBytes on disk, queueing up to bufferSize entries not yet written
+func New(dir string, retention time.Duration, maxBytes int64, bufferSize int) (*Journal, error) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	j := &Journal{
+		dir:       dir,
+		retention: retention,
+		maxBytes:  maxBytes,
+		now:       time.Now,
+		entries:   make(chan Entry, bufferSize),
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	j.mu.Lock()
+	err := j.rotateLocked(j.now())
+	j.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	j.wg.Add(1)
+	go j.writeLoop()
+	return j, nil
+}
+
+// load picks up the segments left by earlier runs
+func (j *Journal) load() error {
+	files, err := os.ReadDir(j.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read journal directory: %w", err)
+	}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		j.segments = append(j.segments, segment{
+			path:  filepath.Join(j.dir, name),
+			start: time.Unix(0, nanos),
+			size:  info.Size(),
+		})
+	}
+	sort.Slice(j.segments, func(a, b int) bool { return j.segments[a].start.Before(j.segments[b].start) })
+	return nil
+}
+
+// Record queues an entry without blocking. The entry is dropped if the buffer
+// is full or the journal is closed.
+func (j *Journal) Record(e Entry) {
+	j.closeMu.RLock()
+	defer j.closeMu.RUnlock()
+	if j.closed {
+		return
+	}
+	select {
+	case j.entries <- e:
+	default:
+		metrics.RecordJournalEntries("dropped", 1)
+	}
+}
+
+// Close writes the entries still queued and closes the current segment
+func (j *Journal) Close() {
+	j.closeMu.Lock()
+	if j.closed {
+		j.closeMu.Unlock()
+		return
+	}
+	j.closed = true
+	close(j.entries)
+	j.closeMu.Unlock()
+
+	j.wg.Wait()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.closeFileLocked()
+}
+
+// writeLoop appends queued entries until the journal is closed, flushing them
+// to the file at least every flushInterval
+func (j *Journal) writeLoop() {
+	defer j.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-j.entries:
+			if !ok {
+				return
+			}
+			j.write(e)
+		case <-ticker.C:
+			j.mu.Lock()
+			j.flushLocked()
+			j.mu.Unlock()
+		}
+	}
+}
+
+// write appends one entry, starting a new segment first when the current one
+// is old or large enough
+func (j *Journal) write(e Entry) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		metrics.RecordJournalEntries("failed", 1)
+		return
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.now()
+	current := &j.segments[len(j.segments)-1]
+	if now.Sub(current.start) >= j.retention/segmentsPerRetention || current.size >= j.maxBytes/segmentsPerRetention {
+		if err := j.rotateLocked(now); err != nil {
+			slog.Warn("Failed to rotate request journal", "error", err)
+		}
+		current = &j.segments[len(j.segments)-1]
+	}
+	if j.w == nil {
+		metrics.RecordJournalEntries("failed", 1)
+		return
+	}
+	if _, err := j.w.Write(line); err != nil {
+		slog.Warn("Failed to write request journal", "error", err)
+		metrics.RecordJournalEntries("failed", 1)
+		return
+	}
+	current.size += int64(len(line))
+	metrics.RecordJournalEntries("written", 1)
+}
+
+// rotateLocked closes the current segment, starts a new one at now, and
+// deletes segments past the retention period or size budget
+func (j *Journal) rotateLocked(now time.Time) error {
+	j.closeFileLocked()
+
+	path := filepath.Join(j.dir, fmt.Sprintf("%s%d%s", segmentPrefix, now.UnixNano(), segmentSuffix))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create journal segment: %w", err)
+	}
+	j.file = f
+	j.w = bufio.NewWriter(f)
+	j.segments = append(j.segments, segment{path: path, start: now})
+	j.pruneLocked(now)
+	return nil
+}
+
+// pruneLocked deletes the oldest segments while every entry in them is past
+// the retention period or the journal is over its size budget; the current
+// segment is always kept
+func (j *Journal) pruneLocked(now time.Time) {
+	var total int64
+	for _, s := range j.segments {
+		total += s.size
+	}
+	cutoff := now.Add(-j.retention)
+	for len(j.segments) > 1 && (j.segments[1].start.Before(cutoff) || total > j.maxBytes) {
+		if err := os.Remove(j.segments[0].path); err != nil && !os.IsNotExist(err) {
+			slog.Warn("Failed to delete journal segment", "path", j.segments[0].path, "error", err)
+		}
+		total -= j.segments[0].size
+		j.segments = j.segments[1:]
+	}
+}
+
+// flushLocked writes buffered entries to the current segment
+func (j *Journal) flushLocked() {
+	if j.w == nil {
+		return
+	}
+	if err := j.w.Flush(); err != nil {
+		slog.Warn("Failed to flush request journal", "error", err)
+	}
+}
+
+// closeFileLocked flushes, syncs, and closes the current segment
+func (j *Journal) closeFileLocked() {
+	if j.file == nil {
+		return
+	}
+	j.flushLocked()
+	j.file.Sync()
+	j.file.Close()
+	j.file, j.w = nil, nil
+}
@@ -0,0 +1,210 @@
+// internal/journal/journal_test.go
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// exported runs q against j and decodes the entries
+func exported(t *testing.T, j *Journal, q Query) []Entry {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := j.Export(&buf, q); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	var entries []Entry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Expected JSON lines, got %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestJournal_ExportFilters(t *testing.T) {
+	j, err := New(t.TempDir(), time.Hour, 1<<20, 16)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	base := time.Now()
+	j.Record(Entry{Time: base, RobotID: 1, Code: "OK"})
+	j.Record(Entry{Time: base.Add(time.Second), RobotID: 2, Code: "OK"})
+	j.Record(Entry{Time: base.Add(2 * time.Second), RobotID: 1, Code: "Unavailable"})
+	j.Close()
+
+	all := Query{From: base.Add(-time.Minute), To: base.Add(time.Minute)}
+	if got := exported(t, j, all); len(got) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(got))
+	}
+
+	robot := uint64(1)
+	got := exported(t, j, Query{From: all.From, To: all.To, RobotID: &robot})
+	if len(got) != 2 || got[0].RobotID != 1 || got[1].Code != "Unavailable" {
+		t.Fatalf("Expected robot 1's 2 entries in order, got %+v", got)
+	}
+	if got := exported(t, j, Query{From: base.Add(time.Second), To: base.Add(2 * time.Second)}); len(got) != 1 || got[0].RobotID != 2 {
+		t.Fatalf("Expected only the entry inside the time range, got %+v", got)
+	}
+}
+
+func TestJournal_ExportActionFormat(t *testing.T) {
+	j, err := New(t.TempDir(), time.Hour, 1<<20, 16)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	base := time.Now()
+	j.Record(Entry{Time: base, RobotID: 1, Code: "OK", Action: []float32{0.123456, -1}})
+	j.Close()
+	q := Query{From: base.Add(-time.Minute), To: base.Add(time.Minute)}
+
+	var buf bytes.Buffer
+	q.Actions = action.JSONFormat{Precision: 2}
+	if _, err := j.Export(&buf, q); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"action":[0.12,-1.00]`)) {
+		t.Fatalf("Expected the action rounded to 2 places, got %s", buf.Bytes())
+	}
+
+	buf.Reset()
+	q.Actions = action.JSONFormat{Base64: true}
+	if _, err := j.Export(&buf, q); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	var e struct {
+		RobotID        uint64 `json:"robot_id"`
+		Action         string `json:"action"`
+		ActionEncoding string `json:"action_encoding"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", buf.Bytes(), err)
+	}
+	decoded, err := action.DecodeBase64(e.Action)
+	if err != nil || e.ActionEncoding != "base64-f32le" || e.RobotID != 1 {
+		t.Fatalf("Expected a base64 action for robot 1, got %+v (err %v)", e, err)
+	}
+	if len(decoded) != 2 || decoded[0] != 0.123456 {
+		t.Fatalf("Expected the action to round-trip exactly, got %v", decoded)
+	}
+}
+
+func TestJournal_RetentionAndSizeBudget(t *testing.T) {
+	dir := t.TempDir()
+	j, err := New(dir, time.Hour, 1<<20, 16)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer j.Close()
+
+	// Write directly, with a controlled clock, instead of through the queue
+	start := time.Now()
+	now := start
+	j.mu.Lock()
+	j.now = func() time.Time { return now }
+	j.mu.Unlock()
+
+	for _, offset := range []time.Duration{time.Second, 5 * time.Minute, 3 * time.Hour} {
+		now = start.Add(offset)
+		j.write(Entry{Time: now, RobotID: 7})
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Fatalf("Expected the expired segment to be deleted, leaving 2, got %d", len(files))
+	}
+	got := exported(t, j, Query{To: now.Add(time.Second)})
+	if len(got) != 2 || !got[0].Time.Equal(start.Add(5*time.Minute)) {
+		t.Fatalf("Expected the 2 entries in kept segments, got %+v", got)
+	}
+
+	// A small size budget keeps only the newest segments
+	j.maxBytes = 200
+	for i := 0; i < 5; i++ {
+		now = now.Add(time.Second)
+		j.write(Entry{Time: now, RobotID: 8, Error: "a fairly long error message to fill the budget"})
+	}
+	files, _ = os.ReadDir(dir)
+	if len(files) > 2 {
+		t.Fatalf("Expected the size budget to delete old segments, got %d files", len(files))
+	}
+}
+
+func TestUnaryInterceptor_JournalsPerRobot(t *testing.T) {
+	j, err := New(t.TempDir(), time.Hour, 1<<20, 16)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	interceptor := j.UnaryInterceptor()
+	ctx := middleware.WithRequestID(context.Background(), "req-1")
+	obs := &pb.Observation{Data: []float32{1, 2}, Channels: 1, Height: 1, Width: 2}
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.PlanResponse{Action: []float32{0.5}, ObsHash: "abc"}, nil
+	}
+	if _, err := interceptor(ctx, &pb.PlanRequest{RobotId: 1, Obs: obs}, &grpc.UnaryServerInfo{FullMethod: "/planner.PathPlanner/Plan"}, ok); err != nil {
+		t.Fatalf("Expected the call to pass, got %v", err)
+	}
+	fail := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "shedding bulk traffic")
+	}
+	batch := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{RobotId: 2, Obs: obs}, {RobotId: 3, Obs: obs}}}
+	interceptor(ctx, batch, &grpc.UnaryServerInfo{FullMethod: "/planner.PathPlanner/BatchPlan"}, fail)
+	j.Close()
+
+	rec := httptest.NewRecorder()
+	mux := admin.New("")
+	j.Register(mux)
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/journal?robot_id=3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var e Entry
+	if err := json.Unmarshal(rec.Body.Bytes(), &e); err != nil {
+		t.Fatalf("Expected one JSON entry, got %q: %v", rec.Body, err)
+	}
+	if e.RobotID != 3 || e.Code != "Unavailable" || e.BatchSize != 2 || e.RequestID != "req-1" || e.ObsShape != "1x1x2" || e.ObsHash == "" {
+		t.Fatalf("Expected a failed BatchPlan entry for robot 3, got %+v", e)
+	}
+
+	got := exported(t, j, Query{To: time.Now().Add(time.Minute)})
+	if len(got) != 3 || got[0].Code != "OK" || got[0].Action[0] != 0.5 || got[0].ObsHash != "abc" {
+		t.Fatalf("Expected 3 entries starting with the served Plan, got %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/journal?from=yesterday", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for an invalid time, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/journal?precision=1&robot_id=1", nil))
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte(`"action":[0.5`)) {
+		t.Fatalf("Expected robot 1's action with 1 decimal place, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/journal?action_encoding=hex", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for an unknown action encoding, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,57 @@
+// Package logging configures the service's structured logger. Messages are
+// written through log/slog as text (key=value) or JSON lines, and output from
+// packages still using the log package goes through the same handler.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// ParseLevel parses "debug", "info", "warn", or "error" (any case); empty
+// means info
+func ParseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	switch strings.ToLower(s) {
+	case "debug", "info", "warn", "error":
+		if err := level.UnmarshalText([]byte(s)); err != nil {
+			return 0, err
+		}
+		return level, nil
+	}
+	return 0, fmt.Errorf("invalid log level: %q", s)
+}
+
+// New returns a logger writing to w at level in format, "text" (the default)
+// or "json"
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid log format: %q", format)
+}
+
+// Setup makes a logger writing to stderr the default for both slog and the
+// log package
+func Setup(level, format string) error {
+	logger, err := New(os.Stderr, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
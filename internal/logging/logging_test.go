@@ -0,0 +1,48 @@
+// internal/logging/logging_test.go
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew_JSONAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", "json")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Info("dropped", "request_id", "req-1")
+	logger.Warn("kept", "request_id", "req-2", "batch_size", 4)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warning to be logged, got %q", buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected a JSON line, got %q: %v", lines[0], err)
+	}
+	if entry["msg"] != "kept" || entry["request_id"] != "req-2" || entry["batch_size"] != float64(4) {
+		t.Fatalf("Expected structured fields, got %v", entry)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel(""); err != nil || level != slog.LevelInfo {
+		t.Fatalf("Expected info by default, got %v (%v)", level, err)
+	}
+	if level, err := ParseLevel("DEBUG"); err != nil || level != slog.LevelDebug {
+		t.Fatalf("Expected debug, got %v (%v)", level, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatal("Expected an error for an unknown level")
+	}
+	if _, err := New(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Fatal("Expected an error for an unknown format")
+	}
+}
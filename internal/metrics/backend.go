@@ -0,0 +1,105 @@
+// internal/metrics/backend.go
+package metrics
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Labels are the label (tag) values attached to a metric sample
+type Labels map[string]string
+
+// Backend receives every metric emitted by the service. Names and labels are
+// the Prometheus ones; other backends translate them as needed.
+type Backend interface {
+	// Observe records a sample in a distribution (histogram)
+	Observe(name string, value float64, labels Labels)
+	// SetGauge sets a gauge to value
+	SetGauge(name string, value float64, labels Labels)
+	// AddGauge adjusts a gauge by delta
+	AddGauge(name string, delta float64, labels Labels)
+	// AddCounter increments a counter by delta
+	AddCounter(name string, delta float64, labels Labels)
+}
+
+// Collectors registered with Prometheus, by metric name
+var (
+	histograms = make(map[string]*prometheus.HistogramVec)
+	gauges     = make(map[string]*prometheus.GaugeVec)
+	counters   = make(map[string]*prometheus.CounterVec)
+)
+
+func newHistogramVec(opts prometheus.HistogramOpts, labels ...string) *prometheus.HistogramVec {
+	h := promauto.NewHistogramVec(opts, labels)
+	histograms[opts.Name] = h
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	describe(opts.Name, "histogram", opts.Help, labels, buckets)
+	return h
+}
+
+func newGaugeVec(opts prometheus.GaugeOpts, labels ...string) *prometheus.GaugeVec {
+	g := promauto.NewGaugeVec(opts, labels)
+	gauges[opts.Name] = g
+	describe(opts.Name, "gauge", opts.Help, labels, nil)
+	return g
+}
+
+func newCounterVec(opts prometheus.CounterOpts, labels ...string) *prometheus.CounterVec {
+	c := promauto.NewCounterVec(opts, labels)
+	counters[opts.Name] = c
+	describe(opts.Name, "counter", opts.Help, labels, nil)
+	return c
+}
+
+// Prometheus is the default Backend, updating the collectors served at /metrics
+type Prometheus struct{}
+
+// Observe implements Backend
+func (Prometheus) Observe(name string, value float64, labels Labels) {
+	histograms[name].With(prometheus.Labels(labels)).Observe(value)
+}
+
+// SetGauge implements Backend
+func (Prometheus) SetGauge(name string, value float64, labels Labels) {
+	gauges[name].With(prometheus.Labels(labels)).Set(value)
+}
+
+// AddGauge implements Backend
+func (Prometheus) AddGauge(name string, delta float64, labels Labels) {
+	gauges[name].With(prometheus.Labels(labels)).Add(delta)
+}
+
+// AddCounter implements Backend
+func (Prometheus) AddCounter(name string, delta float64, labels Labels) {
+	counters[name].With(prometheus.Labels(labels)).Add(delta)
+}
+
+// backend holds the active Backend
+var backend atomic.Value
+
+func init() {
+	backend.Store(backendHolder{Prometheus{}})
+}
+
+// backendHolder lets backends of different concrete types share one atomic.Value
+type backendHolder struct {
+	Backend
+}
+
+// SetBackend replaces the active metrics backend. It should be called once at
+// startup before traffic is served.
+func SetBackend(b Backend) {
+	if b == nil {
+		b = Prometheus{}
+	}
+	backend.Store(backendHolder{b})
+}
+
+func current() Backend {
+	return backend.Load().(backendHolder).Backend
+}
@@ -0,0 +1,77 @@
+// internal/metrics/catalog.go
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric describes one metric the service can emit
+type Metric struct {
+	Name string `json:"name"`
+	// Type is "counter", "gauge", "histogram", "summary", or "untyped"
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels"`
+	// Buckets are a histogram's upper bounds, excluding +Inf
+	Buckets []float64 `json:"buckets,omitempty"`
+}
+
+// described holds the service's own metrics, by name, as they are created.
+// Their vectors only reach the registry's output once they have a series.
+var described = make(map[string]Metric)
+
+// describe adds a metric created by the service to the catalog
+func describe(name, typ, help string, labels []string, buckets []float64) {
+	if labels == nil {
+		labels = []string{}
+	}
+	described[name] = Metric{Name: name, Type: typ, Help: help, Labels: labels, Buckets: buckets}
+}
+
+// Catalog returns every metric the service can emit, sorted by name: its own
+// metrics, whether or not they have been emitted yet, and the others in the
+// Prometheus registry, such as the Go runtime and process collectors
+func Catalog() ([]Metric, error) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Metric, 0, len(described)+len(families))
+	for _, m := range described {
+		out = append(out, m)
+	}
+	for _, f := range families {
+		if _, ok := described[f.GetName()]; ok {
+			continue
+		}
+		m := Metric{Name: f.GetName(), Type: strings.ToLower(f.GetType().String()), Help: f.GetHelp(), Labels: []string{}}
+		if len(f.GetMetric()) > 0 {
+			for _, l := range f.GetMetric()[0].GetLabel() {
+				m.Labels = append(m.Labels, l.GetName())
+			}
+		}
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// CatalogHandler serves the Catalog as JSON, for provisioning dashboards and
+// validating scrape configs
+func CatalogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		catalog, err := Catalog()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(catalog)
+	})
+}
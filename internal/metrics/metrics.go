@@ -3,22 +3,91 @@ package metrics
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
 	// GRPCServerHandlingSeconds is a histogram for gRPC server request latencies
-	GRPCServerHandlingSeconds = promauto.NewHistogramVec(
+	GRPCServerHandlingSeconds = newHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "grpc_server_handling_seconds",
 			Help:    "Histogram of response latency (seconds) of gRPC that had been application-level handled by the server.",
 			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 		},
-		[]string{"method", "code"},
+		"method", "code",
+	)
+
+	// GRPCClientTransitSeconds is the time from a client's stamped send time
+	// until the server received the call
+	GRPCClientTransitSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_client_transit_seconds",
+			Help:    "Histogram of the time (seconds) from the client's x-client-send-time until the server received the call.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		"method",
+	)
+
+	// GRPCInterceptorSeconds is the time spent in each interceptor of the
+	// unary chain, excluding the rest of the chain, and in the handler
+	GRPCInterceptorSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_interceptor_seconds",
+			Help:    "Histogram of the time (seconds) spent in each unary interceptor, excluding the interceptors and handler it calls, by stage (the handler's own time is stage handler).",
+			Buckets: []float64{.00001, .000025, .00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, 1},
+		},
+		"stage",
+	)
+
+	// GRPCClientSendTimeRejectedTotal counts unusable client send times
+	GRPCClientSendTimeRejectedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_client_send_time_rejected_total",
+			Help: "Total number of x-client-send-time values not recorded, by reason (malformed, or in the future from clock skew).",
+		},
+		"method", "reason",
+	)
+
+	// GRPCStreamAgeSeconds tracks how long server streams stayed open
+	GRPCStreamAgeSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_stream_age_seconds",
+			Help:    "Histogram of how long gRPC server streams stayed open (seconds), observed when they end.",
+			Buckets: []float64{.1, 1, 10, 60, 300, 900, 1800, 3600, 4 * 3600, 24 * 3600},
+		},
+		"method",
+	)
+
+	// GRPCStreamMessageDuration tracks how long each message of a planning
+	// stream took to answer
+	GRPCStreamMessageDuration = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_stream_message_duration_seconds",
+			Help:    "Histogram of the time (seconds) to answer each message of a planning stream, by method and status code.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		"method", "code",
+	)
+
+	// GRPCStreamsActive is the number of open server streams
+	GRPCStreamsActive = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "grpc_streams_active",
+			Help: "Number of open gRPC server streams.",
+		},
+		"method",
+	)
+
+	// GRPCStreamsReapedTotal counts streams closed for being idle
+	GRPCStreamsReapedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_streams_reaped_total",
+			Help: "Total number of gRPC server streams closed after being idle too long.",
+		},
+		"method",
 	)
 
 	// InferenceBatchSize is a histogram for tracking inference batch sizes
-	InferenceBatchSize = promauto.NewHistogram(
+	InferenceBatchSize = newHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "inference_batch_size",
 			Help:    "Histogram of batch sizes for inference requests.",
@@ -26,8 +95,26 @@ var (
 		},
 	)
 
+	// BatchingBatchSize tracks observations per coalesced inference call
+	BatchingBatchSize = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "batching_batch_size",
+			Help:    "Histogram of observations per inference call after cross-request batching.",
+			Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128, 256},
+		},
+	)
+
+	// BatchingCalls tracks calls coalesced into each inference call
+	BatchingCalls = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "batching_calls",
+			Help:    "Histogram of calls coalesced into each inference call.",
+			Buckets: []float64{1, 2, 4, 8, 16, 32, 64},
+		},
+	)
+
 	// InferenceLatencySeconds is a histogram for inference-only latency
-	InferenceLatencySeconds = promauto.NewHistogram(
+	InferenceLatencySeconds = newHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "inference_latency_seconds",
 			Help:    "Histogram of inference latency (seconds) excluding gRPC overhead.",
@@ -35,8 +122,706 @@ var (
 		},
 	)
 
+	// InferenceExecutionProvider is set to 1 for each execution provider a model's session runs with
+	InferenceExecutionProvider = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_execution_provider",
+			Help: "Execution providers appended to a model's ONNX Runtime session (1 = appended), by model and provider.",
+		},
+		"model", "provider",
+	)
+
+	// InferenceProviderFallback is 1 when a requested accelerator failed to load for a model
+	InferenceProviderFallback = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_provider_fallback",
+			Help: "Whether a requested execution provider failed to load and the model fell back (1 = fallback).",
+		},
+		"model",
+	)
+
+	// InferenceBreakerState is a gauge of the inference circuit breaker state
+	InferenceBreakerState = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_breaker_state",
+			Help: "Inference circuit breaker state (0 = closed, 1 = half-open, 2 = open).",
+		},
+	)
+
+	// InferenceBreakerRejectedTotal counts batches failed fast by the breaker
+	InferenceBreakerRejectedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_breaker_rejected_total",
+			Help: "Total number of inference batches failed without running while the circuit breaker was open.",
+		},
+	)
+
+	// StageTimeoutSeconds is the current timeout of each internal stage
+	StageTimeoutSeconds = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "stage_timeout_seconds",
+			Help: "Current timeout (seconds) of each internal stage (inference, cache), static or adaptively tuned; 0 means none.",
+		},
+		"stage",
+	)
+
+	// StageTimeoutsTotal counts calls that ran out of their stage's time
+	StageTimeoutsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "stage_timeouts_total",
+			Help: "Total number of internal stage calls (inference, cache) that exceeded the stage timeout.",
+		},
+		"stage",
+	)
+
+	// ModelAlertThreshold exports configured per-model alerting limits
+	ModelAlertThreshold = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "model_alert_threshold",
+			Help: "Configured alerting threshold for the served model, by model and threshold (max_latency_seconds, max_divergence).",
+		},
+		"model", "threshold",
+	)
+
+	// InferenceDeviceLatencySeconds is a histogram of inference latency per GPU
+	InferenceDeviceLatencySeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "inference_device_latency_seconds",
+			Help:    "Histogram of inference latency (seconds) per GPU device.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		"device",
+	)
+
+	// InferenceDeviceInflight is a gauge of batches running on each GPU
+	InferenceDeviceInflight = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_device_inflight",
+			Help: "Number of inference batches currently running per GPU device.",
+		},
+		"device",
+	)
+
+	// InferenceDeviceMemoryUsedBytes is a gauge of memory in use on each GPU
+	InferenceDeviceMemoryUsedBytes = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_device_memory_used_bytes",
+			Help: "GPU memory in use (bytes) per device, as reported by nvidia-smi.",
+		},
+		"device",
+	)
+
+	// InferenceTensorBytes is a histogram of input tensor bytes packed per request
+	InferenceTensorBytes = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "inference_tensor_bytes",
+			Help:    "Histogram of bytes packed into the input tensor per request.",
+			Buckets: []float64{1 << 10, 1 << 14, 1 << 17, 1 << 20, 1 << 22, 1 << 24, 1 << 26, 1 << 28, 1 << 30},
+		},
+	)
+
+	// InferenceTensorBytesInflight is a gauge of input tensor bytes held by requests in inference
+	InferenceTensorBytesInflight = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_tensor_bytes_inflight",
+			Help: "Bytes of input tensors currently packed for requests waiting for or running inference.",
+		},
+	)
+
+	// InferenceTensorBytesHighWater is a gauge of the most input tensor bytes held at once
+	InferenceTensorBytesHighWater = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_tensor_bytes_high_water",
+			Help: "Most bytes of input tensors packed at once since the process started.",
+		},
+	)
+
+	// InferenceTensorRejectedTotal counts requests whose input tensor exceeded the per-request cap
+	InferenceTensorRejectedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_tensor_rejected_total",
+			Help: "Total number of requests rejected because their input tensor exceeded max_tensor_bytes.",
+		},
+	)
+
+	// BatchRejectedTotal counts calls refused for exceeding the batch size limit
+	BatchRejectedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "batch_rejected_total",
+			Help: "Total number of calls rejected because their batch exceeded max_batch_size.",
+		},
+	)
+
+	// SchedulerQueueWaitSeconds is a histogram of time batches spend queued for an inference slot
+	SchedulerQueueWaitSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "scheduler_queue_wait_seconds",
+			Help:    "Histogram of time (seconds) inference batches waited for a scheduler slot, per tenant.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+		"tenant",
+	)
+
+	// SchedulerQueueDepth is a gauge of batches currently waiting for an inference slot
+	SchedulerQueueDepth = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "scheduler_queue_depth",
+			Help: "Number of inference batches waiting for a scheduler slot, per tenant.",
+		},
+		"tenant",
+	)
+
+	// WatchdogGoroutines is a gauge of goroutines observed by the watchdog
+	WatchdogGoroutines = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchdog_goroutines",
+			Help: "Number of goroutines at the last watchdog check.",
+		},
+	)
+
+	// WatchdogPendingBatches is a gauge of inference batches queued or running
+	WatchdogPendingBatches = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchdog_pending_batches",
+			Help: "Number of inference batches queued or running at the last watchdog check.",
+		},
+	)
+
+	// WatchdogTripsTotal counts watchdog detections by reason
+	WatchdogTripsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "watchdog_trips_total",
+			Help: "Total number of watchdog detections, by reason.",
+		},
+		"reason",
+	)
+
+	// RolloutStage is a gauge of the model rollout stage
+	RolloutStage = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rollout_stage",
+			Help: "Model rollout stage (0 = idle, 1 = shadow, 2 = canary, 3 = ramp, 4 = full, -1 = rolled back).",
+		},
+	)
+
+	// RolloutCandidateTrafficPercent is a gauge of traffic served by the candidate model
+	RolloutCandidateTrafficPercent = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rollout_candidate_traffic_percent",
+			Help: "Percentage of traffic served by the candidate model.",
+		},
+	)
+
+	// RolloutBatchesTotal counts inference batches by rollout arm and result
+	RolloutBatchesTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "rollout_batches_total",
+			Help: "Total number of inference batches during a rollout, by arm (primary, candidate, shadow) and result.",
+		},
+		"arm", "result",
+	)
+
+	// RolloutDivergence is a histogram of shadow divergence between candidate and primary
+	RolloutDivergence = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rollout_divergence",
+			Help:    "Histogram of mean absolute action difference between the candidate and primary models in shadow.",
+			Buckets: []float64{.0001, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+	)
+
+	// RolloutRollbacksTotal counts rollbacks of candidate models
+	RolloutRollbacksTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "rollout_rollbacks_total",
+			Help: "Total number of candidate model rollbacks.",
+		},
+	)
+
+	// EnrichmentFetchSeconds is a histogram of feature store lookup latency
+	EnrichmentFetchSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "enrichment_fetch_seconds",
+			Help:    "Histogram of feature store lookup latency (seconds), by source and result.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+		},
+		"source", "result",
+	)
+
+	// EnrichmentMissingTotal counts features replaced by defaults
+	EnrichmentMissingTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "enrichment_missing_total",
+			Help: "Total number of robot features missing from the feature store, by feature.",
+		},
+		"feature",
+	)
+
+	// DedupHitsTotal counts observations answered from the dedup window
+	DedupHitsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "dedup_hits_total",
+			Help: "Total number of repeated observations answered without inference.",
+		},
+	)
+
+	// CacheLookupsTotal counts cache lookups by where they were served from
+	CacheLookupsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "Total number of cache lookups, by source (redis, or the local fallback) and result (hit or miss).",
+		},
+		"source", "result",
+	)
+
+	// PlanProposalsTotal counts two-phase commit proposals by outcome
+	PlanProposalsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "plan_proposals_total",
+			Help: "Total number of proposed actions, by outcome (committed, vetoed, or expired without a commit).",
+		},
+		"result",
+	)
+
+	// PlanProposalWatchDroppedTotal counts proposals not sent to a slow supervisor
+	PlanProposalWatchDroppedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "plan_proposal_watch_dropped_total",
+			Help: "Total number of proposals dropped for a WatchProposals stream whose buffer was full.",
+		},
+	)
+
+	// PlanCacheRequestsTotal counts plan cache lookups by result
+	PlanCacheRequestsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "plan_cache_requests_total",
+			Help: "Total number of observations looked up in the plan cache, by result (hit, miss, or error).",
+		},
+		"result",
+	)
+
+	// PoseCacheLookupsTotal counts cached robot pose lookups by result
+	PoseCacheLookupsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "pose_cache_lookups_total",
+			Help: "Total number of robots whose last pose was looked up in the cache, by result (hit, miss, or error).",
+		},
+		"result",
+	)
+
+	// ObservationOutOfRangeTotal counts observations outside the model's expected input range
+	ObservationOutOfRangeTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "observation_out_of_range_total",
+			Help: "Total number of observations outside the model's expected input range, by action taken.",
+		},
+		"action",
+	)
+
+	// DownsampledObservationsTotal counts observations planned at reduced resolution
+	DownsampledObservationsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "downsampled_observations_total",
+			Help: "Total number of observations downsampled before inference while the service was degraded.",
+		},
+	)
+
+	// PreprocessSeconds is the time spent preprocessing a batch's observations
+	PreprocessSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "preprocess_seconds",
+			Help:    "Histogram of the time (seconds) spent running the preprocessing pipeline over a batch's observations.",
+			Buckets: []float64{.00001, .00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+		},
+	)
+
+	// PreprocessedObservationsTotal counts observations run through a preprocessing pipeline
+	PreprocessedObservationsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "preprocessed_observations_total",
+			Help: "Total number of observations run through a preprocessing pipeline before inference.",
+		},
+	)
+
+	// DecodedObservationsTotal counts observations decoded by a codec
+	DecodedObservationsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "decoded_observations_total",
+			Help: "Total number of observations sent as uint8 or encoded data and decoded to float32 by the server, by codec.",
+		},
+		"encoding",
+	)
+
+	// ObservationDecodeErrorsTotal counts observations that failed to decode
+	ObservationDecodeErrorsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "observation_decode_errors_total",
+			Help: "Total number of observations whose data failed to decode, by codec (or unregistered encoding ID).",
+		},
+		"encoding",
+	)
+
+	// CanarySuccess reports whether the last canary check passed
+	CanarySuccess = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "canary_success",
+			Help: "Whether the last synthetic canary Plan through the gRPC listener passed (1) or failed (0).",
+		},
+	)
+
+	// CanaryChecksTotal counts canary checks by result
+	CanaryChecksTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_checks_total",
+			Help: "Total number of synthetic canary checks by result (ok, error, slow, mismatch).",
+		},
+		"result",
+	)
+
+	// CanaryLatencySeconds is the round trip of canary Plan calls
+	CanaryLatencySeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "canary_latency_seconds",
+			Help:    "Histogram of the round trip (seconds) of synthetic canary Plan calls through the gRPC listener.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+	)
+
+	// ModelRegistryVersion is set to 1 for the registry version the model was resolved to
+	ModelRegistryVersion = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "model_registry_version",
+			Help: "Model registry version the served model was resolved to at startup (1 = served), by registered name, stage, and version.",
+		},
+		"name", "stage", "version",
+	)
+
+	// ModelDownloadsTotal counts models fetched from a URL at startup
+	ModelDownloadsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_downloads_total",
+			Help: "Total number of models given as URLs fetched by result (downloaded, cached, checksum_mismatch, failed).",
+		},
+		"result",
+	)
+
+	// ModelDownloadSeconds is the time spent downloading a model
+	ModelDownloadSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "model_download_seconds",
+			Help:    "Histogram of the time (seconds) spent downloading and verifying a model.",
+			Buckets: []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		},
+	)
+
+	// StateSnapshotsTotal counts per-robot state snapshots saved and restored
+	StateSnapshotsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "state_snapshots_total",
+			Help: "Total number of per-robot state snapshots by result (saved, failed, restored, stale, corrupt).",
+		},
+		"result",
+	)
+
+	// StateSnapshotBytes is the size of the last saved state snapshot
+	StateSnapshotBytes = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "state_snapshot_bytes",
+			Help: "Size in bytes of the last per-robot state snapshot saved to Redis.",
+		},
+	)
+
+	// ModelVersionBatchesTotal counts inference batches by model version
+	ModelVersionBatchesTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_version_batches_total",
+			Help: "Total number of inference batches by model version and result (ok, error).",
+		},
+		"version", "result",
+	)
+
+	// ModelVersionObservationsTotal counts observations planned by model version
+	ModelVersionObservationsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_version_observations_total",
+			Help: "Total number of observations planned by model version.",
+		},
+		"version",
+	)
+
+	// ModelVersionInferenceSeconds is a histogram of inference latency by model version
+	ModelVersionInferenceSeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "model_version_inference_seconds",
+			Help:    "Histogram of inference latency (seconds) by model version.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		"version",
+	)
+
+	// ActionContractViolationsTotal counts inference results that break the action contract
+	ActionContractViolationsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "action_contract_violations_total",
+			Help: "Total number of inference results rejected for breaking the declared action contract, by reason.",
+		},
+		"reason",
+	)
+
+	// NonFiniteOutputsTotal counts observations whose model outputs held NaN
+	// or Inf, by how they were answered
+	NonFiniteOutputsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "non_finite_outputs_total",
+			Help: "Total number of observations whose model outputs held NaN or Inf, by action (rejected or substituted).",
+		},
+		"action",
+	)
+
+	// RobotPlacementClaimsTotal counts claims of robots planned on this
+	// replica by result
+	RobotPlacementClaimsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "robot_placement_claims_total",
+			Help: "Total number of robot claims by this replica, by result (claimed, reassigned, failed, or dropped).",
+		},
+		"result",
+	)
+
+	// RobotsAssigned is the number of robots planned on this replica recently
+	RobotsAssigned = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "robots_assigned",
+			Help: "Number of robots planned on this replica within the placement idle period.",
+		},
+	)
+
+	// UpstreamCallsTotal counts calls proxied to the upstream instance by
+	// reason and result
+	UpstreamCallsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "upstream_calls_total",
+			Help: "Total number of calls proxied to the upstream policy-service, by reason (unavailable or busy) and result (ok, cached, or error).",
+		},
+		"reason", "result",
+	)
+
+	// UpstreamLatencySeconds is a histogram of upstream round trips
+	UpstreamLatencySeconds = newHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "upstream_latency_seconds",
+			Help:    "Round trip of calls proxied to the upstream policy-service in seconds.",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+
+	// SafetyChecksTotal counts planned actions checked against the safety
+	// limits by result
+	SafetyChecksTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "safety_checks_total",
+			Help: "Total number of planned actions checked against the safety limits, by result (safe, flagged, clamped, or invalid).",
+		},
+		"result",
+	)
+
+	// AsyncPlansTotal counts PlanAsync jobs by outcome
+	AsyncPlansTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "async_plans_total",
+			Help: "Total number of PlanAsync jobs, by result (ok, error, or rejected when the queue is full).",
+		},
+		"result",
+	)
+
+	// AsyncQueueDepth is the number of PlanAsync jobs waiting for a worker
+	AsyncQueueDepth = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "async_queue_depth",
+			Help: "Number of PlanAsync jobs waiting for a worker.",
+		},
+	)
+
+	// PlanUpdatesRobots is the number of robots planned on the GetPlanUpdates schedule
+	PlanUpdatesRobots = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "plan_updates_robots",
+			Help: "Number of robots planned on the GetPlanUpdates schedule.",
+		},
+	)
+
+	// PlanUpdatePollsTotal counts GetPlanUpdates polls by result
+	PlanUpdatePollsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "plan_update_polls_total",
+			Help: "Total number of GetPlanUpdates polls, by result (updated or timeout).",
+		},
+		"result",
+	)
+
+	// RecorderEntriesTotal counts episode recorder entries by outcome
+	RecorderEntriesTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "recorder_entries_total",
+			Help: "Total number of episode recorder entries, by result (recorded, duplicate retries, dropped when the buffer is full, or failed).",
+		},
+		"result",
+	)
+
+	// PublishedPlansTotal counts plans sent by the plan publisher by outcome
+	PublishedPlansTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "published_plans_total",
+			Help: "Total number of plans sent by the plan publisher, by result (published, dropped when the buffer is full, or failed).",
+		},
+		"result",
+	)
+
+	// ScheduledPlansTotal counts robots of scheduled groups by outcome
+	ScheduledPlansTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "scheduled_plans_total",
+			Help: "Total number of robots of scheduled groups, by schedule and result (planned, missing an observation, or failed).",
+		},
+		"schedule", "result",
+	)
+
+	// JournalEntriesTotal counts request journal entries by outcome
+	JournalEntriesTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "journal_entries_total",
+			Help: "Total number of request journal entries, by result (written, dropped when the buffer is full, or failed).",
+		},
+		"result",
+	)
+
+	// RecorderCompactionsTotal counts recorder compaction runs by outcome
+	RecorderCompactionsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "recorder_compactions_total",
+			Help: "Total number of recorder stream compactions, by result.",
+		},
+		"result",
+	)
+
+	// RecorderCompactedEntriesTotal counts entries moved to object storage
+	RecorderCompactedEntriesTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "recorder_compacted_entries_total",
+			Help: "Total number of recorder entries moved from the stream to object storage.",
+		},
+	)
+
+	// LoadSheddingActive is 1 while bulk traffic is being shed
+	LoadSheddingActive = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "load_shedding_active",
+			Help: "1 while bulk traffic is shed because the error budget is burning too fast, else 0.",
+		},
+	)
+
+	// LoadSheddingBurnRate is the error budget burn rate per window
+	LoadSheddingBurnRate = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "load_shedding_burn_rate",
+			Help: "Error budget burn rate over the short and long shedding windows.",
+		},
+		"window",
+	)
+
+	// LoadShedRequestsTotal counts bulk requests rejected while shedding
+	LoadShedRequestsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "load_shed_requests_total",
+			Help: "Total number of bulk requests rejected while shedding, by method.",
+		},
+		"method",
+	)
+
+	// AdmissionInFlight is the number of admitted calls running
+	AdmissionInFlight = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "admission_in_flight",
+			Help: "Number of calls admitted by the admission controller and still running.",
+		},
+	)
+
+	// AdmissionQueueLatencySeconds is the shortest inference queue wait over
+	// the last admission interval
+	AdmissionQueueLatencySeconds = newGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "admission_queue_latency_seconds",
+			Help: "Shortest time (seconds) an inference batch waited to run over the last admission interval.",
+		},
+	)
+
+	// AdmissionRejectedTotal counts calls rejected because the service is
+	// overloaded
+	AdmissionRejectedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "admission_rejected_total",
+			Help: "Total number of calls rejected by the admission controller, by method and reason (in_flight or queue_latency).",
+		},
+		"method", "reason",
+	)
+
+	// RobotTokensIssuedTotal counts IssueRobotToken calls by result
+	RobotTokensIssuedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "robot_tokens_issued_total",
+			Help: "Total number of IssueRobotToken calls, by result (issued, denied, or error).",
+		},
+		"result",
+	)
+
+	// TenantGateRejectedTotal counts calls rejected because their tenant is
+	// paused or draining
+	TenantGateRejectedTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "tenant_gate_rejected_total",
+			Help: "Total number of calls rejected because their tenant is paused or draining, by tenant and state.",
+		},
+		"tenant", "state",
+	)
+
+	// RateLimitedRequestsTotal counts requests rejected for exceeding their
+	// client's quota
+	RateLimitedRequestsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limited_requests_total",
+			Help: "Total number of requests rejected by the per-client rate limiter, by method.",
+		},
+		"method",
+	)
+
+	// ModelReloadsTotal counts model hot-reloads
+	ModelReloadsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "model_reloads_total",
+			Help: "Total number of model hot-reloads, by result.",
+		},
+		"result",
+	)
+
+	// InferencePanicsTotal counts panics recovered from the inference call path
+	InferencePanicsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_panics_total",
+			Help: "Total number of panics recovered from inference; each quarantines and recreates the session.",
+		},
+	)
+
+	// GRPCPanicsTotal counts panics recovered in gRPC handlers
+	GRPCPanicsTotal = newCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_panics_total",
+			Help: "Total number of panics recovered in gRPC handlers, by method.",
+		},
+		"method",
+	)
+
 	// HealthStatus is a gauge indicating the health status of the service
-	HealthStatus = promauto.NewGauge(
+	HealthStatus = newGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "health_status",
 			Help: "Health status of the service (1 = healthy, 0 = unhealthy).",
@@ -46,25 +831,483 @@ var (
 
 // RecordGRPCLatency records the latency of a gRPC method call
 func RecordGRPCLatency(method, code string, seconds float64) {
-	GRPCServerHandlingSeconds.WithLabelValues(method, code).Observe(seconds)
+	current().Observe("grpc_server_handling_seconds", seconds, Labels{"method": method, "code": code})
+}
+
+// RecordClientTransit records the time from a client's send time until the
+// server received the call
+func RecordClientTransit(method string, seconds float64) {
+	current().Observe("grpc_client_transit_seconds", seconds, Labels{"method": method})
+}
+
+// RecordInterceptorDuration records the time spent in one stage of the
+// interceptor chain
+func RecordInterceptorDuration(stage string, seconds float64) {
+	current().Observe("grpc_interceptor_seconds", seconds, Labels{"stage": stage})
+}
+
+// RecordClientSendTimeRejected records a client send time that was not usable
+func RecordClientSendTimeRejected(method, reason string) {
+	current().AddCounter("grpc_client_send_time_rejected_total", 1, Labels{"method": method, "reason": reason})
+}
+
+// RecordStreamAge records how long a server stream stayed open
+func RecordStreamAge(method string, seconds float64) {
+	current().Observe("grpc_stream_age_seconds", seconds, Labels{"method": method})
+}
+
+// RecordStreamMessage records the time to answer one message of a planning
+// stream with its status code
+func RecordStreamMessage(method, code string, seconds float64) {
+	current().Observe("grpc_stream_message_duration_seconds", seconds, Labels{"method": method, "code": code})
+}
+
+// AddActiveStreams adjusts the number of open server streams for method by delta
+func AddActiveStreams(method string, delta float64) {
+	current().AddGauge("grpc_streams_active", delta, Labels{"method": method})
+}
+
+// RecordStreamReaped records a server stream closed for being idle
+func RecordStreamReaped(method string) {
+	current().AddCounter("grpc_streams_reaped_total", 1, Labels{"method": method})
 }
 
 // RecordInferenceBatch records the batch size for an inference request
 func RecordInferenceBatch(size int) {
-	InferenceBatchSize.Observe(float64(size))
+	current().Observe("inference_batch_size", float64(size), nil)
+}
+
+// RecordCoalescedBatch records an inference call made of observations from calls requests
+func RecordCoalescedBatch(size, calls int) {
+	current().Observe("batching_batch_size", float64(size), nil)
+	current().Observe("batching_calls", float64(calls), nil)
 }
 
 // RecordInferenceLatency records the latency of an inference call
 func RecordInferenceLatency(seconds float64) {
-	InferenceLatencySeconds.Observe(seconds)
+	current().Observe("inference_latency_seconds", seconds, nil)
+}
+
+// RecordSchedulerQueueWait records how long a tenant's batch waited for an inference slot
+func RecordSchedulerQueueWait(tenant string, seconds float64) {
+	current().Observe("scheduler_queue_wait_seconds", seconds, Labels{"tenant": tenant})
+}
+
+// AddSchedulerQueueDepth adjusts the number of queued batches for a tenant
+func AddSchedulerQueueDepth(tenant string, delta float64) {
+	current().AddGauge("scheduler_queue_depth", delta, Labels{"tenant": tenant})
+}
+
+// SetWatchdogGoroutines records the goroutine count seen by the watchdog
+func SetWatchdogGoroutines(count int) {
+	current().SetGauge("watchdog_goroutines", float64(count), nil)
+}
+
+// SetWatchdogPending records the number of pending inference batches
+func SetWatchdogPending(pending int64) {
+	current().SetGauge("watchdog_pending_batches", float64(pending), nil)
+}
+
+// RecordWatchdogTrip records a watchdog detection
+func RecordWatchdogTrip(reason string) {
+	current().AddCounter("watchdog_trips_total", 1, Labels{"reason": reason})
+}
+
+// SetRolloutStage records the rollout stage and the candidate's traffic share
+func SetRolloutStage(stage, trafficPercent float64) {
+	current().SetGauge("rollout_stage", stage, nil)
+	current().SetGauge("rollout_candidate_traffic_percent", trafficPercent, nil)
+}
+
+// RecordRolloutBatch records an inference batch served by a rollout arm
+func RecordRolloutBatch(arm string, ok bool) {
+	result := "ok"
+	if !ok {
+		result = "error"
+	}
+	current().AddCounter("rollout_batches_total", 1, Labels{"arm": arm, "result": result})
+}
+
+// RecordRolloutDivergence records the divergence of a shadowed batch
+func RecordRolloutDivergence(divergence float64) {
+	current().Observe("rollout_divergence", divergence, nil)
+}
+
+// RecordRollback records a candidate rollback
+func RecordRollback() {
+	current().AddCounter("rollout_rollbacks_total", 1, nil)
+}
+
+// RecordEnrichmentFetch records the latency of a feature store lookup
+func RecordEnrichmentFetch(source string, seconds float64, ok bool) {
+	result := "ok"
+	if !ok {
+		result = "error"
+	}
+	current().Observe("enrichment_fetch_seconds", seconds, Labels{"source": source, "result": result})
+}
+
+// RecordEnrichmentMissing records a feature that was missing for a robot
+func RecordEnrichmentMissing(feature string) {
+	current().AddCounter("enrichment_missing_total", 1, Labels{"feature": feature})
+}
+
+// SetExecutionProviders records the appended execution providers for model and
+// whether any requested provider fell back
+func SetExecutionProviders(model string, providers []string, fallback bool) {
+	for _, provider := range providers {
+		current().SetGauge("inference_execution_provider", 1, Labels{"model": model, "provider": provider})
+	}
+	value := 0.0
+	if fallback {
+		value = 1
+	}
+	current().SetGauge("inference_provider_fallback", value, Labels{"model": model})
+}
+
+// SetBreakerState sets the inference circuit breaker state gauge
+func SetBreakerState(value float64) {
+	current().SetGauge("inference_breaker_state", value, nil)
+}
+
+// RecordBreakerRejected records a batch failed fast by the open breaker
+func RecordBreakerRejected() {
+	current().AddCounter("inference_breaker_rejected_total", 1, nil)
+}
+
+// SetStageTimeout sets the current timeout of an internal stage
+func SetStageTimeout(stage string, seconds float64) {
+	current().SetGauge("stage_timeout_seconds", seconds, Labels{"stage": stage})
+}
+
+// RecordStageTimeout records a call that exceeded its stage's timeout
+func RecordStageTimeout(stage string) {
+	current().AddCounter("stage_timeouts_total", 1, Labels{"stage": stage})
+}
+
+// SetModelAlertThreshold exports a configured alerting threshold for model, so
+// alert rules can compare metrics against it instead of hard-coding limits
+func SetModelAlertThreshold(model, threshold string, value float64) {
+	current().SetGauge("model_alert_threshold", value, Labels{"model": model, "threshold": threshold})
+}
+
+// RecordDeviceLatency records the inference latency of a batch run on device
+func RecordDeviceLatency(device string, seconds float64) {
+	current().Observe("inference_device_latency_seconds", seconds, Labels{"device": device})
+}
+
+// AddDeviceInflight adjusts the number of batches running on device by delta
+func AddDeviceInflight(device string, delta float64) {
+	current().AddGauge("inference_device_inflight", delta, Labels{"device": device})
+}
+
+// SetDeviceMemoryUsed sets the memory in use on device
+func SetDeviceMemoryUsed(device string, bytes float64) {
+	current().SetGauge("inference_device_memory_used_bytes", bytes, Labels{"device": device})
+}
+
+// RecordDedupHits records observations answered from the dedup window
+func RecordDedupHits(n int) {
+	current().AddCounter("dedup_hits_total", float64(n), nil)
+}
+
+// RecordObservationOutOfRange records an out-of-range observation that was
+// rejected or clamped
+func RecordObservationOutOfRange(action string) {
+	current().AddCounter("observation_out_of_range_total", 1, Labels{"action": action})
+}
+
+// RecordDownsampledObservations records observations downsampled before
+// inference in degraded mode
+func RecordDownsampledObservations(n int) {
+	current().AddCounter("downsampled_observations_total", float64(n), nil)
+}
+
+// RecordPreprocess records a batch of n observations preprocessed in seconds
+func RecordPreprocess(n int, seconds float64) {
+	current().AddCounter("preprocessed_observations_total", float64(n), nil)
+	current().Observe("preprocess_seconds", seconds, nil)
+}
+
+// RecordDecodedObservation records an observation decoded by a codec
+func RecordDecodedObservation(encoding string) {
+	current().AddCounter("decoded_observations_total", 1, Labels{"encoding": encoding})
+}
+
+// RecordObservationDecodeError records an observation that failed to decode
+func RecordObservationDecodeError(encoding string) {
+	current().AddCounter("observation_decode_errors_total", 1, Labels{"encoding": encoding})
+}
+
+// RecordCanaryCheck records a canary check with result and its round trip
+func RecordCanaryCheck(result string, seconds float64) {
+	success := 0.0
+	if result == "ok" {
+		success = 1
+	}
+	current().SetGauge("canary_success", success, nil)
+	current().AddCounter("canary_checks_total", 1, Labels{"result": result})
+	current().Observe("canary_latency_seconds", seconds, nil)
+}
+
+// SetModelRegistryVersion records the registry version the model was resolved to
+func SetModelRegistryVersion(name, stage, version string) {
+	current().SetGauge("model_registry_version", 1, Labels{"name": name, "stage": stage, "version": version})
+}
+
+// RecordModelDownload records a model fetch with result, and for
+// downloads the seconds it took
+func RecordModelDownload(result string, seconds float64) {
+	current().AddCounter("model_downloads_total", 1, Labels{"result": result})
+	if seconds > 0 {
+		current().Observe("model_download_seconds", seconds, nil)
+	}
+}
+
+// RecordStateSnapshot records a state snapshot saved or restored with result
+func RecordStateSnapshot(result string) {
+	current().AddCounter("state_snapshots_total", 1, Labels{"result": result})
+}
+
+// SetStateSnapshotBytes records the size of the last saved state snapshot
+func SetStateSnapshotBytes(n int) {
+	current().SetGauge("state_snapshot_bytes", float64(n), nil)
+}
+
+// RecordModelVersionBatch records an inference batch of size observations
+// run by a model version, with its latency when it succeeded
+func RecordModelVersionBatch(version string, size int, seconds float64, ok bool) {
+	result := "ok"
+	if !ok {
+		result = "error"
+	}
+	labels := Labels{"version": version}
+	current().AddCounter("model_version_batches_total", 1, Labels{"version": version, "result": result})
+	if ok {
+		current().AddCounter("model_version_observations_total", float64(size), labels)
+		current().Observe("model_version_inference_seconds", seconds, labels)
+	}
+}
+
+// RecordPlacementClaim records a claim of a robot planned on this replica
+func RecordPlacementClaim(result string) {
+	current().AddCounter("robot_placement_claims_total", 1, Labels{"result": result})
+}
+
+// SetRobotsAssigned sets the number of robots recently planned on this replica
+func SetRobotsAssigned(n int) {
+	current().SetGauge("robots_assigned", float64(n), nil)
+}
+
+// RecordUpstreamCall records a call proxied to the upstream instance
+func RecordUpstreamCall(reason, result string) {
+	current().AddCounter("upstream_calls_total", 1, Labels{"reason": reason, "result": result})
+}
+
+// RecordUpstreamLatency records the round trip of a proxied call
+func RecordUpstreamLatency(seconds float64) {
+	current().Observe("upstream_latency_seconds", seconds, nil)
+}
+
+// RecordSafetyCheck records a planned action checked against the safety limits
+func RecordSafetyCheck(result string) {
+	current().AddCounter("safety_checks_total", 1, Labels{"result": result})
+}
+
+// RecordNonFiniteOutputs records n observations whose model outputs held NaN
+// or Inf
+func RecordNonFiniteOutputs(action string, n int) {
+	current().AddCounter("non_finite_outputs_total", float64(n), Labels{"action": action})
+}
+
+// RecordActionContractViolation records an inference result that broke the
+// action contract
+func RecordActionContractViolation(reason string) {
+	current().AddCounter("action_contract_violations_total", 1, Labels{"reason": reason})
+}
+
+// RecordAsyncPlan records the outcome of a PlanAsync job
+func RecordAsyncPlan(result string) {
+	current().AddCounter("async_plans_total", 1, Labels{"result": result})
+}
+
+// SetAsyncQueueDepth sets the number of PlanAsync jobs waiting for a worker
+func SetAsyncQueueDepth(depth int) {
+	current().SetGauge("async_queue_depth", float64(depth), nil)
+}
+
+// SetPlanUpdatesRobots sets the number of robots planned on the GetPlanUpdates schedule
+func SetPlanUpdatesRobots(n int) {
+	current().SetGauge("plan_updates_robots", float64(n), nil)
+}
+
+// RecordPlanUpdatePoll records a GetPlanUpdates poll with a result
+func RecordPlanUpdatePoll(result string) {
+	current().AddCounter("plan_update_polls_total", 1, Labels{"result": result})
+}
+
+// RecordRecorderEntries records n episode recorder entries with an outcome
+func RecordRecorderEntries(result string, n int) {
+	current().AddCounter("recorder_entries_total", float64(n), Labels{"result": result})
+}
+
+// RecordScheduledPlans records n robots of a scheduled group with an outcome
+func RecordScheduledPlans(schedule, result string, n int) {
+	if n == 0 {
+		return
+	}
+	current().AddCounter("scheduled_plans_total", float64(n), Labels{"schedule": schedule, "result": result})
+}
+
+// RecordPublishedPlans records n plans sent by the plan publisher with an outcome
+func RecordPublishedPlans(result string, n int) {
+	current().AddCounter("published_plans_total", float64(n), Labels{"result": result})
+}
+
+// RecordCacheLookup records a cache lookup served from source
+func RecordCacheLookup(source string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	current().AddCounter("cache_lookups_total", 1, Labels{"source": source, "result": result})
+}
+
+// RecordPlanProposal records the outcome of a proposed action
+func RecordPlanProposal(result string) {
+	current().AddCounter("plan_proposals_total", 1, Labels{"result": result})
+}
+
+// RecordProposalWatchDropped records a proposal dropped for a slow supervisor
+func RecordProposalWatchDropped() {
+	current().AddCounter("plan_proposal_watch_dropped_total", 1, nil)
+}
+
+// RecordPlanCache records n plan cache lookups with a result
+func RecordPlanCache(result string, n int) {
+	current().AddCounter("plan_cache_requests_total", float64(n), Labels{"result": result})
+}
+
+// RecordTensorBytes records the bytes packed into a request's input tensor
+func RecordTensorBytes(bytes int64) {
+	current().Observe("inference_tensor_bytes", float64(bytes), nil)
+}
+
+// SetTensorBytesInflight records the input tensor bytes currently held and
+// the process high-water mark
+func SetTensorBytesInflight(inflight, highWater int64) {
+	current().SetGauge("inference_tensor_bytes_inflight", float64(inflight), nil)
+	current().SetGauge("inference_tensor_bytes_high_water", float64(highWater), nil)
+}
+
+// RecordBatchRejected records a call rejected for its batch size
+func RecordBatchRejected() {
+	current().AddCounter("batch_rejected_total", 1, nil)
+}
+
+// RecordTensorRejected records a request rejected for its input tensor size
+func RecordTensorRejected() {
+	current().AddCounter("inference_tensor_rejected_total", 1, nil)
+}
+
+// RecordPoseLookups records n cached robot pose lookups with a result
+func RecordPoseLookups(result string, n int) {
+	current().AddCounter("pose_cache_lookups_total", float64(n), Labels{"result": result})
+}
+
+// RecordJournalEntries records n request journal entries with an outcome
+func RecordJournalEntries(result string, n int) {
+	current().AddCounter("journal_entries_total", float64(n), Labels{"result": result})
+}
+
+// RecordRecorderCompaction records a compaction run and the entries it moved
+func RecordRecorderCompaction(entries int, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	current().AddCounter("recorder_compactions_total", 1, Labels{"result": result})
+	if entries > 0 {
+		current().AddCounter("recorder_compacted_entries_total", float64(entries), nil)
+	}
+}
+
+// SetLoadShedding sets whether bulk traffic is being shed
+func SetLoadShedding(active bool) {
+	value := 0.0
+	if active {
+		value = 1
+	}
+	current().SetGauge("load_shedding_active", value, nil)
+}
+
+// SetLoadSheddingBurnRate sets the error budget burn rate over a window
+func SetLoadSheddingBurnRate(window string, rate float64) {
+	current().SetGauge("load_shedding_burn_rate", rate, Labels{"window": window})
+}
+
+// RecordLoadShed records a bulk request rejected while shedding
+func RecordLoadShed(method string) {
+	current().AddCounter("load_shed_requests_total", 1, Labels{"method": method})
+}
+
+// AddAdmissionInFlight adjusts the number of admitted calls running
+func AddAdmissionInFlight(delta float64) {
+	current().AddGauge("admission_in_flight", delta, nil)
+}
+
+// SetAdmissionQueueLatency sets the shortest queue wait over the last
+// admission interval
+func SetAdmissionQueueLatency(seconds float64) {
+	current().SetGauge("admission_queue_latency_seconds", seconds, nil)
+}
+
+// RecordAdmissionRejected records a call rejected by the admission controller
+func RecordAdmissionRejected(method, reason string) {
+	current().AddCounter("admission_rejected_total", 1, Labels{"method": method, "reason": reason})
+}
+
+// RecordRobotToken records the result of an IssueRobotToken call
+func RecordRobotToken(result string) {
+	current().AddCounter("robot_tokens_issued_total", 1, Labels{"result": result})
+}
+
+// RecordTenantGateRejected records a call rejected because its tenant is
+// paused or draining
+func RecordTenantGateRejected(tenant, state string) {
+	current().AddCounter("tenant_gate_rejected_total", 1, Labels{"tenant": tenant, "state": state})
+}
+
+// RecordRateLimited records a request rejected by the rate limiter
+func RecordRateLimited(method string) {
+	current().AddCounter("rate_limited_requests_total", 1, Labels{"method": method})
+}
+
+// RecordModelReload records a model hot-reload attempt
+func RecordModelReload(ok bool) {
+	result := "success"
+	if !ok {
+		result = "error"
+	}
+	current().AddCounter("model_reloads_total", 1, Labels{"result": result})
+}
+
+// RecordInferencePanic records a panic recovered from the inference call path
+func RecordInferencePanic() {
+	current().AddCounter("inference_panics_total", 1, nil)
+}
+
+// RecordGRPCPanic records a panic recovered in a gRPC handler
+func RecordGRPCPanic(method string) {
+	current().AddCounter("grpc_panics_total", 1, Labels{"method": method})
 }
 
 // SetHealthy sets the health status to healthy
 func SetHealthy() {
-	HealthStatus.Set(1)
+	current().SetGauge("health_status", 1, nil)
 }
 
 // SetUnhealthy sets the health status to unhealthy
 func SetUnhealthy() {
-	HealthStatus.Set(0)
+	current().SetGauge("health_status", 0, nil)
 }
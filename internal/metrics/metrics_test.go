@@ -0,0 +1,96 @@
+// internal/metrics/metrics_test.go
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusBackend(t *testing.T) {
+	AddSchedulerQueueDepth("prom-test", 2)
+	AddSchedulerQueueDepth("prom-test", -1)
+
+	got := testutil.ToFloat64(SchedulerQueueDepth.WithLabelValues("prom-test"))
+	if got != 1 {
+		t.Fatalf("Expected queue depth 1, got %v", got)
+	}
+}
+
+func TestStatsDBackend(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	sd, err := NewStatsD(conn.LocalAddr().String(), "policy_service.", []string{"env:test"}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewStatsD failed: %v", err)
+	}
+
+	SetBackend(sd)
+	RecordGRPCLatency("/planner.PathPlanner/Plan", "OK", 0.25)
+	AddSchedulerQueueDepth("robots", 3)
+	AddSchedulerQueueDepth("robots", -1)
+	RecordWatchdogTrip("inference_stalled")
+	SetBackend(nil)
+
+	// Close flushes the buffered lines in a single packet
+	if err := sd.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	buf := make([]byte, maxPacketSize)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read packet: %v", err)
+	}
+
+	lines := strings.Split(string(buf[:n]), "\n")
+	want := []string{
+		"policy_service.grpc_server_handling_seconds:0.25|h|#env:test,code:OK,method:/planner.PathPlanner/Plan",
+		"policy_service.scheduler_queue_depth:3|g|#env:test,tenant:robots",
+		"policy_service.scheduler_queue_depth:2|g|#env:test,tenant:robots",
+		"policy_service.watchdog_trips_total:1|c|#env:test,reason:inference_stalled",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %q", len(want), len(lines), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	catalog, err := Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	byName := make(map[string]Metric, len(catalog))
+	for _, m := range catalog {
+		byName[m.Name] = m
+	}
+	// Listed before any sample is recorded
+	if m := byName["grpc_streams_reaped_total"]; m.Type != "counter" || len(m.Labels) != 1 || m.Labels[0] != "method" || m.Help == "" {
+		t.Errorf("Unexpected grpc_streams_reaped_total entry: %+v", m)
+	}
+	if m := byName["grpc_server_handling_seconds"]; m.Type != "histogram" || len(m.Buckets) != 12 {
+		t.Errorf("Unexpected grpc_server_handling_seconds entry: %+v", m)
+	}
+	if m, ok := byName["go_goroutines"]; !ok || m.Type != "gauge" {
+		t.Errorf("Expected the Go runtime collector's metrics, got %+v", m)
+	}
+	for i := 1; i < len(catalog); i++ {
+		if catalog[i-1].Name >= catalog[i].Name {
+			t.Fatalf("Expected the catalog sorted by name, got %s before %s", catalog[i-1].Name, catalog[i].Name)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// internal/metrics/statsd.go
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPacketSize keeps datagrams under a typical 1500-byte MTU
+const maxPacketSize = 1432
+
+// StatsD is a Backend that pushes metrics over UDP using the DogStatsD line
+// format (name:value|type|#tag:value), accepted by the Datadog agent,
+// Telegraf, and statsd_exporter. Lines are buffered and flushed when a packet
+// fills up or every flush interval.
+type StatsD struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	gauges map[string]float64
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewStatsD creates a StatsD backend sending to addr (host:port). prefix is
+// prepended to every metric name and tags (key:value) are added to every sample.
+func NewStatsD(addr, prefix string, tags []string, flushInterval time.Duration) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial statsd at %s: %w", addr, err)
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+
+	s := &StatsD{
+		conn:   conn,
+		prefix: prefix,
+		tags:   tags,
+		gauges: make(map[string]float64),
+		done:   make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop(flushInterval)
+	return s, nil
+}
+
+// Observe implements Backend
+func (s *StatsD) Observe(name string, value float64, labels Labels) {
+	s.write(name, value, "h", labels)
+}
+
+// SetGauge implements Backend
+func (s *StatsD) SetGauge(name string, value float64, labels Labels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gauges[seriesKey(name, labels)] = value
+	s.writeLocked(name, value, "g", labels)
+}
+
+// AddGauge implements Backend. DogStatsD has no relative gauges, so the
+// current value is tracked locally and sent as an absolute gauge.
+func (s *StatsD) AddGauge(name string, delta float64, labels Labels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := seriesKey(name, labels)
+	s.gauges[key] += delta
+	s.writeLocked(name, s.gauges[key], "g", labels)
+}
+
+// AddCounter implements Backend
+func (s *StatsD) AddCounter(name string, delta float64, labels Labels) {
+	s.write(name, delta, "c", labels)
+}
+
+// Close flushes buffered metrics and closes the connection
+func (s *StatsD) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushLocked()
+	return s.conn.Close()
+}
+
+func (s *StatsD) write(name string, value float64, kind string, labels Labels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeLocked(name, value, kind, labels)
+}
+
+func (s *StatsD) writeLocked(name string, value float64, kind string, labels Labels) {
+	line := s.format(name, value, kind, labels)
+	if s.buf.Len() > 0 && s.buf.Len()+1+len(line) > maxPacketSize {
+		s.flushLocked()
+	}
+	if s.buf.Len() > 0 {
+		s.buf.WriteByte('\n')
+	}
+	s.buf.WriteString(line)
+}
+
+// format renders one DogStatsD line
+func (s *StatsD) format(name string, value float64, kind string, labels Labels) string {
+	var b strings.Builder
+	b.WriteString(s.prefix)
+	b.WriteString(name)
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('|')
+	b.WriteString(kind)
+
+	tags := append([]string(nil), s.tags...)
+	for _, k := range sortedKeys(labels) {
+		tags = append(tags, k+":"+sanitizeTag(labels[k]))
+	}
+	if len(tags) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+	return b.String()
+}
+
+func (s *StatsD) flushLoop(interval time.Duration) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.flushLocked()
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *StatsD) flushLocked() {
+	if s.buf.Len() == 0 {
+		return
+	}
+	if _, err := s.conn.Write(s.buf.Bytes()); err != nil {
+		slog.Warn("Failed to send statsd metrics", "error", err)
+	}
+	s.buf.Reset()
+}
+
+// seriesKey identifies a gauge series by name and labels
+func seriesKey(name string, labels Labels) string {
+	key := name
+	for _, k := range sortedKeys(labels) {
+		key += "," + k + "=" + labels[k]
+	}
+	return key
+}
+
+func sortedKeys(labels Labels) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sanitizeTag replaces characters that delimit the DogStatsD line format
+func sanitizeTag(v string) string {
+	return strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_").Replace(v)
+}
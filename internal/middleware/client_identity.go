@@ -0,0 +1,87 @@
+// internal/middleware/client_identity.go
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// clientIdentityKey is the context key for storing the verified client identity
+type clientIdentityKey struct{}
+
+// UnaryClientIdentityInterceptor injects the identity from the caller's
+// verified mTLS client certificate into the context. Calls without one (plain
+// TLS or plaintext) carry no identity.
+func UnaryClientIdentityInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if id := extractClientIdentity(ctx); id != "" {
+			ctx = WithClientIdentity(ctx, id)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamClientIdentityInterceptor is the streaming counterpart of UnaryClientIdentityInterceptor
+func StreamClientIdentityInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if id := extractClientIdentity(ss.Context()); id != "" {
+			ss = withStreamContext(ss, WithClientIdentity(ss.Context(), id))
+		}
+		return handler(srv, ss)
+	}
+}
+
+// extractClientIdentity returns the identity of the peer's verified client
+// certificate, or "" if the peer presented none
+func extractClientIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return CertificateIdentity(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// CertificateIdentity names the holder of a client certificate: its first URI
+// SAN (e.g. a SPIFFE ID), else its subject common name, else its first DNS SAN
+func CertificateIdentity(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
+
+// WithClientIdentity returns a copy of ctx carrying the given client identity
+func WithClientIdentity(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientIdentityKey{}, id)
+}
+
+// GetClientIdentity retrieves the verified client identity from the context,
+// or "" if the caller was not authenticated with a client certificate
+func GetClientIdentity(ctx context.Context) string {
+	id, _ := ctx.Value(clientIdentityKey{}).(string)
+	return id
+}
@@ -0,0 +1,72 @@
+// internal/middleware/client_send_time.go
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// ClientSendTimeHeader is the metadata key carrying the time, in Unix
+// nanoseconds, the client sent a call
+const ClientSendTimeHeader = "x-client-send-time"
+
+// UnaryClientSendTimeInterceptor records how long calls stamped with
+// x-client-send-time took to reach the server: network, proxies, and client
+// queueing, which server-side latency does not cover. It must run first in
+// the chain so the other interceptors' work is not counted.
+func UnaryClientSendTimeInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		recordClientTransit(ctx, info.FullMethod, time.Now())
+		return handler(ctx, req)
+	}
+}
+
+// StreamClientSendTimeInterceptor is the streaming counterpart of
+// UnaryClientSendTimeInterceptor; only the stream's opening is measured
+func StreamClientSendTimeInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		recordClientTransit(ss.Context(), info.FullMethod, time.Now())
+		return handler(srv, ss)
+	}
+}
+
+// recordClientTransit records the time from the client's send time, if the
+// call carries one, until received
+func recordClientTransit(ctx context.Context, method string, received time.Time) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return
+	}
+	values := md.Get(ClientSendTimeHeader)
+	if len(values) == 0 {
+		return
+	}
+	nanos, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || nanos <= 0 {
+		metrics.RecordClientSendTimeRejected(method, "malformed")
+		return
+	}
+	transit := received.Sub(time.Unix(0, nanos))
+	if transit < 0 {
+		// The client's clock is ahead of ours
+		metrics.RecordClientSendTimeRejected(method, "future")
+		return
+	}
+	metrics.RecordClientTransit(method, transit.Seconds())
+}
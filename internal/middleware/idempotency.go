@@ -0,0 +1,25 @@
+// internal/middleware/idempotency.go
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// IdempotencyKeyHeader is the metadata key a client keeps the same across
+// retries of one call, so retries are not recorded twice
+const IdempotencyKeyHeader = "idempotency-key"
+
+// GetIdempotencyKey returns the call's idempotency key from its incoming
+// metadata, or "" if it has none
+func GetIdempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(IdempotencyKeyHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -28,19 +28,36 @@ func UnaryMetricsInterceptor() grpc.UnaryServerInterceptor {
 		// Record the duration
 		duration := time.Since(start).Seconds()
 
-		// Extract status code
-		code := "OK"
-		if err != nil {
-			if st, ok := status.FromError(err); ok {
-				code = st.Code().String()
-			} else {
-				code = "Unknown"
-			}
-		}
-
 		// Record the metric
-		metrics.RecordGRPCLatency(info.FullMethod, code, duration)
+		metrics.RecordGRPCLatency(info.FullMethod, statusCode(err), duration)
 
 		return resp, err
 	}
 }
+
+// StreamMetricsInterceptor records the duration of gRPC streaming calls, from
+// start until the handler returns, with method and status code labels
+func StreamMetricsInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		metrics.RecordGRPCLatency(info.FullMethod, statusCode(err), time.Since(start).Seconds())
+		return err
+	}
+}
+
+// statusCode extracts the gRPC status code name from err
+func statusCode(err error) string {
+	if err == nil {
+		return "OK"
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Code().String()
+	}
+	return "Unknown"
+}
@@ -3,10 +3,24 @@ package middleware
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
 )
 
 func TestUnaryRequestIDInterceptor_GeneratesID(t *testing.T) {
@@ -75,3 +89,293 @@ func TestGetRequestID_EmptyContext(t *testing.T) {
 		t.Errorf("Expected empty request ID from empty context, got %s", requestID)
 	}
 }
+
+func TestUnaryTenantInterceptor_DefaultsTenant(t *testing.T) {
+	interceptor := UnaryTenantInterceptor()
+
+	var capturedCtx context.Context
+	mockHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		capturedCtx = ctx
+		return "response", nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	if _, err := interceptor(context.Background(), nil, info, mockHandler); err != nil {
+		t.Fatalf("Interceptor failed: %v", err)
+	}
+
+	if tenantID := GetTenantID(capturedCtx); tenantID != DefaultTenant {
+		t.Errorf("Expected tenant %s, got %s", DefaultTenant, tenantID)
+	}
+}
+
+func TestUnaryTenantInterceptor_ExtractsTenant(t *testing.T) {
+	interceptor := UnaryTenantInterceptor()
+
+	var capturedCtx context.Context
+	mockHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		capturedCtx = ctx
+		return "response", nil
+	}
+
+	md := metadata.Pairs(TenantIDHeader, "acme")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	if _, err := interceptor(ctx, nil, info, mockHandler); err != nil {
+		t.Fatalf("Interceptor failed: %v", err)
+	}
+
+	if tenantID := GetTenantID(capturedCtx); tenantID != "acme" {
+		t.Errorf("Expected tenant acme, got %s", tenantID)
+	}
+}
+
+func TestUnaryPriorityInterceptor(t *testing.T) {
+	interceptor := UnaryPriorityInterceptor("/test.Service/Bulk")
+
+	var captured string
+	mockHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = GetPriority(ctx)
+		return "response", nil
+	}
+
+	tests := []struct {
+		method string
+		header string
+		want   string
+	}{
+		{"/test.Service/Method", "", PriorityInteractive},
+		{"/test.Service/Method", "bulk", PriorityBulk},
+		{"/test.Service/Method", "urgent", PriorityInteractive},
+		{"/test.Service/Bulk", "interactive", PriorityBulk},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(PriorityHeader, tt.header))
+		}
+		info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+		if _, err := interceptor(ctx, nil, info, mockHandler); err != nil {
+			t.Fatalf("Interceptor failed: %v", err)
+		}
+		if captured != tt.want {
+			t.Errorf("%s with %q: expected priority %s, got %s", tt.method, tt.header, tt.want, captured)
+		}
+	}
+}
+
+func TestUnaryClientIdentityInterceptor(t *testing.T) {
+	interceptor := UnaryClientIdentityInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	var captured string
+	mockHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = GetClientIdentity(ctx)
+		return "response", nil
+	}
+
+	spiffe, _ := url.Parse("spiffe://fleet/robot-7")
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want string
+	}{
+		{"uri", &x509.Certificate{URIs: []*url.URL{spiffe}, Subject: pkix.Name{CommonName: "robot-7"}}, "spiffe://fleet/robot-7"},
+		{"common name", &x509.Certificate{Subject: pkix.Name{CommonName: "controller-2"}}, "controller-2"},
+		{"dns", &x509.Certificate{DNSNames: []string{"robot-9.fleet.local"}}, "robot-9.fleet.local"},
+		{"no client cert", nil, ""},
+	}
+	for _, tt := range tests {
+		state := tls.ConnectionState{}
+		if tt.cert != nil {
+			state.VerifiedChains = [][]*x509.Certificate{{tt.cert}}
+		}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+		if _, err := interceptor(ctx, nil, info, mockHandler); err != nil {
+			t.Fatalf("Interceptor failed: %v", err)
+		}
+		if captured != tt.want {
+			t.Errorf("%s: expected identity %q, got %q", tt.name, tt.want, captured)
+		}
+	}
+}
+
+func TestUnaryRobotIDInterceptor(t *testing.T) {
+	interceptor := UnaryRobotIDInterceptor()
+
+	var capturedCtx context.Context
+	mockHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		capturedCtx = ctx
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/planner.PathPlanner/BatchPlan"}
+
+	req := &pb.BatchPlanRequest{Requests: []*pb.PlanRequest{{RobotId: 7}, {RobotId: 42}}}
+	if _, err := interceptor(context.Background(), req, info, mockHandler); err != nil {
+		t.Fatalf("Interceptor failed: %v", err)
+	}
+	if ids := GetRobotIDs(capturedCtx); len(ids) != 2 || ids[0] != 7 || ids[1] != 42 {
+		t.Errorf("Expected robot IDs [7 42], got %v", ids)
+	}
+
+	if _, err := interceptor(context.Background(), "other", info, mockHandler); err != nil {
+		t.Fatalf("Interceptor failed: %v", err)
+	}
+	if ids := GetRobotIDs(capturedCtx); ids != nil {
+		t.Errorf("Expected no robot IDs for other requests, got %v", ids)
+	}
+}
+
+// testStream is a grpc.ServerStream carrying only a context
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+func (s *testStream) SetHeader(metadata.MD) error { return nil }
+
+func TestStreamInterceptors_InjectRequestAndTenant(t *testing.T) {
+	md := metadata.Pairs(TenantIDHeader, "acme", RequestIDHeader, "req-1")
+	ss := &testStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream", IsServerStream: true}
+
+	var capturedCtx context.Context
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		capturedCtx = stream.Context()
+		return nil
+	}
+	withTenant := func(srv interface{}, stream grpc.ServerStream) error {
+		return StreamTenantInterceptor()(srv, stream, info, handler)
+	}
+	if err := StreamRequestIDInterceptor()(nil, ss, info, withTenant); err != nil {
+		t.Fatalf("Interceptor failed: %v", err)
+	}
+
+	if id := GetRequestID(capturedCtx); id != "req-1" {
+		t.Errorf("Expected request ID req-1, got %s", id)
+	}
+	if tenantID := GetTenantID(capturedCtx); tenantID != "acme" {
+		t.Errorf("Expected tenant acme, got %s", tenantID)
+	}
+}
+
+func TestUnaryRecoveryInterceptor(t *testing.T) {
+	interceptor := UnaryRecoveryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal after a panic, got %v", err)
+	}
+
+	resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+	if err != nil || resp != "response" {
+		t.Errorf("Expected handler result to pass through, got %v (err %v)", resp, err)
+	}
+}
+
+// recordingBackend captures metric samples for assertions
+type recordingBackend struct {
+	mu       sync.Mutex
+	observed map[string][]float64
+	counted  map[string]float64
+}
+
+func (b *recordingBackend) Observe(name string, value float64, labels metrics.Labels) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if stage := labels["stage"]; stage != "" {
+		name += "/" + stage
+	}
+	b.observed[name] = append(b.observed[name], value)
+}
+
+func (b *recordingBackend) SetGauge(name string, value float64, labels metrics.Labels) {}
+
+func (b *recordingBackend) AddGauge(name string, delta float64, labels metrics.Labels) {}
+
+func (b *recordingBackend) AddCounter(name string, delta float64, labels metrics.Labels) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counted[name+"/"+labels["reason"]] += delta
+}
+
+func TestUnaryClientSendTimeInterceptor(t *testing.T) {
+	b := &recordingBackend{observed: make(map[string][]float64), counted: make(map[string]float64)}
+	metrics.SetBackend(b)
+	defer metrics.SetBackend(nil)
+
+	interceptor := UnaryClientSendTimeInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	call := func(sent string) {
+		ctx := context.Background()
+		if sent != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ClientSendTimeHeader, sent))
+		}
+		interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	}
+
+	call(strconv.FormatInt(time.Now().Add(-50*time.Millisecond).UnixNano(), 10))
+	call("")
+	call("yesterday")
+	call(strconv.FormatInt(time.Now().Add(time.Hour).UnixNano(), 10))
+
+	transit := b.observed["grpc_client_transit_seconds"]
+	if len(transit) != 1 || transit[0] < 0.05 || transit[0] > 1 {
+		t.Fatalf("Expected one transit of about 50ms, got %v", transit)
+	}
+	if b.counted["grpc_client_send_time_rejected_total/malformed"] != 1 {
+		t.Errorf("Expected a malformed send time to be counted, got %v", b.counted)
+	}
+	if b.counted["grpc_client_send_time_rejected_total/future"] != 1 {
+		t.Errorf("Expected a send time in the future to be counted, got %v", b.counted)
+	}
+}
+
+func TestUnaryTimedInterceptor(t *testing.T) {
+	b := &recordingBackend{observed: make(map[string][]float64), counted: make(map[string]float64)}
+	metrics.SetBackend(b)
+	defer metrics.SetBackend(nil)
+
+	slow := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return handler(ctx, req)
+	}
+	chain := []grpc.UnaryServerInterceptor{
+		UnaryTimedInterceptor("slow", slow),
+		UnaryTimedInterceptor("request_id", UnaryRequestIDInterceptor()),
+		UnaryHandlerTimingInterceptor(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		time.Sleep(30 * time.Millisecond)
+		return "ok", nil
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, next := chain[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, next)
+		}
+	}
+
+	if resp, err := handler(context.Background(), nil); err != nil || resp != "ok" {
+		t.Fatalf("Expected the handler result, got %v, %v", resp, err)
+	}
+	if d := b.observed["grpc_interceptor_seconds/slow"]; len(d) != 1 || d[0] < 0.02 || d[0] > 0.045 {
+		t.Errorf("Expected about 20ms in the slow stage, excluding the handler, got %v", d)
+	}
+	if d := b.observed["grpc_interceptor_seconds/request_id"]; len(d) != 1 || d[0] > 0.01 {
+		t.Errorf("Expected a fast request_id stage, got %v", d)
+	}
+	if d := b.observed["grpc_interceptor_seconds/"+HandlerStage]; len(d) != 1 || d[0] < 0.03 {
+		t.Errorf("Expected about 30ms in the handler, got %v", d)
+	}
+}
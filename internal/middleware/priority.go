@@ -0,0 +1,87 @@
+// internal/middleware/priority.go
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// PriorityHeader is the metadata key carrying a request's traffic class
+	PriorityHeader = "x-priority"
+
+	// PriorityInteractive is robot control traffic, and the default
+	PriorityInteractive = "interactive"
+
+	// PriorityBulk is traffic that can wait or retry, such as analytics and
+	// replays; it is shed first under load
+	PriorityBulk = "bulk"
+)
+
+// priorityKey is the context key for storing the request priority
+type priorityKey struct{}
+
+// UnaryPriorityInterceptor extracts x-priority from incoming metadata and
+// injects it into the context. Calls to bulkMethods are always bulk; other
+// requests without a recognized value are interactive.
+func UnaryPriorityInterceptor(bulkMethods ...string) grpc.UnaryServerInterceptor {
+	bulk := methodSet(bulkMethods)
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(WithPriority(ctx, priorityFor(ctx, info.FullMethod, bulk)), req)
+	}
+}
+
+// StreamPriorityInterceptor is the streaming counterpart of UnaryPriorityInterceptor
+func StreamPriorityInterceptor(bulkMethods ...string) grpc.StreamServerInterceptor {
+	bulk := methodSet(bulkMethods)
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx := ss.Context()
+		return handler(srv, withStreamContext(ss, WithPriority(ctx, priorityFor(ctx, info.FullMethod, bulk))))
+	}
+}
+
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		set[m] = true
+	}
+	return set
+}
+
+// priorityFor returns the priority of a call to method from its metadata
+func priorityFor(ctx context.Context, method string, bulk map[string]bool) string {
+	if bulk[method] {
+		return PriorityBulk
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(PriorityHeader); len(values) > 0 && values[0] == PriorityBulk {
+			return PriorityBulk
+		}
+	}
+	return PriorityInteractive
+}
+
+// WithPriority returns a copy of ctx carrying the given priority
+func WithPriority(ctx context.Context, priority string) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// GetPriority retrieves the priority from the context, or PriorityInteractive if none is set
+func GetPriority(ctx context.Context) string {
+	if p, ok := ctx.Value(priorityKey{}).(string); ok && p != "" {
+		return p
+	}
+	return PriorityInteractive
+}
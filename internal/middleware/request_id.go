@@ -50,6 +50,27 @@ func UnaryRequestIDInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// StreamRequestIDInterceptor is the streaming counterpart of UnaryRequestIDInterceptor
+func StreamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		requestID := extractRequestID(ss.Context())
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		// Ignore errors as in the unary case; headers are sent with the first message
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, requestID))
+
+		ctx := context.WithValue(ss.Context(), requestIDKey{}, requestID)
+		return handler(srv, withStreamContext(ss, ctx))
+	}
+}
+
 // extractRequestID extracts the request ID from incoming metadata
 func extractRequestID(ctx context.Context) string {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -65,6 +86,11 @@ func extractRequestID(ctx context.Context) string {
 	return values[0]
 }
 
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
 // GetRequestID retrieves the request ID from the context
 func GetRequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
@@ -0,0 +1,46 @@
+// internal/middleware/robot_id.go
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// robotIDsKey is the context key for storing the robot IDs in a request
+type robotIDsKey struct{}
+
+// UnaryRobotIDInterceptor injects the robot IDs carried by Plan and BatchPlan
+// requests into the context, so interceptors further down the chain (e.g. the
+// trace sampler) can act on them before the handler runs
+func UnaryRobotIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		var robotIDs []uint64
+		switch r := req.(type) {
+		case *pb.PlanRequest:
+			robotIDs = []uint64{r.GetRobotId()}
+		case *pb.BatchPlanRequest:
+			for _, planReq := range r.GetRequests() {
+				robotIDs = append(robotIDs, planReq.GetRobotId())
+			}
+		}
+		if len(robotIDs) == 0 {
+			return handler(ctx, req)
+		}
+
+		return handler(context.WithValue(ctx, robotIDsKey{}, robotIDs), req)
+	}
+}
+
+// GetRobotIDs retrieves the robot IDs from the context, or nil if none are set
+func GetRobotIDs(ctx context.Context) []uint64 {
+	ids, _ := ctx.Value(robotIDsKey{}).([]uint64)
+	return ids
+}
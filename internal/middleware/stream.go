@@ -0,0 +1,25 @@
+// internal/middleware/stream.go
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// contextStream overrides the context of a server stream so stream interceptors
+// can pass values to the handler
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the interceptor-provided context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// withStreamContext returns ss with its context replaced by ctx
+func withStreamContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &contextStream{ServerStream: ss, ctx: ctx}
+}
@@ -0,0 +1,87 @@
+// internal/middleware/tenant.go
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TenantIDHeader is the metadata key identifying the tenant that owns a request
+	TenantIDHeader = "x-tenant-id"
+
+	// DefaultTenant is used when a request does not carry a tenant ID
+	DefaultTenant = "default"
+)
+
+// tenantIDKey is the context key for storing the tenant ID
+type tenantIDKey struct{}
+
+// UnaryTenantInterceptor extracts x-tenant-id from incoming metadata and injects it
+// into the context. Requests without the header are attributed to DefaultTenant.
+// When authentication is enabled, the auth chain replaces this tenant with the
+// caller's own and rejects calls whose header names another.
+func UnaryTenantInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		tenantID := RequestedTenantID(ctx)
+		if tenantID == "" {
+			tenantID = DefaultTenant
+		}
+
+		return handler(WithTenantID(ctx, tenantID), req)
+	}
+}
+
+// StreamTenantInterceptor is the streaming counterpart of UnaryTenantInterceptor
+func StreamTenantInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		tenantID := RequestedTenantID(ss.Context())
+		if tenantID == "" {
+			tenantID = DefaultTenant
+		}
+
+		return handler(srv, withStreamContext(ss, WithTenantID(ss.Context(), tenantID)))
+	}
+}
+
+// RequestedTenantID returns the tenant ID the caller claims in its metadata.
+// It is only trustworthy once authentication has confirmed the caller
+// belongs to it.
+func RequestedTenantID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(TenantIDHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// WithTenantID returns a copy of ctx carrying the given tenant ID
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// GetTenantID retrieves the tenant ID from the context, or DefaultTenant if none is set
+func GetTenantID(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultTenant
+}
@@ -0,0 +1,53 @@
+// internal/middleware/timing.go
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// HandlerStage is the stage UnaryHandlerTimingInterceptor records the
+// handler's time as
+const HandlerStage = "handler"
+
+// UnaryTimedInterceptor wraps interceptor to record the time spent in it as
+// stage, excluding the time spent in the rest of the chain and the handler
+func UnaryTimedInterceptor(stage string, interceptor grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		var inner time.Duration
+		resp, err := interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			innerStart := time.Now()
+			// Deferred, so a panic below still leaves its time out of this stage
+			defer func() { inner += time.Since(innerStart) }()
+			return handler(ctx, req)
+		})
+		metrics.RecordInterceptorDuration(stage, (time.Since(start) - inner).Seconds())
+		return resp, err
+	}
+}
+
+// UnaryHandlerTimingInterceptor records the time spent in the handler as
+// HandlerStage; it must be the last interceptor in the chain
+func UnaryHandlerTimingInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.RecordInterceptorDuration(HandlerStage, time.Since(start).Seconds())
+		return resp, err
+	}
+}
@@ -0,0 +1,266 @@
+// internal/modelfetch/modelfetch.go
+
+// Package modelfetch downloads models given as s3://, gs://, or https:// URLs
+// into a local cache directory, verifying their SHA-256 checksum, so model
+// updates ship as a config change rather than a new image
+package modelfetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/recorder"
+)
+
+// Results counted in model_downloads_total
+const (
+	ResultDownloaded = "downloaded"
+	ResultCached     = "cached"
+	ResultMismatch   = "checksum_mismatch"
+	ResultFailed     = "failed"
+)
+
+// IsURL reports whether model names a remote model rather than a local path
+func IsURL(model string) bool {
+	for _, scheme := range []string{"s3://", "gs://", "https://", "http://"} {
+		if strings.HasPrefix(model, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetcher downloads models into Dir. A model with a checksum is cached under
+// Dir/<sha256>/, so an unchanged model is not downloaded again and a new one
+// never overwrites a model in use; a model without one is cached under its
+// URL, which must then name an immutable artifact and not use plain http.
+type Fetcher struct {
+	Dir string
+	// S3Endpoint and S3Region locate s3:// buckets; an empty endpoint means
+	// AWS S3 in S3Region. Credentials come from the AWS_* variables.
+	S3Endpoint string
+	S3Region   string
+	// GCSEndpoint serves gs:// objects; empty means storage.googleapis.com
+	GCSEndpoint string
+	// MetadataURL serves GCS access tokens when GOOGLE_OAUTH_ACCESS_TOKEN is
+	// not set; empty means the GCE metadata server
+	MetadataURL string
+	// HTTPToken is sent as a bearer token with https:// downloads when set
+	HTTPToken string
+	Client    *http.Client
+}
+
+// New creates a Fetcher caching models in dir
+func New(dir, s3Endpoint, s3Region string) *Fetcher {
+	return &Fetcher{
+		Dir:        dir,
+		S3Endpoint: s3Endpoint,
+		S3Region:   s3Region,
+		Client:     &http.Client{Timeout: 30 * time.Minute},
+	}
+}
+
+// Fetch returns the local path of the model at rawURL, downloading it unless
+// it is already cached. With checksum set, a download whose SHA-256 differs
+// is discarded and an error returned; without one, an http:// URL is refused,
+// since nothing would vouch for the bytes. The file keeps the URL's file
+// name, so per-model settings matched by name still apply.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL, checksum string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid model URL %q: %w", rawURL, err)
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "", fmt.Errorf("model URL %q does not name a file", rawURL)
+	}
+	checksum = strings.ToLower(checksum)
+	if checksum != "" {
+		// The checksum names the cache directory, so it must not be a path
+		if sum, err := hex.DecodeString(checksum); err != nil || len(sum) != sha256.Size {
+			return "", fmt.Errorf("invalid model checksum %q: expected 64 hex characters", checksum)
+		}
+	} else if u.Scheme == "http" {
+		return "", fmt.Errorf("model URL %s uses plain http and needs a checksum", u.Redacted())
+	}
+
+	key := checksum
+	if key == "" {
+		sum := sha256.Sum256([]byte(rawURL))
+		key = "url-" + hex.EncodeToString(sum[:8])
+	}
+	dir := filepath.Join(f.Dir, key)
+	local := filepath.Join(dir, name)
+
+	if _, err := os.Stat(local); err == nil {
+		if checksum == "" {
+			metrics.RecordModelDownload(ResultCached, 0)
+			return local, nil
+		}
+		if sum, err := fileSHA256(local); err == nil && sum == checksum {
+			metrics.RecordModelDownload(ResultCached, 0)
+			return local, nil
+		}
+		slog.Warn("Cached model is corrupt; downloading it again", "path", local)
+	}
+
+	start := time.Now()
+	if err := f.download(ctx, u, dir, local, checksum); err != nil {
+		return "", err
+	}
+	metrics.RecordModelDownload(ResultDownloaded, time.Since(start).Seconds())
+	return local, nil
+}
+
+// download writes the model at u to local through a temporary file, which is
+// only renamed into place once its checksum matches
+func (f *Fetcher) download(ctx context.Context, u *url.URL, dir, local, checksum string) error {
+	body, err := f.open(ctx, u)
+	if err != nil {
+		metrics.RecordModelDownload(ResultFailed, 0)
+		return fmt.Errorf("failed to download model %s: %w", u.Redacted(), err)
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		metrics.RecordModelDownload(ResultFailed, 0)
+		return fmt.Errorf("failed to create model cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		metrics.RecordModelDownload(ResultFailed, 0)
+		return fmt.Errorf("failed to create model cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		metrics.RecordModelDownload(ResultFailed, 0)
+		return fmt.Errorf("failed to download model %s: %w", u.Redacted(), err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); checksum != "" && sum != checksum {
+		metrics.RecordModelDownload(ResultMismatch, 0)
+		return fmt.Errorf("model %s has SHA-256 %s, expected %s", u.Redacted(), sum, checksum)
+	}
+	if err := os.Rename(tmp.Name(), local); err != nil {
+		metrics.RecordModelDownload(ResultFailed, 0)
+		return fmt.Errorf("failed to move model into the cache: %w", err)
+	}
+	return nil
+}
+
+// open returns the body of the object at u
+func (f *Fetcher) open(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	switch u.Scheme {
+	case "s3":
+		store, err := recorder.NewS3Store(f.S3Endpoint, f.S3Region, u.Host)
+		if err != nil {
+			return nil, err
+		}
+		store.Client = f.Client
+		return store.Get(ctx, strings.TrimPrefix(u.Path, "/"))
+	case "gs":
+		endpoint := f.GCSEndpoint
+		if endpoint == "" {
+			endpoint = "https://storage.googleapis.com"
+		}
+		token, err := f.gcsToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return f.get(ctx, strings.TrimRight(endpoint, "/")+"/"+u.Host+u.EscapedPath(), token)
+	case "https", "http":
+		return f.get(ctx, u.String(), f.HTTPToken)
+	default:
+		return nil, fmt.Errorf("unsupported model URL scheme %q", u.Scheme)
+	}
+}
+
+// get returns the body of a GET of rawURL, with a bearer token if set
+func (f *Fetcher) get(ctx context.Context, rawURL, token string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
+
+// gcsToken returns an OAuth access token for GCS: GOOGLE_OAUTH_ACCESS_TOKEN,
+// else the workload's service account token from the metadata server, else
+// none, which only reads public objects
+func (f *Fetcher) gcsToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	metadata := f.MetadataURL
+	if metadata == "" {
+		metadata = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		// Not on GCP
+		return "", nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid metadata server token: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// fileSHA256 returns the hex SHA-256 of the file at name
+func fileSHA256(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
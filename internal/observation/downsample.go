@@ -0,0 +1,31 @@
+// internal/observation/downsample.go
+package observation
+
+// Downsample average-pools a channels x height x width observation over
+// factor x factor blocks, returning the pooled data and its new height and
+// width. Rows and columns left over when a side is not a multiple of factor
+// are dropped. The input is left untouched.
+func Downsample(data []float32, c, h, w, factor int64) ([]float32, int64, int64) {
+	if factor <= 1 || h < factor || w < factor {
+		return data, h, w
+	}
+	outH, outW := h/factor, w/factor
+	out := make([]float32, c*outH*outW)
+	scale := 1 / float32(factor*factor)
+	for ch := int64(0); ch < c; ch++ {
+		plane := data[ch*h*w : (ch+1)*h*w]
+		for y := int64(0); y < outH; y++ {
+			for x := int64(0); x < outW; x++ {
+				var sum float32
+				for dy := int64(0); dy < factor; dy++ {
+					row := plane[(y*factor+dy)*w+x*factor:]
+					for dx := int64(0); dx < factor; dx++ {
+						sum += row[dx]
+					}
+				}
+				out[(ch*outH+y)*outW+x] = sum * scale
+			}
+		}
+	}
+	return out, outH, outW
+}
@@ -0,0 +1,39 @@
+// internal/observation/downsample_test.go
+package observation
+
+import "testing"
+
+func TestDownsample_AveragesBlocks(t *testing.T) {
+	// Two channels of 2x5; the fifth column is dropped at factor 2
+	data := []float32{
+		1, 3, 10, 20, 99,
+		5, 7, 30, 40, 99,
+
+		0, 0, 4, 4, 99,
+		0, 0, 4, 4, 99,
+	}
+	out, h, w := Downsample(data, 2, 2, 5, 2)
+	if h != 1 || w != 2 {
+		t.Fatalf("Expected 1x2, got %dx%d", h, w)
+	}
+	want := []float32{4, 25, 0, 4}
+	if len(out) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, out)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, out)
+		}
+	}
+	if data[0] != 1 {
+		t.Error("Expected the input to be left untouched")
+	}
+}
+
+func TestDownsample_TooSmall(t *testing.T) {
+	data := []float32{1, 2, 3}
+	out, h, w := Downsample(data, 1, 1, 3, 2)
+	if h != 1 || w != 3 || len(out) != 3 {
+		t.Fatalf("Expected observations smaller than the factor unchanged, got %dx%d", h, w)
+	}
+}
@@ -0,0 +1,58 @@
+// Package observation provides helpers for working with robot observations
+package observation
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// HashSize is the number of SHA-256 bytes kept in a short observation hash
+const HashSize = 8
+
+// Hash returns a short, stable hex hash of an observation's shape and data.
+// It is the first HashSize bytes of SHA-256 over the big-endian channels,
+// height, and width (uint32) followed by the IEEE-754 bits of each value, so
+// robots can recompute it to confirm which frame an action was planned from.
+// For uint8 observations the uint8_data bytes take the place of the values,
+// and for encoded ones the big-endian encoding (uint32) and encoded_data.
+func Hash(obs *pb.Observation) string {
+	if obs == nil {
+		return ""
+	}
+	return hex.EncodeToString(Digest(obs)[:HashSize])
+}
+
+// Digest returns the full SHA-256 that Hash is cut from, for uses where a
+// short hash colliding would serve the wrong action
+func Digest(obs *pb.Observation) []byte {
+	h := sha256.New()
+
+	var buf [4]byte
+	for _, dim := range []uint32{obs.GetChannels(), obs.GetHeight(), obs.GetWidth()} {
+		binary.BigEndian.PutUint32(buf[:], dim)
+		h.Write(buf[:])
+	}
+
+	if raw := obs.GetUint8Data(); len(raw) > 0 {
+		h.Write(raw)
+		return h.Sum(nil)
+	}
+	if raw := obs.GetEncodedData(); len(raw) > 0 {
+		binary.BigEndian.PutUint32(buf[:], obs.GetEncoding())
+		h.Write(buf[:])
+		h.Write(raw)
+		return h.Sum(nil)
+	}
+
+	data := make([]byte, 4*len(obs.GetData()))
+	for i, v := range obs.GetData() {
+		binary.BigEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	h.Write(data)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,55 @@
+// internal/observation/hash_test.go
+package observation
+
+import (
+	"testing"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+func TestHash_StableAndShort(t *testing.T) {
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+
+	h1 := Hash(obs)
+	h2 := Hash(&pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2})
+
+	if h1 != h2 {
+		t.Errorf("Expected identical observations to hash equally: %s != %s", h1, h2)
+	}
+	if len(h1) != 2*HashSize {
+		t.Errorf("Expected %d hex chars, got %d", 2*HashSize, len(h1))
+	}
+}
+
+func TestHash_DetectsChanges(t *testing.T) {
+	base := Hash(&pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2})
+
+	changedData := Hash(&pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.5}, Channels: 1, Height: 2, Width: 2})
+	if changedData == base {
+		t.Error("Expected different data to change the hash")
+	}
+
+	// Same values reinterpreted with a different shape are a different frame
+	changedShape := Hash(&pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 1, Width: 4})
+	if changedShape == base {
+		t.Error("Expected different dimensions to change the hash")
+	}
+}
+
+func TestHash_Uint8Data(t *testing.T) {
+	raw := &pb.Observation{Uint8Data: []byte{1, 2, 3, 4}, Channels: 1, Height: 2, Width: 2}
+	// The server's float32 conversion keeps uint8_data, and hashes the same
+	converted := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Uint8Data: []byte{1, 2, 3, 4}, Channels: 1, Height: 2, Width: 2}
+	if Hash(raw) != Hash(converted) {
+		t.Error("Expected uint8 observations to hash their uint8 data")
+	}
+	if Hash(raw) == Hash(&pb.Observation{Uint8Data: []byte{1, 2, 3, 5}, Channels: 1, Height: 2, Width: 2}) {
+		t.Error("Expected different uint8 data to change the hash")
+	}
+}
+
+func TestHash_Nil(t *testing.T) {
+	if Hash(nil) != "" {
+		t.Error("Expected empty hash for nil observation")
+	}
+}
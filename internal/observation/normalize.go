@@ -0,0 +1,78 @@
+// internal/observation/normalize.go
+package observation
+
+import "fmt"
+
+// Normalization converts uint8 observation data, such as camera frames, to
+// the float32 values the model expects: (x * Scale - Mean[c]) / Std[c]. Mean
+// and Std hold one value for every channel or one per channel; empty means 0
+// and 1.
+type Normalization struct {
+	Scale float32
+	Mean  []float32
+	Std   []float32
+}
+
+// DefaultNormalization maps uint8 values to [0, 1]
+var DefaultNormalization = Normalization{Scale: 1.0 / 255}
+
+// Validate checks that Scale and every Std are positive
+func (n Normalization) Validate() error {
+	if !(n.Scale > 0) {
+		return fmt.Errorf("scale must be positive, got %g", n.Scale)
+	}
+	for i, s := range n.Std {
+		if !(s > 0) {
+			return fmt.Errorf("std[%d] must be positive, got %g", i, s)
+		}
+	}
+	return nil
+}
+
+// Float32 converts data, laid out as channels planes of equal size, to
+// normalized float32 values
+func (n Normalization) Float32(data []byte, channels int) ([]float32, error) {
+	mean, err := perChannel(n.Mean, 0, channels, "means")
+	if err != nil {
+		return nil, err
+	}
+	std, err := perChannel(n.Std, 1, channels, "stds")
+	if err != nil {
+		return nil, err
+	}
+	if channels <= 0 || len(data)%channels != 0 {
+		return nil, fmt.Errorf("%d uint8 values do not split into %d channels", len(data), channels)
+	}
+
+	// Fold the scale and normalization into one multiply-add per value
+	out := make([]float32, len(data))
+	plane := len(data) / channels
+	for c := 0; c < channels; c++ {
+		mul := n.Scale / std[c]
+		add := -mean[c] / std[c]
+		for i, x := range data[c*plane : (c+1)*plane] {
+			out[c*plane+i] = float32(x)*mul + add
+		}
+	}
+	return out, nil
+}
+
+// perChannel expands values to one per channel; empty means def
+func perChannel(values []float32, def float32, channels int, kind string) ([]float32, error) {
+	switch len(values) {
+	case channels:
+		return values, nil
+	case 0, 1:
+		v := def
+		if len(values) == 1 {
+			v = values[0]
+		}
+		out := make([]float32, channels)
+		for i := range out {
+			out[i] = v
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("uint8 normalization has %d %s but the observation has %d channels", len(values), kind, channels)
+	}
+}
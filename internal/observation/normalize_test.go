@@ -0,0 +1,54 @@
+// internal/observation/normalize_test.go
+package observation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalization_Float32(t *testing.T) {
+	got, err := DefaultNormalization.Float32([]byte{0, 51, 255, 102}, 1)
+	if err != nil {
+		t.Fatalf("Float32 failed: %v", err)
+	}
+	want := []float32{0, 0.2, 1, 0.4}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-6 {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	// Per-channel mean and std over two planes of two values
+	n := Normalization{Scale: 1, Mean: []float32{10, 100}, Std: []float32{2, 50}}
+	got, err = n.Float32([]byte{10, 14, 100, 200}, 2)
+	if err != nil {
+		t.Fatalf("Float32 failed: %v", err)
+	}
+	want = []float32{0, 2, 0, 2}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-6 {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	if _, err := n.Float32([]byte{1, 2, 3}, 3); err == nil {
+		t.Error("Expected error for a mean per channel of another layout")
+	}
+	if _, err := DefaultNormalization.Float32([]byte{1, 2, 3}, 2); err == nil {
+		t.Error("Expected error for data that does not split into channels")
+	}
+}
+
+func TestNormalization_Validate(t *testing.T) {
+	if err := DefaultNormalization.Validate(); err != nil {
+		t.Errorf("Expected default normalization to be valid, got %v", err)
+	}
+	if err := (Normalization{Scale: 1, Std: []float32{0.5, 0}}).Validate(); err == nil {
+		t.Error("Expected error for zero std")
+	}
+	if err := (Normalization{}).Validate(); err == nil {
+		t.Error("Expected error for zero scale")
+	}
+}
@@ -0,0 +1,112 @@
+// internal/observation/range.go
+package observation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Range is the span of values a model expects in its input, e.g. [0, 1] for
+// normalized images
+type Range struct {
+	Min float32
+	Max float32
+	// Tolerance is how far past Min or Max a value may fall before it counts
+	// as out of range; observations within the tolerance are left untouched
+	Tolerance float32
+	// Clamp clamps out-of-range values into [Min, Max] instead of rejecting
+	// the observation
+	Clamp bool
+}
+
+// RangeRule applies a Range to observations with a given layout. A zero
+// Channels, Height, or Width matches any size.
+type RangeRule struct {
+	Channels uint32
+	Height   uint32
+	Width    uint32
+	Range    Range
+}
+
+// matches reports whether the rule applies to a (c, h, w) observation
+func (r RangeRule) matches(c, h, w uint32) bool {
+	return (r.Channels == 0 || r.Channels == c) &&
+		(r.Height == 0 || r.Height == h) &&
+		(r.Width == 0 || r.Width == w)
+}
+
+// FindRange returns the range of the first rule matching a (c, h, w) observation
+func FindRange(rules []RangeRule, c, h, w uint32) (Range, bool) {
+	for _, rule := range rules {
+		if rule.matches(c, h, w) {
+			return rule.Range, true
+		}
+	}
+	return Range{}, false
+}
+
+// ParseLayout parses a CxHxW layout such as "3x64x64"; "*" matches any size
+// in that position and is returned as 0
+func ParseLayout(layout string) (c, h, w uint32, err error) {
+	dims := strings.Split(layout, "x")
+	if len(dims) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid layout %q: expected CxHxW", layout)
+	}
+
+	var parsed [3]uint32
+	for i, d := range dims {
+		if d == "*" {
+			continue
+		}
+		n, err := strconv.ParseUint(d, 10, 32)
+		if err != nil || n == 0 {
+			return 0, 0, 0, fmt.Errorf("invalid layout %q: dimension %q must be a positive integer or *", layout, d)
+		}
+		parsed[i] = uint32(n)
+	}
+	return parsed[0], parsed[1], parsed[2], nil
+}
+
+// Check returns the number of values further than Tolerance outside
+// [Min, Max] (NaN included) and the value furthest out of range
+func (r Range) Check(data []float32) (outliers int, worst float32) {
+	lo, hi := r.Min-r.Tolerance, r.Max+r.Tolerance
+	var worstDist float32 = -1
+	for _, v := range data {
+		var dist float32
+		switch {
+		case v < lo:
+			dist = r.Min - v
+		case v > hi:
+			dist = v - r.Max
+		case v != v:
+			dist = float32(math.Inf(1))
+		default:
+			continue
+		}
+		outliers++
+		if dist > worstDist {
+			worstDist, worst = dist, v
+		}
+	}
+	return outliers, worst
+}
+
+// Clamped returns a copy of data with every value clamped into [Min, Max];
+// NaN becomes Min
+func (r Range) Clamped(data []float32) []float32 {
+	clamped := make([]float32, len(data))
+	for i, v := range data {
+		switch {
+		case v > r.Max:
+			v = r.Max
+		case v >= r.Min:
+		default:
+			v = r.Min
+		}
+		clamped[i] = v
+	}
+	return clamped
+}
@@ -0,0 +1,60 @@
+// internal/observation/range_test.go
+package observation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRange_CheckAndClamp(t *testing.T) {
+	r := Range{Min: 0, Max: 1, Tolerance: 0.1}
+
+	if n, _ := r.Check([]float32{0, 0.5, 1, -0.05, 1.08}); n != 0 {
+		t.Errorf("Expected values within tolerance to pass, got %d outliers", n)
+	}
+
+	n, worst := r.Check([]float32{0.5, 255, -3, float32(math.NaN())})
+	if n != 3 {
+		t.Fatalf("Expected 3 outliers, got %d", n)
+	}
+	if !math.IsNaN(float64(worst)) {
+		t.Errorf("Expected NaN as the worst value, got %v", worst)
+	}
+
+	clamped := r.Clamped([]float32{0.5, 255, -3, float32(math.NaN())})
+	want := []float32{0.5, 1, 0, 0}
+	for i := range want {
+		if clamped[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, clamped)
+		}
+	}
+}
+
+func TestParseLayoutAndFindRange(t *testing.T) {
+	c, h, w, err := ParseLayout("3x*x64")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %v", err)
+	}
+	if c != 3 || h != 0 || w != 64 {
+		t.Fatalf("Expected (3,0,64), got (%d,%d,%d)", c, h, w)
+	}
+	for _, bad := range []string{"", "3x64", "3x0x64", "ax1x1"} {
+		if _, _, _, err := ParseLayout(bad); err == nil {
+			t.Errorf("Expected error for layout %q", bad)
+		}
+	}
+
+	rules := []RangeRule{
+		{Channels: 3, Width: 64, Range: Range{Max: 1}},
+		{Range: Range{Max: 255}},
+	}
+	if r, ok := FindRange(rules, 3, 32, 64); !ok || r.Max != 1 {
+		t.Errorf("Expected the image rule, got %+v", r)
+	}
+	if r, ok := FindRange(rules, 1, 1, 8); !ok || r.Max != 255 {
+		t.Errorf("Expected the catch-all rule, got %+v", r)
+	}
+	if _, ok := FindRange(nil, 1, 1, 8); ok {
+		t.Error("Expected no range without rules")
+	}
+}
@@ -0,0 +1,20 @@
+// internal/placement/http.go
+package placement
+
+import (
+	"net/http"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+)
+
+// Register adds the placement endpoint to the admin API:
+//
+//	GET /admin/placement  this replica's name and its recent reassignments
+func (t *Tracker) Register(m *admin.Mux) {
+	m.HandleFunc("GET /admin/placement", func(w http.ResponseWriter, r *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"replica":       t.replica,
+			"reassignments": t.Events(),
+		})
+	})
+}
@@ -0,0 +1,177 @@
+// Package placement tracks which replica plans each robot, so robot teams'
+// reports of action discontinuities can be correlated with a sharding load
+// balancer or sticky streams moving robots between replicas. Each replica
+// claims the robots it serves in Redis; a robot whose claim was held by
+// another replica has been reassigned, which is logged as a structured event,
+// counted, and kept in a short history.
+package placement
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Claim results counted in robot_placement_claims_total
+const (
+	ResultClaimed    = "claimed"
+	ResultReassigned = "reassigned"
+	ResultFailed     = "failed"
+	ResultDropped    = "dropped"
+)
+
+const (
+	// claimQueueSize is the most claims waiting to be written
+	claimQueueSize = 4096
+	// maxEvents is how many reassignments are kept for the admin API
+	maxEvents = 256
+)
+
+// Store swaps the owner recorded at key, returning the previous owner or ""
+// if there was none; implemented by *cache.Cache
+type Store interface {
+	SwapOwner(ctx context.Context, key, owner string, ttl time.Duration) (string, error)
+}
+
+// Event is a robot moving from one replica to another
+type Event struct {
+	Time    time.Time `json:"time"`
+	Tenant  string    `json:"tenant"`
+	RobotID uint64    `json:"robot_id"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+}
+
+// robot identifies a robot across tenants
+type robot struct {
+	tenant string
+	id     uint64
+}
+
+// Tracker claims the robots planned on this replica and reports the ones
+// reassigned to it
+type Tracker struct {
+	store   Store
+	replica string
+	idle    time.Duration
+	now     func() time.Time
+	claims  chan robot
+
+	mu sync.Mutex
+	// claimed holds when each robot was last claimed for this replica
+	claimed map[robot]time.Time
+	events  []Event
+}
+
+// New creates a Tracker claiming robots for replica in store. A robot not
+// planned for idle is released: its claim expires, and its next replica does
+// not count as a reassignment.
+func New(store Store, replica string, idle time.Duration) *Tracker {
+	return &Tracker{
+		store:   store,
+		replica: replica,
+		idle:    idle,
+		now:     time.Now,
+		claims:  make(chan robot, claimQueueSize),
+		claimed: make(map[robot]time.Time),
+	}
+}
+
+// Seen notes that robotIDs of tenant were planned on this replica. Robots not
+// claimed recently are queued for claiming without blocking; claims that do
+// not fit the queue are dropped and retried on the next plan.
+func (t *Tracker) Seen(tenant string, robotIDs []uint64) {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, id := range robotIDs {
+		r := robot{tenant: tenant, id: id}
+		// Renew claims halfway through their lifetime
+		if at, ok := t.claimed[r]; ok && now.Sub(at) < t.idle/2 {
+			continue
+		}
+		select {
+		case t.claims <- r:
+			t.claimed[r] = now
+		default:
+			metrics.RecordPlacementClaim(ResultDropped)
+		}
+	}
+}
+
+// Run writes queued claims and releases idle robots until ctx is done
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(t.idle/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-t.claims:
+			t.claim(ctx, r)
+		case <-ticker.C:
+			t.release()
+		}
+	}
+}
+
+// claim records this replica as r's owner, reporting a reassignment if
+// another replica owned it
+func (t *Tracker) claim(ctx context.Context, r robot) {
+	previous, err := t.store.SwapOwner(ctx, key(r), t.replica, t.idle)
+	if err != nil {
+		metrics.RecordPlacementClaim(ResultFailed)
+		slog.Warn("Robot placement claim failed", "tenant", r.tenant, "robot_id", r.id, "error", err)
+		// Retry on the robot's next plan
+		t.mu.Lock()
+		delete(t.claimed, r)
+		t.mu.Unlock()
+		return
+	}
+	if previous == "" || previous == t.replica {
+		metrics.RecordPlacementClaim(ResultClaimed)
+		return
+	}
+
+	metrics.RecordPlacementClaim(ResultReassigned)
+	e := Event{Time: t.now(), Tenant: r.tenant, RobotID: r.id, From: previous, To: t.replica}
+	slog.Info("Robot reassigned", "tenant", e.Tenant, "robot_id", e.RobotID, "from", e.From, "to", e.To)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.events) == maxEvents {
+		t.events = append(t.events[:0], t.events[1:]...)
+	}
+	t.events = append(t.events, e)
+}
+
+// release forgets robots not planned for the idle period and updates the
+// count of robots assigned here
+func (t *Tracker) release() {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for r, at := range t.claimed {
+		if now.Sub(at) > t.idle {
+			delete(t.claimed, r)
+		}
+	}
+	metrics.SetRobotsAssigned(len(t.claimed))
+}
+
+// Events returns the most recent reassignments to this replica, oldest first
+func (t *Tracker) Events() []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Event{}, t.events...)
+}
+
+// key is the Redis key holding r's owner
+func key(r robot) string {
+	return "robot-replica:" + r.tenant + ":" + strconv.FormatUint(r.id, 10)
+}
@@ -0,0 +1,89 @@
+// internal/placement/placement_test.go
+package placement
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memStore is an in-memory Store shared by replicas, ignoring expiry
+type memStore struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+func (s *memStore) SwapOwner(ctx context.Context, key, owner string, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.owners[key]
+	s.owners[key] = owner
+	return previous, nil
+}
+
+// drain writes the tracker's queued claims
+func drain(t *Tracker) {
+	for {
+		select {
+		case r := <-t.claims:
+			t.claim(context.Background(), r)
+		default:
+			return
+		}
+	}
+}
+
+func TestTracker_ReportsReassignments(t *testing.T) {
+	store := &memStore{owners: make(map[string]string)}
+	a := New(store, "replica-a", time.Minute)
+	b := New(store, "replica-b", time.Minute)
+
+	a.Seen("acme", []uint64{1, 2})
+	drain(a)
+	if events := a.Events(); len(events) != 0 {
+		t.Fatalf("Expected no reassignments for first claims, got %+v", events)
+	}
+
+	// Robot 2 moves to replica b
+	b.Seen("acme", []uint64{2})
+	drain(b)
+	events := b.Events()
+	if len(events) != 1 || events[0].RobotID != 2 || events[0].From != "replica-a" || events[0].To != "replica-b" {
+		t.Fatalf("Expected robot 2 reassigned from a to b, got %+v", events)
+	}
+
+	// Robots claimed recently are not claimed again
+	a.Seen("acme", []uint64{1, 2})
+	if n := len(a.claims); n != 0 {
+		t.Errorf("Expected no claims within half the idle period, got %d", n)
+	}
+	// The same robot ID of another tenant is a different robot
+	b.Seen("other", []uint64{1})
+	drain(b)
+	if events := b.Events(); len(events) != 1 {
+		t.Errorf("Expected tenants to be tracked separately, got %+v", events)
+	}
+}
+
+func TestTracker_ReleasesIdleRobots(t *testing.T) {
+	store := &memStore{owners: make(map[string]string)}
+	now := time.Now()
+	tr := New(store, "replica-a", time.Minute)
+	tr.now = func() time.Time { return now }
+
+	tr.Seen("acme", []uint64{1})
+	drain(tr)
+	now = now.Add(31 * time.Second)
+	tr.Seen("acme", []uint64{1})
+	if n := len(tr.claims); n != 1 {
+		t.Fatalf("Expected the claim renewed after half the idle period, got %d queued", n)
+	}
+	drain(tr)
+
+	now = now.Add(2 * time.Minute)
+	tr.release()
+	if n := len(tr.claimed); n != 0 {
+		t.Errorf("Expected the idle robot released, got %d claimed", n)
+	}
+}
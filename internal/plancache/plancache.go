@@ -0,0 +1,167 @@
+// Package plancache shares recently planned actions through Redis, keyed by a
+// hash of the model version and the observation, so an identical observation
+// from any robot or replica within a short TTL is answered without running
+// inference
+package plancache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// keyPrefix namespaces plan cache keys within a tenant's keys
+const keyPrefix = "plan:"
+
+// Store holds cached plans per tenant
+type Store interface {
+	// Get returns the tenant's values under keys, "" for keys not found
+	Get(ctx context.Context, tenant string, keys []string) ([]string, error)
+	// Set stores the tenant's values by key for ttl
+	Set(ctx context.Context, tenant string, values map[string]string, ttl time.Duration) error
+}
+
+// RedisStore keeps plans in Redis under each tenant's keys, encrypted when the
+// cache has a keyring
+type RedisStore struct {
+	Cache *cache.Cache
+}
+
+// Get implements Store
+func (s RedisStore) Get(ctx context.Context, tenant string, keys []string) ([]string, error) {
+	t, err := s.Cache.ForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return t.GetMany(ctx, keys)
+}
+
+// Set implements Store
+func (s RedisStore) Set(ctx context.Context, tenant string, values map[string]string, ttl time.Duration) error {
+	t, err := s.Cache.ForTenant(tenant)
+	if err != nil {
+		return err
+	}
+	return t.SetMany(ctx, values, ttl)
+}
+
+// MemoryStore keeps plans in process; it is not shared across replicas
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string]memoryValue
+}
+
+type memoryValue struct {
+	value   string
+	expires time.Time
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(ctx context.Context, tenant string, keys []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		if v, ok := s.values[tenant+"\x00"+key]; ok && now.Before(v.expires) {
+			values[i] = v.value
+		}
+	}
+	return values, nil
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(ctx context.Context, tenant string, values map[string]string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]memoryValue)
+	}
+	expires := time.Now().Add(ttl)
+	for key, value := range values {
+		s.values[tenant+"\x00"+key] = memoryValue{value: value, expires: expires}
+	}
+	return nil
+}
+
+// Cache looks up and stores plans by the version of the model that made them
+type Cache struct {
+	store   Store
+	version func() string
+	ttl     time.Duration
+}
+
+// New creates a Cache for plans kept in store for ttl. version returns the
+// content version of the model serving traffic, or "" while plans must not be
+// cached, e.g. when two models share traffic.
+func New(store Store, version func() string, ttl time.Duration) *Cache {
+	return &Cache{store: store, version: version, ttl: ttl}
+}
+
+// Version returns the version plans are cached under right now, "" if none.
+// It is read once per batch, so a model swapped in mid-batch never has the
+// previous model's plans stored under its version.
+func (c *Cache) Version() string {
+	return c.version()
+}
+
+// Key returns the cache key for obs planned by the given model version: the
+// hex SHA-256 of the version and the observation's full digest
+func Key(version string, obs *pb.Observation) string {
+	h := sha256.New()
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write(observation.Digest(obs))
+	return keyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// Lookup returns the plan cached for each observation of tenant by the given
+// model version, nil for observations not in the cache
+func (c *Cache) Lookup(ctx context.Context, tenant, version string, obs []*pb.Observation) ([]*pb.PlanResponse, error) {
+	keys := make([]string, len(obs))
+	for i, o := range obs {
+		keys[i] = Key(version, o)
+	}
+	values, err := c.store.Get(ctx, tenant, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*pb.PlanResponse, len(obs))
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		plan := &pb.PlanResponse{}
+		if err := proto.Unmarshal([]byte(value), plan); err != nil {
+			return nil, fmt.Errorf("invalid cached plan at %s: %w", keys[i], err)
+		}
+		plans[i] = plan
+	}
+	return plans, nil
+}
+
+// Store caches the plan the given model version made for each observation of
+// tenant. Only the action
+// and safety flag are kept; per-request fields such as obs_hash and the
+// signature are filled in again when the plan is served.
+func (c *Cache) Store(ctx context.Context, tenant, version string, obs []*pb.Observation, plans []*pb.PlanResponse) error {
+	values := make(map[string]string, len(obs))
+	for i, o := range obs {
+		data, err := proto.Marshal(&pb.PlanResponse{Action: plans[i].GetAction(), Safe: plans[i].GetSafe()})
+		if err != nil {
+			return fmt.Errorf("failed to encode plan: %w", err)
+		}
+		values[Key(version, o)] = string(data)
+	}
+	return c.store.Set(ctx, tenant, values, c.ttl)
+}
@@ -0,0 +1,64 @@
+// internal/plancache/plancache_test.go
+package plancache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+func TestCache_LookupAndStore(t *testing.T) {
+	ctx := context.Background()
+	store := &MemoryStore{}
+	c := New(store, func() string { return "v1" }, time.Minute)
+
+	obs := []*pb.Observation{
+		{Data: []float32{0.1, 0.2}, Channels: 1, Height: 1, Width: 2},
+		{Data: []float32{0.3, 0.4}, Channels: 1, Height: 1, Width: 2},
+	}
+	plans, err := c.Lookup(ctx, "acme", "v1", obs)
+	if err != nil || plans[0] != nil || plans[1] != nil {
+		t.Fatalf("Expected misses on an empty cache, got %v (err %v)", plans, err)
+	}
+
+	if err := c.Store(ctx, "acme", "v1", obs[:1], []*pb.PlanResponse{{Action: []float32{0.5, -0.5}, Safe: true, ObsHash: "abc"}}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	plans, err = c.Lookup(ctx, "acme", "v1", obs)
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if plans[0] == nil || len(plans[0].Action) != 2 || plans[0].Action[1] != -0.5 || !plans[0].Safe {
+		t.Fatalf("Expected the stored plan for the first observation, got %v", plans[0])
+	}
+	if plans[0].ObsHash != "" {
+		t.Errorf("Expected per-request fields not to be cached, got obs_hash %q", plans[0].ObsHash)
+	}
+	if plans[1] != nil {
+		t.Errorf("Expected a miss for the second observation, got %v", plans[1])
+	}
+
+	// Other tenants and model versions do not share plans
+	if plans, _ := c.Lookup(ctx, "globex", "v1", obs[:1]); plans[0] != nil {
+		t.Errorf("Expected a miss for another tenant, got %v", plans[0])
+	}
+	if plans, _ := c.Lookup(ctx, "acme", "v2", obs[:1]); plans[0] != nil {
+		t.Errorf("Expected a miss for another model version, got %v", plans[0])
+	}
+}
+
+func TestKey(t *testing.T) {
+	obs := &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}
+
+	if Key("v1", obs) != Key("v1", &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 2, Width: 2}) {
+		t.Error("Expected identical observations to share a key")
+	}
+	if Key("v1", obs) == Key("v1", &pb.Observation{Data: []float32{0.1, 0.2, 0.3, 0.4}, Channels: 1, Height: 1, Width: 4}) {
+		t.Error("Expected a different shape to change the key")
+	}
+	if Key("v1", obs) == Key("v2", obs) {
+		t.Error("Expected a different model version to change the key")
+	}
+}
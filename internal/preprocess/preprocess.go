@@ -0,0 +1,75 @@
+// internal/preprocess/preprocess.go
+
+// Package preprocess transforms observations into the input a model was
+// trained on (cropping, resizing, reordering channels, normalizing) on the
+// server, so clients do not each reimplement a model's preprocessing
+package preprocess
+
+import "fmt"
+
+// Shape is the C,H,W layout of an observation
+type Shape struct {
+	C, H, W int
+}
+
+func (s Shape) String() string {
+	return fmt.Sprintf("%dx%dx%d", s.C, s.H, s.W)
+}
+
+// Step is one transform of a pipeline
+type Step interface {
+	// Name identifies the step in errors
+	Name() string
+	// Shape returns the shape the step turns an observation of shape in
+	// into, or an error if the step cannot apply to it
+	Shape(in Shape) (Shape, error)
+	// Apply transforms data of shape in into a new slice, leaving data
+	// untouched. Shape must have accepted in.
+	Apply(data []float32, in Shape) []float32
+}
+
+// Pipeline applies its steps in order
+type Pipeline []Step
+
+// Shape returns the shape the pipeline turns an observation of shape in into
+func (p Pipeline) Shape(in Shape) (Shape, error) {
+	for i, step := range p {
+		out, err := step.Shape(in)
+		if err != nil {
+			return Shape{}, fmt.Errorf("preprocess step %d (%s) on %s observation: %w", i, step.Name(), in, err)
+		}
+		in = out
+	}
+	return in, nil
+}
+
+// Apply runs the pipeline on data of shape in. Shape must have accepted in.
+func (p Pipeline) Apply(data []float32, in Shape) []float32 {
+	for _, step := range p {
+		out, _ := step.Shape(in)
+		data = step.Apply(data, in)
+		in = out
+	}
+	return data
+}
+
+// Rule applies a Pipeline to observations with a given layout. A zero
+// Channels, Height, or Width matches any size.
+type Rule struct {
+	Channels uint32
+	Height   uint32
+	Width    uint32
+	Pipeline Pipeline
+}
+
+// Find returns the pipeline of the first rule matching a (c, h, w) observation
+func Find(rules []Rule, c, h, w uint32) (Pipeline, bool) {
+	for _, r := range rules {
+		if (r.Channels == 0 || r.Channels == c) &&
+			(r.Height == 0 || r.Height == h) &&
+			(r.Width == 0 || r.Width == w) {
+			return r.Pipeline, true
+		}
+	}
+	return nil, false
+}
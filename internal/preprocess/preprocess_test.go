@@ -0,0 +1,130 @@
+// internal/preprocess/preprocess_test.go
+package preprocess
+
+import (
+	"slices"
+	"testing"
+)
+
+// image is a 2x2x3 observation: channel c holds 10*c + its row-major index
+var image = []float32{
+	0, 1, 2,
+	3, 4, 5,
+
+	10, 11, 12,
+	13, 14, 15,
+}
+
+func TestCrop(t *testing.T) {
+	in := Shape{C: 2, H: 2, W: 3}
+	crop := Crop{Y: 0, X: 1, Height: 2, Width: 2}
+	out, err := crop.Shape(in)
+	if err != nil {
+		t.Fatalf("Shape failed: %v", err)
+	}
+	if out != (Shape{C: 2, H: 2, W: 2}) {
+		t.Errorf("Expected 2x2x2, got %s", out)
+	}
+	if got, want := crop.Apply(image, in), []float32{1, 2, 4, 5, 11, 12, 14, 15}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	center := Crop{Height: 1, Width: 1, Center: true}
+	if got, want := center.Apply(image, in), []float32{1, 11}; !slices.Equal(got, want) {
+		t.Errorf("Expected centre crop %v, got %v", want, got)
+	}
+
+	if _, err := (Crop{Y: 1, Height: 2, Width: 2}).Shape(in); err == nil {
+		t.Error("Expected error for a window past the observation")
+	}
+}
+
+func TestChannelOrder(t *testing.T) {
+	in := Shape{C: 2, H: 2, W: 3}
+	order := ChannelOrder{Order: []int{1, 0}}
+	want := []float32{10, 11, 12, 13, 14, 15, 0, 1, 2, 3, 4, 5}
+	if got := order.Apply(image, in); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if _, err := (ChannelOrder{Order: []int{2}}).Shape(in); err == nil {
+		t.Error("Expected error for a missing channel")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	in := Shape{C: 2, H: 2, W: 3}
+	norm := Normalize{Mean: []float32{0, 10}, Std: []float32{1, 0.5}}
+	want := []float32{0, 1, 2, 3, 4, 5, 0, 2, 4, 6, 8, 10}
+	if got := norm.Apply(image, in); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if _, err := (Normalize{Mean: []float32{0, 0, 0}}).Shape(in); err == nil {
+		t.Error("Expected error for a mean per channel of another layout")
+	}
+}
+
+func TestResize(t *testing.T) {
+	in := Shape{C: 1, H: 2, W: 2}
+	data := []float32{0, 1, 2, 3}
+
+	// Upsampling interpolates between neighbours
+	up := Resize{Height: 4, Width: 4}
+	got := up.Apply(data, in)
+	want := []float32{
+		0, 0.25, 0.75, 1,
+		0.5, 0.75, 1.25, 1.5,
+		1.5, 1.75, 2.25, 2.5,
+		2, 2.25, 2.75, 3,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Resizing to the same size leaves the data unchanged
+	if got := (Resize{Height: 2, Width: 2}).Apply(data, in); !slices.Equal(got, data) {
+		t.Errorf("Expected %v, got %v", data, got)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	p := Pipeline{
+		Crop{X: 1, Height: 2, Width: 2},
+		ChannelOrder{Order: []int{1}},
+		Normalize{Mean: []float32{10}},
+	}
+	in := Shape{C: 2, H: 2, W: 3}
+	out, err := p.Shape(in)
+	if err != nil {
+		t.Fatalf("Shape failed: %v", err)
+	}
+	if out != (Shape{C: 1, H: 2, W: 2}) {
+		t.Errorf("Expected 1x2x2, got %s", out)
+	}
+	original := slices.Clone(image)
+	if got, want := p.Apply(image, in), []float32{1, 2, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if !slices.Equal(image, original) {
+		t.Error("Expected the input data to be left untouched")
+	}
+
+	if _, err := (Pipeline{ChannelOrder{Order: []int{0}}, Crop{Height: 3, Width: 1}}).Shape(in); err == nil {
+		t.Error("Expected error from a later step")
+	}
+}
+
+func TestFind(t *testing.T) {
+	rules := []Rule{
+		{Channels: 3, Pipeline: Pipeline{ChannelOrder{Order: []int{2, 1, 0}}}},
+		{Pipeline: Pipeline{Normalize{}}},
+	}
+	if p, ok := Find(rules, 3, 64, 64); !ok || p[0].Name() != "channel_order" {
+		t.Errorf("Expected the 3-channel pipeline, got %v", p)
+	}
+	if p, ok := Find(rules, 1, 1, 8); !ok || p[0].Name() != "normalize" {
+		t.Errorf("Expected the catch-all pipeline, got %v", p)
+	}
+	if _, ok := Find(nil, 1, 1, 8); ok {
+		t.Error("Expected no pipeline without rules")
+	}
+}
@@ -0,0 +1,188 @@
+// internal/preprocess/steps.go
+package preprocess
+
+import (
+	"fmt"
+	"math"
+)
+
+// Normalize maps each value to (x - Mean[c]) / Std[c]. Mean and Std hold
+// one value for every channel or one per channel; empty means 0 and 1.
+type Normalize struct {
+	Mean []float32
+	Std  []float32
+}
+
+// Name implements Step
+func (Normalize) Name() string { return "normalize" }
+
+// Shape implements Step
+func (n Normalize) Shape(in Shape) (Shape, error) {
+	for _, v := range [][]float32{n.Mean, n.Std} {
+		if len(v) > 1 && len(v) != in.C {
+			return Shape{}, fmt.Errorf("has %d values per channel for %d channels", len(v), in.C)
+		}
+	}
+	for _, std := range n.Std {
+		if !(std > 0) {
+			return Shape{}, fmt.Errorf("std %g must be positive", std)
+		}
+	}
+	return in, nil
+}
+
+// Apply implements Step
+func (n Normalize) Apply(data []float32, in Shape) []float32 {
+	out := make([]float32, len(data))
+	plane := in.H * in.W
+	for c := 0; c < in.C; c++ {
+		mean, std := channelValue(n.Mean, c, 0), channelValue(n.Std, c, 1)
+		for i, x := range data[c*plane : (c+1)*plane] {
+			out[c*plane+i] = (x - mean) / std
+		}
+	}
+	return out
+}
+
+// channelValue returns channel c's value of a per-channel or shared setting
+func channelValue(values []float32, c int, def float32) float32 {
+	switch len(values) {
+	case 0:
+		return def
+	case 1:
+		return values[0]
+	default:
+		return values[c]
+	}
+}
+
+// Resize scales each channel to Height x Width with bilinear interpolation
+type Resize struct {
+	Height int
+	Width  int
+}
+
+// Name implements Step
+func (Resize) Name() string { return "resize" }
+
+// Shape implements Step
+func (r Resize) Shape(in Shape) (Shape, error) {
+	if r.Height <= 0 || r.Width <= 0 {
+		return Shape{}, fmt.Errorf("target size %dx%d must be positive", r.Height, r.Width)
+	}
+	return Shape{C: in.C, H: r.Height, W: r.Width}, nil
+}
+
+// Apply implements Step
+func (r Resize) Apply(data []float32, in Shape) []float32 {
+	out := make([]float32, in.C*r.Height*r.Width)
+	// Sample at pixel centres, as align_corners=false resizes do
+	sy := float64(in.H) / float64(r.Height)
+	sx := float64(in.W) / float64(r.Width)
+	for c := 0; c < in.C; c++ {
+		src := data[c*in.H*in.W : (c+1)*in.H*in.W]
+		dst := out[c*r.Height*r.Width : (c+1)*r.Height*r.Width]
+		for y := 0; y < r.Height; y++ {
+			y0, y1, fy := sample(y, sy, in.H)
+			for x := 0; x < r.Width; x++ {
+				x0, x1, fx := sample(x, sx, in.W)
+				top := src[y0*in.W+x0]*(1-fx) + src[y0*in.W+x1]*fx
+				bottom := src[y1*in.W+x0]*(1-fx) + src[y1*in.W+x1]*fx
+				dst[y*r.Width+x] = top*(1-fy) + bottom*fy
+			}
+		}
+	}
+	return out
+}
+
+// sample returns the two source indices around output index i and the
+// weight of the second
+func sample(i int, scale float64, size int) (int, int, float32) {
+	pos := math.Max((float64(i)+0.5)*scale-0.5, 0)
+	i0 := min(int(pos), size-1)
+	i1 := min(i0+1, size-1)
+	return i0, i1, float32(pos - float64(i0))
+}
+
+// ChannelOrder picks and reorders channels: output channel i is input
+// channel Order[i], e.g. [2, 1, 0] turns BGR into RGB
+type ChannelOrder struct {
+	Order []int
+}
+
+// Name implements Step
+func (ChannelOrder) Name() string { return "channel_order" }
+
+// Shape implements Step
+func (o ChannelOrder) Shape(in Shape) (Shape, error) {
+	if len(o.Order) == 0 {
+		return Shape{}, fmt.Errorf("order is empty")
+	}
+	for _, c := range o.Order {
+		if c < 0 || c >= in.C {
+			return Shape{}, fmt.Errorf("channel %d does not exist", c)
+		}
+	}
+	return Shape{C: len(o.Order), H: in.H, W: in.W}, nil
+}
+
+// Apply implements Step
+func (o ChannelOrder) Apply(data []float32, in Shape) []float32 {
+	plane := in.H * in.W
+	out := make([]float32, len(o.Order)*plane)
+	for i, c := range o.Order {
+		copy(out[i*plane:(i+1)*plane], data[c*plane:(c+1)*plane])
+	}
+	return out
+}
+
+// Crop keeps the Height x Width window at (Y, X), or at the centre with Center
+type Crop struct {
+	Y, X          int
+	Height, Width int
+	Center        bool
+}
+
+// Name implements Step
+func (Crop) Name() string { return "crop" }
+
+// origin returns the top-left corner of the window in an observation of shape in
+func (cr Crop) origin(in Shape) (int, int) {
+	if cr.Center {
+		return (in.H - cr.Height) / 2, (in.W - cr.Width) / 2
+	}
+	return cr.Y, cr.X
+}
+
+// Shape implements Step
+func (cr Crop) Shape(in Shape) (Shape, error) {
+	if cr.Height <= 0 || cr.Width <= 0 {
+		return Shape{}, fmt.Errorf("window %dx%d must be positive", cr.Height, cr.Width)
+	}
+	y, x := cr.origin(in)
+	if y < 0 || x < 0 || y+cr.Height > in.H || x+cr.Width > in.W {
+		return Shape{}, fmt.Errorf("window %dx%d at (%d, %d) does not fit a %dx%d observation", cr.Height, cr.Width, y, x, in.H, in.W)
+	}
+	return Shape{C: in.C, H: cr.Height, W: cr.Width}, nil
+}
+
+// Apply implements Step
+func (cr Crop) Apply(data []float32, in Shape) []float32 {
+	y0, x0 := cr.origin(in)
+	out := make([]float32, 0, in.C*cr.Height*cr.Width)
+	for c := 0; c < in.C; c++ {
+		for y := y0; y < y0+cr.Height; y++ {
+			row := (c*in.H + y) * in.W
+			out = append(out, data[row+x0:row+x0+cr.Width]...)
+		}
+	}
+	return out
+}
+
+// Ensure the steps implement Step at compile time
+var (
+	_ Step = Normalize{}
+	_ Step = Resize{}
+	_ Step = ChannelOrder{}
+	_ Step = Crop{}
+)
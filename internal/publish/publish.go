@@ -0,0 +1,130 @@
+// Package publish emits each planned action to Redis so telemetry and
+// visualization services can follow the fleet without calling the gRPC API.
+// Messages are buffered in memory and sent in the background, so publishing
+// never slows planning.
+package publish
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Defaults for New
+const (
+	DefaultBufferSize = 4096
+	maxBatch          = 256
+	sendTimeout       = 5 * time.Second
+)
+
+// Message is one planned action
+type Message struct {
+	RobotID           uint64    `json:"robot_id"`
+	Tenant            string    `json:"tenant"`
+	Action            []float32 `json:"action"`
+	Safe              bool      `json:"safe"`
+	ObsHash           string    `json:"obs_hash,omitempty"`
+	RequestID         string    `json:"request_id,omitempty"`
+	TimestampUnixNano int64     `json:"timestamp_unix_nano"`
+}
+
+// Sink delivers published messages
+type Sink interface {
+	// Send delivers a batch of messages, in order
+	Send(ctx context.Context, messages []Message) error
+}
+
+// Publisher queues messages and sends them to a sink in batches
+type Publisher struct {
+	sink     Sink
+	messages chan Message
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// New creates a Publisher sending messages to sink, holding up to bufferSize
+// messages not yet sent; more are dropped
+func New(sink Sink, bufferSize int) *Publisher {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	p := &Publisher{
+		sink:     sink,
+		messages: make(chan Message, bufferSize),
+	}
+
+	p.wg.Add(1)
+	go p.sendLoop()
+	return p
+}
+
+// Publish queues a message without blocking. The message is dropped if the
+// buffer is full or the publisher is closed.
+func (p *Publisher) Publish(m Message) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.messages <- m:
+	default:
+		metrics.RecordPublishedPlans("dropped", 1)
+	}
+}
+
+// Close sends the messages still queued and stops the publisher
+func (p *Publisher) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.messages)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// sendLoop sends queued messages in batches until the publisher is closed
+func (p *Publisher) sendLoop() {
+	defer p.wg.Done()
+
+	batch := make([]Message, 0, maxBatch)
+	for m := range p.messages {
+		batch = append(batch[:0], m)
+		// Take whatever else is already queued, up to a batch
+	fill:
+		for len(batch) < maxBatch {
+			select {
+			case m, ok := <-p.messages:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, m)
+			default:
+				break fill
+			}
+		}
+		p.send(batch)
+	}
+}
+
+// send delivers a batch to the sink; failed batches are counted and logged
+func (p *Publisher) send(batch []Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	if err := p.sink.Send(ctx, batch); err != nil {
+		slog.Warn("Failed to publish plans", "count", len(batch), "error", err)
+		metrics.RecordPublishedPlans("failed", len(batch))
+		return
+	}
+	metrics.RecordPublishedPlans("published", len(batch))
+}
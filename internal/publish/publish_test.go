@@ -0,0 +1,52 @@
+// internal/publish/publish_test.go
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+type memoryRedis struct {
+	channels map[string][]string
+	streams  map[string][]string
+}
+
+func (r *memoryRedis) Publish(_ context.Context, channel string, messages ...string) error {
+	r.channels[channel] = append(r.channels[channel], messages...)
+	return nil
+}
+
+func (r *memoryRedis) AppendStream(_ context.Context, stream string, _ int64, data ...string) error {
+	r.streams[stream] = append(r.streams[stream], data...)
+	return nil
+}
+
+func TestPublisher_SendsOnClose(t *testing.T) {
+	for _, mode := range []string{ModeChannel, ModeStream} {
+		redis := &memoryRedis{channels: make(map[string][]string), streams: make(map[string][]string)}
+		p := New(RedisSink{Cache: redis, Mode: mode, Target: DefaultTarget}, 16)
+		for i := 0; i < 10; i++ {
+			p.Publish(Message{RobotID: uint64(i), Tenant: "acme", Action: []float32{float32(i)}, TimestampUnixNano: int64(i)})
+		}
+		p.Close()
+		p.Publish(Message{RobotID: 99}) // Dropped once closed
+
+		sent, other := redis.channels[DefaultTarget], redis.streams
+		if mode == ModeStream {
+			sent, other = redis.streams[DefaultTarget], redis.channels
+		}
+		if len(sent) != 10 || len(other) != 0 {
+			t.Fatalf("Expected 10 messages sent to the %s only, got %d", mode, len(sent))
+		}
+		for i, data := range sent {
+			var m Message
+			if err := json.Unmarshal([]byte(data), &m); err != nil {
+				t.Fatalf("Invalid message %q: %v", data, err)
+			}
+			if m.RobotID != uint64(i) || m.Tenant != "acme" || len(m.Action) != 1 || m.TimestampUnixNano != int64(i) {
+				t.Fatalf("Expected message %d in order, got %+v", i, m)
+			}
+		}
+	}
+}
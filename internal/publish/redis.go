@@ -0,0 +1,52 @@
+// internal/publish/redis.go
+package publish
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Ways messages are delivered through Redis
+const (
+	// ModeChannel publishes to a pub/sub channel; only connected subscribers
+	// receive messages
+	ModeChannel = "channel"
+	// ModeStream appends to a stream, so consumers can catch up after a restart
+	ModeStream = "stream"
+)
+
+// DefaultTarget is the Redis channel or stream plans are published to
+const DefaultTarget = "policy-service:plans"
+
+// Redis is the part of *cache.Cache the Redis sinks use
+type Redis interface {
+	Publish(ctx context.Context, channel string, messages ...string) error
+	AppendStream(ctx context.Context, stream string, maxLen int64, data ...string) error
+}
+
+// RedisSink sends messages as JSON to a Redis channel or stream
+type RedisSink struct {
+	Cache Redis
+	// Mode is ModeChannel or ModeStream
+	Mode   string
+	Target string
+	// MaxLen caps a stream at roughly this many messages, dropping the oldest;
+	// 0 means no cap
+	MaxLen int64
+}
+
+// Send implements Sink
+func (s RedisSink) Send(ctx context.Context, messages []Message) error {
+	data := make([]string, len(messages))
+	for i, m := range messages {
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		data[i] = string(b)
+	}
+	if s.Mode == ModeStream {
+		return s.Cache.AppendStream(ctx, s.Target, s.MaxLen, data...)
+	}
+	return s.Cache.Publish(ctx, s.Target, data...)
+}
@@ -0,0 +1,75 @@
+// internal/ratelimit/grpc.go
+package ratelimit
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// errLimited is returned to calls over their quota; clients should back off
+var errLimited = status.Error(codes.ResourceExhausted, "rate limit exceeded")
+
+// clientKey identifies the caller: its authenticated subject, which callers
+// cannot choose, so robots behind a shared NAT get separate quotas and one
+// client cannot spread its calls over made-up credentials; else its peer IP
+// address
+func clientKey(ctx context.Context) string {
+	if id, ok := auth.FromContext(ctx); ok && id.Subject != "" {
+		return "sub:" + id.Subject
+	}
+	return peerKey(ctx)
+}
+
+// peerKey identifies the caller by its peer IP address
+func peerKey(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return "addr:" + host
+		}
+		return "addr:" + addr
+	}
+	return "unknown"
+}
+
+// UnaryInterceptor rejects calls over their client's quota with
+// ResourceExhausted
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !l.Allow(info.FullMethod, l.key(ctx)) {
+			metrics.RecordRateLimited(info.FullMethod)
+			return nil, errLimited
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is the streaming counterpart of UnaryInterceptor; a
+// stream takes one token when it opens
+func (l *Limiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if !l.Allow(info.FullMethod, l.key(ss.Context())) {
+			metrics.RecordRateLimited(info.FullMethod)
+			return errLimited
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,123 @@
+// Package ratelimit enforces per-client request quotas with token buckets, so
+// one misbehaving client cannot take the capacity other clients rely on.
+//
+// Each client gets a bucket per method holding up to Burst tokens, refilled
+// at RPS tokens per second; a call takes one token or is rejected. Clients are
+// identified by their authenticated subject, else by their peer address.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets of idle clients are dropped
+const sweepInterval = time.Minute
+
+// Limit is a token bucket's refill rate and size; an RPS of zero or less
+// means unlimited
+type Limit struct {
+	RPS   float64
+	Burst int
+}
+
+// bucket is one client's tokens for one method
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// bucketKey identifies a bucket
+type bucketKey struct {
+	method string
+	client string
+}
+
+// Limiter tracks token buckets per client and method
+type Limiter struct {
+	defaultLimit Limit
+	methods      map[string]Limit
+	key          func(ctx context.Context) string
+	now          func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+// New creates a Limiter applying defaultLimit to every method without its own
+// entry in methods, which is keyed by full gRPC method name. Clients are
+// identified by their authenticated subject, so its interceptors must run
+// after authentication.
+func New(defaultLimit Limit, methods map[string]Limit) *Limiter {
+	return &Limiter{
+		defaultLimit: defaultLimit,
+		methods:      methods,
+		key:          clientKey,
+		now:          time.Now,
+		buckets:      make(map[bucketKey]*bucket),
+	}
+}
+
+// NewPerPeer creates a Limiter like New that identifies clients by their peer
+// address alone, for a coarse limit ahead of authentication
+func NewPerPeer(defaultLimit Limit, methods map[string]Limit) *Limiter {
+	l := New(defaultLimit, methods)
+	l.key = peerKey
+	return l
+}
+
+// limitFor returns the limit for method
+func (l *Limiter) limitFor(method string) Limit {
+	if limit, ok := l.methods[method]; ok {
+		return limit
+	}
+	return l.defaultLimit
+}
+
+// Allow takes a token from client's bucket for method, reporting whether the
+// call is within its quota
+func (l *Limiter) Allow(method, client string) bool {
+	limit := l.limitFor(method)
+	if limit.RPS <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweepLocked(now)
+	}
+
+	key := bucketKey{method: method, client: client}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * limit.RPS
+	if b.tokens > float64(limit.Burst) {
+		b.tokens = float64(limit.Burst)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweepLocked drops buckets that have refilled completely, since a new bucket
+// would start out the same
+func (l *Limiter) sweepLocked(now time.Time) {
+	for key, b := range l.buckets {
+		limit := l.limitFor(key.method)
+		if b.tokens+now.Sub(b.last).Seconds()*limit.RPS >= float64(limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
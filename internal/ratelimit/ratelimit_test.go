@@ -0,0 +1,116 @@
+// internal/ratelimit/ratelimit_test.go
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+)
+
+const planMethod = "/planner.PathPlanner/Plan"
+
+func TestLimiter_BurstAndRefill(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := New(Limit{RPS: 2, Burst: 3}, map[string]Limit{
+		"/planner.PathPlanner/GetModelInfo": {},
+	})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow(planMethod, "robot-7") {
+			t.Fatalf("Expected call %d within the burst to be allowed", i)
+		}
+	}
+	if l.Allow(planMethod, "robot-7") {
+		t.Fatal("Expected call beyond the burst to be rejected")
+	}
+	if !l.Allow(planMethod, "robot-8") {
+		t.Fatal("Expected another client to have its own bucket")
+	}
+	if !l.Allow("/planner.PathPlanner/GetModelInfo", "robot-7") {
+		t.Fatal("Expected unlimited method to be allowed")
+	}
+
+	// Half a second refills one token at 2 RPS
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow(planMethod, "robot-7") {
+		t.Fatal("Expected a refilled token to be allowed")
+	}
+	if l.Allow(planMethod, "robot-7") {
+		t.Fatal("Expected only one token after half a second")
+	}
+
+	// Refilled buckets are dropped by the next sweep
+	now = now.Add(sweepInterval)
+	l.Allow(planMethod, "robot-9")
+	if len(l.buckets) != 1 {
+		t.Fatalf("Expected idle buckets to be swept, got %d buckets", len(l.buckets))
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 41000}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	if key := clientKey(ctx); key != "addr:10.0.0.7" {
+		t.Fatalf("Expected peer IP key, got %q", key)
+	}
+
+	// Unverified credentials do not buy a fresh bucket
+	withKey := metadata.NewIncomingContext(ctx, metadata.Pairs(auth.APIKeyHeader, "made-up"))
+	if key := clientKey(withKey); key != "addr:10.0.0.7" {
+		t.Fatalf("Expected an unauthenticated API key to be ignored, got %q", key)
+	}
+
+	robot := auth.WithIdentity(ctx, auth.Identity{Subject: "robot/7"})
+	if key := clientKey(robot); key != "sub:robot/7" {
+		t.Fatalf("Expected the authenticated subject, got %q", key)
+	}
+	if key := peerKey(robot); key != "addr:10.0.0.7" {
+		t.Fatalf("Expected the per-peer key to ignore the subject, got %q", key)
+	}
+}
+
+func TestUnaryInterceptor_RejectsOverQuota(t *testing.T) {
+	interceptor := New(Limit{RPS: 1, Burst: 1}, nil).UnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: planMethod}
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "robot/7"})
+
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("Expected first call to pass, got %v", err)
+	}
+	if _, err := interceptor(ctx, nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got %v", err)
+	}
+	other := auth.WithIdentity(context.Background(), auth.Identity{Subject: "robot/8"})
+	if _, err := interceptor(other, nil, info, handler); err != nil {
+		t.Fatalf("Expected another subject to have its own quota, got %v", err)
+	}
+}
+
+func TestNewPerPeer_IgnoresSubject(t *testing.T) {
+	interceptor := NewPerPeer(Limit{RPS: 1, Burst: 1}, nil).UnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: planMethod}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 41000}})
+
+	if _, err := interceptor(auth.WithIdentity(ctx, auth.Identity{Subject: "robot/7"}), nil, info, handler); err != nil {
+		t.Fatalf("Expected first call to pass, got %v", err)
+	}
+	if _, err := interceptor(auth.WithIdentity(ctx, auth.Identity{Subject: "robot/8"}), nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted for the same peer, got %v", err)
+	}
+}
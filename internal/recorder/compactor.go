@@ -0,0 +1,207 @@
+// internal/recorder/compactor.go
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Defaults for NewCompactor
+const (
+	DefaultCompactionInterval = time.Hour
+	DefaultPrefix             = "episodes"
+	compactorGroup            = "compactor"
+	// compactorConsumer is shared by every leader, so entries a failed leader
+	// left unacknowledged are retried by the next
+	compactorConsumer = "leader"
+	compactorPage     = 1000
+	// maxObjectEntries bounds the entries held in memory for one object
+	maxObjectEntries = 50000
+)
+
+// StreamGroupReader reads a Redis stream through a consumer group and elects a
+// leader; implemented by *cache.Cache
+type StreamGroupReader interface {
+	ReadGroup(ctx context.Context, stream, group, consumer, start string, count int64) ([]cache.StreamEntry, error)
+	AckDelete(ctx context.Context, stream, group string, ids ...string) error
+	AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+}
+
+// ObjectStore stores compacted batches, e.g. in S3
+type ObjectStore interface {
+	Put(ctx context.Context, key string, body []byte) error
+}
+
+// Compactor periodically moves recorded entries from a Redis stream to object
+// storage as gzipped JSON lines, one object per hour of entries. Every replica
+// may run one; a Redis lock makes only one of them compact at a time.
+type Compactor struct {
+	stream   StreamGroupReader
+	store    ObjectStore
+	key      string
+	prefix   string
+	interval time.Duration
+	lockKey  string
+	owner    string
+}
+
+// NewCompactor creates a Compactor moving entries of the stream at key to
+// store under prefix every interval
+func NewCompactor(stream StreamGroupReader, key string, store ObjectStore, prefix string, interval time.Duration) *Compactor {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if interval <= 0 {
+		interval = DefaultCompactionInterval
+	}
+	host, _ := os.Hostname()
+	return &Compactor{
+		stream:   stream,
+		store:    store,
+		key:      key,
+		prefix:   strings.Trim(prefix, "/"),
+		interval: interval,
+		lockKey:  key + ":compactor",
+		owner:    fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}
+}
+
+// Run compacts every interval, while this replica holds the compactor lock,
+// until ctx is done
+func (c *Compactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Hold the lock a little past the next tick so the leader keeps it
+		held, err := c.stream.AcquireLock(ctx, c.lockKey, c.owner, c.interval*3/2)
+		if err != nil {
+			slog.Warn("Recorder compaction skipped", "error", err)
+			continue
+		}
+		if !held {
+			continue
+		}
+
+		n, err := c.Compact(ctx)
+		metrics.RecordRecorderCompaction(n, err)
+		if err != nil {
+			slog.Warn("Recorder compaction failed", "entries", n, "error", err)
+			continue
+		}
+		if n > 0 {
+			slog.Info("Recorder compacted entries", "entries", n, "stream", c.key)
+		}
+	}
+}
+
+// Compact moves every entry currently in the stream to the store and returns
+// how many were moved. Entries are deleted from the stream only once their
+// object is stored; entries read but not stored by an earlier, failed run are
+// retried first.
+func (c *Compactor) Compact(ctx context.Context) (int, error) {
+	var (
+		moved int
+		batch []cache.StreamEntry
+		hour  string
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := c.put(ctx, hour, batch); err != nil {
+			return err
+		}
+		moved += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	// Entries delivered to this consumer before but never acknowledged, then
+	// entries never delivered
+	start := "0"
+	for {
+		entries, err := c.stream.ReadGroup(ctx, c.key, compactorGroup, compactorConsumer, start, compactorPage)
+		if err != nil {
+			return moved, err
+		}
+		if len(entries) == 0 {
+			if start == ">" {
+				break
+			}
+			start = ">"
+			continue
+		}
+		if start != ">" {
+			start = entries[len(entries)-1].ID
+		}
+
+		for _, e := range entries {
+			h, err := entryHour(e.ID)
+			if err != nil {
+				return moved, err
+			}
+			if h != hour || len(batch) >= maxObjectEntries {
+				if err := flush(); err != nil {
+					return moved, err
+				}
+				hour = h
+			}
+			batch = append(batch, e)
+		}
+	}
+	return moved, flush()
+}
+
+// put stores one object of entries, then removes them from the stream
+func (c *Compactor) put(ctx context.Context, hour string, entries []cache.StreamEntry) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, e := range entries {
+		zw.Write([]byte(e.Data))
+		zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	// Named by the first entry so a retried object overwrites, not duplicates
+	key := path.Join(c.prefix, hour, entries[0].ID+".jsonl.gz")
+	if err := c.store.Put(ctx, key, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return c.stream.AckDelete(ctx, c.key, compactorGroup, ids...)
+}
+
+// entryHour returns the UTC hour a stream entry was added, as YYYY/MM/DD/HH,
+// from the millisecond timestamp in its ID
+func entryHour(id string) (string, error) {
+	ms, _, _ := strings.Cut(id, "-")
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid stream entry ID %q", id)
+	}
+	return time.UnixMilli(n).UTC().Format("2006/01/02/15"), nil
+}
@@ -0,0 +1,225 @@
+// Package recorder captures served episodes (each robot's observation and the
+// action it was given) for offline training and incident review. Entries are
+// buffered in memory and written to a Sink in the background, so recording
+// never slows planning. Retried requests are recorded once.
+package recorder
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Defaults for New
+const (
+	DefaultBufferSize = 4096
+	maxBatch          = 256
+	writeTimeout      = 5 * time.Second
+
+	// minSweep is the remembered key count below which expired keys are not
+	// swept
+	minSweep = 1024
+)
+
+// Where a recorded action came from
+const (
+	// SourceInference is an action freshly planned by the primary model
+	SourceInference = "inference"
+	// SourceCache is an action reused from the dedup window or plan cache
+	SourceCache = "cache"
+	// SourceFallback is an action planned in degraded mode, from a
+	// downsampled observation
+	SourceFallback = "fallback"
+	// SourceShadow is an action planned by the primary model while a
+	// candidate model was shadowed on the same traffic for comparison
+	SourceShadow = "shadow"
+)
+
+// Entry is one served plan
+type Entry struct {
+	Time      time.Time `json:"time"`
+	RobotID   uint64    `json:"robot_id"`
+	Tenant    string    `json:"tenant"`
+	Client    string    `json:"client,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	// IdempotencyKey is the client's key for the call, kept across retries
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	Model          string    `json:"model"`
+	ObsHash        string    `json:"obs_hash"`
+	Obs            []float32 `json:"obs"`
+	Channels       uint32    `json:"channels"`
+	Height         uint32    `json:"height"`
+	Width          uint32    `json:"width"`
+	Action         []float32 `json:"action"`
+	// Source is one of the Source constants
+	Source string `json:"source"`
+}
+
+// dedupKey identifies the robot's entry of one call across retries: by its
+// idempotency key, else its request ID. It is empty if the call has neither.
+func (e Entry) dedupKey() string {
+	id := e.IdempotencyKey
+	if id == "" {
+		id = e.RequestID
+	}
+	if id == "" {
+		return ""
+	}
+	return e.Tenant + "\x00" + id + "\x00" + strconv.FormatUint(e.RobotID, 10)
+}
+
+// Sink persists recorded entries
+type Sink interface {
+	// Write persists a batch of entries, in order
+	Write(ctx context.Context, entries []Entry) error
+}
+
+// Recorder queues entries and writes them to a sink in batches
+type Recorder struct {
+	sink    Sink
+	model   string
+	entries chan Entry
+	now     func() time.Time
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+
+	// seen holds when each recent call's entry was recorded, for dedupWindow
+	dedupWindow time.Duration
+	seenMu      sync.Mutex
+	seen        map[string]time.Time
+	sweepAt     int
+}
+
+// New creates a Recorder writing entries for model to sink, holding up to
+// bufferSize entries not yet written; more are dropped. An entry repeating the
+// idempotency key or request ID and robot of one recorded within dedupWindow
+// is a retry and is dropped; 0 records every entry.
+func New(sink Sink, model string, bufferSize int, dedupWindow time.Duration) *Recorder {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	r := &Recorder{
+		sink:        sink,
+		model:       model,
+		entries:     make(chan Entry, bufferSize),
+		now:         time.Now,
+		dedupWindow: dedupWindow,
+		seen:        make(map[string]time.Time),
+		sweepAt:     minSweep,
+	}
+
+	r.wg.Add(1)
+	go r.writeLoop()
+	return r
+}
+
+// Record queues an entry without blocking. The entry is dropped if it repeats
+// a recent call, the buffer is full, or the recorder is closed.
+func (r *Recorder) Record(e Entry) {
+	if e.Model == "" {
+		e.Model = r.model
+	}
+	if e.Source == "" {
+		e.Source = SourceInference
+	}
+	if r.duplicate(e) {
+		metrics.RecordRecorderEntries("duplicate", 1)
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.entries <- e:
+	default:
+		metrics.RecordRecorderEntries("dropped", 1)
+	}
+}
+
+// duplicate reports whether e repeats an entry recorded within the dedup
+// window, remembering it otherwise
+func (r *Recorder) duplicate(e Entry) bool {
+	key := e.dedupKey()
+	if r.dedupWindow <= 0 || key == "" {
+		return false
+	}
+	now := r.now()
+
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	if at, ok := r.seen[key]; ok && now.Sub(at) <= r.dedupWindow {
+		return true
+	}
+	r.seen[key] = now
+
+	// Forget calls past the window so the map tracks recent traffic only
+	if len(r.seen) >= r.sweepAt {
+		for k, at := range r.seen {
+			if now.Sub(at) > r.dedupWindow {
+				delete(r.seen, k)
+			}
+		}
+		r.sweepAt = max(minSweep, 2*len(r.seen))
+	}
+	return false
+}
+
+// Close writes the entries still queued and stops the recorder
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.entries)
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
+
+// writeLoop writes queued entries in batches until the recorder is closed
+func (r *Recorder) writeLoop() {
+	defer r.wg.Done()
+
+	batch := make([]Entry, 0, maxBatch)
+	for e := range r.entries {
+		batch = append(batch[:0], e)
+		// Take whatever else is already queued, up to a batch
+	fill:
+		for len(batch) < maxBatch {
+			select {
+			case e, ok := <-r.entries:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, e)
+			default:
+				break fill
+			}
+		}
+		r.write(batch)
+	}
+}
+
+// write sends a batch to the sink; failed batches are counted and logged
+func (r *Recorder) write(batch []Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := r.sink.Write(ctx, batch); err != nil {
+		slog.Warn("Failed to record entries", "count", len(batch), "error", err)
+		metrics.RecordRecorderEntries("failed", len(batch))
+		return
+	}
+	metrics.RecordRecorderEntries("recorded", len(batch))
+}
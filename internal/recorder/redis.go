@@ -0,0 +1,38 @@
+// internal/recorder/redis.go
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DefaultStream is the Redis stream entries are recorded to
+const DefaultStream = "policy-service:episodes"
+
+// StreamAppender appends entries to a Redis stream; implemented by *cache.Cache
+type StreamAppender interface {
+	AppendStream(ctx context.Context, stream string, maxLen int64, data ...string) error
+}
+
+// RedisStreamSink records entries as JSON to a Redis stream, for sites without
+// Kafka. The Compactor moves them to object storage.
+type RedisStreamSink struct {
+	Cache  StreamAppender
+	Stream string
+	// MaxLen caps the stream at roughly this many entries, dropping the oldest,
+	// so a stalled compactor cannot exhaust Redis memory; 0 means no cap
+	MaxLen int64
+}
+
+// Write implements Sink
+func (s RedisStreamSink) Write(ctx context.Context, entries []Entry) error {
+	data := make([]string, len(entries))
+	for i, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		data[i] = string(b)
+	}
+	return s.Cache.AppendStream(ctx, s.Stream, s.MaxLen, data...)
+}
@@ -0,0 +1,152 @@
+// internal/registry/registry.go
+
+// Package registry resolves models in an MLflow-style model registry, so the
+// service can be pointed at "policy/Production" rather than a file and pick
+// up whichever version the registry promotes
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is a resolved registry model version
+type Version struct {
+	Name    string
+	Stage   string
+	Version string
+	// ArtifactURI is the version's artifact directory, e.g.
+	// s3://bucket/1/abc/artifacts/model or mlflow-artifacts:/1/abc/artifacts/model
+	ArtifactURI string
+}
+
+// ParseRef splits "name/stage" or "name/version" into its parts. Registry
+// names may contain slashes, so the last segment is the stage or version.
+func ParseRef(ref string) (string, string, error) {
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", fmt.Errorf("registry model %q must be name/stage or name/version", ref)
+	}
+	return ref[:i], ref[i+1:], nil
+}
+
+// Client calls the MLflow REST API of a tracking server
+type Client struct {
+	// BaseURL is the tracking server, e.g. http://mlflow:5000
+	BaseURL string
+	// Token is sent as a bearer token when set
+	Token  string
+	Client *http.Client
+}
+
+// New creates a Client for the tracking server at baseURL
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// modelVersion is an MLflow ModelVersion
+type modelVersion struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	CurrentStage string `json:"current_stage"`
+}
+
+// Resolve returns the version of model name that stageOrVersion names: a
+// version number, or the latest version in a stage such as Production
+func (c *Client) Resolve(ctx context.Context, name, stageOrVersion string) (Version, error) {
+	var mv modelVersion
+	if _, err := strconv.ParseUint(stageOrVersion, 10, 64); err == nil {
+		var resp struct {
+			ModelVersion modelVersion `json:"model_version"`
+		}
+		q := url.Values{"name": {name}, "version": {stageOrVersion}}
+		if err := c.call(ctx, http.MethodGet, "/api/2.0/mlflow/model-versions/get?"+q.Encode(), nil, &resp); err != nil {
+			return Version{}, err
+		}
+		mv = resp.ModelVersion
+	} else {
+		var resp struct {
+			ModelVersions []modelVersion `json:"model_versions"`
+		}
+		body := map[string]any{"name": name, "stages": []string{stageOrVersion}}
+		if err := c.call(ctx, http.MethodPost, "/api/2.0/mlflow/registered-models/get-latest-versions", body, &resp); err != nil {
+			return Version{}, err
+		}
+		if len(resp.ModelVersions) == 0 {
+			return Version{}, fmt.Errorf("registry model %s has no version in stage %s", name, stageOrVersion)
+		}
+		mv = resp.ModelVersions[0]
+	}
+
+	var uri struct {
+		ArtifactURI string `json:"artifact_uri"`
+	}
+	q := url.Values{"name": {name}, "version": {mv.Version}}
+	if err := c.call(ctx, http.MethodGet, "/api/2.0/mlflow/model-versions/get-download-uri?"+q.Encode(), nil, &uri); err != nil {
+		return Version{}, err
+	}
+	return Version{Name: name, Stage: mv.CurrentStage, Version: mv.Version, ArtifactURI: uri.ArtifactURI}, nil
+}
+
+// ArtifactURL returns the URL of the file at path in v's artifacts. Artifacts
+// proxied by the tracking server (mlflow-artifacts:) are fetched through its
+// artifact API; others are already s3://, gs://, or https:// URLs.
+func (c *Client) ArtifactURL(v Version, path string) (string, error) {
+	uri := strings.TrimRight(v.ArtifactURI, "/") + "/" + strings.TrimLeft(path, "/")
+	if !strings.HasPrefix(uri, "mlflow-artifacts:") {
+		return uri, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifact URI %q: %w", v.ArtifactURI, err)
+	}
+	return c.BaseURL + "/api/2.0/mlflow-artifacts/artifacts/" + strings.TrimLeft(u.Path, "/"), nil
+}
+
+// call sends an API request with body encoded as JSON, decoding the reply into out
+func (c *Client) call(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("registry %s returned %s: %s", strings.SplitN(path, "?", 2)[0], resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid registry response: %w", err)
+	}
+	return nil
+}
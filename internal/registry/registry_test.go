@@ -0,0 +1,102 @@
+// internal/registry/registry_test.go
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeMLflow serves policy versions 6 (Staging) and 7 (Production)
+func fakeMLflow(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/2.0/mlflow/registered-models/get-latest-versions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error_code":"UNAUTHENTICATED"}`, http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Name   string   `json:"name"`
+			Stages []string `json:"stages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		versions := []map[string]string{}
+		if req.Name == "team/policy" && len(req.Stages) == 1 && req.Stages[0] == "Production" {
+			versions = append(versions, map[string]string{"name": req.Name, "version": "7", "current_stage": "Production"})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"model_versions": versions})
+	})
+	mux.HandleFunc("GET /api/2.0/mlflow/model-versions/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("version") != "6" {
+			http.Error(w, `{"error_code":"RESOURCE_DOES_NOT_EXIST"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"model_version": map[string]string{
+			"name": r.URL.Query().Get("name"), "version": "6", "current_stage": "Staging",
+		}})
+	})
+	mux.HandleFunc("GET /api/2.0/mlflow/model-versions/get-download-uri", func(w http.ResponseWriter, r *http.Request) {
+		uri := "s3://models/1/run7/artifacts/model"
+		if r.URL.Query().Get("version") == "6" {
+			uri = "mlflow-artifacts:/1/run6/artifacts/model"
+		}
+		json.NewEncoder(w).Encode(map[string]string{"artifact_uri": uri})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestParseRef(t *testing.T) {
+	name, stage, err := ParseRef("team/policy/Production")
+	if err != nil || name != "team/policy" || stage != "Production" {
+		t.Errorf("Expected team/policy in Production, got %q %q (err %v)", name, stage, err)
+	}
+	for _, ref := range []string{"policy", "policy/", "/Production"} {
+		if _, _, err := ParseRef(ref); err == nil {
+			t.Errorf("Expected an error for %q", ref)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	srv := fakeMLflow(t)
+	c := New(srv.URL+"/", "secret")
+
+	// A stage resolves to its latest version
+	v, err := c.Resolve(context.Background(), "team/policy", "Production")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if v.Version != "7" || v.Stage != "Production" || v.ArtifactURI != "s3://models/1/run7/artifacts/model" {
+		t.Errorf("Unexpected version: %+v", v)
+	}
+	if u, _ := c.ArtifactURL(v, "model.onnx"); u != "s3://models/1/run7/artifacts/model/model.onnx" {
+		t.Errorf("Expected the artifact under the S3 URI, got %s", u)
+	}
+
+	// A version number resolves to that version; proxied artifacts are
+	// fetched through the tracking server
+	v, err = c.Resolve(context.Background(), "team/policy", "6")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if v.Version != "6" || v.Stage != "Staging" {
+		t.Errorf("Unexpected version: %+v", v)
+	}
+	if u, _ := c.ArtifactURL(v, "model.onnx"); u != srv.URL+"/api/2.0/mlflow-artifacts/artifacts/1/run6/artifacts/model/model.onnx" {
+		t.Errorf("Expected the artifact through the tracking server, got %s", u)
+	}
+
+	if _, err := c.Resolve(context.Background(), "team/policy", "Archived"); err == nil {
+		t.Error("Expected an error for a stage without versions")
+	}
+	if _, err := c.Resolve(context.Background(), "team/policy", "9"); err == nil {
+		t.Error("Expected an error for a missing version")
+	}
+	if _, err := New(srv.URL, "").Resolve(context.Background(), "team/policy", "Production"); err == nil {
+		t.Error("Expected an error without the token")
+	}
+}
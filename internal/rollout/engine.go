@@ -0,0 +1,251 @@
+// internal/rollout/engine.go
+package rollout
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Engine is an InferenceEngine that splits traffic between the primary model
+// and a candidate under rollout. In shadow mode every primary batch is also
+// replayed on the candidate in the background to measure divergence.
+type Engine struct {
+	mu        sync.RWMutex
+	primary   inference.InferenceEngine
+	candidate inference.InferenceEngine
+	percent   float64
+	shadow    bool
+
+	// shadowBusy limits shadow replays to one at a time so they never queue up
+	shadowBusy atomic.Bool
+
+	statsMu sync.Mutex
+	stats   Stats
+}
+
+// Stats summarizes candidate behavior since the last stage transition
+type Stats struct {
+	// Batches is the number of batches the candidate ran (served or shadowed)
+	Batches int64 `json:"batches"`
+	// Errors is the number of those batches that failed
+	Errors int64 `json:"errors"`
+	// Compared is the number of shadow batches compared against the primary
+	Compared int64 `json:"compared"`
+	// DivergenceSum is the sum of per-batch mean absolute action differences
+	DivergenceSum float64 `json:"divergence_sum"`
+}
+
+// ErrorRate returns the fraction of candidate batches that failed
+func (s Stats) ErrorRate() float64 {
+	if s.Batches == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Batches)
+}
+
+// MeanDivergence returns the average shadow divergence from the primary
+func (s Stats) MeanDivergence() float64 {
+	if s.Compared == 0 {
+		return 0
+	}
+	return s.DivergenceSum / float64(s.Compared)
+}
+
+// NewEngine wraps the primary engine; all traffic goes to it until a candidate is set
+func NewEngine(primary inference.InferenceEngine) *Engine {
+	return &Engine{primary: primary}
+}
+
+// Predict implements inference.InferenceEngine
+func (e *Engine) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.candidate != nil && !e.shadow && rand.Float64()*100 < e.percent {
+		actions, err := e.candidate.Predict(obsBatch, c, h, w)
+		e.record(err, 0, false)
+		metrics.RecordRolloutBatch("candidate", err == nil)
+		return actions, err
+	}
+
+	actions, err := e.primary.Predict(obsBatch, c, h, w)
+	metrics.RecordRolloutBatch("primary", err == nil)
+
+	if err == nil && e.candidate != nil && e.shadow && e.shadowBusy.CompareAndSwap(false, true) {
+		go e.replay(obsBatch, c, h, w, actions)
+	}
+	return actions, err
+}
+
+// replay runs a batch on the candidate and compares it with the primary's actions
+func (e *Engine) replay(obsBatch [][]float32, c, h, w int64, expected []float32) {
+	defer e.shadowBusy.Store(false)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.candidate == nil || !e.shadow {
+		return
+	}
+
+	actions, err := e.candidate.Predict(obsBatch, c, h, w)
+	metrics.RecordRolloutBatch("shadow", err == nil)
+	if err != nil {
+		e.record(err, 0, false)
+		return
+	}
+
+	// An incompatible output shape counts as a candidate failure
+	if len(actions) != len(expected) {
+		e.record(errShapeMismatch, 0, false)
+		return
+	}
+
+	div := divergence(expected, actions)
+	metrics.RecordRolloutDivergence(div)
+	e.record(nil, div, true)
+}
+
+// errShapeMismatch marks shadow outputs whose size differs from the primary's
+var errShapeMismatch = errors.New("candidate output shape differs from primary")
+
+// divergence is the mean absolute difference between two equal-length action vectors
+func divergence(a, b []float32) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for i := range a {
+		sum += math.Abs(float64(a[i] - b[i]))
+	}
+	return sum / float64(len(a))
+}
+
+func (e *Engine) record(err error, div float64, compared bool) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	e.stats.Batches++
+	if err != nil {
+		e.stats.Errors++
+	}
+	if compared {
+		e.stats.Compared++
+		e.stats.DivergenceSum += div
+	}
+}
+
+// CandidateStats returns candidate stats since the last reset
+func (e *Engine) CandidateStats() Stats {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	return e.stats
+}
+
+// ResetStats clears candidate stats, typically on a stage transition
+func (e *Engine) ResetStats() {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	e.stats = Stats{}
+}
+
+// SetPrimary replaces the primary engine, closing the previous one once in-flight calls finish
+func (e *Engine) SetPrimary(primary inference.InferenceEngine) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.primary != nil && e.primary != primary {
+		e.primary.Close()
+	}
+	e.primary = primary
+}
+
+// SetCandidate installs a candidate receiving percent of traffic, or shadow traffic only
+func (e *Engine) SetCandidate(candidate inference.InferenceEngine, percent float64, shadow bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.candidate != nil && e.candidate != candidate {
+		e.candidate.Close()
+	}
+	e.candidate = candidate
+	e.percent = percent
+	e.shadow = shadow
+}
+
+// SetTraffic changes the candidate's share of traffic
+func (e *Engine) SetTraffic(percent float64, shadow bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.percent = percent
+	e.shadow = shadow
+}
+
+// ClearCandidate unloads the candidate, returning all traffic to the primary
+func (e *Engine) ClearCandidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.candidate != nil {
+		e.candidate.Close()
+	}
+	e.candidate = nil
+	e.percent = 0
+	e.shadow = false
+}
+
+// Promote makes the candidate the primary and unloads the previous primary
+func (e *Engine) Promote() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.candidate == nil {
+		return
+	}
+	if e.primary != nil {
+		e.primary.Close()
+	}
+	e.primary = e.candidate
+	e.candidate = nil
+	e.percent = 0
+	e.shadow = false
+}
+
+// Shadowing reports whether a candidate is replaying primary traffic for
+// comparison
+func (e *Engine) Shadowing() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.candidate != nil && e.shadow
+}
+
+// Unwrap returns the primary engine, implementing inference.Wrapper
+func (e *Engine) Unwrap() inference.InferenceEngine {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.primary
+}
+
+// Close implements inference.InferenceEngine
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var err error
+	if e.candidate != nil {
+		err = e.candidate.Close()
+		e.candidate = nil
+	}
+	if e.primary != nil {
+		if perr := e.primary.Close(); perr != nil {
+			err = perr
+		}
+	}
+	return err
+}
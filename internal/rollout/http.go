@@ -0,0 +1,72 @@
+// internal/rollout/http.go
+package rollout
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+)
+
+// Register adds the rollout endpoints to the admin API:
+//
+//	GET  /admin/rollout           current state and candidate stats
+//	POST /admin/rollout/start     {"model": "/models/v2.onnx"}
+//	POST /admin/rollout/advance
+//	POST /admin/rollout/rollback  {"reason": "..."} (optional)
+//	POST /admin/rollout/complete
+func (c *Controller) Register(m *admin.Mux) {
+	m.HandleFunc("GET /admin/rollout", c.handleStatus)
+	m.HandleFunc("POST /admin/rollout/start", c.handleStart)
+	m.HandleFunc("POST /admin/rollout/advance", func(w http.ResponseWriter, r *http.Request) {
+		c.respond(w, c.Advance())
+	})
+	m.HandleFunc("POST /admin/rollout/rollback", c.handleRollback)
+	m.HandleFunc("POST /admin/rollout/complete", func(w http.ResponseWriter, r *http.Request) {
+		c.respond(w, c.Complete())
+	})
+}
+
+func (c *Controller) handleStatus(w http.ResponseWriter, r *http.Request) {
+	state := c.State()
+	admin.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"state":           state,
+		"traffic_percent": trafficPercent[state.Stage],
+		"candidate_stats": c.engine.CandidateStats(),
+	})
+}
+
+func (c *Controller) handleStart(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Model == "" {
+		admin.WriteError(w, http.StatusBadRequest, `expected JSON body {"model": "<path>"}`)
+		return
+	}
+	c.respond(w, c.Start(body.Model))
+}
+
+func (c *Controller) handleRollback(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	if body.Reason == "" {
+		body.Reason = "rolled back by operator"
+	}
+	c.respond(w, c.Rollback(body.Reason))
+}
+
+// respond writes the new state, or maps err to an HTTP status
+func (c *Controller) respond(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		admin.WriteJSON(w, http.StatusOK, c.State())
+	case errors.Is(err, ErrInvalidTransition):
+		admin.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		admin.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,295 @@
+// Package rollout moves a candidate model through staged traffic shifts
+// (shadow, 5% canary, 50%, 100%) and rolls it back automatically when its
+// error rate or divergence from the primary model exceeds thresholds
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Stage is a step in the rollout state machine
+type Stage string
+
+// Rollout stages
+const (
+	StageIdle       Stage = "idle"
+	StageShadow     Stage = "shadow"
+	StageCanary     Stage = "canary"
+	StageRamp       Stage = "ramp"
+	StageFull       Stage = "full"
+	StageRolledBack Stage = "rolled_back"
+)
+
+// trafficPercent is the share of traffic served by the candidate in each stage
+var trafficPercent = map[Stage]float64{
+	StageShadow: 0,
+	StageCanary: 5,
+	StageRamp:   50,
+	StageFull:   100,
+}
+
+// nextStage defines the forward transitions
+var nextStage = map[Stage]Stage{
+	StageShadow: StageCanary,
+	StageCanary: StageRamp,
+	StageRamp:   StageFull,
+}
+
+// stageOrdinal is exported as the rollout_stage gauge
+var stageOrdinal = map[Stage]float64{
+	StageIdle:       0,
+	StageShadow:     1,
+	StageCanary:     2,
+	StageRamp:       3,
+	StageFull:       4,
+	StageRolledBack: -1,
+}
+
+// ErrInvalidTransition is returned when an operation is not allowed in the current stage
+var ErrInvalidTransition = errors.New("invalid rollout transition")
+
+// Active reports whether a candidate is loaded in this stage
+func (s Stage) Active() bool {
+	_, ok := trafficPercent[s]
+	return ok
+}
+
+// State is the persisted rollout state
+type State struct {
+	Stage Stage `json:"stage"`
+	// Candidate is the model path being rolled out
+	Candidate string `json:"candidate,omitempty"`
+	// Primary overrides the configured model after a completed rollout
+	Primary string `json:"primary,omitempty"`
+	// Reason explains the last transition (e.g. why a rollback happened)
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Thresholds control automatic rollback and advancement
+type Thresholds struct {
+	// MaxErrorRate is the candidate error rate (0..1) that triggers rollback
+	MaxErrorRate float64
+	// MaxDivergence is the mean absolute action difference from the primary
+	// (measured in shadow) that triggers rollback
+	MaxDivergence float64
+	// MinSamples is the number of candidate batches required before judging
+	MinSamples int64
+	// StageDuration advances healthy stages automatically after this long; 0 means manual only
+	StageDuration time.Duration
+}
+
+// Loader loads a model for the given path
+type Loader func(path string) (inference.InferenceEngine, error)
+
+// Controller drives the rollout state machine
+type Controller struct {
+	engine     *Engine
+	store      Store
+	load       Loader
+	thresholds Thresholds
+	now        func() time.Time
+
+	mu    sync.Mutex
+	state State
+}
+
+// NewController restores the persisted state, loading any promoted primary or
+// in-progress candidate, and returns a controller serving through engine
+func NewController(engine *Engine, store Store, load Loader, thresholds Thresholds) (*Controller, error) {
+	c := &Controller{
+		engine:     engine,
+		store:      store,
+		load:       load,
+		thresholds: thresholds,
+		now:        time.Now,
+		state:      State{Stage: StageIdle},
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rollout state: %w", err)
+	}
+	if state == nil {
+		c.state.UpdatedAt = c.now()
+		c.publish()
+		return c, nil
+	}
+	c.state = *state
+
+	// A completed rollout replaced the configured model
+	if state.Primary != "" {
+		primary, err := load(state.Primary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load promoted model %s: %w", state.Primary, err)
+		}
+		engine.SetPrimary(primary)
+		slog.Info("Rollout restored promoted model", "model", state.Primary)
+	}
+
+	// Resume an in-progress rollout
+	if state.Stage.Active() {
+		candidate, err := load(state.Candidate)
+		if err != nil {
+			c.transitionLocked(StageRolledBack, fmt.Sprintf("failed to reload candidate on restart: %v", err))
+		} else {
+			engine.SetCandidate(candidate, trafficPercent[state.Stage], state.Stage == StageShadow)
+			slog.Info("Rollout resumed", "candidate", state.Candidate, "stage", state.Stage)
+		}
+	}
+
+	c.publish()
+	return c, nil
+}
+
+// State returns the current rollout state
+func (c *Controller) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Start loads candidate and begins shadowing it
+func (c *Controller) Start(candidate string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state.Stage != StageIdle && c.state.Stage != StageRolledBack {
+		return fmt.Errorf("%w: cannot start from %s", ErrInvalidTransition, c.state.Stage)
+	}
+
+	engine, err := c.load(candidate)
+	if err != nil {
+		return fmt.Errorf("failed to load candidate %s: %w", candidate, err)
+	}
+
+	c.engine.SetCandidate(engine, trafficPercent[StageShadow], true)
+	c.state.Candidate = candidate
+	return c.transitionLocked(StageShadow, "started")
+}
+
+// Advance moves the candidate to the next traffic stage
+func (c *Controller) Advance() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.advanceLocked("advanced by operator")
+}
+
+func (c *Controller) advanceLocked(reason string) error {
+	next, ok := nextStage[c.state.Stage]
+	if !ok {
+		return fmt.Errorf("%w: cannot advance from %s", ErrInvalidTransition, c.state.Stage)
+	}
+
+	c.engine.SetTraffic(trafficPercent[next], false)
+	return c.transitionLocked(next, reason)
+}
+
+// Rollback returns all traffic to the primary model and unloads the candidate
+func (c *Controller) Rollback(reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rollbackLocked(reason)
+}
+
+func (c *Controller) rollbackLocked(reason string) error {
+	if !c.state.Stage.Active() {
+		return fmt.Errorf("%w: nothing to roll back in %s", ErrInvalidTransition, c.state.Stage)
+	}
+
+	c.engine.ClearCandidate()
+	metrics.RecordRollback()
+	slog.Warn("Rollout rolling back", "candidate", c.state.Candidate, "reason", reason)
+	return c.transitionLocked(StageRolledBack, reason)
+}
+
+// Complete makes a fully rolled out candidate the primary model
+func (c *Controller) Complete() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state.Stage != StageFull {
+		return fmt.Errorf("%w: cannot complete from %s", ErrInvalidTransition, c.state.Stage)
+	}
+
+	c.engine.Promote()
+	c.state.Primary = c.state.Candidate
+	c.state.Candidate = ""
+	return c.transitionLocked(StageIdle, "completed")
+}
+
+// Evaluate checks candidate health, rolling back on breached thresholds and
+// advancing after StageDuration when healthy
+func (c *Controller) Evaluate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.state.Stage.Active() {
+		return
+	}
+
+	stats := c.engine.CandidateStats()
+	if stats.Batches >= c.thresholds.MinSamples {
+		if rate := stats.ErrorRate(); c.thresholds.MaxErrorRate > 0 && rate > c.thresholds.MaxErrorRate {
+			c.rollbackLocked(fmt.Sprintf("candidate error rate %.3f exceeds %.3f", rate, c.thresholds.MaxErrorRate))
+			return
+		}
+	}
+	if stats.Compared >= c.thresholds.MinSamples {
+		if div := stats.MeanDivergence(); c.thresholds.MaxDivergence > 0 && div > c.thresholds.MaxDivergence {
+			c.rollbackLocked(fmt.Sprintf("candidate divergence %.4f exceeds %.4f", div, c.thresholds.MaxDivergence))
+			return
+		}
+	}
+
+	if c.thresholds.StageDuration > 0 && c.state.Stage != StageFull &&
+		c.now().Sub(c.state.UpdatedAt) >= c.thresholds.StageDuration && stats.Batches >= c.thresholds.MinSamples {
+		if err := c.advanceLocked("advanced automatically"); err != nil {
+			slog.Warn("Rollout auto-advance failed", "error", err)
+		}
+	}
+}
+
+// Run evaluates the rollout every interval until ctx is done
+func (c *Controller) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Evaluate()
+		}
+	}
+}
+
+// transitionLocked records a new stage, resets candidate stats, and persists the state
+func (c *Controller) transitionLocked(stage Stage, reason string) error {
+	previous := c.state.Stage
+	c.state.Stage = stage
+	c.state.Reason = reason
+	c.state.UpdatedAt = c.now()
+	c.engine.ResetStats()
+	c.publish()
+
+	slog.Info("Rollout transition", "from", previous, "to", stage, "reason", reason)
+	if err := c.store.Save(&c.state); err != nil {
+		return fmt.Errorf("failed to persist rollout state: %w", err)
+	}
+	return nil
+}
+
+// publish exports the current stage as a metric
+func (c *Controller) publish() {
+	metrics.SetRolloutStage(stageOrdinal[c.state.Stage], trafficPercent[c.state.Stage])
+}
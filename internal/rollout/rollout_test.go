@@ -0,0 +1,168 @@
+// internal/rollout/rollout_test.go
+package rollout
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+)
+
+// loaderFor returns a Loader serving the given engines by path
+func loaderFor(engines map[string]inference.InferenceEngine) Loader {
+	return func(path string) (inference.InferenceEngine, error) {
+		e, ok := engines[path]
+		if !ok {
+			return nil, fmt.Errorf("no model at %s", path)
+		}
+		return e, nil
+	}
+}
+
+var testObs = [][]float32{{0.1, 0.2, 0.3, 0.4}}
+
+func TestController_StagesAndComplete(t *testing.T) {
+	primary := inference.NewMockWithAction([]float32{1, 1})
+	candidate := inference.NewMockWithAction([]float32{2, 2})
+	load := loaderFor(map[string]inference.InferenceEngine{"v2.onnx": candidate})
+
+	store := &MemoryStore{}
+	engine := NewEngine(primary)
+	c, err := NewController(engine, store, load, Thresholds{MinSamples: 1000})
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	if err := c.Advance(); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("Expected ErrInvalidTransition advancing from idle, got %v", err)
+	}
+
+	if err := c.Start("v2.onnx"); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for _, want := range []Stage{StageCanary, StageRamp, StageFull} {
+		if err := c.Advance(); err != nil {
+			t.Fatalf("Advance failed: %v", err)
+		}
+		if got := c.State().Stage; got != want {
+			t.Fatalf("Expected stage %s, got %s", want, got)
+		}
+	}
+
+	// At full rollout every batch goes to the candidate
+	actions, err := engine.Predict(testObs, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if actions[0] != 2 {
+		t.Errorf("Expected candidate action, got %v", actions)
+	}
+
+	if err := c.Complete(); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	state := c.State()
+	if state.Stage != StageIdle || state.Primary != "v2.onnx" {
+		t.Fatalf("Expected idle with promoted primary, got %+v", state)
+	}
+
+	// A restarted controller reloads the promoted model as primary
+	restarted := NewEngine(inference.NewMockWithAction([]float32{1, 1}))
+	if _, err := NewController(restarted, store, load, Thresholds{}); err != nil {
+		t.Fatalf("NewController after restart failed: %v", err)
+	}
+	actions, err = restarted.Predict(testObs, 1, 2, 2)
+	if err != nil || actions[0] != 2 {
+		t.Errorf("Expected promoted model after restart, got %v (err %v)", actions, err)
+	}
+}
+
+func TestController_RollbackOnDivergence(t *testing.T) {
+	primary := inference.NewMockWithAction([]float32{1, 1})
+	candidate := inference.NewMockWithAction([]float32{1.5, 1.5})
+	load := loaderFor(map[string]inference.InferenceEngine{"v2.onnx": candidate})
+
+	engine := NewEngine(primary)
+	c, err := NewController(engine, &MemoryStore{}, load, Thresholds{MaxDivergence: 0.1, MinSamples: 1})
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	if err := c.Start("v2.onnx"); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Shadow mode serves the primary and replays on the candidate in the background
+	actions, err := engine.Predict(testObs, 1, 2, 2)
+	if err != nil || actions[0] != 1 {
+		t.Fatalf("Expected primary action in shadow, got %v (err %v)", actions, err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for engine.CandidateStats().Compared == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Shadow replay did not complete")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Evaluate()
+	if state := c.State(); state.Stage != StageRolledBack {
+		t.Fatalf("Expected rollback on divergence, got %+v", state)
+	}
+}
+
+func TestController_RollbackOnErrors(t *testing.T) {
+	primary := inference.NewMockWithAction([]float32{1, 1})
+	candidate := inference.NewMock()
+	candidate.ShouldError = true
+	load := loaderFor(map[string]inference.InferenceEngine{"v2.onnx": candidate})
+
+	engine := NewEngine(primary)
+	c, err := NewController(engine, &MemoryStore{}, load, Thresholds{MaxErrorRate: 0.5, MinSamples: 3})
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	c.Start("v2.onnx")
+	c.Advance()
+	c.Advance()
+	c.Advance()
+
+	for i := 0; i < 3; i++ {
+		engine.Predict(testObs, 1, 2, 2)
+	}
+
+	c.Evaluate()
+	if state := c.State(); state.Stage != StageRolledBack {
+		t.Fatalf("Expected rollback on errors, got %+v", state)
+	}
+
+	// Traffic is back on the primary
+	actions, err := engine.Predict(testObs, 1, 2, 2)
+	if err != nil || actions[0] != 1 {
+		t.Errorf("Expected primary after rollback, got %v (err %v)", actions, err)
+	}
+}
+
+func TestController_ResumesAfterRestart(t *testing.T) {
+	candidate := inference.NewMockWithAction([]float32{2, 2})
+	load := loaderFor(map[string]inference.InferenceEngine{"v2.onnx": candidate})
+	store := &MemoryStore{}
+
+	c, err := NewController(NewEngine(inference.NewMock()), store, load, Thresholds{})
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	c.Start("v2.onnx")
+	c.Advance()
+
+	restarted, err := NewController(NewEngine(inference.NewMock()), store, load, Thresholds{})
+	if err != nil {
+		t.Fatalf("NewController after restart failed: %v", err)
+	}
+	state := restarted.State()
+	if state.Stage != StageCanary || state.Candidate != "v2.onnx" {
+		t.Fatalf("Expected canary of v2.onnx after restart, got %+v", state)
+	}
+}
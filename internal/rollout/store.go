@@ -0,0 +1,78 @@
+// internal/rollout/store.go
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+)
+
+// DefaultStateKey is the Redis key holding the rollout state
+const DefaultStateKey = "policy-service:rollout"
+
+// Store persists rollout state across restarts
+type Store interface {
+	// Load returns the saved state, or nil if none was saved
+	Load() (*State, error)
+	Save(state *State) error
+}
+
+// RedisStore keeps rollout state as JSON in Redis
+type RedisStore struct {
+	Cache *cache.Cache
+	Key   string
+}
+
+// Load implements Store
+func (s RedisStore) Load() (*State, error) {
+	data, err := s.Cache.Get(s.Key)
+	if err != nil {
+		return nil, err
+	}
+	if data == "" {
+		return nil, nil
+	}
+
+	var state State
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("invalid rollout state in %s: %w", s.Key, err)
+	}
+	return &state, nil
+}
+
+// Save implements Store
+func (s RedisStore) Save(state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.Cache.Set(s.Key, string(data), 0)
+}
+
+// MemoryStore keeps rollout state in process; it does not survive restarts
+type MemoryStore struct {
+	mu    sync.Mutex
+	state *State
+}
+
+// Load implements Store
+func (s *MemoryStore) Load() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return nil, nil
+	}
+	state := *s.state
+	return &state, nil
+}
+
+// Save implements Store
+func (s *MemoryStore) Save(state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *state
+	s.state = &saved
+	return nil
+}
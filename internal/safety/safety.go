@@ -0,0 +1,95 @@
+// Package safety checks each planned action against operational limits before
+// it is sent to the robot. Unlike the action contract, which fails the call
+// when the model misbehaves, a safety violation still answers the robot: the
+// response is marked unsafe, or its action is clamped into the limits.
+package safety
+
+import (
+	"fmt"
+	"math"
+)
+
+// Modes of handling an action outside the limits
+const (
+	// ModeFlag sends the action unchanged with PlanResponse.Safe false
+	ModeFlag = "flag"
+	// ModeClamp clamps each value into its limit and keeps the response safe
+	ModeClamp = "clamp"
+)
+
+// Results of checking one action
+const (
+	ResultSafe    = "safe"
+	ResultFlagged = "flagged"
+	ResultClamped = "clamped"
+	// ResultInvalid is an action with non-finite values or the wrong number of
+	// values; it cannot be clamped and is always flagged
+	ResultInvalid = "invalid"
+)
+
+// Limit is the inclusive range of one action value
+type Limit struct {
+	Min float32
+	Max float32
+}
+
+// Checker checks actions against per-value limits
+type Checker struct {
+	mode   string
+	limits []Limit
+}
+
+// New creates a Checker for mode. limits holds one Limit per action value, or
+// a single Limit for all of them.
+func New(mode string, limits []Limit) (*Checker, error) {
+	if mode != ModeFlag && mode != ModeClamp {
+		return nil, fmt.Errorf("unsupported safety mode: %q", mode)
+	}
+	if len(limits) == 0 {
+		return nil, fmt.Errorf("safety limits are empty")
+	}
+	for i, l := range limits {
+		if l.Min > l.Max {
+			return nil, fmt.Errorf("safety limit %d has min %g above max %g", i, l.Min, l.Max)
+		}
+	}
+	return &Checker{mode: mode, limits: limits}, nil
+}
+
+// limit returns the limit of action value i
+func (c *Checker) limit(i int) Limit {
+	if len(c.limits) == 1 {
+		return c.limits[0]
+	}
+	return c.limits[i]
+}
+
+// Check returns the action to send, whether it is safe, and one of the Result
+// constants. A clamped action is a copy; action itself is never modified.
+func (c *Checker) Check(action []float32) ([]float32, bool, string) {
+	if len(c.limits) > 1 && len(action) != len(c.limits) {
+		return action, false, ResultInvalid
+	}
+	outside := false
+	for i, v := range action {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return action, false, ResultInvalid
+		}
+		if l := c.limit(i); v < l.Min || v > l.Max {
+			outside = true
+		}
+	}
+	switch {
+	case !outside:
+		return action, true, ResultSafe
+	case c.mode == ModeFlag:
+		return action, false, ResultFlagged
+	}
+
+	clamped := make([]float32, len(action))
+	for i, v := range action {
+		l := c.limit(i)
+		clamped[i] = min(max(v, l.Min), l.Max)
+	}
+	return clamped, true, ResultClamped
+}
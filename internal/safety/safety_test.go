@@ -0,0 +1,70 @@
+// internal/safety/safety_test.go
+package safety
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChecker_Flag(t *testing.T) {
+	c, err := New(ModeFlag, []Limit{{Min: -1, Max: 1}, {Min: 0, Max: 2}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	cases := []struct {
+		action []float32
+		safe   bool
+		result string
+	}{
+		{[]float32{-1, 2}, true, ResultSafe},
+		{[]float32{1.5, 1}, false, ResultFlagged},
+		{[]float32{0, float32(math.NaN())}, false, ResultInvalid},
+		{[]float32{0, 1, 2}, false, ResultInvalid},
+	}
+	for _, tc := range cases {
+		out, safe, result := c.Check(tc.action)
+		if safe != tc.safe || result != tc.result {
+			t.Errorf("Expected safe=%v (%s) for %v, got safe=%v (%s)", tc.safe, tc.result, tc.action, safe, result)
+		}
+		if len(out) != len(tc.action) || (len(out) > 0 && &out[0] != &tc.action[0]) {
+			t.Errorf("Expected flag mode to send %v unchanged, got %v", tc.action, out)
+		}
+	}
+}
+
+func TestChecker_Clamp(t *testing.T) {
+	// A single limit applies to every value
+	c, err := New(ModeClamp, []Limit{{Min: -1, Max: 1}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	action := []float32{-3, 0.5, 2}
+	out, safe, result := c.Check(action)
+	if !safe || result != ResultClamped {
+		t.Fatalf("Expected a safe clamped action, got safe=%v (%s)", safe, result)
+	}
+	if out[0] != -1 || out[1] != 0.5 || out[2] != 1 {
+		t.Errorf("Expected [-1 0.5 1], got %v", out)
+	}
+	if action[0] != -3 {
+		t.Error("Expected the original action to be left untouched")
+	}
+
+	if _, safe, result := c.Check([]float32{float32(math.Inf(1))}); safe || result != ResultInvalid {
+		t.Errorf("Expected non-finite values to be flagged, got safe=%v (%s)", safe, result)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New("ignore", []Limit{{Min: 0, Max: 1}}); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+	if _, err := New(ModeFlag, nil); err == nil {
+		t.Error("Expected an error without limits")
+	}
+	if _, err := New(ModeFlag, []Limit{{Min: 1, Max: 0}}); err == nil {
+		t.Error("Expected an error for min above max")
+	}
+}
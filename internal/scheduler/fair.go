@@ -0,0 +1,251 @@
+// Package scheduler provides weighted fair queuing of inference batches across tenants
+package scheduler
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// FairScheduler limits the number of inference batches running concurrently and
+// hands out free slots in weighted fair order across tenants.
+//
+// Each batch is tagged with a virtual finish time of start + cost/weight, where
+// cost is the batch size. Waiting batches are dispatched in finish-time order, so
+// a tenant submitting very large batches accumulates virtual time quickly and
+// cannot starve tenants sending small ones.
+//
+// Metrics label tenants without a configured weight as OtherTenant, so
+// tenant IDs do not grow the metric series without bound.
+type FairScheduler struct {
+	mu            sync.Mutex
+	slots         int
+	active        int
+	weights       map[string]float64
+	defaultWeight float64
+	virtualTime   float64
+	lastFinish    map[string]float64
+	queue         waitQueue
+	seq           uint64
+}
+
+// OtherTenant labels the metrics of tenants without a configured weight
+const OtherTenant = "other"
+
+// New creates a FairScheduler with the given number of concurrent batch slots.
+// weights maps tenant IDs to their relative share; tenants not listed get weight 1.
+func New(slots int, weights map[string]float64) *FairScheduler {
+	if slots <= 0 {
+		slots = 1
+	}
+
+	w := make(map[string]float64, len(weights))
+	for tenant, weight := range weights {
+		if weight > 0 {
+			w[tenant] = weight
+		}
+	}
+
+	return &FairScheduler{
+		slots:         slots,
+		weights:       w,
+		defaultWeight: 1,
+		lastFinish:    make(map[string]float64),
+	}
+}
+
+// Acquire blocks until the tenant's batch of the given cost may run, or ctx is done.
+// On success it returns a release function that must be called once the batch completes.
+func (s *FairScheduler) Acquire(ctx context.Context, tenant string, cost int) (func(), error) {
+	if cost < 1 {
+		cost = 1
+	}
+	enqueued := time.Now()
+	label := s.label(tenant)
+
+	s.mu.Lock()
+	start := s.virtualTime
+	if last := s.lastFinish[tenant]; last > start {
+		start = last
+	}
+	finish := start + float64(cost)/s.weight(tenant)
+	s.lastFinish[tenant] = finish
+
+	// Fast path: a slot is free and nobody is ahead of us
+	if s.active < s.slots && s.queue.Len() == 0 {
+		s.active++
+		s.virtualTime = start
+		s.mu.Unlock()
+		metrics.RecordSchedulerQueueWait(label, 0)
+		return s.releaser(), nil
+	}
+
+	w := &waiter{
+		tenant:   tenant,
+		queuedAt: s.virtualTime,
+		start:    start,
+		finish:   finish,
+		seq:      s.seq,
+		ready:    make(chan struct{}),
+	}
+	s.seq++
+	heap.Push(&s.queue, w)
+	metrics.AddSchedulerQueueDepth(label, 1)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		metrics.RecordSchedulerQueueWait(label, time.Since(enqueued).Seconds())
+		return s.releaser(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		if w.granted {
+			// Lost the race: the slot was handed to us as ctx was cancelled
+			s.mu.Unlock()
+			s.releaser()()
+			return nil, ctx.Err()
+		}
+		heap.Remove(&s.queue, w.index)
+		s.forgetLocked(w)
+		metrics.AddSchedulerQueueDepth(label, -1)
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Slots returns the number of batches that may run concurrently
+func (s *FairScheduler) Slots() int {
+	return s.slots
+}
+
+// Queued returns the number of batches currently waiting for a slot
+func (s *FairScheduler) Queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.Len()
+}
+
+// weight returns the configured weight for a tenant
+func (s *FairScheduler) weight(tenant string) float64 {
+	if w, ok := s.weights[tenant]; ok {
+		return w
+	}
+	return s.defaultWeight
+}
+
+// label returns the metric label for a tenant
+func (s *FairScheduler) label(tenant string) string {
+	if _, ok := s.weights[tenant]; ok {
+		return tenant
+	}
+	return OtherTenant
+}
+
+// forgetLocked rolls back the virtual time a cancelled waiter charged its
+// tenant, so a batch that never ran does not push back the tenant's later
+// ones. The tenant's later waiters are all still queued, since they finish
+// after the cancelled one, and are retagged as if it had never been queued.
+func (s *FairScheduler) forgetLocked(cancelled *waiter) {
+	var later []*waiter
+	for _, w := range s.queue {
+		if w.tenant == cancelled.tenant && w.seq > cancelled.seq {
+			later = append(later, w)
+		}
+	}
+	slices.SortFunc(later, func(a, b *waiter) int { return cmp.Compare(a.seq, b.seq) })
+
+	// The cancelled waiter started no earlier than both the virtual time and
+	// the tenant's previous finish when it was queued
+	finish := cancelled.start
+	for _, w := range later {
+		cost := w.finish - w.start
+		w.start = max(w.queuedAt, finish)
+		w.finish = w.start + cost
+		finish = w.finish
+	}
+	s.lastFinish[cancelled.tenant] = finish
+	heap.Init(&s.queue)
+}
+
+// releaser returns an idempotent function that frees one slot
+func (s *FairScheduler) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(s.release)
+	}
+}
+
+// release frees a slot and dispatches the next waiters in fair order
+func (s *FairScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active--
+	for s.active < s.slots && s.queue.Len() > 0 {
+		w := heap.Pop(&s.queue).(*waiter)
+		w.granted = true
+		s.active++
+		if w.start > s.virtualTime {
+			s.virtualTime = w.start
+		}
+		metrics.AddSchedulerQueueDepth(s.label(w.tenant), -1)
+		close(w.ready)
+	}
+
+	// Forget accumulated history once the scheduler goes idle
+	if s.active == 0 && s.queue.Len() == 0 {
+		s.virtualTime = 0
+		s.lastFinish = make(map[string]float64)
+	}
+}
+
+// waiter is a batch queued for a slot
+type waiter struct {
+	tenant   string
+	queuedAt float64 // virtual time when the waiter was queued
+	start    float64
+	finish   float64
+	seq      uint64
+	index    int
+	granted  bool
+	ready    chan struct{}
+}
+
+// waitQueue is a min-heap of waiters ordered by virtual finish time
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].finish != q[j].finish {
+		return q[i].finish < q[j].finish
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
@@ -0,0 +1,182 @@
+// internal/scheduler/fair_test.go
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFairScheduler_AcquireWithFreeSlot(t *testing.T) {
+	s := New(2, nil)
+
+	release, err := s.Acquire(context.Background(), "a", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	release()
+	release() // Must be safe to call twice
+
+	if s.Queued() != 0 {
+		t.Errorf("Expected empty queue, got %d", s.Queued())
+	}
+}
+
+func TestFairScheduler_SmallTenantOvertakesLargeBatches(t *testing.T) {
+	s := New(1, nil)
+
+	// Occupy the only slot so subsequent requests queue up
+	hold, err := s.Acquire(context.Background(), "bulk", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	r := &recorder{}
+	r.enqueue(t, s, "bulk", 1024)
+	r.enqueue(t, s, "bulk", 1024)
+	r.enqueue(t, s, "robot", 1)
+
+	hold()
+	order := r.wait()
+
+	if len(order) != 3 {
+		t.Fatalf("Expected 3 dispatches, got %d", len(order))
+	}
+	if order[0] != "robot" {
+		t.Errorf("Expected small tenant to be dispatched first, got order %v", order)
+	}
+}
+
+func TestFairScheduler_WeightsFavorHeavierTenant(t *testing.T) {
+	s := New(1, map[string]float64{"gold": 4})
+
+	hold, err := s.Acquire(context.Background(), "x", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	r := &recorder{}
+	r.enqueue(t, s, "bronze", 8)
+	r.enqueue(t, s, "gold", 8)
+
+	hold()
+	order := r.wait()
+
+	if len(order) != 2 || order[0] != "gold" {
+		t.Errorf("Expected gold tenant first, got order %v", order)
+	}
+}
+
+func TestFairScheduler_ContextCancelled(t *testing.T) {
+	s := New(1, nil)
+
+	hold, err := s.Acquire(context.Background(), "a", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer hold()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := s.Acquire(ctx, "b", 1); err != context.DeadlineExceeded {
+		t.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	if s.Queued() != 0 {
+		t.Errorf("Expected cancelled waiter to be removed, got %d queued", s.Queued())
+	}
+}
+
+// recorder queues Acquire calls and records the order in which they are granted
+type recorder struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	order  []string
+	queued int
+}
+
+// enqueue starts an Acquire in the background and waits until it is queued
+func (r *recorder) enqueue(t *testing.T, s *FairScheduler, tenant string, cost int) {
+	t.Helper()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		release, err := s.Acquire(context.Background(), tenant, cost)
+		if err != nil {
+			t.Errorf("Acquire failed: %v", err)
+			return
+		}
+		r.mu.Lock()
+		r.order = append(r.order, tenant)
+		r.mu.Unlock()
+		release()
+	}()
+
+	r.queued++
+	deadline := time.Now().Add(time.Second)
+	for s.Queued() < r.queued {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %d queued batches", r.queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// wait blocks until all queued Acquire calls finish and returns the grant order
+func (r *recorder) wait() []string {
+	r.wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.order
+}
+
+func TestFairScheduler_CancelledWaiterRollsBack(t *testing.T) {
+	s := New(1, map[string]float64{"robots": 1})
+
+	hold, err := s.Acquire(context.Background(), "x", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	// A bulk batch that gives up must not charge its tenant
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(ctx, "bulk", 1000)
+		cancelled <- err
+	}()
+	for s.Queued() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-cancelled; err != context.Canceled {
+		t.Fatalf("Expected Canceled, got %v", err)
+	}
+	s.mu.Lock()
+	charged := s.lastFinish["bulk"]
+	s.mu.Unlock()
+	if charged != 0 {
+		t.Fatalf("Expected the cancelled batch's cost rolled back, got finish %g", charged)
+	}
+
+	r := &recorder{}
+	r.enqueue(t, s, "bulk", 2)
+	r.enqueue(t, s, "robots", 4)
+	hold()
+	if order := r.wait(); len(order) != 2 || order[0] != "bulk" {
+		t.Errorf("Expected bulk's small batch first once its cancelled one was forgotten, got order %v", order)
+	}
+}
+
+func TestFairScheduler_Label(t *testing.T) {
+	s := New(1, map[string]float64{"robots": 4})
+	if got := s.label("robots"); got != "robots" {
+		t.Errorf("Expected a weighted tenant to keep its label, got %q", got)
+	}
+	if got := s.label("tenant-from-anywhere"); got != OtherTenant {
+		t.Errorf("Expected %q for an unweighted tenant, got %q", OtherTenant, got)
+	}
+}
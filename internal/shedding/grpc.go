@@ -0,0 +1,74 @@
+// internal/shedding/grpc.go
+package shedding
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+)
+
+// errShed is returned to bulk requests while shedding; clients should back off
+var errShed = status.Error(codes.ResourceExhausted, "bulk traffic is being shed: error budget is burning too fast")
+
+// spendsBudget reports whether a call's error counts against the SLO. Errors
+// caused by the request itself, such as invalid arguments, do not.
+func spendsBudget(err error) bool {
+	switch status.Code(err) {
+	case codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// UnaryInterceptor rejects bulk requests while shedding and records the
+// outcome of every admitted request. It must run after the priority
+// interceptor.
+func (s *Shedder) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !s.Allow(middleware.GetPriority(ctx) == middleware.PriorityBulk) {
+			metrics.RecordLoadShed(info.FullMethod)
+			return nil, errShed
+		}
+		resp, err := handler(ctx, req)
+		s.Record(spendsBudget(err))
+		return resp, err
+	}
+}
+
+// StreamInterceptor is the streaming counterpart of UnaryInterceptor
+func (s *Shedder) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if !s.Allow(middleware.GetPriority(ss.Context()) == middleware.PriorityBulk) {
+			metrics.RecordLoadShed(info.FullMethod)
+			return errShed
+		}
+		err := handler(srv, ss)
+		s.Record(spendsBudget(err))
+		return err
+	}
+}
+
+// Register adds the shedding status endpoint to the admin API
+func (s *Shedder) Register(m *admin.Mux) {
+	m.HandleFunc("GET /admin/shedding", func(w http.ResponseWriter, r *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, s.Status())
+	})
+}
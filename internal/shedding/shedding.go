@@ -0,0 +1,204 @@
+// Package shedding sheds bulk traffic while the service's error budget is
+// burning fast, so robot control traffic keeps the capacity that is left.
+//
+// It follows the multiwindow burn-rate alerting from the Google SRE workbook:
+// the burn rate is the observed error rate divided by the error rate the SLO
+// allows, and bulk traffic is shed while both the short and the long window
+// burn faster than the policy's threshold. The long window keeps a brief spike
+// from triggering shedding; the short window ends it soon after errors stop.
+package shedding
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// bucketsPerShortWindow sets how finely outcomes are bucketed
+const bucketsPerShortWindow = 10
+
+// Policy decides when bulk traffic is shed
+type Policy struct {
+	// SLOTarget is the fraction of requests that must succeed, e.g. 0.999
+	SLOTarget float64
+	// ShortWindow and LongWindow are the burn-rate windows, e.g. 5m and 1h
+	ShortWindow time.Duration
+	LongWindow  time.Duration
+	// BurnRate is the threshold both windows must reach, e.g. 14.4 (2% of a
+	// 30-day budget spent in an hour)
+	BurnRate float64
+	// MinRequests is the fewest requests in a window for its burn rate to count
+	MinRequests int64
+}
+
+// bucket counts request outcomes over one slice of time
+type bucket struct {
+	index  int64 // Time slice the counts belong to
+	total  int64
+	errors int64
+}
+
+// Status is the shedder's current state, as served by the admin API
+type Status struct {
+	Shedding bool `json:"shedding"`
+	// Since is when shedding last started or stopped; zero if it never has
+	Since         time.Time `json:"since"`
+	ShortBurnRate float64   `json:"short_burn_rate"`
+	LongBurnRate  float64   `json:"long_burn_rate"`
+	ShedTotal     int64     `json:"shed_total"`
+
+	SLOTarget   float64 `json:"slo_target"`
+	ShortWindow string  `json:"short_window"`
+	LongWindow  string  `json:"long_window"`
+	BurnRate    float64 `json:"burn_rate_threshold"`
+}
+
+// Shedder tracks request outcomes against the SLO and decides whether bulk
+// traffic is admitted
+type Shedder struct {
+	policy Policy
+	width  time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   []bucket
+	shortBurn float64
+	longBurn  float64
+	since     time.Time
+
+	shedding atomic.Bool
+	shed     atomic.Int64
+}
+
+// New creates a Shedder applying policy
+func New(policy Policy) *Shedder {
+	width := policy.ShortWindow / bucketsPerShortWindow
+	if width <= 0 {
+		width = time.Second
+	}
+	n := int(policy.LongWindow/width) + 1
+	if n < bucketsPerShortWindow+1 {
+		n = bucketsPerShortWindow + 1
+	}
+	return &Shedder{
+		policy:  policy,
+		width:   width,
+		now:     time.Now,
+		buckets: make([]bucket, n),
+	}
+}
+
+// Record counts the outcome of an admitted request; failed reports whether it
+// spent error budget
+func (s *Shedder) Record(failed bool) {
+	index := s.now().UnixNano() / int64(s.width)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := &s.buckets[index%int64(len(s.buckets))]
+	if b.index != index {
+		*b = bucket{index: index}
+	}
+	b.total++
+	if failed {
+		b.errors++
+	}
+}
+
+// Allow reports whether a request of the given priority is admitted, counting
+// it as shed if not
+func (s *Shedder) Allow(bulk bool) bool {
+	if !bulk || !s.shedding.Load() {
+		return true
+	}
+	s.shed.Add(1)
+	return false
+}
+
+// Shedding reports whether bulk traffic is currently being shed
+func (s *Shedder) Shedding() bool {
+	return s.shedding.Load()
+}
+
+// Evaluate recomputes both windows' burn rates and starts or stops shedding
+func (s *Shedder) Evaluate() {
+	now := s.now()
+	index := now.UnixNano() / int64(s.width)
+
+	s.mu.Lock()
+	s.shortBurn = s.burnRateLocked(index, s.policy.ShortWindow)
+	s.longBurn = s.burnRateLocked(index, s.policy.LongWindow)
+	shed := s.shortBurn >= s.policy.BurnRate && s.longBurn >= s.policy.BurnRate
+	changed := shed != s.shedding.Load()
+	if changed {
+		s.since = now
+		s.shedding.Store(shed)
+	}
+	shortBurn, longBurn := s.shortBurn, s.longBurn
+	s.mu.Unlock()
+
+	metrics.SetLoadSheddingBurnRate("short", shortBurn)
+	metrics.SetLoadSheddingBurnRate("long", longBurn)
+	metrics.SetLoadShedding(shed)
+	if changed && shed {
+		slog.Warn("Error budget burning; shedding bulk traffic", "short_burn", shortBurn, "long_burn", longBurn)
+	} else if changed {
+		slog.Info("Error budget burn rate recovered; admitting bulk traffic", "short_burn", shortBurn)
+	}
+}
+
+// burnRateLocked returns the burn rate over the window ending in the bucket at
+// index, or 0 when the window has too few requests
+func (s *Shedder) burnRateLocked(index int64, window time.Duration) float64 {
+	oldest := index - int64(window/s.width) + 1
+	var total, errors int64
+	for _, b := range s.buckets {
+		if b.index >= oldest && b.index <= index {
+			total += b.total
+			errors += b.errors
+		}
+	}
+	if total == 0 || total < s.policy.MinRequests {
+		return 0
+	}
+	allowed := 1 - s.policy.SLOTarget
+	if allowed <= 0 {
+		return 0
+	}
+	return float64(errors) / float64(total) / allowed
+}
+
+// Run evaluates the burn rates every interval until ctx is done
+func (s *Shedder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Evaluate()
+		}
+	}
+}
+
+// Status returns the current shedding state and burn rates
+func (s *Shedder) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Status{
+		Shedding:      s.shedding.Load(),
+		Since:         s.since,
+		ShortBurnRate: s.shortBurn,
+		LongBurnRate:  s.longBurn,
+		ShedTotal:     s.shed.Load(),
+		SLOTarget:     s.policy.SLOTarget,
+		ShortWindow:   s.policy.ShortWindow.String(),
+		LongWindow:    s.policy.LongWindow.String(),
+		BurnRate:      s.policy.BurnRate,
+	}
+}
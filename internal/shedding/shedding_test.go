@@ -0,0 +1,104 @@
+// internal/shedding/shedding_test.go
+package shedding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+)
+
+func testShedder(now *time.Time) *Shedder {
+	s := New(Policy{
+		SLOTarget:   0.99,
+		ShortWindow: time.Minute,
+		LongWindow:  10 * time.Minute,
+		BurnRate:    10,
+		MinRequests: 10,
+	})
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestShedder_ShedsBulkWhileBothWindowsBurn(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := testShedder(&now)
+
+	// A brief spike: the short window burns but the long window holds
+	for i := 0; i < 500; i++ {
+		s.Record(false)
+	}
+	now = now.Add(9 * time.Minute)
+	for i := 0; i < 20; i++ {
+		s.Record(true)
+	}
+	s.Evaluate()
+	if !s.Allow(true) {
+		t.Fatalf("Expected bulk admitted while only the short window burns, got %+v", s.Status())
+	}
+
+	// Sustained errors: both windows burn past 10x (error rate > 10%)
+	for i := 0; i < 100; i++ {
+		s.Record(true)
+	}
+	s.Evaluate()
+	if s.Allow(true) {
+		t.Fatalf("Expected bulk shed, got %+v", s.Status())
+	}
+	if !s.Allow(false) {
+		t.Fatal("Expected interactive traffic admitted while shedding")
+	}
+
+	// Errors stop: the short window recovers and shedding ends
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 50; i++ {
+		s.Record(false)
+	}
+	s.Evaluate()
+	status := s.Status()
+	if status.Shedding || !s.Allow(true) {
+		t.Fatalf("Expected shedding to stop, got %+v", status)
+	}
+	if status.ShedTotal != 1 || status.LongBurnRate < 10 {
+		t.Fatalf("Expected 1 shed request and a long burn rate still high, got %+v", status)
+	}
+}
+
+func TestShedder_UnaryInterceptor(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := testShedder(&now)
+	interceptor := s.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	invalid := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad")
+	}
+
+	// Client errors do not spend the budget
+	for i := 0; i < 20; i++ {
+		interceptor(context.Background(), nil, info, invalid)
+	}
+	s.Evaluate()
+	if s.Status().Shedding {
+		t.Fatal("Expected no shedding from client errors")
+	}
+
+	for i := 0; i < 20; i++ {
+		interceptor(context.Background(), nil, info, failing)
+	}
+	s.Evaluate()
+	bulk := middleware.WithPriority(context.Background(), middleware.PriorityBulk)
+	if _, err := interceptor(bulk, nil, info, failing); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted for bulk traffic, got %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, info, invalid); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected interactive traffic to reach the handler, got %v", err)
+	}
+}
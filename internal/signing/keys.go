@@ -0,0 +1,77 @@
+// internal/signing/keys.go
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DirSource loads PEM-encoded (PKCS#8) ed25519 private keys from a directory.
+// Each "<key_id>.pem" file is one key. The active key is ActiveID if set,
+// otherwise the lexicographically greatest key ID, so date-stamped IDs such as
+// "2024-06-01" rotate simply by dropping a newer file into the directory
+// (e.g. from a KMS or secret-sync sidecar).
+type DirSource struct {
+	Dir      string
+	ActiveID string
+}
+
+// Load reads all keys in the directory
+func (d DirSource) Load() ([]Key, string, error) {
+	paths, err := filepath.Glob(filepath.Join(d.Dir, "*.pem"))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(paths) == 0 {
+		return nil, "", fmt.Errorf("no signing keys found in %s", d.Dir)
+	}
+	sort.Strings(paths)
+
+	keys := make([]Key, 0, len(paths))
+	for _, path := range paths {
+		priv, err := readPrivateKey(path)
+		if err != nil {
+			return nil, "", err
+		}
+		keys = append(keys, Key{
+			ID:         strings.TrimSuffix(filepath.Base(path), ".pem"),
+			PrivateKey: priv,
+		})
+	}
+
+	activeID := d.ActiveID
+	if activeID == "" {
+		activeID = keys[len(keys)-1].ID
+	}
+	return keys, activeID, nil
+}
+
+// readPrivateKey parses a PEM-encoded PKCS#8 ed25519 private key
+func readPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %s: %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an ed25519 key", path)
+	}
+	return priv, nil
+}
@@ -0,0 +1,155 @@
+// Package signing signs PlanResponses so robot-side safety monitors can detect tampering
+package signing
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Key is a named ed25519 signing key
+type Key struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+}
+
+// KeySource supplies the current set of signing keys and which one is active.
+// Implementations may read from disk, a secret store, or a KMS.
+type KeySource interface {
+	// Load returns all available keys and the ID of the key to sign with
+	Load() (keys []Key, activeID string, err error)
+}
+
+// Signer signs responses with the active key of its KeySource. Keys are reloaded
+// on demand so a new key can be rotated in without restarting the service.
+type Signer struct {
+	source KeySource
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	active Key
+	public map[string]ed25519.PublicKey
+}
+
+// New creates a Signer and loads the initial keys from source
+func New(source KeySource) (*Signer, error) {
+	s := &Signer{
+		source: source,
+		now:    time.Now,
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads keys from the source and switches to the current active key.
+// On error the previously loaded keys stay in use.
+func (s *Signer) Reload() error {
+	keys, activeID, err := s.source.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	public := make(map[string]ed25519.PublicKey, len(keys))
+	var active *Key
+	for i := range keys {
+		k := keys[i]
+		if len(k.PrivateKey) != ed25519.PrivateKeySize {
+			return fmt.Errorf("signing key %q has invalid size %d", k.ID, len(k.PrivateKey))
+		}
+		public[k.ID] = k.PrivateKey.Public().(ed25519.PublicKey)
+		if k.ID == activeID {
+			active = &keys[i]
+		}
+	}
+	if active == nil {
+		return fmt.Errorf("active signing key %q not found", activeID)
+	}
+
+	s.mu.Lock()
+	s.active = *active
+	s.public = public
+	s.mu.Unlock()
+	return nil
+}
+
+// ActiveKeyID returns the ID of the key currently used for signing
+func (s *Signer) ActiveKeyID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active.ID
+}
+
+// PublicKeys returns the public half of every loaded key, keyed by key ID.
+// Robots fetch these to verify signatures across a rotation.
+func (s *Signer) PublicKeys() map[string]ed25519.PublicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make(map[string]ed25519.PublicKey, len(s.public))
+	for id, k := range s.public {
+		keys[id] = k
+	}
+	return keys
+}
+
+// Sign attaches a signature over robotID, the current time, and resp.Action
+func (s *Signer) Sign(robotID uint64, resp *pb.PlanResponse) {
+	s.mu.RLock()
+	key := s.active
+	s.mu.RUnlock()
+
+	ts := s.now().UnixNano()
+	resp.Signature = &pb.ResponseSignature{
+		KeyId:             key.ID,
+		TimestampUnixNano: ts,
+		Signature:         ed25519.Sign(key.PrivateKey, Payload(robotID, ts, resp.Action)),
+	}
+}
+
+// Verify checks resp's signature for robotID against the given public key
+func Verify(pub ed25519.PublicKey, robotID uint64, resp *pb.PlanResponse) bool {
+	sig := resp.GetSignature()
+	if sig == nil {
+		return false
+	}
+	return ed25519.Verify(pub, Payload(robotID, sig.TimestampUnixNano, resp.Action), sig.Signature)
+}
+
+// Payload builds the canonical signed bytes: big-endian robot ID, timestamp,
+// and the IEEE-754 bits of each action value
+func Payload(robotID uint64, timestampUnixNano int64, action []float32) []byte {
+	buf := make([]byte, 16+4*len(action))
+	binary.BigEndian.PutUint64(buf[0:8], robotID)
+	binary.BigEndian.PutUint64(buf[8:16], uint64(timestampUnixNano))
+	for i, v := range action {
+		binary.BigEndian.PutUint32(buf[16+4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// KeysHandler serves the active key ID and all public keys as JSON so robots can
+// pick up new verification keys ahead of a rotation
+func (s *Signer) KeysHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := make(map[string]string)
+		for id, pub := range s.PublicKeys() {
+			keys[id] = base64.StdEncoding.EncodeToString(pub)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"active_key_id": s.ActiveKeyID(),
+			"keys":          keys,
+		})
+	})
+}
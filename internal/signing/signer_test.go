@@ -0,0 +1,103 @@
+// internal/signing/signer_test.go
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// writeKey generates an ed25519 key and writes it to dir/<id>.pem
+func writeKey(t *testing.T, dir, id string) ed25519.PublicKey {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey failed: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, id+".pem"), data, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return pub
+}
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	pub := writeKey(t, dir, "k1")
+
+	s, err := New(DirSource{Dir: dir})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	resp := &pb.PlanResponse{Action: []float32{0.1, -0.2, 0.3}, Safe: true}
+	s.Sign(42, resp)
+
+	if resp.Signature == nil || resp.Signature.KeyId != "k1" {
+		t.Fatalf("Expected signature from key k1, got %+v", resp.Signature)
+	}
+	if !Verify(pub, 42, resp) {
+		t.Fatal("Expected signature to verify")
+	}
+
+	// Signatures are bound to the robot ID and the exact action values
+	if Verify(pub, 43, resp) {
+		t.Error("Expected verification to fail for a different robot ID")
+	}
+	resp.Action[1] = 0.2
+	if Verify(pub, 42, resp) {
+		t.Error("Expected verification to fail for a tampered action")
+	}
+}
+
+func TestSigner_RotatesToNewestKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "2024-01-01")
+
+	s, err := New(DirSource{Dir: dir})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if s.ActiveKeyID() != "2024-01-01" {
+		t.Fatalf("Expected active key 2024-01-01, got %s", s.ActiveKeyID())
+	}
+
+	newPub := writeKey(t, dir, "2024-06-01")
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if s.ActiveKeyID() != "2024-06-01" {
+		t.Fatalf("Expected active key 2024-06-01, got %s", s.ActiveKeyID())
+	}
+
+	// Both keys stay published so in-flight responses remain verifiable
+	if len(s.PublicKeys()) != 2 {
+		t.Errorf("Expected 2 public keys, got %d", len(s.PublicKeys()))
+	}
+
+	resp := &pb.PlanResponse{Action: []float32{1, 2}}
+	s.Sign(7, resp)
+	if !Verify(newPub, 7, resp) {
+		t.Error("Expected signature from the rotated key to verify")
+	}
+}
+
+func TestSigner_MissingActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "k1")
+
+	if _, err := New(DirSource{Dir: dir, ActiveID: "k2"}); err == nil {
+		t.Fatal("Expected error for unknown active key")
+	}
+}
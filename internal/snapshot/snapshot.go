@@ -0,0 +1,189 @@
+// internal/snapshot/snapshot.go
+
+// Package snapshot periodically saves the service's in-memory per-robot
+// state to Redis and restores it on startup, so a pod crash does not reset
+// every robot's state at once. All sources are written together in one
+// checksummed value with a single SET, so a crash mid-save leaves the
+// previous snapshot intact rather than a mix of old and new state.
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Results counted in state_snapshots_total
+const (
+	ResultSaved    = "saved"
+	ResultFailed   = "failed"
+	ResultRestored = "restored"
+	ResultStale    = "stale"
+	ResultCorrupt  = "corrupt"
+)
+
+// version is the envelope format; snapshots of other versions are ignored
+const version = 1
+
+// Source is in-memory per-robot state that is snapshotted
+type Source interface {
+	// Name identifies the source's state in the snapshot
+	Name() string
+	// Snapshot encodes the source's current state
+	Snapshot() ([]byte, error)
+	// Restore loads state encoded by Snapshot
+	Restore(data []byte) error
+}
+
+// Store holds snapshots; implemented by *cache.Cache
+type Store interface {
+	Set(key, value string, ttl time.Duration) error
+	Get(key string) (string, error)
+}
+
+// envelope is the stored snapshot
+type envelope struct {
+	Version int               `json:"version"`
+	TakenAt time.Time         `json:"taken_at"`
+	Sources map[string][]byte `json:"sources"`
+	// Checksum is the SHA-256 of the sources, by name
+	Checksum string `json:"checksum"`
+}
+
+// checksum hashes the sources in name order
+func checksum(sources map[string][]byte) string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s:%d:", name, len(sources[name]))
+		h.Write(sources[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Snapshotter saves its sources to key every interval. Snapshots older than
+// maxAge are not restored, and expire from the store after it.
+type Snapshotter struct {
+	store    Store
+	key      string
+	interval time.Duration
+	maxAge   time.Duration
+	sources  []Source
+
+	// mu serializes saves, so a slow periodic save cannot overwrite the
+	// final one taken at shutdown
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// New creates a Snapshotter saving sources to key in store
+func New(store Store, key string, interval, maxAge time.Duration, sources ...Source) *Snapshotter {
+	return &Snapshotter{
+		store:    store,
+		key:      key,
+		interval: interval,
+		maxAge:   maxAge,
+		sources:  sources,
+		now:      time.Now,
+	}
+}
+
+// Key returns the key snapshots are stored under
+func (s *Snapshotter) Key() string { return s.key }
+
+// Save snapshots every source and stores them together
+func (s *Snapshotter) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env := envelope{Version: version, TakenAt: s.now(), Sources: make(map[string][]byte, len(s.sources))}
+	for _, src := range s.sources {
+		data, err := src.Snapshot()
+		if err != nil {
+			metrics.RecordStateSnapshot(ResultFailed)
+			return fmt.Errorf("failed to snapshot %s: %w", src.Name(), err)
+		}
+		env.Sources[src.Name()] = data
+	}
+	env.Checksum = checksum(env.Sources)
+
+	value, err := json.Marshal(env)
+	if err != nil {
+		metrics.RecordStateSnapshot(ResultFailed)
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := s.store.Set(s.key, string(value), s.maxAge); err != nil {
+		metrics.RecordStateSnapshot(ResultFailed)
+		return fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	metrics.RecordStateSnapshot(ResultSaved)
+	metrics.SetStateSnapshotBytes(len(value))
+	return nil
+}
+
+// Restore loads the stored snapshot into the sources, returning when it was
+// taken, or the zero time if there was none to restore. A snapshot that is
+// older than maxAge, fails its checksum, or was written in another format
+// is skipped, leaving every source empty.
+func (s *Snapshotter) Restore() (time.Time, error) {
+	value, err := s.store.Get(s.key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	var env envelope
+	if err := json.Unmarshal([]byte(value), &env); err != nil || env.Version != version || checksum(env.Sources) != env.Checksum {
+		metrics.RecordStateSnapshot(ResultCorrupt)
+		return time.Time{}, fmt.Errorf("snapshot at %s is corrupt or from another version; starting empty", s.key)
+	}
+	if age := s.now().Sub(env.TakenAt); s.maxAge > 0 && age > s.maxAge {
+		metrics.RecordStateSnapshot(ResultStale)
+		return time.Time{}, nil
+	}
+
+	for _, src := range s.sources {
+		data, ok := env.Sources[src.Name()]
+		if !ok {
+			continue
+		}
+		if err := src.Restore(data); err != nil {
+			metrics.RecordStateSnapshot(ResultCorrupt)
+			return time.Time{}, fmt.Errorf("failed to restore %s: %w", src.Name(), err)
+		}
+	}
+	metrics.RecordStateSnapshot(ResultRestored)
+	return env.TakenAt, nil
+}
+
+// Run saves a snapshot every interval until ctx is done. The final snapshot
+// is left to the caller, once no more requests change the state.
+func (s *Snapshotter) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Save(); err != nil {
+				slog.Warn("Failed to save state snapshot", "key", s.Key(), "error", err)
+			}
+		}
+	}
+}
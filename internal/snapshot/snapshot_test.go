@@ -0,0 +1,124 @@
+// internal/snapshot/snapshot_test.go
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mapStore is an in-memory Store
+type mapStore map[string]string
+
+func (m mapStore) Set(key, value string, ttl time.Duration) error {
+	m[key] = value
+	return nil
+}
+
+func (m mapStore) Get(key string) (string, error) {
+	return m[key], nil
+}
+
+// stringSource is a Source holding one string
+type stringSource struct {
+	name  string
+	value string
+	fail  bool
+}
+
+func (s *stringSource) Name() string { return s.name }
+
+func (s *stringSource) Snapshot() ([]byte, error) {
+	if s.fail {
+		return nil, fmt.Errorf("broken")
+	}
+	return []byte(s.value), nil
+}
+
+func (s *stringSource) Restore(data []byte) error {
+	s.value = string(data)
+	return nil
+}
+
+func TestSnapshotter_SaveRestore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := mapStore{}
+	a, b := &stringSource{name: "a", value: "one"}, &stringSource{name: "b", value: "two"}
+	s := New(store, "state:r1", time.Second, time.Minute, a, b)
+	s.now = func() time.Time { return now }
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	a2, b2 := &stringSource{name: "a"}, &stringSource{name: "b"}
+	restored := New(store, "state:r1", time.Second, time.Minute, a2, b2)
+	restored.now = func() time.Time { return now.Add(30 * time.Second) }
+	taken, err := restored.Restore()
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if !taken.Equal(now) {
+		t.Errorf("Expected snapshot taken at %s, got %s", now, taken)
+	}
+	if a2.value != "one" || b2.value != "two" {
+		t.Errorf("Expected restored values one and two, got %q and %q", a2.value, b2.value)
+	}
+}
+
+func TestSnapshotter_FailedSaveKeepsPrevious(t *testing.T) {
+	store := mapStore{}
+	a := &stringSource{name: "a", value: "one"}
+	s := New(store, "state", time.Second, time.Minute, a)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved := store["state"]
+
+	a.value, a.fail = "two", true
+	if err := s.Save(); err == nil {
+		t.Fatal("Expected Save to fail when a source fails")
+	}
+	if store["state"] != saved {
+		t.Error("Expected a failed save to leave the previous snapshot")
+	}
+}
+
+func TestSnapshotter_SkipsUnusableSnapshots(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name    string
+		value   func(saved string) string
+		after   time.Duration
+		wantErr bool
+	}{
+		{name: "missing", value: func(string) string { return "" }},
+		{name: "stale", value: func(saved string) string { return saved }, after: 2 * time.Minute},
+		{name: "truncated", value: func(saved string) string { return saved[:len(saved)/2] }, wantErr: true},
+		{name: "tampered", value: func(saved string) string { return strings.Replace(saved, "b25l", "dHdv", 1) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mapStore{}
+			s := New(store, "state", time.Second, time.Minute, &stringSource{name: "a", value: "one"})
+			s.now = func() time.Time { return now }
+			if err := s.Save(); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+			store["state"] = tt.value(store["state"])
+
+			a := &stringSource{name: "a"}
+			restored := New(store, "state", time.Second, time.Minute, a)
+			restored.now = func() time.Time { return now.Add(tt.after) }
+			taken, err := restored.Restore()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !taken.IsZero() || a.value != "" {
+				t.Errorf("Expected nothing restored, got %q taken at %s", a.value, taken)
+			}
+		})
+	}
+}
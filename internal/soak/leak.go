@@ -0,0 +1,114 @@
+// Package soak runs long-lived load against the service and detects resource leaks
+package soak
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LeakDetector flags resource series that grow steadily over a soak run.
+// A series is leaking when its samples trend upward consistently (Kendall tau
+// of at least MinTrend) and its level grew by at least MinGrowth relative to
+// the start of the run. Requiring both avoids flagging noisy but flat series
+// and small monotonic drifts such as a warming cache.
+type LeakDetector struct {
+	// MinTrend is the Kendall rank correlation (0..1) with time required to flag growth
+	MinTrend float64
+	// MinGrowth is the relative growth between the first and last quarter of the run
+	MinGrowth float64
+	// MinSamples is the number of samples required before a verdict is given
+	MinSamples int
+}
+
+// DefaultLeakDetector returns thresholds suited to multi-hour runs
+func DefaultLeakDetector() LeakDetector {
+	return LeakDetector{
+		MinTrend:   0.7,
+		MinGrowth:  0.2,
+		MinSamples: 20,
+	}
+}
+
+// Verdict is the outcome of checking one series
+type Verdict struct {
+	Name    string
+	Samples int
+	First   float64
+	Last    float64
+	Trend   float64
+	Growth  float64
+	Leaking bool
+}
+
+// String formats the verdict as a single report line
+func (v Verdict) String() string {
+	state := "ok"
+	if v.Leaking {
+		state = "LEAK"
+	}
+	return fmt.Sprintf("%-32s %-5s samples=%d first=%.0f last=%.0f trend=%.2f growth=%.1f%%",
+		v.Name, state, v.Samples, v.First, v.Last, v.Trend, 100*v.Growth)
+}
+
+// Check evaluates a series of samples taken at a fixed interval
+func (d LeakDetector) Check(name string, values []float64) Verdict {
+	v := Verdict{Name: name, Samples: len(values)}
+	if len(values) == 0 {
+		return v
+	}
+	v.First = values[0]
+	v.Last = values[len(values)-1]
+	if len(values) < d.MinSamples || len(values) < 4 {
+		return v
+	}
+
+	quarter := len(values) / 4
+	start := median(values[:quarter])
+	end := median(values[len(values)-quarter:])
+	base := start
+	if base < 1 {
+		base = 1
+	}
+
+	v.Trend = kendallTau(values)
+	v.Growth = (end - start) / base
+	v.Leaking = v.Trend >= d.MinTrend && v.Growth >= d.MinGrowth
+	return v
+}
+
+// kendallTau returns the Kendall rank correlation between sample order and value
+func kendallTau(values []float64) float64 {
+	var concordant, discordant int
+	for i := 0; i < len(values); i++ {
+		for j := i + 1; j < len(values); j++ {
+			switch {
+			case values[j] > values[i]:
+				concordant++
+			case values[j] < values[i]:
+				discordant++
+			}
+		}
+	}
+
+	n := len(values)
+	pairs := n * (n - 1) / 2
+	if pairs == 0 {
+		return 0
+	}
+	return float64(concordant-discordant) / float64(pairs)
+}
+
+// median returns the median of values without modifying them
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,74 @@
+// internal/soak/leak_test.go
+package soak
+
+import (
+	"bufio"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLeakDetector_FlagsSteadyGrowth(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		// Linear growth with a little jitter
+		values[i] = 1000 + float64(i)*20 + 5*math.Sin(float64(i))
+	}
+
+	v := DefaultLeakDetector().Check("rss", values)
+	if !v.Leaking {
+		t.Errorf("Expected leak to be detected, got %s", v)
+	}
+}
+
+func TestLeakDetector_IgnoresNoisyFlatSeries(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = 1000 + 200*math.Sin(float64(i)/3)
+	}
+
+	v := DefaultLeakDetector().Check("goroutines", values)
+	if v.Leaking {
+		t.Errorf("Expected no leak for a flat series, got %s", v)
+	}
+}
+
+func TestLeakDetector_IgnoresSmallMonotonicDrift(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		// Grows every sample but only ~5% overall
+		values[i] = 1000 + float64(i)*0.5
+	}
+
+	v := DefaultLeakDetector().Check("heap", values)
+	if v.Leaking {
+		t.Errorf("Expected small drift not to be flagged, got %s", v)
+	}
+}
+
+func TestLeakDetector_TooFewSamples(t *testing.T) {
+	v := DefaultLeakDetector().Check("fds", []float64{1, 2, 3, 4, 5})
+	if v.Leaking {
+		t.Error("Expected no verdict with too few samples")
+	}
+}
+
+func TestParseUnlabeled(t *testing.T) {
+	text := `# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 42
+process_resident_memory_bytes 1.048576e+07
+grpc_server_handling_seconds_count{code="OK",method="/planner.PathPlanner/Plan"} 10
+`
+	values := parseUnlabeled(bufio.NewScanner(strings.NewReader(text)))
+
+	if values[SeriesGoroutines] != 42 {
+		t.Errorf("Expected 42 goroutines, got %v", values[SeriesGoroutines])
+	}
+	if values[SeriesRSS] != 10485760 {
+		t.Errorf("Expected RSS 10485760, got %v", values[SeriesRSS])
+	}
+	if len(values) != 2 {
+		t.Errorf("Expected labeled series to be skipped, got %v", values)
+	}
+}
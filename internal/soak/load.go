@@ -0,0 +1,119 @@
+// internal/soak/load.go
+package soak
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// LoadConfig describes the mixed workload sent during a soak run
+type LoadConfig struct {
+	// Concurrency is the number of concurrent client workers
+	Concurrency int
+	// Channels, Height, and Width are the observation dimensions
+	Channels, Height, Width uint32
+	// MaxBatch is the largest BatchPlan size sent
+	MaxBatch int
+	// BatchRatio is the fraction of calls sent as BatchPlan instead of Plan
+	BatchRatio float64
+	// InvalidRatio is the fraction of calls deliberately malformed to exercise error paths
+	InvalidRatio float64
+	// Timeout is the per-call deadline
+	Timeout time.Duration
+}
+
+// LoadStats counts calls made by the load generator
+type LoadStats struct {
+	Plans      atomic.Int64
+	Batches    atomic.Int64
+	Invalid    atomic.Int64
+	Errors     atomic.Int64
+	Unexpected atomic.Int64
+}
+
+// RunLoad drives mixed Plan/BatchPlan traffic until ctx is done
+func RunLoad(ctx context.Context, client pb.PathPlannerClient, cfg LoadConfig, stats *LoadStats) {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxBatch <= 0 {
+		cfg.MaxBatch = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for ctx.Err() == nil {
+				callOnce(ctx, client, cfg, stats, rng)
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+}
+
+// callOnce sends one randomly chosen request and records its outcome
+func callOnce(ctx context.Context, client pb.PathPlannerClient, cfg LoadConfig, stats *LoadStats, rng *rand.Rand) {
+	callCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	invalid := rng.Float64() < cfg.InvalidRatio
+
+	var err error
+	if rng.Float64() < cfg.BatchRatio {
+		batch := 1 + rng.Intn(cfg.MaxBatch)
+		req := &pb.BatchPlanRequest{Requests: make([]*pb.PlanRequest, batch)}
+		for i := range req.Requests {
+			req.Requests[i] = randomRequest(cfg, rng, invalid && i == batch-1)
+		}
+		_, err = client.BatchPlan(callCtx, req)
+		stats.Batches.Add(1)
+	} else {
+		_, err = client.Plan(callCtx, randomRequest(cfg, rng, invalid))
+		stats.Plans.Add(1)
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		// Shutting down; ignore calls cut short by the end of the run
+	case invalid && status.Code(err) == codes.InvalidArgument:
+		stats.Invalid.Add(1)
+	case invalid:
+		stats.Unexpected.Add(1)
+	case err != nil:
+		stats.Errors.Add(1)
+	}
+}
+
+// randomRequest builds a PlanRequest with random data, truncated when invalid
+func randomRequest(cfg LoadConfig, rng *rand.Rand, invalid bool) *pb.PlanRequest {
+	n := int(cfg.Channels * cfg.Height * cfg.Width)
+	if invalid && n > 1 {
+		n--
+	}
+
+	data := make([]float32, n)
+	for i := range data {
+		data[i] = rng.Float32()
+	}
+
+	return &pb.PlanRequest{
+		RobotId: uint64(rng.Intn(1000)),
+		Obs: &pb.Observation{
+			Data:     data,
+			Channels: cfg.Channels,
+			Height:   cfg.Height,
+			Width:    cfg.Width,
+		},
+	}
+}
@@ -0,0 +1,148 @@
+// internal/soak/sampler.go
+package soak
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resource series tracked during a soak run. They come from the Go and process
+// collectors that the Prometheus client registers by default.
+const (
+	SeriesGoroutines = "go_goroutines"
+	SeriesRSS        = "process_resident_memory_bytes"
+	SeriesOpenFDs    = "process_open_fds"
+	SeriesGoHeap     = "go_memstats_heap_inuse_bytes"
+	SeriesGoSys      = "go_memstats_sys_bytes"
+
+	// SeriesNativeMemory approximates memory held outside the Go runtime
+	// (mostly ONNX Runtime tensors and arenas) as RSS minus Go runtime memory
+	SeriesNativeMemory = "native_memory_bytes"
+)
+
+// trackedSeries are the series checked for leaks, in report order
+var trackedSeries = []string{SeriesGoroutines, SeriesRSS, SeriesOpenFDs, SeriesGoHeap, SeriesNativeMemory}
+
+// Sampler periodically scrapes the service's /metrics endpoint
+type Sampler struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	samples map[string][]float64
+}
+
+// NewSampler creates a Sampler for the given metrics URL
+func NewSampler(metricsURL string) *Sampler {
+	return &Sampler{
+		url:     metricsURL,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		samples: make(map[string][]float64),
+	}
+}
+
+// Run scrapes every interval until ctx is done. Scrape failures are reported
+// through onError and skipped.
+func (s *Sampler) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sample(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sample performs a single scrape and records the tracked series
+func (s *Sampler) Sample(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to scrape %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to scrape %s: status %d", s.url, resp.StatusCode)
+	}
+
+	values := parseUnlabeled(bufio.NewScanner(resp.Body))
+	if rss, ok := values[SeriesRSS]; ok {
+		if sys, ok := values[SeriesGoSys]; ok {
+			values[SeriesNativeMemory] = rss - sys
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range trackedSeries {
+		if v, ok := values[name]; ok {
+			s.samples[name] = append(s.samples[name], v)
+		}
+	}
+	return nil
+}
+
+// Samples returns a copy of the recorded samples for a series
+func (s *Sampler) Samples(name string) []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]float64(nil), s.samples[name]...)
+}
+
+// Evaluate checks every tracked series, discarding the first skip samples (warmup)
+func (s *Sampler) Evaluate(d LeakDetector, skip int) []Verdict {
+	verdicts := make([]Verdict, 0, len(trackedSeries))
+	for _, name := range trackedSeries {
+		values := s.Samples(name)
+		if len(values) == 0 {
+			continue
+		}
+		if skip < len(values) {
+			values = values[skip:]
+		} else {
+			values = nil
+		}
+		verdicts = append(verdicts, d.Check(name, values))
+	}
+	return verdicts
+}
+
+// parseUnlabeled extracts unlabeled samples from the Prometheus text format
+func parseUnlabeled(scanner *bufio.Scanner) map[string]float64 {
+	values := make(map[string]float64)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") || strings.Contains(line, "{") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		values[fields[0]] = v
+	}
+	return values
+}
@@ -0,0 +1,209 @@
+// Package streams tracks open server streams and reaps those idle beyond a
+// bound, so a robot that vanished without closing its connection does not hold
+// a stream, and the state tied to it, until TCP keepalive notices.
+//
+// A reaped stream ends with codes.Unavailable, the status a GOAWAY produces,
+// so well-behaved clients reconnect rather than treat it as a failed call.
+package streams
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// errReaped is the cancellation cause of a reaped stream's context
+var errReaped = errors.New("stream reaped")
+
+// stream is one open server stream
+type stream struct {
+	method  string
+	started time.Time
+	// lastActive is the UnixNano time a message was last received or sent
+	lastActive atomic.Int64
+	cancel     context.CancelCauseFunc
+
+	mu     sync.Mutex
+	robots []uint64
+}
+
+// Tracker tracks open streams and reaps idle ones
+type Tracker struct {
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu     sync.Mutex
+	open   map[*stream]struct{}
+	onReap []func(robotIDs []uint64)
+}
+
+// New creates a Tracker reaping streams without a message received or sent
+// for idleTimeout; 0 only tracks them
+func New(idleTimeout time.Duration) *Tracker {
+	return &Tracker{
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		open:        make(map[*stream]struct{}),
+	}
+}
+
+// OnReap registers f to release the per-robot state of the robots planned on
+// a reaped stream
+func (t *Tracker) OnReap(f func(robotIDs []uint64)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onReap = append(t.onReap, f)
+}
+
+// Len returns the number of open streams
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.open)
+}
+
+// Run reaps idle streams every interval until ctx is done
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	if t.idleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Reap()
+		}
+	}
+}
+
+// Reap cancels the streams idle for longer than the idle timeout and returns
+// how many it reaped
+func (t *Tracker) Reap() int {
+	if t.idleTimeout <= 0 {
+		return 0
+	}
+	cutoff := t.now().Add(-t.idleTimeout).UnixNano()
+
+	t.mu.Lock()
+	var idle []*stream
+	for s := range t.open {
+		if s.lastActive.Load() < cutoff {
+			idle = append(idle, s)
+			delete(t.open, s)
+		}
+	}
+	hooks := t.onReap
+	t.mu.Unlock()
+
+	for _, s := range idle {
+		s.cancel(errReaped)
+		metrics.RecordStreamReaped(s.method)
+
+		s.mu.Lock()
+		robots := s.robots
+		s.mu.Unlock()
+		if len(robots) > 0 {
+			for _, f := range hooks {
+				f(robots)
+			}
+		}
+	}
+	return len(idle)
+}
+
+// StreamInterceptor tracks each stream's activity until it ends, exporting
+// the number open and their age. Reaped streams end with codes.Unavailable.
+func (t *Tracker) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, cancel := context.WithCancelCause(ss.Context())
+		s := &stream{method: info.FullMethod, started: t.now(), cancel: cancel}
+		s.lastActive.Store(s.started.UnixNano())
+
+		t.mu.Lock()
+		t.open[s] = struct{}{}
+		t.mu.Unlock()
+		metrics.AddActiveStreams(info.FullMethod, 1)
+
+		err := handler(srv, &trackedStream{ServerStream: ss, ctx: ctx, stream: s, now: t.now})
+
+		t.mu.Lock()
+		delete(t.open, s)
+		t.mu.Unlock()
+		metrics.AddActiveStreams(info.FullMethod, -1)
+		metrics.RecordStreamAge(info.FullMethod, t.now().Sub(s.started).Seconds())
+
+		reaped := errors.Is(context.Cause(ctx), errReaped)
+		cancel(nil)
+		if reaped {
+			return status.Errorf(codes.Unavailable, "stream idle for more than %s; reconnect", t.idleTimeout)
+		}
+		return err
+	}
+}
+
+// trackedStream records message activity and the robots planned on a stream,
+// and carries the context cancelled when the stream is reaped
+type trackedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	stream *stream
+	now    func() time.Time
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *trackedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.stream.lastActive.Store(s.now().UnixNano())
+		var robots []uint64
+		switch r := m.(type) {
+		case *pb.BatchPlanStreamRequest:
+			robots = make([]uint64, 0, len(r.GetRequests()))
+			for _, req := range r.GetRequests() {
+				robots = append(robots, req.GetRobotId())
+			}
+		case *pb.StreamPlanRequest:
+			robots = []uint64{r.GetRequest().GetRobotId()}
+		}
+		if len(robots) > 0 {
+			// StreamPlan sends the same robot on every message; keep it once
+			s.stream.mu.Lock()
+			for _, robotID := range robots {
+				if !slices.Contains(s.stream.robots, robotID) {
+					s.stream.robots = append(s.stream.robots, robotID)
+				}
+			}
+			s.stream.mu.Unlock()
+		}
+	}
+	return err
+}
+
+func (s *trackedStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.stream.lastActive.Store(s.now().UnixNano())
+	}
+	return err
+}
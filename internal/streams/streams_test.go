@@ -0,0 +1,90 @@
+// internal/streams/streams_test.go
+package streams
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// fakeStream delivers one request and accepts any sends
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func (s *fakeStream) SendMsg(m interface{}) error { return nil }
+
+func TestTracker_ReapsIdleStreams(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tracker := New(time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	var reaped []uint64
+	tracker.OnReap(func(robotIDs []uint64) { reaped = append(reaped, robotIDs...) })
+
+	req := &pb.BatchPlanStreamRequest{Requests: []*pb.PlanRequest{{RobotId: 7}, {RobotId: 9}}}
+	received := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.StreamInterceptor()(nil, &fakeStream{ctx: context.Background(), req: req},
+			&grpc.StreamServerInfo{FullMethod: "/planner.PathPlanner/BatchPlanStream"},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				if err := ss.RecvMsg(&pb.BatchPlanStreamRequest{}); err != nil {
+					return err
+				}
+				close(received)
+				<-ss.Context().Done()
+				return ss.Context().Err()
+			})
+	}()
+	<-received
+
+	if n := tracker.Reap(); n != 0 || tracker.Len() != 1 {
+		t.Fatalf("Expected an active stream to be kept, reaped %d", n)
+	}
+	now = now.Add(2 * time.Minute)
+	if n := tracker.Reap(); n != 1 {
+		t.Fatalf("Expected the idle stream to be reaped, reaped %d", n)
+	}
+
+	err := <-done
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable for a reaped stream, got %v", err)
+	}
+	if len(reaped) != 2 || reaped[0] != 7 || reaped[1] != 9 {
+		t.Errorf("Expected the stream's robots to be released, got %v", reaped)
+	}
+	if tracker.Len() != 0 {
+		t.Errorf("Expected no open streams, got %d", tracker.Len())
+	}
+}
+
+func TestTracker_PassesThroughErrors(t *testing.T) {
+	tracker := New(0)
+	want := status.Error(codes.InvalidArgument, "bad request")
+	err := tracker.StreamInterceptor()(nil, &fakeStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/planner.PathPlanner/WatchPlanResults"},
+		func(srv interface{}, ss grpc.ServerStream) error { return want })
+	if err != want {
+		t.Fatalf("Expected the handler's error, got %v", err)
+	}
+	if tracker.Reap() != 0 {
+		t.Error("Expected no reaping without an idle timeout")
+	}
+}
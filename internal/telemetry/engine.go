@@ -0,0 +1,33 @@
+// internal/telemetry/engine.go
+package telemetry
+
+import (
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+)
+
+// engine records every batch served by the wrapped engine under a model name
+type engine struct {
+	inference.InferenceEngine
+	model     string
+	collector *Collector
+}
+
+// Instrument wraps e so its batches are reported to c as model
+func Instrument(e inference.InferenceEngine, model string, c *Collector) inference.InferenceEngine {
+	return &engine{InferenceEngine: e, model: model, collector: c}
+}
+
+// Unwrap implements inference.Wrapper
+func (e *engine) Unwrap() inference.InferenceEngine {
+	return e.InferenceEngine
+}
+
+// Predict implements inference.InferenceEngine
+func (e *engine) Predict(obsBatch [][]float32, c, h, w int64) ([]float32, error) {
+	start := time.Now()
+	actions, err := e.InferenceEngine.Predict(obsBatch, c, h, w)
+	e.collector.RecordInference(e.model, len(obsBatch), time.Since(start), err)
+	return actions, err
+}
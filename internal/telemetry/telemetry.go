@@ -0,0 +1,235 @@
+// Package telemetry keeps a downsampled, read-only snapshot of fleet activity,
+// per-model throughput and latency, and health for lightweight dashboards
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxSamples bounds the latency samples kept per model between snapshots
+const maxSamples = 1024
+
+// Snapshot is the JSON document served to dashboards
+type Snapshot struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Healthy   bool      `json:"healthy"`
+	Fleet     Fleet     `json:"fleet"`
+	// QPS is plan requests (observations) per second across all models
+	QPS    float64      `json:"qps"`
+	Models []ModelStats `json:"models"`
+}
+
+// Fleet counts the robots talking to this instance
+type Fleet struct {
+	// ActiveRobots is the number of distinct robots seen within WindowSeconds
+	ActiveRobots  int     `json:"active_robots"`
+	WindowSeconds float64 `json:"window_seconds"`
+}
+
+// ModelStats summarizes one model over the last snapshot interval
+type ModelStats struct {
+	Model        string  `json:"model"`
+	QPS          float64 `json:"qps"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+	ErrorRate    float64 `json:"error_rate"`
+}
+
+// modelWindow accumulates one model's traffic between snapshots
+type modelWindow struct {
+	requests int64
+	batches  int64
+	errors   int64
+	samples  []float64
+	lastSeen time.Time
+}
+
+// Collector records traffic and periodically folds it into a Snapshot
+type Collector struct {
+	interval     time.Duration
+	activeWindow time.Duration
+	healthy      func() bool
+	now          func() time.Time
+
+	mu         sync.Mutex
+	robots     map[uint64]time.Time
+	models     map[string]*modelWindow
+	lastUpdate time.Time
+
+	snapshotMu sync.RWMutex
+	snapshot   []byte
+}
+
+// NewCollector creates a Collector that publishes a snapshot every interval.
+// Robots and models idle for longer than activeWindow drop out of the snapshot;
+// healthy reports the serving status and may be nil.
+func NewCollector(interval, activeWindow time.Duration, healthy func() bool) *Collector {
+	c := &Collector{
+		interval:     interval,
+		activeWindow: activeWindow,
+		healthy:      healthy,
+		now:          time.Now,
+		robots:       make(map[uint64]time.Time),
+		models:       make(map[string]*modelWindow),
+	}
+	c.lastUpdate = c.now()
+	return c
+}
+
+// RecordRobots marks robots as active
+func (c *Collector) RecordRobots(robotIDs []uint64) {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range robotIDs {
+		c.robots[id] = now
+	}
+}
+
+// RecordInference records one inference batch of the given size for model
+func (c *Collector) RecordInference(model string, batchSize int, latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.models[model]
+	if !ok {
+		m = &modelWindow{}
+		c.models[model] = m
+	}
+	m.requests += int64(batchSize)
+	m.batches++
+	if err != nil {
+		m.errors++
+	}
+	m.lastSeen = c.now()
+
+	// Reservoir sampling keeps the percentile unbiased under heavy traffic
+	ms := float64(latency.Microseconds()) / 1000.0
+	if len(m.samples) < maxSamples {
+		m.samples = append(m.samples, ms)
+	} else if j := rand.Int63n(m.batches); j < maxSamples {
+		m.samples[j] = ms
+	}
+}
+
+// Run publishes a snapshot immediately and then every interval until ctx is done
+func (c *Collector) Run(ctx context.Context) {
+	c.update()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.update()
+		}
+	}
+}
+
+// update folds the traffic recorded since the last update into a new snapshot
+func (c *Collector) update() {
+	snap := c.collect()
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return
+	}
+
+	c.snapshotMu.Lock()
+	c.snapshot = data
+	c.snapshotMu.Unlock()
+}
+
+func (c *Collector) collect() Snapshot {
+	now := c.now()
+	snap := Snapshot{
+		UpdatedAt: now,
+		Healthy:   c.healthy == nil || c.healthy(),
+		Fleet:     Fleet{WindowSeconds: c.activeWindow.Seconds()},
+		Models:    []ModelStats{},
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := now.Sub(c.lastUpdate).Seconds()
+	c.lastUpdate = now
+
+	for id, seen := range c.robots {
+		if now.Sub(seen) > c.activeWindow {
+			delete(c.robots, id)
+		}
+	}
+	snap.Fleet.ActiveRobots = len(c.robots)
+
+	for name, m := range c.models {
+		if now.Sub(m.lastSeen) > c.activeWindow {
+			delete(c.models, name)
+			continue
+		}
+
+		stats := ModelStats{Model: name, P95LatencyMs: percentile(m.samples, 0.95)}
+		if elapsed > 0 {
+			stats.QPS = float64(m.requests) / elapsed
+		}
+		if m.batches > 0 {
+			stats.ErrorRate = float64(m.errors) / float64(m.batches)
+		}
+		snap.QPS += stats.QPS
+		snap.Models = append(snap.Models, stats)
+
+		*m = modelWindow{lastSeen: m.lastSeen, samples: m.samples[:0]}
+	}
+	sort.Slice(snap.Models, func(i, j int) bool { return snap.Models[i].Model < snap.Models[j].Model })
+
+	return snap
+}
+
+// percentile returns the p-th percentile of samples (nearest rank), or 0 if empty.
+// samples is sorted in place.
+func percentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sort.Float64s(samples)
+	idx := int(p*float64(len(samples))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(samples) {
+		idx = len(samples) - 1
+	}
+	return samples[idx]
+}
+
+// Handler serves the latest snapshot as JSON. It needs no credentials and
+// allows cross-origin reads so browser dashboards can poll it directly.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		c.snapshotMu.RLock()
+		data := c.snapshot
+		c.snapshotMu.RUnlock()
+		if data == nil {
+			http.Error(w, "telemetry not collected yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Write(data)
+	})
+}
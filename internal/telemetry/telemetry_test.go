@@ -0,0 +1,99 @@
+// internal/telemetry/telemetry_test.go
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+)
+
+func TestCollector_Snapshot(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	healthy := true
+	c := NewCollector(5*time.Second, time.Minute, func() bool { return healthy })
+	c.now = func() time.Time { return now }
+	c.lastUpdate = now
+
+	c.RecordRobots([]uint64{1, 2, 3})
+	c.RecordRobots([]uint64{3})
+	for i := 1; i <= 20; i++ {
+		c.RecordInference("policy_v1.onnx", 2, time.Duration(i)*time.Millisecond, nil)
+	}
+	c.RecordInference("policy_v2.onnx", 1, time.Millisecond, errors.New("boom"))
+
+	now = now.Add(5 * time.Second)
+	snap := c.collect()
+
+	if !snap.Healthy {
+		t.Errorf("Expected healthy snapshot")
+	}
+	if snap.Fleet.ActiveRobots != 3 {
+		t.Errorf("Expected 3 active robots, got %d", snap.Fleet.ActiveRobots)
+	}
+	if snap.QPS != 41.0/5 {
+		t.Errorf("Expected total QPS %v, got %v", 41.0/5, snap.QPS)
+	}
+	if len(snap.Models) != 2 {
+		t.Fatalf("Expected 2 models, got %+v", snap.Models)
+	}
+	v1, v2 := snap.Models[0], snap.Models[1]
+	if v1.Model != "policy_v1.onnx" || v1.QPS != 8 || v1.P95LatencyMs != 19 {
+		t.Errorf("Unexpected stats for v1: %+v", v1)
+	}
+	if v2.ErrorRate != 1 {
+		t.Errorf("Expected v2 error rate 1, got %v", v2.ErrorRate)
+	}
+
+	// The next window starts empty; idle robots and models age out
+	healthy = false
+	now = now.Add(2 * time.Minute)
+	snap = c.collect()
+	if snap.Healthy || snap.Fleet.ActiveRobots != 0 || len(snap.Models) != 0 || snap.QPS != 0 {
+		t.Errorf("Expected empty unhealthy snapshot, got %+v", snap)
+	}
+}
+
+func TestCollector_Handler(t *testing.T) {
+	c := NewCollector(time.Second, time.Minute, nil)
+	engine := Instrument(inference.NewMock(), "policy.onnx", c)
+	handler := c.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/telemetry", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 before the first snapshot, got %d", rec.Code)
+	}
+
+	if _, err := engine.Predict([][]float32{{0.1}, {0.2}}, 1, 1, 1); err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	c.update()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/telemetry", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("Expected CORS header for browser dashboards")
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("Invalid snapshot JSON: %v", err)
+	}
+	if len(snap.Models) != 1 || snap.Models[0].Model != "policy.onnx" {
+		t.Errorf("Expected stats for policy.onnx, got %+v", snap.Models)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/telemetry", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,102 @@
+// internal/tenantgate/grpc.go
+package tenantgate
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// RetryAfterTrailer carries the whole seconds a rejected client should wait
+// before retrying, as HTTP's Retry-After header does
+const RetryAfterTrailer = "retry-after"
+
+// gatedPrefix selects the methods gated; health checks and reflection are
+// always served
+const gatedPrefix = "/planner.PathPlanner/"
+
+// trailer returns the retry-after trailer of a rejection
+func (r *Rejection) trailer() metadata.MD {
+	seconds := int64(math.Ceil(r.RetryAfter.Seconds()))
+	return metadata.Pairs(RetryAfterTrailer, strconv.FormatInt(seconds, 10))
+}
+
+// err returns the status a rejected call ends with
+func (r *Rejection) err() error {
+	return status.Error(codes.Unavailable, r.Error())
+}
+
+// UnaryInterceptor rejects calls of paused and draining tenants. It must run
+// after the tenant and auth interceptors, and gates the authenticated tenant,
+// so a paused tenant cannot slip through by changing or omitting its
+// x-tenant-id header.
+func (g *Gate) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, gatedPrefix) {
+			return handler(ctx, req)
+		}
+		tenant := auth.TenantFromContext(ctx)
+		callCtx, done, rejected := g.admit(ctx, tenant, false)
+		if rejected != nil {
+			metrics.RecordTenantGateRejected(tenant, rejected.State)
+			grpc.SetTrailer(ctx, rejected.trailer())
+			return nil, rejected.err()
+		}
+		defer done()
+		return handler(callCtx, req)
+	}
+}
+
+// StreamInterceptor is the streaming counterpart of UnaryInterceptor; streams
+// of a draining tenant end with codes.Unavailable
+func (g *Gate) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if !strings.HasPrefix(info.FullMethod, gatedPrefix) {
+			return handler(srv, ss)
+		}
+		tenant := auth.TenantFromContext(ss.Context())
+		ctx, done, rejected := g.admit(ss.Context(), tenant, true)
+		if rejected != nil {
+			metrics.RecordTenantGateRejected(tenant, rejected.State)
+			ss.SetTrailer(rejected.trailer())
+			return rejected.err()
+		}
+		defer done()
+
+		err := handler(srv, &gatedStream{ServerStream: ss, ctx: ctx})
+		if drained := g.drained(ctx, tenant); drained != nil {
+			ss.SetTrailer(drained.trailer())
+			return drained.err()
+		}
+		return err
+	}
+}
+
+// gatedStream carries the context a drain cancels
+type gatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *gatedStream) Context() context.Context {
+	return s.ctx
+}
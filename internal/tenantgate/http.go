@@ -0,0 +1,65 @@
+// internal/tenantgate/http.go
+package tenantgate
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+)
+
+// Register adds the tenant gate endpoints to the admin API
+func (g *Gate) Register(m *admin.Mux) {
+	m.HandleFunc("GET /admin/tenants", func(w http.ResponseWriter, r *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, g.Status())
+	})
+	m.HandleFunc("POST /admin/tenants/{tenant}/pause", func(w http.ResponseWriter, r *http.Request) {
+		retryAfter, ok := parseRetryAfter(w, r)
+		if !ok {
+			return
+		}
+		g.Pause(r.PathValue("tenant"), retryAfter)
+		g.writeTenant(w, r.PathValue("tenant"))
+	})
+	m.HandleFunc("POST /admin/tenants/{tenant}/drain", func(w http.ResponseWriter, r *http.Request) {
+		retryAfter, ok := parseRetryAfter(w, r)
+		if !ok {
+			return
+		}
+		g.Drain(r.PathValue("tenant"), retryAfter)
+		g.writeTenant(w, r.PathValue("tenant"))
+	})
+	m.HandleFunc("POST /admin/tenants/{tenant}/resume", func(w http.ResponseWriter, r *http.Request) {
+		if !g.Resume(r.PathValue("tenant")) {
+			admin.WriteError(w, http.StatusNotFound, "tenant is neither paused nor draining")
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, map[string]string{"tenant": r.PathValue("tenant"), "state": "open"})
+	})
+}
+
+// parseRetryAfter reads the optional retry_after query parameter, a duration,
+// writing an error response if it is invalid
+func parseRetryAfter(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	v := r.URL.Query().Get("retry_after")
+	if v == "" {
+		return DefaultRetryAfter, true
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		admin.WriteError(w, http.StatusBadRequest, "retry_after must be a positive duration, e.g. 30s")
+		return 0, false
+	}
+	return d, true
+}
+
+// writeTenant writes the status of the tenant's gate
+func (g *Gate) writeTenant(w http.ResponseWriter, tenant string) {
+	for _, s := range g.Status() {
+		if s.Tenant == tenant {
+			admin.WriteJSON(w, http.StatusOK, s)
+			return
+		}
+	}
+	admin.WriteError(w, http.StatusNotFound, "tenant is neither paused nor draining")
+}
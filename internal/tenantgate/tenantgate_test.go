@@ -0,0 +1,113 @@
+// internal/tenantgate/tenantgate_test.go
+package tenantgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+)
+
+const planMethod = "/planner.PathPlanner/Plan"
+
+func unaryCall(g *Gate, tenant, method string) error {
+	ctx := middleware.WithTenantID(context.Background(), tenant)
+	_, err := g.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil })
+	return err
+}
+
+func TestGate_PauseAndResume(t *testing.T) {
+	g := New()
+	g.Pause("sim", time.Minute)
+
+	err := unaryCall(g, "sim", planMethod)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable for a paused tenant, got %v", err)
+	}
+	if err := unaryCall(g, "acme", planMethod); err != nil {
+		t.Errorf("Expected other tenants to be served, got %v", err)
+	}
+	if err := unaryCall(g, "sim", "/grpc.health.v1.Health/Check"); err != nil {
+		t.Errorf("Expected health checks to be served, got %v", err)
+	}
+
+	gates := g.Status()
+	if len(gates) != 1 || gates[0].Tenant != "sim" || gates[0].State != Paused || gates[0].RetryAfter != "1m0s" {
+		t.Errorf("Expected sim paused for 1m, got %+v", gates)
+	}
+
+	if !g.Resume("sim") || g.Resume("sim") {
+		t.Error("Expected Resume to report the paused tenant once")
+	}
+	if err := unaryCall(g, "sim", planMethod); err != nil {
+		t.Errorf("Expected a resumed tenant to be served, got %v", err)
+	}
+}
+
+// trailerStream is a server stream recording its trailer
+type trailerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Context() context.Context { return s.ctx }
+
+func (s *trailerStream) SetTrailer(md metadata.MD) { s.trailer = md }
+
+func TestGate_AuthenticatedTenant(t *testing.T) {
+	g := New()
+	g.Pause("sim", time.Minute)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: planMethod}
+
+	// The authenticated tenant is gated whatever tenant the context claims
+	ctx := auth.WithIdentity(middleware.WithTenantID(context.Background(), middleware.DefaultTenant),
+		auth.Identity{Subject: "sim-runner", Tenant: "sim"})
+	if _, err := g.UnaryInterceptor()(ctx, nil, info, handler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable for the authenticated paused tenant, got %v", err)
+	}
+	ctx = auth.WithIdentity(middleware.WithTenantID(context.Background(), "sim"),
+		auth.Identity{Subject: "controller-1", Tenant: "acme"})
+	if _, err := g.UnaryInterceptor()(ctx, nil, info, handler); err != nil {
+		t.Fatalf("Expected another authenticated tenant to pass, got %v", err)
+	}
+}
+
+func TestGate_DrainEndsStreams(t *testing.T) {
+	g := New()
+	ss := &trailerStream{ctx: middleware.WithTenantID(context.Background(), "sim")}
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- g.StreamInterceptor()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/planner.PathPlanner/StreamPlan"},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				close(started)
+				<-ss.Context().Done()
+				return status.FromContextError(ss.Context().Err()).Err()
+			})
+	}()
+	<-started
+
+	if n := g.Drain("sim", 90*time.Second); n != 1 {
+		t.Fatalf("Expected 1 call in flight, got %d", n)
+	}
+	err := <-done
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected a drained stream to end Unavailable, got %v", err)
+	}
+	if got := ss.trailer.Get(RetryAfterTrailer); len(got) != 1 || got[0] != "90" {
+		t.Errorf("Expected retry-after 90, got %v", got)
+	}
+	if gates := g.Status(); len(gates) != 1 || gates[0].State != Draining || gates[0].InFlight != 0 {
+		t.Errorf("Expected sim drained with nothing in flight, got %+v", gates)
+	}
+}
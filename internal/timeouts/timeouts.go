@@ -0,0 +1,123 @@
+// internal/timeouts/timeouts.go
+
+// Package timeouts provides the timeouts of internal stages such as inference
+// and cache lookups, either static or tuned from the stage's recent latencies
+package timeouts
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Stages with timeouts
+const (
+	StageInference = "inference"
+	StageCache     = "cache"
+)
+
+// Tuning configures an adaptive timeout: Multiplier times the Percentile of
+// the last Window latencies, clamped to [Floor, Ceiling]
+type Tuning struct {
+	Window     int
+	Percentile float64
+	Multiplier float64
+	Floor      time.Duration
+	Ceiling    time.Duration
+}
+
+// Stage is the timeout of one internal stage. A static stage keeps its
+// timeout; an adaptive one starts from it and, once it has seen enough
+// latencies, retunes it periodically as they drift, so a model with a
+// different latency profile does not need its timeouts re-tuned by hand.
+type Stage struct {
+	name    string
+	current atomic.Int64 // Timeout in nanoseconds; 0 disables it
+
+	// Adaptive tuning; tuning.Window is 0 for a static stage
+	tuning  Tuning
+	mu      sync.Mutex
+	samples []time.Duration // Ring of the last tuning.Window latencies
+	next    int             // Ring index of the next sample
+	seen    int             // Samples since the last retune
+}
+
+// Static returns a stage that always times out after timeout; 0 disables it
+func Static(name string, timeout time.Duration) *Stage {
+	s := &Stage{name: name}
+	s.set(timeout)
+	return s
+}
+
+// Adaptive returns a stage timing out after initial until it has seen a tenth
+// of tuning.Window latencies, then tuned from them as Tuning describes
+func Adaptive(name string, initial time.Duration, tuning Tuning) *Stage {
+	s := &Stage{name: name, tuning: tuning, samples: make([]time.Duration, 0, tuning.Window)}
+	s.set(initial)
+	return s
+}
+
+// Name returns the stage name
+func (s *Stage) Name() string { return s.name }
+
+// Timeout returns the stage's current timeout; 0 means none
+func (s *Stage) Timeout() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(s.current.Load())
+}
+
+// Context returns ctx bounded by the stage's current timeout
+func (s *Stage) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := s.Timeout(); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return ctx, func() {}
+}
+
+// Observe records the latency of a completed call of the stage. Adaptive
+// stages retune after every tenth of their window.
+func (s *Stage) Observe(latency time.Duration) {
+	if s == nil || s.tuning.Window == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.samples) < s.tuning.Window {
+		s.samples = append(s.samples, latency)
+	} else {
+		s.samples[s.next] = latency
+	}
+	s.next = (s.next + 1) % s.tuning.Window
+	s.seen++
+	if every := max(s.tuning.Window/10, 1); s.seen >= every && len(s.samples) >= every {
+		s.seen = 0
+		s.set(s.tuneLocked())
+	}
+}
+
+// Expired records a call that ran out of the stage's time
+func (s *Stage) Expired() {
+	metrics.RecordStageTimeout(s.name)
+}
+
+// tuneLocked returns the timeout for the current samples
+func (s *Stage) tuneLocked() time.Duration {
+	sorted := slices.Clone(s.samples)
+	slices.Sort(sorted)
+	i := int(math.Ceil(s.tuning.Percentile*float64(len(sorted)))) - 1
+	p := sorted[min(max(i, 0), len(sorted)-1)]
+	timeout := time.Duration(float64(p) * s.tuning.Multiplier)
+	return min(max(timeout, s.tuning.Floor), s.tuning.Ceiling)
+}
+
+func (s *Stage) set(timeout time.Duration) {
+	s.current.Store(int64(timeout))
+	metrics.SetStageTimeout(s.name, timeout.Seconds())
+}
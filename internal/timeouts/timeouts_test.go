@@ -0,0 +1,67 @@
+// internal/timeouts/timeouts_test.go
+package timeouts
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStatic(t *testing.T) {
+	s := Static(StageCache, 50*time.Millisecond)
+	for i := 0; i < 100; i++ {
+		s.Observe(time.Second)
+	}
+	if got := s.Timeout(); got != 50*time.Millisecond {
+		t.Errorf("Expected a static stage to keep 50ms, got %s", got)
+	}
+
+	none := Static(StageCache, 0)
+	ctx, cancel := none.Context(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline from a stage without a timeout")
+	}
+}
+
+func TestAdaptive_TunesFromPercentile(t *testing.T) {
+	s := Adaptive(StageInference, time.Second, Tuning{
+		Window:     100,
+		Percentile: 0.99,
+		Multiplier: 2,
+		Floor:      5 * time.Millisecond,
+		Ceiling:    500 * time.Millisecond,
+	})
+
+	// The initial timeout holds until a tenth of the window is seen
+	for i := 0; i < 9; i++ {
+		s.Observe(10 * time.Millisecond)
+	}
+	if got := s.Timeout(); got != time.Second {
+		t.Fatalf("Expected the initial 1s before enough samples, got %s", got)
+	}
+
+	// The window is now full, its p99 the second largest latency: 90ms
+	for i := 1; i <= 91; i++ {
+		s.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if got := s.Timeout(); got != 180*time.Millisecond {
+		t.Errorf("Expected 2 x p99 = 180ms, got %s", got)
+	}
+
+	// A faster model pulls the timeout down to the floor
+	for i := 0; i < 100; i++ {
+		s.Observe(time.Millisecond)
+	}
+	if got := s.Timeout(); got != 5*time.Millisecond {
+		t.Errorf("Expected the 5ms floor, got %s", got)
+	}
+
+	// A slower one raises it to the ceiling
+	for i := 0; i < 100; i++ {
+		s.Observe(time.Second)
+	}
+	if got := s.Timeout(); got != 500*time.Millisecond {
+		t.Errorf("Expected the 500ms ceiling, got %s", got)
+	}
+}
@@ -0,0 +1,204 @@
+// Package watchdog detects goroutine leaks and a wedged inference pipeline
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// Trip reasons reported in logs and metrics
+const (
+	ReasonGoroutineGrowth = "goroutine_growth"
+	ReasonStalled         = "inference_stalled"
+)
+
+// Progress tracks work entering and leaving the inference pipeline
+type Progress struct {
+	pending   atomic.Int64
+	completed atomic.Uint64
+}
+
+// Begin marks a batch as queued for inference. The returned func must be
+// called once the batch leaves the pipeline, successfully or not.
+func (p *Progress) Begin() func() {
+	p.pending.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.pending.Add(-1)
+			p.completed.Add(1)
+		})
+	}
+}
+
+// Pending returns the number of batches queued or running
+func (p *Progress) Pending() int64 {
+	return p.pending.Load()
+}
+
+// Completed returns the number of batches that have left the pipeline
+func (p *Progress) Completed() uint64 {
+	return p.completed.Load()
+}
+
+// Config controls watchdog thresholds
+type Config struct {
+	// Interval between checks
+	Interval time.Duration
+	// GoroutineWindow is the number of consecutive checks over which the
+	// goroutine count must not decrease to count as sustained growth
+	GoroutineWindow int
+	// GoroutineGrowth is the minimum increase across the window to trip
+	GoroutineGrowth int
+	// StallTimeout is how long work may be pending without any completion
+	StallTimeout time.Duration
+}
+
+// Watchdog periodically checks goroutine counts and inference progress
+type Watchdog struct {
+	cfg      Config
+	progress *Progress
+	onChange func(healthy bool)
+
+	// numGoroutine is runtime.NumGoroutine, replaceable in tests
+	numGoroutine func() int
+
+	mu            sync.Mutex
+	goroutines    []int
+	lastCompleted uint64
+	lastProgress  time.Time
+	tripped       map[string]bool
+}
+
+// New creates a Watchdog. progress may be nil to disable stall detection.
+// onChange, if set, is called when the service becomes unhealthy (a check
+// trips) and when it recovers (all checks clear).
+func New(cfg Config, progress *Progress, onChange func(healthy bool)) *Watchdog {
+	if cfg.GoroutineWindow < 2 {
+		cfg.GoroutineWindow = 2
+	}
+	return &Watchdog{
+		cfg:          cfg,
+		progress:     progress,
+		onChange:     onChange,
+		numGoroutine: runtime.NumGoroutine,
+		tripped:      make(map[string]bool),
+	}
+}
+
+// Run checks every Interval until ctx is done
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.Check(now)
+		}
+	}
+}
+
+// Check runs one round of checks and returns the reasons currently tripped
+func (w *Watchdog) Check(now time.Time) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wasHealthy := len(w.tripped) == 0
+
+	count := w.numGoroutine()
+	metrics.SetWatchdogGoroutines(count)
+	w.update(ReasonGoroutineGrowth, w.goroutineGrowth(count))
+
+	if w.progress != nil {
+		w.update(ReasonStalled, w.stalled(now))
+	}
+
+	healthy := len(w.tripped) == 0
+	if healthy != wasHealthy && w.onChange != nil {
+		w.onChange(healthy)
+	}
+
+	reasons := make([]string, 0, len(w.tripped))
+	for reason := range w.tripped {
+		reasons = append(reasons, reason)
+	}
+	return reasons
+}
+
+// goroutineGrowth records count and reports whether it has grown steadily
+// across the whole window
+func (w *Watchdog) goroutineGrowth(count int) bool {
+	w.goroutines = append(w.goroutines, count)
+	if len(w.goroutines) > w.cfg.GoroutineWindow {
+		w.goroutines = w.goroutines[1:]
+	}
+	if len(w.goroutines) < w.cfg.GoroutineWindow {
+		return false
+	}
+
+	for i := 1; i < len(w.goroutines); i++ {
+		if w.goroutines[i] < w.goroutines[i-1] {
+			return false
+		}
+	}
+	return w.goroutines[len(w.goroutines)-1]-w.goroutines[0] >= w.cfg.GoroutineGrowth
+}
+
+// stalled reports whether work has been pending without completions for StallTimeout
+func (w *Watchdog) stalled(now time.Time) bool {
+	pending := w.progress.Pending()
+	completed := w.progress.Completed()
+	metrics.SetWatchdogPending(pending)
+
+	if pending == 0 || completed != w.lastCompleted || w.lastProgress.IsZero() {
+		w.lastCompleted = completed
+		w.lastProgress = now
+		return false
+	}
+	return now.Sub(w.lastProgress) >= w.cfg.StallTimeout
+}
+
+// update transitions a check between tripped and clear, logging on change
+func (w *Watchdog) update(reason string, tripped bool) {
+	if tripped == w.tripped[reason] {
+		return
+	}
+
+	if tripped {
+		w.tripped[reason] = true
+		metrics.RecordWatchdogTrip(reason)
+		slog.Warn("Watchdog tripped", "reason", reason, "goroutines", w.goroutines, "pending", w.pending(),
+			"goroutine_dump", goroutineDump())
+		return
+	}
+
+	delete(w.tripped, reason)
+	slog.Info("Watchdog cleared", "reason", reason)
+}
+
+func (w *Watchdog) pending() int64 {
+	if w.progress == nil {
+		return 0
+	}
+	return w.progress.Pending()
+}
+
+// goroutineDump returns the stacks of all goroutines
+func goroutineDump() []byte {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,109 @@
+// internal/watchdog/watchdog_test.go
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestWatchdog(progress *Progress, counts *int) (*Watchdog, *[]bool) {
+	var changes []bool
+	w := New(Config{
+		Interval:        time.Second,
+		GoroutineWindow: 3,
+		GoroutineGrowth: 10,
+		StallTimeout:    5 * time.Second,
+	}, progress, func(healthy bool) {
+		changes = append(changes, healthy)
+	})
+	w.numGoroutine = func() int { return *counts }
+	return w, &changes
+}
+
+func TestWatchdog_GoroutineGrowth(t *testing.T) {
+	count := 100
+	w, changes := newTestWatchdog(nil, &count)
+	now := time.Now()
+
+	for _, c := range []int{100, 105, 112} {
+		count = c
+		now = now.Add(time.Second)
+		w.Check(now)
+	}
+	if len(*changes) != 1 || (*changes)[0] {
+		t.Fatalf("Expected a single unhealthy transition, got %v", *changes)
+	}
+
+	// A drop in goroutines clears the condition
+	count = 50
+	if reasons := w.Check(now.Add(time.Second)); len(reasons) != 0 {
+		t.Fatalf("Expected no tripped checks, got %v", reasons)
+	}
+	if len(*changes) != 2 || !(*changes)[1] {
+		t.Fatalf("Expected recovery, got %v", *changes)
+	}
+}
+
+func TestWatchdog_IgnoresSmallGrowth(t *testing.T) {
+	count := 100
+	w, changes := newTestWatchdog(nil, &count)
+	now := time.Now()
+
+	for _, c := range []int{100, 101, 102, 103, 104} {
+		count = c
+		now = now.Add(time.Second)
+		w.Check(now)
+	}
+	if len(*changes) != 0 {
+		t.Fatalf("Expected no health changes, got %v", *changes)
+	}
+}
+
+func TestWatchdog_Stalled(t *testing.T) {
+	count := 10
+	progress := &Progress{}
+	w, changes := newTestWatchdog(progress, &count)
+	now := time.Now()
+
+	done := progress.Begin()
+	w.Check(now)
+	if reasons := w.Check(now.Add(4 * time.Second)); len(reasons) != 0 {
+		t.Fatalf("Expected no stall before timeout, got %v", reasons)
+	}
+
+	reasons := w.Check(now.Add(6 * time.Second))
+	if len(reasons) != 1 || reasons[0] != ReasonStalled {
+		t.Fatalf("Expected %s, got %v", ReasonStalled, reasons)
+	}
+
+	done()
+	if reasons := w.Check(now.Add(7 * time.Second)); len(reasons) != 0 {
+		t.Fatalf("Expected stall to clear after completion, got %v", reasons)
+	}
+	if len(*changes) != 2 || (*changes)[0] || !(*changes)[1] {
+		t.Fatalf("Expected unhealthy then healthy, got %v", *changes)
+	}
+}
+
+func TestWatchdog_IdleIsNotStalled(t *testing.T) {
+	count := 10
+	progress := &Progress{}
+	w, _ := newTestWatchdog(progress, &count)
+	now := time.Now()
+
+	w.Check(now)
+	if reasons := w.Check(now.Add(time.Minute)); len(reasons) != 0 {
+		t.Fatalf("Expected idle pipeline not to stall, got %v", reasons)
+	}
+}
+
+func TestProgress_DoneIsIdempotent(t *testing.T) {
+	p := &Progress{}
+	done := p.Begin()
+	done()
+	done()
+
+	if p.Pending() != 0 || p.Completed() != 1 {
+		t.Fatalf("Expected pending=0 completed=1, got pending=%d completed=%d", p.Pending(), p.Completed())
+	}
+}
@@ -20,16 +20,76 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// PlanState is the progress of an async plan
+type PlanState int32
+
+const (
+	PlanState_PLAN_STATE_PENDING PlanState = 0 // Queued or running
+	PlanState_PLAN_STATE_DONE    PlanState = 1 // Planned; response is set
+	PlanState_PLAN_STATE_FAILED  PlanState = 2 // Failed; error_code and error are set
+)
+
+// Enum value maps for PlanState.
+var (
+	PlanState_name = map[int32]string{
+		0: "PLAN_STATE_PENDING",
+		1: "PLAN_STATE_DONE",
+		2: "PLAN_STATE_FAILED",
+	}
+	PlanState_value = map[string]int32{
+		"PLAN_STATE_PENDING": 0,
+		"PLAN_STATE_DONE":    1,
+		"PLAN_STATE_FAILED":  2,
+	}
+)
+
+func (x PlanState) Enum() *PlanState {
+	p := new(PlanState)
+	*p = x
+	return p
+}
+
+func (x PlanState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PlanState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_planner_proto_enumTypes[0].Descriptor()
+}
+
+func (PlanState) Type() protoreflect.EnumType {
+	return &file_proto_planner_proto_enumTypes[0]
+}
+
+func (x PlanState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PlanState.Descriptor instead.
+func (PlanState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{0}
+}
+
 // Observation represents sensor/state data for a robot
 type Observation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data     []float32 `protobuf:"fixed32,1,rep,packed,name=data,proto3" json:"data,omitempty"`         // Flattened observation data
-	Channels uint32    `protobuf:"varint,2,opt,name=channels,proto3" json:"channels,omitempty"`        // Number of channels (C)
-	Height   uint32    `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`            // Height dimension (H)
-	Width    uint32    `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`              // Width dimension (W)
+	Data     []float32 `protobuf:"fixed32,1,rep,packed,name=data,proto3" json:"data,omitempty"` // Flattened observation data
+	Channels uint32    `protobuf:"varint,2,opt,name=channels,proto3" json:"channels,omitempty"` // Number of channels (C)
+	Height   uint32    `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`     // Height dimension (H)
+	Width    uint32    `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`       // Width dimension (W)
+	// uint8 values in the same C,H,W order, sent instead of data for camera
+	// frames at a quarter of the bandwidth. The server converts them to
+	// float32 as (x * scale - mean[c]) / std[c] with the configured
+	// uint8_normalization before planning.
+	Uint8Data []byte `protobuf:"bytes,5,opt,name=uint8_data,json=uint8Data,proto3" json:"uint8_data,omitempty"`
+	// Data in another encoding, sent instead of data and uint8_data. The
+	// server decodes it with the codec registered under encoding, e.g. 2 for
+	// float16 or 4 for JPEG; GetCapabilities lists the registered codecs.
+	Encoding    uint32 `protobuf:"varint,6,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	EncodedData []byte `protobuf:"bytes,7,opt,name=encoded_data,json=encodedData,proto3" json:"encoded_data,omitempty"`
 }
 
 func (x *Observation) Reset() {
@@ -92,14 +152,37 @@ func (x *Observation) GetWidth() uint32 {
 	return 0
 }
 
+func (x *Observation) GetUint8Data() []byte {
+	if x != nil {
+		return x.Uint8Data
+	}
+	return nil
+}
+
+func (x *Observation) GetEncoding() uint32 {
+	if x != nil {
+		return x.Encoding
+	}
+	return 0
+}
+
+func (x *Observation) GetEncodedData() []byte {
+	if x != nil {
+		return x.EncodedData
+	}
+	return nil
+}
+
 // PlanRequest contains a single robot's planning request
 type PlanRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RobotId uint64       `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"` // Unique robot identifier
-	Obs     *Observation `protobuf:"bytes,2,opt,name=obs,proto3" json:"obs,omitempty"`                         // Robot's current observation
+	RobotId      uint64       `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`               // Unique robot identifier
+	Obs          *Observation `protobuf:"bytes,2,opt,name=obs,proto3" json:"obs,omitempty"`                                       // Robot's current observation
+	Pose         *Pose        `protobuf:"bytes,3,opt,name=pose,proto3" json:"pose,omitempty"`                                     // Robot's current pose; cached as its last pose after planning when pose_mode is set
+	ModelVersion string       `protobuf:"bytes,4,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"` // Model version to plan with; empty uses the latest. Requests in a batch must agree.
 }
 
 func (x *PlanRequest) Reset() {
@@ -148,20 +231,123 @@ func (x *PlanRequest) GetObs() *Observation {
 	return nil
 }
 
+func (x *PlanRequest) GetPose() *Pose {
+	if x != nil {
+		return x.Pose
+	}
+	return nil
+}
+
+func (x *PlanRequest) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+// Pose is a robot's planar pose, cached between its requests
+type Pose struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X                 float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"` // Position (m)
+	Y                 float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Theta             float64 `protobuf:"fixed64,3,opt,name=theta,proto3" json:"theta,omitempty"`                                                   // Heading (rad)
+	Velocity          float64 `protobuf:"fixed64,4,opt,name=velocity,proto3" json:"velocity,omitempty"`                                             // Forward velocity (m/s)
+	TimestampUnixNano int64   `protobuf:"varint,5,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"` // When the pose was measured; set to the server's receipt time if 0
+}
+
+func (x *Pose) Reset() {
+	*x = Pose{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pose) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pose) ProtoMessage() {}
+
+func (x *Pose) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pose.ProtoReflect.Descriptor instead.
+func (*Pose) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Pose) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Pose) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Pose) GetTheta() float64 {
+	if x != nil {
+		return x.Theta
+	}
+	return 0
+}
+
+func (x *Pose) GetVelocity() float64 {
+	if x != nil {
+		return x.Velocity
+	}
+	return 0
+}
+
+func (x *Pose) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
 // PlanResponse contains the computed action for a single robot
 type PlanResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Action []float32 `protobuf:"fixed32,1,rep,packed,name=action,proto3" json:"action,omitempty"` // Action vector output from policy
-	Safe   bool      `protobuf:"varint,2,opt,name=safe,proto3" json:"safe,omitempty"`             // Safety flag (placeholder for confidence logic)
+	Action             []float32          `protobuf:"fixed32,1,rep,packed,name=action,proto3" json:"action,omitempty"`                                           // Action vector output from policy
+	Safe               bool               `protobuf:"varint,2,opt,name=safe,proto3" json:"safe,omitempty"`                                                       // False if the action is outside the safety limits (safety_mode "flag")
+	Signature          *ResponseSignature `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`                                              // Present when response signing is enabled
+	ObsHash            string             `protobuf:"bytes,4,opt,name=obs_hash,json=obsHash,proto3" json:"obs_hash,omitempty"`                                   // Short hash of the observation the action was computed from
+	DegradedResolution bool               `protobuf:"varint,5,opt,name=degraded_resolution,json=degradedResolution,proto3" json:"degraded_resolution,omitempty"` // Planned from a downsampled observation while the service was degraded
+	ValidityMs         uint32             `protobuf:"varint,6,opt,name=validity_ms,json=validityMs,proto3" json:"validity_ms,omitempty"`                         // How long after receipt the action may be applied; 0 means no limit
+	ExpiresUnixNano    int64              `protobuf:"varint,7,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"`        // Server time after which the action is expired; 0 means no limit
+	CommitToken        string             `protobuf:"bytes,8,opt,name=commit_token,json=commitToken,proto3" json:"commit_token,omitempty"`                       // Set for proposed actions: apply only once CommitPlan(commit_token) commits it
+	LastPose           *Pose              `protobuf:"bytes,9,opt,name=last_pose,json=lastPose,proto3" json:"last_pose,omitempty"`                                // Robot's cached pose from before this request, when pose_mode is "response"
+	ActionSpace        *ActionSpace       `protobuf:"bytes,10,opt,name=action_space,json=actionSpace,proto3" json:"action_space,omitempty"`                      // How to interpret action, when action_space_in_responses is set
+	ModelVersion       string             `protobuf:"bytes,11,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`                   // Model version that planned the action, when model_versions is set
 }
 
 func (x *PlanResponse) Reset() {
 	*x = PlanResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_planner_proto_msgTypes[2]
+		mi := &file_proto_planner_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -174,7 +360,7 @@ func (x *PlanResponse) String() string {
 func (*PlanResponse) ProtoMessage() {}
 
 func (x *PlanResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_planner_proto_msgTypes[2]
+	mi := &file_proto_planner_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -187,7 +373,7 @@ func (x *PlanResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PlanResponse.ProtoReflect.Descriptor instead.
 func (*PlanResponse) Descriptor() ([]byte, []int) {
-	return file_proto_planner_proto_rawDescGZIP(), []int{2}
+	return file_proto_planner_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PlanResponse) GetAction() []float32 {
@@ -204,6 +390,136 @@ func (x *PlanResponse) GetSafe() bool {
 	return false
 }
 
+func (x *PlanResponse) GetSignature() *ResponseSignature {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *PlanResponse) GetObsHash() string {
+	if x != nil {
+		return x.ObsHash
+	}
+	return ""
+}
+
+func (x *PlanResponse) GetDegradedResolution() bool {
+	if x != nil {
+		return x.DegradedResolution
+	}
+	return false
+}
+
+func (x *PlanResponse) GetValidityMs() uint32 {
+	if x != nil {
+		return x.ValidityMs
+	}
+	return 0
+}
+
+func (x *PlanResponse) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
+
+func (x *PlanResponse) GetCommitToken() string {
+	if x != nil {
+		return x.CommitToken
+	}
+	return ""
+}
+
+func (x *PlanResponse) GetLastPose() *Pose {
+	if x != nil {
+		return x.LastPose
+	}
+	return nil
+}
+
+func (x *PlanResponse) GetActionSpace() *ActionSpace {
+	if x != nil {
+		return x.ActionSpace
+	}
+	return nil
+}
+
+func (x *PlanResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+// ResponseSignature lets robot-side safety monitors verify an action was not
+// tampered with in transit. The ed25519 signature covers the big-endian
+// concatenation of robot_id (uint64), timestamp_unix_nano (int64), and the
+// IEEE-754 bits of each action value (float32).
+type ResponseSignature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId             string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`                                        // Identifies the signing key (supports rotation)
+	TimestampUnixNano int64  `protobuf:"varint,2,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"` // Time the response was signed
+	Signature         []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`                                             // ed25519 signature over the payload
+}
+
+func (x *ResponseSignature) Reset() {
+	*x = ResponseSignature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseSignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseSignature) ProtoMessage() {}
+
+func (x *ResponseSignature) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseSignature.ProtoReflect.Descriptor instead.
+func (*ResponseSignature) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResponseSignature) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *ResponseSignature) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
+func (x *ResponseSignature) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
 // BatchPlanRequest contains multiple planning requests
 type BatchPlanRequest struct {
 	state         protoimpl.MessageState
@@ -216,7 +532,7 @@ type BatchPlanRequest struct {
 func (x *BatchPlanRequest) Reset() {
 	*x = BatchPlanRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_planner_proto_msgTypes[3]
+		mi := &file_proto_planner_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -229,7 +545,7 @@ func (x *BatchPlanRequest) String() string {
 func (*BatchPlanRequest) ProtoMessage() {}
 
 func (x *BatchPlanRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_planner_proto_msgTypes[3]
+	mi := &file_proto_planner_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -242,7 +558,7 @@ func (x *BatchPlanRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BatchPlanRequest.ProtoReflect.Descriptor instead.
 func (*BatchPlanRequest) Descriptor() ([]byte, []int) {
-	return file_proto_planner_proto_rawDescGZIP(), []int{3}
+	return file_proto_planner_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *BatchPlanRequest) GetRequests() []*PlanRequest {
@@ -264,7 +580,7 @@ type BatchPlanResponse struct {
 func (x *BatchPlanResponse) Reset() {
 	*x = BatchPlanResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_planner_proto_msgTypes[4]
+		mi := &file_proto_planner_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -277,7 +593,7 @@ func (x *BatchPlanResponse) String() string {
 func (*BatchPlanResponse) ProtoMessage() {}
 
 func (x *BatchPlanResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_planner_proto_msgTypes[4]
+	mi := &file_proto_planner_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -290,7 +606,7 @@ func (x *BatchPlanResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BatchPlanResponse.ProtoReflect.Descriptor instead.
 func (*BatchPlanResponse) Descriptor() ([]byte, []int) {
-	return file_proto_planner_proto_rawDescGZIP(), []int{4}
+	return file_proto_planner_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BatchPlanResponse) GetResponses() []*PlanResponse {
@@ -300,93 +616,2605 @@ func (x *BatchPlanResponse) GetResponses() []*PlanResponse {
 	return nil
 }
 
-var File_proto_planner_proto protoreflect.FileDescriptor
+// BatchPlanStreamRequest contains planning requests to be answered in chunks
+type BatchPlanStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_proto_planner_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x22, 0x65,
-	0x0a, 0x0b, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x04, 0x64, 0x61, 0x74,
-	0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x16, 0x0a,
-	0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68,
-	0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22, 0x4d, 0x0a, 0x0b, 0x50,
-	0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f,
-	0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x6f,
-	0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x03, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x4f, 0x62, 0x73,
-	0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x6f, 0x62, 0x73, 0x22, 0x3a, 0x0a,
-	0x0c, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a,
-	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x06, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x66, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x61, 0x66, 0x65, 0x22, 0x42, 0x0a, 0x10, 0x42, 0x61, 0x74,
-	0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a,
-	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22,
-	0x46, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
-	0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x32, 0x84, 0x01, 0x0a, 0x0b, 0x50, 0x61,
-	0x74, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x04, 0x50, 0x6c, 0x61,
-	0x6e, 0x12, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
-	0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40,
-	0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x19, 0x2e, 0x70, 0x6c,
-	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72,
-	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x53, 0x79, 0x65, 0x64, 0x44, 0x61, 0x69, 0x61, 0x6d, 0x39, 0x31, 0x30, 0x31, 0x2f, 0x70, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
-	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	Requests  []*PlanRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	ChunkSize uint32         `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"` // Requests per chunk; 0 uses the server default
 }
 
-var (
-	file_proto_planner_proto_rawDescOnce sync.Once
-	file_proto_planner_proto_rawDescData = file_proto_planner_proto_rawDesc
-)
+func (x *BatchPlanStreamRequest) Reset() {
+	*x = BatchPlanStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_proto_planner_proto_rawDescGZIP() []byte {
-	file_proto_planner_proto_rawDescOnce.Do(func() {
-		file_proto_planner_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_planner_proto_rawDescData)
-	})
-	return file_proto_planner_proto_rawDescData
+func (x *BatchPlanStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_proto_planner_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_proto_planner_proto_goTypes = []interface{}{
-	(*Observation)(nil),       // 0: planner.Observation
-	(*PlanRequest)(nil),       // 1: planner.PlanRequest
-	(*PlanResponse)(nil),      // 2: planner.PlanResponse
-	(*BatchPlanRequest)(nil),  // 3: planner.BatchPlanRequest
-	(*BatchPlanResponse)(nil), // 4: planner.BatchPlanResponse
+func (*BatchPlanStreamRequest) ProtoMessage() {}
+
+func (x *BatchPlanStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_proto_planner_proto_depIdxs = []int32{
-	0, // 0: planner.PlanRequest.obs:type_name -> planner.Observation
-	1, // 1: planner.BatchPlanRequest.requests:type_name -> planner.PlanRequest
-	2, // 2: planner.BatchPlanResponse.responses:type_name -> planner.PlanResponse
-	1, // 3: planner.PathPlanner.Plan:input_type -> planner.PlanRequest
-	3, // 4: planner.PathPlanner.BatchPlan:input_type -> planner.BatchPlanRequest
-	2, // 5: planner.PathPlanner.Plan:output_type -> planner.PlanResponse
-	4, // 6: planner.PathPlanner.BatchPlan:output_type -> planner.BatchPlanResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+
+// Deprecated: Use BatchPlanStreamRequest.ProtoReflect.Descriptor instead.
+func (*BatchPlanStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{7}
 }
 
-func init() { file_proto_planner_proto_init() }
+func (x *BatchPlanStreamRequest) GetRequests() []*PlanRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
 
-func file_proto_planner_proto_init() {
-	if File_proto_planner_proto != nil {
-		return
+func (x *BatchPlanStreamRequest) GetChunkSize() uint32 {
+	if x != nil {
+		return x.ChunkSize
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_planner_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+	return 0
+}
+
+// BatchPlanChunk holds the responses for one chunk. Chunks arrive in
+// completion order, not request order.
+type BatchPlanChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Offset    uint32          `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`      // Index of the first response in the request
+	Responses []*PlanResponse `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"` // Responses for requests[offset:offset+len(responses)]
+}
+
+func (x *BatchPlanChunk) Reset() {
+	*x = BatchPlanChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchPlanChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPlanChunk) ProtoMessage() {}
+
+func (x *BatchPlanChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPlanChunk.ProtoReflect.Descriptor instead.
+func (*BatchPlanChunk) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchPlanChunk) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *BatchPlanChunk) GetResponses() []*PlanResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+// PlanTicket identifies an async plan; only the tenant that enqueued it can
+// read its result
+type PlanTicket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticket string `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+}
+
+func (x *PlanTicket) Reset() {
+	*x = PlanTicket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanTicket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanTicket) ProtoMessage() {}
+
+func (x *PlanTicket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanTicket.ProtoReflect.Descriptor instead.
+func (*PlanTicket) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PlanTicket) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
+// PlanResult is the outcome of an async plan
+type PlanResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticket    string             `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	State     PlanState          `protobuf:"varint,2,opt,name=state,proto3,enum=planner.PlanState" json:"state,omitempty"`
+	Response  *BatchPlanResponse `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`                     // Set when state is DONE
+	ErrorCode int32              `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // gRPC status code when state is FAILED
+	Error     string             `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                           // Error message when state is FAILED
+}
+
+func (x *PlanResult) Reset() {
+	*x = PlanResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanResult) ProtoMessage() {}
+
+func (x *PlanResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanResult.ProtoReflect.Descriptor instead.
+func (*PlanResult) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlanResult) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
+func (x *PlanResult) GetState() PlanState {
+	if x != nil {
+		return x.State
+	}
+	return PlanState_PLAN_STATE_PENDING
+}
+
+func (x *PlanResult) GetResponse() *BatchPlanResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *PlanResult) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PlanResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// WatchPlanResultsRequest lists the tickets to stream results for
+type WatchPlanResultsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tickets []string `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+}
+
+func (x *WatchPlanResultsRequest) Reset() {
+	*x = WatchPlanResultsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPlanResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPlanResultsRequest) ProtoMessage() {}
+
+func (x *WatchPlanResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPlanResultsRequest.ProtoReflect.Descriptor instead.
+func (*WatchPlanResultsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchPlanResultsRequest) GetTickets() []string {
+	if x != nil {
+		return x.Tickets
+	}
+	return nil
+}
+
+// ModelInfoRequest is empty; the primary serving model is described
+type ModelInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ModelInfoRequest) Reset() {
+	*x = ModelInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModelInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelInfoRequest) ProtoMessage() {}
+
+func (x *ModelInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelInfoRequest.ProtoReflect.Descriptor instead.
+func (*ModelInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{12}
+}
+
+// ModelInfo describes the serving model and its ONNX Runtime session, so a
+// silent fallback from an accelerator to CPU is visible to operators
+type ModelInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Model                       string           `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`                                                                                  // Model path
+	OrtVersion                  string           `protobuf:"bytes,2,opt,name=ort_version,json=ortVersion,proto3" json:"ort_version,omitempty"`                                                      // ONNX Runtime library version
+	AppendedExecutionProviders  []string         `protobuf:"bytes,3,rep,name=appended_execution_providers,json=appendedExecutionProviders,proto3" json:"appended_execution_providers,omitempty"`    // Providers appended to the session without error, in priority order
+	RequestedExecutionProviders []string         `protobuf:"bytes,4,rep,name=requested_execution_providers,json=requestedExecutionProviders,proto3" json:"requested_execution_providers,omitempty"` // Configured providers, in priority order
+	ProviderErrors              []string         `protobuf:"bytes,5,rep,name=provider_errors,json=providerErrors,proto3" json:"provider_errors,omitempty"`                                          // Why requested providers are not active
+	IntraOpThreads              int32            `protobuf:"varint,6,opt,name=intra_op_threads,json=intraOpThreads,proto3" json:"intra_op_threads,omitempty"`                                       // 0 means ORT's default
+	InterOpThreads              int32            `protobuf:"varint,7,opt,name=inter_op_threads,json=interOpThreads,proto3" json:"inter_op_threads,omitempty"`                                       // 0 means ORT's default
+	OptimizedCacheHit           bool             `protobuf:"varint,8,opt,name=optimized_cache_hit,json=optimizedCacheHit,proto3" json:"optimized_cache_hit,omitempty"`                              // Loaded from the optimized model cache
+	ActionDim                   int64            `protobuf:"varint,9,opt,name=action_dim,json=actionDim,proto3" json:"action_dim,omitempty"`                                                        // Raw model output size per observation
+	Devices                     []int32          `protobuf:"varint,10,rep,packed,name=devices,proto3" json:"devices,omitempty"`                                                                     // GPUs the model runs on, if any
+	InputShape                  []int64          `protobuf:"varint,11,rep,packed,name=input_shape,json=inputShape,proto3" json:"input_shape,omitempty"`                                             // Observation tensor shape from model metadata; -1 is dynamic
+	OutputShape                 []int64          `protobuf:"varint,12,rep,packed,name=output_shape,json=outputShape,proto3" json:"output_shape,omitempty"`                                          // Action tensor shape from model metadata; -1 is dynamic
+	ResidentModels              []*ResidentModel `protobuf:"bytes,13,rep,name=resident_models,json=residentModels,proto3" json:"resident_models,omitempty"`                                         // Loaded model versions, the serving one first
+	Sessions                    int32            `protobuf:"varint,14,opt,name=sessions,proto3" json:"sessions,omitempty"`                                                                          // Sessions running batches in parallel
+	RegistryModel               *RegistryModel   `protobuf:"bytes,15,opt,name=registry_model,json=registryModel,proto3" json:"registry_model,omitempty"`                                            // Registry version the model was resolved to; unset without a registry
+	ModelVersions               []string         `protobuf:"bytes,16,rep,name=model_versions,json=modelVersions,proto3" json:"model_versions,omitempty"`                                            // Versions requests may pin, the latest first; empty when model_versions is unset
+}
+
+func (x *ModelInfo) Reset() {
+	*x = ModelInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModelInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelInfo) ProtoMessage() {}
+
+func (x *ModelInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelInfo.ProtoReflect.Descriptor instead.
+func (*ModelInfo) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ModelInfo) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetOrtVersion() string {
+	if x != nil {
+		return x.OrtVersion
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetAppendedExecutionProviders() []string {
+	if x != nil {
+		return x.AppendedExecutionProviders
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetRequestedExecutionProviders() []string {
+	if x != nil {
+		return x.RequestedExecutionProviders
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetProviderErrors() []string {
+	if x != nil {
+		return x.ProviderErrors
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetIntraOpThreads() int32 {
+	if x != nil {
+		return x.IntraOpThreads
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetInterOpThreads() int32 {
+	if x != nil {
+		return x.InterOpThreads
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetOptimizedCacheHit() bool {
+	if x != nil {
+		return x.OptimizedCacheHit
+	}
+	return false
+}
+
+func (x *ModelInfo) GetActionDim() int64 {
+	if x != nil {
+		return x.ActionDim
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetDevices() []int32 {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetInputShape() []int64 {
+	if x != nil {
+		return x.InputShape
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetOutputShape() []int64 {
+	if x != nil {
+		return x.OutputShape
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetResidentModels() []*ResidentModel {
+	if x != nil {
+		return x.ResidentModels
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetRegistryModel() *RegistryModel {
+	if x != nil {
+		return x.RegistryModel
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetModelVersions() []string {
+	if x != nil {
+		return x.ModelVersions
+	}
+	return nil
+}
+
+// RegistryModel is the model registry version the served model was resolved
+// to at startup
+type RegistryModel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                  // Registered model name
+	Stage       string `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`                                // Stage of the version when resolved, e.g. Production
+	Version     string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`                            // Registry version number
+	ArtifactUri string `protobuf:"bytes,4,opt,name=artifact_uri,json=artifactUri,proto3" json:"artifact_uri,omitempty"` // Artifact directory the model was downloaded from
+}
+
+func (x *RegistryModel) Reset() {
+	*x = RegistryModel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegistryModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistryModel) ProtoMessage() {}
+
+func (x *RegistryModel) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistryModel.ProtoReflect.Descriptor instead.
+func (*RegistryModel) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RegistryModel) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegistryModel) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *RegistryModel) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegistryModel) GetArtifactUri() string {
+	if x != nil {
+		return x.ArtifactUri
+	}
+	return ""
+}
+
+// ResidentModel is a model version loaded in memory: the one serving traffic,
+// or the previous one kept loaded after a reload for instant rollback
+type ResidentModel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version        string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`                                        // Start of the model file's SHA-256 digest; empty if unknown
+	Active         bool   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`                                         // Serving traffic; false for the spare
+	LoadedUnixNano int64  `protobuf:"varint,3,opt,name=loaded_unix_nano,json=loadedUnixNano,proto3" json:"loaded_unix_nano,omitempty"` // When the version was loaded
+}
+
+func (x *ResidentModel) Reset() {
+	*x = ResidentModel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResidentModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResidentModel) ProtoMessage() {}
+
+func (x *ResidentModel) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResidentModel.ProtoReflect.Descriptor instead.
+func (*ResidentModel) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ResidentModel) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ResidentModel) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *ResidentModel) GetLoadedUnixNano() int64 {
+	if x != nil {
+		return x.LoadedUnixNano
+	}
+	return 0
+}
+
+// CapabilitiesRequest is empty; the serving contract is described
+type CapabilitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CapabilitiesRequest) Reset() {
+	*x = CapabilitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilitiesRequest) ProtoMessage() {}
+
+func (x *CapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*CapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{16}
+}
+
+// Capabilities is the contract every Plan response is checked against
+type Capabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Model                string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`                                                           // Model path
+	ObservationShape     []int64                `protobuf:"varint,2,rep,packed,name=observation_shape,json=observationShape,proto3" json:"observation_shape,omitempty"`     // Model input shape incl. batch; -1 is dynamic
+	ActionDim            uint32                 `protobuf:"varint,3,opt,name=action_dim,json=actionDim,proto3" json:"action_dim,omitempty"`                                 // Values per returned action; 0 if unknown
+	ActionDtype          string                 `protobuf:"bytes,4,opt,name=action_dtype,json=actionDtype,proto3" json:"action_dtype,omitempty"`                            // Element type of actions, always "float32"
+	ActionBounds         []*ActionBound         `protobuf:"bytes,5,rep,name=action_bounds,json=actionBounds,proto3" json:"action_bounds,omitempty"`                         // One per action value, or one for all; empty is unbounded
+	ActionMode           string                 `protobuf:"bytes,6,opt,name=action_mode,json=actionMode,proto3" json:"action_mode,omitempty"`                               // "continuous", "argmax", or "sample"
+	DiscreteActions      uint32                 `protobuf:"varint,7,opt,name=discrete_actions,json=discreteActions,proto3" json:"discrete_actions,omitempty"`               // Size of the discrete action set, if any
+	ActionSpace          *ActionSpace           `protobuf:"bytes,8,opt,name=action_space,json=actionSpace,proto3" json:"action_space,omitempty"`                            // How to interpret each returned action
+	ObservationEncodings []*ObservationEncoding `protobuf:"bytes,9,rep,name=observation_encodings,json=observationEncodings,proto3" json:"observation_encodings,omitempty"` // Codecs accepted in Observation.encoding
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Capabilities) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Capabilities) GetObservationShape() []int64 {
+	if x != nil {
+		return x.ObservationShape
+	}
+	return nil
+}
+
+func (x *Capabilities) GetActionDim() uint32 {
+	if x != nil {
+		return x.ActionDim
+	}
+	return 0
+}
+
+func (x *Capabilities) GetActionDtype() string {
+	if x != nil {
+		return x.ActionDtype
+	}
+	return ""
+}
+
+func (x *Capabilities) GetActionBounds() []*ActionBound {
+	if x != nil {
+		return x.ActionBounds
+	}
+	return nil
+}
+
+func (x *Capabilities) GetActionMode() string {
+	if x != nil {
+		return x.ActionMode
+	}
+	return ""
+}
+
+func (x *Capabilities) GetDiscreteActions() uint32 {
+	if x != nil {
+		return x.DiscreteActions
+	}
+	return 0
+}
+
+func (x *Capabilities) GetActionSpace() *ActionSpace {
+	if x != nil {
+		return x.ActionSpace
+	}
+	return nil
+}
+
+func (x *Capabilities) GetObservationEncodings() []*ObservationEncoding {
+	if x != nil {
+		return x.ObservationEncodings
+	}
+	return nil
+}
+
+// ObservationEncoding is an observation codec registered with the server
+type ObservationEncoding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   uint32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`    // Value of Observation.encoding
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // e.g. "float16", "jpeg"
+}
+
+func (x *ObservationEncoding) Reset() {
+	*x = ObservationEncoding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObservationEncoding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObservationEncoding) ProtoMessage() {}
+
+func (x *ObservationEncoding) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObservationEncoding.ProtoReflect.Descriptor instead.
+func (*ObservationEncoding) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ObservationEncoding) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ObservationEncoding) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ActionBound is the inclusive range of an action value
+type ActionBound struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min float32 `protobuf:"fixed32,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max float32 `protobuf:"fixed32,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *ActionBound) Reset() {
+	*x = ActionBound{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActionBound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionBound) ProtoMessage() {}
+
+func (x *ActionBound) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionBound.ProtoReflect.Descriptor instead.
+func (*ActionBound) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ActionBound) GetMin() float32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ActionBound) GetMax() float32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+// ActionSpace tells downstream tooling (recorders, dashboards, safety
+// filters) how to interpret the raw values of an action
+type ActionSpace struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    string             `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`       // "continuous" or "discrete"
+	Dims    []*ActionDimension `protobuf:"bytes,2,rep,name=dims,proto3" json:"dims,omitempty"`       // One per action value; empty if the dimension is unknown
+	Choices []*DiscreteAction  `protobuf:"bytes,3,rep,name=choices,proto3" json:"choices,omitempty"` // The discrete action set, by index; empty if continuous
+}
+
+func (x *ActionSpace) Reset() {
+	*x = ActionSpace{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActionSpace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionSpace) ProtoMessage() {}
+
+func (x *ActionSpace) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionSpace.ProtoReflect.Descriptor instead.
+func (*ActionSpace) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ActionSpace) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ActionSpace) GetDims() []*ActionDimension {
+	if x != nil {
+		return x.Dims
+	}
+	return nil
+}
+
+func (x *ActionSpace) GetChoices() []*DiscreteAction {
+	if x != nil {
+		return x.Choices
+	}
+	return nil
+}
+
+// ActionDimension is one value of an action
+type ActionDimension struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`   // Configured name, e.g. "linear_x"; may be empty
+	Bound *ActionBound `protobuf:"bytes,2,opt,name=bound,proto3" json:"bound,omitempty"` // Unset if the value is unbounded
+}
+
+func (x *ActionDimension) Reset() {
+	*x = ActionDimension{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActionDimension) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionDimension) ProtoMessage() {}
+
+func (x *ActionDimension) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionDimension.ProtoReflect.Descriptor instead.
+func (*ActionDimension) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ActionDimension) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ActionDimension) GetBound() *ActionBound {
+	if x != nil {
+		return x.Bound
+	}
+	return nil
+}
+
+// DiscreteAction is one member of a discrete action set
+type DiscreteAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index  uint32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Label  string    `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`            // Configured label, e.g. "forward"; may be empty
+	Values []float32 `protobuf:"fixed32,3,rep,packed,name=values,proto3" json:"values,omitempty"` // Action values returned when it is selected
+}
+
+func (x *DiscreteAction) Reset() {
+	*x = DiscreteAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiscreteAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscreteAction) ProtoMessage() {}
+
+func (x *DiscreteAction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscreteAction.ProtoReflect.Descriptor instead.
+func (*DiscreteAction) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DiscreteAction) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *DiscreteAction) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *DiscreteAction) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// CommitPlanRequest confirms the proposal issued with token
+type CommitPlanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *CommitPlanRequest) Reset() {
+	*x = CommitPlanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitPlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitPlanRequest) ProtoMessage() {}
+
+func (x *CommitPlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitPlanRequest.ProtoReflect.Descriptor instead.
+func (*CommitPlanRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CommitPlanRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// CommitPlanResponse is the decision on a proposal; the robot applies the
+// action only when committed is true
+type CommitPlanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Committed  bool      `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	VetoReason string    `protobuf:"bytes,2,opt,name=veto_reason,json=vetoReason,proto3" json:"veto_reason,omitempty"` // Set when a supervisor vetoed the proposal
+	Action     []float32 `protobuf:"fixed32,3,rep,packed,name=action,proto3" json:"action,omitempty"`                  // The committed action, as proposed
+}
+
+func (x *CommitPlanResponse) Reset() {
+	*x = CommitPlanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitPlanResponse) ProtoMessage() {}
+
+func (x *CommitPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitPlanResponse.ProtoReflect.Descriptor instead.
+func (*CommitPlanResponse) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CommitPlanResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *CommitPlanResponse) GetVetoReason() string {
+	if x != nil {
+		return x.VetoReason
+	}
+	return ""
+}
+
+func (x *CommitPlanResponse) GetAction() []float32 {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+// VetoPlanRequest rejects the proposal issued with token
+type VetoPlanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token  string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *VetoPlanRequest) Reset() {
+	*x = VetoPlanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VetoPlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VetoPlanRequest) ProtoMessage() {}
+
+func (x *VetoPlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VetoPlanRequest.ProtoReflect.Descriptor instead.
+func (*VetoPlanRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *VetoPlanRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *VetoPlanRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// VetoPlanResponse reports whether the veto took effect; it does not once
+// the veto window has passed
+type VetoPlanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vetoed bool `protobuf:"varint,1,opt,name=vetoed,proto3" json:"vetoed,omitempty"`
+}
+
+func (x *VetoPlanResponse) Reset() {
+	*x = VetoPlanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VetoPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VetoPlanResponse) ProtoMessage() {}
+
+func (x *VetoPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VetoPlanResponse.ProtoReflect.Descriptor instead.
+func (*VetoPlanResponse) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *VetoPlanResponse) GetVetoed() bool {
+	if x != nil {
+		return x.Vetoed
+	}
+	return false
+}
+
+// WatchProposalsRequest is empty; the caller's tenant's proposals are streamed
+type WatchProposalsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchProposalsRequest) Reset() {
+	*x = WatchProposalsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchProposalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchProposalsRequest) ProtoMessage() {}
+
+func (x *WatchProposalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchProposalsRequest.ProtoReflect.Descriptor instead.
+func (*WatchProposalsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{27}
+}
+
+// PlanProposal is a proposed action awaiting its veto window
+type PlanProposal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token                string    `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RobotId              uint64    `protobuf:"varint,2,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Action               []float32 `protobuf:"fixed32,3,rep,packed,name=action,proto3" json:"action,omitempty"`
+	ObsHash              string    `protobuf:"bytes,4,opt,name=obs_hash,json=obsHash,proto3" json:"obs_hash,omitempty"`
+	ProposedUnixNano     int64     `protobuf:"varint,5,opt,name=proposed_unix_nano,json=proposedUnixNano,proto3" json:"proposed_unix_nano,omitempty"`
+	VetoDeadlineUnixNano int64     `protobuf:"varint,6,opt,name=veto_deadline_unix_nano,json=vetoDeadlineUnixNano,proto3" json:"veto_deadline_unix_nano,omitempty"` // Vetoes after this time have no effect
+}
+
+func (x *PlanProposal) Reset() {
+	*x = PlanProposal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanProposal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanProposal) ProtoMessage() {}
+
+func (x *PlanProposal) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanProposal.ProtoReflect.Descriptor instead.
+func (*PlanProposal) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PlanProposal) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PlanProposal) GetRobotId() uint64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *PlanProposal) GetAction() []float32 {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+func (x *PlanProposal) GetObsHash() string {
+	if x != nil {
+		return x.ObsHash
+	}
+	return ""
+}
+
+func (x *PlanProposal) GetProposedUnixNano() int64 {
+	if x != nil {
+		return x.ProposedUnixNano
+	}
+	return 0
+}
+
+func (x *PlanProposal) GetVetoDeadlineUnixNano() int64 {
+	if x != nil {
+		return x.VetoDeadlineUnixNano
+	}
+	return 0
+}
+
+// PlanUpdatesRequest schedules a robot for planning and waits for its next plan
+type PlanUpdatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId       uint64       `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Obs           *Observation `protobuf:"bytes,2,opt,name=obs,proto3" json:"obs,omitempty"`                                             // Latest observation; omit to keep planning from the last one sent
+	Pose          *Pose        `protobuf:"bytes,3,opt,name=pose,proto3" json:"pose,omitempty"`                                           // Latest pose, cached as with PlanRequest.pose
+	AfterUnixNano int64        `protobuf:"varint,4,opt,name=after_unix_nano,json=afterUnixNano,proto3" json:"after_unix_nano,omitempty"` // planned_unix_nano of the last update received; 0 accepts the latest plan
+}
+
+func (x *PlanUpdatesRequest) Reset() {
+	*x = PlanUpdatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanUpdatesRequest) ProtoMessage() {}
+
+func (x *PlanUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*PlanUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PlanUpdatesRequest) GetRobotId() uint64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *PlanUpdatesRequest) GetObs() *Observation {
+	if x != nil {
+		return x.Obs
+	}
+	return nil
+}
+
+func (x *PlanUpdatesRequest) GetPose() *Pose {
+	if x != nil {
+		return x.Pose
+	}
+	return nil
+}
+
+func (x *PlanUpdatesRequest) GetAfterUnixNano() int64 {
+	if x != nil {
+		return x.AfterUnixNano
+	}
+	return 0
+}
+
+// PlanStreamRequest starts replanning a robot at a fixed rate
+type PlanStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request    *PlanRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	IntervalMs uint32       `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"` // Time between plans; 0 uses the server default, and values below the server minimum are raised to it
+}
+
+func (x *PlanStreamRequest) Reset() {
+	*x = PlanStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanStreamRequest) ProtoMessage() {}
+
+func (x *PlanStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanStreamRequest.ProtoReflect.Descriptor instead.
+func (*PlanStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PlanStreamRequest) GetRequest() *PlanRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *PlanStreamRequest) GetIntervalMs() uint32 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+// StreamPlanRequest is one observation sent on a StreamPlan call
+type StreamPlanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request *PlanRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Seq     uint64       `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"` // Client sequence number, echoed in the response
+}
+
+func (x *StreamPlanRequest) Reset() {
+	*x = StreamPlanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamPlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPlanRequest) ProtoMessage() {}
+
+func (x *StreamPlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPlanRequest.ProtoReflect.Descriptor instead.
+func (*StreamPlanRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *StreamPlanRequest) GetRequest() *PlanRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *StreamPlanRequest) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// StreamPlanResponse answers one StreamPlanRequest, in the order received. A
+// failed plan sets error_code and error and leaves the stream open.
+type StreamPlanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq       uint64        `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`                              // seq of the request answered
+	RequestId string        `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`  // Per-message request ID: the stream's x-request-id and the message's position on the stream
+	Response  *PlanResponse `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`                     // Unset if planning failed
+	ErrorCode int32         `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // gRPC status code of a failed plan; 0 on success
+	Error     string        `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                           // Error message of a failed plan
+}
+
+func (x *StreamPlanResponse) Reset() {
+	*x = StreamPlanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPlanResponse) ProtoMessage() {}
+
+func (x *StreamPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPlanResponse.ProtoReflect.Descriptor instead.
+func (*StreamPlanResponse) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *StreamPlanResponse) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *StreamPlanResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *StreamPlanResponse) GetResponse() *PlanResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *StreamPlanResponse) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *StreamPlanResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// WarmupRequest sets how the model is warmed up
+type WarmupRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Inferences uint32   `protobuf:"varint,1,opt,name=inferences,proto3" json:"inferences,omitempty"`                          // Per batch size; 0 uses the server's warmup_inferences, or 1
+	BatchSizes []uint32 `protobuf:"varint,2,rep,packed,name=batch_sizes,json=batchSizes,proto3" json:"batch_sizes,omitempty"` // Empty uses the server's warmup_batch_sizes
+}
+
+func (x *WarmupRequest) Reset() {
+	*x = WarmupRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WarmupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarmupRequest) ProtoMessage() {}
+
+func (x *WarmupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarmupRequest.ProtoReflect.Descriptor instead.
+func (*WarmupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *WarmupRequest) GetInferences() uint32 {
+	if x != nil {
+		return x.Inferences
+	}
+	return 0
+}
+
+func (x *WarmupRequest) GetBatchSizes() []uint32 {
+	if x != nil {
+		return x.BatchSizes
+	}
+	return nil
+}
+
+// WarmupResponse reports the latency of each batch size warmed up
+type WarmupResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Batches []*WarmupBatch `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
+}
+
+func (x *WarmupResponse) Reset() {
+	*x = WarmupResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WarmupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarmupResponse) ProtoMessage() {}
+
+func (x *WarmupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarmupResponse.ProtoReflect.Descriptor instead.
+func (*WarmupResponse) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *WarmupResponse) GetBatches() []*WarmupBatch {
+	if x != nil {
+		return x.Batches
+	}
+	return nil
+}
+
+// WarmupBatch is the latency of warming up one batch size
+type WarmupBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchSize  uint32  `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	Inferences uint32  `protobuf:"varint,2,opt,name=inferences,proto3" json:"inferences,omitempty"`
+	FirstMs    float64 `protobuf:"fixed64,3,opt,name=first_ms,json=firstMs,proto3" json:"first_ms,omitempty"` // Latency of the first inference
+	LastMs     float64 `protobuf:"fixed64,4,opt,name=last_ms,json=lastMs,proto3" json:"last_ms,omitempty"`    // Latency of the last inference
+}
+
+func (x *WarmupBatch) Reset() {
+	*x = WarmupBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WarmupBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarmupBatch) ProtoMessage() {}
+
+func (x *WarmupBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarmupBatch.ProtoReflect.Descriptor instead.
+func (*WarmupBatch) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WarmupBatch) GetBatchSize() uint32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *WarmupBatch) GetInferences() uint32 {
+	if x != nil {
+		return x.Inferences
+	}
+	return 0
+}
+
+func (x *WarmupBatch) GetFirstMs() float64 {
+	if x != nil {
+		return x.FirstMs
+	}
+	return 0
+}
+
+func (x *WarmupBatch) GetLastMs() float64 {
+	if x != nil {
+		return x.LastMs
+	}
+	return 0
+}
+
+// IssueRobotTokenRequest names the robot a token is issued for
+type IssueRobotTokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId    uint64 `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`          // Robot the token is issued for; required
+	TtlSeconds uint32 `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Token lifetime; 0 uses robot_token_ttl, at most robot_token_max_ttl
+}
+
+func (x *IssueRobotTokenRequest) Reset() {
+	*x = IssueRobotTokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IssueRobotTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueRobotTokenRequest) ProtoMessage() {}
+
+func (x *IssueRobotTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueRobotTokenRequest.ProtoReflect.Descriptor instead.
+func (*IssueRobotTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *IssueRobotTokenRequest) GetRobotId() uint64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *IssueRobotTokenRequest) GetTtlSeconds() uint32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+// IssueRobotTokenResponse holds the token, sent as "authorization: Bearer <token>"
+type IssueRobotTokenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token           string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	KeyId           string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`                                  // Signing key, as in the token's kid header
+	ExpiresUnixNano int64  `protobuf:"varint,3,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"` // Server time after which the token is rejected
+}
+
+func (x *IssueRobotTokenResponse) Reset() {
+	*x = IssueRobotTokenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IssueRobotTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueRobotTokenResponse) ProtoMessage() {}
+
+func (x *IssueRobotTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueRobotTokenResponse.ProtoReflect.Descriptor instead.
+func (*IssueRobotTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *IssueRobotTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *IssueRobotTokenResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *IssueRobotTokenResponse) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
+
+// PlanUpdate is a robot's scheduled plan
+type PlanUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response         *PlanResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`                                              // Unset if no newer plan was made before the poll timed out
+	PlannedUnixNano  int64         `protobuf:"varint,2,opt,name=planned_unix_nano,json=plannedUnixNano,proto3" json:"planned_unix_nano,omitempty"`      // When the plan was made; pass as after_unix_nano on the next poll
+	NextPlanUnixNano int64         `protobuf:"varint,3,opt,name=next_plan_unix_nano,json=nextPlanUnixNano,proto3" json:"next_plan_unix_nano,omitempty"` // When the server plans next; the robot may sleep until then
+}
+
+func (x *PlanUpdate) Reset() {
+	*x = PlanUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_planner_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanUpdate) ProtoMessage() {}
+
+func (x *PlanUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_planner_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanUpdate.ProtoReflect.Descriptor instead.
+func (*PlanUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_planner_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PlanUpdate) GetResponse() *PlanResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *PlanUpdate) GetPlannedUnixNano() int64 {
+	if x != nil {
+		return x.PlannedUnixNano
+	}
+	return 0
+}
+
+func (x *PlanUpdate) GetNextPlanUnixNano() int64 {
+	if x != nil {
+		return x.NextPlanUnixNano
+	}
+	return 0
+}
+
+var File_proto_planner_proto protoreflect.FileDescriptor
+
+var file_proto_planner_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x22, 0xc9,
+	0x01, 0x0a, 0x0b, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a,
+	0x75, 0x69, 0x6e, 0x74, 0x38, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x75, 0x69, 0x6e, 0x74, 0x38, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x6e, 0x63, 0x6f, 0x64,
+	0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x22, 0x98, 0x01, 0x0a, 0x0b, 0x50,
+	0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x03, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x4f, 0x62, 0x73,
+	0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x6f, 0x62, 0x73, 0x12, 0x21, 0x0a,
+	0x04, 0x70, 0x6f, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x65, 0x52, 0x04, 0x70, 0x6f, 0x73, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x84, 0x01, 0x0a, 0x04, 0x50, 0x6f, 0x73, 0x65, 0x12, 0x0c,
+	0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x68,
+	0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x68, 0x65, 0x74, 0x61,
+	0x12, 0x1a, 0x0a, 0x08, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x08, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x12, 0x2e, 0x0a, 0x13,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e,
+	0x61, 0x6e, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x22, 0xba, 0x03, 0x0a,
+	0x0c, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x66, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x61, 0x66, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70,
+	0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x62, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x62, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2f,
+	0x0a, 0x13, 0x64, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x6c,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x64, 0x65, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1f, 0x0a, 0x0b, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x4d, 0x73,
+	0x12, 0x2a, 0x0a, 0x11, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x75, 0x6e, 0x69, 0x78,
+	0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x73, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x2a, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x6f, 0x73, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73,
+	0x65, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x50, 0x6f, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0c, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x78, 0x0a, 0x11, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x15,
+	0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x11, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x55, 0x6e, 0x69,
+	0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x22, 0x44, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x48, 0x0a, 0x11, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33,
+	0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x73, 0x22, 0x69, 0x0a, 0x16, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x5d,
+	0x0a, 0x0e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x33, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22, 0x24, 0x0a,
+	0x0a, 0x50, 0x6c, 0x61, 0x6e, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63,
+	0x6b, 0x65, 0x74, 0x22, 0xbb, 0x01, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x36, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x33, 0x0a, 0x17, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xb5, 0x05, 0x0a, 0x09, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6f, 0x72, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x72, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x40, 0x0a, 0x1c, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x1a, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x73, 0x12, 0x42, 0x0a, 0x1d, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x1b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x65, 0x64, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x28,
+	0x0a, 0x10, 0x69, 0x6e, 0x74, 0x72, 0x61, 0x5f, 0x6f, 0x70, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x69, 0x6e, 0x74, 0x72, 0x61, 0x4f,
+	0x70, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x5f, 0x6f, 0x70, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x4f, 0x70, 0x54, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65, 0x64, 0x5f,
+	0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x68, 0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x11, 0x6f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65, 0x64, 0x43, 0x61, 0x63, 0x68, 0x65, 0x48,
+	0x69, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x6d,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69,
+	0x6d, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03,
+	0x28, 0x05, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x03,
+	0x52, 0x0a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x68, 0x61, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x70, 0x65, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x03, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x68, 0x61, 0x70, 0x65, 0x12,
+	0x3f, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x6c,
+	0x52, 0x0e, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3d, 0x0a, 0x0e,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x0d, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x10, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x76, 0x0a, 0x0d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x55, 0x72, 0x69, 0x22, 0x6b, 0x0a, 0x0d, 0x52, 0x65,
+	0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x28, 0x0a,
+	0x10, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e,
+	0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x55,
+	0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x22, 0x15, 0x0a, 0x13, 0x43, 0x61, 0x70, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xa6,
+	0x03, 0x0a, 0x0c, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x2b, 0x0a, 0x11, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x68, 0x61, 0x70, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x03,
+	0x52, 0x10, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x68, 0x61,
+	0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69,
+	0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x39, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x62,
+	0x6f, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f, 0x75, 0x6e,
+	0x64, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65,
+	0x12, 0x29, 0x0a, 0x10, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x74, 0x65, 0x5f, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x64, 0x69, 0x73, 0x63,
+	0x72, 0x65, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x37, 0x0a, 0x0c, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x15, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x4f, 0x62,
+	0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x14, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e,
+	0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x39, 0x0a, 0x13, 0x4f, 0x62, 0x73, 0x65, 0x72,
+	0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x22, 0x31, 0x0a, 0x0b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f, 0x75, 0x6e,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x03,
+	0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0x82, 0x01, 0x0a, 0x0b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x2c, 0x0a, 0x04, 0x64, 0x69, 0x6d,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
+	0x72, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x04, 0x64, 0x69, 0x6d, 0x73, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x0f, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x2a, 0x0a, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x52, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0x54, 0x0a,
+	0x0e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x02, 0x52, 0x06, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x22, 0x29, 0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x50, 0x6c, 0x61,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x6b,
+	0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74,
+	0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x65, 0x74, 0x6f, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x65, 0x74, 0x6f, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x02, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3f, 0x0a, 0x0f, 0x56,
+	0x65, 0x74, 0x6f, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x2a, 0x0a, 0x10,
+	0x56, 0x65, 0x74, 0x6f, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x74, 0x6f, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x76, 0x65, 0x74, 0x6f, 0x65, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0xd7, 0x01, 0x0a, 0x0c, 0x50, 0x6c, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f,
+	0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x02, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6f,
+	0x62, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f,
+	0x62, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x65, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x64, 0x55, 0x6e, 0x69, 0x78,
+	0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x35, 0x0a, 0x17, 0x76, 0x65, 0x74, 0x6f, 0x5f, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x76, 0x65, 0x74, 0x6f, 0x44, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x22, 0xa2, 0x01, 0x0a, 0x12,
+	0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x26, 0x0a,
+	0x03, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61,
+	0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x03, 0x6f, 0x62, 0x73, 0x12, 0x21, 0x0a, 0x04, 0x70, 0x6f, 0x73, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6f,
+	0x73, 0x65, 0x52, 0x04, 0x70, 0x6f, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x66, 0x74, 0x65,
+	0x72, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0d, 0x61, 0x66, 0x74, 0x65, 0x72, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f,
+	0x22, 0x64, 0x0a, 0x11, 0x50, 0x6c, 0x61, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72,
+	0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x22, 0x55, 0x0a, 0x11, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x07, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70,
+	0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x22, 0xad, 0x01,
+	0x0a, 0x12, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
+	0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x50, 0x0a,
+	0x0d, 0x57, 0x61, 0x72, 0x6d, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e,
+	0x0a, 0x0a, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0a, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x22,
+	0x40, 0x0a, 0x0e, 0x57, 0x61, 0x72, 0x6d, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2e, 0x0a, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x57, 0x61, 0x72,
+	0x6d, 0x75, 0x70, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x73, 0x22, 0x80, 0x01, 0x0a, 0x0b, 0x57, 0x61, 0x72, 0x6d, 0x75, 0x70, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x12, 0x19, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x07, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6c, 0x61,
+	0x73, 0x74, 0x4d, 0x73, 0x22, 0x63, 0x0a, 0x16, 0x49, 0x73, 0x73, 0x75, 0x65, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x74, 0x6c,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x74, 0x74, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03,
+	0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x72, 0x0a, 0x17, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65,
+	0x79, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49,
+	0x64, 0x12, 0x2a, 0x0a, 0x11, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x75, 0x6e, 0x69,
+	0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x22, 0x9a, 0x01,
+	0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f,
+	0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x64, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x2d, 0x0a, 0x13, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61,
+	0x6e, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x6c,
+	0x61, 0x6e, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x2a, 0x4f, 0x0a, 0x09, 0x50, 0x6c,
+	0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x4c, 0x41, 0x4e, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12,
+	0x13, 0x0a, 0x0f, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x44, 0x4f,
+	0x4e, 0x45, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x32, 0x9c, 0x09, 0x0a, 0x0b,
+	0x50, 0x61, 0x74, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x04, 0x50,
+	0x6c, 0x61, 0x6e, 0x12, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c,
+	0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x42, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x19, 0x2e,
+	0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61,
+	0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1f, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
+	0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x30, 0x01, 0x12, 0x3b, 0x0a, 0x09, 0x50, 0x6c, 0x61, 0x6e, 0x41, 0x73, 0x79, 0x6e, 0x63,
+	0x12, 0x19, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x74,
+	0x12, 0x39, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e,
+	0x54, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72,
+	0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4b, 0x0a, 0x10, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12,
+	0x20, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50,
+	0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01, 0x12, 0x3d, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x4d, 0x6f,
+	0x64, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x46, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x6c, 0x61,
+	0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x45, 0x0a, 0x0a, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x1a, 0x2e,
+	0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x50, 0x6c,
+	0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x56, 0x65, 0x74, 0x6f, 0x50, 0x6c,
+	0x61, 0x6e, 0x12, 0x18, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x56, 0x65, 0x74,
+	0x6f, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70,
+	0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x56, 0x65, 0x74, 0x6f, 0x50, 0x6c, 0x61, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x73, 0x12, 0x1e, 0x2e, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61,
+	0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c,
+	0x30, 0x01, 0x12, 0x42, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50,
+	0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x6e, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x1a, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50,
+	0x6c, 0x61, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x15, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x49, 0x0a, 0x0a, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x1a, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
+	0x72, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x28, 0x01, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x0d, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x14, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e,
+	0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x39, 0x0a, 0x06, 0x57, 0x61, 0x72,
+	0x6d, 0x75, 0x70, 0x12, 0x16, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x57, 0x61,
+	0x72, 0x6d, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x2e, 0x57, 0x61, 0x72, 0x6d, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0f, 0x49, 0x73, 0x73, 0x75, 0x65, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1f, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65,
+	0x72, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x79, 0x65, 0x64, 0x44, 0x61, 0x69,
+	0x61, 0x6d, 0x39, 0x31, 0x30, 0x31, 0x2f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2d, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_planner_proto_rawDescOnce sync.Once
+	file_proto_planner_proto_rawDescData = file_proto_planner_proto_rawDesc
+)
+
+func file_proto_planner_proto_rawDescGZIP() []byte {
+	file_proto_planner_proto_rawDescOnce.Do(func() {
+		file_proto_planner_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_planner_proto_rawDescData)
+	})
+	return file_proto_planner_proto_rawDescData
+}
+
+var file_proto_planner_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_planner_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
+var file_proto_planner_proto_goTypes = []interface{}{
+	(PlanState)(0),                  // 0: planner.PlanState
+	(*Observation)(nil),             // 1: planner.Observation
+	(*PlanRequest)(nil),             // 2: planner.PlanRequest
+	(*Pose)(nil),                    // 3: planner.Pose
+	(*PlanResponse)(nil),            // 4: planner.PlanResponse
+	(*ResponseSignature)(nil),       // 5: planner.ResponseSignature
+	(*BatchPlanRequest)(nil),        // 6: planner.BatchPlanRequest
+	(*BatchPlanResponse)(nil),       // 7: planner.BatchPlanResponse
+	(*BatchPlanStreamRequest)(nil),  // 8: planner.BatchPlanStreamRequest
+	(*BatchPlanChunk)(nil),          // 9: planner.BatchPlanChunk
+	(*PlanTicket)(nil),              // 10: planner.PlanTicket
+	(*PlanResult)(nil),              // 11: planner.PlanResult
+	(*WatchPlanResultsRequest)(nil), // 12: planner.WatchPlanResultsRequest
+	(*ModelInfoRequest)(nil),        // 13: planner.ModelInfoRequest
+	(*ModelInfo)(nil),               // 14: planner.ModelInfo
+	(*RegistryModel)(nil),           // 15: planner.RegistryModel
+	(*ResidentModel)(nil),           // 16: planner.ResidentModel
+	(*CapabilitiesRequest)(nil),     // 17: planner.CapabilitiesRequest
+	(*Capabilities)(nil),            // 18: planner.Capabilities
+	(*ObservationEncoding)(nil),     // 19: planner.ObservationEncoding
+	(*ActionBound)(nil),             // 20: planner.ActionBound
+	(*ActionSpace)(nil),             // 21: planner.ActionSpace
+	(*ActionDimension)(nil),         // 22: planner.ActionDimension
+	(*DiscreteAction)(nil),          // 23: planner.DiscreteAction
+	(*CommitPlanRequest)(nil),       // 24: planner.CommitPlanRequest
+	(*CommitPlanResponse)(nil),      // 25: planner.CommitPlanResponse
+	(*VetoPlanRequest)(nil),         // 26: planner.VetoPlanRequest
+	(*VetoPlanResponse)(nil),        // 27: planner.VetoPlanResponse
+	(*WatchProposalsRequest)(nil),   // 28: planner.WatchProposalsRequest
+	(*PlanProposal)(nil),            // 29: planner.PlanProposal
+	(*PlanUpdatesRequest)(nil),      // 30: planner.PlanUpdatesRequest
+	(*PlanStreamRequest)(nil),       // 31: planner.PlanStreamRequest
+	(*StreamPlanRequest)(nil),       // 32: planner.StreamPlanRequest
+	(*StreamPlanResponse)(nil),      // 33: planner.StreamPlanResponse
+	(*WarmupRequest)(nil),           // 34: planner.WarmupRequest
+	(*WarmupResponse)(nil),          // 35: planner.WarmupResponse
+	(*WarmupBatch)(nil),             // 36: planner.WarmupBatch
+	(*IssueRobotTokenRequest)(nil),  // 37: planner.IssueRobotTokenRequest
+	(*IssueRobotTokenResponse)(nil), // 38: planner.IssueRobotTokenResponse
+	(*PlanUpdate)(nil),              // 39: planner.PlanUpdate
+}
+var file_proto_planner_proto_depIdxs = []int32{
+	1,  // 0: planner.PlanRequest.obs:type_name -> planner.Observation
+	3,  // 1: planner.PlanRequest.pose:type_name -> planner.Pose
+	5,  // 2: planner.PlanResponse.signature:type_name -> planner.ResponseSignature
+	3,  // 3: planner.PlanResponse.last_pose:type_name -> planner.Pose
+	21, // 4: planner.PlanResponse.action_space:type_name -> planner.ActionSpace
+	2,  // 5: planner.BatchPlanRequest.requests:type_name -> planner.PlanRequest
+	4,  // 6: planner.BatchPlanResponse.responses:type_name -> planner.PlanResponse
+	2,  // 7: planner.BatchPlanStreamRequest.requests:type_name -> planner.PlanRequest
+	4,  // 8: planner.BatchPlanChunk.responses:type_name -> planner.PlanResponse
+	0,  // 9: planner.PlanResult.state:type_name -> planner.PlanState
+	7,  // 10: planner.PlanResult.response:type_name -> planner.BatchPlanResponse
+	16, // 11: planner.ModelInfo.resident_models:type_name -> planner.ResidentModel
+	15, // 12: planner.ModelInfo.registry_model:type_name -> planner.RegistryModel
+	20, // 13: planner.Capabilities.action_bounds:type_name -> planner.ActionBound
+	21, // 14: planner.Capabilities.action_space:type_name -> planner.ActionSpace
+	19, // 15: planner.Capabilities.observation_encodings:type_name -> planner.ObservationEncoding
+	22, // 16: planner.ActionSpace.dims:type_name -> planner.ActionDimension
+	23, // 17: planner.ActionSpace.choices:type_name -> planner.DiscreteAction
+	20, // 18: planner.ActionDimension.bound:type_name -> planner.ActionBound
+	1,  // 19: planner.PlanUpdatesRequest.obs:type_name -> planner.Observation
+	3,  // 20: planner.PlanUpdatesRequest.pose:type_name -> planner.Pose
+	2,  // 21: planner.PlanStreamRequest.request:type_name -> planner.PlanRequest
+	2,  // 22: planner.StreamPlanRequest.request:type_name -> planner.PlanRequest
+	4,  // 23: planner.StreamPlanResponse.response:type_name -> planner.PlanResponse
+	36, // 24: planner.WarmupResponse.batches:type_name -> planner.WarmupBatch
+	4,  // 25: planner.PlanUpdate.response:type_name -> planner.PlanResponse
+	2,  // 26: planner.PathPlanner.Plan:input_type -> planner.PlanRequest
+	6,  // 27: planner.PathPlanner.BatchPlan:input_type -> planner.BatchPlanRequest
+	8,  // 28: planner.PathPlanner.BatchPlanStream:input_type -> planner.BatchPlanStreamRequest
+	6,  // 29: planner.PathPlanner.PlanAsync:input_type -> planner.BatchPlanRequest
+	10, // 30: planner.PathPlanner.GetPlanResult:input_type -> planner.PlanTicket
+	12, // 31: planner.PathPlanner.WatchPlanResults:input_type -> planner.WatchPlanResultsRequest
+	13, // 32: planner.PathPlanner.GetModelInfo:input_type -> planner.ModelInfoRequest
+	17, // 33: planner.PathPlanner.GetCapabilities:input_type -> planner.CapabilitiesRequest
+	24, // 34: planner.PathPlanner.CommitPlan:input_type -> planner.CommitPlanRequest
+	26, // 35: planner.PathPlanner.VetoPlan:input_type -> planner.VetoPlanRequest
+	28, // 36: planner.PathPlanner.WatchProposals:input_type -> planner.WatchProposalsRequest
+	30, // 37: planner.PathPlanner.GetPlanUpdates:input_type -> planner.PlanUpdatesRequest
+	31, // 38: planner.PathPlanner.PlanStream:input_type -> planner.PlanStreamRequest
+	32, // 39: planner.PathPlanner.StreamPlan:input_type -> planner.StreamPlanRequest
+	2,  // 40: planner.PathPlanner.AggregatePlan:input_type -> planner.PlanRequest
+	34, // 41: planner.PathPlanner.Warmup:input_type -> planner.WarmupRequest
+	37, // 42: planner.PathPlanner.IssueRobotToken:input_type -> planner.IssueRobotTokenRequest
+	4,  // 43: planner.PathPlanner.Plan:output_type -> planner.PlanResponse
+	7,  // 44: planner.PathPlanner.BatchPlan:output_type -> planner.BatchPlanResponse
+	9,  // 45: planner.PathPlanner.BatchPlanStream:output_type -> planner.BatchPlanChunk
+	10, // 46: planner.PathPlanner.PlanAsync:output_type -> planner.PlanTicket
+	11, // 47: planner.PathPlanner.GetPlanResult:output_type -> planner.PlanResult
+	11, // 48: planner.PathPlanner.WatchPlanResults:output_type -> planner.PlanResult
+	14, // 49: planner.PathPlanner.GetModelInfo:output_type -> planner.ModelInfo
+	18, // 50: planner.PathPlanner.GetCapabilities:output_type -> planner.Capabilities
+	25, // 51: planner.PathPlanner.CommitPlan:output_type -> planner.CommitPlanResponse
+	27, // 52: planner.PathPlanner.VetoPlan:output_type -> planner.VetoPlanResponse
+	29, // 53: planner.PathPlanner.WatchProposals:output_type -> planner.PlanProposal
+	39, // 54: planner.PathPlanner.GetPlanUpdates:output_type -> planner.PlanUpdate
+	4,  // 55: planner.PathPlanner.PlanStream:output_type -> planner.PlanResponse
+	33, // 56: planner.PathPlanner.StreamPlan:output_type -> planner.StreamPlanResponse
+	7,  // 57: planner.PathPlanner.AggregatePlan:output_type -> planner.BatchPlanResponse
+	35, // 58: planner.PathPlanner.Warmup:output_type -> planner.WarmupResponse
+	38, // 59: planner.PathPlanner.IssueRobotToken:output_type -> planner.IssueRobotTokenResponse
+	43, // [43:60] is the sub-list for method output_type
+	26, // [26:43] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_proto_planner_proto_init() }
+func file_proto_planner_proto_init() {
+	if File_proto_planner_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_planner_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Observation); i {
 			case 0:
 				return &v.state
@@ -398,8 +3226,128 @@ func file_proto_planner_proto_init() {
 				return nil
 			}
 		}
-		file_proto_planner_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PlanRequest); i {
+		file_proto_planner_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pose); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseSignature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchPlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchPlanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchPlanStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchPlanChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanTicket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPlanResultsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -410,8 +3358,8 @@ func file_proto_planner_proto_init() {
 				return nil
 			}
 		}
-		file_proto_planner_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PlanResponse); i {
+		file_proto_planner_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModelInfoRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -422,8 +3370,8 @@ func file_proto_planner_proto_init() {
 				return nil
 			}
 		}
-		file_proto_planner_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BatchPlanRequest); i {
+		file_proto_planner_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModelInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -434,8 +3382,296 @@ func file_proto_planner_proto_init() {
 				return nil
 			}
 		}
-		file_proto_planner_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BatchPlanResponse); i {
+		file_proto_planner_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegistryModel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResidentModel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CapabilitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Capabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObservationEncoding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionBound); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionSpace); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionDimension); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiscreteAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitPlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitPlanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VetoPlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VetoPlanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchProposalsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanProposal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanUpdatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamPlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamPlanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WarmupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WarmupResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WarmupBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IssueRobotTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IssueRobotTokenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_planner_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanUpdate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -452,17 +3688,18 @@ func file_proto_planner_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_planner_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   39,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_planner_proto_goTypes,
 		DependencyIndexes: file_proto_planner_proto_depIdxs,
+		EnumInfos:         file_proto_planner_proto_enumTypes,
 		MessageInfos:      file_proto_planner_proto_msgTypes,
 	}.Build()
 	File_proto_planner_proto = out.File
-	file_proto_planner_proto_rawDescGZIP()
+	file_proto_planner_proto_rawDesc = nil
 	file_proto_planner_proto_goTypes = nil
 	file_proto_planner_proto_depIdxs = nil
 }
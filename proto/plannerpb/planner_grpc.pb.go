@@ -19,8 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	PathPlanner_Plan_FullMethodName      = "/planner.PathPlanner/Plan"
-	PathPlanner_BatchPlan_FullMethodName = "/planner.PathPlanner/BatchPlan"
+	PathPlanner_Plan_FullMethodName             = "/planner.PathPlanner/Plan"
+	PathPlanner_BatchPlan_FullMethodName        = "/planner.PathPlanner/BatchPlan"
+	PathPlanner_BatchPlanStream_FullMethodName  = "/planner.PathPlanner/BatchPlanStream"
+	PathPlanner_PlanAsync_FullMethodName        = "/planner.PathPlanner/PlanAsync"
+	PathPlanner_GetPlanResult_FullMethodName    = "/planner.PathPlanner/GetPlanResult"
+	PathPlanner_WatchPlanResults_FullMethodName = "/planner.PathPlanner/WatchPlanResults"
+	PathPlanner_GetModelInfo_FullMethodName     = "/planner.PathPlanner/GetModelInfo"
+	PathPlanner_GetCapabilities_FullMethodName  = "/planner.PathPlanner/GetCapabilities"
+	PathPlanner_CommitPlan_FullMethodName       = "/planner.PathPlanner/CommitPlan"
+	PathPlanner_VetoPlan_FullMethodName         = "/planner.PathPlanner/VetoPlan"
+	PathPlanner_WatchProposals_FullMethodName   = "/planner.PathPlanner/WatchProposals"
+	PathPlanner_GetPlanUpdates_FullMethodName   = "/planner.PathPlanner/GetPlanUpdates"
+	PathPlanner_PlanStream_FullMethodName       = "/planner.PathPlanner/PlanStream"
+	PathPlanner_StreamPlan_FullMethodName       = "/planner.PathPlanner/StreamPlan"
+	PathPlanner_AggregatePlan_FullMethodName    = "/planner.PathPlanner/AggregatePlan"
+	PathPlanner_Warmup_FullMethodName           = "/planner.PathPlanner/Warmup"
+	PathPlanner_IssueRobotToken_FullMethodName  = "/planner.PathPlanner/IssueRobotToken"
 )
 
 // PathPlannerClient is the client API for PathPlanner service.
@@ -31,6 +46,56 @@ type PathPlannerClient interface {
 	Plan(ctx context.Context, in *PlanRequest, opts ...grpc.CallOption) (*PlanResponse, error)
 	// BatchPlan computes actions for multiple robot observations in a single call
 	BatchPlan(ctx context.Context, in *BatchPlanRequest, opts ...grpc.CallOption) (*BatchPlanResponse, error)
+	// BatchPlanStream splits a large batch into chunks and streams each chunk's
+	// responses as soon as it finishes; cancelling the call skips the rest
+	BatchPlanStream(ctx context.Context, in *BatchPlanStreamRequest, opts ...grpc.CallOption) (PathPlanner_BatchPlanStreamClient, error)
+	// PlanAsync enqueues a batch for background planning and returns a ticket
+	// immediately, for consumers that do not need an answer right away
+	PlanAsync(ctx context.Context, in *BatchPlanRequest, opts ...grpc.CallOption) (*PlanTicket, error)
+	// GetPlanResult returns the state of an async plan and, once done, its result
+	GetPlanResult(ctx context.Context, in *PlanTicket, opts ...grpc.CallOption) (*PlanResult, error)
+	// WatchPlanResults streams the result of each ticket as it completes and
+	// ends once every ticket has been answered
+	WatchPlanResults(ctx context.Context, in *WatchPlanResultsRequest, opts ...grpc.CallOption) (PathPlanner_WatchPlanResultsClient, error)
+	// GetModelInfo reports the serving model and how ONNX Runtime executes it
+	GetModelInfo(ctx context.Context, in *ModelInfoRequest, opts ...grpc.CallOption) (*ModelInfo, error)
+	// GetCapabilities reports the contract clients can rely on: the observation
+	// shape the model accepts and the size and bounds of returned actions
+	GetCapabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*Capabilities, error)
+	// CommitPlan confirms a proposed action before the robot applies it. It
+	// answers once the proposal's veto window has passed, or as soon as a
+	// supervisor vetoes it.
+	CommitPlan(ctx context.Context, in *CommitPlanRequest, opts ...grpc.CallOption) (*CommitPlanResponse, error)
+	// VetoPlan rejects a proposed action within its veto window; for
+	// supervising safety services
+	VetoPlan(ctx context.Context, in *VetoPlanRequest, opts ...grpc.CallOption) (*VetoPlanResponse, error)
+	// WatchProposals streams the caller's tenant's proposed actions as they are
+	// made, so a supervising safety service can veto them in time
+	WatchProposals(ctx context.Context, in *WatchProposalsRequest, opts ...grpc.CallOption) (PathPlanner_WatchProposalsClient, error)
+	// GetPlanUpdates long-polls for a robot's next scheduled action. The
+	// server plans polling robots on a fixed cadence from their latest
+	// observation, so low-power robots can sleep their radios between polls.
+	GetPlanUpdates(ctx context.Context, in *PlanUpdatesRequest, opts ...grpc.CallOption) (*PlanUpdate, error)
+	// PlanStream replans one robot's observation at a fixed rate, with its
+	// latest cached pose, and streams each plan until the call is cancelled
+	PlanStream(ctx context.Context, in *PlanStreamRequest, opts ...grpc.CallOption) (PathPlanner_PlanStreamClient, error)
+	// StreamPlan plans each observation a robot streams and streams back its
+	// action over one long-lived call, avoiding per-call overhead in
+	// high-rate control loops
+	StreamPlan(ctx context.Context, opts ...grpc.CallOption) (PathPlanner_StreamPlanClient, error)
+	// AggregatePlan lets a fleet gateway stream many requests, which the
+	// server plans in large batches as they arrive, and returns every
+	// response, in request order, once the gateway closes its side
+	AggregatePlan(ctx context.Context, opts ...grpc.CallOption) (PathPlanner_AggregatePlanClient, error)
+	// Warmup runs synthetic inferences through the model, e.g. after a deploy
+	// behind a load balancer, so the first real requests do not pay for lazy
+	// session initialization
+	Warmup(ctx context.Context, in *WarmupRequest, opts ...grpc.CallOption) (*WarmupResponse, error)
+	// IssueRobotToken mints a short-lived bearer token for one robot, signed
+	// by the service. For provisioning systems listed in robot_token_issuers;
+	// robots renew their token before it expires instead of holding static
+	// API keys.
+	IssueRobotToken(ctx context.Context, in *IssueRobotTokenRequest, opts ...grpc.CallOption) (*IssueRobotTokenResponse, error)
 }
 
 type pathPlannerClient struct {
@@ -59,6 +124,280 @@ func (c *pathPlannerClient) BatchPlan(ctx context.Context, in *BatchPlanRequest,
 	return out, nil
 }
 
+func (c *pathPlannerClient) BatchPlanStream(ctx context.Context, in *BatchPlanStreamRequest, opts ...grpc.CallOption) (PathPlanner_BatchPlanStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PathPlanner_ServiceDesc.Streams[0], PathPlanner_BatchPlanStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pathPlannerBatchPlanStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PathPlanner_BatchPlanStreamClient interface {
+	Recv() (*BatchPlanChunk, error)
+	grpc.ClientStream
+}
+
+type pathPlannerBatchPlanStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *pathPlannerBatchPlanStreamClient) Recv() (*BatchPlanChunk, error) {
+	m := new(BatchPlanChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pathPlannerClient) PlanAsync(ctx context.Context, in *BatchPlanRequest, opts ...grpc.CallOption) (*PlanTicket, error) {
+	out := new(PlanTicket)
+	err := c.cc.Invoke(ctx, PathPlanner_PlanAsync_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) GetPlanResult(ctx context.Context, in *PlanTicket, opts ...grpc.CallOption) (*PlanResult, error) {
+	out := new(PlanResult)
+	err := c.cc.Invoke(ctx, PathPlanner_GetPlanResult_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) WatchPlanResults(ctx context.Context, in *WatchPlanResultsRequest, opts ...grpc.CallOption) (PathPlanner_WatchPlanResultsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PathPlanner_ServiceDesc.Streams[1], PathPlanner_WatchPlanResults_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pathPlannerWatchPlanResultsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PathPlanner_WatchPlanResultsClient interface {
+	Recv() (*PlanResult, error)
+	grpc.ClientStream
+}
+
+type pathPlannerWatchPlanResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *pathPlannerWatchPlanResultsClient) Recv() (*PlanResult, error) {
+	m := new(PlanResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pathPlannerClient) GetModelInfo(ctx context.Context, in *ModelInfoRequest, opts ...grpc.CallOption) (*ModelInfo, error) {
+	out := new(ModelInfo)
+	err := c.cc.Invoke(ctx, PathPlanner_GetModelInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) GetCapabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*Capabilities, error) {
+	out := new(Capabilities)
+	err := c.cc.Invoke(ctx, PathPlanner_GetCapabilities_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) CommitPlan(ctx context.Context, in *CommitPlanRequest, opts ...grpc.CallOption) (*CommitPlanResponse, error) {
+	out := new(CommitPlanResponse)
+	err := c.cc.Invoke(ctx, PathPlanner_CommitPlan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) VetoPlan(ctx context.Context, in *VetoPlanRequest, opts ...grpc.CallOption) (*VetoPlanResponse, error) {
+	out := new(VetoPlanResponse)
+	err := c.cc.Invoke(ctx, PathPlanner_VetoPlan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) WatchProposals(ctx context.Context, in *WatchProposalsRequest, opts ...grpc.CallOption) (PathPlanner_WatchProposalsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PathPlanner_ServiceDesc.Streams[2], PathPlanner_WatchProposals_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pathPlannerWatchProposalsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PathPlanner_WatchProposalsClient interface {
+	Recv() (*PlanProposal, error)
+	grpc.ClientStream
+}
+
+type pathPlannerWatchProposalsClient struct {
+	grpc.ClientStream
+}
+
+func (x *pathPlannerWatchProposalsClient) Recv() (*PlanProposal, error) {
+	m := new(PlanProposal)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pathPlannerClient) GetPlanUpdates(ctx context.Context, in *PlanUpdatesRequest, opts ...grpc.CallOption) (*PlanUpdate, error) {
+	out := new(PlanUpdate)
+	err := c.cc.Invoke(ctx, PathPlanner_GetPlanUpdates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) PlanStream(ctx context.Context, in *PlanStreamRequest, opts ...grpc.CallOption) (PathPlanner_PlanStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PathPlanner_ServiceDesc.Streams[3], PathPlanner_PlanStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pathPlannerPlanStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PathPlanner_PlanStreamClient interface {
+	Recv() (*PlanResponse, error)
+	grpc.ClientStream
+}
+
+type pathPlannerPlanStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *pathPlannerPlanStreamClient) Recv() (*PlanResponse, error) {
+	m := new(PlanResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pathPlannerClient) StreamPlan(ctx context.Context, opts ...grpc.CallOption) (PathPlanner_StreamPlanClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PathPlanner_ServiceDesc.Streams[4], PathPlanner_StreamPlan_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pathPlannerStreamPlanClient{stream}
+	return x, nil
+}
+
+type PathPlanner_StreamPlanClient interface {
+	Send(*StreamPlanRequest) error
+	Recv() (*StreamPlanResponse, error)
+	grpc.ClientStream
+}
+
+type pathPlannerStreamPlanClient struct {
+	grpc.ClientStream
+}
+
+func (x *pathPlannerStreamPlanClient) Send(m *StreamPlanRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pathPlannerStreamPlanClient) Recv() (*StreamPlanResponse, error) {
+	m := new(StreamPlanResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pathPlannerClient) AggregatePlan(ctx context.Context, opts ...grpc.CallOption) (PathPlanner_AggregatePlanClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PathPlanner_ServiceDesc.Streams[5], PathPlanner_AggregatePlan_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pathPlannerAggregatePlanClient{stream}
+	return x, nil
+}
+
+type PathPlanner_AggregatePlanClient interface {
+	Send(*PlanRequest) error
+	CloseAndRecv() (*BatchPlanResponse, error)
+	grpc.ClientStream
+}
+
+type pathPlannerAggregatePlanClient struct {
+	grpc.ClientStream
+}
+
+func (x *pathPlannerAggregatePlanClient) Send(m *PlanRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pathPlannerAggregatePlanClient) CloseAndRecv() (*BatchPlanResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BatchPlanResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pathPlannerClient) Warmup(ctx context.Context, in *WarmupRequest, opts ...grpc.CallOption) (*WarmupResponse, error) {
+	out := new(WarmupResponse)
+	err := c.cc.Invoke(ctx, PathPlanner_Warmup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pathPlannerClient) IssueRobotToken(ctx context.Context, in *IssueRobotTokenRequest, opts ...grpc.CallOption) (*IssueRobotTokenResponse, error) {
+	out := new(IssueRobotTokenResponse)
+	err := c.cc.Invoke(ctx, PathPlanner_IssueRobotToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PathPlannerServer is the server API for PathPlanner service.
 // All implementations must embed UnimplementedPathPlannerServer
 // for forward compatibility
@@ -67,6 +406,56 @@ type PathPlannerServer interface {
 	Plan(context.Context, *PlanRequest) (*PlanResponse, error)
 	// BatchPlan computes actions for multiple robot observations in a single call
 	BatchPlan(context.Context, *BatchPlanRequest) (*BatchPlanResponse, error)
+	// BatchPlanStream splits a large batch into chunks and streams each chunk's
+	// responses as soon as it finishes; cancelling the call skips the rest
+	BatchPlanStream(*BatchPlanStreamRequest, PathPlanner_BatchPlanStreamServer) error
+	// PlanAsync enqueues a batch for background planning and returns a ticket
+	// immediately, for consumers that do not need an answer right away
+	PlanAsync(context.Context, *BatchPlanRequest) (*PlanTicket, error)
+	// GetPlanResult returns the state of an async plan and, once done, its result
+	GetPlanResult(context.Context, *PlanTicket) (*PlanResult, error)
+	// WatchPlanResults streams the result of each ticket as it completes and
+	// ends once every ticket has been answered
+	WatchPlanResults(*WatchPlanResultsRequest, PathPlanner_WatchPlanResultsServer) error
+	// GetModelInfo reports the serving model and how ONNX Runtime executes it
+	GetModelInfo(context.Context, *ModelInfoRequest) (*ModelInfo, error)
+	// GetCapabilities reports the contract clients can rely on: the observation
+	// shape the model accepts and the size and bounds of returned actions
+	GetCapabilities(context.Context, *CapabilitiesRequest) (*Capabilities, error)
+	// CommitPlan confirms a proposed action before the robot applies it. It
+	// answers once the proposal's veto window has passed, or as soon as a
+	// supervisor vetoes it.
+	CommitPlan(context.Context, *CommitPlanRequest) (*CommitPlanResponse, error)
+	// VetoPlan rejects a proposed action within its veto window; for
+	// supervising safety services
+	VetoPlan(context.Context, *VetoPlanRequest) (*VetoPlanResponse, error)
+	// WatchProposals streams the caller's tenant's proposed actions as they are
+	// made, so a supervising safety service can veto them in time
+	WatchProposals(*WatchProposalsRequest, PathPlanner_WatchProposalsServer) error
+	// GetPlanUpdates long-polls for a robot's next scheduled action. The
+	// server plans polling robots on a fixed cadence from their latest
+	// observation, so low-power robots can sleep their radios between polls.
+	GetPlanUpdates(context.Context, *PlanUpdatesRequest) (*PlanUpdate, error)
+	// PlanStream replans one robot's observation at a fixed rate, with its
+	// latest cached pose, and streams each plan until the call is cancelled
+	PlanStream(*PlanStreamRequest, PathPlanner_PlanStreamServer) error
+	// StreamPlan plans each observation a robot streams and streams back its
+	// action over one long-lived call, avoiding per-call overhead in
+	// high-rate control loops
+	StreamPlan(PathPlanner_StreamPlanServer) error
+	// AggregatePlan lets a fleet gateway stream many requests, which the
+	// server plans in large batches as they arrive, and returns every
+	// response, in request order, once the gateway closes its side
+	AggregatePlan(PathPlanner_AggregatePlanServer) error
+	// Warmup runs synthetic inferences through the model, e.g. after a deploy
+	// behind a load balancer, so the first real requests do not pay for lazy
+	// session initialization
+	Warmup(context.Context, *WarmupRequest) (*WarmupResponse, error)
+	// IssueRobotToken mints a short-lived bearer token for one robot, signed
+	// by the service. For provisioning systems listed in robot_token_issuers;
+	// robots renew their token before it expires instead of holding static
+	// API keys.
+	IssueRobotToken(context.Context, *IssueRobotTokenRequest) (*IssueRobotTokenResponse, error)
 	mustEmbedUnimplementedPathPlannerServer()
 }
 
@@ -80,6 +469,51 @@ func (UnimplementedPathPlannerServer) Plan(context.Context, *PlanRequest) (*Plan
 func (UnimplementedPathPlannerServer) BatchPlan(context.Context, *BatchPlanRequest) (*BatchPlanResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchPlan not implemented")
 }
+func (UnimplementedPathPlannerServer) BatchPlanStream(*BatchPlanStreamRequest, PathPlanner_BatchPlanStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchPlanStream not implemented")
+}
+func (UnimplementedPathPlannerServer) PlanAsync(context.Context, *BatchPlanRequest) (*PlanTicket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanAsync not implemented")
+}
+func (UnimplementedPathPlannerServer) GetPlanResult(context.Context, *PlanTicket) (*PlanResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlanResult not implemented")
+}
+func (UnimplementedPathPlannerServer) WatchPlanResults(*WatchPlanResultsRequest, PathPlanner_WatchPlanResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPlanResults not implemented")
+}
+func (UnimplementedPathPlannerServer) GetModelInfo(context.Context, *ModelInfoRequest) (*ModelInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModelInfo not implemented")
+}
+func (UnimplementedPathPlannerServer) GetCapabilities(context.Context, *CapabilitiesRequest) (*Capabilities, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedPathPlannerServer) CommitPlan(context.Context, *CommitPlanRequest) (*CommitPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitPlan not implemented")
+}
+func (UnimplementedPathPlannerServer) VetoPlan(context.Context, *VetoPlanRequest) (*VetoPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VetoPlan not implemented")
+}
+func (UnimplementedPathPlannerServer) WatchProposals(*WatchProposalsRequest, PathPlanner_WatchProposalsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProposals not implemented")
+}
+func (UnimplementedPathPlannerServer) GetPlanUpdates(context.Context, *PlanUpdatesRequest) (*PlanUpdate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlanUpdates not implemented")
+}
+func (UnimplementedPathPlannerServer) PlanStream(*PlanStreamRequest, PathPlanner_PlanStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PlanStream not implemented")
+}
+func (UnimplementedPathPlannerServer) StreamPlan(PathPlanner_StreamPlanServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPlan not implemented")
+}
+func (UnimplementedPathPlannerServer) AggregatePlan(PathPlanner_AggregatePlanServer) error {
+	return status.Errorf(codes.Unimplemented, "method AggregatePlan not implemented")
+}
+func (UnimplementedPathPlannerServer) Warmup(context.Context, *WarmupRequest) (*WarmupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Warmup not implemented")
+}
+func (UnimplementedPathPlannerServer) IssueRobotToken(context.Context, *IssueRobotTokenRequest) (*IssueRobotTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueRobotToken not implemented")
+}
 func (UnimplementedPathPlannerServer) mustEmbedUnimplementedPathPlannerServer() {}
 
 // UnsafePathPlannerServer may be embedded to opt out of forward compatibility for this service.
@@ -129,6 +563,304 @@ func _PathPlanner_BatchPlan_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PathPlanner_BatchPlanStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchPlanStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PathPlannerServer).BatchPlanStream(m, &pathPlannerBatchPlanStreamServer{stream})
+}
+
+type PathPlanner_BatchPlanStreamServer interface {
+	Send(*BatchPlanChunk) error
+	grpc.ServerStream
+}
+
+type pathPlannerBatchPlanStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *pathPlannerBatchPlanStreamServer) Send(m *BatchPlanChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PathPlanner_PlanAsync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).PlanAsync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_PlanAsync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).PlanAsync(ctx, req.(*BatchPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_GetPlanResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanTicket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).GetPlanResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_GetPlanResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).GetPlanResult(ctx, req.(*PlanTicket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_WatchPlanResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPlanResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PathPlannerServer).WatchPlanResults(m, &pathPlannerWatchPlanResultsServer{stream})
+}
+
+type PathPlanner_WatchPlanResultsServer interface {
+	Send(*PlanResult) error
+	grpc.ServerStream
+}
+
+type pathPlannerWatchPlanResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *pathPlannerWatchPlanResultsServer) Send(m *PlanResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PathPlanner_GetModelInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).GetModelInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_GetModelInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).GetModelInfo(ctx, req.(*ModelInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).GetCapabilities(ctx, req.(*CapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_CommitPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).CommitPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_CommitPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).CommitPlan(ctx, req.(*CommitPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_VetoPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VetoPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).VetoPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_VetoPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).VetoPlan(ctx, req.(*VetoPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_WatchProposals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProposalsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PathPlannerServer).WatchProposals(m, &pathPlannerWatchProposalsServer{stream})
+}
+
+type PathPlanner_WatchProposalsServer interface {
+	Send(*PlanProposal) error
+	grpc.ServerStream
+}
+
+type pathPlannerWatchProposalsServer struct {
+	grpc.ServerStream
+}
+
+func (x *pathPlannerWatchProposalsServer) Send(m *PlanProposal) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PathPlanner_GetPlanUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).GetPlanUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_GetPlanUpdates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).GetPlanUpdates(ctx, req.(*PlanUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_PlanStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PlanStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PathPlannerServer).PlanStream(m, &pathPlannerPlanStreamServer{stream})
+}
+
+type PathPlanner_PlanStreamServer interface {
+	Send(*PlanResponse) error
+	grpc.ServerStream
+}
+
+type pathPlannerPlanStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *pathPlannerPlanStreamServer) Send(m *PlanResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PathPlanner_StreamPlan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PathPlannerServer).StreamPlan(&pathPlannerStreamPlanServer{stream})
+}
+
+type PathPlanner_StreamPlanServer interface {
+	Send(*StreamPlanResponse) error
+	Recv() (*StreamPlanRequest, error)
+	grpc.ServerStream
+}
+
+type pathPlannerStreamPlanServer struct {
+	grpc.ServerStream
+}
+
+func (x *pathPlannerStreamPlanServer) Send(m *StreamPlanResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pathPlannerStreamPlanServer) Recv() (*StreamPlanRequest, error) {
+	m := new(StreamPlanRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _PathPlanner_AggregatePlan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PathPlannerServer).AggregatePlan(&pathPlannerAggregatePlanServer{stream})
+}
+
+type PathPlanner_AggregatePlanServer interface {
+	SendAndClose(*BatchPlanResponse) error
+	Recv() (*PlanRequest, error)
+	grpc.ServerStream
+}
+
+type pathPlannerAggregatePlanServer struct {
+	grpc.ServerStream
+}
+
+func (x *pathPlannerAggregatePlanServer) SendAndClose(m *BatchPlanResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pathPlannerAggregatePlanServer) Recv() (*PlanRequest, error) {
+	m := new(PlanRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _PathPlanner_Warmup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarmupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).Warmup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_Warmup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).Warmup(ctx, req.(*WarmupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PathPlanner_IssueRobotToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueRobotTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PathPlannerServer).IssueRobotToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PathPlanner_IssueRobotToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PathPlannerServer).IssueRobotToken(ctx, req.(*IssueRobotTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PathPlanner_ServiceDesc is the grpc.ServiceDesc for PathPlanner service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -144,7 +876,75 @@ var PathPlanner_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchPlan",
 			Handler:    _PathPlanner_BatchPlan_Handler,
 		},
+		{
+			MethodName: "PlanAsync",
+			Handler:    _PathPlanner_PlanAsync_Handler,
+		},
+		{
+			MethodName: "GetPlanResult",
+			Handler:    _PathPlanner_GetPlanResult_Handler,
+		},
+		{
+			MethodName: "GetModelInfo",
+			Handler:    _PathPlanner_GetModelInfo_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _PathPlanner_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "CommitPlan",
+			Handler:    _PathPlanner_CommitPlan_Handler,
+		},
+		{
+			MethodName: "VetoPlan",
+			Handler:    _PathPlanner_VetoPlan_Handler,
+		},
+		{
+			MethodName: "GetPlanUpdates",
+			Handler:    _PathPlanner_GetPlanUpdates_Handler,
+		},
+		{
+			MethodName: "Warmup",
+			Handler:    _PathPlanner_Warmup_Handler,
+		},
+		{
+			MethodName: "IssueRobotToken",
+			Handler:    _PathPlanner_IssueRobotToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchPlanStream",
+			Handler:       _PathPlanner_BatchPlanStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchPlanResults",
+			Handler:       _PathPlanner_WatchPlanResults_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchProposals",
+			Handler:       _PathPlanner_WatchProposals_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PlanStream",
+			Handler:       _PathPlanner_PlanStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamPlan",
+			Handler:       _PathPlanner_StreamPlan_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AggregatePlan",
+			Handler:       _PathPlanner_AggregatePlan_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/planner.proto",
 }
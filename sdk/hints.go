@@ -0,0 +1,28 @@
+// sdk/hints.go
+package sdk
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// suggestedIntervalTrailer carries the server's suggested call interval in
+// milliseconds, when client hints are enabled
+const suggestedIntervalTrailer = "x-suggested-interval-ms"
+
+// SuggestedInterval returns the interval the server suggests leaving between
+// calls, read from a response's trailer (see grpc.Trailer). It reports false
+// when the server sent no hint.
+func SuggestedInterval(trailer metadata.MD) (time.Duration, bool) {
+	values := trailer.Get(suggestedIntervalTrailer)
+	if len(values) == 0 {
+		return 0, false
+	}
+	ms, err := strconv.ParseFloat(values[0], 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+	return time.Duration(ms * float64(time.Millisecond)), true
+}
@@ -0,0 +1,22 @@
+// sdk/hints_test.go
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestSuggestedInterval(t *testing.T) {
+	d, ok := SuggestedInterval(metadata.Pairs("x-suggested-interval-ms", "99.500"))
+	if !ok || d != 99500*time.Microsecond {
+		t.Fatalf("Expected 99.5ms, got %s (ok=%t)", d, ok)
+	}
+	if _, ok := SuggestedInterval(metadata.MD{}); ok {
+		t.Error("Expected no hint without the trailer")
+	}
+	if _, ok := SuggestedInterval(metadata.Pairs("x-suggested-interval-ms", "soon")); ok {
+		t.Error("Expected no hint for a malformed trailer")
+	}
+}
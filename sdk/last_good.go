@@ -0,0 +1,103 @@
+// sdk/last_good.go
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Planned is the outcome of LastGood.Plan
+type Planned struct {
+	Response *pb.PlanResponse
+	// Received is when Response was received from the server
+	Received time.Time
+	// Stale is set when the call failed and Response is the robot's last
+	// good response; Err is then the call's error
+	Stale bool
+	Err   error
+}
+
+// lastResponse is a robot's last good response
+type lastResponse struct {
+	resp     *pb.PlanResponse
+	received time.Time
+}
+
+// LastGood plans through client, remembering each robot's last successful
+// response. When the server cannot be reached within the call's deadline, it
+// returns that response again, flagged stale, so a robot can continue briefly
+// through a network blip instead of stopping dead. A stale response is only
+// returned within maxAge of its receipt and while its action is still valid
+// (see Expired).
+type LastGood struct {
+	client pb.PathPlannerClient
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	last map[uint64]lastResponse
+}
+
+// NewLastGood creates a LastGood falling back to responses at most maxAge old
+func NewLastGood(client pb.PathPlannerClient, maxAge time.Duration) *LastGood {
+	return &LastGood{
+		client: client,
+		maxAge: maxAge,
+		now:    time.Now,
+		last:   make(map[uint64]lastResponse),
+	}
+}
+
+// Plan calls Plan on the server. If the call fails with Unavailable or
+// DeadlineExceeded and the robot has a recent enough good response, it is
+// returned with Stale set instead of the error. Other errors, such as
+// InvalidArgument, are returned as they are.
+func (c *LastGood) Plan(ctx context.Context, req *pb.PlanRequest, opts ...grpc.CallOption) (Planned, error) {
+	resp, err := c.client.Plan(ctx, req, opts...)
+	now := c.now()
+	if err == nil {
+		c.mu.Lock()
+		c.last[req.GetRobotId()] = lastResponse{resp: resp, received: now}
+		c.mu.Unlock()
+		return Planned{Response: resp, Received: now}, nil
+	}
+	if !unreachable(err) {
+		return Planned{}, err
+	}
+
+	c.mu.Lock()
+	last, ok := c.last[req.GetRobotId()]
+	c.mu.Unlock()
+	if !ok || now.Sub(last.received) > c.maxAge || Expired(last.resp, last.received, now) {
+		return Planned{}, err
+	}
+	return Planned{Response: last.resp, Received: last.received, Stale: true, Err: err}, nil
+}
+
+// Forget drops the robot's last good response, e.g. once it has stopped
+func (c *LastGood) Forget(robotID uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, robotID)
+}
+
+// unreachable reports whether err means the server was not reached in time,
+// rather than that it rejected the request
+func unreachable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
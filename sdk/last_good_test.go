@@ -0,0 +1,62 @@
+// sdk/last_good_test.go
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// plannerClient answers Plan with resp, or err when set
+type plannerClient struct {
+	pb.PathPlannerClient
+	resp *pb.PlanResponse
+	err  error
+}
+
+func (c *plannerClient) Plan(context.Context, *pb.PlanRequest, ...grpc.CallOption) (*pb.PlanResponse, error) {
+	return c.resp, c.err
+}
+
+func TestLastGood(t *testing.T) {
+	client := &plannerClient{resp: &pb.PlanResponse{Action: []float32{0.5}, ValidityMs: 500}}
+	lg := NewLastGood(client, time.Second)
+	now := time.Unix(1700000000, 0)
+	lg.now = func() time.Time { return now }
+	req := &pb.PlanRequest{RobotId: 7}
+
+	planned, err := lg.Plan(context.Background(), req)
+	if err != nil || planned.Stale || planned.Response.Action[0] != 0.5 {
+		t.Fatalf("Expected a fresh response, got %+v, %v", planned, err)
+	}
+
+	// The server becomes unreachable
+	client.err = status.Error(codes.Unavailable, "connection refused")
+	now = now.Add(200 * time.Millisecond)
+	planned, err = lg.Plan(context.Background(), req)
+	if err != nil || !planned.Stale || planned.Response.Action[0] != 0.5 || status.Code(planned.Err) != codes.Unavailable {
+		t.Fatalf("Expected the last good response flagged stale, got %+v, %v", planned, err)
+	}
+	if _, err := lg.Plan(context.Background(), &pb.PlanRequest{RobotId: 8}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the error for a robot without a good response, got %v", err)
+	}
+
+	// Past the action's validity
+	now = now.Add(400 * time.Millisecond)
+	if _, err := lg.Plan(context.Background(), req); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the error once the last action expired, got %v", err)
+	}
+
+	// Rejections are not covered up
+	now = now.Add(-400 * time.Millisecond)
+	client.err = status.Error(codes.InvalidArgument, "bad observation")
+	if _, err := lg.Plan(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument to be returned, got %v", err)
+	}
+}
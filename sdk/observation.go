@@ -0,0 +1,218 @@
+// Package sdk helps Go clients build requests the planner accepts. Packing
+// observation tensors by hand is the most common source of InvalidArgument
+// errors, so these helpers build Observation messages from images and grids
+// and check them before they are sent.
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Option adjusts how observation values are built
+type Option func(*options)
+
+type options struct {
+	scale float32
+	mean  []float32
+	std   []float32
+	alpha bool
+	gray  bool
+}
+
+// Scale multiplies every value by s, e.g. 1.0/255 to map 8-bit pixels to [0, 1]
+func Scale(s float32) Option {
+	return func(o *options) {
+		o.scale = s
+	}
+}
+
+// MeanStd normalizes each channel c, after scaling, to (v - mean[c]) / std[c].
+// A single mean and std apply to every channel.
+func MeanStd(mean, std []float32) Option {
+	return func(o *options) {
+		o.mean, o.std = mean, std
+	}
+}
+
+// WithAlpha keeps the alpha channel, building 4-channel RGBA observations
+// instead of RGB
+func WithAlpha() Option {
+	return func(o *options) {
+		o.alpha = true
+	}
+}
+
+// Grayscale converts color images to a single luminance channel
+func Grayscale() Option {
+	return func(o *options) {
+		o.gray = true
+	}
+}
+
+func buildOptions(opts []Option) options {
+	o := options{scale: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// FromImage builds a CHW observation from img with 8-bit values (0-255).
+// Grayscale images (or any image with the Grayscale option) have one channel;
+// others have three (RGB), or four with WithAlpha.
+func FromImage(img image.Image, opts ...Option) (*pb.Observation, error) {
+	if img == nil {
+		return nil, fmt.Errorf("image is nil")
+	}
+	o := buildOptions(opts)
+	b := img.Bounds()
+	h, w := b.Dy(), b.Dx()
+	if h <= 0 || w <= 0 {
+		return nil, fmt.Errorf("image is empty (%dx%d)", w, h)
+	}
+
+	c := 3
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+		o.gray = true
+	}
+	if o.gray {
+		c = 1
+	} else if o.alpha {
+		c = 4
+	}
+
+	// Channel planes follow each other: all of R, then all of G, and so on
+	plane := h * w
+	data := make([]float32, c*plane)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			px := img.At(b.Min.X+x, b.Min.Y+y)
+			if o.gray {
+				data[i] = float32(color.GrayModel.Convert(px).(color.Gray).Y)
+				continue
+			}
+			nrgba := color.NRGBAModel.Convert(px).(color.NRGBA)
+			data[i] = float32(nrgba.R)
+			data[plane+i] = float32(nrgba.G)
+			data[2*plane+i] = float32(nrgba.B)
+			if o.alpha {
+				data[3*plane+i] = float32(nrgba.A)
+			}
+		}
+	}
+	return build(data, c, h, w, o)
+}
+
+// FromGrid builds a single-channel observation from rows of equal length,
+// such as an occupancy grid or depth map
+func FromGrid(grid [][]float32, opts ...Option) (*pb.Observation, error) {
+	return FromChannels([][][]float32{grid}, opts...)
+}
+
+// FromChannels builds a CHW observation from one grid per channel; every grid
+// must have the same, rectangular shape
+func FromChannels(channels [][][]float32, opts ...Option) (*pb.Observation, error) {
+	if len(channels) == 0 || len(channels[0]) == 0 || len(channels[0][0]) == 0 {
+		return nil, fmt.Errorf("grid is empty")
+	}
+	c, h, w := len(channels), len(channels[0]), len(channels[0][0])
+
+	data := make([]float32, 0, c*h*w)
+	for ch, grid := range channels {
+		if len(grid) != h {
+			return nil, fmt.Errorf("channel %d has %d rows, expected %d", ch, len(grid), h)
+		}
+		for y, row := range grid {
+			if len(row) != w {
+				return nil, fmt.Errorf("channel %d row %d has %d values, expected %d", ch, y, len(row), w)
+			}
+			data = append(data, row...)
+		}
+	}
+	return build(data, c, h, w, buildOptions(opts))
+}
+
+// build applies the normalization options and validates the result
+func build(data []float32, c, h, w int, o options) (*pb.Observation, error) {
+	if len(o.mean) != len(o.std) || (len(o.mean) != 0 && len(o.mean) != 1 && len(o.mean) != c) {
+		return nil, fmt.Errorf("mean and std must have 1 or %d values each, got %d and %d", c, len(o.mean), len(o.std))
+	}
+
+	plane := h * w
+	for ch := 0; ch < c; ch++ {
+		mean, std := float32(0), float32(1)
+		if len(o.mean) == 1 {
+			mean, std = o.mean[0], o.std[0]
+		} else if len(o.mean) == c {
+			mean, std = o.mean[ch], o.std[ch]
+		}
+		if std == 0 {
+			return nil, fmt.Errorf("std for channel %d is zero", ch)
+		}
+		values := data[ch*plane : (ch+1)*plane]
+		for i, v := range values {
+			values[i] = (v*o.scale - mean) / std
+		}
+	}
+
+	obs := &pb.Observation{
+		Data:     data,
+		Channels: uint32(c),
+		Height:   uint32(h),
+		Width:    uint32(w),
+	}
+	return obs, Validate(obs)
+}
+
+// Validate checks an observation the way the planner does: positive
+// dimensions, data length matching them, and finite values
+func Validate(obs *pb.Observation) error {
+	if obs == nil {
+		return fmt.Errorf("observation is nil")
+	}
+	if obs.Channels == 0 || obs.Height == 0 || obs.Width == 0 {
+		return fmt.Errorf("invalid observation dimensions: channels=%d, height=%d, width=%d", obs.Channels, obs.Height, obs.Width)
+	}
+	if want := uint64(obs.Channels) * uint64(obs.Height) * uint64(obs.Width); uint64(len(obs.Data)) != want {
+		return fmt.Errorf("observation has wrong data length: got %d, expected %d (%dx%dx%d)",
+			len(obs.Data), want, obs.Channels, obs.Height, obs.Width)
+	}
+	for i, v := range obs.Data {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return fmt.Errorf("observation value %d is not finite: %v", i, v)
+		}
+	}
+	return nil
+}
+
+// CheckShape checks an observation against a model's observation_shape from
+// GetCapabilities: [batch, C, H, W] for image models or [batch, D] for flat
+// ones, with -1 for dynamic sizes
+func CheckShape(obs *pb.Observation, shape []int64) error {
+	if err := Validate(obs); err != nil {
+		return err
+	}
+	switch len(shape) {
+	case 4:
+		dims := []uint32{obs.Channels, obs.Height, obs.Width}
+		for i, want := range shape[1:] {
+			if want > 0 && int64(dims[i]) != want {
+				return fmt.Errorf("observation shape (%d,%d,%d) does not match model input shape %v",
+					obs.Channels, obs.Height, obs.Width, shape)
+			}
+		}
+	case 2:
+		if shape[1] > 0 && int64(len(obs.Data)) != shape[1] {
+			return fmt.Errorf("observation has %d values, model input shape %v expects %d",
+				len(obs.Data), shape, shape[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// sdk/observation_test.go
+package sdk
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+func TestFromImage_CHWLayout(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 255, G: 0, B: 51, A: 255})
+	img.Set(1, 0, color.RGBA{R: 0, G: 102, B: 0, A: 255})
+
+	obs, err := FromImage(img, Scale(1.0/255))
+	if err != nil {
+		t.Fatalf("FromImage failed: %v", err)
+	}
+	if obs.Channels != 3 || obs.Height != 1 || obs.Width != 2 {
+		t.Fatalf("Expected 3x1x2, got %dx%dx%d", obs.Channels, obs.Height, obs.Width)
+	}
+	want := []float32{1, 0, 0, 0.4, 0.2, 0}
+	for i := range want {
+		if math.Abs(float64(obs.Data[i]-want[i])) > 1e-6 {
+			t.Fatalf("Expected %v, got %v", want, obs.Data)
+		}
+	}
+
+	gray, err := FromImage(image.NewGray(image.Rect(0, 0, 4, 3)))
+	if err != nil || gray.Channels != 1 || len(gray.Data) != 12 {
+		t.Fatalf("Expected a 1x3x4 observation, got %+v, %v", gray, err)
+	}
+}
+
+func TestFromChannels_ValidatesAndNormalizes(t *testing.T) {
+	obs, err := FromChannels([][][]float32{
+		{{1, 2}, {3, 4}},
+		{{10, 20}, {30, 40}},
+	}, MeanStd([]float32{0, 10}, []float32{1, 10}))
+	if err != nil {
+		t.Fatalf("FromChannels failed: %v", err)
+	}
+	want := []float32{1, 2, 3, 4, 0, 1, 2, 3}
+	for i := range want {
+		if obs.Data[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, obs.Data)
+		}
+	}
+
+	if _, err := FromGrid([][]float32{{1, 2}, {3}}); err == nil {
+		t.Error("Expected error for a ragged grid")
+	}
+	if _, err := FromGrid([][]float32{{1, float32(math.NaN())}}); err == nil {
+		t.Error("Expected error for a NaN value")
+	}
+	if _, err := FromGrid([][]float32{{1}}, MeanStd([]float32{0, 0}, []float32{1, 1})); err == nil {
+		t.Error("Expected error for mean and std of the wrong length")
+	}
+}
+
+func TestCheckShape(t *testing.T) {
+	obs := &pb.Observation{Data: make([]float32, 12), Channels: 3, Height: 2, Width: 2}
+
+	if err := CheckShape(obs, []int64{-1, 3, 2, 2}); err != nil {
+		t.Errorf("Expected matching image shape to pass, got %v", err)
+	}
+	if err := CheckShape(obs, []int64{-1, 3, -1, -1}); err != nil {
+		t.Errorf("Expected dynamic dimensions to pass, got %v", err)
+	}
+	if err := CheckShape(obs, []int64{-1, 1, 2, 2}); err == nil {
+		t.Error("Expected error for mismatched channels")
+	}
+	if err := CheckShape(obs, []int64{-1, 12}); err != nil {
+		t.Errorf("Expected matching flat shape to pass, got %v", err)
+	}
+	if err := CheckShape(obs, []int64{-1, 8}); err == nil {
+		t.Error("Expected error for mismatched flat size")
+	}
+	if err := CheckShape(&pb.Observation{Data: make([]float32, 3), Channels: 1, Height: 1, Width: 4}, nil); err == nil {
+		t.Error("Expected error for wrong data length")
+	}
+}
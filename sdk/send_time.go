@@ -0,0 +1,21 @@
+// sdk/send_time.go
+package sdk
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// clientSendTimeHeader carries the time a call was sent, in Unix nanoseconds
+const clientSendTimeHeader = "x-client-send-time"
+
+// WithSendTime stamps the outgoing call made with ctx with now as its send
+// time, so the server can export how long calls take to reach it. Stamp just
+// before the call; the measurement is only as good as the agreement between
+// the robot's and the server's clocks.
+func WithSendTime(ctx context.Context, now time.Time) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, clientSendTimeHeader, strconv.FormatInt(now.UnixNano(), 10))
+}
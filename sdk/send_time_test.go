@@ -0,0 +1,18 @@
+// sdk/send_time_test.go
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithSendTime(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	md, _ := metadata.FromOutgoingContext(WithSendTime(context.Background(), now))
+	if values := md.Get("x-client-send-time"); len(values) != 1 || values[0] != "1700000000123456789" {
+		t.Fatalf("Expected the send time in Unix nanoseconds, got %v", values)
+	}
+}
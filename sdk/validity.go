@@ -0,0 +1,19 @@
+// sdk/validity.go
+package sdk
+
+import (
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Expired reports whether the action in resp, received at received, may no
+// longer be applied at now. It counts validity_ms from receipt, so it does
+// not depend on the robot's clock agreeing with the server's. Actions without
+// a validity never expire.
+func Expired(resp *pb.PlanResponse, received, now time.Time) bool {
+	if resp.GetValidityMs() == 0 {
+		return false
+	}
+	return now.Sub(received) > time.Duration(resp.GetValidityMs())*time.Millisecond
+}
@@ -0,0 +1,24 @@
+// sdk/validity_test.go
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+func TestExpired(t *testing.T) {
+	received := time.Unix(1700000000, 0)
+	resp := &pb.PlanResponse{Action: []float32{0.5}, ValidityMs: 100}
+
+	if Expired(resp, received, received.Add(100*time.Millisecond)) {
+		t.Error("Expected the action to be valid for its full validity")
+	}
+	if !Expired(resp, received, received.Add(101*time.Millisecond)) {
+		t.Error("Expected the action to expire after its validity")
+	}
+	if Expired(&pb.PlanResponse{Action: []float32{0.5}}, received, received.Add(time.Hour)) {
+		t.Error("Expected actions without a validity never to expire")
+	}
+}
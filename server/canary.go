@@ -0,0 +1,86 @@
+// server/canary.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/canary"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// startCanary dials the gRPC listener at addr and plans a synthetic
+// observation of the model's input size through it every canary_interval
+// until ctx is done
+func (s *Server) startCanary(ctx context.Context, addr net.Addr) error {
+	cfg := s.cfg
+	creds := s.canaryCreds
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(loopbackTarget(addr), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	md := metadata.MD{}
+	if cfg.CanaryTenant != "" {
+		md.Set(middleware.TenantIDHeader, cfg.CanaryTenant)
+	}
+	if cfg.CanaryAPIKey != "" {
+		md.Set(auth.APIKeyHeader, cfg.CanaryAPIKey)
+	}
+
+	c, h, w := inference.SyntheticDims(s.infer)
+	obs := &pb.Observation{
+		Data:     inference.SyntheticObservations(1, c, h, w)[0],
+		Channels: uint32(c),
+		Height:   uint32(h),
+		Width:    uint32(w),
+	}
+	var expected []float32
+	if len(cfg.CanaryExpectedAction) > 0 {
+		expected = cfg.CanaryExpectedAction
+	}
+	check := canary.New(pb.NewPathPlannerClient(conn), canary.Config{
+		Interval:   cfg.CanaryInterval,
+		Timeout:    cfg.CanaryTimeout,
+		MaxLatency: cfg.CanaryMaxLatency,
+		RobotID:    cfg.CanaryRobotID,
+		Obs:        obs,
+		Expected:   expected,
+		Tolerance:  cfg.CanaryTolerance,
+		Metadata:   md,
+	})
+	go check.Run(ctx)
+	slog.Info("Canary enabled",
+		"interval", cfg.CanaryInterval, "max_latency", cfg.CanaryMaxLatency,
+		"observation_shape", []int64{c, h, w}, "robot_id", cfg.CanaryRobotID)
+	return nil
+}
+
+// loopbackTarget returns a dial target for the listener at addr, replacing a
+// wildcard host with localhost
+func loopbackTarget(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,78 @@
+// server/config.go
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/handler"
+)
+
+// logConfigDiff logs each key whose effective value differs from its default,
+// secrets masked, so operators can see what this instance is running with
+func logConfigDiff(cfg *Config, reason string) {
+	changes := cfg.Diff()
+	attrs := make([]any, 0, len(changes))
+	for _, c := range changes {
+		attrs = append(attrs, slog.Group(c.Key, "value", c.Value, "default", c.Default))
+	}
+	slog.Info("Configuration differs from defaults",
+		"reason", reason, "changed", len(changes), slog.Group("diff", attrs...))
+}
+
+// replayConfig returns a copy of cfg with every stage that can make a plan
+// depend on anything but its observation turned off, so replayed recordings
+// produce bit-identical actions across builds: sampled discrete actions are
+// decoded by argmax, nothing is served from the dedup window or plan cache,
+// calls are not coalesced, downsampled, or proxied upstream, and no traffic
+// goes to a rollout candidate. Stages that change the model's input, cached
+// poses appended to observations and enrichment features, cannot be turned
+// off, so they are refused.
+func replayConfig(cfg *Config) (*Config, error) {
+	if cfg.PoseMode == handler.PoseObservation {
+		return nil, fmt.Errorf("replay mode cannot append cached poses to observations (pose_mode: %s)", cfg.PoseMode)
+	}
+	if cfg.EnrichmentSource != "" {
+		return nil, fmt.Errorf("replay mode cannot enrich observations from %s (enrichment_source)", cfg.EnrichmentSource)
+	}
+
+	c := *cfg
+	var disabled []string
+	if c.ActionMode == action.ModeSample {
+		c.ActionMode = action.ModeArgmax
+		disabled = append(disabled, "action sampling")
+	}
+	for _, stage := range []struct {
+		name    string
+		enabled *bool
+	}{
+		{"dedup", &c.DedupEnabled},
+		{"plan cache", &c.PlanCacheEnabled},
+		{"batching", &c.BatchingEnabled},
+		{"downsampling", &c.DownsampleEnabled},
+		{"rollout", &c.RolloutEnabled},
+	} {
+		if *stage.enabled {
+			*stage.enabled = false
+			disabled = append(disabled, stage.name)
+		}
+	}
+	if c.UpstreamAddress != "" {
+		c.UpstreamAddress = ""
+		disabled = append(disabled, "upstream proxy")
+	}
+	slog.Info("Replay mode: deterministic planning only", "disabled", disabled)
+	return &c, nil
+}
+
+// handleConfig serves GET /admin/config: the effective configuration and its
+// diff against the defaults, secrets masked
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	admin.WriteJSON(w, http.StatusOK, map[string]any{
+		"settings": s.cfg.Settings(),
+		"diff":     s.cfg.Diff(),
+	})
+}
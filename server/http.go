@@ -0,0 +1,101 @@
+// server/http.go
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// ListenAddrs are the addresses the server is listening on
+type ListenAddrs struct {
+	GRPC    string `json:"grpc"`
+	Metrics string `json:"metrics"`
+}
+
+// startHTTPServer serves metrics, health checks, and handlers on port,
+// returning the address it listens on
+func startHTTPServer(port int, healthServer *health.Server, handlers map[string]http.Handler) (*http.Server, net.Addr, error) {
+	mux := http.NewServeMux()
+
+	// Feature-specific endpoints
+	for path, h := range handlers {
+		mux.Handle(path, h)
+	}
+
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/metrics/catalog", metrics.CatalogHandler())
+
+	// Health check endpoint
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := healthServer.Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Service Unavailable"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	// Readiness check (same as healthz for now)
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := healthServer.Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Not Ready"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Ready"))
+	})
+
+	// Listen before serving, so a port that is taken fails startup instead of
+	// leaving the service running without metrics; port 0 picks a free port
+	addr := fmt.Sprintf(":%d", port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on %s for metrics and health checks (metrics_port): %w", addr, err)
+	}
+	server := &http.Server{
+		Addr:    lis.Addr().String(),
+		Handler: mux,
+	}
+
+	go func() {
+		slog.Info("HTTP server listening (metrics, health)", "address", lis.Addr().String())
+		if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			slog.Error("HTTP server error", "error", err)
+		}
+	}()
+
+	return server, lis.Addr(), nil
+}
+
+// Addrs returns the addresses Run is listening on, resolving port 0 to the
+// port picked; ok is false until Run has started listening
+func (s *Server) Addrs() (addrs ListenAddrs, ok bool) {
+	if a := s.addrs.Load(); a != nil {
+		return *a, true
+	}
+	return ListenAddrs{}, false
+}
+
+// handleListeners serves the addresses the server is listening on
+func (s *Server) handleListeners(w http.ResponseWriter, r *http.Request) {
+	addrs, ok := s.Addrs()
+	if !ok {
+		admin.WriteError(w, http.StatusServiceUnavailable, "server is not listening yet")
+		return
+	}
+	admin.WriteJSON(w, http.StatusOK, addrs)
+}
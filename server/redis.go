@@ -0,0 +1,76 @@
+// server/redis.go
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+)
+
+// redisOptions returns the cache options selecting cluster or Sentinel mode
+// and carrying the configured credentials, database, and TLS settings
+func redisOptions(cfg *Config) ([]cache.Option, error) {
+	var opts []cache.Option
+	if cfg.RedisCluster {
+		opts = append(opts, cache.WithCluster())
+	}
+	if cfg.RedisSentinelMaster != "" {
+		opts = append(opts, cache.WithSentinel(cfg.RedisSentinelMaster))
+	}
+	if cfg.CacheLocalFallbackSize > 0 {
+		opts = append(opts, cache.WithLocalFallback(cfg.CacheLocalFallbackSize))
+	}
+
+	conn := cache.Options{
+		Username:         cfg.RedisUsername,
+		Password:         cfg.RedisPassword,
+		DB:               cfg.RedisDB,
+		SentinelPassword: cfg.RedisSentinelPassword,
+	}
+	if cfg.RedisPasswordFile != "" {
+		data, err := os.ReadFile(cfg.RedisPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis_password_file: %w", err)
+		}
+		conn.Password = strings.TrimRight(string(data), "\r\n")
+	}
+	if cfg.RedisTLS {
+		tlsConfig, err := redisTLS(cfg)
+		if err != nil {
+			return nil, err
+		}
+		conn.TLS = tlsConfig
+	}
+	return append(opts, cache.WithOptions(conn)), nil
+}
+
+// redisTLS builds the TLS configuration for connections to Redis
+func redisTLS(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.RedisTLSServerName,
+	}
+	if cfg.RedisTLSCA != "" {
+		pem, err := os.ReadFile(cfg.RedisTLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis_tls_ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.RedisTLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.RedisTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.RedisTLSCert, cfg.RedisTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Redis client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
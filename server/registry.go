@@ -0,0 +1,59 @@
+// server/registry.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/modelfetch"
+	"github.com/SyedDaiam9101/policy-service/internal/registry"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// resolveRegistryModel resolves model_registry_model in the registry and
+// points the config at the version's model artifact, which fetchModel then
+// downloads. Registry versions are immutable, so the artifact is cached by
+// URL without a checksum, which modelfetch refuses over plain http.
+func (s *Server) resolveRegistryModel() error {
+	name, stage, err := registry.ParseRef(s.cfg.ModelRegistryModel)
+	if err != nil {
+		return err
+	}
+	client := registry.New(s.cfg.ModelRegistryURL, s.cfg.ModelRegistryToken)
+	version, err := client.Resolve(context.Background(), name, stage)
+	if err != nil {
+		return fmt.Errorf("failed to resolve registry model %s: %w", s.cfg.ModelRegistryModel, err)
+	}
+	artifact, err := client.ArtifactURL(version, s.cfg.ModelRegistryArtifact)
+	if err != nil {
+		return err
+	}
+	slog.Info("Resolved registry model",
+		"model", s.cfg.ModelRegistryModel, "version", version.Version, "stage", version.Stage, "artifact", artifact)
+
+	cfg := *s.cfg
+	cfg.ModelSHA256 = ""
+	switch {
+	case strings.HasPrefix(artifact, "file://"):
+		// A registry sharing a volume with the server
+		cfg.Model = strings.TrimPrefix(artifact, "file://")
+	case modelfetch.IsURL(artifact):
+		cfg.Model = artifact
+	default:
+		return fmt.Errorf("registry model %s version %s has artifact %s, which is not a URL the server can download",
+			name, version.Version, artifact)
+	}
+	s.cfg = &cfg
+
+	s.registryModel = &pb.RegistryModel{
+		Name:        version.Name,
+		Stage:       version.Stage,
+		Version:     version.Version,
+		ArtifactUri: version.ArtifactURI,
+	}
+	metrics.SetModelRegistryVersion(version.Name, version.Stage, version.Version)
+	return nil
+}
@@ -0,0 +1,167 @@
+// server/reload.go
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+)
+
+// errModelPromoted is returned when a completed rollout replaced the configured model
+var errModelPromoted = errors.New("the configured model was replaced by a completed rollout; use the rollout API instead")
+
+// modelVersion identifies the model file at path by the start of its SHA-256
+// digest and returns its size; both are empty if it cannot be read, e.g. for
+// the mock engine
+func modelVersion(path string) (string, int64) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], n
+}
+
+// reloadModel loads the configured model from disk and swaps it in. In-flight
+// batches finish on the previous session, which is then kept loaded as the
+// spare when configured and closed otherwise; on failure the previous session
+// keeps serving.
+func (s *Server) reloadModel(reason string) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if s.rollout != nil && s.rollout.State().Primary != "" {
+		return errModelPromoted
+	}
+
+	slog.Info("Reloading model", "model", s.cfg.Model, "reason", reason)
+	start := time.Now()
+	version, size := modelVersion(s.cfg.Model)
+	keepPrevious := s.cfg.ModelKeepPrevious && (s.cfg.ModelKeepPreviousMaxBytes == 0 || size <= s.cfg.ModelKeepPreviousMaxBytes)
+	engine, err := s.openEngine(s.cfg.Model)
+	if err == nil && s.cfg.WarmupInferences > 0 {
+		// Warm the new session before it takes traffic
+		if err = warmup(engine, s.cfg.WarmupInferences, s.cfg.WarmupBatchSizes); err != nil {
+			engine.Close()
+		}
+	}
+	if err == nil {
+		err = s.model.Swap(engine, version, keepPrevious)
+	}
+	metrics.RecordModelReload(err == nil)
+	if err != nil {
+		slog.Error("Model reload failed; keeping the previous model", "error", err)
+		return err
+	}
+	slog.Info("Model reloaded", "elapsed", time.Since(start).Round(time.Millisecond), "version", version, "previous_kept", keepPrevious)
+	logConfigDiff(s.cfg, "model reload")
+	return nil
+}
+
+// handleReload serves POST /admin/model/reload
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	err := s.reloadModel("admin request")
+	switch {
+	case err == nil:
+		admin.WriteJSON(w, http.StatusOK, map[string]string{"model": s.cfg.Model, "status": "reloaded"})
+	case errors.Is(err, errModelPromoted), errors.Is(err, inference.ErrShuttingDown):
+		admin.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		admin.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// rollbackModel swaps the previous model, kept loaded since the last reload,
+// back in and returns its version
+func (s *Server) rollbackModel() (string, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if s.rollout != nil && s.rollout.State().Primary != "" {
+		return "", errModelPromoted
+	}
+	version, err := s.model.Rollback()
+	if err != nil {
+		slog.Error("Model rollback failed", "error", err)
+		return "", err
+	}
+	slog.Info("Rolled back to the previous model", "version", version)
+	return version, nil
+}
+
+// handleRollback serves POST /admin/model/rollback
+func (s *Server) handleRollback(w http.ResponseWriter, r *http.Request) {
+	version, err := s.rollbackModel()
+	switch {
+	case err == nil:
+		admin.WriteJSON(w, http.StatusOK, map[string]string{"model": s.cfg.Model, "version": version, "status": "rolled back"})
+	case errors.Is(err, errModelPromoted), errors.Is(err, inference.ErrNoSpare), errors.Is(err, inference.ErrShuttingDown):
+		admin.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		admin.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// watchModel reloads the model when its file changes, once writes have settled
+// for the settle period so a partially copied model is never loaded
+func (s *Server) watchModel(ctx context.Context, settle time.Duration) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("Model file watching disabled", "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	// Watch the directory, since deploy tools often replace the file by rename,
+	// which drops a watch on the file itself
+	path := filepath.Clean(s.cfg.Model)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		slog.Warn("Model file watching disabled", "error", err)
+		return
+	}
+	slog.Info("Watching for model changes", "path", path)
+
+	settled := time.NewTimer(settle)
+	settled.Stop()
+	defer settled.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				settled.Reset(settle)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("Model file watcher error", "error", err)
+		case <-settled.C:
+			s.reloadModel("model file changed")
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// server/selftest.go
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+)
+
+// Self-test check outcomes
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+	CheckSkip = "skip"
+)
+
+// certExpiryWarning is how close to expiry a certificate is reported as a warning
+const certExpiryWarning = 7 * 24 * time.Hour
+
+// canaryBatchSizes are the batch sizes of the canary inferences
+var canaryBatchSizes = []int{1, 4}
+
+// SelfTestCheck is the outcome of one self-test check
+type SelfTestCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// SelfTestReport is the outcome of SelfTest; it passes when no check failed
+type SelfTestReport struct {
+	Passed bool            `json:"passed"`
+	Checks []SelfTestCheck `json:"checks"`
+}
+
+// SelfTest exercises what the server needs to serve traffic, without serving
+// it: the config, TLS material, model loading, canary inferences, and Redis.
+// It is meant to gate pods before they join a Service, e.g. as an init
+// container.
+func SelfTest(ctx context.Context, cfg *Config) SelfTestReport {
+	var report SelfTestReport
+	run := func(name string, check func() (string, string)) {
+		start := time.Now()
+		status, detail := check()
+		report.Checks = append(report.Checks, SelfTestCheck{
+			Name:     name,
+			Status:   status,
+			Detail:   detail,
+			Duration: time.Since(start).Round(time.Millisecond).String(),
+		})
+	}
+
+	run("config", func() (string, string) {
+		if err := cfg.Validate(); err != nil {
+			return CheckFail, err.Error()
+		}
+		return CheckPass, ""
+	})
+	run("tls", func() (string, string) { return checkTLS(ctx, cfg) })
+
+	s := &Server{cfg: cfg}
+	var engine inference.InferenceEngine
+	run("model", func() (string, string) {
+		var err error
+		engine, err = s.openEngine(cfg.Model)
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		info, ok := inference.Describe(engine)
+		if !ok {
+			return CheckPass, "mock engine"
+		}
+		if info.Fallback() {
+			return CheckWarn, fmt.Sprintf("providers %v appended; fell back: %s", info.AppendedProviders, strings.Join(info.ProviderErrors, "; "))
+		}
+		return CheckPass, fmt.Sprintf("providers %v, input %v, output %v", info.AppendedProviders, info.InputShape, info.OutputShape)
+	})
+	run("canary_inference", func() (string, string) {
+		if engine == nil {
+			return CheckSkip, "model did not load"
+		}
+		return checkCanary(engine)
+	})
+	if engine != nil {
+		engine.Close()
+	}
+	run("redis", func() (string, string) { return checkRedis(cfg) })
+
+	report.Passed = true
+	for _, c := range report.Checks {
+		if c.Status == CheckFail {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+// checkTLS loads the configured certificate and client CAs, or fetches the
+// SVID from the SPIFFE Workload API
+func checkTLS(ctx context.Context, cfg *Config) (string, string) {
+	if cfg.SPIFFEEndpointSocket != "" {
+		source, err := auth.NewSPIFFESource(ctx, cfg.SPIFFEEndpointSocket)
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		defer source.Close()
+		svid, err := source.GetX509SVID()
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		return certStatus(svid.Certificates[0], "SVID "+svid.ID.String())
+	}
+	if cfg.TLSCert == "" {
+		return CheckSkip, "TLS is not configured"
+	}
+
+	pair, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return CheckFail, fmt.Sprintf("invalid certificate %s: %v", cfg.TLSCert, err)
+	}
+	if cfg.TLSClientCA != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCA)
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+			return CheckFail, fmt.Sprintf("no certificates found in %s", cfg.TLSClientCA)
+		}
+	}
+	return certStatus(leaf, "certificate "+cfg.TLSCert)
+}
+
+// certStatus checks that cert is valid now and not about to expire
+func certStatus(cert *x509.Certificate, name string) (string, string) {
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore):
+		return CheckFail, fmt.Sprintf("%s is not valid until %s", name, cert.NotBefore.Format(time.RFC3339))
+	case now.After(cert.NotAfter):
+		return CheckFail, fmt.Sprintf("%s expired at %s", name, cert.NotAfter.Format(time.RFC3339))
+	case cert.NotAfter.Sub(now) < certExpiryWarning:
+		return CheckWarn, fmt.Sprintf("%s expires at %s", name, cert.NotAfter.Format(time.RFC3339))
+	}
+	return CheckPass, fmt.Sprintf("%s valid until %s", name, cert.NotAfter.Format(time.RFC3339))
+}
+
+// checkCanary runs batches of synthetic observations through engine and checks
+// that each returns one finite action per observation
+func checkCanary(engine inference.InferenceEngine) (string, string) {
+	c, h, w := inference.SyntheticDims(engine)
+	var details []string
+	for _, batch := range canaryBatchSizes {
+		obs := inference.SyntheticObservations(batch, c, h, w)
+
+		start := time.Now()
+		actions, err := engine.Predict(obs, c, h, w)
+		if err != nil {
+			return CheckFail, fmt.Sprintf("batch of %d (%dx%dx%d): %v", batch, c, h, w, err)
+		}
+		if len(actions) == 0 || len(actions)%batch != 0 {
+			return CheckFail, fmt.Sprintf("batch of %d returned %d action values", batch, len(actions))
+		}
+		for _, a := range actions {
+			if math.IsNaN(float64(a)) || math.IsInf(float64(a), 0) {
+				return CheckFail, fmt.Sprintf("batch of %d returned non-finite actions", batch)
+			}
+		}
+		details = append(details, fmt.Sprintf("batch %d in %s", batch, time.Since(start).Round(time.Microsecond)))
+	}
+	return CheckPass, fmt.Sprintf("%dx%dx%d observations: %s", c, h, w, strings.Join(details, ", "))
+}
+
+// checkRedis connects to the configured Redis. The server runs without the
+// cache when Redis is down, so this only fails when a feature requires it.
+func checkRedis(cfg *Config) (string, string) {
+	if cfg.Redis == "" {
+		return CheckSkip, "Redis is not configured"
+	}
+	opts, err := redisOptions(cfg)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+	c, err := cache.New(cfg.Redis, opts...)
+	if err != nil {
+		switch {
+		case cfg.RecorderEnabled:
+			return CheckFail, fmt.Sprintf("%v (required by the recorder)", err)
+		case cfg.EnrichmentSource == "redis" && cfg.EnrichmentRequired:
+			return CheckFail, fmt.Sprintf("%v (required by enrichment)", err)
+		}
+		return CheckWarn, fmt.Sprintf("%v (the server would run without the cache)", err)
+	}
+	c.Close()
+	return CheckPass, fmt.Sprintf("connected to %s", cfg.Redis)
+}
@@ -0,0 +1,1847 @@
+// Package server wires the PathPlanner gRPC service, its HTTP endpoints, and
+// optional subsystems together so the planner can be embedded in other binaries
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/SyedDaiam9101/policy-service/internal/action"
+	"github.com/SyedDaiam9101/policy-service/internal/admin"
+	"github.com/SyedDaiam9101/policy-service/internal/admission"
+	"github.com/SyedDaiam9101/policy-service/internal/affinity"
+	"github.com/SyedDaiam9101/policy-service/internal/auth"
+	"github.com/SyedDaiam9101/policy-service/internal/batching"
+	"github.com/SyedDaiam9101/policy-service/internal/cache"
+	"github.com/SyedDaiam9101/policy-service/internal/codec"
+	"github.com/SyedDaiam9101/policy-service/internal/config"
+	"github.com/SyedDaiam9101/policy-service/internal/dedup"
+	"github.com/SyedDaiam9101/policy-service/internal/enrich"
+	"github.com/SyedDaiam9101/policy-service/internal/epoch"
+	"github.com/SyedDaiam9101/policy-service/internal/handler"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/journal"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	"github.com/SyedDaiam9101/policy-service/internal/modelfetch"
+	"github.com/SyedDaiam9101/policy-service/internal/observation"
+	"github.com/SyedDaiam9101/policy-service/internal/placement"
+	"github.com/SyedDaiam9101/policy-service/internal/plancache"
+	"github.com/SyedDaiam9101/policy-service/internal/preprocess"
+	"github.com/SyedDaiam9101/policy-service/internal/publish"
+	"github.com/SyedDaiam9101/policy-service/internal/ratelimit"
+	"github.com/SyedDaiam9101/policy-service/internal/recorder"
+	"github.com/SyedDaiam9101/policy-service/internal/rollout"
+	"github.com/SyedDaiam9101/policy-service/internal/safety"
+	"github.com/SyedDaiam9101/policy-service/internal/scheduler"
+	"github.com/SyedDaiam9101/policy-service/internal/shedding"
+	"github.com/SyedDaiam9101/policy-service/internal/signing"
+	"github.com/SyedDaiam9101/policy-service/internal/snapshot"
+	"github.com/SyedDaiam9101/policy-service/internal/streams"
+	"github.com/SyedDaiam9101/policy-service/internal/telemetry"
+	"github.com/SyedDaiam9101/policy-service/internal/tenantgate"
+	"github.com/SyedDaiam9101/policy-service/internal/timeouts"
+	"github.com/SyedDaiam9101/policy-service/internal/watchdog"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// ServiceName is the name reported to the health service and tracing
+const ServiceName = "policy-service"
+
+// Config is the service configuration, loaded with config.Load or built directly
+type Config = config.Config
+
+// Server is an embeddable policy-service instance
+type Server struct {
+	cfg *Config
+
+	infer      inference.InferenceEngine
+	ownsEngine bool
+	model      *inference.Swappable
+	reloadMu   sync.Mutex
+	cache      *cache.Cache
+	ownsCache  bool
+	// localCache serves poses and plans in process when Redis was unreachable
+	// at startup; Redis-only features stay off
+	localCache *cache.Cache
+	signer     *signing.Signer
+	progress   *watchdog.Progress
+	statsd     *metrics.StatsD
+	decoder    *action.DiscreteDecoder
+	rollout    *rollout.Controller
+	admin      *admin.Mux
+	cpus       []int
+	telemetry  *telemetry.Collector
+	recorder   *recorder.Recorder
+	publisher  *publish.Publisher
+	placement  *placement.Tracker
+	upstream   *grpc.ClientConn
+	journal    *journal.Journal
+	compactor  *recorder.Compactor
+	epochs     *epoch.Runner
+	shedder    *shedding.Shedder
+	admission  *admission.Controller
+	streams    *streams.Tracker
+	gate       *tenantgate.Gate
+	snapshots  *snapshot.Snapshotter
+
+	// addrs are the addresses Run listens on, once it does
+	addrs atomic.Pointer[ListenAddrs]
+	// robotTokens issues and verifies robot tokens; nil when disabled
+	robotTokens *auth.RobotTokens
+	// breaker fails inference fast while the engine keeps failing
+	breaker *inference.Breaker
+	// cacheTimeout bounds pose and plan cache lookups; nil for none
+	cacheTimeout *timeouts.Stage
+
+	interceptors []grpc.UnaryServerInterceptor
+	grpcOptions  []grpc.ServerOption
+	listener     net.Listener
+	drainDelay   time.Duration
+
+	httpHandlers map[string]http.Handler
+	// registryModel is the registry version the model was resolved to; nil
+	// without a registry
+	registryModel *pb.RegistryModel
+	// versions are the older model versions requests may pin, by version
+	versions map[string]inference.InferenceEngine
+	// rolloutVersions are the content versions of models the rollout
+	// controller loaded, by path
+	rolloutVersions sync.Map
+	// canaryCreds secure the canary's loopback connection; nil for plaintext
+	canaryCreds credentials.TransportCredentials
+	// codecs are site-specific observation codecs added to the built-in ones
+	codecs       []customCodec
+	grpcServer   *grpc.Server
+	healthServer *health.Server
+	handler      *handler.Handler
+}
+
+// Option customizes a Server
+type Option func(*Server)
+
+// WithEngine uses the given inference engine instead of loading cfg.Model.
+// The caller keeps ownership and must close it after Run returns.
+func WithEngine(e inference.InferenceEngine) Option {
+	return func(s *Server) {
+		s.infer = e
+	}
+}
+
+// WithCache uses the given cache instead of connecting to cfg.Redis.
+// The caller keeps ownership and must close it after Run returns.
+func WithCache(c *cache.Cache) Option {
+	return func(s *Server) {
+		s.cache = c
+	}
+}
+
+// WithUnaryInterceptors appends interceptors after the built-in chain
+// (request ID, tenant, metrics, panic recovery, tracing)
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+// WithGRPCOptions passes additional options to grpc.NewServer
+func WithGRPCOptions(opts ...grpc.ServerOption) Option {
+	return func(s *Server) {
+		s.grpcOptions = append(s.grpcOptions, opts...)
+	}
+}
+
+// WithListener serves gRPC on lis instead of listening on cfg.Port
+func WithListener(lis net.Listener) Option {
+	return func(s *Server) {
+		s.listener = lis
+	}
+}
+
+// WithDrainDelay sets how long Run reports NOT_SERVING before stopping, giving
+// load balancers time to notice (default 5s)
+func WithDrainDelay(d time.Duration) Option {
+	return func(s *Server) {
+		s.drainDelay = d
+	}
+}
+
+// WithHTTPHandler registers an extra handler on the metrics/health HTTP server
+func WithHTTPHandler(path string, h http.Handler) Option {
+	return func(s *Server) {
+		s.httpHandlers[path] = h
+	}
+}
+
+// WithCanaryCredentials sets the transport credentials the synthetic canary
+// dials the gRPC listener with; it dials without TLS by default
+func WithCanaryCredentials(creds credentials.TransportCredentials) Option {
+	return func(s *Server) {
+		s.canaryCreds = creds
+	}
+}
+
+// customCodec is a codec registered through WithCodec
+type customCodec struct {
+	id    uint32
+	codec codec.Codec
+}
+
+// WithCodec registers a site-specific observation codec under id, which
+// clients put in Observation.encoding. IDs below codec.MinCustomID are
+// reserved for the built-in codecs.
+func WithCodec(id uint32, c codec.Codec) Option {
+	return func(s *Server) {
+		s.codecs = append(s.codecs, customCodec{id: id, codec: c})
+	}
+}
+
+// New builds a Server from cfg, loading the model and connecting to Redis
+// unless an engine or cache is supplied through options
+func New(cfg *Config, opts ...Option) (*Server, error) {
+	if cfg.ReplayMode {
+		var err error
+		if cfg, err = replayConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+	s := &Server{
+		cfg:          cfg,
+		progress:     &watchdog.Progress{},
+		drainDelay:   5 * time.Second,
+		httpHandlers: make(map[string]http.Handler),
+		healthServer: health.NewServer(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.setup(); err != nil {
+		s.closeOwned()
+		return nil, err
+	}
+	return s, nil
+}
+
+// setup creates the dependencies not supplied through options and registers services
+func (s *Server) setup() error {
+	cfg := s.cfg
+
+	// Push metrics to StatsD/DogStatsD instead of exposing them for scraping
+	if cfg.MetricsBackend == "statsd" {
+		sd, err := metrics.NewStatsD(cfg.StatsDAddress, cfg.StatsDPrefix, cfg.StatsDTags, cfg.StatsDFlushInterval)
+		if err != nil {
+			return err
+		}
+		s.statsd = sd
+		metrics.SetBackend(sd)
+		slog.Info("Sending metrics to StatsD", "address", cfg.StatsDAddress)
+	}
+
+	// Pin to the configured cores before ORT creates its thread pools
+	if err := s.pinCPUs(); err != nil {
+		return err
+	}
+
+	// Operator endpoints share the metrics/health HTTP server
+	s.admin = admin.New(cfg.AdminToken)
+	s.httpHandlers[admin.Prefix] = s.admin
+	s.admin.HandleFunc("GET /admin/config", s.handleConfig)
+	s.admin.HandleFunc("GET /admin/listeners", s.handleListeners)
+	logConfigDiff(cfg, "startup")
+
+	// Downsampled JSON snapshot for dashboards; needs no credentials
+	if cfg.TelemetryEnabled {
+		s.telemetry = telemetry.NewCollector(cfg.TelemetryInterval, cfg.TelemetryActiveWindow, s.serving)
+		s.httpHandlers["/telemetry"] = s.telemetry.Handler()
+	}
+
+	// Build the discrete action decoder for policies that output logits
+	if cfg.ActionMode != "" && cfg.ActionMode != action.ModeContinuous {
+		var err error
+		s.decoder, err = action.NewDiscreteDecoder(cfg.ActionMode, cfg.ActionTable, cfg.ActionTemperature)
+		if err != nil {
+			return fmt.Errorf("invalid discrete action config: %w", err)
+		}
+		slog.Info("Discrete action decoding enabled", "mode", cfg.ActionMode, "actions", s.decoder.NumActions())
+	}
+
+	// Load response signing keys
+	if cfg.SigningEnabled {
+		var err error
+		s.signer, err = signing.New(signing.DirSource{Dir: cfg.SigningKeyDir, ActiveID: cfg.SigningActiveKey})
+		if err != nil {
+			return fmt.Errorf("failed to load signing keys: %w", err)
+		}
+		slog.Info("Response signing enabled", "active_key", s.signer.ActiveKeyID())
+		s.httpHandlers["/signing/keys"] = s.signer.KeysHandler()
+	}
+
+	// Load robot token keys
+	if cfg.RobotTokensEnabled {
+		var err error
+		s.robotTokens, err = auth.NewRobotTokens(signing.DirSource{Dir: cfg.RobotTokenKeyDir, ActiveID: cfg.RobotTokenActiveKey}, cfg.RobotTokenIssuer)
+		if err != nil {
+			return err
+		}
+		slog.Info("Robot tokens enabled", "issuer", cfg.RobotTokenIssuer, "active_key", s.robotTokens.ActiveKeyID(), "ttl", cfg.RobotTokenTTL)
+	}
+
+	// Load inference engine
+	if s.infer == nil {
+		if cfg.ModelRegistryModel != "" && !cfg.UseMockInference {
+			if err := s.resolveRegistryModel(); err != nil {
+				return err
+			}
+			cfg = s.cfg
+		}
+		if modelfetch.IsURL(cfg.Model) && !cfg.UseMockInference {
+			if err := s.fetchModel(); err != nil {
+				return err
+			}
+			cfg = s.cfg
+		}
+		if err := s.loadEngine(); err != nil {
+			return err
+		}
+	} else {
+		s.infer = s.instrument(s.infer, cfg.Model)
+	}
+	// The breaker wraps the deadline, so timed out batches count as failures
+	inferenceTimeout := s.stageTimeout(timeouts.StageInference, cfg.InferenceTimeout, cfg.InferenceTimeoutFloor, cfg.InferenceTimeoutCeiling)
+	if inferenceTimeout != nil {
+		s.infer = inference.NewDeadline(s.infer, inferenceTimeout)
+	}
+	if len(cfg.ModelVersions) > 0 {
+		if err := s.loadModelVersions(inferenceTimeout); err != nil {
+			return err
+		}
+	}
+	s.cacheTimeout = s.stageTimeout(timeouts.StageCache, cfg.CacheTimeout, cfg.CacheTimeoutFloor, cfg.CacheTimeoutCeiling)
+	if cfg.BreakerEnabled {
+		s.breaker = inference.NewBreaker(s.infer, cfg.BreakerFailureThreshold, cfg.BreakerProbeInterval)
+		s.infer = s.breaker
+		slog.Info("Inference circuit breaker enabled", "threshold", cfg.BreakerFailureThreshold, "probe_interval", cfg.BreakerProbeInterval)
+	}
+
+	// Initialize Redis cache (optional)
+	if s.cache == nil && cfg.Redis != "" {
+		// Tenant isolation is contractual, so missing keys are fatal rather than
+		// falling back to plaintext
+		cacheOpts, err := redisOptions(cfg)
+		if err != nil {
+			return err
+		}
+		if cfg.CacheEncryptionEnabled {
+			keyring, err := cache.LoadKeyring(cfg.CacheEncryptionKeyDir)
+			if err != nil {
+				return fmt.Errorf("failed to load cache encryption keys: %w", err)
+			}
+			slog.Info("Cache encryption enabled", "tenant_keys", keyring.Len())
+			cacheOpts = append(cacheOpts, cache.WithKeyring(keyring))
+		}
+
+		slog.Info("Connecting to Redis", "address", cfg.Redis)
+		c, err := cache.New(cfg.Redis, cacheOpts...)
+		switch {
+		case err != nil && cfg.CacheLocalFallbackSize > 0:
+			slog.Warn("Failed to connect to Redis; caching in process until restarted", "error", err, "local_fallback_size", cfg.CacheLocalFallbackSize)
+			s.localCache = cache.NewLocal(cfg.CacheLocalFallbackSize, cacheOpts...)
+		case err != nil:
+			slog.Warn("Failed to connect to Redis; continuing without cache", "error", err)
+		default:
+			s.cache = c
+			s.ownsCache = true
+			slog.Info("Redis connected successfully")
+		}
+	}
+
+	// Append feature store features to observations for models that expect them
+	var enricher *enrich.Enricher
+	if cfg.EnrichmentSource != "" {
+		var err error
+		enricher, err = s.newEnricher()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record served episodes to a Redis stream for offline training
+	if cfg.RecorderEnabled {
+		if err := s.setupRecorder(); err != nil {
+			return err
+		}
+	}
+
+	// Publish planned actions to Redis for telemetry and visualization
+	if cfg.PublishEnabled {
+		if s.cache == nil {
+			return fmt.Errorf("publish_enabled requires the Redis cache")
+		}
+		sink := publish.RedisSink{Cache: s.cache, Mode: cfg.PublishMode, Target: cfg.PublishTarget, MaxLen: cfg.PublishStreamMaxLen}
+		s.publisher = publish.New(sink, cfg.PublishBufferSize)
+		slog.Info("Plan publisher enabled", "mode", cfg.PublishMode, "target", cfg.PublishTarget)
+	}
+
+	// Track which replica plans each robot
+	if cfg.PlacementEnabled {
+		if s.cache == nil {
+			return fmt.Errorf("placement_enabled requires the Redis cache")
+		}
+		replica := cfg.ReplicaID
+		if replica == "" {
+			replica, _ = os.Hostname()
+		}
+		s.placement = placement.New(s.cache, replica, cfg.PlacementIdle)
+		s.placement.Register(s.admin)
+		slog.Info("Robot placement tracking enabled", "replica", replica, "idle", cfg.PlacementIdle)
+	}
+
+	// Proxy calls this edge instance cannot serve to an upstream instance
+	if cfg.UpstreamAddress != "" {
+		conn, err := dialUpstream(cfg)
+		if err != nil {
+			return err
+		}
+		s.upstream = conn
+		slog.Info("Upstream proxy enabled",
+			"address", cfg.UpstreamAddress, "timeout", cfg.UpstreamTimeout, "max_hops", cfg.UpstreamMaxHops)
+	}
+
+	// Keep call summaries on local disk for post-incident reconstruction
+	if cfg.JournalEnabled {
+		if err := s.setupJournal(); err != nil {
+			return err
+		}
+	}
+
+	// Route traffic between the primary model and a candidate under rollout
+	if cfg.RolloutEnabled {
+		if err := s.setupRollout(); err != nil {
+			return err
+		}
+	}
+	s.exportThresholds()
+
+	// Build interceptor chain; the rate limiters and shedder sit inside the
+	// metrics interceptor so rejected calls are counted, the per-peer limiter
+	// before authentication so floods are turned away cheaply, the per-client
+	// limiter after it so quotas follow subjects callers cannot choose, and the
+	// shedder outside recovery so panics spend budget. The journal sits after authentication,
+	// so entries name the authenticated client, and before the shedder, so shed
+	// calls are journaled. The admission controller sits inside the shedder,
+	// so calls rejected for overload spend budget and shed calls take no slot.
+	// The client send time is read first, so client transit excludes the
+	// server's own work.
+	// With interceptor timing, each stage records the time spent in its own
+	// interceptor, excluding the rest of the chain
+	var interceptors []grpc.UnaryServerInterceptor
+	use := func(stage string, i grpc.UnaryServerInterceptor) {
+		if cfg.InterceptorTimingEnabled {
+			i = middleware.UnaryTimedInterceptor(stage, i)
+		}
+		interceptors = append(interceptors, i)
+	}
+	use("client_send_time", middleware.UnaryClientSendTimeInterceptor())
+	use("request_id", middleware.UnaryRequestIDInterceptor())
+	use("tenant", middleware.UnaryTenantInterceptor())
+	use("client_identity", middleware.UnaryClientIdentityInterceptor())
+	use("priority", middleware.UnaryPriorityInterceptor(pb.PathPlanner_PlanAsync_FullMethodName))
+	use("metrics", middleware.UnaryMetricsInterceptor())
+	peerLimiter, limiter := s.rateLimiters()
+	if peerLimiter != nil {
+		use("peer_rate_limit", peerLimiter.UnaryInterceptor())
+	}
+	authChain, err := s.authChain()
+	if err != nil {
+		return err
+	}
+	if authChain != nil {
+		use("auth", authChain.UnaryInterceptor())
+	}
+	if limiter != nil {
+		use("rate_limit", limiter.UnaryInterceptor())
+	}
+	// Operators pause or drain single tenants from the admin API
+	s.gate = tenantgate.New()
+	s.gate.Register(s.admin)
+	use("tenant_gate", s.gate.UnaryInterceptor())
+	if s.journal != nil {
+		use("journal", s.journal.UnaryInterceptor())
+	}
+	if cfg.SheddingEnabled {
+		s.setupShedding()
+		use("shedding", s.shedder.UnaryInterceptor())
+	}
+	if cfg.AdmissionEnabled {
+		s.setupAdmission()
+		use("admission", s.admission.UnaryInterceptor(map[string]bool{
+			pb.PathPlanner_Plan_FullMethodName:      true,
+			pb.PathPlanner_BatchPlan_FullMethodName: true,
+		}))
+	}
+	use("recovery", middleware.UnaryRecoveryInterceptor())
+
+	// Add OpenTelemetry interceptor if enabled; robot IDs are extracted first
+	// so the sampler can always trace robots under investigation
+	if cfg.OTELEnabled {
+		if len(cfg.OTELSampleRobotIDs) > 0 {
+			use("robot_id", middleware.UnaryRobotIDInterceptor())
+		}
+		use("otel", otelgrpc.UnaryServerInterceptor())
+	}
+	for _, i := range s.interceptors {
+		use("custom", i)
+	}
+	if cfg.InterceptorTimingEnabled {
+		interceptors = append(interceptors, middleware.UnaryHandlerTimingInterceptor())
+		slog.Info("Interceptor timing enabled", "stages", len(interceptors))
+	}
+
+	// BatchPlanStream gets the same request ID, tenant, metrics, and tracing.
+	// The stream tracker sits inside the metrics interceptor so reaped streams
+	// are counted as UNAVAILABLE.
+	s.streams = streams.New(cfg.StreamIdleTimeout)
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		middleware.StreamClientSendTimeInterceptor(),
+		middleware.StreamRequestIDInterceptor(),
+		middleware.StreamTenantInterceptor(),
+		middleware.StreamClientIdentityInterceptor(),
+		middleware.StreamPriorityInterceptor(),
+		middleware.StreamMetricsInterceptor(),
+		s.streams.StreamInterceptor(),
+	}
+	if peerLimiter != nil {
+		streamInterceptors = append(streamInterceptors, peerLimiter.StreamInterceptor())
+	}
+	if authChain != nil {
+		streamInterceptors = append(streamInterceptors, authChain.StreamInterceptor())
+	}
+	if limiter != nil {
+		streamInterceptors = append(streamInterceptors, limiter.StreamInterceptor())
+	}
+	streamInterceptors = append(streamInterceptors, s.gate.StreamInterceptor())
+	if s.journal != nil {
+		streamInterceptors = append(streamInterceptors, s.journal.StreamInterceptor())
+	}
+	if s.shedder != nil {
+		streamInterceptors = append(streamInterceptors, s.shedder.StreamInterceptor())
+	}
+	streamInterceptors = append(streamInterceptors, middleware.StreamRecoveryInterceptor())
+	if cfg.OTELEnabled {
+		streamInterceptors = append(streamInterceptors, otelgrpc.StreamServerInterceptor())
+	}
+
+	// Create gRPC server with interceptors
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.GRPCKeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+	// Serve TLS from the tls_* files; SPIFFE credentials, which need the
+	// Workload API, are passed in through WithGRPCOptions
+	if cfg.TLSCert != "" {
+		creds, err := serverCredentials(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS configuration: %w", err)
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(creds))
+		if cfg.CanaryEnabled && s.canaryCreds == nil {
+			if s.canaryCreds, err = canaryCredentials(cfg); err != nil {
+				return fmt.Errorf("failed to load TLS configuration: %w", err)
+			}
+		}
+		slog.Info("gRPC TLS enabled", "cert", cfg.TLSCert, "client_ca", cfg.TLSClientCA)
+	}
+	grpcOpts = append(grpcOpts, s.grpcOptions...)
+	if cfg.GRPCKeepaliveTime > 0 {
+		grpcOpts = append(grpcOpts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.GRPCKeepaliveTime,
+			Timeout: cfg.GRPCKeepaliveTimeout,
+		}))
+	}
+	s.grpcServer = grpc.NewServer(grpcOpts...)
+
+	// Enable weighted fair scheduling across tenants
+	var handlerOpts []handler.Option
+	if cfg.FairScheduling {
+		slog.Info("Fair scheduling enabled", "slots", cfg.SchedulerSlots, "tenant_weights", cfg.TenantWeights)
+		handlerOpts = append(handlerOpts, handler.WithScheduler(scheduler.New(cfg.SchedulerSlots, cfg.TenantWeights)))
+	}
+	if s.decoder != nil {
+		handlerOpts = append(handlerOpts, handler.WithActionDecoder(s.decoder))
+	}
+	if s.signer != nil {
+		handlerOpts = append(handlerOpts, handler.WithSigner(s.signer))
+	}
+	if s.robotTokens != nil {
+		handlerOpts = append(handlerOpts, handler.WithRobotTokens(s.robotTokens, cfg.RobotTokenTTL, cfg.RobotTokenMaxTTL, cfg.RobotTokenIssuers))
+	}
+	if cfg.WatchdogEnabled {
+		handlerOpts = append(handlerOpts, handler.WithProgress(s.progress))
+	}
+	if enricher != nil {
+		handlerOpts = append(handlerOpts, handler.WithEnricher(enricher))
+	}
+	if s.telemetry != nil {
+		handlerOpts = append(handlerOpts, handler.WithTelemetry(s.telemetry))
+	}
+	if cfg.DedupEnabled {
+		if opt := s.dedupWindow(enricher); opt != nil {
+			handlerOpts = append(handlerOpts, opt)
+		}
+	}
+	if cfg.PoseMode != "" {
+		if s.valueCache() == nil {
+			slog.Warn("Redis unavailable; robot poses are not cached")
+		}
+		slog.Info("Robot pose cache enabled", "mode", cfg.PoseMode, "ttl", cfg.PoseTTL)
+		handlerOpts = append(handlerOpts, handler.WithPoseCache(cfg.PoseMode, cfg.PoseTTL))
+	}
+	if s.cacheTimeout != nil && (cfg.PoseMode != "" || cfg.PlanCacheEnabled) {
+		handlerOpts = append(handlerOpts, handler.WithCacheTimeout(s.cacheTimeout))
+	}
+	if cfg.PlanCacheEnabled {
+		if opt := s.planCache(enricher); opt != nil {
+			handlerOpts = append(handlerOpts, opt)
+		}
+	}
+	if cfg.BatchingEnabled {
+		slog.Info("Cross-request batching enabled", "window", cfg.BatchingWindow, "max_batch", cfg.BatchingMaxBatch)
+		handlerOpts = append(handlerOpts, handler.WithBatcher(batching.New(s.infer, cfg.BatchingWindow, cfg.BatchingMaxBatch, cfg.MaxBatchSize)))
+	}
+	handlerOpts = append(handlerOpts, handler.WithStreamChunks(cfg.StreamChunkSize, cfg.StreamMaxConcurrentChunks))
+	handlerOpts = append(handlerOpts, handler.WithPlanStreamInterval(cfg.PlanStreamInterval, cfg.PlanStreamMinInterval))
+	handlerOpts = append(handlerOpts, handler.WithAggregation(cfg.AggregateBatchSize, cfg.AggregateMaxRequests))
+	handlerOpts = append(handlerOpts, handler.WithWarmup(cfg.WarmupInferences, cfg.WarmupBatchSizes))
+	if cfg.ClientHintsEnabled {
+		slog.Info("Client hints enabled",
+			"queue_target", cfg.ClientHintsQueueTarget, "base_interval", cfg.ClientHintsBaseInterval, "max_interval", cfg.ClientHintsMaxInterval)
+		handlerOpts = append(handlerOpts, handler.WithClientHints(cfg.ClientHintsQueueTarget, cfg.ClientHintsBaseInterval, cfg.ClientHintsMaxInterval))
+	}
+	if cfg.AsyncEnabled {
+		slog.Info("Async planning enabled", "workers", cfg.AsyncWorkers, "queue", cfg.AsyncQueueSize, "result_ttl", cfg.AsyncResultTTL)
+		handlerOpts = append(handlerOpts, handler.WithAsync(cfg.AsyncWorkers, cfg.AsyncQueueSize, cfg.AsyncResultTTL))
+	}
+	if cfg.PlanUpdatesEnabled {
+		slog.Info("Plan updates enabled",
+			"interval", cfg.PlanUpdatesInterval, "max_wait", cfg.PlanUpdatesMaxWait, "idle_timeout", cfg.PlanUpdatesIdleTimeout)
+		handlerOpts = append(handlerOpts, handler.WithPlanUpdates(cfg.PlanUpdatesInterval, cfg.PlanUpdatesMaxWait, cfg.PlanUpdatesIdleTimeout))
+	}
+	if s.recorder != nil {
+		handlerOpts = append(handlerOpts, handler.WithRecorder(s.recorder))
+	}
+	if s.publisher != nil {
+		handlerOpts = append(handlerOpts, handler.WithPublisher(s.publisher))
+	}
+	if s.placement != nil {
+		handlerOpts = append(handlerOpts, handler.WithPlacement(s.placement))
+	}
+	if s.admission != nil {
+		handlerOpts = append(handlerOpts, handler.WithAdmission(s.admission))
+	}
+	if s.upstream != nil {
+		handlerOpts = append(handlerOpts, handler.WithUpstream(pb.NewPathPlannerClient(s.upstream),
+			cfg.UpstreamTimeout, cfg.UpstreamQueueThreshold, cfg.UpstreamMaxHops, cfg.UpstreamCacheTTL))
+	}
+	contract, err := s.actionContract()
+	if err != nil {
+		return err
+	}
+	handlerOpts = append(handlerOpts, handler.WithContract(contract))
+	if cfg.NonFiniteOutputs == "substitute" {
+		if contract.Dim != 0 && len(cfg.NonFiniteSafeAction) != contract.Dim {
+			return fmt.Errorf("non_finite_safe_action has %d values but actions have %d", len(cfg.NonFiniteSafeAction), contract.Dim)
+		}
+		slog.Info("Non-finite model outputs answered with the safe action", "action", cfg.NonFiniteSafeAction)
+		handlerOpts = append(handlerOpts, handler.WithNonFiniteSubstitute(cfg.NonFiniteSafeAction))
+	}
+	if cfg.SafetyEnabled {
+		limits := make([]safety.Limit, len(cfg.SafetyLimits))
+		for i, l := range cfg.SafetyLimits {
+			limits[i] = safety.Limit{Min: l[0], Max: l[1]}
+		}
+		if len(limits) > 1 && contract.Dim != 0 && len(limits) != contract.Dim {
+			return fmt.Errorf("safety_limits has %d entries but actions have %d values", len(limits), contract.Dim)
+		}
+		checker, err := safety.New(cfg.SafetyMode, limits)
+		if err != nil {
+			return fmt.Errorf("invalid safety config: %w", err)
+		}
+		slog.Info("Safety limits enabled", "mode", cfg.SafetyMode, "limits", len(limits))
+		handlerOpts = append(handlerOpts, handler.WithSafety(checker))
+	}
+	space, err := action.NewSpace(contract, s.decoder, cfg.ActionValueNames, cfg.ActionLabels)
+	if err != nil {
+		return fmt.Errorf("invalid action space config: %w", err)
+	}
+	handlerOpts = append(handlerOpts, handler.WithActionSpace(space, cfg.ActionSpaceInResponses))
+	if cfg.MaxBatchSize > 0 {
+		slog.Info("Batches limited", "max_batch_size", cfg.MaxBatchSize)
+		handlerOpts = append(handlerOpts, handler.WithMaxBatchSize(cfg.MaxBatchSize))
+	}
+	if workers := cfg.BatchValidationWorkers; workers != 1 {
+		if workers == 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		slog.Info("Parallel batch validation enabled", "workers", workers, "min_batch", cfg.BatchValidationMinParallel)
+		handlerOpts = append(handlerOpts, handler.WithParallelValidation(workers, cfg.BatchValidationMinParallel))
+	}
+	if cfg.MaxTensorBytes > 0 {
+		slog.Info("Input tensors limited", "max_tensor_bytes", cfg.MaxTensorBytes)
+		handlerOpts = append(handlerOpts, handler.WithMaxTensorBytes(cfg.MaxTensorBytes))
+	}
+	codecs := codec.Builtin(observation.Normalization{
+		Scale: cfg.Uint8Scale,
+		Mean:  cfg.Uint8Mean,
+		Std:   cfg.Uint8Std,
+	})
+	for _, c := range s.codecs {
+		if c.id < codec.MinCustomID {
+			return fmt.Errorf("codec %q: custom encodings start at %d, got %d", c.codec.Name(), codec.MinCustomID, c.id)
+		}
+		if err := codecs.Register(c.id, c.codec); err != nil {
+			return err
+		}
+		slog.Info("Observation codec registered", "codec", c.codec.Name(), "encoding", c.id)
+	}
+	handlerOpts = append(handlerOpts, handler.WithCodecs(codecs))
+	if rules := s.inputRanges(); len(rules) > 0 {
+		slog.Info("Observation range checks enabled", "rules", len(rules))
+		handlerOpts = append(handlerOpts, handler.WithInputRanges(rules))
+	}
+	if rules := s.preprocessRules(); len(rules) > 0 {
+		slog.Info("Observation preprocessing enabled", "pipelines", len(rules))
+		handlerOpts = append(handlerOpts, handler.WithPreprocess(rules))
+	}
+	if cfg.DownsampleEnabled {
+		if opt := s.downsampling(); opt != nil {
+			handlerOpts = append(handlerOpts, opt)
+		}
+	}
+	if rules := s.validityRules(); cfg.ActionValidity > 0 || len(rules) > 0 {
+		slog.Info("Action validity enabled", "default", cfg.ActionValidity, "rules", len(rules))
+		handlerOpts = append(handlerOpts, handler.WithActionValidity(cfg.ActionValidity, rules))
+	}
+	if cfg.CommitEnabled {
+		slog.Info("Two-phase commit enabled", "veto_window", cfg.CommitVetoWindow, "ttl", cfg.CommitTTL, "clients", cfg.CommitClients)
+		handlerOpts = append(handlerOpts, handler.WithTwoPhaseCommit(cfg.CommitVetoWindow, cfg.CommitTTL, cfg.CommitClients, cfg.CommitSupervisorRole))
+	}
+	if s.registryModel != nil {
+		handlerOpts = append(handlerOpts, handler.WithRegistryModel(s.registryModel))
+	}
+	if s.versions != nil {
+		handlerOpts = append(handlerOpts, handler.WithModelVersions(cfg.ModelVersion, s.versions))
+	}
+
+	// Register PathPlanner service
+	s.handler = handler.New(s.infer, s.valueCache(), handlerOpts...)
+	pb.RegisterPathPlannerServer(s.grpcServer, s.handler)
+
+	// Snapshot per-robot state so a restart does not reset every robot
+	if cfg.StateSnapshotEnabled {
+		if s.cache == nil {
+			return fmt.Errorf("state_snapshot_enabled requires the Redis cache")
+		}
+		replica := cfg.ReplicaID
+		if replica == "" {
+			replica, _ = os.Hostname()
+		}
+		key := cfg.StateSnapshotKey + ":" + replica
+		s.snapshots = snapshot.New(s.cache, key, cfg.StateSnapshotInterval, cfg.StateSnapshotMaxAge, s.handler.StateSources()...)
+		slog.Info("State snapshots enabled", "key", key, "interval", cfg.StateSnapshotInterval, "max_age", cfg.StateSnapshotMaxAge)
+	}
+
+	// Plan configured robot groups at fixed control epochs
+	if len(cfg.PlanSchedules) > 0 {
+		if err := s.setupPlanSchedules(); err != nil {
+			return err
+		}
+	}
+
+	// Register health service
+	healthpb.RegisterHealthServer(s.grpcServer, s.healthServer)
+
+	// Enable server reflection for debugging
+	reflection.Register(s.grpcServer)
+
+	return nil
+}
+
+// fetchModel downloads a model given as a URL into the model cache and
+// points the config at the cached file
+func (s *Server) fetchModel() error {
+	slog.Info("Fetching model", "model", s.cfg.Model, "cache_dir", s.cfg.ModelCacheDir)
+	fetcher := modelfetch.New(s.cfg.ModelCacheDir, s.cfg.ModelS3Endpoint, s.cfg.ModelS3Region)
+	if s.registryModel != nil && strings.HasPrefix(s.cfg.Model, strings.TrimRight(s.cfg.ModelRegistryURL, "/")+"/") {
+		// Artifacts proxied by the registry need its token
+		fetcher.HTTPToken = s.cfg.ModelRegistryToken
+	}
+	local, err := fetcher.Fetch(context.Background(), s.cfg.Model, s.cfg.ModelSHA256)
+	if err != nil {
+		return err
+	}
+	slog.Info("Model cached", "model", s.cfg.Model, "path", local)
+
+	cfg := *s.cfg
+	cfg.Model = local
+	s.cfg = &cfg
+	return nil
+}
+
+// loadEngine loads the model named in the config
+func (s *Server) loadEngine() error {
+	if s.cfg.UseMockInference {
+		slog.Info("Using mock inference engine")
+	} else {
+		slog.Info("Loading ONNX model", "path", s.cfg.Model)
+	}
+
+	engine, err := s.openEngine(s.cfg.Model)
+	if err != nil {
+		return err
+	}
+	// Loaded models can be swapped for a new version without a restart
+	version, _ := modelVersion(s.cfg.Model)
+	s.model = inference.NewSwappable(engine, version)
+	s.infer = s.model
+	s.ownsEngine = true
+	s.admin.HandleFunc("POST /admin/model/reload", s.handleReload)
+	s.admin.HandleFunc("POST /admin/model/rollback", s.handleRollback)
+	return nil
+}
+
+// openEngine loads an ONNX model (or the mock engine when configured),
+// sized for the configured action decoder
+func (s *Server) openEngine(path string) (inference.InferenceEngine, error) {
+	if s.cfg.UseMockInference {
+		return s.instrument(inference.NewMock(), path), nil
+	}
+
+	devices := s.cfg.ORTDevices
+	if len(devices) == 0 {
+		// GPU 0 if an accelerator is configured, otherwise ignored
+		devices = []int{0}
+	}
+	perDevice := max(s.cfg.ORTSessionsPerDevice, 1)
+
+	// A single session
+	if len(devices) == 1 && perDevice == 1 {
+		engine, err := s.openSession(path, devices[0], s.cpus)
+		if err != nil {
+			return nil, err
+		}
+		return s.instrument(engine, path), nil
+	}
+
+	// perDevice sessions pinned to each GPU, with batches spread across all of
+	// them; each session runs one batch at a time, so they run in parallel
+	engines := make([]inference.InferenceEngine, 0, len(devices)*perDevice)
+	sessionDevices := make([]int, 0, len(devices)*perDevice)
+	for _, device := range devices {
+		for i := 0; i < perDevice; i++ {
+			engine, err := s.openSession(path, device, sessionCPUs(s.cpus, i, perDevice))
+			if err != nil {
+				for _, e := range engines {
+					e.Close()
+				}
+				return nil, err
+			}
+			engines = append(engines, engine)
+			sessionDevices = append(sessionDevices, device)
+		}
+	}
+	pool, err := inference.NewPool(engines, sessionDevices, s.cfg.ORTDeviceDispatch)
+	if err != nil {
+		for _, e := range engines {
+			e.Close()
+		}
+		return nil, err
+	}
+	if len(s.cfg.ORTDevices) > 1 {
+		slog.Info("Dispatching model across GPUs", "path", path, "devices", s.cfg.ORTDevices, "sessions_per_device", perDevice, "dispatch", s.cfg.ORTDeviceDispatch)
+	} else {
+		slog.Info("Dispatching model across sessions", "path", path, "sessions", perDevice, "dispatch", s.cfg.ORTDeviceDispatch)
+	}
+	return s.instrument(pool, path), nil
+}
+
+// pinCPUs restricts the process to the configured cores or NUMA node, keeping
+// inference threads and the memory they first touch on one node
+func (s *Server) pinCPUs() error {
+	var cpus []int
+	var err error
+	switch {
+	case s.cfg.CPUAffinity != "":
+		cpus, err = affinity.ParseCPUList(s.cfg.CPUAffinity)
+	case s.cfg.CPUAffinityNUMANode >= 0:
+		cpus, err = affinity.NodeCPUs(s.cfg.CPUAffinityNUMANode)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := affinity.Pin(cpus); err != nil {
+		return fmt.Errorf("failed to pin CPUs %v: %w", cpus, err)
+	}
+	s.cpus = cpus
+	slog.Info("Pinned to CPUs", "cpus", cpus)
+	return nil
+}
+
+// sessionCPUs returns the share of cpus for session i of n on a device, so
+// parallel sessions pin their intra-op threads to different cores. Sessions
+// share all cpus when there are fewer cpus than sessions.
+func sessionCPUs(cpus []int, i, n int) []int {
+	if len(cpus) < n {
+		return cpus
+	}
+	return cpus[i*len(cpus)/n : (i+1)*len(cpus)/n]
+}
+
+// openSession loads one ONNX Runtime session for path on the given GPU, with
+// its intra-op threads pinned to cpus
+func (s *Server) openSession(path string, device int, cpus []int) (inference.InferenceEngine, error) {
+	loadStart := time.Now()
+	engine, err := inference.NewWithOptions(path, inference.Options{
+		OptimizedModelDir:       s.cfg.ORTOptimizedModelDir,
+		ExecutionProviders:      s.cfg.ORTExecutionProviders,
+		DeviceID:                device,
+		CUDAMemLimit:            s.cfg.ORTCUDAMemLimit,
+		TensorRTEngineCachePath: s.cfg.ORTTensorRTEngineCachePath,
+		TensorRTFP16:            s.cfg.ORTTensorRTFP16,
+		IntraOpThreads:          s.cfg.ORTIntraOpThreads,
+		InterOpThreads:          s.cfg.ORTInterOpThreads,
+		IntraOpAffinity:         affinity.IntraOpAffinities(cpus, s.cfg.ORTIntraOpThreads),
+		Quantization: inference.Quantization{
+			Format:         s.cfg.ModelQuantization,
+			InputType:      s.cfg.ModelInputType,
+			InputScale:     s.cfg.ModelInputScale,
+			InputZeroPoint: s.cfg.ModelInputZeroPoint,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ONNX model: %w", err)
+	}
+	if s.decoder != nil {
+		// The model emits one logit per discrete action
+		if err := engine.SetActionDim(int64(s.decoder.NumActions())); err != nil {
+			engine.Close()
+			return nil, fmt.Errorf("model does not match discrete_actions: %w", err)
+		}
+	}
+	slog.Info("ONNX model loaded successfully", "path", path, "elapsed", time.Since(loadStart).Round(time.Millisecond))
+	logRuntimeInfo(engine.RuntimeInfo())
+	return engine, nil
+}
+
+// logRuntimeInfo logs how ORT runs a model and exports its appended providers,
+// so a silent fallback from an accelerator to CPU is visible
+func logRuntimeInfo(info inference.RuntimeInfo) {
+	threads := func(n int) string {
+		if n == 0 {
+			return "default"
+		}
+		return fmt.Sprint(n)
+	}
+
+	attrs := []any{
+		"model", info.ModelPath,
+		"ort_version", info.ORTVersion,
+		"requested_providers", info.RequestedProviders,
+		"appended_providers", info.AppendedProviders,
+	}
+	if len(info.InputShape) > 0 {
+		attrs = append(attrs, "input_shape", info.InputShape, "output_shape", info.OutputShape)
+	}
+	if info.InputType != "" && info.InputType != inference.InputFloat32 {
+		attrs = append(attrs, "input_type", info.InputType)
+	}
+	if info.Quantization != "" {
+		attrs = append(attrs, "quantization", info.Quantization)
+	}
+	if len(info.Devices) > 0 {
+		attrs = append(attrs, "devices", info.Devices)
+	}
+	if info.Sessions > 1 {
+		attrs = append(attrs, "sessions", info.Sessions)
+	}
+	if info.CUDAMemLimit > 0 {
+		attrs = append(attrs, "cuda_mem_limit_bytes", info.CUDAMemLimit)
+	}
+	attrs = append(attrs,
+		"intra_op_threads", threads(info.IntraOpThreads),
+		"inter_op_threads", threads(info.InterOpThreads))
+	if info.IntraOpAffinity != "" {
+		attrs = append(attrs, "intra_op_affinity", info.IntraOpAffinity)
+	}
+	slog.Info("ONNX Runtime session", attrs...)
+	for _, reason := range info.ProviderErrors {
+		slog.Warn("Execution provider unavailable, falling back", "reason", reason)
+	}
+
+	metrics.SetExecutionProviders(filepath.Base(info.ModelPath), info.AppendedProviders, info.Fallback())
+}
+
+// instrument reports the engine's batches to the telemetry snapshot, if enabled
+func (s *Server) instrument(engine inference.InferenceEngine, path string) inference.InferenceEngine {
+	if s.telemetry == nil {
+		return engine
+	}
+	return telemetry.Instrument(engine, filepath.Base(path), s.telemetry)
+}
+
+// newEnricher builds the enrichment stage for the configured model, or returns
+// nil if no features are configured for it
+func (s *Server) newEnricher() (*enrich.Enricher, error) {
+	var features []enrich.Feature
+	for _, m := range s.cfg.EnrichmentModels {
+		if m.Model != s.cfg.Model && m.Model != filepath.Base(s.cfg.Model) {
+			continue
+		}
+		for _, f := range m.Features {
+			features = append(features, enrich.Feature{Name: f.Name, Dim: f.Dim, Default: f.Default})
+		}
+	}
+	if len(features) == 0 {
+		slog.Info("No enrichment features configured", "model", s.cfg.Model)
+		return nil, nil
+	}
+
+	var source enrich.Source
+	switch s.cfg.EnrichmentSource {
+	case "feast":
+		source = enrich.NewFeastSource(s.cfg.EnrichmentFeastURL)
+	default:
+		if s.cache == nil {
+			if s.cfg.EnrichmentRequired {
+				return nil, fmt.Errorf("enrichment from Redis is required but Redis is unavailable")
+			}
+			slog.Warn("Redis unavailable; observations will not be enriched")
+			return nil, nil
+		}
+		source = enrich.RedisSource{Cache: s.cache}
+	}
+
+	enricher, err := enrich.New(source, features, s.cfg.EnrichmentTimeout, s.cfg.EnrichmentRequired)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrichment config: %w", err)
+	}
+	slog.Info("Observation enrichment enabled",
+		"source", source.Name(), "features", len(features), "dim", enricher.Dim())
+	return enricher, nil
+}
+
+// actionContract returns the contract every inference result is checked
+// against, failing if the configured action_dim disagrees with the model
+func (s *Server) actionContract() (action.Contract, error) {
+	var dim int
+	if s.decoder != nil {
+		dim = s.decoder.ActionDim()
+	} else if info, ok := inference.Describe(s.infer); ok {
+		dim = int(info.ActionDim)
+	}
+
+	contract := action.Contract{Dim: s.cfg.ActionDim}
+	switch {
+	case contract.Dim == 0:
+		contract.Dim = dim
+	case dim != 0 && dim != contract.Dim:
+		return contract, fmt.Errorf("action_dim is %d but the model returns %d values per action", contract.Dim, dim)
+	}
+	if len(s.cfg.ActionBounds) > 1 && contract.Dim != 0 && len(s.cfg.ActionBounds) != contract.Dim {
+		return contract, fmt.Errorf("action_bounds has %d entries but actions have %d values", len(s.cfg.ActionBounds), contract.Dim)
+	}
+	for _, b := range s.cfg.ActionBounds {
+		contract.Bounds = append(contract.Bounds, action.Bound{Min: b[0], Max: b[1]})
+	}
+	return contract, nil
+}
+
+// inputRanges returns the expected observation ranges configured for the model
+func (s *Server) inputRanges() []observation.RangeRule {
+	var rules []observation.RangeRule
+	for _, r := range s.cfg.InputRanges {
+		if r.Model != "" && r.Model != s.cfg.Model && r.Model != filepath.Base(s.cfg.Model) {
+			continue
+		}
+		// Layouts were checked by config validation; empty matches any size
+		var c, h, w uint32
+		if r.Layout != "" {
+			c, h, w, _ = observation.ParseLayout(r.Layout)
+		}
+		rules = append(rules, observation.RangeRule{
+			Channels: c,
+			Height:   h,
+			Width:    w,
+			Range: observation.Range{
+				Min:       r.Min,
+				Max:       r.Max,
+				Tolerance: r.Tolerance,
+				Clamp:     r.Action == "clamp",
+			},
+		})
+	}
+	return rules
+}
+
+// preprocessRules returns the preprocessing pipelines configured for the model
+func (s *Server) preprocessRules() []preprocess.Rule {
+	var rules []preprocess.Rule
+	for _, p := range s.cfg.Preprocess {
+		if p.Model != "" && p.Model != s.cfg.Model && p.Model != filepath.Base(s.cfg.Model) {
+			continue
+		}
+		// Layouts and steps were checked by config validation
+		var c, h, w uint32
+		if p.Layout != "" {
+			c, h, w, _ = observation.ParseLayout(p.Layout)
+		}
+		rule := preprocess.Rule{Channels: c, Height: h, Width: w}
+		for _, step := range p.Steps {
+			switch step.Op {
+			case "normalize":
+				rule.Pipeline = append(rule.Pipeline, preprocess.Normalize{Mean: step.Mean, Std: step.Std})
+			case "resize":
+				rule.Pipeline = append(rule.Pipeline, preprocess.Resize{Height: step.Height, Width: step.Width})
+			case "channel_order":
+				rule.Pipeline = append(rule.Pipeline, preprocess.ChannelOrder{Order: step.Order})
+			case "crop":
+				rule.Pipeline = append(rule.Pipeline, preprocess.Crop{Y: step.Y, X: step.X, Height: step.Height, Width: step.Width, Center: step.Center})
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// exportThresholds exports the served model's alerting thresholds as gauges.
+// Each threshold comes from the first model_thresholds entry matching the model
+// that sets it; max_divergence defaults to rollout_max_divergence during a
+// rollout.
+func (s *Server) exportThresholds() {
+	var maxLatency time.Duration
+	var maxDivergence float64
+	for _, t := range s.cfg.ModelThresholds {
+		if t.Model != "" && t.Model != s.cfg.Model && t.Model != filepath.Base(s.cfg.Model) {
+			continue
+		}
+		if maxLatency == 0 {
+			maxLatency = t.MaxLatency
+		}
+		if maxDivergence == 0 {
+			maxDivergence = t.MaxDivergence
+		}
+	}
+	if maxDivergence == 0 && s.cfg.RolloutEnabled {
+		maxDivergence = s.cfg.RolloutMaxDivergence
+	}
+
+	model := filepath.Base(s.cfg.Model)
+	if maxLatency > 0 {
+		metrics.SetModelAlertThreshold(model, "max_latency_seconds", maxLatency.Seconds())
+	}
+	if maxDivergence > 0 {
+		metrics.SetModelAlertThreshold(model, "max_divergence", maxDivergence)
+	}
+}
+
+// setupRollout wraps the engine in a rollout router whose state is persisted in Redis
+func (s *Server) setupRollout() error {
+	var store rollout.Store
+	if s.cache != nil {
+		store = rollout.RedisStore{Cache: s.cache, Key: s.cfg.RolloutStateKey}
+	} else {
+		slog.Warn("Redis unavailable; rollout state will not survive restarts")
+		store = &rollout.MemoryStore{}
+	}
+
+	engine := rollout.NewEngine(s.infer)
+	controller, err := rollout.NewController(engine, store, func(path string) (inference.InferenceEngine, error) {
+		version, _ := modelVersion(path)
+		engine, err := s.openEngine(path)
+		if err == nil {
+			s.rolloutVersions.Store(path, version)
+		}
+		return engine, err
+	}, rollout.Thresholds{
+		MaxErrorRate:  s.cfg.RolloutMaxErrorRate,
+		MaxDivergence: s.cfg.RolloutMaxDivergence,
+		MinSamples:    int64(s.cfg.RolloutMinSamples),
+		StageDuration: s.cfg.RolloutStageDuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.infer = engine
+	s.rollout = controller
+	controller.Register(s.admin)
+	slog.Info("Model rollout enabled", "stage", controller.State().Stage)
+	return nil
+}
+
+// authChain builds the configured authentication providers, or returns nil
+// when authentication is disabled
+func (s *Server) authChain() (*auth.Chain, error) {
+	cfg := s.cfg
+	if len(cfg.AuthProviders) == 0 {
+		return nil, nil
+	}
+
+	var providers []auth.Provider
+	for _, name := range cfg.AuthProviders {
+		switch name {
+		case "mtls":
+			providers = append(providers, auth.MTLSProvider{})
+		case "spiffe":
+			roles := make([]auth.SPIFFERole, len(cfg.AuthSPIFFERoles))
+			for i, r := range cfg.AuthSPIFFERoles {
+				roles[i] = auth.SPIFFERole{ID: r.ID, Roles: r.Roles}
+			}
+			p, err := auth.NewSPIFFEProvider(cfg.SPIFFETrustDomain, roles)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, p)
+		case "jwt":
+			p, err := auth.NewJWTProvider(auth.JWTConfig{
+				PublicKeyFile: cfg.AuthJWTPublicKey,
+				JWKSURL:       cfg.AuthJWTJWKSURL,
+				JWKSRefresh:   cfg.AuthJWTJWKSRefresh,
+				Issuer:        cfg.AuthJWTIssuer,
+				Audience:      cfg.AuthJWTAudience,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to set up JWT authentication: %w", err)
+			}
+			providers = append(providers, p)
+		case "api_key":
+			p, err := auth.NewAPIKeyProvider(cfg.AuthAPIKeys)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, p)
+		case "robot_token":
+			providers = append(providers, s.robotTokens)
+		}
+	}
+	slog.Info("Authentication enabled", "providers", cfg.AuthProviders)
+	chain := auth.NewChain(providers, healthpb.Health_Check_FullMethodName, healthpb.Health_Watch_FullMethodName)
+	return chain.WithTenants(auth.Tenants{Claim: cfg.AuthTenantClaim, Subjects: cfg.AuthTenants}), nil
+}
+
+// rateLimiters builds the per-peer limiter run before authentication, nil
+// unless rate_limit_peer_rps is set, and the per-client limiter run after it,
+// both nil when rate limiting is disabled. Health checks are never limited.
+func (s *Server) rateLimiters() (*ratelimit.Limiter, *ratelimit.Limiter) {
+	cfg := s.cfg
+	if !cfg.RateLimitEnabled {
+		return nil, nil
+	}
+
+	exempt := func() map[string]ratelimit.Limit {
+		return map[string]ratelimit.Limit{
+			healthpb.Health_Check_FullMethodName: {},
+			healthpb.Health_Watch_FullMethodName: {},
+		}
+	}
+	var peer *ratelimit.Limiter
+	if cfg.RateLimitPeerRPS > 0 {
+		peer = ratelimit.NewPerPeer(ratelimit.Limit{RPS: cfg.RateLimitPeerRPS, Burst: cfg.RateLimitPeerBurst}, exempt())
+	}
+	methods := exempt()
+	for _, m := range cfg.RateLimitMethods {
+		methods[m.Method] = ratelimit.Limit{RPS: m.RPS, Burst: m.Burst}
+	}
+	slog.Info("Rate limiting enabled",
+		"rps", cfg.RateLimitRPS, "burst", cfg.RateLimitBurst, "method_overrides", len(cfg.RateLimitMethods), "peer_rps", cfg.RateLimitPeerRPS)
+	return peer, ratelimit.New(ratelimit.Limit{RPS: cfg.RateLimitRPS, Burst: cfg.RateLimitBurst}, methods)
+}
+
+// downsampling returns the handler option downsampling observations while the
+// service is degraded, or nil if the model cannot take smaller observations
+func (s *Server) downsampling() handler.Option {
+	info, ok := inference.Describe(s.infer)
+	if ok && len(info.InputShape) == 4 && (info.InputShape[2] > 0 || info.InputShape[3] > 0) {
+		slog.Warn("Model input has a fixed height or width; degraded-mode downsampling disabled", "input_shape", info.InputShape)
+		return nil
+	}
+	slog.Info("Degraded-mode downsampling enabled", "factor", s.cfg.DownsampleFactor, "min_size", s.cfg.DownsampleMinSize)
+	return handler.WithDownsampling(int64(s.cfg.DownsampleFactor), int64(s.cfg.DownsampleMinSize), s.degraded)
+}
+
+// validityRules returns the action_validity_rules that apply to the configured model
+func (s *Server) validityRules() []handler.ValidityRule {
+	var rules []handler.ValidityRule
+	for _, r := range s.cfg.ActionValidityRules {
+		if r.Model != "" && r.Model != s.cfg.Model && r.Model != filepath.Base(s.cfg.Model) {
+			continue
+		}
+		rules = append(rules, handler.ValidityRule{Client: r.Client, Validity: r.Validity})
+	}
+	return rules
+}
+
+// valueCache returns the cache for poses and plans: Redis, else the in-process
+// cache used when Redis was unreachable at startup, else nil
+func (s *Server) valueCache() *cache.Cache {
+	if s.cache != nil {
+		return s.cache
+	}
+	return s.localCache
+}
+
+// stageTimeout returns the named stage's timeout: adaptive between floor and
+// ceiling when adaptive timeouts are enabled, else static, or nil when the
+// stage has no timeout
+func (s *Server) stageTimeout(name string, timeout, floor, ceiling time.Duration) *timeouts.Stage {
+	cfg := s.cfg
+	if !cfg.AdaptiveTimeoutsEnabled {
+		if timeout <= 0 {
+			return nil
+		}
+		slog.Info("Stage timeout enabled", "stage", name, "timeout", timeout)
+		return timeouts.Static(name, timeout)
+	}
+	if timeout <= 0 {
+		timeout = ceiling
+	}
+	slog.Info("Adaptive stage timeout enabled",
+		"stage", name, "initial", timeout, "floor", floor, "ceiling", ceiling,
+		"percentile", cfg.AdaptiveTimeoutPercentile, "multiplier", cfg.AdaptiveTimeoutMultiplier, "window", cfg.AdaptiveTimeoutWindow)
+	return timeouts.Adaptive(name, timeout, timeouts.Tuning{
+		Window:     cfg.AdaptiveTimeoutWindow,
+		Percentile: cfg.AdaptiveTimeoutPercentile,
+		Multiplier: cfg.AdaptiveTimeoutMultiplier,
+		Floor:      floor,
+		Ceiling:    ceiling,
+	})
+}
+
+// dedupWindow returns the handler option answering repeated observations from
+// the dedup window, or nil if actions depend on more than the observation
+func (s *Server) dedupWindow(enricher *enrich.Enricher) handler.Option {
+	if enricher != nil {
+		slog.Warn("Observations are enriched with per-robot features; observation dedup disabled")
+		return nil
+	}
+	if s.cfg.PoseMode == handler.PoseObservation {
+		slog.Warn("Observations are extended with cached robot poses; observation dedup disabled")
+		return nil
+	}
+	slog.Info("Observation dedup enabled", "window", s.cfg.DedupWindow)
+	window := dedup.New(s.cfg.DedupWindow)
+	// Robots on reaped streams start afresh when they reconnect
+	s.streams.OnReap(window.Forget)
+	return handler.WithDedup(window)
+}
+
+// planCache returns the handler option sharing plans through Redis, or nil if
+// no cache is available or actions depend on more than the observation
+func (s *Server) planCache(enricher *enrich.Enricher) handler.Option {
+	c := s.valueCache()
+	if c == nil {
+		slog.Warn("Redis unavailable; plan cache disabled")
+		return nil
+	}
+	if enricher != nil {
+		slog.Warn("Observations are enriched with per-robot features; plan cache disabled")
+		return nil
+	}
+	if s.cfg.PoseMode == handler.PoseObservation {
+		slog.Warn("Observations are extended with cached robot poses; plan cache disabled")
+		return nil
+	}
+	if c == s.localCache {
+		slog.Info("Plan cache enabled in process only", "ttl", s.cfg.PlanCacheTTL)
+	} else {
+		slog.Info("Plan cache enabled", "ttl", s.cfg.PlanCacheTTL)
+	}
+	return handler.WithPlanCache(plancache.New(plancache.RedisStore{Cache: c}, s.servingVersion, s.cfg.PlanCacheTTL))
+}
+
+// servingVersion returns the content version of the model serving traffic,
+// which changes with every reload, rollback, and promotion. It returns "",
+// turning the plan cache off, while a rollout splits traffic between the
+// primary and a candidate, so each model's requests are answered and compared
+// by that model.
+func (s *Server) servingVersion() string {
+	if s.rollout != nil {
+		state := s.rollout.State()
+		if state.Stage.Active() {
+			return ""
+		}
+		if state.Primary != "" {
+			version, _ := s.rolloutVersions.Load(state.Primary)
+			v, _ := version.(string)
+			return v
+		}
+	}
+	return s.model.Version()
+}
+
+// degraded reports whether the service is shedding bulk traffic, its
+// inference queue is overloaded, or the model is running on a fallback
+// execution provider
+func (s *Server) degraded() bool {
+	if s.shedder != nil && s.shedder.Shedding() {
+		return true
+	}
+	if s.admission != nil && s.admission.QueueOverloaded() {
+		return true
+	}
+	if s.breaker != nil && s.breaker.Open() {
+		return true
+	}
+	info, ok := inference.Describe(s.infer)
+	return ok && info.Fallback()
+}
+
+// setupShedding creates the error-budget shedder and its admin status endpoint
+func (s *Server) setupShedding() {
+	cfg := s.cfg
+	s.shedder = shedding.New(shedding.Policy{
+		SLOTarget:   cfg.SheddingSLOTarget,
+		ShortWindow: cfg.SheddingShortWindow,
+		LongWindow:  cfg.SheddingLongWindow,
+		BurnRate:    cfg.SheddingBurnRate,
+		MinRequests: cfg.SheddingMinRequests,
+	})
+	s.shedder.Register(s.admin)
+	slog.Info("Load shedding enabled",
+		"slo", cfg.SheddingSLOTarget, "short_window", cfg.SheddingShortWindow, "long_window", cfg.SheddingLongWindow, "burn_rate", cfg.SheddingBurnRate)
+}
+
+// setupAdmission creates the admission controller and its admin status endpoint
+func (s *Server) setupAdmission() {
+	cfg := s.cfg
+	s.admission = admission.New(admission.Limits{
+		MaxInFlight:     cfg.AdmissionMaxInFlight,
+		MaxQueueLatency: cfg.AdmissionMaxQueueLatency,
+		Interval:        cfg.AdmissionInterval,
+	})
+	s.admission.Register(s.admin)
+	slog.Info("Admission control enabled",
+		"max_in_flight", cfg.AdmissionMaxInFlight, "max_queue_latency", cfg.AdmissionMaxQueueLatency, "interval", cfg.AdmissionInterval)
+}
+
+// setupJournal opens the request journal and its admin export endpoint
+func (s *Server) setupJournal() error {
+	cfg := s.cfg
+	j, err := journal.New(cfg.JournalDir, cfg.JournalRetention, cfg.JournalMaxBytes, cfg.JournalBufferSize)
+	if err != nil {
+		return fmt.Errorf("failed to open request journal: %w", err)
+	}
+	s.journal = j
+	j.Register(s.admin)
+	slog.Info("Request journal enabled", "dir", cfg.JournalDir, "retention", cfg.JournalRetention, "max_bytes", cfg.JournalMaxBytes)
+	return nil
+}
+
+// setupPlanSchedules creates the runner planning the configured robot groups
+// at their epochs
+func (s *Server) setupPlanSchedules() error {
+	if s.cache == nil {
+		return fmt.Errorf("plan_schedules requires the Redis cache")
+	}
+	groups := make([]epoch.Group, len(s.cfg.PlanSchedules))
+	for i, sc := range s.cfg.PlanSchedules {
+		mode := sc.Mode
+		if mode == "" {
+			mode = publish.ModeChannel
+		}
+		groups[i] = epoch.Group{
+			Name:     sc.Name,
+			Tenant:   sc.Tenant,
+			RobotIDs: sc.RobotIDs,
+			Interval: sc.Interval,
+			Sink:     publish.RedisSink{Cache: s.cache, Mode: mode, Target: sc.Target, MaxLen: sc.StreamMaxLen},
+		}
+		slog.Info("Plan schedule enabled",
+			"schedule", sc.Name, "tenant", sc.Tenant, "robots", len(sc.RobotIDs), "interval", sc.Interval, "mode", mode, "target", sc.Target)
+	}
+	s.epochs = epoch.New(s.handler, epoch.CacheSource{Cache: s.cache}, s.cache, groups)
+	return nil
+}
+
+// setupRecorder creates the episode recorder and, if enabled, the compactor
+// moving its stream to S3
+func (s *Server) setupRecorder() error {
+	cfg := s.cfg
+	if s.cache == nil {
+		return fmt.Errorf("recorder_enabled requires the Redis cache")
+	}
+
+	if cfg.RecorderCompactionEnabled {
+		store, err := recorder.NewS3Store(cfg.RecorderS3Endpoint, cfg.RecorderS3Region, cfg.RecorderS3Bucket)
+		if err != nil {
+			return err
+		}
+		s.compactor = recorder.NewCompactor(s.cache, cfg.RecorderStream, store, cfg.RecorderS3Prefix, cfg.RecorderCompactionInterval)
+		slog.Info("Recorder compaction enabled",
+			"bucket", cfg.RecorderS3Bucket, "prefix", cfg.RecorderS3Prefix, "interval", cfg.RecorderCompactionInterval)
+	}
+
+	sink := recorder.RedisStreamSink{Cache: s.cache, Stream: cfg.RecorderStream, MaxLen: cfg.RecorderStreamMaxLen}
+	s.recorder = recorder.New(sink, cfg.Model, cfg.RecorderBufferSize, cfg.RecorderDedupWindow)
+	slog.Info("Episode recorder enabled", "stream", cfg.RecorderStream)
+	return nil
+}
+
+// GRPCServer returns the underlying gRPC server so embedders can register
+// additional services before calling Run
+func (s *Server) GRPCServer() *grpc.Server {
+	return s.grpcServer
+}
+
+// Run serves gRPC and HTTP until ctx is done, then drains and shuts down
+// gracefully. Resources created by New (engine, cache, tracer) are released
+// before Run returns.
+func (s *Server) Run(ctx context.Context) error {
+	defer s.closeOwned()
+
+	cfg := s.cfg
+
+	// Initialize OpenTelemetry tracer
+	if cfg.OTELEnabled {
+		tracerShutdown, err := initTracer(cfg.OTELEndpoint, newSampler(cfg.OTELSampleRatio, cfg.OTELSampleRobotIDs))
+		if err != nil {
+			slog.Warn("Failed to initialize tracer", "error", err)
+		} else {
+			slog.Info("OpenTelemetry tracing enabled",
+				"endpoint", cfg.OTELEndpoint, "sample_ratio", cfg.OTELSampleRatio, "sampled_robots", cfg.OTELSampleRobotIDs)
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				tracerShutdown(shutdownCtx)
+			}()
+		}
+	}
+
+	// Start listening
+	lis := s.listener
+	if lis == nil {
+		addr := fmt.Sprintf(":%d", cfg.Port)
+		var err error
+		lis, err = net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+	}
+
+	// Report NOT_SERVING until the model is warm
+	s.setServing(false)
+
+	// Start HTTP server for metrics and health checks
+	httpServer, metricsAddr, httpErr := startHTTPServer(cfg.MetricsPort, s.healthServer, s.httpHandlers)
+	if httpErr != nil {
+		lis.Close()
+		return httpErr
+	}
+	s.addrs.Store(&ListenAddrs{GRPC: lis.Addr().String(), Metrics: metricsAddr.String()})
+
+	// Background tasks stop with the server
+	bgCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+
+	if s.signer != nil && cfg.SigningReloadInterval > 0 {
+		go s.reloadSigningKeys(bgCtx, cfg.SigningReloadInterval)
+	}
+	if s.robotTokens != nil && cfg.RobotTokenReloadInterval > 0 {
+		go s.reloadRobotTokenKeys(bgCtx, cfg.RobotTokenReloadInterval)
+	}
+
+	if cfg.WarmupInferences > 0 {
+		if err := warmup(s.infer, cfg.WarmupInferences, cfg.WarmupBatchSizes); err != nil {
+			slog.Warn("Model warmup failed", "error", err)
+		}
+	}
+
+	// Pick up the per-robot state the previous process left behind
+	if s.snapshots != nil {
+		if taken, err := s.snapshots.Restore(); err != nil {
+			slog.Warn("Failed to restore per-robot state", "error", err)
+		} else if !taken.IsZero() {
+			slog.Info("Restored per-robot state", "key", s.snapshots.Key(), "age", time.Since(taken).Round(time.Millisecond))
+		}
+		go s.snapshots.Run(bgCtx)
+	}
+
+	// Set health status to serving
+	s.setServing(true)
+
+	if s.rollout != nil {
+		go s.rollout.Run(bgCtx, cfg.RolloutInterval)
+	}
+
+	if s.telemetry != nil {
+		go s.telemetry.Run(bgCtx)
+	}
+
+	go s.handler.RunAsync(bgCtx)
+	go s.handler.RunPlanUpdates(bgCtx)
+
+	if s.compactor != nil {
+		go s.compactor.Run(bgCtx)
+	}
+	if s.epochs != nil {
+		go s.epochs.Run(bgCtx)
+	}
+	if s.placement != nil {
+		go s.placement.Run(bgCtx)
+	}
+
+	if s.shedder != nil {
+		go s.shedder.Run(bgCtx, cfg.SheddingEvalInterval)
+	}
+
+	if cfg.StreamIdleTimeout > 0 {
+		slog.Info("Idle stream reaping enabled", "timeout", cfg.StreamIdleTimeout)
+		go s.streams.Run(bgCtx, max(cfg.StreamIdleTimeout/4, time.Second))
+	}
+
+	if s.model != nil && cfg.ModelWatch && !cfg.UseMockInference {
+		go s.watchModel(bgCtx, cfg.ModelWatchSettle)
+	}
+
+	if len(cfg.ORTDevices) > 0 && cfg.ORTDeviceMemoryInterval > 0 && !cfg.UseMockInference {
+		go sampleDeviceMemory(bgCtx, cfg.ORTDeviceMemoryInterval)
+	}
+
+	// Watch for goroutine leaks and a wedged inference pipeline
+	if cfg.WatchdogEnabled {
+		wd := watchdog.New(watchdog.Config{
+			Interval:        cfg.WatchdogInterval,
+			GoroutineWindow: cfg.WatchdogGoroutineWindow,
+			GoroutineGrowth: cfg.WatchdogGoroutineGrowth,
+			StallTimeout:    cfg.WatchdogStallTimeout,
+		}, s.progress, func(healthy bool) {
+			if !cfg.WatchdogFailHealth || bgCtx.Err() != nil {
+				return
+			}
+			slog.Info("Watchdog changing serving status", "serving", healthy)
+			s.setServing(healthy)
+		})
+		go wd.Run(bgCtx)
+		slog.Info("Watchdog enabled",
+			"interval", cfg.WatchdogInterval, "stall_timeout", cfg.WatchdogStallTimeout, "fail_health", cfg.WatchdogFailHealth)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.grpcServer.Serve(lis)
+	}()
+
+	slog.Info("gRPC server listening", "address", lis.Addr().String())
+
+	if cfg.CanaryEnabled {
+		if err := s.startCanary(bgCtx, lis.Addr()); err != nil {
+			slog.Warn("Canary disabled", "error", err)
+		}
+	}
+	slog.Info("Service is ready to accept requests", "service", ServiceName)
+
+	var err error
+	select {
+	case err = <-serveErr:
+		if err != nil {
+			err = fmt.Errorf("failed to serve: %w", err)
+		}
+		stopBackground()
+		s.setServing(false)
+
+		// Let handlers already running finish before the engine is closed
+		s.grpcServer.GracefulStop()
+	case <-ctx.Done():
+		slog.Info("Shutting down gracefully")
+
+		// Keep the watchdog from flipping health back to serving
+		stopBackground()
+
+		// Set health to not serving
+		s.setServing(false)
+
+		// Give time for load balancers to detect unhealthy status
+		time.Sleep(s.drainDelay)
+
+		// Shutdown gRPC server
+		s.grpcServer.GracefulStop()
+	}
+
+	// Save the final state once no handler can change it
+	if s.snapshots != nil {
+		if err := s.snapshots.Save(); err != nil {
+			slog.Warn("Failed to save per-robot state", "error", err)
+		}
+	}
+
+	// Shutdown HTTP server so no admin call (e.g. a rollout start) races the
+	// engine being closed by the deferred closeOwned
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	httpServer.Shutdown(shutdownCtx)
+
+	slog.Info("Server shutdown complete")
+	return err
+}
+
+// warmup runs synthetic inferences through engine, logging their latency
+func warmup(engine inference.InferenceEngine, n int, batchSizes []int) error {
+	start := time.Now()
+	results, err := inference.Warmup(engine, n, batchSizes)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		slog.Info("Warmed up batch",
+			"batch_size", r.BatchSize, "inferences", r.Inferences, "first", r.First.Round(time.Microsecond), "last", r.Last.Round(time.Microsecond))
+	}
+	slog.Info("Model warmup finished", "elapsed", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// sampleDeviceMemory exports GPU memory usage every interval until ctx is done
+func sampleDeviceMemory(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		used, err := inference.DeviceMemoryUsed(ctx)
+		if err != nil {
+			slog.Warn("GPU memory metrics disabled", "error", err)
+			return
+		}
+		for device, bytes := range used {
+			metrics.SetDeviceMemoryUsed(strconv.Itoa(device), float64(bytes))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setServing updates the gRPC health service and health metric
+func (s *Server) setServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+		metrics.SetHealthy()
+	} else {
+		metrics.SetUnhealthy()
+	}
+	s.healthServer.SetServingStatus(ServiceName, status)
+	s.healthServer.SetServingStatus("", status) // Overall health
+}
+
+// serving reports whether the health service currently reports SERVING
+func (s *Server) serving() bool {
+	resp, err := s.healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	return err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING
+}
+
+// reloadSigningKeys periodically reloads keys so rotated keys take effect without a restart
+func (s *Server) reloadSigningKeys(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		previous := s.signer.ActiveKeyID()
+		if err := s.signer.Reload(); err != nil {
+			slog.Warn("Failed to reload signing keys", "error", err)
+		} else if active := s.signer.ActiveKeyID(); active != previous {
+			slog.Info("Rotated signing key", "previous", previous, "active", active)
+		}
+	}
+}
+
+// reloadRobotTokenKeys periodically reloads robot token keys; tokens signed
+// with a retired key stay valid while the key is still in the directory
+func (s *Server) reloadRobotTokenKeys(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		previous := s.robotTokens.ActiveKeyID()
+		if err := s.robotTokens.Reload(); err != nil {
+			slog.Warn("Failed to reload robot token keys", "error", err)
+		} else if active := s.robotTokens.ActiveKeyID(); active != previous {
+			slog.Info("Rotated robot token key", "previous", previous, "active", active)
+		}
+	}
+}
+
+// closeOwned releases the engine, cache, and metrics backend if New created
+// them. Run calls it only after gRPC and HTTP have stopped; the engine still
+// rejects stray Predicts and waits for in-flight runs before it is destroyed.
+func (s *Server) closeOwned() {
+	if s.ownsEngine && s.infer != nil {
+		if err := s.infer.Close(); err != nil {
+			slog.Warn("Failed to close inference engine", "error", err)
+		}
+	} else if router, ok := s.infer.(*rollout.Engine); ok {
+		// Candidates loaded by the rollout controller belong to the server
+		router.ClearCandidate()
+	}
+	s.closeModelVersions()
+	// Before the cache, so queued entries are still written
+	if s.recorder != nil {
+		s.recorder.Close()
+	}
+	if s.publisher != nil {
+		s.publisher.Close()
+	}
+	if s.journal != nil {
+		s.journal.Close()
+	}
+	if s.upstream != nil {
+		s.upstream.Close()
+	}
+	if s.ownsCache && s.cache != nil {
+		s.cache.Close()
+	}
+	// Last, so metrics recorded while closing are still flushed
+	if s.statsd != nil {
+		metrics.SetBackend(nil)
+		s.statsd.Close()
+	}
+}
@@ -0,0 +1,472 @@
+// server/server_test.go
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/SyedDaiam9101/policy-service/internal/config"
+	"github.com/SyedDaiam9101/policy-service/internal/handler"
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/metrics"
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+func TestServer_EmbeddedRun(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	intercepted := false
+	engine := inference.NewMockWithAction([]float32{1, 2})
+	srv, err := New(&Config{ActionMode: "continuous"},
+		WithEngine(engine),
+		WithListener(lis),
+		WithDrainDelay(0),
+		WithUnaryInterceptors(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			intercepted = true
+			return handler(ctx, req)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Run(ctx)
+	}()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	callCtx, callCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer callCancel()
+
+	health, err := healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if health.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Expected SERVING, got %v", health.Status)
+	}
+
+	resp, err := pb.NewPathPlannerClient(conn).Plan(callCtx, &pb.PlanRequest{
+		RobotId: 1,
+		Obs:     &pb.Observation{Data: []float32{0, 0, 0, 0}, Channels: 1, Height: 2, Width: 2},
+	})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(resp.Action) != 2 || resp.Action[0] != 1 || resp.Action[1] != 2 {
+		t.Errorf("Expected action [1 2], got %v", resp.Action)
+	}
+	if !intercepted {
+		t.Error("Expected extra interceptor to run")
+	}
+	// metrics_port 0 picks a free port
+	addrs, ok := srv.Addrs()
+	if !ok || strings.HasSuffix(addrs.Metrics, ":0") || addrs.GRPC != lis.Addr().String() {
+		t.Errorf("Expected the bound gRPC and metrics addresses, got %+v (ok=%v)", addrs, ok)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	// The caller owns engines passed through WithEngine
+	if engine.CallCount != 1 {
+		t.Errorf("Expected 1 Predict call, got %d", engine.CallCount)
+	}
+}
+
+// testCert is a certificate and key issued by a test CA
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// issueCert creates a certificate for tmpl signed by parent, or self-signed
+// when parent is nil
+func issueCert(t *testing.T, tmpl *x509.Certificate, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore = time.Now().Add(-time.Minute)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// writePEM writes c's certificate and key to dir as <name>.crt and <name>.key
+func (c *testCert) writePEM(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+	keyDER, _ := x509.MarshalECPrivateKey(c.key)
+	certPath, keyPath := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return certPath, keyPath
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	caPath, _ := ca.writePEM(t, dir, "ca")
+	serverCert := issueCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "policy-service"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	certPath, keyPath := serverCert.writePEM(t, dir, "server")
+	clientCert := issueCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "robot-7"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv, err := New(&Config{ActionMode: "continuous", TLSCert: certPath, TLSKey: keyPath, TLSClientCA: caPath},
+		WithEngine(inference.NewMock()), WithListener(lis), WithDrainDelay(0))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	check := func(creds credentials.TransportCredentials) error {
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer conn.Close()
+		callCtx, callCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer callCancel()
+		_, err = healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+	withCert := func(certs ...tls.Certificate) credentials.TransportCredentials {
+		return credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: certs, MinVersion: tls.VersionTLS12})
+	}
+
+	client := tls.Certificate{Certificate: [][]byte{clientCert.der}, PrivateKey: clientCert.key}
+	if err := check(withCert(client)); err != nil {
+		t.Fatalf("Expected a client with a certificate from tls_client_ca to connect, got %v", err)
+	}
+	if err := check(withCert()); err == nil {
+		t.Fatal("Expected a client without a certificate to be rejected")
+	}
+	if err := check(insecure.NewCredentials()); err == nil {
+		t.Fatal("Expected a plaintext client to be rejected")
+	}
+}
+
+func TestServer_MetricsPortTaken(t *testing.T) {
+	taken, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer taken.Close()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	srv, err := New(&Config{ActionMode: "continuous", MetricsPort: taken.Addr().(*net.TCPAddr).Port},
+		WithEngine(inference.NewMock()), WithListener(lis), WithDrainDelay(0))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Run(context.Background())
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "metrics_port") {
+			t.Fatalf("Expected an error naming metrics_port, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Run to fail when the metrics port is taken")
+	}
+}
+
+func TestSampler_AlwaysSamplesListedRobots(t *testing.T) {
+	sampler := newSampler(0, []uint64{42})
+
+	sample := func(robotIDs ...uint64) sdktrace.SamplingDecision {
+		var ctx context.Context
+		interceptor := middleware.UnaryRobotIDInterceptor()
+		req := &pb.BatchPlanRequest{}
+		for _, id := range robotIDs {
+			req.Requests = append(req.Requests, &pb.PlanRequest{RobotId: id})
+		}
+		interceptor(context.Background(), req, &grpc.UnaryServerInfo{}, func(c context.Context, req interface{}) (interface{}, error) {
+			ctx = c
+			return nil, nil
+		})
+		return sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: ctx, TraceID: trace.TraceID{1}}).Decision
+	}
+
+	if got := sample(7, 42); got != sdktrace.RecordAndSample {
+		t.Errorf("Expected batch with a listed robot to be sampled, got %v", got)
+	}
+	if got := sample(7, 8); got != sdktrace.Drop {
+		t.Errorf("Expected other robots to follow the fleet-wide ratio, got %v", got)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	received := make(chan otlpTraceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != otlpTracesPath || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON POST to %s, got %s %s", otlpTracesPath, r.Header.Get("Content-Type"), r.URL.Path)
+		}
+		var req otlpTraceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode export request: %v", err)
+		}
+		received <- req
+	}))
+	defer collector.Close()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(newOTLPExporter(collector.URL)))
+	defer tp.Shutdown(context.Background())
+	_, span := tp.Tracer("test").Start(context.Background(), "Plan", trace.WithAttributes(attribute.Int64("robot.id", 42)))
+	span.End()
+
+	req := <-received
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Expected one resource and scope, got %+v", req)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 || spans[0].Name != "Plan" {
+		t.Fatalf("Expected the Plan span, got %+v", spans)
+	}
+	if got, want := spans[0].TraceID, span.SpanContext().TraceID().String(); got != want {
+		t.Errorf("Expected trace ID %s, got %s", want, got)
+	}
+	if attrs := spans[0].Attributes; len(attrs) != 1 || attrs[0].Value.IntValue == nil || *attrs[0].Value.IntValue != "42" {
+		t.Errorf("Expected robot.id attribute 42, got %+v", attrs)
+	}
+}
+
+func TestServer_ReloadModel(t *testing.T) {
+	srv, err := New(&Config{UseMockInference: true, ActionMode: "continuous"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer srv.closeOwned()
+
+	before := srv.model.Unwrap()
+	rec := httptest.NewRecorder()
+	srv.admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/model/reload", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if srv.model.Unwrap() == before {
+		t.Error("Expected a new engine after reload")
+	}
+}
+
+func TestServer_RollbackModel(t *testing.T) {
+	srv, err := New(&Config{UseMockInference: true, ActionMode: "continuous", ModelKeepPrevious: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer srv.closeOwned()
+
+	rollback := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/model/rollback", nil))
+		return rec
+	}
+	if rec := rollback(); rec.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 with no previous model, got %d: %s", rec.Code, rec.Body)
+	}
+
+	before := srv.model.Unwrap()
+	if err := srv.reloadModel("test"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if rec := rollback(); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if srv.model.Unwrap() != before {
+		t.Error("Expected the previous engine to serve again after rollback")
+	}
+}
+
+func TestReplayConfig(t *testing.T) {
+	cfg := &Config{
+		ReplayMode:       true,
+		ActionMode:       "sample",
+		DedupEnabled:     true,
+		PlanCacheEnabled: true,
+		BatchingEnabled:  true,
+		RolloutEnabled:   true,
+		UpstreamAddress:  "planner.example.com:50051",
+		PoseMode:         handler.PoseResponse,
+	}
+	replay, err := replayConfig(cfg)
+	if err != nil {
+		t.Fatalf("replayConfig failed: %v", err)
+	}
+	if replay.ActionMode != "argmax" || replay.DedupEnabled || replay.PlanCacheEnabled || replay.BatchingEnabled || replay.RolloutEnabled || replay.UpstreamAddress != "" {
+		t.Errorf("Expected nondeterministic stages off, got action_mode=%s dedup=%t plan_cache=%t batching=%t rollout=%t upstream=%q",
+			replay.ActionMode, replay.DedupEnabled, replay.PlanCacheEnabled, replay.BatchingEnabled, replay.RolloutEnabled, replay.UpstreamAddress)
+	}
+	if replay.PoseMode != handler.PoseResponse {
+		t.Errorf("Expected poses still returned in responses, got pose_mode=%q", replay.PoseMode)
+	}
+	if cfg.ActionMode != "sample" || !cfg.BatchingEnabled || cfg.UpstreamAddress == "" {
+		t.Error("Expected the original config to be left untouched")
+	}
+
+	// Stages that change the model's input cannot be turned off, so they are refused
+	for name, refused := range map[string]*Config{
+		"pose_mode observation": {ReplayMode: true, PoseMode: handler.PoseObservation},
+		"feast enrichment":      {ReplayMode: true, EnrichmentSource: "feast"},
+		"redis enrichment":      {ReplayMode: true, EnrichmentSource: "redis"},
+	} {
+		if _, err := replayConfig(refused); err == nil {
+			t.Errorf("Expected replay mode to refuse %s", name)
+		}
+		if _, err := New(refused); err == nil {
+			t.Errorf("Expected New to refuse replay mode with %s", name)
+		}
+	}
+}
+
+func TestServer_ExportThresholds(t *testing.T) {
+	s := &Server{cfg: &Config{
+		Model:                "/models/threshold_test.onnx",
+		RolloutEnabled:       true,
+		RolloutMaxDivergence: 0.2,
+		ModelThresholds: []config.ModelThreshold{
+			{Model: "other.onnx", MaxLatency: time.Second},
+			{Model: "threshold_test.onnx", MaxLatency: 40 * time.Millisecond},
+			{MaxLatency: 100 * time.Millisecond, MaxDivergence: 0.05},
+		},
+	}}
+	s.exportThresholds()
+
+	latency := testutil.ToFloat64(metrics.ModelAlertThreshold.WithLabelValues("threshold_test.onnx", "max_latency_seconds"))
+	if latency != 0.04 {
+		t.Errorf("Expected the model's own max latency 0.04, got %v", latency)
+	}
+	divergence := testutil.ToFloat64(metrics.ModelAlertThreshold.WithLabelValues("threshold_test.onnx", "max_divergence"))
+	if divergence != 0.05 {
+		t.Errorf("Expected max divergence 0.05 from the catch-all entry, got %v", divergence)
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfg.UseMockInference = true
+	cfg.Redis = ""
+
+	report := SelfTest(context.Background(), cfg)
+	if !report.Passed {
+		t.Fatalf("Expected self-test to pass, got %+v", report)
+	}
+	statuses := make(map[string]string)
+	for _, c := range report.Checks {
+		statuses[c.Name] = c.Status
+	}
+	want := map[string]string{"config": CheckPass, "tls": CheckSkip, "model": CheckPass, "canary_inference": CheckPass, "redis": CheckSkip}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("Expected %s to %s, got %q", name, status, statuses[name])
+		}
+	}
+
+	cfg.TLSCert, cfg.TLSKey = "missing.crt", "missing.key"
+	if report := SelfTest(context.Background(), cfg); report.Passed {
+		t.Fatalf("Expected missing TLS files to fail the self-test, got %+v", report)
+	}
+}
+
+func TestSessionCPUs(t *testing.T) {
+	cpus := []int{0, 1, 2, 3, 4, 5}
+	if got := sessionCPUs(cpus, 1, 3); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("Expected session 1 of 3 on cpus [2 3], got %v", got)
+	}
+	if got := sessionCPUs(cpus[:2], 1, 3); len(got) != 2 {
+		t.Errorf("Expected sessions to share cpus when there are fewer cpus than sessions, got %v", got)
+	}
+	if got := sessionCPUs(nil, 0, 2); got != nil {
+		t.Errorf("Expected no pinning without cpus, got %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+// server/tracing.go
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/SyedDaiam9101/policy-service/internal/middleware"
+)
+
+func initTracer(endpoint string, sampler sdktrace.Sampler) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	if endpoint != "" {
+		// Spans go to an OTLP/HTTP receiver such as the OTel collector
+		exporter = newOTLPExporter(endpoint)
+	} else {
+		stdout, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		}
+		exporter = stdout
+	}
+
+	// Create resource with service information
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion("1.0.0"),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	// Create tracer provider
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+
+	// Set global tracer provider
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// robotSampler always samples requests for robots under investigation and
+// defers to the fleet-wide sampler for everything else
+type robotSampler struct {
+	robots   map[uint64]bool
+	fallback sdktrace.Sampler
+}
+
+// newSampler samples ratio of traces (following the parent's decision when
+// there is one), plus every request that involves one of robotIDs
+func newSampler(ratio float64, robotIDs []uint64) sdktrace.Sampler {
+	fallback := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	if len(robotIDs) == 0 {
+		return fallback
+	}
+
+	robots := make(map[uint64]bool, len(robotIDs))
+	for _, id := range robotIDs {
+		robots[id] = true
+	}
+	return robotSampler{robots: robots, fallback: fallback}
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s robotSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	var matched []int64
+	for _, id := range middleware.GetRobotIDs(p.ParentContext) {
+		if s.robots[id] {
+			matched = append(matched, int64(id))
+		}
+	}
+	if len(matched) == 0 {
+		return s.fallback.ShouldSample(p)
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.RecordAndSample,
+		Attributes: []attribute.KeyValue{attribute.Int64Slice("robot.ids.sampled", matched)},
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler
+func (s robotSampler) Description() string {
+	return fmt.Sprintf("RobotSampler{robots=%d,fallback=%s}", len(s.robots), s.fallback.Description())
+}
@@ -0,0 +1,58 @@
+// server/upstream.go
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// dialUpstream connects to the upstream policy-service that calls this
+// instance cannot serve are proxied to
+func dialUpstream(cfg *Config) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.UpstreamTLS {
+		tlsConfig, err := upstreamTLS(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(cfg.UpstreamAddress, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial upstream %s: %w", cfg.UpstreamAddress, err)
+	}
+	return conn, nil
+}
+
+// upstreamTLS builds the TLS configuration for the upstream connection
+func upstreamTLS(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.UpstreamTLSServerName,
+	}
+	if cfg.UpstreamTLSCA != "" {
+		pem, err := os.ReadFile(cfg.UpstreamTLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read upstream_tls_ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.UpstreamTLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.UpstreamTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.UpstreamTLSCert, cfg.UpstreamTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load upstream client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,43 @@
+// server/versions.go
+package server
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+	"github.com/SyedDaiam9101/policy-service/internal/timeouts"
+)
+
+// loadModelVersions loads the older versions in model_versions, so requests
+// can pin one while the latest version serves the rest. Batches for each time
+// out like the latest version's when timeout is set.
+func (s *Server) loadModelVersions(timeout *timeouts.Stage) error {
+	versions := make(map[string]inference.InferenceEngine, len(s.cfg.ModelVersions))
+	for _, v := range s.cfg.ModelVersions {
+		slog.Info("Loading model version", "version", v.Version, "model", v.Model)
+		engine, err := s.openEngine(v.Model)
+		if err != nil {
+			for _, e := range versions {
+				e.Close()
+			}
+			return fmt.Errorf("failed to load model version %s: %w", v.Version, err)
+		}
+		if timeout != nil {
+			engine = inference.NewDeadline(engine, timeout)
+		}
+		versions[v.Version] = engine
+	}
+	s.versions = versions
+	slog.Info("Model versions enabled", "latest", s.cfg.ModelVersion, "pinnable", len(versions))
+	return nil
+}
+
+// closeModelVersions releases the engines loadModelVersions loaded
+func (s *Server) closeModelVersions() {
+	for version, engine := range s.versions {
+		if err := engine.Close(); err != nil {
+			slog.Warn("Failed to close model version", "version", version, "error", err)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// testenv/env.go
+
+// Package testenv connects integration tests to a running policy-service
+// environment, such as the one docker-compose.yaml in this directory brings
+// up: the service on a generated dummy model, Redis, Prometheus, and an
+// OpenTelemetry collector. Addresses default to the compose port mappings and
+// can be overridden with POLICY_TESTENV_* environment variables, so the same
+// tests run against a team's own deployment.
+package testenv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// Observation dimensions and action size of the model the compose
+// environment generates
+const (
+	Channels = 3
+	Height   = 8
+	Width    = 8
+	Actions  = 2
+)
+
+// Env addresses a running environment
+type Env struct {
+	// GRPCAddr is the service's gRPC host:port
+	GRPCAddr string
+	// MetricsURL is the service's HTTP base URL (metrics, health, admin)
+	MetricsURL string
+	// PrometheusURL is the Prometheus base URL; empty skips Prometheus checks
+	PrometheusURL string
+	// CollectorMetricsURL is the OTel collector's own metrics endpoint; empty
+	// skips trace checks
+	CollectorMetricsURL string
+
+	client *http.Client
+}
+
+// FromEnv returns the environment described by POLICY_TESTENV_GRPC,
+// POLICY_TESTENV_METRICS, POLICY_TESTENV_PROMETHEUS, and
+// POLICY_TESTENV_COLLECTOR_METRICS, defaulting to docker-compose.yaml's ports
+func FromEnv() *Env {
+	return &Env{
+		GRPCAddr:            getenv("POLICY_TESTENV_GRPC", "localhost:50051"),
+		MetricsURL:          getenv("POLICY_TESTENV_METRICS", "http://localhost:9100"),
+		PrometheusURL:       getenv("POLICY_TESTENV_PROMETHEUS", "http://localhost:9090"),
+		CollectorMetricsURL: getenv("POLICY_TESTENV_COLLECTOR_METRICS", "http://localhost:8888/metrics"),
+		client:              &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func getenv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
+
+// Dial connects a PathPlanner client to the service
+func (e *Env) Dial() (pb.PathPlannerClient, *grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(e.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial %s: %w", e.GRPCAddr, err)
+	}
+	return pb.NewPathPlannerClient(conn), conn, nil
+}
+
+// WaitReady polls the service's gRPC health check until it is serving or ctx
+// is done
+func (e *Env) WaitReady(ctx context.Context) error {
+	conn, err := grpc.NewClient(e.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to dial %s: %w", e.GRPCAddr, err)
+	}
+	defer conn.Close()
+	health := healthpb.NewHealthClient(conn)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		resp, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = fmt.Errorf("status %s", resp.Status)
+			}
+			return fmt.Errorf("service at %s not ready: %w", e.GRPCAddr, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Observation returns a valid observation for the dummy model whose values
+// are derived from seed, so equal seeds give equal observations
+func Observation(seed int) *pb.Observation {
+	data := make([]float32, Channels*Height*Width)
+	for i := range data {
+		data[i] = float32((seed*31+i)%97) / 97
+	}
+	return &pb.Observation{Data: data, Channels: Channels, Height: Height, Width: Width}
+}
+
+// Metrics is a scrape in the Prometheus text format, keyed by series: the
+// metric name followed by its labels as exposed, e.g.
+// plan_cache_requests_total{result="hit"}
+type Metrics map[string]float64
+
+// Sum adds the values of every series of metric whose labels include all of
+// labels, given as name="value" pairs
+func (m Metrics) Sum(metric string, labels ...string) float64 {
+	var sum float64
+	for series, v := range m {
+		name, rest, _ := strings.Cut(series, "{")
+		if name != metric {
+			continue
+		}
+		matched := true
+		for _, l := range labels {
+			if !strings.Contains("{"+rest, "{"+l) && !strings.Contains(rest, ","+l) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			sum += v
+		}
+	}
+	return sum
+}
+
+// Scrape fetches metrics from url in the Prometheus text format
+func (e *Env) Scrape(ctx context.Context, url string) (Metrics, error) {
+	body, err := e.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseMetrics(body)
+}
+
+// ServiceMetrics scrapes the service's /metrics endpoint
+func (e *Env) ServiceMetrics(ctx context.Context) (Metrics, error) {
+	return e.Scrape(ctx, strings.TrimSuffix(e.MetricsURL, "/")+"/metrics")
+}
+
+// parseMetrics reads samples in the Prometheus text format
+func parseMetrics(r io.Reader) (Metrics, error) {
+	metrics := make(Metrics)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Label values may contain spaces, so split after the closing brace
+		i := strings.LastIndex(line, "}")
+		if i < 0 {
+			i = strings.IndexByte(line, ' ') - 1
+		}
+		if i < 0 || i+1 >= len(line) {
+			continue
+		}
+		fields := strings.Fields(line[i+1:])
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		metrics[line[:i+1]] = v
+	}
+	return metrics, scanner.Err()
+}
+
+// QueryPrometheus runs an instant PromQL query and returns the value of
+// each resulting series
+func (e *Env) QueryPrometheus(ctx context.Context, query string) ([]float64, error) {
+	u := strings.TrimSuffix(e.PrometheusURL, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	body, err := e.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			Result []struct {
+				Value [2]interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode Prometheus response: %w", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query %q failed: %s", query, resp.Error)
+	}
+	values := make([]float64, 0, len(resp.Data.Result))
+	for _, r := range resp.Data.Result {
+		s, _ := r.Value[1].(string)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("prometheus query %q returned invalid value %q", query, s)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// Eventually calls check every interval until it returns nil or ctx is done,
+// returning check's last error in that case
+func Eventually(ctx context.Context, interval time.Duration, check func(context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Env) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := e.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
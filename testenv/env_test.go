@@ -0,0 +1,46 @@
+// testenv/env_test.go
+package testenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMetrics(t *testing.T) {
+	text := `# HELP plan_cache_requests_total Plan cache lookups
+# TYPE plan_cache_requests_total counter
+plan_cache_requests_total{result="hit"} 3
+plan_cache_requests_total{result="miss"} 5
+grpc_server_handling_seconds_count{method="/planner.PathPlanner/Plan",code="OK"} 7
+grpc_server_handling_seconds_count{method="/planner.PathPlanner/BatchPlan",code="OK"} 2
+go_goroutines 12
+otelcol_receiver_accepted_spans{receiver="otlp",transport="http"} 4 1700000000000
+`
+	m, err := parseMetrics(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("parseMetrics failed: %v", err)
+	}
+
+	if got := m.Sum("plan_cache_requests_total", `result="hit"`); got != 3 {
+		t.Errorf("Expected 3 cache hits, got %v", got)
+	}
+	if got := m.Sum("plan_cache_requests_total"); got != 8 {
+		t.Errorf("Expected 8 cache lookups, got %v", got)
+	}
+	if got := m.Sum("grpc_server_handling_seconds_count", `code="OK"`, `method="/planner.PathPlanner/Plan"`); got != 7 {
+		t.Errorf("Expected 7 Plan calls, got %v", got)
+	}
+	if got := m.Sum("go_goroutines"); got != 12 {
+		t.Errorf("Expected 12 goroutines, got %v", got)
+	}
+	if got := m.Sum("otelcol_receiver_accepted_spans", `receiver="otlp"`); got != 4 {
+		t.Errorf("Expected 4 spans with a timestamped sample, got %v", got)
+	}
+}
+
+func TestExpectedActions(t *testing.T) {
+	got := ExpectedActions([]float32{1, 2, 3, 4, 5}, 2)
+	if got[0] != 9 || got[1] != 6 {
+		t.Errorf("Expected [9 6], got %v", got)
+	}
+}
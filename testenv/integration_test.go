@@ -0,0 +1,205 @@
+// testenv/integration_test.go
+
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/SyedDaiam9101/policy-service/proto/plannerpb"
+)
+
+// These tests run against a live environment:
+//
+//	docker compose -f testenv/docker-compose.yaml up -d --build --wait
+//	go test -tags integration ./testenv/
+
+// env is the environment under test, ready before any test runs
+var env = FromEnv()
+
+func TestMain(m *testing.M) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	err := env.WaitReady(ctx)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Environment not available: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func dial(t *testing.T) pb.PathPlannerClient {
+	t.Helper()
+	client, conn, err := env.Dial()
+	if err != nil {
+		t.Fatalf("Failed to dial service: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return client
+}
+
+func checkActions(t *testing.T, obs *pb.Observation, action []float32) {
+	t.Helper()
+	expected := ExpectedActions(obs.Data, Actions)
+	if len(action) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d", len(expected), len(action))
+	}
+	for i := range expected {
+		if math.Abs(float64(action[i]-expected[i])) > 1e-3 {
+			t.Fatalf("Expected actions %v, got %v", expected, action)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	client := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	obs := Observation(1)
+	resp, err := client.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: obs})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	checkActions(t, obs, resp.Action)
+
+	// Observations the model cannot take are rejected
+	bad := &pb.Observation{Data: obs.Data[1:], Channels: Channels, Height: Height, Width: Width}
+	if _, err := client.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: bad}); err == nil {
+		t.Error("Expected Plan to reject a truncated observation")
+	}
+}
+
+func TestBatchPlan(t *testing.T) {
+	client := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := &pb.BatchPlanRequest{}
+	for i := 0; i < 8; i++ {
+		req.Requests = append(req.Requests, &pb.PlanRequest{RobotId: uint64(100 + i), Obs: Observation(100 + i)})
+	}
+	resp, err := client.BatchPlan(ctx, req)
+	if err != nil {
+		t.Fatalf("BatchPlan failed: %v", err)
+	}
+	if len(resp.Responses) != len(req.Requests) {
+		t.Fatalf("Expected %d responses, got %d", len(req.Requests), len(resp.Responses))
+	}
+	for i, r := range resp.Responses {
+		checkActions(t, req.Requests[i].Obs, r.Action)
+	}
+}
+
+func TestPlanCache(t *testing.T) {
+	client := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	before, err := env.ServiceMetrics(ctx)
+	if err != nil {
+		t.Fatalf("Failed to scrape service metrics: %v", err)
+	}
+
+	// Different robots sending the same observation share one plan
+	obs := Observation(int(time.Now().UnixNano() % 1000003))
+	for robot := uint64(200); robot < 203; robot++ {
+		resp, err := client.Plan(ctx, &pb.PlanRequest{RobotId: robot, Obs: obs})
+		if err != nil {
+			t.Fatalf("Plan failed: %v", err)
+		}
+		checkActions(t, obs, resp.Action)
+	}
+
+	after, err := env.ServiceMetrics(ctx)
+	if err != nil {
+		t.Fatalf("Failed to scrape service metrics: %v", err)
+	}
+	hits := after.Sum("plan_cache_requests_total", `result="hit"`) - before.Sum("plan_cache_requests_total", `result="hit"`)
+	if hits < 2 {
+		t.Errorf("Expected at least 2 plan cache hits, got %v", hits)
+	}
+}
+
+func TestMetricsScrape(t *testing.T) {
+	client := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	if _, err := client.Plan(ctx, &pb.PlanRequest{RobotId: 1, Obs: Observation(1)}); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	m, err := env.ServiceMetrics(ctx)
+	if err != nil {
+		t.Fatalf("Failed to scrape service metrics: %v", err)
+	}
+	if got := m.Sum("grpc_server_handling_seconds_count", `method="/planner.PathPlanner/Plan"`, `code="OK"`); got < 1 {
+		t.Errorf("Expected successful Plan calls in grpc_server_handling_seconds, got %v", got)
+	}
+
+	if env.PrometheusURL == "" {
+		t.Skip("POLICY_TESTENV_PROMETHEUS is empty; skipping the Prometheus check")
+	}
+	// Prometheus scrapes the service and sees the calls above
+	err = Eventually(ctx, time.Second, func(ctx context.Context) error {
+		values, err := env.QueryPrometheus(ctx, `sum(grpc_server_handling_seconds_count{method="/planner.PathPlanner/Plan",code="OK"})`)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 || values[0] < 1 {
+			return fmt.Errorf("no Plan calls in Prometheus yet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected Prometheus to scrape the service: %v", err)
+	}
+}
+
+func TestTraces(t *testing.T) {
+	if env.CollectorMetricsURL == "" {
+		t.Skip("POLICY_TESTENV_COLLECTOR_METRICS is empty; skipping the trace check")
+	}
+	client := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	accepted := func(ctx context.Context) (float64, error) {
+		m, err := env.Scrape(ctx, env.CollectorMetricsURL)
+		if err != nil {
+			return 0, err
+		}
+		return m.Sum("otelcol_receiver_accepted_spans") + m.Sum("otelcol_receiver_accepted_spans_total"), nil
+	}
+	before, err := accepted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to scrape collector metrics: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.Plan(ctx, &pb.PlanRequest{RobotId: 300, Obs: Observation(300 + i)}); err != nil {
+			t.Fatalf("Plan failed: %v", err)
+		}
+	}
+
+	// Spans are batched, so the collector receives them a few seconds later
+	err = Eventually(ctx, time.Second, func(ctx context.Context) error {
+		after, err := accepted(ctx)
+		if err != nil {
+			return err
+		}
+		if after-before < 5 {
+			return fmt.Errorf("collector accepted %v spans, expected at least 5", after-before)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected the service to export Plan spans to the collector: %v", err)
+	}
+}
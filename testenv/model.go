@@ -0,0 +1,171 @@
+// testenv/model.go
+package testenv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ONNX protobuf field numbers used by DummyModel (onnx/onnx.proto)
+const (
+	onnxModelIRVersion    = 1
+	onnxModelProducerName = 2
+	onnxModelGraph        = 7
+	onnxModelOpsetImport  = 8
+
+	onnxOpsetVersion = 2
+
+	onnxGraphNode        = 1
+	onnxGraphName        = 2
+	onnxGraphInitializer = 5
+	onnxGraphInput       = 11
+	onnxGraphOutput      = 12
+
+	onnxNodeInput  = 1
+	onnxNodeOutput = 2
+	onnxNodeName   = 3
+	onnxNodeOpType = 4
+
+	onnxTensorDims     = 1
+	onnxTensorDataType = 2
+	onnxTensorName     = 8
+	onnxTensorRawData  = 9
+
+	onnxValueInfoName = 1
+	onnxValueInfoType = 2
+
+	onnxTypeTensorType = 1
+	onnxTensorElemType = 1
+	onnxTensorShape    = 2
+	onnxShapeDim       = 1
+	onnxDimValue       = 1
+	onnxDimParam       = 2
+
+	onnxFloat = 1
+)
+
+// dummyWeight is the weight DummyModel gives observation value i in action
+func dummyWeight(i, action, actions int) float32 {
+	if i%actions == action {
+		return 1
+	}
+	return 0
+}
+
+// DummyModel encodes a minimal ONNX model taking obs of shape [N, c, h, w]
+// and returning action of shape [N, actions], where action j is the sum of
+// the flattened observation values at indexes congruent to j mod actions.
+// It needs nothing but ONNX Runtime, so any environment can run the service
+// on it; ExpectedActions computes its output.
+func DummyModel(c, h, w, actions int) ([]byte, error) {
+	if c <= 0 || h <= 0 || w <= 0 || actions <= 0 {
+		return nil, fmt.Errorf("dummy model dimensions must be positive, got %dx%dx%d with %d actions", c, h, w, actions)
+	}
+	n := c * h * w
+	weights := make([]byte, 0, 4*n*actions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < actions; j++ {
+			weights = binary.LittleEndian.AppendUint32(weights, math.Float32bits(dummyWeight(i, j, actions)))
+		}
+	}
+	var initializer []byte
+	initializer = appendVarintField(initializer, onnxTensorDims, uint64(n))
+	initializer = appendVarintField(initializer, onnxTensorDims, uint64(actions))
+	initializer = appendVarintField(initializer, onnxTensorDataType, onnxFloat)
+	initializer = appendStringField(initializer, onnxTensorName, "W")
+	initializer = appendBytesField(initializer, onnxTensorRawData, weights)
+
+	var graph []byte
+	graph = appendBytesField(graph, onnxGraphNode, node("flatten", "Flatten", []string{"obs"}, "flat"))
+	graph = appendBytesField(graph, onnxGraphNode, node("matmul", "MatMul", []string{"flat", "W"}, "action"))
+	graph = appendStringField(graph, onnxGraphName, "dummy")
+	graph = appendBytesField(graph, onnxGraphInitializer, initializer)
+	graph = appendBytesField(graph, onnxGraphInput, valueInfo("obs", -1, int64(c), int64(h), int64(w)))
+	graph = appendBytesField(graph, onnxGraphOutput, valueInfo("action", -1, int64(actions)))
+
+	var opset []byte
+	opset = appendVarintField(opset, onnxOpsetVersion, 13)
+
+	var model []byte
+	model = appendVarintField(model, onnxModelIRVersion, 7)
+	model = appendStringField(model, onnxModelProducerName, "policy-service-testenv")
+	model = appendBytesField(model, onnxModelGraph, graph)
+	model = appendBytesField(model, onnxModelOpsetImport, opset)
+	return model, nil
+}
+
+// WriteDummyModel writes DummyModel(c, h, w, actions) to path
+func WriteDummyModel(path string, c, h, w, actions int) error {
+	model, err := DummyModel(c, h, w, actions)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, model, 0o644)
+}
+
+// ExpectedActions returns the dummy model's action for obs
+func ExpectedActions(obs []float32, actions int) []float32 {
+	out := make([]float32, actions)
+	for i, v := range obs {
+		out[i%actions] += v
+	}
+	return out
+}
+
+// node encodes a NodeProto
+func node(name, opType string, inputs []string, output string) []byte {
+	var b []byte
+	for _, input := range inputs {
+		b = appendStringField(b, onnxNodeInput, input)
+	}
+	b = appendStringField(b, onnxNodeOutput, output)
+	b = appendStringField(b, onnxNodeName, name)
+	b = appendStringField(b, onnxNodeOpType, opType)
+	return b
+}
+
+// valueInfo encodes a float tensor ValueInfoProto; -1 is a dynamic batch
+// dimension
+func valueInfo(name string, dims ...int64) []byte {
+	var shape []byte
+	for _, d := range dims {
+		var dim []byte
+		if d < 0 {
+			dim = appendStringField(dim, onnxDimParam, "N")
+		} else {
+			dim = appendVarintField(dim, onnxDimValue, uint64(d))
+		}
+		shape = appendBytesField(shape, onnxShapeDim, dim)
+	}
+
+	var tensor []byte
+	tensor = appendVarintField(tensor, onnxTensorElemType, onnxFloat)
+	tensor = appendBytesField(tensor, onnxTensorShape, shape)
+
+	var typ []byte
+	typ = appendBytesField(typ, onnxTypeTensorType, tensor)
+
+	var info []byte
+	info = appendStringField(info, onnxValueInfoName, name)
+	info = appendBytesField(info, onnxValueInfoType, typ)
+	return info
+}
+
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendStringField(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
@@ -0,0 +1,40 @@
+// testenv/model_ort_test.go
+
+//go:build cgo && !noort
+
+package testenv
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/SyedDaiam9101/policy-service/internal/inference"
+)
+
+func TestDummyModel_RunsInORT(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dummy.onnx")
+	if err := WriteDummyModel(path, Channels, Height, Width, Actions); err != nil {
+		t.Fatalf("WriteDummyModel failed: %v", err)
+	}
+	engine, err := inference.New(path)
+	if err != nil {
+		t.Skipf("Skipping dummy model test: %v", err)
+	}
+	defer engine.Close()
+
+	obs := Observation(1).Data
+	actions, err := engine.Predict([][]float32{obs}, Channels, Height, Width)
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	expected := ExpectedActions(obs, Actions)
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d", len(expected), len(actions))
+	}
+	for i := range expected {
+		if math.Abs(float64(actions[i]-expected[i])) > 1e-4 {
+			t.Fatalf("Expected actions %v, got %v", expected, actions)
+		}
+	}
+}